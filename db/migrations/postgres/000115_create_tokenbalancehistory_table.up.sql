@@ -0,0 +1,27 @@
+BEGIN;
+CREATE TABLE tokenbalancehistory (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  token_index      VARCHAR(1024),
+  uri              VARCHAR(1024),
+  connector        VARCHAR(64),
+  key              VARCHAR(1024)   NOT NULL,
+  amount           VARCHAR(65),
+  balance          VARCHAR(65),
+  transfer_id      UUID,
+  blockchain_event UUID,
+  blockchain_event_seq BIGINT,
+  opening          BOOLEAN         NOT NULL DEFAULT false,
+  timestamp        BIGINT          NOT NULL,
+  created          BIGINT          NOT NULL
+);
+
+CREATE INDEX tokenbalancehistory_account ON tokenbalancehistory(namespace,pool_id,token_index,key);
+CREATE INDEX tokenbalancehistory_timestamp ON tokenbalancehistory(namespace,timestamp);
+CREATE INDEX tokenbalancehistory_eventseq ON tokenbalancehistory(namespace,blockchain_event_seq);
+
+-- Existing balances are the opening entries of the history
+INSERT INTO tokenbalancehistory (namespace, pool_id, token_index, uri, connector, key, amount, balance, timestamp, created, opening)
+  SELECT namespace, pool_id, token_index, uri, connector, key, balance, balance, COALESCE(updated, 0), COALESCE(updated, 0), true FROM tokenbalance ORDER BY seq;
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+CREATE TABLE tokenbalancemismatch (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  reconciliation_id UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  pool_id           UUID            NOT NULL,
+  token_index       VARCHAR(1024),
+  connector         VARCHAR(64),
+  key               VARCHAR(1024)   NOT NULL,
+  local_balance     VARCHAR(65),
+  chain_balance     VARCHAR(65),
+  corrected         BOOLEAN         NOT NULL,
+  created           BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenbalancemismatch_id ON tokenbalancemismatch(namespace,id);
+CREATE INDEX tokenbalancemismatch_reconciliation ON tokenbalancemismatch(namespace,reconciliation_id);
+CREATE INDEX tokenbalancemismatch_account ON tokenbalancemismatch(namespace,pool_id,token_index,key);
+COMMIT;
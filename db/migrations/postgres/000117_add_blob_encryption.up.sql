@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE blobs ADD COLUMN encryption TEXT;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX messages_expires;
+ALTER TABLE messages DROP COLUMN expires;
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+CREATE TABLE blobuploads (
+  seq            SERIAL          PRIMARY KEY,
+  id             UUID            NOT NULL,
+  namespace      VARCHAR(64)     NOT NULL,
+  size           BIGINT,
+  received       BIGINT          NOT NULL,
+  chunks         TEXT,
+  hash_state     TEXT,
+  state          VARCHAR(64)     NOT NULL DEFAULT 'receiving',
+  data_id        UUID,
+  error          TEXT            NOT NULL DEFAULT '',
+  finalize       TEXT,
+  created        BIGINT          NOT NULL,
+  updated        BIGINT          NOT NULL,
+  expires        BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX blobuploads_id ON blobuploads(namespace,id);
+CREATE INDEX blobuploads_expires ON blobuploads(namespace,expires);
+CREATE INDEX blobuploads_state ON blobuploads(namespace,state);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX messages_reply_deadline;
+ALTER TABLE messages DROP COLUMN reply_deadline;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN reply_deadline BIGINT;
+CREATE INDEX messages_reply_deadline ON messages(namespace_local, reply_deadline);
+COMMIT;
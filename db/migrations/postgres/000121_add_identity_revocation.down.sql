@@ -0,0 +1,6 @@
+BEGIN;
+ALTER TABLE verifiers DROP COLUMN revoked_seq;
+ALTER TABLE verifiers DROP COLUMN revoked;
+ALTER TABLE identities DROP COLUMN revoked;
+ALTER TABLE identities DROP COLUMN messages_revocation;
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+ALTER TABLE identities ADD COLUMN messages_revocation UUID;
+ALTER TABLE identities ADD COLUMN revoked BIGINT;
+ALTER TABLE verifiers ADD COLUMN revoked BIGINT;
+ALTER TABLE verifiers ADD COLUMN revoked_seq BIGINT DEFAULT 0;
+COMMIT;
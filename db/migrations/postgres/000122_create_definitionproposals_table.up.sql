@@ -0,0 +1,20 @@
+BEGIN;
+CREATE TABLE definitionproposals (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  tag               VARCHAR(64)     NOT NULL,
+  topic             VARCHAR(64)     NOT NULL,
+  author            VARCHAR(1024)   NOT NULL,
+  tx_id             UUID,
+  state             VARCHAR(64)     NOT NULL,
+  quorum            INTEGER         NOT NULL,
+  votes             TEXT,
+  error             TEXT,
+  created           BIGINT          NOT NULL,
+  updated           BIGINT
+);
+
+CREATE UNIQUE INDEX definitionproposals_id ON definitionproposals(namespace,id);
+CREATE INDEX definitionproposals_state ON definitionproposals(namespace,state);
+COMMIT;
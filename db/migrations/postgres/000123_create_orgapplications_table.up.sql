@@ -0,0 +1,20 @@
+BEGIN;
+CREATE TABLE orgapplications (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  sponsor           VARCHAR(1024)   NOT NULL,
+  org               TEXT            NOT NULL,
+  node              TEXT            NOT NULL,
+  key               VARCHAR(1024)   NOT NULL,
+  state             VARCHAR(64)     NOT NULL,
+  reason            TEXT,
+  message_id        UUID,
+  review_id         UUID,
+  created           BIGINT          NOT NULL,
+  updated           BIGINT
+);
+
+CREATE UNIQUE INDEX orgapplications_id ON orgapplications(namespace,id);
+CREATE INDEX orgapplications_state ON orgapplications(namespace,state);
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+ALTER TABLE datatypes ADD COLUMN group_hash CHAR(64);
+ALTER TABLE ffi ADD COLUMN group_hash CHAR(64);
+ALTER TABLE contractapis ADD COLUMN group_hash CHAR(64);
+ALTER TABLE tokenpool ADD COLUMN group_hash CHAR(64);
+
+DROP INDEX datatypes_unique;
+CREATE UNIQUE INDEX datatypes_unique ON datatypes(namespace,name,version) WHERE group_hash IS NULL;
+CREATE UNIQUE INDEX datatypes_group_unique ON datatypes(namespace,group_hash,name,version) WHERE group_hash IS NOT NULL;
+
+DROP INDEX ffi_networkname;
+CREATE UNIQUE INDEX ffi_networkname ON ffi(namespace,network_name,version) WHERE group_hash IS NULL;
+CREATE UNIQUE INDEX ffi_group_networkname ON ffi(namespace,group_hash,network_name,version) WHERE group_hash IS NOT NULL;
+
+DROP INDEX contractapis_networkname;
+CREATE UNIQUE INDEX contractapis_networkname ON contractapis(namespace,network_name) WHERE group_hash IS NULL;
+CREATE UNIQUE INDEX contractapis_group_networkname ON contractapis(namespace,group_hash,network_name) WHERE group_hash IS NOT NULL;
+
+DROP INDEX tokenpool_networkname;
+CREATE UNIQUE INDEX tokenpool_networkname ON tokenpool(namespace,network_name) WHERE group_hash IS NULL;
+CREATE UNIQUE INDEX tokenpool_group_networkname ON tokenpool(namespace,group_hash,network_name) WHERE group_hash IS NOT NULL;
+COMMIT;
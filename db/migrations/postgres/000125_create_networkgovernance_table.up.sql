@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE networkgovernance (
+  seq               SERIAL          PRIMARY KEY,
+  namespace         VARCHAR(64)     NOT NULL,
+  definition_quorum INTEGER         NOT NULL,
+  org_onboarding    BOOLEAN         DEFAULT false,
+  author            VARCHAR(1024)   NOT NULL,
+  message_id        UUID,
+  updated           BIGINT
+);
+
+CREATE UNIQUE INDEX networkgovernance_namespace ON networkgovernance(namespace);
+COMMIT;
@@ -0,0 +1,2 @@
+DROP INDEX messages_expires;
+ALTER TABLE messages DROP COLUMN expires;
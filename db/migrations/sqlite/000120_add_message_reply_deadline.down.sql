@@ -0,0 +1,2 @@
+DROP INDEX messages_reply_deadline;
+ALTER TABLE messages DROP COLUMN reply_deadline;
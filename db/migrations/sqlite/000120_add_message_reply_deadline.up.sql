@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN reply_deadline BIGINT;
+CREATE INDEX messages_reply_deadline ON messages(namespace_local, reply_deadline);
@@ -0,0 +1,20 @@
+DROP INDEX datatypes_group_unique;
+DROP INDEX datatypes_unique;
+CREATE UNIQUE INDEX datatypes_unique ON datatypes(namespace,name,version);
+
+DROP INDEX ffi_group_networkname;
+DROP INDEX ffi_networkname;
+CREATE UNIQUE INDEX ffi_networkname ON ffi(namespace,network_name,version);
+
+DROP INDEX contractapis_group_networkname;
+DROP INDEX contractapis_networkname;
+CREATE UNIQUE INDEX contractapis_networkname ON contractapis(namespace,network_name);
+
+DROP INDEX tokenpool_group_networkname;
+DROP INDEX tokenpool_networkname;
+CREATE UNIQUE INDEX tokenpool_networkname ON tokenpool(namespace,network_name);
+
+ALTER TABLE datatypes DROP COLUMN group_hash;
+ALTER TABLE ffi DROP COLUMN group_hash;
+ALTER TABLE contractapis DROP COLUMN group_hash;
+ALTER TABLE tokenpool DROP COLUMN group_hash;
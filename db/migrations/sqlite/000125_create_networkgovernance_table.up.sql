@@ -0,0 +1,11 @@
+CREATE TABLE networkgovernance (
+  seq               INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace         VARCHAR(64)     NOT NULL,
+  definition_quorum INTEGER         NOT NULL,
+  org_onboarding    BOOLEAN         DEFAULT false,
+  author            VARCHAR(1024)   NOT NULL,
+  message_id        UUID,
+  updated           BIGINT
+);
+
+CREATE UNIQUE INDEX networkgovernance_namespace ON networkgovernance(namespace);
@@ -0,0 +1,157 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"database/sql/driver"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// addDataValueFilters adds conditions to the request filter for any query parameters of the form
+// "value.<path>", which match a dot-separated JSON path within the value of data. These cannot be
+// declared up-front on the route, but support the same operator prefixes as other filter fields.
+func addDataValueFilters(r *ffapi.APIRequest, queryFields *ffapi.QueryFields) error {
+	ctx := r.Req.Context()
+	_ = r.Req.ParseForm()
+	valuesByPath := make(map[string][]string)
+	for name, values := range r.Req.Form {
+		if len(name) > len(database.DataValueFieldPrefix) && strings.EqualFold(name[0:len(database.DataValueFieldPrefix)], database.DataValueFieldPrefix) {
+			path := name[len(database.DataValueFieldPrefix):]
+			valuesByPath[path] = append(valuesByPath[path], values...)
+		}
+	}
+	if len(valuesByPath) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(valuesByPath))
+	for path := range valuesByPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fb := database.WithDataValueFields(queryFields, paths...).NewFilter(ctx)
+	for _, path := range paths {
+		values := valuesByPath[path]
+		sort.Strings(values)
+		conditions := make([]ffapi.Filter, len(values))
+		for i, value := range values {
+			cond, err := dataValueCondition(ctx, fb, path, value)
+			if err != nil {
+				return err
+			}
+			conditions[i] = cond
+		}
+		if len(conditions) == 1 {
+			r.Filter.Condition(conditions[0])
+		} else {
+			r.Filter.Condition(fb.Or(conditions...))
+		}
+	}
+	return nil
+}
+
+// dataValueCondition parses the operator and modifier prefixes of a filter value,
+// in the same way as the filters built automatically for the fields of a route.
+// The parser in ffapi is not exported, so TestDataValueConditionParity pins the two together.
+// Range comparisons against a number compare numerically, rather than as text.
+func dataValueCondition(ctx context.Context, fb ffapi.FilterBuilder, path, value string) (ffapi.Filter, error) {
+	field := database.DataValueFieldPrefix + path
+	var negate, caseInsensitive, emptyIsNull bool
+	operator := ""
+	prefixLength := 0
+opFinder:
+	for _, r := range value {
+		switch r {
+		case '!':
+			negate = true
+		case ':':
+			caseInsensitive = true
+		case '?':
+			emptyIsNull = true
+		case '>', '<':
+			if len(operator) == 1 && operator[0] != byte(r) {
+				// "><" or "<>" is a single char operator, followed by the match string
+				break opFinder
+			}
+			operator += string(r)
+			if len(operator) > 1 {
+				prefixLength++
+				break opFinder
+			}
+		case '=', '@', '^', '$':
+			operator += string(r)
+			prefixLength++
+			break opFinder
+		default:
+			break opFinder
+		}
+		prefixLength++
+	}
+
+	var match driver.Value = value[prefixLength:]
+	if emptyIsNull && prefixLength == len(value) {
+		match = nil
+	}
+
+	switch operator {
+	case ">=", "<=", ">", ">>", "<", "<<":
+		if negate || caseInsensitive || emptyIsNull {
+			return nil, i18n.NewError(ctx, i18n.MsgQueryOpUnsupportedMod, operator, field)
+		}
+		if _, err := strconv.ParseFloat(value[prefixLength:], 64); err == nil {
+			field = database.DataValueNumberFieldPrefix + path
+		}
+		switch operator {
+		case ">=":
+			return fb.Gte(field, match), nil
+		case "<=":
+			return fb.Lte(field, match), nil
+		case ">", ">>":
+			return fb.Gt(field, match), nil
+		default:
+			return fb.Lt(field, match), nil
+		}
+	case "@":
+		return pickCondition(negate, caseInsensitive, fb.Contains, fb.NotContains, fb.IContains, fb.NotIContains)(field, match), nil
+	case "^":
+		return pickCondition(negate, caseInsensitive, fb.StartsWith, fb.NotStartsWith, fb.IStartsWith, fb.NotIStartsWith)(field, match), nil
+	case "$":
+		return pickCondition(negate, caseInsensitive, fb.EndsWith, fb.NotEndsWith, fb.IEndsWith, fb.NotIEndsWith)(field, match), nil
+	default:
+		return pickCondition(negate, caseInsensitive, fb.Eq, fb.Neq, fb.IEq, fb.NIeq)(field, match), nil
+	}
+}
+
+func pickCondition(negate, caseInsensitive bool, match, notMatch, iMatch, notIMatch func(string, driver.Value) ffapi.Filter) func(string, driver.Value) ffapi.Filter {
+	switch {
+	case caseInsensitive && negate:
+		return notIMatch
+	case caseInsensitive:
+		return iMatch
+	case negate:
+		return notMatch
+	default:
+		return match
+	}
+}
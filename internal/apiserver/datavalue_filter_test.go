@@ -0,0 +1,218 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDataValueFilters(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data?value.customer.id=123&value.amount=>>10&value.amount=<<20&validator=json", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetData", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		upper, _ := fi.Children[1].Children[0].Value.Value()
+		lower, _ := fi.Children[1].Children[1].Value.Value()
+		return fi.Children[0].Field == "validator" &&
+			fi.Children[1].Children[0].Field == "valuenum.amount" && upper == float64(20) &&
+			fi.Children[1].Children[1].Field == "valuenum.amount" && lower == float64(10) &&
+			fi.Children[2].Field == "value.customer.id"
+	})).Return(core.DataArray{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesDataValueFilters(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?Value.customerId=:!^ab", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		return fi.String() == "( value.customerId ;^ 'ab' ) limit=25"
+	})).Return([]*core.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesDataValueFiltersBadModifier(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?value.amount=!>=10", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF00193", res.Body.String())
+}
+
+func TestGetDataValueFiltersBadModifier(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data?value.amount=?<", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestDataValueConditionOperators(t *testing.T) {
+	ctx := context.Background()
+	fb := database.WithDataValueFields(database.DataQueryFactory, "a").NewFilter(ctx)
+	for value, expected := range map[string]string{
+		"b":    "value.a == 'b'",
+		"!b":   "value.a != 'b'",
+		":b":   "value.a := 'b'",
+		"!:b":  "value.a ;= 'b'",
+		"?":    "value.a == null",
+		">=b":  "value.a >= 'b'",
+		"<=b":  "value.a <= 'b'",
+		">b":   "value.a >> 'b'",
+		"<b":   "value.a << 'b'",
+		"<>1":  "value.a << '>1'",
+		"@b":   "value.a %= 'b'",
+		"!@b":  "value.a !% 'b'",
+		":@b":  "value.a :% 'b'",
+		"!:@b": "value.a ;% 'b'",
+		"^b":   "value.a ^= 'b'",
+		"!^b":  "value.a !^ 'b'",
+		":^b":  "value.a :^ 'b'",
+		"$b":   "value.a $= 'b'",
+		"!$b":  "value.a !$ 'b'",
+		":$b":  "value.a :$ 'b'",
+		"!:$b": "value.a ;$ 'b'",
+	} {
+		cond, err := dataValueCondition(ctx, fb, "a", value)
+		assert.NoError(t, err)
+		fi, err := cond.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, fi.String(), value)
+	}
+}
+
+// ffapiCondition parses a filter value with the parser in ffapi, by running it through the handler of
+// a route that declares the field up-front
+func ffapiCondition(t *testing.T, field, value string) (string, error) {
+	var condition string
+	hf := &ffapi.HandlerFactory{DefaultRequestTimeout: 10 * time.Second}
+	handler := hf.RouteHandler(&ffapi.Route{
+		Name:            "parity",
+		Path:            "parity",
+		Method:          http.MethodGet,
+		FilterFactory:   &ffapi.QueryFields{field: &ffapi.StringField{}},
+		JSONOutputCodes: []int{http.StatusOK},
+		JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+			fi, err := r.Filter.Finalize()
+			if err != nil {
+				return nil, err
+			}
+			condition = fi.Children[0].String()
+			return fftypes.JSONObject{}, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/parity?"+url.Values{field: []string{value}}.Encode(), nil)
+	res := httptest.NewRecorder()
+	handler(res, req)
+	if res.Code != http.StatusOK {
+		var restErr fftypes.RESTError
+		err := json.Unmarshal(res.Body.Bytes(), &restErr)
+		assert.NoError(t, err)
+		return "", errors.New(restErr.Error)
+	}
+	return condition, nil
+}
+
+func TestDataValueConditionParity(t *testing.T) {
+	ctx := context.Background()
+	fb := database.WithDataValueFields(database.DataQueryFactory, "a").NewFilter(ctx)
+	for _, value := range []string{
+		"", "b", "=b", "==b", "=>b", "!b", "!=b", "!!b", ":b", "::b", ":!b", "!:b", "!:=b",
+		"?", "?=", "!?", ":?", "?b", "?!b",
+		">b", ">>b", ">>>b", ">=b", ">==b", "<b", "<<b", "<=b", "<<=b", "<>b", "><b", ">", "<=",
+		"!>b", ":<b", "?>=", "?<", "!:>=b",
+		"@b", "!@b", ":@b", "!:@b", "@", "@@b",
+		"^b", "!^b", ":^b", ":!^b", "^$b",
+		"$b", "!$b", ":$b", "!:$b", "$^b",
+	} {
+		expected, expectedErr := ffapiCondition(t, "value.a", value)
+		var fi *ffapi.FilterInfo
+		cond, err := dataValueCondition(ctx, fb, "a", value)
+		if err == nil {
+			fi, err = cond.Finalize()
+		}
+		if expectedErr != nil {
+			assert.Regexp(t, strings.SplitN(expectedErr.Error(), ":", 2)[0], err, value)
+			continue
+		}
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, fi.String(), value)
+	}
+}
+
+func TestDataValueConditionNumbers(t *testing.T) {
+	ctx := context.Background()
+	fb := database.WithDataValueFields(database.DataQueryFactory, "a").NewFilter(ctx)
+	for value, expected := range map[string]ffapi.FilterOp{
+		">=1":    ffapi.FilterOpGte,
+		"<=-1.5": ffapi.FilterOpLte,
+		">1e3":   ffapi.FilterOpGt,
+		"<0":     ffapi.FilterOpLt,
+	} {
+		cond, err := dataValueCondition(ctx, fb, "a", value)
+		assert.NoError(t, err)
+		fi, err := cond.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "valuenum.a", fi.Field, value)
+		assert.Equal(t, expected, fi.Op, value)
+	}
+
+	// Equality is always on the text of the value
+	cond, err := dataValueCondition(ctx, fb, "a", "1")
+	assert.NoError(t, err)
+	fi, err := cond.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "value.a == '1'", fi.String())
+}
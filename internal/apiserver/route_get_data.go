@@ -38,6 +38,9 @@ var getData = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			if err := addDataValueFilters(r, database.DataQueryFactory); err != nil {
+				return nil, err
+			}
 			return r.FilterResult(cr.or.GetData(cr.ctx, r.Filter))
 		},
 	},
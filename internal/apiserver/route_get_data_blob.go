@@ -17,10 +17,15 @@
 package apiserver
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/orchestrator"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -45,14 +50,83 @@ var getDataBlob = &ffapi.Route{
 			return or.Data().BlobsEnabled()
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			blob, reader, err := cr.or.Data().DownloadBlob(cr.ctx, r.PP["dataid"])
-			if err == nil {
-				r.ResponseHeaders.Set(core.HTTPHeadersBlobHashSHA256, blob.Hash.String())
-				if blob.Size > 0 {
-					r.ResponseHeaders.Set(core.HTTPHeadersBlobSize, strconv.FormatInt(blob.Size, 10))
-				}
+			blob, err := cr.or.Data().GetDataBlob(cr.ctx, r.PP["dataid"])
+			if err != nil {
+				return nil, err
 			}
-			return reader, nil
+			r.ResponseHeaders.Set(core.HTTPHeadersBlobHashSHA256, blob.Hash.String())
+			if blob.Size > 0 {
+				r.ResponseHeaders.Set(core.HTTPHeadersBlobSize, strconv.FormatInt(blob.Size, 10))
+				r.ResponseHeaders.Set("Accept-Ranges", "bytes")
+				return serveBlobRange(cr, r, blob)
+			}
+			return cr.or.Data().OpenBlob(cr.ctx, blob)
 		},
 	},
 }
+
+// serveBlobRange narrows the response to the byte range requested in the Range header, if any,
+// downloading only that range of the blob from the data exchange
+func serveBlobRange(cr *coreRequest, r *ffapi.APIRequest, blob *core.Blob) (io.ReadCloser, error) {
+	header := r.Req.Header.Get("Range")
+	start, end, ranged, err := parseBlobRange(cr.ctx, header, blob.Size)
+	if err != nil {
+		r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes */%d", blob.Size))
+		return nil, err
+	}
+	if !ranged {
+		return cr.or.Data().OpenBlob(cr.ctx, blob)
+	}
+	reader, err := cr.or.Data().OpenBlobRange(cr.ctx, blob, start, end-start+1)
+	if err != nil {
+		return nil, err
+	}
+	r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, blob.Size))
+	r.SuccessStatus = http.StatusPartialContent
+	return reader, nil
+}
+
+// parseBlobRange parses a Range header containing a single byte range. Headers that cannot be parsed,
+// or that request multiple ranges, are ignored so that the whole blob is returned.
+func parseBlobRange(ctx context.Context, header string, size int64) (start, end int64, ranged bool, err error) {
+	spec := strings.TrimPrefix(header, "bytes=")
+	if spec == header || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+	parts := strings.SplitN(strings.TrimSpace(spec), "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false, nil
+	}
+	if parts[0] == "" {
+		// A suffix range, of the last N bytes
+		suffix, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, 0, false, nil
+		}
+		if suffix == 0 {
+			return 0, 0, false, i18n.NewError(ctx, coremsgs.MsgBlobRangeNotSatisfiable, header, size)
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, size - 1, true, nil
+	}
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, nil
+	}
+	end = size - 1
+	if parts[1] != "" {
+		last, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || last < start {
+			return 0, 0, false, nil
+		}
+		if last < end {
+			end = last
+		}
+	}
+	if start >= size {
+		return 0, 0, false, i18n.NewError(ctx, coremsgs.MsgBlobRangeNotSatisfiable, header, size)
+	}
+	return start, end, true, nil
+}
@@ -18,11 +18,15 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"io/ioutil"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -42,11 +46,12 @@ func TestGetDataBlob(t *testing.T) {
 	res := httptest.NewRecorder()
 
 	blobHash := fftypes.NewRandB32()
-	mdm.On("DownloadBlob", mock.Anything, "abcd1234").
-		Return(&core.Blob{
-			Hash: blobHash,
-			Size: 12345,
-		}, ioutil.NopCloser(bytes.NewReader([]byte("hello"))), nil)
+	blob := &core.Blob{
+		Hash: blobHash,
+		Size: 12345,
+	}
+	mdm.On("GetDataBlob", mock.Anything, "abcd1234").Return(blob, nil)
+	mdm.On("OpenBlob", mock.Anything, blob).Return(ioutil.NopCloser(bytes.NewReader([]byte("hello"))), nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
@@ -56,3 +61,154 @@ func TestGetDataBlob(t *testing.T) {
 	assert.Equal(t, "12345", res.Result().Header.Get(core.HTTPHeadersBlobSize))
 	assert.Equal(t, blobHash.String(), res.Result().Header.Get(core.HTTPHeadersBlobHashSHA256))
 }
+
+func TestGetDataBlobNotFound(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	res := httptest.NewRecorder()
+
+	mdm.On("GetDataBlob", mock.Anything, "abcd1234").Return(nil, i18n.NewError(context.Background(), coremsgs.Msg404NoResult))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 404, res.Result().StatusCode)
+	mdm.AssertExpectations(t)
+}
+
+func TestGetDataBlobEmpty(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Range", "bytes=0-1")
+	res := httptest.NewRecorder()
+
+	blob := &core.Blob{Hash: fftypes.NewRandB32()}
+	mdm.On("GetDataBlob", mock.Anything, "abcd1234").Return(blob, nil)
+	mdm.On("OpenBlob", mock.Anything, blob).Return(ioutil.NopCloser(bytes.NewReader([]byte{})), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get("Accept-Ranges"))
+	mdm.AssertExpectations(t)
+}
+
+func testGetDataBlobRange(t *testing.T, rangeHeader string, size int64) *httptest.ResponseRecorder {
+	return testGetDataBlobRangeErr(t, rangeHeader, size, nil)
+}
+
+func testGetDataBlobRangeErr(t *testing.T, rangeHeader string, size int64, rangeErr error) *httptest.ResponseRecorder {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Range", rangeHeader)
+	res := httptest.NewRecorder()
+
+	content := []byte("hello world")
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+		Size: size,
+	}
+	mdm.On("GetDataBlob", mock.Anything, "abcd1234").Return(blob, nil)
+	mdm.On("OpenBlob", mock.Anything, blob).Return(ioutil.NopCloser(bytes.NewReader(content)), nil).Maybe()
+	mdm.On("OpenBlobRange", mock.Anything, blob, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, blob *core.Blob, offset, length int64) io.ReadCloser {
+			if rangeErr != nil {
+				return nil
+			}
+			return ioutil.NopCloser(bytes.NewReader(content[offset : offset+length]))
+		},
+		rangeErr,
+	).Maybe()
+	r.ServeHTTP(res, req)
+	return res
+}
+
+func TestGetDataBlobRange(t *testing.T) {
+	res := testGetDataBlobRange(t, "bytes=6-9", 11)
+	assert.Equal(t, 206, res.Result().StatusCode)
+	assert.Equal(t, "worl", res.Body.String())
+	assert.Equal(t, "bytes 6-9/11", res.Result().Header.Get("Content-Range"))
+	assert.Equal(t, "bytes", res.Result().Header.Get("Accept-Ranges"))
+}
+
+func TestGetDataBlobRangeOpenEnded(t *testing.T) {
+	res := testGetDataBlobRange(t, "bytes=6-", 11)
+	assert.Equal(t, 206, res.Result().StatusCode)
+	assert.Equal(t, "world", res.Body.String())
+	assert.Equal(t, "bytes 6-10/11", res.Result().Header.Get("Content-Range"))
+}
+
+func TestGetDataBlobRangeSuffix(t *testing.T) {
+	res := testGetDataBlobRange(t, "bytes=-3", 11)
+	assert.Equal(t, 206, res.Result().StatusCode)
+	assert.Equal(t, "rld", res.Body.String())
+	assert.Equal(t, "bytes 8-10/11", res.Result().Header.Get("Content-Range"))
+}
+
+func TestGetDataBlobRangeMultipleIgnored(t *testing.T) {
+	res := testGetDataBlobRange(t, "bytes=0-1,3-4", 11)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "hello world", res.Body.String())
+}
+
+func TestGetDataBlobRangeNotSatisfiable(t *testing.T) {
+	res := testGetDataBlobRange(t, "bytes=20-", 11)
+	assert.Equal(t, 416, res.Result().StatusCode)
+	assert.Regexp(t, "FF10551", res.Body.String())
+	assert.Equal(t, "bytes */11", res.Result().Header.Get("Content-Range"))
+}
+
+func TestGetDataBlobRangeDownloadFail(t *testing.T) {
+	res := testGetDataBlobRangeErr(t, "bytes=6-", 11, i18n.NewError(context.Background(), coremsgs.MsgBlobStreamingFailed))
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.Regexp(t, "FF10217", res.Body.String())
+	assert.Empty(t, res.Result().Header.Get("Content-Range"))
+}
+
+func TestParseBlobRange(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		header string
+		start  int64
+		end    int64
+		ranged bool
+		err    string
+	}{
+		{header: ""},
+		{header: "items=0-1"},
+		{header: "bytes=1"},
+		{header: "bytes=-"},
+		{header: "bytes=-x"},
+		{header: "bytes=x-1"},
+		{header: "bytes=5-1"},
+		{header: "bytes=1-x"},
+		{header: "bytes=-0", err: "FF10551"},
+		{header: "bytes=10-", err: "FF10551"},
+		{header: "bytes=-20", start: 0, end: 9, ranged: true},
+		{header: "bytes=2-20", start: 2, end: 9, ranged: true},
+		{header: "bytes= 0-0", start: 0, end: 0, ranged: true},
+	} {
+		start, end, ranged, err := parseBlobRange(ctx, tc.header, 10)
+		if tc.err != "" {
+			assert.Regexp(t, tc.err, err, tc.header)
+			continue
+		}
+		assert.NoError(t, err, tc.header)
+		assert.Equal(t, tc.ranged, ranged, tc.header)
+		assert.Equal(t, tc.start, start, tc.header)
+		assert.Equal(t, tc.end, end, tc.header)
+	}
+}
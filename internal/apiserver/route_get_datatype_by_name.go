@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -32,14 +33,22 @@ var getDatatypeByName = &ffapi.Route{
 		{Name: "name", Description: coremsgs.APIParamsDatatypeName},
 		{Name: "version", Description: coremsgs.APIParamsDatatypeVersion},
 	},
-	QueryParams:     nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "group", Description: coremsgs.APIDatatypeGroupQueryParam},
+	},
 	Description:     coremsgs.APIEndpointsGetDatatypeByName,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return &core.Datatype{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			output, err = cr.or.GetDatatypeByName(cr.ctx, r.PP["name"], r.PP["version"])
+			var group *fftypes.Bytes32
+			if r.QP["group"] != "" {
+				if group, err = fftypes.ParseBytes32(cr.ctx, r.QP["group"]); err != nil {
+					return nil, err
+				}
+			}
+			output, err = cr.or.GetDatatypeByName(cr.ctx, r.PP["name"], r.PP["version"], group)
 			return output, err
 		},
 	},
@@ -41,6 +41,9 @@ var getMsgs = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			if err := addDataValueFilters(r, database.MessageQueryFactory); err != nil {
+				return nil, err
+			}
 			if strings.EqualFold(r.QP["fetchdata"], "true") {
 				return r.FilterResult(cr.or.GetMessagesWithData(cr.ctx, r.Filter))
 			}
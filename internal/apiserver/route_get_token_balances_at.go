@@ -0,0 +1,72 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var tokenBalancePointParams = []*ffapi.QueryParam{
+	{Name: "timestamp", Description: coremsgs.APIParamsTokenBalanceTimestamp},
+	{Name: "sequence", Description: coremsgs.APIParamsTokenBalanceSequence},
+}
+
+func parseTokenBalancePoint(ctx context.Context, qp map[string]string) (point *core.TokenBalancePoint, err error) {
+	point = &core.TokenBalancePoint{}
+	if ts := qp["timestamp"]; ts != "" {
+		if point.Timestamp, err = fftypes.ParseTimeString(ts); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidTokenBalancePoint, "timestamp", ts)
+		}
+	}
+	if seq := qp["sequence"]; seq != "" {
+		if point.Sequence, err = strconv.ParseInt(seq, 10, 64); err != nil || point.Sequence <= 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidTokenBalancePoint, "sequence", seq)
+		}
+	}
+	return point, nil
+}
+
+var getTokenBalancesAt = &ffapi.Route{
+	Name:            "getTokenBalancesAt",
+	Path:            "tokens/balances/at",
+	Method:          http.MethodGet,
+	PathParams:      nil,
+	QueryParams:     tokenBalancePointParams,
+	FilterFactory:   database.TokenBalanceQueryFactory,
+	Description:     coremsgs.APIEndpointsGetTokenBalancesAt,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []*core.TokenBalance{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			point, err := parseTokenBalancePoint(cr.ctx, r.QP)
+			if err != nil {
+				return nil, err
+			}
+			return r.FilterResult(cr.or.Assets().GetTokenBalancesAt(cr.ctx, point, r.Filter))
+		},
+	},
+}
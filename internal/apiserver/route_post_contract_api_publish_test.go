@@ -22,6 +22,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func TestPostContractAPIPublish(t *testing.T) {
 	res := httptest.NewRecorder()
 	api := &core.ContractAPI{}
 
-	mds.On("PublishContractAPI", mock.Anything, "http://127.0.0.1:5000/api/v1", "banana", "banana-net", false).Return(api, nil)
+	mds.On("PublishContractAPI", mock.Anything, "http://127.0.0.1:5000/api/v1", "banana", "banana-net", (*fftypes.Bytes32)(nil), false).Return(api, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 202, res.Result().StatusCode)
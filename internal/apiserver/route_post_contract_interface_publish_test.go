@@ -42,7 +42,7 @@ func TestPostContractInterfacePublish(t *testing.T) {
 	res := httptest.NewRecorder()
 	ffi := &fftypes.FFI{}
 
-	mds.On("PublishFFI", mock.Anything, "ffi1", "1.0", "", false).Return(ffi, nil)
+	mds.On("PublishFFI", mock.Anything, "ffi1", "1.0", "", (*fftypes.Bytes32)(nil), false).Return(ffi, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 202, res.Result().StatusCode)
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -32,6 +33,7 @@ var postNewDatatype = &ffapi.Route{
 	PathParams: nil,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "confirm", Description: coremsgs.APIConfirmQueryParam, IsBool: true, Example: "true"},
+		{Name: "group", Description: coremsgs.APIDefinitionGroupQueryParam},
 	},
 	Description:     coremsgs.APIEndpointsPostNewDatatype,
 	JSONInputValue:  func() interface{} { return &core.Datatype{} },
@@ -41,7 +43,13 @@ var postNewDatatype = &ffapi.Route{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			err = cr.or.DefinitionSender().DefineDatatype(cr.ctx, r.Input.(*core.Datatype), waitConfirm)
+			var group *fftypes.Bytes32
+			if r.QP["group"] != "" {
+				if group, err = fftypes.ParseBytes32(cr.ctx, r.QP["group"]); err != nil {
+					return nil, err
+				}
+			}
+			err = cr.or.DefinitionSender().DefineDatatype(cr.ctx, r.Input.(*core.Datatype), group, waitConfirm)
 			return r.Input, err
 		},
 	},
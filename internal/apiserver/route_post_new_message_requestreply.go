@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -26,21 +27,25 @@ import (
 )
 
 var postNewMessageRequestReply = &ffapi.Route{
-	Name:            "postNewMessageRequestReply",
-	Path:            "messages/requestreply",
-	Method:          http.MethodPost,
-	PathParams:      nil,
-	QueryParams:     nil,
+	Name:       "postNewMessageRequestReply",
+	Path:       "messages/requestreply",
+	Method:     http.MethodPost,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "async", Description: coremsgs.APIAsyncRequestReplyQueryParam, IsBool: true},
+	},
 	Description:     coremsgs.APIEndpointsPostNewMessageRequestReply,
 	JSONInputValue:  func() interface{} { return &core.MessageInOut{} },
 	JSONOutputValue: func() interface{} { return &core.MessageInOut{} },
-	JSONOutputCodes: []int{http.StatusOK}, // Sync operation
+	JSONOutputCodes: []int{http.StatusOK, http.StatusAccepted},
 	Extensions: &coreExtensions{
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.MultiParty() != nil
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			output, err = cr.or.RequestReply(cr.ctx, r.Input.(*core.MessageInOut))
+			async := strings.EqualFold(r.QP["async"], "true")
+			r.SuccessStatus = syncRetcode(!async)
+			output, err = cr.or.RequestReply(cr.ctx, r.Input.(*core.MessageInOut), async)
 			return output, err
 		},
 	},
@@ -33,7 +33,7 @@ func TestPostNewMessageRequestReply(t *testing.T) {
 	o, r := newTestAPIServer()
 	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
 	o.On("PrivateMessaging").Return(&privatemessagingmocks.Manager{})
-	o.On("RequestReply", mock.Anything, mock.Anything).Return(&core.MessageInOut{}, nil)
+	o.On("RequestReply", mock.Anything, mock.Anything, false).Return(&core.MessageInOut{}, nil)
 	mmp := &multipartymocks.Manager{}
 	o.On("MultiParty").Return(mmp)
 	input := &core.MessageInOut{}
@@ -46,3 +46,21 @@ func TestPostNewMessageRequestReply(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestPostNewMessageRequestReplyAsync(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("PrivateMessaging").Return(&privatemessagingmocks.Manager{})
+	o.On("RequestReply", mock.Anything, mock.Anything, true).Return(&core.MessageInOut{}, nil)
+	mmp := &multipartymocks.Manager{}
+	o.On("MultiParty").Return(mmp)
+	input := &core.MessageInOut{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/requestreply?async", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+}
@@ -44,7 +44,7 @@ var postTokenPoolPublish = &ffapi.Route{
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
 			input := r.Input.(*core.DefinitionPublish)
-			return cr.or.DefinitionSender().PublishTokenPool(cr.ctx, r.PP["nameOrId"], input.NetworkName, waitConfirm)
+			return cr.or.DefinitionSender().PublishTokenPool(cr.ctx, r.PP["nameOrId"], input.NetworkName, input.Group, waitConfirm)
 		},
 	},
 }
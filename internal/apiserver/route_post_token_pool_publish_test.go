@@ -22,6 +22,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func TestPostTokenPoolPublish(t *testing.T) {
 	res := httptest.NewRecorder()
 	pool := &core.TokenPool{}
 
-	mds.On("PublishTokenPool", mock.Anything, "pool1", "", false).Return(pool, nil)
+	mds.On("PublishTokenPool", mock.Anything, "pool1", "", (*fftypes.Bytes32)(nil), false).Return(pool, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 202, res.Result().StatusCode)
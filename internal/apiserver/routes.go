@@ -56,7 +56,9 @@ var routes = append(
 		deleteContractInterface,
 		deleteContractListener,
 		deleteData,
+		deleteDataUpload,
 		deleteSubscription,
+		deleteSubscriptionDeadLetters,
 		deleteTokenPool,
 		getBatchByID,
 		getBatches,
@@ -77,9 +79,12 @@ var routes = append(
 		getDataSubPaths,
 		getDataValue,
 		getDataByID,
+		getDataUploadByID,
 		getDataMsgs,
 		getDatatypeByName,
 		getDatatypes,
+		getDefinitionProposalByID,
+		getDefinitionProposals,
 		getEventByID,
 		getEvents,
 		getGroupByHash,
@@ -92,9 +97,13 @@ var routes = append(
 		getMsgByID,
 		getMsgData,
 		getMsgEvents,
+		getMsgReplies,
 		getMsgs,
 		getMsgTxn,
+		getNetworkApplicationByID,
+		getNetworkApplications,
 		getNetworkDIDDocByDID,
+		getNetworkGovernance,
 		getNetworkIdentities,
 		getNetworkIdentityByDID,
 		getNetworkNode,
@@ -108,14 +117,20 @@ var routes = append(
 		getStatus,
 		getStatusBatchManager,
 		getSubscriptionByID,
+		getSubscriptionDeadLetters,
 		getSubscriptions,
 		getTokenAccountPools,
 		getTokenAccounts,
 		getTokenApprovals,
+		getTokenBalanceHistory,
+		getTokenBalanceMismatches,
 		getTokenBalances,
+		getTokenBalancesAt,
 		getTokenConnectors,
+		getTokenPoolBalanceSnapshot,
 		getTokenPoolByNameOrID,
 		getTokenPools,
+		getTokenTransferBatch,
 		getTokenTransferByID,
 		getTokenTransfers,
 		getTxnBlockchainEvents,
@@ -137,8 +152,16 @@ var routes = append(
 		postContractQuery,
 		postData,
 		postDataBlobPublish,
+		postDataUpload,
+		postDataUploadFinalize,
 		postDataValuePublish,
+		postDefinitionProposalVote,
+		postIdentityRevoke,
+		postIdentityRotateKey,
 		postNetworkAction,
+		postNetworkApplication,
+		postNetworkApplicationReview,
+		postNetworkGovernance,
 		postNewContractAPI,
 		postNewContractInterface,
 		postNewContractListener,
@@ -153,13 +176,19 @@ var routes = append(
 		postNodesSelf,
 		postOpRetry,
 		postPinsRewind,
+		postSubscriptionDeadLetterReplay,
 		postTokenApproval,
 		postTokenBurn,
+		postTokenBurnBatch,
 		postTokenMint,
+		postTokenMintBatch,
 		postTokenPool,
 		postTokenPoolPublish,
+		postTokenReconcile,
 		postTokenTransfer,
+		postTokenTransferBatch,
 		putContractAPI,
+		putDataUploadChunk,
 		putSubscription,
 		postVerifiersResolve,
 	})...,
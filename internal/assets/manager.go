@@ -19,11 +19,13 @@ package assets
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/broadcast"
 	"github.com/hyperledger/firefly/internal/contracts"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/identity"
 	"github.com/hyperledger/firefly/internal/metrics"
@@ -51,6 +53,11 @@ type Manager interface {
 	GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
 	GetTokenAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAccount, *ffapi.FilterResult, error)
 	GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)
+	GetTokenBalanceHistory(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error)
+	GetTokenBalancesAt(ctx context.Context, point *core.TokenBalancePoint, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
+	GetTokenPoolBalanceSnapshot(ctx context.Context, poolNameOrID string, point *core.TokenBalancePoint) (*core.TokenBalanceSnapshot, error)
+	ReconcileTokenBalances(ctx context.Context, input *core.TokenReconcileInput) (*core.TokenReconciliation, error)
+	GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)
 
 	GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)
 	GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error)
@@ -59,6 +66,10 @@ type Manager interface {
 	MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	BurnTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	TransferTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
+	MintTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)
+	BurnTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)
+	TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)
+	GetTokenTransferBatch(ctx context.Context, txID string) (*core.TokenTransferBatch, error)
 
 	GetTokenConnectors(ctx context.Context) []*core.TokenConnector
 
@@ -72,19 +83,20 @@ type Manager interface {
 }
 
 type assetManager struct {
-	ctx              context.Context
-	namespace        string
-	database         database.Plugin
-	txHelper         txcommon.Helper
-	identity         identity.Manager
-	syncasync        syncasync.Bridge
-	broadcast        broadcast.Manager        // optional
-	messaging        privatemessaging.Manager // optional
-	tokens           map[string]tokens.Plugin
-	metrics          metrics.Manager
-	operations       operations.Manager
-	contracts        contracts.Manager
-	keyNormalization int
+	ctx                context.Context
+	namespace          string
+	database           database.Plugin
+	txHelper           txcommon.Helper
+	identity           identity.Manager
+	syncasync          syncasync.Bridge
+	broadcast          broadcast.Manager        // optional
+	messaging          privatemessaging.Manager // optional
+	tokens             map[string]tokens.Plugin
+	metrics            metrics.Manager
+	operations         operations.Manager
+	contracts          contracts.Manager
+	keyNormalization   int
+	transferBatchLimit int
 }
 
 func NewAssetManager(ctx context.Context, ns, keyNormalization string, di database.Plugin, ti map[string]tokens.Plugin, im identity.Manager, sa syncasync.Bridge, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, om operations.Manager, cm contracts.Manager, txHelper txcommon.Helper) (Manager, error) {
@@ -92,25 +104,27 @@ func NewAssetManager(ctx context.Context, ns, keyNormalization string, di databa
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "AssetManager")
 	}
 	am := &assetManager{
-		ctx:              ctx,
-		namespace:        ns,
-		database:         di,
-		txHelper:         txHelper,
-		identity:         im,
-		syncasync:        sa,
-		broadcast:        bm,
-		messaging:        pm,
-		tokens:           ti,
-		keyNormalization: identity.ParseKeyNormalizationConfig(keyNormalization),
-		metrics:          mm,
-		operations:       om,
-		contracts:        cm,
+		ctx:                ctx,
+		namespace:          ns,
+		database:           di,
+		txHelper:           txHelper,
+		identity:           im,
+		syncasync:          sa,
+		broadcast:          bm,
+		messaging:          pm,
+		tokens:             ti,
+		keyNormalization:   identity.ParseKeyNormalizationConfig(keyNormalization),
+		metrics:            mm,
+		operations:         om,
+		contracts:          cm,
+		transferBatchLimit: config.GetInt(coreconfig.AssetManagerTransferBatchLimit),
 	}
 	om.RegisterHandler(ctx, am, []core.OpType{
 		core.OpTypeTokenCreatePool,
 		core.OpTypeTokenActivatePool,
 		core.OpTypeTokenTransfer,
 		core.OpTypeTokenApproval,
+		core.OpTypeTokenReconcile,
 	})
 	return am, nil
 }
@@ -140,6 +154,40 @@ func (am *assetManager) GetTokenAccountPools(ctx context.Context, key string, fi
 	return am.database.GetTokenAccountPools(ctx, am.namespace, key, filter)
 }
 
+func (am *assetManager) GetTokenBalanceHistory(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error) {
+	return am.database.GetTokenBalanceHistory(ctx, am.namespace, filter)
+}
+
+func (am *assetManager) GetTokenBalancesAt(ctx context.Context, point *core.TokenBalancePoint, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	return am.database.GetTokenBalancesAt(ctx, am.namespace, point, filter)
+}
+
+func (am *assetManager) GetTokenPoolBalanceSnapshot(ctx context.Context, poolNameOrID string, point *core.TokenBalancePoint) (*core.TokenBalanceSnapshot, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	balances, _, err := am.database.GetTokenBalancesAt(ctx, am.namespace, point, fb.And(fb.Eq("pool", pool.ID)).Sort("key").Sort("tokenindex"))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &core.TokenBalanceSnapshot{
+		Pool:      pool.ID,
+		Timestamp: point.Timestamp,
+		Sequence:  point.Sequence,
+		Balances:  make([]*core.TokenBalance, 0, len(balances)),
+	}
+	for _, balance := range balances {
+		if balance.Balance.Int().Sign() != 0 {
+			snapshot.Balances = append(snapshot.Balances, balance)
+		}
+	}
+	return snapshot, nil
+}
+
 func (am *assetManager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
 	connectors := []*core.TokenConnector{}
 	for token := range am.tokens {
@@ -17,9 +17,11 @@ package assets
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -129,6 +131,82 @@ func TestGetTokenAccountPools(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetTokenBalanceHistory(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenBalanceChangeQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenBalanceHistory", context.Background(), "ns1", f).Return([]*core.TokenBalanceChange{}, nil, nil)
+	_, _, err := am.GetTokenBalanceHistory(context.Background(), f)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenBalancesAt(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenBalanceQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	point := &core.TokenBalancePoint{Sequence: 10}
+	mdi.On("GetTokenBalancesAt", context.Background(), "ns1", point, f).Return([]*core.TokenBalance{}, nil, nil)
+	_, _, err := am.GetTokenBalancesAt(context.Background(), point, f)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenPoolBalanceSnapshot(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	point := &core.TokenBalancePoint{Timestamp: fftypes.Now()}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenBalancesAt", context.Background(), "ns1", point, mock.Anything).Return([]*core.TokenBalance{
+		{Pool: pool.ID, Key: "0x1", Balance: *fftypes.NewFFBigInt(10)},
+		{Pool: pool.ID, Key: "0x2", Balance: *fftypes.NewFFBigInt(0)},
+	}, nil, nil)
+
+	snapshot, err := am.GetTokenPoolBalanceSnapshot(context.Background(), "pool1", point)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, snapshot.Pool)
+	assert.Equal(t, point.Timestamp, snapshot.Timestamp)
+	assert.Len(t, snapshot.Balances, 1)
+	assert.Equal(t, "0x1", snapshot.Balances[0].Key)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenPoolBalanceSnapshotBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.GetTokenPoolBalanceSnapshot(context.Background(), "pool1", &core.TokenBalancePoint{})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenPoolBalanceSnapshotFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenBalancesAt", context.Background(), "ns1", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenPoolBalanceSnapshot(context.Background(), "pool1", &core.TokenBalancePoint{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestGetTokenConnectors(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
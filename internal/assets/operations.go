@@ -46,6 +46,12 @@ type approvalData struct {
 	Approval *core.TokenApproval `json:"approval"`
 }
 
+type reconcileData struct {
+	Reconciliation *fftypes.UUID   `json:"reconciliation"`
+	Pool           *core.TokenPool `json:"pool"`
+	Correct        bool            `json:"correct"`
+}
+
 func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	switch op.Type {
 	case core.OpTypeTokenCreatePool:
@@ -94,6 +100,19 @@ func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation
 		}
 		return opApproval(op, pool, approval), nil
 
+	case core.OpTypeTokenReconcile:
+		poolID, correct, err := txcommon.RetrieveTokenReconcileInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := am.GetTokenPoolByID(ctx, poolID)
+		if err != nil {
+			return nil, err
+		} else if pool == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opReconcile(op, op.Transaction, pool, correct), nil
+
 	default:
 		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
 	}
@@ -140,6 +159,22 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 		}
 		return nil, false, plugin.TokensApproval(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Approval, data.Pool.Methods)
 
+	case reconcileData:
+		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		if err != nil {
+			return nil, false, err
+		}
+		result, err := am.reconcilePool(ctx, data.Reconciliation, plugin, data.Pool, data.Correct)
+		if err != nil {
+			return nil, false, err
+		}
+		return fftypes.JSONObject{
+			"blockNumber": result.BlockNumber,
+			"checked":     result.Checked,
+			"mismatches":  result.Mismatches,
+			"corrected":   result.Corrected,
+		}, true, nil
+
 	default:
 		return nil, false, i18n.NewError(ctx, coremsgs.MsgOperationDataIncorrect, op.Data)
 	}
@@ -242,3 +277,13 @@ func opApproval(op *core.Operation, pool *core.TokenPool, approval *core.TokenAp
 		Data:      approvalData{Pool: pool, Approval: approval},
 	}
 }
+
+func opReconcile(op *core.Operation, reconciliationID *fftypes.UUID, pool *core.TokenPool, correct bool) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      reconcileData{Reconciliation: reconciliationID, Pool: pool, Correct: correct},
+	}
+}
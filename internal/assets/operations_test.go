@@ -172,6 +172,43 @@ func TestPrepareAndRunApproval(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareAndRunReconcile(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:        core.OpTypeTokenReconcile,
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Transaction: fftypes.NewUUID(),
+	}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		Locator:   "F1",
+	}
+	txcommon.AddTokenReconcileInputs(op, pool.ID, true)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{}, nil, nil)
+
+	po, err := am.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, pool, po.Data.(reconcileData).Pool)
+	assert.Equal(t, op.Transaction, po.Data.(reconcileData).Reconciliation)
+	assert.True(t, po.Data.(reconcileData).Correct)
+
+	outputs, complete, err := am.RunOperation(context.Background(), po)
+
+	assert.True(t, complete)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), outputs["checked"])
+
+	mdi.AssertExpectations(t)
+}
+
 func TestPrepareOperationNotSupported(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -348,6 +385,57 @@ func TestPrepareOperationApprovalNotFound(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareOperationReconcileBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeTokenReconcile,
+		Input: fftypes.JSONObject{"pool": "bad"},
+	}
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestPrepareOperationReconcileError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	poolID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeTokenReconcile,
+		Input: fftypes.JSONObject{"pool": poolID.String()},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", poolID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationReconcileNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	poolID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeTokenReconcile,
+		Input: fftypes.JSONObject{"pool": poolID.String()},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", poolID).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestRunOperationNotSupported(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -435,6 +523,35 @@ func TestRunOperationApprovalBadPlugin(t *testing.T) {
 	assert.Regexp(t, "FF10272", err)
 }
 
+func TestRunOperationReconcileBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{}
+
+	_, complete, err := am.RunOperation(context.Background(), opReconcile(op, fftypes.NewUUID(), pool, false))
+
+	assert.False(t, complete)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestRunOperationReconcileFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{Connector: "magic-tokens"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, complete, err := am.RunOperation(context.Background(), opReconcile(op, fftypes.NewUUID(), pool, false))
+
+	assert.False(t, complete)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestRunOperationTransferUnknownType(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -0,0 +1,268 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+// reconcilePageSize is the number of local balances read from the database at a time during reconciliation
+const reconcilePageSize = 100
+
+func (am *assetManager) GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	return am.database.GetTokenBalanceMismatches(ctx, am.namespace, filter)
+}
+
+// reconcileResult tracks the progress of a token_reconcile operation, and is returned as its output
+type reconcileResult struct {
+	BlockNumber string
+	Checked     int64
+	Mismatches  int64
+	Corrected   int64
+}
+
+// ReconcileTokenBalances submits a transaction with a token_reconcile operation for each pool, and runs the
+// operations in the background. Each operation checks the local token balances of its pool against the balances
+// reported by the token connector, recording a mismatch for every balance that differs.
+//
+// Only balances already stored locally are checked - the connector is not asked to discover other accounts.
+func (am *assetManager) ReconcileTokenBalances(ctx context.Context, input *core.TokenReconcileInput) (*core.TokenReconciliation, error) {
+	pools, err := am.getPoolsToReconcile(ctx, input.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := &core.TokenReconciliation{
+		Pools:   []*fftypes.UUID{},
+		Correct: input.Correct,
+		Created: fftypes.Now(),
+	}
+	var ops []*core.PreparedOperation
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		reconciliation.ID, err = am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenReconcile, "")
+		if err != nil {
+			return err
+		}
+		for _, pool := range pools {
+			plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+			if err != nil {
+				return err
+			}
+			if !plugin.Capabilities().BalanceQuery {
+				if input.Pool != "" {
+					return i18n.NewError(ctx, coremsgs.MsgTokenBalanceQueryNotSupported, pool.Connector)
+				}
+				reconciliation.Skipped = append(reconciliation.Skipped, pool.ID)
+				continue
+			}
+			op := core.NewOperation(plugin, am.namespace, reconciliation.ID, core.OpTypeTokenReconcile)
+			txcommon.AddTokenReconcileInputs(op, pool.ID, input.Correct)
+			if err := am.operations.AddOrReuseOperation(ctx, op); err != nil {
+				return err
+			}
+			reconciliation.Pools = append(reconciliation.Pools, pool.ID)
+			ops = append(ops, opReconcile(op, reconciliation.ID, pool, input.Correct))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go am.runReconcileOperations(ops)
+	return reconciliation, nil
+}
+
+// runReconcileOperations runs the operations of a reconciliation one after another, so that only one
+// pool is being queried from the connectors at a time
+func (am *assetManager) runReconcileOperations(ops []*core.PreparedOperation) {
+	for _, op := range ops {
+		if _, err := am.operations.RunOperation(am.ctx, op); err != nil {
+			log.L(am.ctx).Errorf("Token balance reconciliation operation %s failed: %s", op.ID, err)
+		}
+	}
+}
+
+func (am *assetManager) getPoolsToReconcile(ctx context.Context, poolNameOrID string) ([]*core.TokenPool, error) {
+	if poolNameOrID != "" {
+		pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+		if err != nil {
+			return nil, err
+		}
+		if pool.State != core.TokenPoolStateConfirmed {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotConfirmed)
+		}
+		return []*core.TokenPool{pool}, nil
+	}
+	fb := database.TokenPoolQueryFactory.NewFilter(ctx)
+	pools, _, err := am.database.GetTokenPools(ctx, am.namespace, fb.And(fb.Eq("state", core.TokenPoolStateConfirmed)))
+	return pools, err
+}
+
+// confirmedBlockNumber returns the block number of the most recent blockchain event that has been processed
+// locally for a pool, or an empty string if it is not known
+func (am *assetManager) confirmedBlockNumber(ctx context.Context, pool *core.TokenPool) (string, error) {
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	transfers, _, err := am.database.GetTokenTransfers(ctx, am.namespace,
+		fb.And(fb.Eq("pool", pool.ID)).Limit(1))
+	if err != nil || len(transfers) == 0 || transfers[0].BlockchainEvent == nil {
+		return "", err
+	}
+	event, err := am.database.GetBlockchainEventByID(ctx, am.namespace, transfers[0].BlockchainEvent)
+	if err != nil || event == nil {
+		return "", err
+	}
+	return event.Info.GetString("blockNumber"), nil
+}
+
+func (am *assetManager) reconcilePool(ctx context.Context, reconciliationID *fftypes.UUID, plugin tokens.Plugin, pool *core.TokenPool, correct bool) (*reconcileResult, error) {
+	// The connector is asked for balances as of the latest block this node has confirmed for the pool, so that
+	// corrections never include transfers that are still to be delivered on the local event stream
+	blockNumber, err := am.confirmedBlockNumber(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &reconcileResult{BlockNumber: blockNumber}
+	fb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	for skip := uint64(0); ; skip += reconcilePageSize {
+		balances, _, err := am.database.GetTokenBalances(ctx, am.namespace,
+			fb.And(fb.Eq("pool", pool.ID)).Skip(skip).Limit(reconcilePageSize))
+		if err != nil {
+			return nil, err
+		}
+		for _, balance := range balances {
+			if err := am.reconcileBalance(ctx, reconciliationID, result, plugin, pool, balance, correct); err != nil {
+				return nil, err
+			}
+		}
+		if len(balances) < reconcilePageSize {
+			break
+		}
+	}
+
+	log.L(ctx).Infof("Token balance reconciliation %s checked %d balances in pool %s at block '%s': mismatches=%d corrected=%d",
+		reconciliationID, result.Checked, pool.ID, blockNumber, result.Mismatches, result.Corrected)
+	return result, nil
+}
+
+func (am *assetManager) reconcileBalance(ctx context.Context, reconciliationID *fftypes.UUID, result *reconcileResult, plugin tokens.Plugin, pool *core.TokenPool, balance *core.TokenBalance, correct bool) error {
+	chainBalance, err := plugin.QueryBalance(ctx, pool.Locator, balance.TokenIndex, balance.Key, result.BlockNumber)
+	if err != nil {
+		return err
+	}
+	result.Checked++
+	if chainBalance.Balance.Int().Cmp(balance.Balance.Int()) == 0 {
+		return nil
+	}
+
+	// A balance read at any other block than the one confirmed locally can only be reported, as the
+	// difference might be made up of transfers that are still to be processed
+	correct = correct && result.BlockNumber != "" && chainBalance.BlockNumber == result.BlockNumber
+	mismatch := &core.TokenBalanceMismatch{
+		ID:             fftypes.NewUUID(),
+		Reconciliation: reconciliationID,
+		Namespace:      am.namespace,
+		Pool:           pool.ID,
+		TokenIndex:     balance.TokenIndex,
+		Connector:      pool.Connector,
+		Key:            balance.Key,
+		LocalBalance:   balance.Balance,
+		ChainBalance:   *chainBalance.Balance,
+		Created:        fftypes.Now(),
+	}
+	recorded := false
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		// Transfers might have been confirmed while the connector was queried, so the mismatch is
+		// only recorded if the local balance is unchanged since it was read
+		current, err := am.database.GetTokenBalance(ctx, am.namespace, pool.ID, balance.TokenIndex, balance.Key)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Balance.Int().Cmp(balance.Balance.Int()) != 0 {
+			log.L(ctx).Debugf("Token balance %s changed during reconciliation", balance.Identifier())
+			return nil
+		}
+		if correct {
+			// Any transfer in a later block confirmed since the pool was read invalidates the comparison
+			blockNumber, err := am.confirmedBlockNumber(ctx, pool)
+			if err != nil {
+				return err
+			}
+			mismatch.Corrected = blockNumber == result.BlockNumber
+		}
+		if mismatch.Corrected {
+			if err := am.database.UpdateTokenBalances(ctx, balanceCorrection(current, chainBalance.Balance), nil); err != nil {
+				return err
+			}
+		}
+		if err := am.database.InsertTokenBalanceMismatch(ctx, mismatch); err != nil {
+			return err
+		}
+		if mismatch.Corrected {
+			event := core.NewEvent(core.EventTypeTokenBalanceCorrected, am.namespace, mismatch.ID, nil, pool.ID.String())
+			if err := am.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if recorded {
+		log.L(ctx).Warnf("Token balance %s does not match connector: local=%s chain=%s block='%s' corrected=%t",
+			balance.Identifier(), balance.Balance.String(), chainBalance.Balance.String(), chainBalance.BlockNumber, mismatch.Corrected)
+		result.Mismatches++
+		if mismatch.Corrected {
+			result.Corrected++
+		}
+	}
+	return nil
+}
+
+// balanceCorrection builds a transfer that moves a local balance to the balance reported by the connector.
+// It is only used to update the stored balance (and its history), and is never recorded as a transfer.
+func balanceCorrection(balance *core.TokenBalance, chainBalance *fftypes.FFBigInt) *core.TokenTransfer {
+	correction := &core.TokenTransfer{
+		Namespace:  balance.Namespace,
+		Pool:       balance.Pool,
+		TokenIndex: balance.TokenIndex,
+		URI:        balance.URI,
+		Connector:  balance.Connector,
+	}
+	diff := correction.Amount.Int().Sub(chainBalance.Int(), balance.Balance.Int())
+	if diff.Sign() < 0 {
+		diff.Neg(diff)
+		correction.From = balance.Key
+	} else {
+		correction.To = balance.Key
+	}
+	return correction
+}
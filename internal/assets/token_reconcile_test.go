@@ -0,0 +1,538 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestReconcilePool() *core.TokenPool {
+	return &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		State:     core.TokenPoolStateConfirmed,
+	}
+}
+
+func newTestReconcileBalance(pool *core.TokenPool, key string, balance int64) *core.TokenBalance {
+	return &core.TokenBalance{
+		Pool:       pool.ID,
+		TokenIndex: "1",
+		Connector:  pool.Connector,
+		Namespace:  "ns1",
+		Key:        key,
+		Balance:    *fftypes.NewFFBigInt(balance),
+	}
+}
+
+func mockReconcileBlock(mdi *databasemocks.Plugin, blockNumber string) {
+	eventID := fftypes.NewUUID()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{BlockchainEvent: eventID}}, nil, nil)
+	mdi.On("GetBlockchainEventByID", context.Background(), "ns1", eventID).Return(&core.BlockchainEvent{
+		Info: fftypes.JSONObject{"blockNumber": blockNumber},
+	}, nil)
+}
+
+func chainBalance(balance int64, blockNumber string) *tokens.Balance {
+	return &tokens.Balance{Balance: fftypes.NewFFBigInt(balance), BlockNumber: blockNumber}
+}
+
+func TestReconcileTokenBalances(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool1 := newTestReconcilePool()
+	pool2 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "other", State: core.TokenPoolStateConfirmed}
+	txID := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti2 := &tokenmocks.Plugin{}
+	am.tokens["other"] = mti2
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{pool1, pool2}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenReconcile, core.IdempotencyKey("")).Return(txID, nil)
+	mti.On("Capabilities").Return(&tokens.Capabilities{BalanceQuery: true})
+	mti2.On("Capabilities").Return(&tokens.Capabilities{})
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenReconcile && op.Transaction == txID &&
+			op.Input.GetString("pool") == pool1.ID.String() && op.Input.GetBool("correct")
+	})).Return(nil)
+	done := make(chan struct{})
+	mom.On("RunOperation", am.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(reconcileData)
+		return op.Type == core.OpTypeTokenReconcile && data.Pool == pool1 && data.Reconciliation == txID && data.Correct
+	})).Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(done)
+	})
+
+	reconciliation, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{Correct: true})
+	assert.NoError(t, err)
+	assert.Equal(t, txID, reconciliation.ID)
+	assert.Equal(t, []*fftypes.UUID{pool1.ID}, reconciliation.Pools)
+	assert.Equal(t, []*fftypes.UUID{pool2.ID}, reconciliation.Skipped)
+	assert.True(t, reconciliation.Correct)
+	<-done
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestReconcileTokenBalancesPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{Pool: "pool1"})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReconcileTokenBalancesPoolNotConfirmed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	pool.State = core.TokenPoolStatePending
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{Pool: "pool1"})
+	assert.Regexp(t, "FF10293", err)
+}
+
+func TestReconcileTokenBalancesPoolNotSupported(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenReconcile, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mti.On("Capabilities").Return(&tokens.Capabilities{})
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{Pool: "pool1"})
+	assert.Regexp(t, "FF10508.*magic-tokens", err)
+}
+
+func TestReconcileTokenBalancesGetPoolsFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenBalancesSubmitTransactionFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{newTestReconcilePool()}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenReconcile, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenBalancesBadConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	pool.Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{pool}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenReconcile, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{})
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestReconcileTokenBalancesAddOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{newTestReconcilePool()}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenReconcile, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mti.On("Capabilities").Return(&tokens.Capabilities{BalanceQuery: true})
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.ReconcileTokenBalances(context.Background(), &core.TokenReconcileInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolCorrect(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	matched := newTestReconcileBalance(pool, "0x1", 10)
+	higher := newTestReconcileBalance(pool, "0x2", 10)
+	lower := newTestReconcileBalance(pool, "0x3", 10)
+	changed := newTestReconcileBalance(pool, "0x4", 10)
+	reconciliationID := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{matched, higher, lower, changed}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(chainBalance(10, "42"), nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x2", "42").Return(chainBalance(15, "42"), nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x3", "42").Return(chainBalance(3, "42"), nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x4", "42").Return(chainBalance(12, "42"), nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x2").Return(higher, nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x3").Return(lower, nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x4").Return(newTestReconcileBalance(pool, "0x4", 12), nil)
+	mdi.On("UpdateTokenBalances", context.Background(), mock.MatchedBy(func(transfer *core.TokenTransfer) bool {
+		return transfer.To == "0x2" && transfer.From == "" && transfer.Amount.Int().Int64() == 5
+	}), (*fftypes.FFTime)(nil)).Return(nil)
+	mdi.On("UpdateTokenBalances", context.Background(), mock.MatchedBy(func(transfer *core.TokenTransfer) bool {
+		return transfer.From == "0x3" && transfer.To == "" && transfer.Amount.Int().Int64() == 7
+	}), (*fftypes.FFTime)(nil)).Return(nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.MatchedBy(func(mismatch *core.TokenBalanceMismatch) bool {
+		return mismatch.Corrected && mismatch.Pool == pool.ID && mismatch.Reconciliation == reconciliationID &&
+			mismatch.LocalBalance.Int().Int64() == 10
+	})).Return(nil).Twice()
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeTokenBalanceCorrected && event.Topic == pool.ID.String()
+	})).Return(nil).Twice()
+
+	result, err := am.reconcilePool(context.Background(), reconciliationID, mti, pool, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", result.BlockNumber)
+	assert.Equal(t, int64(4), result.Checked)
+	assert.Equal(t, int64(2), result.Mismatches)
+	assert.Equal(t, int64(2), result.Corrected)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcilePoolUnconfirmedBlock(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(chainBalance(15, "45"), nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x1").Return(balance, nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.MatchedBy(func(mismatch *core.TokenBalanceMismatch) bool {
+		return !mismatch.Corrected && mismatch.ChainBalance.Int().Int64() == 15
+	})).Return(nil)
+
+	result, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Mismatches)
+	assert.Equal(t, int64(0), result.Corrected)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcilePoolBlockConfirmedDuringQuery(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+	event1 := fftypes.NewUUID()
+	event2 := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{BlockchainEvent: event1}}, nil, nil).Once()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{BlockchainEvent: event2}}, nil, nil).Once()
+	mdi.On("GetBlockchainEventByID", context.Background(), "ns1", event1).Return(&core.BlockchainEvent{
+		Info: fftypes.JSONObject{"blockNumber": "42"},
+	}, nil)
+	mdi.On("GetBlockchainEventByID", context.Background(), "ns1", event2).Return(&core.BlockchainEvent{
+		Info: fftypes.JSONObject{"blockNumber": "43"},
+	}, nil)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(chainBalance(15, "42"), nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x1").Return(balance, nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.MatchedBy(func(mismatch *core.TokenBalanceMismatch) bool {
+		return !mismatch.Corrected
+	})).Return(nil)
+
+	result, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Mismatches)
+	assert.Equal(t, int64(0), result.Corrected)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcilePoolReportOnly(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+	removed := newTestReconcileBalance(pool, "0x2", 10)
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance, removed}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "").Return(chainBalance(0, ""), nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x2", "").Return(chainBalance(0, ""), nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x1").Return(balance, nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", pool.ID, "1", "0x2").Return(nil, nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.MatchedBy(func(mismatch *core.TokenBalanceMismatch) bool {
+		return !mismatch.Corrected && mismatch.Key == "0x1" && mismatch.ChainBalance.Int().Sign() == 0
+	})).Return(nil)
+
+	result, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Checked)
+	assert.Equal(t, int64(1), result.Mismatches)
+	assert.Equal(t, int64(0), result.Corrected)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcilePoolPaging(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	balances := make([]*core.TokenBalance, reconcilePageSize)
+	for i := range balances {
+		balances[i] = newTestReconcileBalance(pool, "0x1", 10)
+	}
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return(balances, nil, nil).Once()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{}, nil, nil).Once()
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(chainBalance(10, "42"), nil)
+
+	result, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(reconcilePageSize), result.Checked)
+	assert.Equal(t, int64(0), result.Mismatches)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcilePoolGetTransfersFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, newTestReconcilePool(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolGetEventFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{BlockchainEvent: fftypes.NewUUID()}}, nil, nil)
+	mdi.On("GetBlockchainEventByID", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, newTestReconcilePool(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolGetBalancesFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, newTestReconcilePool(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolQueryBalanceFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func mockReconcileMismatch(am *assetManager) (*core.TokenPool, *tokenmocks.Plugin, *databasemocks.Plugin, *core.TokenBalance) {
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance}, nil, nil)
+	mti.On("QueryBalance", context.Background(), "F1", "1", "0x1", "42").Return(chainBalance(5, "42"), nil)
+	return pool, mti, mdi, balance
+}
+
+func TestReconcilePoolGetBalanceFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, mti, mdi, _ := mockReconcileMismatch(am)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalance", context.Background(), "ns1", mock.Anything, "1", "0x1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolRecheckBlockFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, mti, mdi, balance := mockReconcileMismatch(am)
+	eventID := fftypes.NewUUID()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{BlockchainEvent: eventID}}, nil, nil).Once()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+	mdi.On("GetBlockchainEventByID", context.Background(), "ns1", eventID).Return(&core.BlockchainEvent{
+		Info: fftypes.JSONObject{"blockNumber": "42"},
+	}, nil)
+	mdi.On("GetTokenBalance", context.Background(), "ns1", mock.Anything, "1", "0x1").Return(balance, nil)
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolUpdateBalanceFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, mti, mdi, balance := mockReconcileMismatch(am)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalance", context.Background(), "ns1", mock.Anything, "1", "0x1").Return(balance, nil)
+	mdi.On("UpdateTokenBalances", context.Background(), mock.Anything, (*fftypes.FFTime)(nil)).Return(fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolInsertMismatchFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, mti, mdi, balance := mockReconcileMismatch(am)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalance", context.Background(), "ns1", mock.Anything, "1", "0x1").Return(balance, nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcilePoolInsertEventFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, mti, mdi, balance := mockReconcileMismatch(am)
+	mockReconcileBlock(mdi, "42")
+	mdi.On("GetTokenBalance", context.Background(), "ns1", mock.Anything, "1", "0x1").Return(balance, nil)
+	mdi.On("UpdateTokenBalances", context.Background(), mock.Anything, (*fftypes.FFTime)(nil)).Return(nil)
+	mdi.On("InsertTokenBalanceMismatch", context.Background(), mock.Anything).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.reconcilePool(context.Background(), fftypes.NewUUID(), mti, pool, true)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenBalanceMismatches(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenBalanceMismatches", context.Background(), "ns1", f).Return([]*core.TokenBalanceMismatch{}, nil, nil)
+	_, _, err := am.GetTokenBalanceMismatches(context.Background(), f)
+	assert.NoError(t, err)
+}
+
+func TestBalanceCorrection(t *testing.T) {
+	pool := newTestReconcilePool()
+	balance := newTestReconcileBalance(pool, "0x1", 10)
+	balance.URI = "firefly://token/1"
+
+	correction := balanceCorrection(balance, fftypes.NewFFBigInt(25))
+	assert.Equal(t, "0x1", correction.To)
+	assert.Equal(t, "", correction.From)
+	assert.Equal(t, int64(15), correction.Amount.Int().Int64())
+	assert.Equal(t, pool.ID, correction.Pool)
+	assert.Equal(t, "1", correction.TokenIndex)
+	assert.Equal(t, "firefly://token/1", correction.URI)
+	assert.Equal(t, "magic-tokens", correction.Connector)
+	assert.Equal(t, "ns1", correction.Namespace)
+	assert.Nil(t, correction.LocalID)
+
+	correction = balanceCorrection(balance, fftypes.NewFFBigInt(4))
+	assert.Equal(t, "0x1", correction.From)
+	assert.Equal(t, "", correction.To)
+	assert.Equal(t, int64(6), correction.Amount.Int().Int64())
+}
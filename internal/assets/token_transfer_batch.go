@@ -0,0 +1,260 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+// batchTransfer is a single validated item of a batch, along with the operation that submits it
+type batchTransfer struct {
+	input  *core.TokenTransferInput
+	pool   *core.TokenPool
+	plugin tokens.Plugin
+	op     *core.Operation
+	item   *core.TokenTransferBatchItem
+}
+
+func (am *assetManager) MintTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	return am.sendTransferBatch(ctx, core.TokenTransferTypeMint, batch)
+}
+
+func (am *assetManager) BurnTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	return am.sendTransferBatch(ctx, core.TokenTransferTypeBurn, batch)
+}
+
+func (am *assetManager) TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	return am.sendTransferBatch(ctx, core.TokenTransferTypeTransfer, batch)
+}
+
+func (am *assetManager) validateTransferBatch(ctx context.Context, transferType core.TokenTransferType, batch *core.TokenTransferBatchInput) ([]*batchTransfer, error) {
+	if len(batch.Items) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchEmpty)
+	}
+	if len(batch.Items) > am.transferBatchLimit {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchTooLarge, len(batch.Items), am.transferBatchLimit)
+	}
+
+	transfers := make([]*batchTransfer, len(batch.Items))
+	err := am.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		for i, item := range batch.Items {
+			t := &batchTransfer{
+				input: &core.TokenTransferInput{
+					TokenTransfer: item.TokenTransfer,
+					Pool:          item.Pool,
+				},
+			}
+			t.input.Type = transferType
+			t.input.LocalID = fftypes.NewUUID()
+			if t.pool, err = am.validateTransfer(ctx, t.input); err == nil {
+				if transferType == core.TokenTransferTypeTransfer && t.input.From == t.input.To {
+					err = i18n.NewError(ctx, coremsgs.MsgCannotTransferToSelf)
+				} else {
+					t.plugin, err = am.selectTokenPlugin(ctx, t.input.Connector)
+				}
+			}
+			if err != nil {
+				return i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchItemInvalid, i, err)
+			}
+			transfers[i] = t
+		}
+		return nil
+	})
+	return transfers, err
+}
+
+func (am *assetManager) sendTransferBatch(ctx context.Context, transferType core.TokenTransferType, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	// Validate every item before anything is written, so that an invalid item rejects the whole batch
+	transfers, err := am.validateTransferBatch(ctx, transferType, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenTransfer, batch.IdempotencyKey)
+	if err != nil {
+		// On a clash of idempotency key, resubmit any operations that were never submitted and report on the existing batch
+		if idemErr, ok := err.(*sqlcommon.IdempotencyError); ok {
+			operation, resubmitErr := am.operations.ResubmitOperations(ctx, idemErr.ExistingTXID)
+			if resubmitErr != nil {
+				err = resubmitErr
+			} else if operation != nil {
+				return am.GetTokenTransferBatch(ctx, idemErr.ExistingTXID.String())
+			}
+		}
+		return nil, err
+	}
+
+	result := &core.TokenTransferBatch{
+		TX: core.TransactionRef{
+			ID:   txid,
+			Type: core.TransactionTypeTokenTransfer,
+		},
+		Items: make([]*core.TokenTransferBatchItem, len(transfers)),
+	}
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		for i, t := range transfers {
+			t.input.TX = result.TX
+			t.op = core.NewOperation(t.plugin, am.namespace, txid, core.OpTypeTokenTransfer)
+			if err = txcommon.AddTokenTransferInputs(t.op, &t.input.TokenTransfer); err == nil {
+				err = am.operations.AddOrReuseOperation(ctx, t.op)
+			}
+			if err != nil {
+				return err
+			}
+			t.item = &core.TokenTransferBatchItem{
+				Transfer:  &t.input.TokenTransfer,
+				Operation: t.op.ID,
+				Status:    core.OpStatusPending,
+			}
+			result.Items[i] = t.item
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range transfers {
+		if am.metrics.IsMetricsEnabled() {
+			am.metrics.TransferSubmitted(&t.input.TokenTransfer)
+		}
+	}
+	// Submitting a large batch to the connectors can take some time, so it happens in the background against the
+	// context of the manager (not the request). Progress of each item is reported by GetTokenTransferBatch.
+	go am.runTransferBatch(am.ctx, transfers)
+	return result, nil
+}
+
+// runTransferBatch submits the operations of a batch, in a single request to each connector that supports it,
+// and one by one otherwise. Failures are recorded against the individual operations, rather than failing the batch.
+func (am *assetManager) runTransferBatch(ctx context.Context, transfers []*batchTransfer) {
+	byConnector := make(map[string][]*batchTransfer)
+	connectors := []string{}
+	for _, t := range transfers {
+		if _, ok := byConnector[t.input.Connector]; !ok {
+			connectors = append(connectors, t.input.Connector)
+		}
+		byConnector[t.input.Connector] = append(byConnector[t.input.Connector], t)
+	}
+
+	for _, connector := range connectors {
+		group := byConnector[connector]
+		plugin := group[0].plugin
+		if !plugin.Capabilities().BatchTransfers {
+			for _, t := range group {
+				if _, err := am.operations.RunOperation(ctx, opTransfer(t.op, t.pool, &t.input.TokenTransfer)); err != nil {
+					log.L(ctx).Errorf("Failed to submit token transfer operation %s: %s", t.op.ID, err)
+				}
+			}
+			continue
+		}
+
+		items := make([]*tokens.TransferBatchItem, len(group))
+		for i, t := range group {
+			items[i] = &tokens.TransferBatchItem{
+				NSOpID:      opTransfer(t.op, t.pool, &t.input.TokenTransfer).NamespacedIDString(),
+				PoolLocator: t.pool.Locator,
+				Transfer:    &t.input.TokenTransfer,
+				Methods:     t.pool.Methods,
+			}
+		}
+		err := plugin.TransferTokensBatch(ctx, items)
+		for i, t := range group {
+			update := &core.OperationUpdate{
+				NamespacedOpID: items[i].NSOpID,
+				Plugin:         t.op.Plugin,
+				Status:         core.OpStatusPending,
+			}
+			if err != nil {
+				update.Status = core.OpStatusFailed
+				update.ErrorMessage = err.Error()
+			}
+			am.operations.SubmitOperationUpdate(update)
+		}
+	}
+}
+
+func (am *assetManager) GetTokenTransferBatch(ctx context.Context, txID string) (*core.TokenTransferBatch, error) {
+	u, err := fftypes.ParseUUID(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := am.database.GetOperations(ctx, am.namespace, fb.And(
+		fb.Eq("tx", u),
+		fb.Eq("type", core.OpTypeTokenTransfer),
+	).Sort("created"))
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+
+	tfb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	confirmed, _, err := am.database.GetTokenTransfers(ctx, am.namespace, tfb.And(tfb.Eq("tx.id", u)))
+	if err != nil {
+		return nil, err
+	}
+	confirmedByID := make(map[fftypes.UUID]*core.TokenTransfer, len(confirmed))
+	for _, transfer := range confirmed {
+		confirmedByID[*transfer.LocalID] = transfer
+	}
+
+	batch := &core.TokenTransferBatch{
+		TX: core.TransactionRef{
+			ID:   u,
+			Type: core.TransactionTypeTokenTransfer,
+		},
+		Items: make([]*core.TokenTransferBatchItem, 0, len(ops)),
+	}
+	for _, op := range ops {
+		if op.Retry != nil {
+			// Superseded by a retry, which is reported in its place
+			continue
+		}
+		transfer, err := txcommon.RetrieveTokenTransferInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		item := &core.TokenTransferBatchItem{
+			Transfer:  transfer,
+			Operation: op.ID,
+			Status:    op.Status,
+			Error:     op.Error,
+		}
+		if transfer.LocalID != nil {
+			if c, ok := confirmedByID[*transfer.LocalID]; ok {
+				item.Transfer = c
+				item.Confirmed = true
+			}
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	return batch, nil
+}
@@ -0,0 +1,421 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestTransferBatch() *core.TokenTransferBatchInput {
+	return &core.TokenTransferBatchInput{
+		Items: []*core.TokenTransferBatchInputItem{
+			{
+				TokenTransfer: core.TokenTransfer{To: "A", Amount: *fftypes.NewFFBigInt(5)},
+				Pool:          "pool1",
+			},
+			{
+				TokenTransfer: core.TokenTransfer{To: "B", Amount: *fftypes.NewFFBigInt(10)},
+				Pool:          "pool1",
+			},
+		},
+		IdempotencyKey: "idem1",
+	}
+}
+
+func mockTransferBatchValidation(am *assetManager) *core.TokenPool {
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	return pool
+}
+
+func TestTransferTokensBatchRunOperations(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	pool := mockTransferBatchValidation(am)
+	txid := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mti.On("Capabilities").Return(&tokens.Capabilities{})
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil).Twice()
+	submitted := make(chan struct{}, 2)
+	mom.On("RunOperation", am.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return data.Pool == pool && data.Transfer.To == "A"
+	})).Return(nil, nil).Run(func(args mock.Arguments) { submitted <- struct{}{} })
+	mom.On("RunOperation", am.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return data.Pool == pool && data.Transfer.To == "B"
+	})).Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) { submitted <- struct{}{} })
+
+	result, err := am.TransferTokensBatch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, result.TX.ID)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, core.TokenTransferTypeTransfer, result.Items[0].Transfer.Type)
+	assert.Equal(t, "0x12345", result.Items[0].Transfer.From)
+	assert.Equal(t, txid, result.Items[0].Transfer.TX.ID)
+	assert.NotNil(t, result.Items[0].Transfer.LocalID)
+	assert.NotNil(t, result.Items[0].Operation)
+	assert.Equal(t, core.OpStatusPending, result.Items[0].Status)
+	assert.Equal(t, core.OpStatusPending, result.Items[1].Status)
+	<-submitted
+	<-submitted
+
+	mti.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMintTokensBatchConnectorBatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockTransferBatchValidation(am)
+	txid := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mti.On("Capabilities").Return(&tokens.Capabilities{BatchTransfers: true})
+	mti.On("TransferTokensBatch", am.ctx, mock.MatchedBy(func(items []*tokens.TransferBatchItem) bool {
+		return len(items) == 2 &&
+			items[0].PoolLocator == "F1" &&
+			items[0].Transfer.Type == core.TokenTransferTypeMint &&
+			items[1].Transfer.To == "B"
+	})).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil).Twice()
+	submitted := make(chan struct{}, 2)
+	mom.On("SubmitOperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending && update.Plugin == "ut"
+	})).Run(func(args mock.Arguments) { submitted <- struct{}{} }).Twice()
+
+	result, err := am.MintTokensBatch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, core.OpStatusPending, result.Items[0].Status)
+	assert.Equal(t, core.OpStatusPending, result.Items[1].Status)
+	<-submitted
+	<-submitted
+
+	mti.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestBurnTokensBatchConnectorBatchFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockTransferBatchValidation(am)
+	txid := fftypes.NewUUID()
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mti.On("Capabilities").Return(&tokens.Capabilities{BatchTransfers: true})
+	mti.On("TransferTokensBatch", am.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil).Twice()
+	submitted := make(chan struct{}, 2)
+	mom.On("SubmitOperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusFailed && update.ErrorMessage == "pop"
+	})).Run(func(args mock.Arguments) { submitted <- struct{}{} }).Twice()
+
+	result, err := am.BurnTokensBatch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenTransferTypeBurn, result.Items[0].Transfer.Type)
+	<-submitted
+	<-submitted
+
+	mti.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchEmpty(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.TransferTokensBatch(context.Background(), &core.TokenTransferBatchInput{})
+	assert.Regexp(t, "FF10505", err)
+}
+
+func TestTransferTokensBatchTooLarge(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	am.transferBatchLimit = 1
+	_, err := am.TransferTokensBatch(context.Background(), newTestTransferBatch())
+	assert.Regexp(t, "FF10580", err)
+}
+
+func TestTransferTokensBatchBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.TransferTokensBatch(context.Background(), newTestTransferBatch())
+	assert.Regexp(t, "FF10506.*0.*FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchToSelf(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	batch.Items[1].To = "0x12345"
+	mockTransferBatchValidation(am)
+
+	_, err := am.TransferTokensBatch(context.Background(), batch)
+	assert.Regexp(t, "FF10506.*1.*FF10280", err)
+}
+
+func TestTransferTokensBatchBadConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := mockTransferBatchValidation(am)
+	pool.Connector = "bad"
+
+	_, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.Regexp(t, "FF10506.*FF10272", err)
+}
+
+func TestTransferTokensBatchTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockTransferBatchValidation(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentResubmit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	txid := fftypes.NewUUID()
+	mockTransferBatchValidation(am)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, &sqlcommon.IdempotencyError{
+		ExistingTXID:  txid,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", txid)})
+	mom.On("ResubmitOperations", context.Background(), txid).Return(&core.Operation{}, nil)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		{ID: fftypes.NewUUID(), Status: core.OpStatusPending, Input: fftypes.JSONObject{"to": "A"}},
+	}, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+
+	result, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.NoError(t, err)
+	assert.Equal(t, txid, result.TX.ID)
+	assert.Len(t, result.Items, 1)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentResubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	txid := fftypes.NewUUID()
+	mockTransferBatchValidation(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, &sqlcommon.IdempotencyError{
+		ExistingTXID:  txid,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", txid)})
+	mom.On("ResubmitOperations", context.Background(), txid).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentNoOperationToResubmit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	txid := fftypes.NewUUID()
+	mockTransferBatchValidation(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txid, &sqlcommon.IdempotencyError{
+		ExistingTXID:  txid,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", txid)})
+	mom.On("ResubmitOperations", context.Background(), txid).Return(nil, nil)
+
+	_, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.Regexp(t, "FF10431", err)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchAddOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockTransferBatchValidation(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBatch(context.Background(), newTestTransferBatch())
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestGetTokenTransferBatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	txid := fftypes.NewUUID()
+	localID1 := fftypes.NewUUID()
+	localID2 := fftypes.NewUUID()
+	ops := []*core.Operation{
+		{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, Retry: fftypes.NewUUID(), Input: fftypes.JSONObject{"localId": localID1.String()}},
+		{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, Input: fftypes.JSONObject{"localId": localID2.String()}},
+		{ID: fftypes.NewUUID(), Status: core.OpStatusPending, Input: fftypes.JSONObject{"localId": localID1.String()}},
+		{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, Error: "pop", Input: fftypes.JSONObject{}},
+	}
+	confirmed := &core.TokenTransfer{LocalID: localID2, ProtocolID: "000001"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(ops, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{confirmed}, nil, nil)
+
+	batch, err := am.GetTokenTransferBatch(context.Background(), txid.String())
+	assert.NoError(t, err)
+	assert.Equal(t, txid, batch.TX.ID)
+	assert.Len(t, batch.Items, 3)
+	assert.True(t, batch.Items[0].Confirmed)
+	assert.Equal(t, confirmed, batch.Items[0].Transfer)
+	assert.False(t, batch.Items[1].Confirmed)
+	assert.Equal(t, localID1, batch.Items[1].Transfer.LocalID)
+	assert.Equal(t, ops[2].ID, batch.Items[1].Operation)
+	assert.Equal(t, core.OpStatusPending, batch.Items[1].Status)
+	assert.Equal(t, "pop", batch.Items[2].Error)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenTransferBatchBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.GetTokenTransferBatch(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetTokenTransferBatchGetOperationsFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenTransferBatch(context.Background(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenTransferBatchNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	_, err := am.GetTokenTransferBatch(context.Background(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetTokenTransferBatchGetTransfersFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{{}}, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenTransferBatch(context.Background(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenTransferBatchBadOperation(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		{Input: fftypes.JSONObject{"amount": "bad"}},
+	}, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+
+	_, err := am.GetTokenTransferBatch(context.Background(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF00127", err)
+}
@@ -149,9 +149,9 @@ func (cb *callbacks) BatchPinOrNetworkAction(ctx context.Context, subInfo *Subsc
 			for _, localNames := range subInfo.V1Namespace {
 				namespaces = append(namespaces, localNames...)
 			}
-			return cb.networkAction(ctx, namespaces, action, location, event, signingKey)
+			return cb.networkAction(ctx, namespaces, action, params.PayloadRef, location, event, signingKey)
 		}
-		return cb.networkAction(ctx, []string{subInfo.V2Namespace}, action, location, event, signingKey)
+		return cb.networkAction(ctx, []string{subInfo.V2Namespace}, action, params.PayloadRef, location, event, signingKey)
 	}
 
 	batch, err := buildBatchPin(ctx, event, params)
@@ -193,10 +193,10 @@ func (cb *callbacks) batchPinComplete(ctx context.Context, namespaces []string,
 	return nil
 }
 
-func (cb *callbacks) networkAction(ctx context.Context, namespaces []string, action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
+func (cb *callbacks) networkAction(ctx context.Context, namespaces []string, action, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
 	for _, namespace := range namespaces {
 		if handler, ok := cb.handlers[namespace]; ok {
-			if err := handler.BlockchainNetworkAction(action, location, event, signingKey); err != nil {
+			if err := handler.BlockchainNetworkAction(action, payload, location, event, signingKey); err != nil {
 				return err
 			}
 		} else {
@@ -193,11 +193,11 @@ func TestCallbackNetworkAction(t *testing.T) {
 		Version:     2,
 		V2Namespace: "ns1",
 	}
-	mcb.On("BlockchainNetworkAction", "terminate", mock.Anything, mock.Anything, verifier).Return(nil).Once()
+	mcb.On("BlockchainNetworkAction", "terminate", "", mock.Anything, mock.Anything, verifier).Return(nil).Once()
 	err := cb.BatchPinOrNetworkAction(context.Background(), sub, fftypes.JSONAnyPtr("{}"), event, verifier, params)
 	assert.NoError(t, err)
 
-	mcb.On("BlockchainNetworkAction", "terminate", mock.Anything, mock.Anything, verifier).Return(fmt.Errorf("pop")).Once()
+	mcb.On("BlockchainNetworkAction", "terminate", "", mock.Anything, mock.Anything, verifier).Return(fmt.Errorf("pop")).Once()
 	err = cb.BatchPinOrNetworkAction(context.Background(), sub, fftypes.JSONAnyPtr("{}"), event, verifier, params)
 	assert.EqualError(t, err, "pop")
 
@@ -205,13 +205,36 @@ func TestCallbackNetworkAction(t *testing.T) {
 		Version:     1,
 		V1Namespace: map[string][]string{"ns2": {"ns1", "ns"}},
 	}
-	mcb.On("BlockchainNetworkAction", "terminate", mock.Anything, mock.Anything, verifier).Return(nil).Once()
+	mcb.On("BlockchainNetworkAction", "terminate", "", mock.Anything, mock.Anything, verifier).Return(nil).Once()
 	err = cb.BatchPinOrNetworkAction(context.Background(), sub, fftypes.JSONAnyPtr("{}"), event, verifier, params)
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
 }
 
+func TestCallbackNetworkActionPayload(t *testing.T) {
+	event := &blockchain.Event{}
+	verifier := &core.VerifierRef{}
+	params := &BatchPinParams{
+		NsOrAction: "firefly:migrate",
+		PayloadRef: `{"location":{"address":"0x456"}}`,
+	}
+
+	mcb := &blockchainmocks.Callbacks{}
+	cb := NewBlockchainCallbacks()
+	cb.SetHandler("ns1", mcb)
+
+	sub := &SubscriptionInfo{
+		Version:     2,
+		V2Namespace: "ns1",
+	}
+	mcb.On("BlockchainNetworkAction", "migrate", `{"location":{"address":"0x456"}}`, mock.Anything, event, verifier).Return(nil).Once()
+	err := cb.BatchPinOrNetworkAction(context.Background(), sub, fftypes.JSONAnyPtr("{}"), event, verifier, params)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
 func TestBuildBatchPinErrors(t *testing.T) {
 	event := &blockchain.Event{}
 	params := &BatchPinParams{}
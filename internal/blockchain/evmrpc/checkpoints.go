@@ -0,0 +1,120 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// checkpointStore persists the checkpoint of each listener to a file, so that polling resumes from the last
+// block that was fully processed after a restart, rather than from the first event of the listener.
+// The whole file is rewritten on each change, to a temporary file that is then renamed over the original.
+type checkpointStore struct {
+	file        string
+	mux         sync.Mutex
+	checkpoints map[string]int64
+}
+
+func loadCheckpoints(ctx context.Context, file string) (*checkpointStore, error) {
+	cs := &checkpointStore{
+		file:        file,
+		checkpoints: make(map[string]int64),
+	}
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		log.L(ctx).Infof("No listener checkpoints found at '%s'", file)
+		return cs, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(b, &cs.checkpoints)
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgEVMRPCCheckpointsReadFailed, file)
+	}
+	log.L(ctx).Infof("Loaded %d listener checkpoints from '%s'", len(cs.checkpoints), file)
+	return cs, nil
+}
+
+func (cs *checkpointStore) get(id string) (checkpoint int64, ok bool) {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	checkpoint, ok = cs.checkpoints[id]
+	return checkpoint, ok
+}
+
+func (cs *checkpointStore) set(ctx context.Context, id string, checkpoint int64) error {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	previous, existed := cs.checkpoints[id]
+	cs.checkpoints[id] = checkpoint
+	if err := cs.write(ctx); err != nil {
+		if existed {
+			cs.checkpoints[id] = previous
+		} else {
+			delete(cs.checkpoints, id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (cs *checkpointStore) remove(ctx context.Context, id string) error {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	previous, ok := cs.checkpoints[id]
+	if !ok {
+		return nil
+	}
+	delete(cs.checkpoints, id)
+	if err := cs.write(ctx); err != nil {
+		cs.checkpoints[id] = previous
+		return err
+	}
+	return nil
+}
+
+func (cs *checkpointStore) write(ctx context.Context) error {
+	b, _ := json.Marshal(cs.checkpoints)
+	tmpFile, err := os.CreateTemp(filepath.Dir(cs.file), filepath.Base(cs.file)+".*.tmp")
+	if err == nil {
+		_, err = tmpFile.Write(b)
+		if err == nil {
+			err = tmpFile.Sync()
+		}
+		if closeErr := tmpFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmpFile.Name(), cs.file)
+		}
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgEVMRPCCheckpointsWriteFailed, cs.file)
+	}
+	return nil
+}
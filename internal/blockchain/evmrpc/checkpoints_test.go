@@ -0,0 +1,100 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointsPersisted(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "checkpoints.json")
+	cs, err := loadCheckpoints(ctx, file)
+	assert.NoError(t, err)
+	_, ok := cs.get("sub1")
+	assert.False(t, ok)
+
+	err = cs.set(ctx, "sub1", 10)
+	assert.NoError(t, err)
+	err = cs.set(ctx, "sub2", 20)
+	assert.NoError(t, err)
+	err = cs.remove(ctx, "sub2")
+	assert.NoError(t, err)
+	err = cs.remove(ctx, "sub3")
+	assert.NoError(t, err)
+
+	cs, err = loadCheckpoints(ctx, file)
+	assert.NoError(t, err)
+	checkpoint, ok := cs.get("sub1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), checkpoint)
+	_, ok = cs.get("sub2")
+	assert.False(t, ok)
+}
+
+func TestCheckpointsLoadBadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "checkpoints.json")
+	err := os.WriteFile(file, []byte("!json"), 0600)
+	assert.NoError(t, err)
+	_, err = loadCheckpoints(context.Background(), file)
+	assert.Regexp(t, "FF10583", err)
+}
+
+func TestCheckpointsWriteFail(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cs, err := loadCheckpoints(ctx, filepath.Join(dir, "checkpoints.json"))
+	assert.NoError(t, err)
+	err = cs.set(ctx, "sub1", 10)
+	assert.NoError(t, err)
+
+	cs.file = filepath.Join(dir, "missing", "checkpoints.json")
+	err = cs.set(ctx, "sub1", 20)
+	assert.Regexp(t, "FF10584", err)
+	err = cs.set(ctx, "sub2", 20)
+	assert.Regexp(t, "FF10584", err)
+	err = cs.remove(ctx, "sub1")
+	assert.Regexp(t, "FF10584", err)
+
+	checkpoint, ok := cs.get("sub1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), checkpoint)
+	_, ok = cs.get("sub2")
+	assert.False(t, ok)
+}
+
+func TestCheckpointsRenameFail(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cs, err := loadCheckpoints(ctx, filepath.Join(dir, "checkpoints.json"))
+	assert.NoError(t, err)
+	err = os.Mkdir(cs.file, 0700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(cs.file, "other"), []byte{}, 0600)
+	assert.NoError(t, err)
+
+	err = cs.set(ctx, "sub1", 10)
+	assert.Regexp(t, "FF10584", err)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	defaultSignerType              = "node"
+	defaultGasEstimationFactor     = 1.5
+	defaultConfirmations           = 0
+	defaultEventsPollingInterval   = "1s"
+	defaultEventsMaxBlockRange     = 1000
+	defaultReceiptsPollingInterval = "1s"
+)
+
+const (
+	// RPCConfigKey is a sub-key in the config containing the HTTP client config for the JSON-RPC endpoint of the node
+	RPCConfigKey = "rpc"
+	// SignerConfigKey is a sub-key in the config containing the transaction signer config
+	SignerConfigKey = "signer"
+	// SignerConfigType selects how transactions are signed - "node" submits them to the node with eth_sendTransaction,
+	// and "jsonrpc" signs them with eth_signTransaction on a separate signer before submitting them to the node
+	SignerConfigType = "type"
+	// SignerConfigGasEstimationFactor is the factor applied to the gas estimate from the node, for transactions signed with a "jsonrpc" signer
+	SignerConfigGasEstimationFactor = "gasEstimationFactor"
+	// ConfigConfirmations is the number of blocks that must be mined on top of a block, before its events and receipts are processed
+	ConfigConfirmations = "confirmations"
+	// EventsConfigPollingInterval is how often to poll the node for new blocks, to query the logs for each listener
+	EventsConfigPollingInterval = "events.pollingInterval"
+	// EventsConfigCheckpointFile is the file the checkpoint of each listener is persisted to
+	EventsConfigCheckpointFile = "events.checkpointFile"
+	// EventsConfigMaxBlockRange is the maximum number of blocks to query the logs for in a single request
+	EventsConfigMaxBlockRange = "events.maxBlockRange"
+	// ReceiptsConfigPollingInterval is how often to poll the node for the receipts of submitted transactions
+	ReceiptsConfigPollingInterval = "receipts.pollingInterval"
+)
+
+func (e *EVMRPC) InitConfig(config config.Section) {
+	e.rpcConf = config.SubSection(RPCConfigKey)
+	ffresty.InitConfig(e.rpcConf)
+
+	e.signerConf = config.SubSection(SignerConfigKey)
+	ffresty.InitConfig(e.signerConf)
+	e.signerConf.AddKnownKey(SignerConfigType, defaultSignerType)
+	e.signerConf.AddKnownKey(SignerConfigGasEstimationFactor, defaultGasEstimationFactor)
+
+	config.AddKnownKey(ConfigConfirmations, defaultConfirmations)
+	config.AddKnownKey(EventsConfigPollingInterval, defaultEventsPollingInterval)
+	config.AddKnownKey(EventsConfigCheckpointFile)
+	config.AddKnownKey(EventsConfigMaxBlockRange, defaultEventsMaxBlockRange)
+	config.AddKnownKey(ReceiptsConfigPollingInterval, defaultReceiptsPollingInterval)
+}
@@ -0,0 +1,355 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+)
+
+const devChainGenesisTime = 1700000000
+
+// devLog is a log emitted by a transaction on the dev chain
+type devLog struct {
+	address string
+	topics  []ethtypes.HexBytes0xPrefix
+	data    ethtypes.HexBytes0xPrefix
+}
+
+// devChain is a stand-in for a local development chain, serving the subset of the Ethereum JSON-RPC
+// interface used by the plugin. Each transaction is mined into its own block.
+type devChain struct {
+	t        *testing.T
+	mux      sync.Mutex
+	server   *httptest.Server
+	head     int64
+	txs      []*ethTransaction
+	receipts map[string]fftypes.JSONObject
+	logs     []fftypes.JSONObject
+	// calls maps a function selector to the hex result of eth_call
+	calls map[string]string
+	// reverts maps a function selector to the error returned by eth_call
+	reverts map[string]*rpcbackend.RPCError
+	// failures maps a method to an error it will always return
+	failures map[string]*rpcbackend.RPCError
+	// onTransaction returns the status and logs for each transaction as it is mined
+	onTransaction func(tx *ethTransaction) (status int64, logs []*devLog)
+}
+
+type devRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func newDevChain(t *testing.T) *devChain {
+	d := &devChain{
+		t:        t,
+		receipts: make(map[string]fftypes.JSONObject),
+		calls:    make(map[string]string),
+		reverts:  make(map[string]*rpcbackend.RPCError),
+		failures: make(map[string]*rpcbackend.RPCError),
+	}
+	d.server = httptest.NewServer(http.HandlerFunc(d.serveRPC))
+	return d
+}
+
+func hexInt(i int64) string {
+	return fmt.Sprintf("0x%x", i)
+}
+
+func devBlockHash(block int64) string {
+	return fmt.Sprintf("0x%064x", block+0xb10c)
+}
+
+func serveJSONRPC(w http.ResponseWriter, r *http.Request, handler func(method string, params []json.RawMessage) (interface{}, *rpcbackend.RPCError)) {
+	var req devRPCRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	result, rpcErr := handler(req.Method, req.Params)
+	res := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+	}
+	if rpcErr != nil {
+		res["error"] = rpcErr
+	} else {
+		res["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (d *devChain) serveRPC(w http.ResponseWriter, r *http.Request) {
+	serveJSONRPC(w, r, func(method string, params []json.RawMessage) (interface{}, *rpcbackend.RPCError) {
+		d.mux.Lock()
+		defer d.mux.Unlock()
+		if rpcErr, ok := d.failures[method]; ok {
+			return nil, rpcErr
+		}
+		return d.handle(method, params)
+	})
+}
+
+func (d *devChain) param(params []json.RawMessage, idx int, v interface{}) {
+	err := json.Unmarshal(params[idx], v)
+	assert.NoError(d.t, err)
+}
+
+func (d *devChain) handle(method string, params []json.RawMessage) (interface{}, *rpcbackend.RPCError) {
+	switch method {
+	case "eth_blockNumber":
+		return hexInt(d.head), nil
+	case "eth_getBlockByNumber":
+		var block ethtypes.HexInteger
+		d.param(params, 0, &block)
+		if block.BigInt().Int64() > d.head {
+			return nil, nil
+		}
+		return map[string]string{
+			"number":    block.String(),
+			"hash":      devBlockHash(block.BigInt().Int64()),
+			"timestamp": hexInt(devChainGenesisTime + block.BigInt().Int64()),
+		}, nil
+	case "eth_sendTransaction":
+		var tx ethTransaction
+		d.param(params, 0, &tx)
+		return d.mine(&tx), nil
+	case "eth_sendRawTransaction":
+		// The dev signer "signs" a transaction by hex encoding its JSON
+		var raw ethtypes.HexBytes0xPrefix
+		d.param(params, 0, &raw)
+		var tx ethTransaction
+		if err := json.Unmarshal(raw, &tx); err != nil {
+			return nil, &rpcbackend.RPCError{Code: -32000, Message: "invalid raw transaction"}
+		}
+		return d.mine(&tx), nil
+	case "eth_getTransactionReceipt":
+		var txHash string
+		d.param(params, 0, &txHash)
+		return d.receipts[txHash], nil
+	case "eth_getLogs":
+		var filter logFilter
+		d.param(params, 0, &filter)
+		return d.filterLogs(&filter), nil
+	case "eth_call":
+		var tx ethTransaction
+		d.param(params, 0, &tx)
+		selector := ethtypes.HexBytes0xPrefix(tx.Data[0:4]).String()
+		if rpcErr, ok := d.reverts[selector]; ok {
+			return nil, rpcErr
+		}
+		if result, ok := d.calls[selector]; ok {
+			return result, nil
+		}
+		return "0x", nil
+	case "eth_getTransactionCount":
+		return hexInt(int64(len(d.txs))), nil
+	case "eth_estimateGas":
+		return hexInt(21000), nil
+	case "eth_gasPrice":
+		return hexInt(1000000000), nil
+	case "eth_chainId":
+		return hexInt(1337), nil
+	}
+	return nil, &rpcbackend.RPCError{Code: -32601, Message: fmt.Sprintf("method %s not found", method)}
+}
+
+func (d *devChain) mine(tx *ethTransaction) string {
+	d.head++
+	d.txs = append(d.txs, tx)
+	txHash := fmt.Sprintf("0x%064x", d.head)
+	status := int64(1)
+	var logs []*devLog
+	if d.onTransaction != nil {
+		status, logs = d.onTransaction(tx)
+	}
+	receipt := fftypes.JSONObject{
+		"transactionHash":  txHash,
+		"blockHash":        devBlockHash(d.head),
+		"blockNumber":      hexInt(d.head),
+		"transactionIndex": "0x0",
+		"status":           hexInt(status),
+		"gasUsed":          hexInt(21000),
+	}
+	if tx.To == "" {
+		receipt["contractAddress"] = fmt.Sprintf("0x%040x", d.head)
+	}
+	d.receipts[txHash] = receipt
+	for i, l := range logs {
+		d.logs = append(d.logs, fftypes.JSONObject{
+			"address":          l.address,
+			"topics":           l.topics,
+			"data":             l.data,
+			"blockNumber":      hexInt(d.head),
+			"blockHash":        devBlockHash(d.head),
+			"transactionHash":  txHash,
+			"transactionIndex": "0x0",
+			"logIndex":         hexInt(int64(i)),
+			"removed":          false,
+		})
+	}
+	return txHash
+}
+
+func (d *devChain) filterLogs(filter *logFilter) []fftypes.JSONObject {
+	logs := []fftypes.JSONObject{}
+	for _, l := range d.logs {
+		var block ethtypes.HexInteger
+		_ = json.Unmarshal([]byte(fmt.Sprintf(`"%s"`, l["blockNumber"])), &block)
+		if block.BigInt().Cmp(filter.FromBlock.BigInt()) < 0 || block.BigInt().Cmp(filter.ToBlock.BigInt()) > 0 {
+			continue
+		}
+		if filter.Address != "" && !strings.EqualFold(filter.Address, l["address"].(string)) {
+			continue
+		}
+		topics := l["topics"].([]ethtypes.HexBytes0xPrefix)
+		if len(filter.Topics) > 0 && (len(topics) == 0 || !bytes.Equal(filter.Topics[0], topics[0])) {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs
+}
+
+// mineBlocks advances the chain without any transactions
+func (d *devChain) mineBlocks(count int64) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.head += count
+}
+
+func (d *devChain) setCall(t *testing.T, method *abi.Entry, outputs ...interface{}) {
+	result, err := method.Outputs.EncodeABIDataValues(outputs)
+	assert.NoError(t, err)
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.calls[method.FunctionSelectorBytes().String()] = ethtypes.HexBytes0xPrefix(result).String()
+}
+
+func (d *devChain) setRevert(method *abi.Entry, rpcErr *rpcbackend.RPCError) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.reverts[method.FunctionSelectorBytes().String()] = rpcErr
+}
+
+func (d *devChain) setFailure(method string, rpcErr *rpcbackend.RPCError) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if rpcErr == nil {
+		delete(d.failures, method)
+	} else {
+		d.failures[method] = rpcErr
+	}
+}
+
+func (d *devChain) transactions() []*ethTransaction {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return append([]*ethTransaction{}, d.txs...)
+}
+
+// emitBatchPins configures the chain to behave like the FireFly multiparty contract at the given address,
+// emitting a BatchPin event for each pinBatch and networkAction transaction
+func (d *devChain) emitBatchPins(address string) {
+	d.onTransaction = func(tx *ethTransaction) (int64, []*devLog) {
+		if !strings.EqualFold(tx.To, address) || len(tx.Data) < 4 {
+			return 1, nil
+		}
+		ctx := context.Background()
+		serializer := abi.NewSerializer().SetByteSerializer(abi.HexByteSerializer0xPrefix)
+		var values []interface{}
+		switch {
+		case bytes.Equal(tx.Data[0:4], batchPinMethodABIV1.FunctionSelectorBytes()):
+			cv, err := batchPinMethodABIV1.DecodeCallDataCtx(ctx, tx.Data)
+			assert.NoError(d.t, err)
+			input, _ := serializer.SerializeInterfaceCtx(ctx, cv)
+			m := input.(map[string]interface{})
+			values = []interface{}{tx.From, devChainGenesisTime, m["namespace"], m["uuids"], m["batchHash"], m["payloadRef"], m["contexts"]}
+		case bytes.Equal(tx.Data[0:4], batchPinMethodABI.FunctionSelectorBytes()):
+			cv, err := batchPinMethodABI.DecodeCallDataCtx(ctx, tx.Data)
+			assert.NoError(d.t, err)
+			input, _ := serializer.SerializeInterfaceCtx(ctx, cv)
+			m := input.(map[string]interface{})
+			values = []interface{}{tx.From, devChainGenesisTime, "", m["uuids"], m["batchHash"], m["payloadRef"], m["contexts"]}
+		case bytes.Equal(tx.Data[0:4], networkActionMethodABI.FunctionSelectorBytes()):
+			cv, err := networkActionMethodABI.DecodeCallDataCtx(ctx, tx.Data)
+			assert.NoError(d.t, err)
+			input, _ := serializer.SerializeInterfaceCtx(ctx, cv)
+			m := input.(map[string]interface{})
+			values = []interface{}{tx.From, devChainGenesisTime, m["action"], ethHexFormatB32(nil), ethHexFormatB32(nil), m["payload"], []string{}}
+		default:
+			return 1, nil
+		}
+		data, err := batchPinEventABI.Inputs.EncodeABIDataValues(values)
+		assert.NoError(d.t, err)
+		return 1, []*devLog{{
+			address: strings.ToLower(address),
+			topics:  []ethtypes.HexBytes0xPrefix{batchPinEventABI.SignatureHashBytes()},
+			data:    data,
+		}}
+	}
+}
+
+// devSigner is a stand-in for a remote signer, that "signs" a transaction by hex encoding its JSON
+type devSigner struct {
+	server   *httptest.Server
+	mux      sync.Mutex
+	txs      []*ethTransaction
+	rawField bool
+	empty    bool
+	failure  *rpcbackend.RPCError
+}
+
+func newDevSigner(t *testing.T) *devSigner {
+	s := &devSigner{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveJSONRPC(w, r, func(method string, params []json.RawMessage) (interface{}, *rpcbackend.RPCError) {
+			s.mux.Lock()
+			defer s.mux.Unlock()
+			if s.failure != nil {
+				return nil, s.failure
+			}
+			var tx ethTransaction
+			err := json.Unmarshal(params[0], &tx)
+			assert.NoError(t, err)
+			s.txs = append(s.txs, &tx)
+			b, _ := json.Marshal(&tx)
+			raw := ethtypes.HexBytes0xPrefix(b).String()
+			if s.empty {
+				raw = "0x"
+			}
+			if s.rawField {
+				return map[string]string{"raw": raw}, nil
+			}
+			return raw, nil
+		})
+	}))
+	return s
+}
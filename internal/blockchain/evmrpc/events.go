@@ -0,0 +1,439 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/ffi2abi"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// listener is a filter on the logs of the chain, for a single event signature and (optionally) a single contract address.
+// The checkpoint is the last block that has been fully processed for the listener, and is persisted in the checkpoint store.
+type listener struct {
+	id         string
+	namespace  string
+	address    string
+	event      *abi.Entry
+	signature  string
+	topic      ethtypes.HexBytes0xPrefix
+	checkpoint int64
+	firefly    bool
+	location   *fftypes.JSONAny
+}
+
+type logFilter struct {
+	FromBlock *ethtypes.HexInteger        `json:"fromBlock"`
+	ToBlock   *ethtypes.HexInteger        `json:"toBlock"`
+	Address   string                      `json:"address,omitempty"`
+	Topics    []ethtypes.HexBytes0xPrefix `json:"topics"`
+}
+
+type ethLog struct {
+	Address          *ethtypes.Address0xHex      `json:"address"`
+	Topics           []ethtypes.HexBytes0xPrefix `json:"topics"`
+	Data             ethtypes.HexBytes0xPrefix   `json:"data"`
+	BlockNumber      *ethtypes.HexInteger        `json:"blockNumber"`
+	BlockHash        string                      `json:"blockHash"`
+	TransactionHash  string                      `json:"transactionHash"`
+	TransactionIndex *ethtypes.HexInteger        `json:"transactionIndex"`
+	LogIndex         *ethtypes.HexInteger        `json:"logIndex"`
+	Removed          bool                        `json:"removed"`
+}
+
+type blockHeader struct {
+	Timestamp *ethtypes.HexInteger `json:"timestamp"`
+}
+
+func (e *EVMRPC) blockNumber(ctx context.Context) (int64, error) {
+	var head ethtypes.HexInteger
+	if rpcErr := e.rpc.CallRPC(ctx, &head, "eth_blockNumber"); rpcErr != nil {
+		return 0, i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	return head.BigInt().Int64(), nil
+}
+
+// initialCheckpoint returns the checkpoint for a new listener, so that polling starts from the requested first event
+func (e *EVMRPC) initialCheckpoint(ctx context.Context, firstEvent string) (int64, error) {
+	switch firstEvent {
+	case "", string(core.SubOptsFirstEventOldest):
+		return -1, nil
+	case string(core.SubOptsFirstEventNewest):
+		return e.blockNumber(ctx)
+	}
+	block, err := strconv.ParseInt(firstEvent, 10, 64)
+	if err != nil || block < 0 {
+		return 0, i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidFirstEvent, firstEvent)
+	}
+	return block - 1, nil
+}
+
+func (e *EVMRPC) addListener(ctx context.Context, l *listener, firstEvent string) (err error) {
+	l.topic = l.event.SignatureHashBytes()
+	l.signature = ffi2abi.ABIMethodToSignature(l.event)
+	if checkpoint, ok := e.checkpoints.get(l.id); ok {
+		log.L(ctx).Infof("Resuming listener '%s' from checkpoint at block %d", l.id, checkpoint)
+		l.checkpoint = checkpoint
+	} else if l.checkpoint, err = e.initialCheckpoint(ctx, firstEvent); err != nil {
+		return err
+	}
+	e.listenersMutex.Lock()
+	defer e.listenersMutex.Unlock()
+	if _, exists := e.listeners[l.id]; !exists {
+		e.listeners[l.id] = l
+	}
+	return nil
+}
+
+func (e *EVMRPC) AddFireflySubscription(ctx context.Context, namespace *core.Namespace, contract *blockchain.MultipartyContract) (string, error) {
+	ethLocation, err := e.parseContractLocation(ctx, contract.Location)
+	if err != nil {
+		return "", err
+	}
+	address, err := formatEthAddress(ctx, ethLocation.Address)
+	if err != nil {
+		return "", err
+	}
+	location, _ := e.encodeContractLocation(ctx, &ethereum.Location{Address: address})
+
+	version, err := e.GetNetworkVersion(ctx, contract.Location)
+	if err != nil {
+		return "", err
+	}
+
+	// A V1 contract is shared by all namespaces, so there is a single listener for the contract
+	subID := fmt.Sprintf("ff-batchpin-%s", address)
+	if version > 1 {
+		subID = fmt.Sprintf("%s-%s", subID, namespace.Name)
+	}
+	err = e.addListener(ctx, &listener{
+		id:        subID,
+		namespace: namespace.Name,
+		address:   address,
+		event:     batchPinEventABI,
+		firefly:   true,
+		location:  location,
+	}, contract.FirstEvent)
+	if err != nil {
+		return "", err
+	}
+
+	e.subs.AddSubscription(ctx, namespace, version, subID, nil)
+	return subID, nil
+}
+
+func (e *EVMRPC) RemoveFireflySubscription(ctx context.Context, subID string) {
+	// The listener is left in place, as it may be shared with other namespaces on a V1 contract.
+	// Events for subscriptions that have been removed are ignored.
+	e.subs.RemoveSubscription(ctx, subID)
+}
+
+func (e *EVMRPC) AddContractListener(ctx context.Context, listener *core.ContractListener) (err error) {
+	var address string
+	if listener.Location != nil {
+		location, err := e.parseContractLocation(ctx, listener.Location)
+		if err != nil {
+			return err
+		}
+		if address, err = formatEthAddress(ctx, location.Address); err != nil {
+			return err
+		}
+	}
+	eventABI, err := ffi2abi.ConvertFFIEventDefinitionToABI(ctx, &listener.Event.FFIEventDefinition)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgContractParamInvalid)
+	}
+
+	subID := fmt.Sprintf("ff-sub-%s-%s", listener.Namespace, listener.ID)
+	firstEvent := string(core.SubOptsFirstEventNewest)
+	if listener.Options != nil {
+		firstEvent = listener.Options.FirstEvent
+	}
+	err = e.addListener(ctx, newListener(subID, listener.Namespace, address, eventABI), firstEvent)
+	if err != nil {
+		return err
+	}
+	listener.BackendID = subID
+	return nil
+}
+
+func newListener(id, namespace, address string, event *abi.Entry) *listener {
+	return &listener{
+		id:        id,
+		namespace: namespace,
+		address:   address,
+		event:     event,
+	}
+}
+
+func (e *EVMRPC) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
+	e.listenersMutex.Lock()
+	defer e.listenersMutex.Unlock()
+	if _, ok := e.listeners[subscription.BackendID]; !ok {
+		if okNotFound {
+			return nil
+		}
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCListenerNotFound, subscription.BackendID)
+	}
+	delete(e.listeners, subscription.BackendID)
+	return e.checkpoints.remove(ctx, subscription.BackendID)
+}
+
+// GetContractListenerStatus returns the checkpoint of a listener. Listeners are held in memory, so after
+// a restart the listener is not found until it is created again - resuming from its persisted checkpoint.
+func (e *EVMRPC) GetContractListenerStatus(ctx context.Context, subID string, okNotFound bool) (found bool, status interface{}, err error) {
+	e.listenersMutex.Lock()
+	defer e.listenersMutex.Unlock()
+	l, ok := e.listeners[subID]
+	if !ok {
+		if okNotFound {
+			return false, nil, nil
+		}
+		return false, nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCListenerNotFound, subID)
+	}
+	return true, &ethereum.ListenerStatus{
+		Catchup: e.safeHead >= 0 && e.safeHead-l.checkpoint > e.maxBlockRange,
+		Checkpoint: ethereum.ListenerCheckpoint{
+			Block: l.checkpoint,
+		},
+	}, nil
+}
+
+func (e *EVMRPC) eventLoop() {
+	l := log.L(e.ctx).WithField("role", "event-loop")
+	ctx := log.WithLogger(e.ctx, l)
+	ticker := time.NewTicker(e.eventsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			l.Debugf("Event loop exiting")
+			return
+		case <-ticker.C:
+			e.pollEvents(ctx)
+		}
+	}
+}
+
+// pollEvents processes the logs for every listener, up to the newest block that has the required number of confirmations
+func (e *EVMRPC) pollEvents(ctx context.Context) {
+	head, err := e.blockNumber(ctx)
+	if err != nil {
+		log.L(ctx).Errorf("Failed to query block number for events: %s", err)
+		return
+	}
+	safeHead := head - e.confirmations
+
+	e.listenersMutex.Lock()
+	e.safeHead = safeHead
+	listeners := make([]*listener, 0, len(e.listeners))
+	for _, l := range e.listeners {
+		listeners = append(listeners, l)
+	}
+	e.listenersMutex.Unlock()
+	sort.Slice(listeners, func(i, j int) bool { return listeners[i].id < listeners[j].id })
+
+	for _, l := range listeners {
+		if err := e.pollListener(ctx, l, safeHead); err != nil {
+			log.L(ctx).Errorf("Failed to process events for listener '%s': %s", l.id, err)
+		}
+	}
+}
+
+func (e *EVMRPC) pollListener(ctx context.Context, l *listener, safeHead int64) error {
+	for ctx.Err() == nil {
+		e.listenersMutex.Lock()
+		fromBlock := l.checkpoint + 1
+		e.listenersMutex.Unlock()
+		if fromBlock > safeHead {
+			return nil
+		}
+		toBlock := fromBlock + e.maxBlockRange - 1
+		if toBlock > safeHead {
+			toBlock = safeHead
+		}
+
+		logs, err := e.getLogs(ctx, l, fromBlock, toBlock)
+		if err != nil {
+			return err
+		}
+		for _, entry := range logs {
+			if err := e.handleLog(ctx, l, entry); err != nil {
+				return err
+			}
+		}
+
+		// The checkpoint only moves once every event in the range has been accepted
+		if err := e.checkpoints.set(ctx, l.id, toBlock); err != nil {
+			return err
+		}
+		e.listenersMutex.Lock()
+		l.checkpoint = toBlock
+		e.listenersMutex.Unlock()
+	}
+	return nil
+}
+
+func (e *EVMRPC) getLogs(ctx context.Context, l *listener, fromBlock, toBlock int64) ([]*ethLog, error) {
+	var logs []*ethLog
+	rpcErr := e.rpc.CallRPC(ctx, &logs, "eth_getLogs", &logFilter{
+		FromBlock: ethtypes.NewHexInteger64(fromBlock),
+		ToBlock:   ethtypes.NewHexInteger64(toBlock),
+		Address:   l.address,
+		Topics:    []ethtypes.HexBytes0xPrefix{l.topic},
+	})
+	if rpcErr != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		bi, bj := logs[i].BlockNumber.BigInt(), logs[j].BlockNumber.BigInt()
+		if c := bi.Cmp(bj); c != 0 {
+			return c < 0
+		}
+		return logs[i].LogIndex.BigInt().Cmp(logs[j].LogIndex.BigInt()) < 0
+	})
+	return logs, nil
+}
+
+func (e *EVMRPC) getBlockTimestamp(ctx context.Context, blockNumber int64) (*fftypes.FFTime, error) {
+	cacheKey := fmt.Sprintf("block:%d", blockNumber)
+	if timestamp := e.cache.GetInt(cacheKey); timestamp != 0 {
+		return fftypes.UnixTime(int64(timestamp)), nil
+	}
+	var block *blockHeader
+	if rpcErr := e.rpc.CallRPC(ctx, &block, "eth_getBlockByNumber", ethtypes.NewHexInteger64(blockNumber), false); rpcErr != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	if block == nil || block.Timestamp == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, fmt.Sprintf("block %d not found", blockNumber))
+	}
+	timestamp := block.Timestamp.BigInt().Int64()
+	e.cache.SetInt(cacheKey, int(timestamp))
+	return fftypes.UnixTime(timestamp), nil
+}
+
+// parseLog decodes a log into an event, returning nil for logs that cannot be decoded against the ABI of the listener
+func (e *EVMRPC) parseLog(ctx context.Context, l *listener, entry *ethLog) (*blockchain.Event, error) {
+	if entry.BlockNumber == nil || entry.TransactionIndex == nil || entry.LogIndex == nil || entry.Address == nil {
+		log.L(ctx).Errorf("Log is not valid - missing data: %+v", entry)
+		return nil, nil // move on
+	}
+	cv, err := l.event.DecodeEventDataCtx(ctx, entry.Topics, entry.Data)
+	if err != nil {
+		log.L(ctx).Errorf("Log is not valid for event '%s' (%s): %+v", l.signature, err, entry)
+		return nil, nil // move on
+	}
+	output, err := abi.NewSerializer().SetByteSerializer(abi.HexByteSerializer0xPrefix).SerializeInterfaceCtx(ctx, cv)
+	if err != nil {
+		log.L(ctx).Errorf("Log is not valid for event '%s' (%s): %+v", l.signature, err, entry)
+		return nil, nil // move on
+	}
+
+	blockNumber := entry.BlockNumber.BigInt().Int64()
+	txIndex := entry.TransactionIndex.BigInt().Int64()
+	logIndex := entry.LogIndex.BigInt().Int64()
+	timestamp, err := e.getBlockTimestamp(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	address := entry.Address.String()
+	outputJSON, _ := output.(map[string]interface{})
+	return &blockchain.Event{
+		BlockchainTXID: entry.TransactionHash,
+		Source:         e.Name(),
+		Name:           l.event.Name,
+		ProtocolID:     fmt.Sprintf("%.12d/%.6d/%.6d", blockNumber, txIndex, logIndex),
+		Output:         outputJSON,
+		Info: fftypes.JSONObject{
+			"address":          address,
+			"blockNumber":      strconv.FormatInt(blockNumber, 10),
+			"blockHash":        entry.BlockHash,
+			"transactionHash":  entry.TransactionHash,
+			"transactionIndex": strconv.FormatInt(txIndex, 10),
+			"logIndex":         strconv.FormatInt(logIndex, 10),
+			"signature":        l.signature,
+			"subId":            l.id,
+			"timestamp":        strconv.FormatInt(timestamp.Time().Unix(), 10),
+		},
+		Timestamp: timestamp,
+		Location:  fmt.Sprintf("address=%s", address),
+		Signature: l.signature,
+	}, nil
+}
+
+func (e *EVMRPC) handleLog(ctx context.Context, l *listener, entry *ethLog) error {
+	if entry.Removed {
+		return nil
+	}
+	event, err := e.parseLog(ctx, l, entry)
+	if err != nil || event == nil {
+		return err
+	}
+	log.L(ctx).Infof("Received '%s' event %s on '%s'", l.signature, event.ProtocolID, l.id)
+	if l.firefly {
+		subInfo := e.subs.GetSubscription(l.id)
+		if subInfo == nil {
+			log.L(ctx).Debugf("Ignoring event for removed subscription '%s'", l.id)
+			return nil
+		}
+		return e.handleBatchPinEvent(ctx, l.location, subInfo, event)
+	}
+	return e.callbacks.BlockchainEvent(ctx, l.namespace, &blockchain.EventWithSubscription{
+		Event:        *event,
+		Subscription: l.id,
+	})
+}
+
+func (e *EVMRPC) handleBatchPinEvent(ctx context.Context, location *fftypes.JSONAny, subInfo *common.SubscriptionInfo, event *blockchain.Event) error {
+	nsOrAction := event.Output.GetString("action")
+	if nsOrAction == "" {
+		nsOrAction = event.Output.GetString("namespace")
+	}
+	params := &common.BatchPinParams{
+		UUIDs:      event.Output.GetString("uuids"),
+		BatchHash:  event.Output.GetString("batchHash"),
+		PayloadRef: event.Output.GetString("payloadRef"),
+		Contexts:   event.Output.GetStringArray("contexts"),
+		NsOrAction: nsOrAction,
+	}
+
+	authorAddress, err := formatEthAddress(ctx, event.Output.GetString("author"))
+	if err != nil {
+		log.L(ctx).Errorf("BatchPin event is not valid - bad from address (%s): %+v", err, event.Output)
+		return nil // move on
+	}
+	verifier := &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: authorAddress,
+	}
+	return e.callbacks.BatchPinOrNetworkAction(ctx, subInfo, location, event, verifier, params)
+}
@@ -0,0 +1,560 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var changedEventABI = &abi.Entry{
+	Type: "event",
+	Name: "Changed",
+	Inputs: abi.ParameterArray{
+		{Name: "from", Type: "address", Indexed: true},
+		{Name: "value", Type: "uint256"},
+	},
+}
+
+func testChangedEvent() *core.FFISerializedEvent {
+	return &core.FFISerializedEvent{
+		FFIEventDefinition: fftypes.FFIEventDefinition{
+			Name: "Changed",
+			Params: fftypes.FFIParams{
+				{
+					Name:   "from",
+					Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"address","indexed":true}}`),
+				},
+				{
+					Name:   "value",
+					Schema: fftypes.JSONAnyPtr(`{"type":"integer","details":{"type":"uint256"}}`),
+				},
+			},
+		},
+	}
+}
+
+// emitChanged configures the chain to emit a Changed event for every transaction, with the value set to the block number
+func emitChanged(t *testing.T, d *devChain) {
+	d.onTransaction = func(tx *ethTransaction) (int64, []*devLog) {
+		data, err := changedEventABI.Inputs[1:].EncodeABIDataValues([]interface{}{d.head})
+		assert.NoError(t, err)
+		from := ethtypes.MustNewHexBytes0xPrefix("0x000000000000000000000000" + strings.TrimPrefix(tx.From, "0x"))
+		return 1, []*devLog{{
+			address: tx.To,
+			topics:  []ethtypes.HexBytes0xPrefix{changedEventABI.SignatureHashBytes(), from},
+			data:    data,
+		}}
+	}
+}
+
+func newTestEventHandler(e *EVMRPC) *blockchainmocks.Callbacks {
+	em := &blockchainmocks.Callbacks{}
+	e.SetHandler("ns1", em)
+	return em
+}
+
+func addTestListener(t *testing.T, e *EVMRPC, firstEvent string) *core.ContractListener {
+	listener := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Location:  testLocation(),
+		Event:     testChangedEvent(),
+		Options:   &core.ContractListenerOptions{FirstEvent: firstEvent},
+	}
+	err := e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+	return listener
+}
+
+func TestContractListenerEvents(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	listener := addTestListener(t, e, "oldest")
+	assert.Equal(t, fmt.Sprintf("ff-sub-ns1-%s", listener.ID), listener.BackendID)
+	submitTestTransaction(t, e)
+	submitTestTransaction(t, e)
+
+	var events []*blockchain.EventWithSubscription
+	em.On("BlockchainEvent", mock.Anything).Run(func(args mock.Arguments) {
+		events = append(events, args[0].(*blockchain.EventWithSubscription))
+	}).Return(nil)
+	e.pollEvents(context.Background())
+
+	assert.Len(t, events, 2)
+	event := events[1]
+	assert.Equal(t, listener.BackendID, event.Subscription)
+	assert.Equal(t, "Changed", event.Name)
+	assert.Equal(t, "evmrpc", event.Source)
+	assert.Equal(t, "Changed(address,uint256)", event.Signature)
+	assert.Equal(t, "000000000002/000000/000000", event.ProtocolID)
+	assert.Equal(t, fmt.Sprintf("0x%064x", 2), event.BlockchainTXID)
+	assert.Equal(t, fmt.Sprintf("address=%s", testContractAddress), event.Location)
+	assert.Equal(t, int64(devChainGenesisTime+2), event.Timestamp.Time().Unix())
+	assert.Equal(t, testSigningKey, event.Output.GetString("from"))
+	assert.Equal(t, "2", event.Output.GetString("value"))
+	assert.Equal(t, "2", event.Info.GetString("blockNumber"))
+	assert.Equal(t, listener.BackendID, event.Info.GetString("subId"))
+
+	found, status, err := e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, &ethereum.ListenerStatus{Checkpoint: ethereum.ListenerCheckpoint{Block: 2}}, status)
+
+	// Polling again does not redeliver the events
+	e.pollEvents(context.Background())
+	assert.Len(t, events, 2)
+	em.AssertExpectations(t)
+}
+
+func TestContractListenerAllAddressesNewest(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	submitTestTransaction(t, e)
+	listener := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Event:     testChangedEvent(),
+	}
+	err := e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+	submitTestTransaction(t, e)
+
+	em.On("BlockchainEvent", mock.MatchedBy(func(event *blockchain.EventWithSubscription) bool {
+		return event.ProtocolID == "000000000002/000000/000000"
+	})).Return(nil).Once()
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+}
+
+func TestContractListenerConfirmationsAndRange(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	e.confirmations = 1
+	e.maxBlockRange = 1
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	listener := addTestListener(t, e, "0")
+	submitTestTransaction(t, e)
+	submitTestTransaction(t, e)
+	submitTestTransaction(t, e)
+
+	em.On("BlockchainEvent", mock.Anything).Return(nil).Twice()
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+
+	_, status, _ := e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Equal(t, int64(2), status.(*ethereum.ListenerStatus).Checkpoint.Block)
+	assert.False(t, status.(*ethereum.ListenerStatus).Catchup)
+
+	d.mineBlocks(5)
+	e.safeHead = 7
+	_, status, _ = e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.True(t, status.(*ethereum.ListenerStatus).Catchup)
+}
+
+func TestContractListenerCallbackFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	listener := addTestListener(t, e, "oldest")
+	submitTestTransaction(t, e)
+
+	em.On("BlockchainEvent", mock.Anything).Return(fmt.Errorf("pop")).Once()
+	e.pollEvents(context.Background())
+	_, status, _ := e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Equal(t, int64(-1), status.(*ethereum.ListenerStatus).Checkpoint.Block)
+
+	em.On("BlockchainEvent", mock.Anything).Return(nil).Once()
+	e.pollEvents(context.Background())
+	_, status, _ = e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Equal(t, int64(1), status.(*ethereum.ListenerStatus).Checkpoint.Block)
+	em.AssertExpectations(t)
+}
+
+func TestContractListenerResumesFromCheckpoint(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	listener := addTestListener(t, e, "oldest")
+	submitTestTransaction(t, e)
+
+	em.On("BlockchainEvent", mock.Anything).Return(nil).Once()
+	e.pollEvents(context.Background())
+
+	// Simulate a restart, where the listener is re-created from the persisted checkpoints
+	checkpoints, err := loadCheckpoints(context.Background(), e.checkpoints.file)
+	assert.NoError(t, err)
+	e.checkpoints = checkpoints
+	delete(e.listeners, listener.BackendID)
+	err = e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+	_, status, _ := e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Equal(t, int64(1), status.(*ethereum.ListenerStatus).Checkpoint.Block)
+
+	// The event that was already processed is not redelivered
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+}
+
+func TestContractListenerCheckpointWriteFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	listener := addTestListener(t, e, "oldest")
+	submitTestTransaction(t, e)
+	e.checkpoints.file = filepath.Join(t.TempDir(), "missing", "checkpoints.json")
+
+	em.On("BlockchainEvent", mock.Anything).Return(nil)
+	e.pollEvents(context.Background())
+	_, status, _ := e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Equal(t, int64(-1), status.(*ethereum.ListenerStatus).Checkpoint.Block)
+	_, ok := e.checkpoints.get(listener.BackendID)
+	assert.False(t, ok)
+}
+
+func TestContractListenerSkipsBadLogs(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	em := newTestEventHandler(e)
+	listener := addTestListener(t, e, "oldest")
+
+	d.mineBlocks(1)
+	d.mux.Lock()
+	topics := []ethtypes.HexBytes0xPrefix{changedEventABI.SignatureHashBytes()}
+	d.logs = append(d.logs,
+		// removed by a re-org
+		fftypes.JSONObject{"address": testContractAddress, "topics": topics, "blockNumber": "0x1", "transactionIndex": "0x0", "logIndex": "0x0", "removed": true},
+		// missing the indexed topic
+		fftypes.JSONObject{"address": testContractAddress, "topics": topics, "blockNumber": "0x1", "transactionIndex": "0x0", "logIndex": "0x1", "data": "0x"},
+		// missing fields
+		fftypes.JSONObject{"address": testContractAddress, "topics": topics, "blockNumber": "0x1", "data": "0x"},
+	)
+	d.mux.Unlock()
+
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+	assert.Equal(t, int64(1), e.listeners[listener.BackendID].checkpoint)
+}
+
+func TestPollEventsBlockNumberFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_blockNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	e.pollEvents(context.Background())
+	assert.Equal(t, int64(-1), e.safeHead)
+}
+
+func TestPollEventsGetLogsFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	listener := addTestListener(t, e, "oldest")
+	d.mineBlocks(1)
+	d.setFailure("eth_getLogs", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	e.pollEvents(context.Background())
+	assert.Equal(t, int64(-1), e.listeners[listener.BackendID].checkpoint)
+}
+
+func TestPollEventsBlockTimestampFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	listener := addTestListener(t, e, "oldest")
+	submitTestTransaction(t, e)
+
+	d.setFailure("eth_getBlockByNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	e.pollEvents(context.Background())
+	assert.Equal(t, int64(-1), e.listeners[listener.BackendID].checkpoint)
+}
+
+func TestGetBlockTimestampNotFound(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, err := e.getBlockTimestamp(context.Background(), 100)
+	assert.Regexp(t, "FF10491.*block 100 not found", err)
+}
+
+func TestGetBlockTimestampCached(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.mineBlocks(1)
+	ts, err := e.getBlockTimestamp(context.Background(), 1)
+	assert.NoError(t, err)
+	d.setFailure("eth_getBlockByNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	ts2, err := e.getBlockTimestamp(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ts, ts2)
+}
+
+func TestPollListenerCancelled(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	listener := addTestListener(t, e, "oldest")
+	d.mineBlocks(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := e.pollListener(ctx, e.listeners[listener.BackendID], 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), e.listeners[listener.BackendID].checkpoint)
+}
+
+func TestEventLoop(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	e.eventsInterval = time.Millisecond
+	emitChanged(t, d)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+	addTestListener(t, e, "oldest")
+	submitTestTransaction(t, e)
+
+	received := make(chan struct{})
+	em.On("BlockchainEvent", mock.Anything).Run(func(args mock.Arguments) {
+		close(received)
+	}).Return(nil).Once()
+
+	loopDone := make(chan struct{})
+	go func() {
+		e.eventLoop()
+		close(loopDone)
+	}()
+	<-received
+	e.cancelCtx()
+	<-loopDone
+}
+
+func TestInitialCheckpoint(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.mineBlocks(10)
+	for firstEvent, expected := range map[string]int64{
+		"":       -1,
+		"oldest": -1,
+		"newest": 10,
+		"0":      -1,
+		"5":      4,
+	} {
+		checkpoint, err := e.initialCheckpoint(context.Background(), firstEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, checkpoint, firstEvent)
+	}
+	_, err := e.initialCheckpoint(context.Background(), "bad")
+	assert.Regexp(t, "FF10495.*bad", err)
+	_, err = e.initialCheckpoint(context.Background(), "-1")
+	assert.Regexp(t, "FF10495", err)
+
+	d.setFailure("eth_blockNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	_, err = e.initialCheckpoint(context.Background(), "newest")
+	assert.Regexp(t, "FF10491.*pop", err)
+}
+
+func TestAddContractListenerErrors(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	listener := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Location:  fftypes.JSONAnyPtr(`{}`),
+		Event:     testChangedEvent(),
+	}
+	err := e.AddContractListener(context.Background(), listener)
+	assert.Regexp(t, "FF10310", err)
+
+	listener.Location = fftypes.JSONAnyPtr(`{"address":"bad"}`)
+	err = e.AddContractListener(context.Background(), listener)
+	assert.Regexp(t, "FF10141", err)
+
+	listener.Location = testLocation()
+	listener.Event = &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{
+		Name:   "Bad",
+		Params: fftypes.FFIParams{{Name: "x", Schema: fftypes.JSONAnyPtr(`{"type":"wrong"}`)}},
+	}}
+	err = e.AddContractListener(context.Background(), listener)
+	assert.Regexp(t, "FF10311", err)
+
+	listener.Event = testChangedEvent()
+	listener.Options = &core.ContractListenerOptions{FirstEvent: "bad"}
+	err = e.AddContractListener(context.Background(), listener)
+	assert.Regexp(t, "FF10495", err)
+	assert.Empty(t, e.listeners)
+}
+
+func TestDeleteContractListener(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	listener := addTestListener(t, e, "oldest")
+	err := e.checkpoints.set(context.Background(), listener.BackendID, 10)
+	assert.NoError(t, err)
+
+	err = e.DeleteContractListener(context.Background(), listener, false)
+	assert.NoError(t, err)
+	_, ok := e.checkpoints.get(listener.BackendID)
+	assert.False(t, ok)
+	err = e.DeleteContractListener(context.Background(), listener, true)
+	assert.NoError(t, err)
+	err = e.DeleteContractListener(context.Background(), listener, false)
+	assert.Regexp(t, "FF10497", err)
+
+	found, _, err := e.GetContractListenerStatus(context.Background(), listener.BackendID, true)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	_, _, err = e.GetContractListenerStatus(context.Background(), listener.BackendID, false)
+	assert.Regexp(t, "FF10497", err)
+}
+
+func TestFireFlySubscriptionV2(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.emitBatchPins(testContractAddress)
+	d.setCall(t, networkVersionMethodABI, 2)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	subID, err := e.AddFireflySubscription(context.Background(), &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location:   testLocation(),
+		FirstEvent: "oldest",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("ff-batchpin-%s-ns1", testContractAddress), subID)
+
+	batch := testBatchPin()
+	err = e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "ns1", testSigningKey, batch, testLocation())
+	assert.NoError(t, err)
+	err = e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionTerminate, "", testLocation())
+	assert.NoError(t, err)
+
+	em.On("BatchPinComplete", "ns1", mock.MatchedBy(func(b *blockchain.BatchPin) bool {
+		return b.BatchID.Equals(batch.BatchID) &&
+			b.TransactionID.Equals(batch.TransactionID) &&
+			b.BatchHash.Equals(batch.BatchHash) &&
+			b.BatchPayloadRef == batch.BatchPayloadRef &&
+			len(b.Contexts) == 2 && b.Contexts[1].Equals(batch.Contexts[1]) &&
+			b.TransactionType == core.TransactionTypeBatchPin &&
+			b.Event.ProtocolID == "000000000001/000000/000000"
+	}), &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: testSigningKey}).Return(nil).Once()
+	em.On("BlockchainNetworkAction", "terminate", "", mock.MatchedBy(func(location *fftypes.JSONAny) bool {
+		return location.JSONObject().GetString("address") == testContractAddress
+	}), mock.Anything, &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: testSigningKey}).Return(nil).Once()
+
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+
+	// Events for a removed subscription are ignored
+	e.RemoveFireflySubscription(context.Background(), subID)
+	err = e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "ns1", testSigningKey, testBatchPin(), testLocation())
+	assert.NoError(t, err)
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+}
+
+func TestFireFlySubscriptionV1(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.emitBatchPins(testContractAddress)
+	d.setRevert(networkVersionMethodABI, &rpcbackend.RPCError{Code: 3, Message: "execution reverted"})
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	em := newTestEventHandler(e)
+
+	subID, err := e.AddFireflySubscription(context.Background(), &core.Namespace{Name: "ns1", NetworkName: "network1"}, &blockchain.MultipartyContract{
+		Location: testLocation(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("ff-batchpin-%s", testContractAddress), subID)
+
+	batch := testBatchPin()
+	err = e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "network1", testSigningKey, batch, testLocation())
+	assert.NoError(t, err)
+
+	em.On("BatchPinComplete", "ns1", mock.MatchedBy(func(b *blockchain.BatchPin) bool {
+		return b.BatchID.Equals(batch.BatchID)
+	}), mock.Anything).Return(nil).Once()
+	e.pollEvents(context.Background())
+	em.AssertExpectations(t)
+}
+
+func TestAddFireflySubscriptionErrors(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := e.AddFireflySubscription(context.Background(), ns, &blockchain.MultipartyContract{Location: fftypes.JSONAnyPtr(`{}`)})
+	assert.Regexp(t, "FF10310", err)
+	_, err = e.AddFireflySubscription(context.Background(), ns, &blockchain.MultipartyContract{Location: fftypes.JSONAnyPtr(`{"address":"bad"}`)})
+	assert.Regexp(t, "FF10141", err)
+
+	d.setFailure("eth_call", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	_, err = e.AddFireflySubscription(context.Background(), ns, &blockchain.MultipartyContract{Location: testLocation()})
+	assert.Regexp(t, "FF10491", err)
+
+	d.setFailure("eth_call", nil)
+	d.setCall(t, networkVersionMethodABI, 2)
+	_, err = e.AddFireflySubscription(context.Background(), ns, &blockchain.MultipartyContract{Location: testLocation(), FirstEvent: "bad"})
+	assert.Regexp(t, "FF10495", err)
+}
+
+func TestHandleBatchPinEventBadAuthor(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.handleBatchPinEvent(context.Background(), testLocation(), nil, &blockchain.Event{
+		Output: fftypes.JSONObject{"author": "bad"},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,606 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/ffi2abi"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	batchPinEventSignature = "BatchPin(address,uint256,string,bytes32,bytes32,string,bytes32[])"
+)
+
+// EVMRPC is a blockchain plugin for EVM based chains, that talks directly to the standard JSON-RPC
+// interface of a node - rather than through an EthConnect or EVMConnect gateway. It builds and ABI encodes
+// transactions itself, polls the node for receipts, and polls the logs of each block for events.
+type EVMRPC struct {
+	ctx                 context.Context
+	cancelCtx           context.CancelFunc
+	capabilities        *blockchain.Capabilities
+	callbacks           common.BlockchainCallbacks
+	subs                common.FireflySubscriptions
+	metrics             metrics.Manager
+	cache               cache.CInterface
+	rpcConf             config.Section
+	signerConf          config.Section
+	rpc                 rpcbackend.Backend
+	signer              transactionSigner
+	confirmations       int64
+	eventsInterval      time.Duration
+	maxBlockRange       int64
+	receiptsInterval    time.Duration
+	listenersMutex      sync.Mutex
+	listeners           map[string]*listener
+	checkpoints         *checkpointStore
+	safeHead            int64
+	receiptsMutex       sync.Mutex
+	pendingTransactions map[string]*pendingTransaction
+}
+
+type queryOutput struct {
+	Output interface{} `json:"output"`
+}
+
+// ethTransaction is the transaction object passed to eth_sendTransaction, eth_signTransaction and eth_estimateGas
+type ethTransaction struct {
+	From     string                    `json:"from"`
+	To       string                    `json:"to,omitempty"`
+	Data     ethtypes.HexBytes0xPrefix `json:"data"`
+	Gas      *ethtypes.HexInteger      `json:"gas,omitempty"`
+	GasPrice *ethtypes.HexInteger      `json:"gasPrice,omitempty"`
+	Value    *ethtypes.HexInteger      `json:"value,omitempty"`
+	Nonce    *ethtypes.HexInteger      `json:"nonce,omitempty"`
+	ChainID  *ethtypes.HexInteger      `json:"chainId,omitempty"`
+}
+
+var addressVerify = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// revertReasonABI is the standard Error(string) raised by require/revert in Solidity
+var revertReasonABI = &abi.Entry{
+	Type:   "error",
+	Name:   "Error",
+	Inputs: abi.ParameterArray{{Name: "reason", Type: "string"}},
+}
+
+func (e *EVMRPC) Name() string {
+	return "evmrpc"
+}
+
+func (e *EVMRPC) VerifierType() core.VerifierType {
+	return core.VerifierTypeEthAddress
+}
+
+func (e *EVMRPC) Init(ctx context.Context, cancelCtx context.CancelFunc, conf config.Section, metrics metrics.Manager, cacheManager cache.Manager) (err error) {
+	e.InitConfig(conf)
+
+	e.ctx = log.WithLogField(ctx, "proto", "evmrpc")
+	e.cancelCtx = cancelCtx
+	e.metrics = metrics
+	e.capabilities = &blockchain.Capabilities{}
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.subs = common.NewFireflySubscriptions()
+	e.listeners = make(map[string]*listener)
+	e.pendingTransactions = make(map[string]*pendingTransaction)
+	e.safeHead = -1
+
+	if e.rpcConf.GetString(ffresty.HTTPConfigURL) == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "url", "blockchain.evmrpc.rpc")
+	}
+	client, err := ffresty.New(e.ctx, e.rpcConf)
+	if err != nil {
+		return err
+	}
+	e.rpc = rpcbackend.NewRPCClient(client)
+
+	switch signerType := e.signerConf.GetString(SignerConfigType); signerType {
+	case "node":
+		e.signer = &nodeSigner{rpc: e.rpc}
+	case "jsonrpc":
+		if e.signerConf.GetString(ffresty.HTTPConfigURL) == "" {
+			return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "url", "blockchain.evmrpc.signer")
+		}
+		signerClient, err := ffresty.New(e.ctx, e.signerConf)
+		if err != nil {
+			return err
+		}
+		e.signer = &remoteSigner{
+			signer:              rpcbackend.NewRPCClient(signerClient),
+			node:                e.rpc,
+			gasEstimationFactor: e.signerConf.GetFloat64(SignerConfigGasEstimationFactor),
+		}
+	default:
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidSignerType, signerType)
+	}
+
+	checkpointFile := conf.GetString(EventsConfigCheckpointFile)
+	if checkpointFile == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "events.checkpointFile", "blockchain.evmrpc")
+	}
+	if e.checkpoints, err = loadCheckpoints(e.ctx, checkpointFile); err != nil {
+		return err
+	}
+
+	e.confirmations = conf.GetInt64(ConfigConfirmations)
+	e.eventsInterval = conf.GetDuration(EventsConfigPollingInterval)
+	e.maxBlockRange = conf.GetInt64(EventsConfigMaxBlockRange)
+	e.receiptsInterval = conf.GetDuration(ReceiptsConfigPollingInterval)
+
+	e.cache, err = cacheManager.GetCache(
+		cache.NewCacheConfig(
+			ctx,
+			coreconfig.CacheBlockchainLimit,
+			coreconfig.CacheBlockchainTTL,
+			"",
+		),
+	)
+	return err
+}
+
+func (e *EVMRPC) SetHandler(namespace string, handler blockchain.Callbacks) {
+	e.callbacks.SetHandler(namespace, handler)
+}
+
+func (e *EVMRPC) SetOperationHandler(namespace string, handler core.OperationCallbacks) {
+	e.callbacks.SetOperationalHandler(namespace, handler)
+}
+
+func (e *EVMRPC) Start() error {
+	go e.eventLoop()
+	go e.receiptLoop()
+	return nil
+}
+
+func (e *EVMRPC) Capabilities() *blockchain.Capabilities {
+	return e.capabilities
+}
+
+func formatEthAddress(ctx context.Context, key string) (string, error) {
+	keyLower := strings.ToLower(key)
+	keyNoHexPrefix := strings.TrimPrefix(keyLower, "0x")
+	if addressVerify.MatchString(keyNoHexPrefix) {
+		return "0x" + keyNoHexPrefix, nil
+	}
+	return "", i18n.NewError(ctx, coremsgs.MsgInvalidEthAddress)
+}
+
+func ethHexFormatB32(b *fftypes.Bytes32) string {
+	if b == nil {
+		return "0x0000000000000000000000000000000000000000000000000000000000000000"
+	}
+	return "0x" + hex.EncodeToString(b[0:32])
+}
+
+func (e *EVMRPC) ResolveSigningKey(ctx context.Context, key string, intent blockchain.ResolveKeyIntent) (string, error) {
+	return formatEthAddress(ctx, key)
+}
+
+// applyOptions sets the gas, gas price and value of a transaction from the options on the request
+func (e *EVMRPC) applyOptions(ctx context.Context, tx *ethTransaction, options map[string]interface{}) error {
+	for k, v := range options {
+		var target **ethtypes.HexInteger
+		switch k {
+		case "gas":
+			target = &tx.Gas
+		case "gasPrice":
+			target = &tx.GasPrice
+		case "value":
+			target = &tx.Value
+		default:
+			return i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidOption, k, "unsupported option")
+		}
+		b, _ := json.Marshal(v)
+		var i fftypes.FFBigInt
+		if err := i.UnmarshalJSON(b); err != nil {
+			return i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidOption, k, err)
+		}
+		*target = ethtypes.NewHexInteger(i.Int())
+	}
+	return nil
+}
+
+func (e *EVMRPC) sendTransaction(ctx context.Context, nsOpID string, tx *ethTransaction, options map[string]interface{}) error {
+	if err := e.applyOptions(ctx, tx, options); err != nil {
+		return err
+	}
+	txHash, err := e.signer.sendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	e.addPendingTransaction(ctx, nsOpID, txHash)
+	return nil
+}
+
+func (e *EVMRPC) invokeContractMethod(ctx context.Context, address, signingKey string, method *abi.Entry, nsOpID string, input []interface{}, options map[string]interface{}) error {
+	if e.metrics.IsMetricsEnabled() {
+		e.metrics.BlockchainTransaction(address, method.Name)
+	}
+	callData, err := method.EncodeCallDataValuesCtx(ctx, input)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgContractParamInvalid, err)
+	}
+	return e.sendTransaction(ctx, nsOpID, &ethTransaction{
+		From: signingKey,
+		To:   address,
+		Data: callData,
+	}, options)
+}
+
+// decodeRevert returns a readable error from a failed call. Reverts are decoded using the standard
+// Error(string), as well as any custom errors that were supplied for the method.
+func (e *EVMRPC) decodeRevert(ctx context.Context, rpcErr *rpcbackend.RPCError, errors []*abi.Entry) error {
+	var revertData ethtypes.HexBytes0xPrefix
+	_ = json.Unmarshal(rpcErr.Data.Bytes(), &revertData)
+	if len(revertData) >= 4 {
+		for _, errorABI := range append([]*abi.Entry{revertReasonABI}, errors...) {
+			if errorABI == nil || !bytes.Equal(errorABI.FunctionSelectorBytes(), revertData[0:4]) {
+				continue
+			}
+			cv, err := errorABI.Inputs.DecodeABIDataCtx(ctx, revertData, 4)
+			if err != nil {
+				break
+			}
+			if errorABI == revertReasonABI {
+				return i18n.NewError(ctx, coremsgs.MsgEVMRPCCallReverted, cv.Children[0].Value)
+			}
+			values, _ := abi.NewSerializer().SetByteSerializer(abi.HexByteSerializer0xPrefix).SerializeJSONCtx(ctx, cv)
+			return i18n.NewError(ctx, coremsgs.MsgEVMRPCCallReverted, fmt.Sprintf("%s%s", errorABI.Name, values))
+		}
+	}
+	if len(revertData) > 0 || strings.Contains(strings.ToLower(rpcErr.Message), "revert") {
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCCallReverted, rpcErr.Message)
+	}
+	return i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+}
+
+func (e *EVMRPC) callContractMethod(ctx context.Context, address, signingKey string, method *abi.Entry, input []interface{}, errors []*abi.Entry) (interface{}, error) {
+	if e.metrics.IsMetricsEnabled() {
+		e.metrics.BlockchainQuery(address, method.Name)
+	}
+	callData, err := method.EncodeCallDataValuesCtx(ctx, input)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgContractParamInvalid, err)
+	}
+	var result ethtypes.HexBytes0xPrefix
+	if rpcErr := e.rpc.CallRPC(ctx, &result, "eth_call", &ethTransaction{
+		From: signingKey,
+		To:   address,
+		Data: callData,
+	}, "latest"); rpcErr != nil {
+		return nil, e.decodeRevert(ctx, rpcErr, errors)
+	}
+	cv, err := method.Outputs.DecodeABIDataCtx(ctx, result, 0)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgEVMRPCErr, err)
+	}
+	// Outputs without a name are returned as "output", "output1", "output2" etc.
+	return abi.NewSerializer().
+		SetByteSerializer(abi.HexByteSerializer0xPrefix).
+		SetDefaultNameGenerator(func(idx int) string {
+			if idx == 0 {
+				return "output"
+			}
+			return fmt.Sprintf("output%d", idx)
+		}).
+		SerializeInterfaceCtx(ctx, cv)
+}
+
+func (e *EVMRPC) buildBatchPinInput(ctx context.Context, version int, namespace string, batch *blockchain.BatchPin) (*abi.Entry, []interface{}) {
+	ethHashes := make([]string, len(batch.Contexts))
+	for i, v := range batch.Contexts {
+		ethHashes[i] = ethHexFormatB32(v)
+	}
+	var uuids fftypes.Bytes32
+	copy(uuids[0:16], (*batch.TransactionID)[:])
+	copy(uuids[16:32], (*batch.BatchID)[:])
+
+	if version == 1 {
+		return batchPinMethodABIV1, []interface{}{
+			namespace,
+			ethHexFormatB32(&uuids),
+			ethHexFormatB32(batch.BatchHash),
+			batch.BatchPayloadRef,
+			ethHashes,
+		}
+	}
+	return batchPinMethodABI, []interface{}{
+		ethHexFormatB32(&uuids),
+		ethHexFormatB32(batch.BatchHash),
+		batch.BatchPayloadRef,
+		ethHashes,
+	}
+}
+
+func (e *EVMRPC) SubmitBatchPin(ctx context.Context, nsOpID, networkNamespace, signingKey string, batch *blockchain.BatchPin, location *fftypes.JSONAny) error {
+	ethLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return err
+	}
+	version, err := e.GetNetworkVersion(ctx, location)
+	if err != nil {
+		return err
+	}
+	method, input := e.buildBatchPinInput(ctx, version, networkNamespace, batch)
+	return e.invokeContractMethod(ctx, ethLocation.Address, signingKey, method, nsOpID, input, nil)
+}
+
+func (e *EVMRPC) SubmitNetworkAction(ctx context.Context, nsOpID string, signingKey string, action core.NetworkActionType, payload string, location *fftypes.JSONAny) error {
+	ethLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return err
+	}
+	version, err := e.GetNetworkVersion(ctx, location)
+	if err != nil {
+		return err
+	}
+
+	var input []interface{}
+	var method *abi.Entry
+	if version == 1 {
+		method = batchPinMethodABIV1
+		input = []interface{}{
+			blockchain.FireFlyActionPrefix + action,
+			ethHexFormatB32(nil),
+			ethHexFormatB32(nil),
+			payload,
+			[]string{},
+		}
+	} else {
+		method = networkActionMethodABI
+		input = []interface{}{
+			blockchain.FireFlyActionPrefix + action,
+			payload,
+		}
+	}
+	return e.invokeContractMethod(ctx, ethLocation.Address, signingKey, method, nsOpID, input, nil)
+}
+
+// DeployContract deploys the bytecode in "contract", passing the input to the constructor from the ABI in "definition"
+func (e *EVMRPC) DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) error {
+	if e.metrics.IsMetricsEnabled() {
+		e.metrics.BlockchainContractDeployment()
+	}
+	var contractABI abi.ABI
+	if err := json.Unmarshal(definition.Bytes(), &contractABI); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidContract, err)
+	}
+	var bytecode ethtypes.HexBytes0xPrefix
+	if err := json.Unmarshal(contract.Bytes(), &bytecode); err != nil || len(bytecode) == 0 {
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidContract, "contract must be the bytecode as a hex string")
+	}
+	constructor := contractABI.Constructor()
+	if constructor == nil {
+		constructor = &abi.Entry{Type: abi.Constructor}
+	}
+	params, err := constructor.Inputs.EncodeABIDataValuesCtx(ctx, input)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgContractParamInvalid, err)
+	}
+	return e.sendTransaction(ctx, nsOpID, &ethTransaction{
+		From: signingKey,
+		Data: append(bytecode, params...),
+	}, options)
+}
+
+// Check if a method supports passing extra data via conformance to ERC5750.
+// That is, check if the last method input is a "bytes" parameter.
+func (e *EVMRPC) checkDataSupport(ctx context.Context, method *abi.Entry) error {
+	if len(method.Inputs) > 0 {
+		lastParam := method.Inputs[len(method.Inputs)-1]
+		if lastParam.Type == "bytes" {
+			return nil
+		}
+	}
+	return i18n.NewError(ctx, coremsgs.MsgMethodDoesNotSupportPinning)
+}
+
+func (e *EVMRPC) prepareRequest(ctx context.Context, method *fftypes.FFIMethod, errors []*fftypes.FFIError, input map[string]interface{}) (*abi.Entry, []*abi.Entry, []interface{}, error) {
+	errorsAbi := make([]*abi.Entry, len(errors))
+	orderedInput := make([]interface{}, len(method.Params))
+	methodABI, err := ffi2abi.ConvertFFIMethodToABI(ctx, method)
+	if err != nil {
+		return methodABI, errorsAbi, orderedInput, err
+	}
+	for i, ffiError := range errors {
+		errorABI, err := ffi2abi.ConvertFFIErrorDefinitionToABI(ctx, &ffiError.FFIErrorDefinition)
+		if err == nil {
+			errorsAbi[i] = errorABI
+		}
+	}
+	for i, ffiParam := range method.Params {
+		orderedInput[i] = input[ffiParam.Name]
+	}
+	return methodABI, errorsAbi, orderedInput, nil
+}
+
+func (e *EVMRPC) ValidateInvokeRequest(ctx context.Context, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, hasMessage bool) error {
+	methodABI, _, _, err := e.prepareRequest(ctx, method, errors, input)
+	if err == nil && hasMessage {
+		err = e.checkDataSupport(ctx, methodABI)
+	}
+	return err
+}
+
+func (e *EVMRPC) InvokeContract(ctx context.Context, nsOpID string, signingKey string, location *fftypes.JSONAny, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, options map[string]interface{}, batch *blockchain.BatchPin) error {
+	ethLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return err
+	}
+	methodABI, _, orderedInput, err := e.prepareRequest(ctx, method, errors, input)
+	if err != nil {
+		return err
+	}
+	if batch != nil {
+		err := e.checkDataSupport(ctx, methodABI)
+		if err == nil {
+			pinMethod, batchPin := e.buildBatchPinInput(ctx, 2, "", batch)
+			encoded, err := pinMethod.Inputs.EncodeABIDataValuesCtx(ctx, batchPin)
+			if err == nil {
+				orderedInput[len(orderedInput)-1] = hex.EncodeToString(encoded)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return e.invokeContractMethod(ctx, ethLocation.Address, signingKey, methodABI, nsOpID, orderedInput, options)
+}
+
+func (e *EVMRPC) QueryContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, options map[string]interface{}) (interface{}, error) {
+	ethLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	methodABI, errorsABI, orderedInput, err := e.prepareRequest(ctx, method, errors, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(options) > 0 {
+		// Calls are made against the latest block, with no gas or value
+		for k := range options {
+			return nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCInvalidOption, k, "unsupported option")
+		}
+	}
+	return e.callContractMethod(ctx, ethLocation.Address, signingKey, methodABI, orderedInput, errorsABI)
+}
+
+func (e *EVMRPC) NormalizeContractLocation(ctx context.Context, ntype blockchain.NormalizeType, location *fftypes.JSONAny) (result *fftypes.JSONAny, err error) {
+	parsed, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return e.encodeContractLocation(ctx, parsed)
+}
+
+func (e *EVMRPC) parseContractLocation(ctx context.Context, location *fftypes.JSONAny) (*ethereum.Location, error) {
+	ethLocation := ethereum.Location{}
+	if err := json.Unmarshal(location.Bytes(), &ethLocation); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, err)
+	}
+	if ethLocation.Address == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, "'address' not set")
+	}
+	return &ethLocation, nil
+}
+
+func (e *EVMRPC) encodeContractLocation(ctx context.Context, location *ethereum.Location) (result *fftypes.JSONAny, err error) {
+	location.Address, err = formatEthAddress(ctx, location.Address)
+	if err != nil {
+		return nil, err
+	}
+	normalized, _ := json.Marshal(location)
+	return fftypes.JSONAnyPtrBytes(normalized), nil
+}
+
+func (e *EVMRPC) GetFFIParamValidator(ctx context.Context) (fftypes.FFIParamValidator, error) {
+	return &ffi2abi.ParamValidator{}, nil
+}
+
+func (e *EVMRPC) GenerateEventSignature(ctx context.Context, event *fftypes.FFIEventDefinition) string {
+	eventABI, err := ffi2abi.ConvertFFIEventDefinitionToABI(ctx, event)
+	if err != nil {
+		return ""
+	}
+	return ffi2abi.ABIMethodToSignature(eventABI)
+}
+
+func (e *EVMRPC) GenerateErrorSignature(ctx context.Context, errorDef *fftypes.FFIErrorDefinition) string {
+	errorABI, err := ffi2abi.ConvertFFIErrorDefinitionToABI(ctx, errorDef)
+	if err != nil {
+		return ""
+	}
+	return ffi2abi.ABIMethodToSignature(errorABI)
+}
+
+func (e *EVMRPC) GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
+	var input ethereum.FFIGenerationInput
+	err := json.Unmarshal(generationRequest.Input.Bytes(), &input)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgFFIGenerationFailed, "unable to deserialize JSON as ABI")
+	}
+	if input.ABI == nil || len(*input.ABI) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, "ABI is empty")
+	}
+	return ffi2abi.ConvertABIToFFI(ctx, generationRequest.Namespace, generationRequest.Name, generationRequest.Version, generationRequest.Description, input.ABI)
+}
+
+func (e *EVMRPC) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (version int, err error) {
+	ethLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return 0, err
+	}
+
+	cacheKey := "version:" + ethLocation.Address
+	if cachedValue := e.cache.GetInt(cacheKey); cachedValue != 0 {
+		return cachedValue, nil
+	}
+
+	version, err = e.queryNetworkVersion(ctx, ethLocation.Address)
+	if err == nil {
+		e.cache.SetInt(cacheKey, version)
+	}
+	return version, err
+}
+
+func (e *EVMRPC) queryNetworkVersion(ctx context.Context, address string) (version int, err error) {
+	output, err := e.callContractMethod(ctx, address, "", networkVersionMethodABI, []interface{}{}, nil)
+	if err != nil {
+		// A failed call is interpreted as "method does not exist, default to version 1"
+		if strings.Contains(err.Error(), string(coremsgs.MsgEVMRPCCallReverted)) {
+			return 1, nil
+		}
+		return 0, err
+	}
+	result, ok := output.(map[string]interface{})["output"].(string)
+	if ok {
+		_, err = fmt.Sscan(result, &version)
+	}
+	if !ok || err != nil {
+		return 0, i18n.NewError(ctx, coremsgs.MsgBadNetworkVersion, result)
+	}
+	return version, nil
+}
+
+func (e *EVMRPC) GetAndConvertDeprecatedContractConfig(ctx context.Context) (location *fftypes.JSONAny, fromBlock string, err error) {
+	// There is no deprecated contract config for this plugin - the contract must be configured on the namespace
+	return nil, "", i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "location", "namespaces.predefined[].multiparty.contract[]")
+}
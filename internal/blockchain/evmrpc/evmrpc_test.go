@@ -0,0 +1,747 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var utConfig = config.RootSection("evmrpc_unit_tests")
+
+const testContractAddress = "0x1c197604587f046fd40684a8f21f4609fb811a7b"
+const testSigningKey = "0x2b036ee2d59cc9f0c1fab2c1c5e1e4de45d1f89c"
+
+func testFFIMethod() *fftypes.FFIMethod {
+	return &fftypes.FFIMethod{
+		Name: "sum",
+		Params: []*fftypes.FFIParam{
+			{
+				Name:   "x",
+				Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint256"}}`),
+			},
+			{
+				Name:   "y",
+				Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint256"}}`),
+			},
+		},
+		Returns: []*fftypes.FFIParam{
+			{
+				Name:   "z",
+				Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint256"}}`),
+			},
+		},
+	}
+}
+
+func testFFIPinMethod() *fftypes.FFIMethod {
+	return &fftypes.FFIMethod{
+		Name: "customPin",
+		Params: []*fftypes.FFIParam{
+			{
+				Name:   "data",
+				Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"bytes"}}`),
+			},
+		},
+		Returns: []*fftypes.FFIParam{},
+	}
+}
+
+func testFFIErrors() []*fftypes.FFIError {
+	return []*fftypes.FFIError{{
+		FFIErrorDefinition: fftypes.FFIErrorDefinition{
+			Name: "CustomError1",
+			Params: []*fftypes.FFIParam{
+				{
+					Name:   "x",
+					Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint256"}}`),
+				},
+			},
+		},
+	}}
+}
+
+func testLocation() *fftypes.JSONAny {
+	return fftypes.JSONAnyPtr(fmt.Sprintf(`{"address":"%s"}`, testContractAddress))
+}
+
+func testBatchPin() *blockchain.BatchPin {
+	return &blockchain.BatchPin{
+		TransactionID:   fftypes.NewUUID(),
+		BatchID:         fftypes.NewUUID(),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts:        []*fftypes.Bytes32{fftypes.NewRandB32(), fftypes.NewRandB32()},
+	}
+}
+
+func resetConf(t *testing.T, e *EVMRPC) {
+	coreconfig.Reset()
+	e.InitConfig(utConfig)
+	utConfig.Set(EventsConfigCheckpointFile, filepath.Join(t.TempDir(), "checkpoints.json"))
+}
+
+func newTestEVMRPC(t *testing.T) (*EVMRPC, *devChain, func()) {
+	d := newDevChain(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	mm := &metricsmocks.Manager{}
+	mm.On("IsMetricsEnabled").Return(true)
+	mm.On("BlockchainTransaction", mock.Anything, mock.Anything).Return()
+	mm.On("BlockchainContractDeployment").Return()
+	mm.On("BlockchainQuery", mock.Anything, mock.Anything).Return()
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, d.server.URL)
+	err := e.Init(ctx, cancel, utConfig, mm, cache.NewCacheManager(ctx))
+	assert.NoError(t, err)
+	return e, d, func() {
+		cancel()
+		d.server.Close()
+	}
+}
+
+func newTestOperationHandler(e *EVMRPC) *coremocks.OperationCallbacks {
+	om := &coremocks.OperationCallbacks{}
+	e.SetOperationHandler("ns1", om)
+	return om
+}
+
+func TestInitMissingURL(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "https://localhost:12345")
+	tlsConf := e.rpcConf.SubSection("tls")
+	tlsConf.Set("enabled", true)
+	tlsConf.Set("caFile", "!!!badfile")
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Error(t, err)
+}
+
+func TestInitBadSignerType(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	e.signerConf.Set(SignerConfigType, "wrong")
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10493.*wrong", err)
+}
+
+func TestInitJSONRPCSignerMissingURL(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	e.signerConf.Set(SignerConfigType, "jsonrpc")
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10138.*blockchain.evmrpc.signer", err)
+}
+
+func TestInitJSONRPCSignerBadTLS(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	e.signerConf.Set(SignerConfigType, "jsonrpc")
+	e.signerConf.Set(ffresty.HTTPConfigURL, "https://localhost:12345")
+	tlsConf := e.signerConf.SubSection("tls")
+	tlsConf.Set("enabled", true)
+	tlsConf.Set("caFile", "!!!badfile")
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Error(t, err)
+}
+
+func TestInitJSONRPCSigner(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	e.signerConf.Set(SignerConfigType, "jsonrpc")
+	e.signerConf.Set(ffresty.HTTPConfigURL, "http://localhost:12346")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := e.Init(ctx, cancel, utConfig, &metricsmocks.Manager{}, cache.NewCacheManager(ctx))
+	assert.NoError(t, err)
+	assert.IsType(t, &remoteSigner{}, e.signer)
+	assert.Equal(t, 1.5, e.signer.(*remoteSigner).gasEstimationFactor)
+}
+
+func TestInitMissingCheckpointFile(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(EventsConfigCheckpointFile, "")
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10138.*events.checkpointFile", err)
+}
+
+func TestInitBadCheckpointFile(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoints.json")
+	err := os.WriteFile(checkpointFile, []byte("!json"), 0600)
+	assert.NoError(t, err)
+	utConfig.Set(EventsConfigCheckpointFile, checkpointFile)
+	err = e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10583", err)
+}
+
+func TestInitCacheFail(t *testing.T) {
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	err := e.Init(context.Background(), func() {}, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInitAndStart(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	assert.Equal(t, "evmrpc", e.Name())
+	assert.Equal(t, core.VerifierTypeEthAddress, e.VerifierType())
+	assert.NotNil(t, e.Capabilities())
+	assert.IsType(t, &nodeSigner{}, e.signer)
+	e.SetHandler("ns1", nil)
+	err := e.Start()
+	assert.NoError(t, err)
+}
+
+func TestResolveSigningKey(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	key, err := e.ResolveSigningKey(context.Background(), "0x2B036EE2D59CC9F0C1FAB2C1C5E1E4DE45D1F89C", blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, testSigningKey, key)
+	_, err = e.ResolveSigningKey(context.Background(), "bad", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestApplyOptions(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	tx := &ethTransaction{}
+	err := e.applyOptions(context.Background(), tx, map[string]interface{}{
+		"gas":      100000,
+		"gasPrice": "2000000000",
+		"value":    "0x10",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100000), tx.Gas.BigInt().Int64())
+	assert.Equal(t, int64(2000000000), tx.GasPrice.BigInt().Int64())
+	assert.Equal(t, int64(16), tx.Value.BigInt().Int64())
+}
+
+func TestApplyOptionsUnsupported(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.applyOptions(context.Background(), &ethTransaction{}, map[string]interface{}{"nonce": 1})
+	assert.Regexp(t, "FF10494.*nonce", err)
+}
+
+func TestApplyOptionsBadValue(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.applyOptions(context.Background(), &ethTransaction{}, map[string]interface{}{"gas": "lots"})
+	assert.Regexp(t, "FF10494.*gas", err)
+}
+
+func TestInvokeContract(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	opID := fftypes.NewUUID()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+opID.String() &&
+			update.Status == core.OpStatusPending &&
+			update.Output.GetString("transactionHash") == fmt.Sprintf("0x%064x", 1)
+	})).Return()
+
+	err := e.InvokeContract(context.Background(), "ns1:"+opID.String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, map[string]interface{}{"gas": 50000}, nil)
+	assert.NoError(t, err)
+
+	txs := d.transactions()
+	assert.Len(t, txs, 1)
+	assert.Equal(t, testSigningKey, txs[0].From)
+	assert.Equal(t, testContractAddress, txs[0].To)
+	assert.Equal(t, int64(50000), txs[0].Gas.BigInt().Int64())
+	methodABI := &abi.Entry{Type: "function", Name: "sum", Inputs: abi.ParameterArray{{Type: "uint256"}, {Type: "uint256"}}}
+	expected, _ := methodABI.EncodeCallDataValues([]interface{}{1, 2})
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(expected), txs[0].Data)
+	assert.Contains(t, e.pendingTransactions, fmt.Sprintf("0x%064x", 1))
+	om.AssertExpectations(t)
+}
+
+func TestInvokeContractWithBatchPin(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+
+	batch := testBatchPin()
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIPinMethod(),
+		map[string]interface{}{"data": ""}, nil, nil, batch)
+	assert.NoError(t, err)
+
+	txs := d.transactions()
+	assert.Len(t, txs, 1)
+	pinABI := &abi.Entry{Type: "function", Name: "customPin", Inputs: abi.ParameterArray{{Name: "data", Type: "bytes"}}}
+	cv, err := pinABI.DecodeCallData(txs[0].Data)
+	assert.NoError(t, err)
+	pinData := cv.Children[0].Value.([]byte)
+	pinCV, err := batchPinMethodABI.Inputs.DecodeABIData(pinData, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, batch.BatchPayloadRef, pinCV.Children[2].Value)
+	assert.Equal(t, batch.BatchHash[:], pinCV.Children[1].Value)
+}
+
+func TestInvokeContractBatchPinNotSupported(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, nil, testBatchPin())
+	assert.Regexp(t, "FF10443", err)
+}
+
+func TestInvokeContractBadLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, fftypes.JSONAnyPtr(`{}`), testFFIMethod(),
+		map[string]interface{}{}, nil, nil, nil)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestInvokeContractBadMethod(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	method := &fftypes.FFIMethod{
+		Name:   "bad",
+		Params: []*fftypes.FFIParam{{Name: "x", Schema: fftypes.JSONAnyPtr(`{"type":"wrong"}`)}},
+	}
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), method,
+		map[string]interface{}{}, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestInvokeContractBadInput(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": "not a number", "y": 2}, nil, nil, nil)
+	assert.Regexp(t, "FF10311", err)
+}
+
+func TestInvokeContractBadOption(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, map[string]interface{}{"privateFor": []string{}}, nil)
+	assert.Regexp(t, "FF10494", err)
+}
+
+func TestInvokeContractSendFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_sendTransaction", &rpcbackend.RPCError{Code: -32000, Message: "insufficient funds"})
+	err := e.InvokeContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, nil, nil)
+	assert.Regexp(t, "FF10491.*insufficient funds", err)
+}
+
+func TestValidateInvokeRequest(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.ValidateInvokeRequest(context.Background(), testFFIPinMethod(), nil, nil, true)
+	assert.NoError(t, err)
+	err = e.ValidateInvokeRequest(context.Background(), testFFIMethod(), nil, nil, true)
+	assert.Regexp(t, "FF10443", err)
+	err = e.ValidateInvokeRequest(context.Background(), testFFIMethod(), nil, testFFIErrors(), false)
+	assert.NoError(t, err)
+}
+
+func TestQueryContract(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	sumABI := &abi.Entry{Type: "function", Name: "sum", Inputs: abi.ParameterArray{{Type: "uint256"}, {Type: "uint256"}}, Outputs: abi.ParameterArray{{Name: "z", Type: "uint256"}}}
+	d.setCall(t, sumABI, 3)
+	result, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, nil)
+	assert.NoError(t, err)
+	b, _ := json.Marshal(result)
+	assert.JSONEq(t, `{"z":"3"}`, string(b))
+}
+
+func TestQueryContractUnnamedOutputs(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	method := &fftypes.FFIMethod{
+		Name: "get",
+		Returns: []*fftypes.FFIParam{
+			{Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"bytes32"}}`)},
+			{Schema: fftypes.JSONAnyPtr(`{"type":"boolean","details":{"type":"bool"}}`)},
+		},
+	}
+	getABI := &abi.Entry{Type: "function", Name: "get", Outputs: abi.ParameterArray{{Type: "bytes32"}, {Type: "bool"}}}
+	hash := fftypes.NewRandB32()
+	d.setCall(t, getABI, ethHexFormatB32(hash), true)
+	result, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), method, map[string]interface{}{}, nil, nil)
+	assert.NoError(t, err)
+	b, _ := json.Marshal(result)
+	assert.JSONEq(t, fmt.Sprintf(`{"output":"%s","output1":true}`, ethHexFormatB32(hash)), string(b))
+}
+
+func TestQueryContractBadOutput(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.mux.Lock()
+	d.calls["0xcad0899b"] = "0x1234"
+	d.mux.Unlock()
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, nil)
+	assert.Regexp(t, "FF10491", err)
+}
+
+func TestQueryContractRevertReason(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	sumABI := &abi.Entry{Type: "function", Name: "sum", Inputs: abi.ParameterArray{{Type: "uint256"}, {Type: "uint256"}}}
+	revertData, _ := revertReasonABI.EncodeCallDataValues([]interface{}{"overflow"})
+	d.setRevert(sumABI, &rpcbackend.RPCError{
+		Code:    3,
+		Message: "execution reverted: overflow",
+		Data:    *fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, ethtypes.HexBytes0xPrefix(revertData))),
+	})
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, nil)
+	assert.Regexp(t, "FF10499.*overflow", err)
+}
+
+func TestQueryContractCustomError(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	sumABI := &abi.Entry{Type: "function", Name: "sum", Inputs: abi.ParameterArray{{Type: "uint256"}, {Type: "uint256"}}}
+	customABI := &abi.Entry{Type: "error", Name: "CustomError1", Inputs: abi.ParameterArray{{Name: "x", Type: "uint256"}}}
+	revertData, _ := customABI.EncodeCallDataValues([]interface{}{42})
+	d.setRevert(sumABI, &rpcbackend.RPCError{
+		Code:    3,
+		Message: "execution reverted",
+		Data:    *fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, ethtypes.HexBytes0xPrefix(revertData))),
+	})
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, testFFIErrors(), nil)
+	assert.Regexp(t, `FF10499.*CustomError1.*"x":"42"`, err)
+}
+
+func TestQueryContractUnknownRevertData(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	sumABI := &abi.Entry{Type: "function", Name: "sum", Inputs: abi.ParameterArray{{Type: "uint256"}, {Type: "uint256"}}}
+	d.setRevert(sumABI, &rpcbackend.RPCError{
+		Code:    3,
+		Message: "execution failed",
+		Data:    *fftypes.JSONAnyPtr(`"0x08c379a0"`),
+	})
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, nil)
+	assert.Regexp(t, "FF10499.*execution failed", err)
+}
+
+func TestQueryContractRPCError(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_call", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, nil)
+	assert.Regexp(t, "FF10491.*pop", err)
+}
+
+func TestQueryContractBadLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, err := e.QueryContract(context.Background(), testSigningKey, fftypes.JSONAnyPtr(`{}`), testFFIMethod(), map[string]interface{}{}, nil, nil)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestQueryContractBadMethod(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	method := &fftypes.FFIMethod{
+		Name:   "bad",
+		Params: []*fftypes.FFIParam{{Name: "x", Schema: fftypes.JSONAnyPtr(`{"type":"wrong"}`)}},
+	}
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), method, map[string]interface{}{}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestQueryContractBadInput(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": "bad"}, nil, nil)
+	assert.Regexp(t, "FF10311", err)
+}
+
+func TestQueryContractOptions(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, err := e.QueryContract(context.Background(), testSigningKey, testLocation(), testFFIMethod(), map[string]interface{}{"x": 1, "y": 2}, nil, map[string]interface{}{"blockNumber": "1"})
+	assert.Regexp(t, "FF10494.*blockNumber", err)
+}
+
+func TestDeployContract(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+
+	definition := fftypes.JSONAnyPtr(`[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`)
+	contract := fftypes.JSONAnyPtr(`"0x6080604052"`)
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, definition, contract, []interface{}{1000}, nil)
+	assert.NoError(t, err)
+
+	txs := d.transactions()
+	assert.Len(t, txs, 1)
+	assert.Empty(t, txs[0].To)
+	assert.Equal(t, "6080604052"+"00000000000000000000000000000000000000000000000000000000000003e8", hex.EncodeToString(txs[0].Data))
+}
+
+func TestDeployContractNoConstructor(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, fftypes.JSONAnyPtr(`[]`), fftypes.JSONAnyPtr(`"0x6080604052"`), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "6080604052", hex.EncodeToString(d.transactions()[0].Data))
+}
+
+func TestDeployContractBadABI(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, fftypes.JSONAnyPtr(`{}`), fftypes.JSONAnyPtr(`"0x6080604052"`), nil, nil)
+	assert.Regexp(t, "FF10496", err)
+}
+
+func TestDeployContractBadBytecode(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, fftypes.JSONAnyPtr(`[]`), fftypes.JSONAnyPtr(`"not hex"`), nil, nil)
+	assert.Regexp(t, "FF10496", err)
+}
+
+func TestDeployContractBadInput(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	definition := fftypes.JSONAnyPtr(`[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`)
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, definition, fftypes.JSONAnyPtr(`"0x6080604052"`), []interface{}{"bad"}, nil)
+	assert.Regexp(t, "FF10311", err)
+}
+
+func TestSubmitBatchPinV1(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	d.setRevert(networkVersionMethodABI, &rpcbackend.RPCError{Code: 3, Message: "execution reverted"})
+
+	err := e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "ns1", testSigningKey, testBatchPin(), testLocation())
+	assert.NoError(t, err)
+	txs := d.transactions()
+	assert.Len(t, txs, 1)
+	assert.Equal(t, batchPinMethodABIV1.FunctionSelectorBytes(), ethtypes.HexBytes0xPrefix(txs[0].Data[0:4]))
+}
+
+func TestSubmitBatchPinBadLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "ns1", testSigningKey, testBatchPin(), fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSubmitBatchPinVersionFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_call", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	err := e.SubmitBatchPin(context.Background(), "ns1:"+fftypes.NewUUID().String(), "ns1", testSigningKey, testBatchPin(), testLocation())
+	assert.Regexp(t, "FF10491", err)
+}
+
+func TestSubmitNetworkActionV1(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	d.setRevert(networkVersionMethodABI, &rpcbackend.RPCError{Code: 3, Message: "execution reverted"})
+
+	err := e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionTerminate, "", testLocation())
+	assert.NoError(t, err)
+	txs := d.transactions()
+	cv, err := batchPinMethodABIV1.DecodeCallData(txs[0].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, "firefly:terminate", cv.Children[0].Value)
+}
+
+func TestSubmitNetworkActionV2(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	d.setCall(t, networkVersionMethodABI, 2)
+
+	err := e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionTerminate, "", testLocation())
+	assert.NoError(t, err)
+	txs := d.transactions()
+	cv, err := networkActionMethodABI.DecodeCallData(txs[0].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, "firefly:terminate", cv.Children[0].Value)
+}
+
+func TestSubmitNetworkActionV2Payload(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+	d.setCall(t, networkVersionMethodABI, 2)
+
+	err := e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionMigrate, `{"location":{"address":"0x456"}}`, testLocation())
+	assert.NoError(t, err)
+	txs := d.transactions()
+	cv, err := networkActionMethodABI.DecodeCallData(txs[0].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, "firefly:migrate", cv.Children[0].Value)
+	assert.Equal(t, `{"location":{"address":"0x456"}}`, cv.Children[1].Value)
+}
+
+func TestSubmitNetworkActionBadLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	err := e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionTerminate, "", fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSubmitNetworkActionVersionFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_call", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	err := e.SubmitNetworkAction(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey, core.NetworkActionTerminate, "", testLocation())
+	assert.Regexp(t, "FF10491", err)
+}
+
+func TestGetNetworkVersionCached(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setCall(t, networkVersionMethodABI, 2)
+	version, err := e.GetNetworkVersion(context.Background(), testLocation())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	d.setFailure("eth_call", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	version, err = e.GetNetworkVersion(context.Background(), testLocation())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+}
+
+func TestGetNetworkVersionBadLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, err := e.GetNetworkVersion(context.Background(), fftypes.JSONAnyPtr(`bad`))
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestQueryNetworkVersionBadOutput(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.mux.Lock()
+	d.calls[networkVersionMethodABI.FunctionSelectorBytes().String()] = "0x1234"
+	d.mux.Unlock()
+	_, err := e.queryNetworkVersion(context.Background(), testContractAddress)
+	assert.Regexp(t, "FF10491", err)
+}
+
+func TestNormalizeContractLocation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	result, err := e.NormalizeContractLocation(context.Background(), blockchain.NormalizeListener, fftypes.JSONAnyPtr(`{"address":"0x1C197604587F046FD40684A8F21F4609FB811A7B"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"address":"%s"}`, testContractAddress), result.String())
+
+	_, err = e.NormalizeContractLocation(context.Background(), blockchain.NormalizeListener, fftypes.JSONAnyPtr(`{"address":"bad"}`))
+	assert.Regexp(t, "FF10141", err)
+	_, err = e.NormalizeContractLocation(context.Background(), blockchain.NormalizeListener, fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestGenerateFFI(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	ffi, err := e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{
+		Name:    "Simple",
+		Version: "v0.0.1",
+		Input:   fftypes.JSONAnyPtr(`{"abi":[{"name":"sum","type":"function","inputs":[{"name":"x","type":"uint256"}],"outputs":[{"name":"z","type":"uint256"}]}]}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", ffi.Methods[0].Name)
+
+	_, err = e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{Input: fftypes.JSONAnyPtr(`{"abi":[]}`)})
+	assert.Regexp(t, "FF10346", err)
+	_, err = e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{Input: fftypes.JSONAnyPtr(`{"abi":"bad"}`)})
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestGenerateSignatures(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	params := fftypes.FFIParams{{Name: "x", Schema: fftypes.JSONAnyPtr(`{"type":"integer","details":{"type":"uint256"}}`)}}
+	assert.Equal(t, "Changed(uint256)", e.GenerateEventSignature(context.Background(), &fftypes.FFIEventDefinition{Name: "Changed", Params: params}))
+	assert.Equal(t, "Failed(uint256)", e.GenerateErrorSignature(context.Background(), &fftypes.FFIErrorDefinition{Name: "Failed", Params: params}))
+
+	badParams := fftypes.FFIParams{{Name: "x", Schema: fftypes.JSONAnyPtr(`{"type":"wrong"}`)}}
+	assert.Empty(t, e.GenerateEventSignature(context.Background(), &fftypes.FFIEventDefinition{Name: "Changed", Params: badParams}))
+	assert.Empty(t, e.GenerateErrorSignature(context.Background(), &fftypes.FFIErrorDefinition{Name: "Failed", Params: badParams}))
+
+	validator, err := e.GetFFIParamValidator(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, validator)
+}
+
+func TestGetAndConvertDeprecatedContractConfig(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	_, _, err := e.GetAndConvertDeprecatedContractConfig(context.Background())
+	assert.Regexp(t, "FF10138", err)
+}
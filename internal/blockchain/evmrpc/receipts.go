@@ -0,0 +1,195 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	txStatusPending   = "Pending"
+	txStatusSucceeded = "Succeeded"
+	txStatusFailed    = "Failed"
+)
+
+type pendingTransaction struct {
+	nsOpID string
+	txHash string
+}
+
+type txReceipt struct {
+	TransactionHash  string                 `json:"transactionHash"`
+	BlockHash        string                 `json:"blockHash"`
+	BlockNumber      *ethtypes.HexInteger   `json:"blockNumber"`
+	TransactionIndex *ethtypes.HexInteger   `json:"transactionIndex"`
+	Status           *ethtypes.HexInteger   `json:"status,omitempty"`
+	GasUsed          *ethtypes.HexInteger   `json:"gasUsed,omitempty"`
+	ContractAddress  *ethtypes.Address0xHex `json:"contractAddress,omitempty"`
+}
+
+// addPendingTransaction records a submitted transaction, so that its receipt is polled until it is mined
+func (e *EVMRPC) addPendingTransaction(ctx context.Context, nsOpID, txHash string) {
+	e.receiptsMutex.Lock()
+	e.pendingTransactions[txHash] = &pendingTransaction{nsOpID: nsOpID, txHash: txHash}
+	e.receiptsMutex.Unlock()
+
+	// The transaction hash is stored in the output of the operation, so the status can still be queried after a restart
+	_ = common.HandleReceipt(ctx, e, &common.BlockchainReceiptNotification{
+		Headers: common.BlockchainReceiptHeaders{
+			ReceiptID: nsOpID,
+			ReplyType: "TransactionUpdate",
+		},
+		TxHash: txHash,
+	}, e.callbacks)
+}
+
+func (e *EVMRPC) removePendingTransaction(txHash string) {
+	e.receiptsMutex.Lock()
+	defer e.receiptsMutex.Unlock()
+	delete(e.pendingTransactions, txHash)
+}
+
+func (e *EVMRPC) getReceipt(ctx context.Context, txHash string) (receipt *txReceipt, err error) {
+	if rpcErr := e.rpc.CallRPC(ctx, &receipt, "eth_getTransactionReceipt", txHash); rpcErr != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	return receipt, nil
+}
+
+// receiptNotification builds the notification for a receipt, or returns nil if the transaction
+// has not been mined, or does not yet have the required number of confirmations
+func (e *EVMRPC) receiptNotification(ctx context.Context, nsOpID string, receipt *txReceipt, head int64) *common.BlockchainReceiptNotification {
+	if receipt == nil || receipt.BlockNumber == nil {
+		return nil
+	}
+	blockNumber := receipt.BlockNumber.BigInt().Int64()
+	if blockNumber > head-e.confirmations {
+		return nil
+	}
+	notification := &common.BlockchainReceiptNotification{
+		Headers: common.BlockchainReceiptHeaders{
+			ReceiptID: nsOpID,
+			ReplyType: ethereum.ReceiptTransactionSuccess,
+		},
+		TxHash:     receipt.TransactionHash,
+		ProtocolID: fmt.Sprintf("%.12d/%.6d", blockNumber, receipt.TransactionIndex.BigInt().Int64()),
+	}
+	// Receipts from before the Byzantium fork do not have a status
+	if receipt.Status != nil && receipt.Status.BigInt().Sign() == 0 {
+		notification.Headers.ReplyType = ethereum.ReceiptTransactionFailed
+		notification.Message = i18n.NewError(ctx, coremsgs.MsgEVMRPCTransactionReverted, receipt.TransactionHash, blockNumber).Error()
+	}
+	if receipt.ContractAddress != nil {
+		notification.ContractLocation = fftypes.JSONAnyPtr(fmt.Sprintf(`{"address":"%s"}`, receipt.ContractAddress))
+	}
+	return notification
+}
+
+func (e *EVMRPC) checkReceipts(ctx context.Context) {
+	e.receiptsMutex.Lock()
+	pending := make([]*pendingTransaction, 0, len(e.pendingTransactions))
+	for _, tx := range e.pendingTransactions {
+		pending = append(pending, tx)
+	}
+	e.receiptsMutex.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	head, err := e.blockNumber(ctx)
+	if err != nil {
+		log.L(ctx).Errorf("Failed to query block number for receipts: %s", err)
+		return
+	}
+	for _, tx := range pending {
+		receipt, err := e.getReceipt(ctx, tx.txHash)
+		if err != nil {
+			log.L(ctx).Errorf("Failed to query receipt for transaction %s: %s", tx.txHash, err)
+			continue
+		}
+		if notification := e.receiptNotification(ctx, tx.nsOpID, receipt, head); notification != nil {
+			_ = common.HandleReceipt(ctx, e, notification, e.callbacks)
+			e.removePendingTransaction(tx.txHash)
+		}
+	}
+}
+
+func (e *EVMRPC) receiptLoop() {
+	l := log.L(e.ctx).WithField("role", "receipt-loop")
+	ctx := log.WithLogger(e.ctx, l)
+	ticker := time.NewTicker(e.receiptsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			l.Debugf("Receipt loop exiting")
+			return
+		case <-ticker.C:
+			e.checkReceipts(ctx)
+		}
+	}
+}
+
+// GetTransactionStatus returns the receipt for the transaction submitted by an operation, and
+// resolves the operation if it is still pending - for example if FireFly restarted before the receipt was processed
+func (e *EVMRPC) GetTransactionStatus(ctx context.Context, operation *core.Operation) (interface{}, error) {
+	txHash := operation.Output.GetString("transactionHash")
+	if txHash == "" {
+		return nil, nil
+	}
+	receipt, err := e.getReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	status := fftypes.JSONObject{
+		"transactionHash": txHash,
+		"status":          txStatusPending,
+	}
+	if receipt == nil {
+		return status, nil
+	}
+	status["receipt"] = receipt
+
+	head, err := e.blockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nsOpID := (&core.PreparedOperation{ID: operation.ID, Namespace: operation.Namespace}).NamespacedIDString()
+	if notification := e.receiptNotification(ctx, nsOpID, receipt, head); notification != nil {
+		status["status"] = txStatusSucceeded
+		if notification.Headers.ReplyType == ethereum.ReceiptTransactionFailed {
+			status["status"] = txStatusFailed
+			status["errorMessage"] = notification.Message
+		}
+		if operation.Status == core.OpStatusPending || operation.Status == core.OpStatusInitialized {
+			_ = common.HandleReceipt(ctx, e, notification, e.callbacks)
+			e.removePendingTransaction(txHash)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,301 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func submitTestTransaction(t *testing.T, e *EVMRPC) (nsOpID, txHash string) {
+	nsOpID = "ns1:" + fftypes.NewUUID().String()
+	err := e.InvokeContract(context.Background(), nsOpID, testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, nil, nil)
+	assert.NoError(t, err)
+	for txHash = range e.pendingTransactions {
+	}
+	return nsOpID, txHash
+}
+
+func TestReceiptSuccess(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+
+	nsOpID, txHash := submitTestTransaction(t, e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == nsOpID &&
+			update.Status == core.OpStatusSucceeded &&
+			update.BlockchainTXID == txHash &&
+			update.Output.GetString("protocolId") == "000000000001/000000"
+	})).Return().Once()
+
+	e.checkReceipts(context.Background())
+	assert.Empty(t, e.pendingTransactions)
+	om.AssertExpectations(t)
+
+	// Nothing to do once all receipts are processed
+	e.checkReceipts(context.Background())
+}
+
+func TestReceiptReverted(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.onTransaction = func(tx *ethTransaction) (int64, []*devLog) { return 0, nil }
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+
+	_, txHash := submitTestTransaction(t, e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusFailed &&
+			update.ErrorMessage == fmt.Sprintf("FF10498: Transaction %s reverted in block 1", txHash)
+	})).Return().Once()
+
+	e.checkReceipts(context.Background())
+	om.AssertExpectations(t)
+}
+
+func TestReceiptContractDeployment(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		location := update.Output.GetObject("contractLocation")
+		return update.Status == core.OpStatusSucceeded &&
+			location.GetString("address") == fmt.Sprintf("0x%040x", 1)
+	})).Return().Once()
+
+	err := e.DeployContract(context.Background(), "ns1:"+fftypes.NewUUID().String(), testSigningKey,
+		fftypes.JSONAnyPtr(`[]`), fftypes.JSONAnyPtr(`"0x6080604052"`), nil, nil)
+	assert.NoError(t, err)
+	e.checkReceipts(context.Background())
+	om.AssertExpectations(t)
+}
+
+func TestReceiptWaitsForConfirmations(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	e.confirmations = 2
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+
+	submitTestTransaction(t, e)
+	e.checkReceipts(context.Background())
+	assert.Len(t, e.pendingTransactions, 1)
+
+	d.mineBlocks(1)
+	e.checkReceipts(context.Background())
+	assert.Len(t, e.pendingTransactions, 1)
+
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusSucceeded
+	})).Return().Once()
+	d.mineBlocks(1)
+	e.checkReceipts(context.Background())
+	assert.Empty(t, e.pendingTransactions)
+	om.AssertExpectations(t)
+}
+
+func TestReceiptNotMined(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+
+	_, txHash := submitTestTransaction(t, e)
+	d.mux.Lock()
+	delete(d.receipts, txHash)
+	d.mux.Unlock()
+	e.checkReceipts(context.Background())
+	assert.Len(t, e.pendingTransactions, 1)
+	om.AssertExpectations(t)
+}
+
+func TestReceiptBlockNumberFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+
+	submitTestTransaction(t, e)
+	d.setFailure("eth_blockNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	e.checkReceipts(context.Background())
+	assert.Len(t, e.pendingTransactions, 1)
+}
+
+func TestReceiptQueryFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+
+	submitTestTransaction(t, e)
+	d.setFailure("eth_getTransactionReceipt", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	e.checkReceipts(context.Background())
+	assert.Len(t, e.pendingTransactions, 1)
+}
+
+func TestReceiptLoop(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	e.receiptsInterval = time.Millisecond
+	om := newTestOperationHandler(e)
+	succeeded := make(chan struct{})
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusSucceeded
+	})).Run(func(args mock.Arguments) {
+		close(succeeded)
+	}).Return().Once()
+
+	submitTestTransaction(t, e)
+	loopDone := make(chan struct{})
+	go func() {
+		e.receiptLoop()
+		close(loopDone)
+	}()
+	<-succeeded
+	e.cancelCtx()
+	<-loopDone
+}
+
+func TestGetTransactionStatusNoHash(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	status, err := e.GetTransactionStatus(context.Background(), &core.Operation{})
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestGetTransactionStatusPending(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	status, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		Output: fftypes.JSONObject{"transactionHash": "0xabcd"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pending", status.(fftypes.JSONObject)["status"])
+}
+
+func TestGetTransactionStatusResolvesOperation(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.Status == core.OpStatusPending
+	})).Return().Once()
+	nsOpID, txHash := submitTestTransaction(t, e)
+
+	// Simulate a restart, where the pending transaction is no longer tracked in memory
+	e.removePendingTransaction(txHash)
+	om.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == nsOpID && update.Status == core.OpStatusSucceeded
+	})).Return().Once()
+
+	opID, _ := fftypes.ParseUUID(context.Background(), nsOpID[4:])
+	status, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		ID:        opID,
+		Namespace: "ns1",
+		Status:    core.OpStatusPending,
+		Output:    fftypes.JSONObject{"transactionHash": txHash},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Succeeded", status.(fftypes.JSONObject)["status"])
+	assert.NotNil(t, status.(fftypes.JSONObject)["receipt"])
+	om.AssertExpectations(t)
+}
+
+func TestGetTransactionStatusFailedAlreadyResolved(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.onTransaction = func(tx *ethTransaction) (int64, []*devLog) { return 0, nil }
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+	_, txHash := submitTestTransaction(t, e)
+
+	status, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Status:    core.OpStatusFailed,
+		Output:    fftypes.JSONObject{"transactionHash": txHash},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Failed", status.(fftypes.JSONObject)["status"])
+	assert.Regexp(t, "FF10498", status.(fftypes.JSONObject)["errorMessage"])
+	om.AssertExpectations(t)
+}
+
+func TestGetTransactionStatusAwaitingConfirmations(t *testing.T) {
+	e, _, done := newTestEVMRPC(t)
+	defer done()
+	e.confirmations = 5
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+	_, txHash := submitTestTransaction(t, e)
+
+	status, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		Status: core.OpStatusPending,
+		Output: fftypes.JSONObject{"transactionHash": txHash},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pending", status.(fftypes.JSONObject)["status"])
+	om.AssertExpectations(t)
+}
+
+func TestGetTransactionStatusReceiptFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	d.setFailure("eth_getTransactionReceipt", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	_, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		Output: fftypes.JSONObject{"transactionHash": "0xabcd"},
+	})
+	assert.Regexp(t, "FF10491.*pop", err)
+}
+
+func TestGetTransactionStatusBlockNumberFail(t *testing.T) {
+	e, d, done := newTestEVMRPC(t)
+	defer done()
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return().Once()
+	_, txHash := submitTestTransaction(t, e)
+
+	d.setFailure("eth_blockNumber", &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+	_, err := e.GetTransactionStatus(context.Background(), &core.Operation{
+		Output: fftypes.JSONObject{"transactionHash": txHash},
+	})
+	assert.Regexp(t, "FF10491.*pop", err)
+}
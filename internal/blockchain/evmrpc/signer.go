@@ -0,0 +1,121 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// transactionSigner signs a transaction with the key in its "from" address, and submits it to the chain
+type transactionSigner interface {
+	sendTransaction(ctx context.Context, tx *ethTransaction) (txHash string, err error)
+}
+
+// nodeSigner submits transactions with eth_sendTransaction, for the node to sign with the keys it manages.
+// This is also the mode to use with a signing proxy in front of the node, such as FireFly EthSigner.
+type nodeSigner struct {
+	rpc rpcbackend.Backend
+}
+
+// remoteSigner fills in the nonce, gas and chain ID of a transaction from the node, has it signed with
+// eth_signTransaction by a separate JSON-RPC signer, and then submits the raw signed transaction to the node
+type remoteSigner struct {
+	signer              rpcbackend.Backend
+	node                rpcbackend.Backend
+	gasEstimationFactor float64
+	// nonces are allocated from the pending transaction count, so transactions are submitted one at a time
+	mux sync.Mutex
+}
+
+// signedTransaction is the result of eth_signTransaction from signers that return the decoded transaction, as well as the raw bytes
+type signedTransaction struct {
+	Raw ethtypes.HexBytes0xPrefix `json:"raw"`
+}
+
+func (s *nodeSigner) sendTransaction(ctx context.Context, tx *ethTransaction) (string, error) {
+	var txHash string
+	if rpcErr := s.rpc.CallRPC(ctx, &txHash, "eth_sendTransaction", tx); rpcErr != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	return txHash, nil
+}
+
+func (s *remoteSigner) callNode(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if rpcErr := s.node.CallRPC(ctx, result, method, params...); rpcErr != nil {
+		return i18n.NewError(ctx, coremsgs.MsgEVMRPCErr, rpcErr.Message)
+	}
+	return nil
+}
+
+func (s *remoteSigner) fillTransaction(ctx context.Context, tx *ethTransaction) error {
+	if tx.Gas == nil {
+		var estimate ethtypes.HexInteger
+		if err := s.callNode(ctx, &estimate, "eth_estimateGas", tx); err != nil {
+			return err
+		}
+		gas, _ := new(big.Float).Mul(new(big.Float).SetInt(estimate.BigInt()), big.NewFloat(s.gasEstimationFactor)).Int(nil)
+		tx.Gas = ethtypes.NewHexInteger(gas)
+	}
+	if tx.GasPrice == nil {
+		tx.GasPrice = new(ethtypes.HexInteger)
+		if err := s.callNode(ctx, tx.GasPrice, "eth_gasPrice"); err != nil {
+			return err
+		}
+	}
+	tx.ChainID = new(ethtypes.HexInteger)
+	if err := s.callNode(ctx, tx.ChainID, "eth_chainId"); err != nil {
+		return err
+	}
+	tx.Nonce = new(ethtypes.HexInteger)
+	return s.callNode(ctx, tx.Nonce, "eth_getTransactionCount", tx.From, "pending")
+}
+
+func (s *remoteSigner) sendTransaction(ctx context.Context, tx *ethTransaction) (string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if err := s.fillTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+
+	var signed json.RawMessage
+	if rpcErr := s.signer.CallRPC(ctx, &signed, "eth_signTransaction", tx); rpcErr != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgEVMRPCSignerErr, rpcErr.Message)
+	}
+	// Signers either return the raw transaction as a hex string, or an object containing it
+	var raw ethtypes.HexBytes0xPrefix
+	if err := json.Unmarshal(signed, &raw); err != nil {
+		var result signedTransaction
+		_ = json.Unmarshal(signed, &result)
+		raw = result.Raw
+	}
+	if len(raw) == 0 {
+		return "", i18n.NewError(ctx, coremsgs.MsgEVMRPCSignerErr, string(signed))
+	}
+
+	var txHash string
+	err := s.callNode(ctx, &txHash, "eth_sendRawTransaction", raw)
+	return txHash, err
+}
@@ -0,0 +1,139 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmrpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRemoteSigner(t *testing.T) (*remoteSigner, *devChain, *devSigner, func()) {
+	d := newDevChain(t)
+	ds := newDevSigner(t)
+	s := &remoteSigner{
+		signer:              rpcbackend.NewRPCClient(resty.New().SetBaseURL(ds.server.URL)),
+		node:                rpcbackend.NewRPCClient(resty.New().SetBaseURL(d.server.URL)),
+		gasEstimationFactor: 1.5,
+	}
+	return s, d, ds, func() {
+		d.server.Close()
+		ds.server.Close()
+	}
+}
+
+func TestRemoteSignerSendTransaction(t *testing.T) {
+	s, d, ds, done := newTestRemoteSigner(t)
+	defer done()
+
+	txHash, err := s.sendTransaction(context.Background(), &ethTransaction{
+		From: testSigningKey,
+		To:   testContractAddress,
+		Data: ethtypes.MustNewHexBytes0xPrefix("0x12345678"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("0x%064x", 1), txHash)
+
+	assert.Len(t, ds.txs, 1)
+	signed := ds.txs[0]
+	assert.Equal(t, int64(31500), signed.Gas.BigInt().Int64())
+	assert.Equal(t, int64(1000000000), signed.GasPrice.BigInt().Int64())
+	assert.Equal(t, int64(1337), signed.ChainID.BigInt().Int64())
+	assert.Equal(t, int64(0), signed.Nonce.BigInt().Int64())
+	assert.Equal(t, signed, d.transactions()[0])
+}
+
+func TestRemoteSignerSendTransactionRawObject(t *testing.T) {
+	s, d, ds, done := newTestRemoteSigner(t)
+	defer done()
+	ds.rawField = true
+
+	tx := &ethTransaction{
+		From:     testSigningKey,
+		To:       testContractAddress,
+		Gas:      ethtypes.NewHexInteger64(100000),
+		GasPrice: ethtypes.NewHexInteger64(0),
+	}
+	_, err := s.sendTransaction(context.Background(), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100000), d.transactions()[0].Gas.BigInt().Int64())
+	assert.Equal(t, int64(0), d.transactions()[0].GasPrice.BigInt().Int64())
+}
+
+func TestRemoteSignerNodeFailures(t *testing.T) {
+	for _, method := range []string{"eth_estimateGas", "eth_gasPrice", "eth_chainId", "eth_getTransactionCount", "eth_sendRawTransaction"} {
+		s, d, _, done := newTestRemoteSigner(t)
+		d.setFailure(method, &rpcbackend.RPCError{Code: -32000, Message: "pop"})
+		_, err := s.sendTransaction(context.Background(), &ethTransaction{From: testSigningKey})
+		assert.Regexp(t, "FF10491.*pop", err, method)
+		done()
+	}
+}
+
+func TestRemoteSignerSignFail(t *testing.T) {
+	s, _, ds, done := newTestRemoteSigner(t)
+	defer done()
+	ds.failure = &rpcbackend.RPCError{Code: -32000, Message: "unknown account"}
+	_, err := s.sendTransaction(context.Background(), &ethTransaction{From: testSigningKey})
+	assert.Regexp(t, "FF10492.*unknown account", err)
+}
+
+func TestRemoteSignerSignEmpty(t *testing.T) {
+	s, _, ds, done := newTestRemoteSigner(t)
+	defer done()
+	ds.empty = true
+	_, err := s.sendTransaction(context.Background(), &ethTransaction{From: testSigningKey})
+	assert.Regexp(t, "FF10492", err)
+}
+
+func TestInvokeContractJSONRPCSigner(t *testing.T) {
+	d := newDevChain(t)
+	defer d.server.Close()
+	ds := newDevSigner(t)
+	defer ds.server.Close()
+
+	e := &EVMRPC{}
+	resetConf(t, e)
+	e.rpcConf.Set(ffresty.HTTPConfigURL, d.server.URL)
+	e.signerConf.Set(SignerConfigType, "jsonrpc")
+	e.signerConf.Set(ffresty.HTTPConfigURL, ds.server.URL)
+	e.signerConf.Set(SignerConfigGasEstimationFactor, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mm := &metricsmocks.Manager{}
+	mm.On("IsMetricsEnabled").Return(false)
+	err := e.Init(ctx, cancel, utConfig, mm, cache.NewCacheManager(ctx))
+	assert.NoError(t, err)
+	om := newTestOperationHandler(e)
+	om.On("OperationUpdate", mock.Anything).Return()
+
+	err = e.InvokeContract(ctx, "ns1:"+fftypes.NewUUID().String(), testSigningKey, testLocation(), testFFIMethod(),
+		map[string]interface{}{"x": 1, "y": 2}, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42000), ds.txs[0].Gas.BigInt().Int64())
+	assert.Len(t, d.transactions(), 1)
+}
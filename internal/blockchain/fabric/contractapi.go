@@ -0,0 +1,301 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	metadataMethodName     = "org.hyperledger.fabric:GetMetadata"
+	systemContractName     = "org.hyperledger.fabric"
+	componentSchemaRefBase = "#/components/schemas/"
+)
+
+// FFIGenerationInput is the input for generating an FFI from the metadata of a chaincode written with
+// the Fabric contract-api. Either the "metadata" is supplied directly, or it is retrieved by evaluating
+// "org.hyperledger.fabric:GetMetadata" against a deployed "chaincode". Where the chaincode contains
+// multiple contracts, "contract" selects a single one of them.
+type FFIGenerationInput struct {
+	Metadata  *fftypes.JSONAny `json:"metadata,omitempty"`
+	Channel   string           `json:"channel,omitempty"`
+	Chaincode string           `json:"chaincode,omitempty"`
+	Contract  string           `json:"contract,omitempty"`
+}
+
+type contractMetadata struct {
+	Info       *metadataInfo                `json:"info,omitempty"`
+	Contracts  map[string]*metadataContract `json:"contracts"`
+	Components *metadataComponents          `json:"components,omitempty"`
+}
+
+type metadataInfo struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+type metadataComponents struct {
+	Schemas map[string]interface{} `json:"schemas,omitempty"`
+}
+
+type metadataContract struct {
+	Name         string                 `json:"name"`
+	Info         *metadataInfo          `json:"info,omitempty"`
+	Transactions []*metadataTransaction `json:"transactions"`
+}
+
+type metadataTransaction struct {
+	Name       string               `json:"name"`
+	Tag        []string             `json:"tag,omitempty"`
+	Tags       []string             `json:"tags,omitempty"`
+	Parameters []*metadataParameter `json:"parameters,omitempty"`
+	Returns    json.RawMessage      `json:"returns,omitempty"`
+}
+
+type metadataParameter struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+}
+
+func (f *Fabric) GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
+	var input FFIGenerationInput
+	if generationRequest.Input == nil || json.Unmarshal(generationRequest.Input.Bytes(), &input) != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, "unable to deserialize JSON as generation input")
+	}
+	metadata, err := f.getContractMetadata(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.toFFI(ctx, generationRequest, input.Contract)
+}
+
+func (f *Fabric) getContractMetadata(ctx context.Context, input *FFIGenerationInput) (*contractMetadata, error) {
+	var metadataBytes []byte
+	switch {
+	case input.Metadata != nil:
+		metadataBytes = input.Metadata.Bytes()
+	case input.Chaincode != "":
+		channel := input.Channel
+		if channel == "" {
+			channel = f.defaultChannel
+		}
+		res, err := f.queryContractMethod(ctx, channel, input.Chaincode, metadataMethodName, f.signer, "", []*PrefixItem{}, map[string]interface{}{}, nil)
+		if err != nil {
+			return nil, err
+		}
+		output := &fabQueryNamedOutput{}
+		if err = json.Unmarshal(res.Body(), output); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgFFIGenerationFailed, "unable to parse chaincode metadata")
+		}
+		// The metadata may be returned as a JSON string, or already parsed into an object
+		if metadataString, ok := output.Result.(string); ok {
+			metadataBytes = []byte(metadataString)
+		} else {
+			metadataBytes, _ = json.Marshal(output.Result)
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, "either 'metadata' or 'chaincode' must be set")
+	}
+
+	var metadata contractMetadata
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgFFIGenerationFailed, "unable to parse chaincode metadata")
+	}
+	return &metadata, nil
+}
+
+func (m *contractMetadata) selectContracts(ctx context.Context, contractName string) ([]*metadataContract, error) {
+	if contractName != "" {
+		contract, ok := m.Contracts[contractName]
+		if !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, fmt.Sprintf("contract '%s' not found in chaincode metadata", contractName))
+		}
+		return []*metadataContract{contract}, nil
+	}
+	names := make([]string, 0, len(m.Contracts))
+	for name := range m.Contracts {
+		if name != systemContractName {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, "no contracts found in chaincode metadata")
+	}
+	sort.Strings(names)
+	contracts := make([]*metadataContract, len(names))
+	for i, name := range names {
+		contracts[i] = m.Contracts[name]
+	}
+	return contracts, nil
+}
+
+func (m *contractMetadata) toFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest, contractName string) (*fftypes.FFI, error) {
+	contracts, err := m.selectContracts(ctx, contractName)
+	if err != nil {
+		return nil, err
+	}
+
+	ffi := &fftypes.FFI{
+		Namespace:   generationRequest.Namespace,
+		Name:        generationRequest.Name,
+		Description: generationRequest.Description,
+		Version:     generationRequest.Version,
+		Methods:     []*fftypes.FFIMethod{},
+	}
+	info := m.Info
+	if len(contracts) == 1 && contracts[0].Info != nil {
+		info = contracts[0].Info
+	}
+	if info != nil {
+		if ffi.Description == "" {
+			ffi.Description = info.Description
+		}
+		if ffi.Version == "" {
+			ffi.Version = info.Version
+		}
+	}
+
+	// Transactions outside of the default contract of the chaincode must be invoked with the
+	// contract name as a prefix, which we cannot distinguish in the metadata - so the prefix is
+	// used whenever there is more than one contract, or a contract has been explicitly chosen
+	qualified := contractName != "" || len(contracts) > 1
+	for _, contract := range contracts {
+		for _, tx := range contract.Transactions {
+			method, err := m.toFFIMethod(ctx, contract, tx, qualified)
+			if err != nil {
+				return nil, err
+			}
+			ffi.Methods = append(ffi.Methods, method)
+		}
+	}
+	return ffi, nil
+}
+
+func (m *contractMetadata) toFFIMethod(ctx context.Context, contract *metadataContract, tx *metadataTransaction, qualified bool) (*fftypes.FFIMethod, error) {
+	method := &fftypes.FFIMethod{
+		Name:    tx.Name,
+		Params:  fftypes.FFIParams{},
+		Returns: fftypes.FFIParams{},
+	}
+	if qualified {
+		method.Name = fmt.Sprintf("%s:%s", contract.Name, tx.Name)
+	}
+	for _, tag := range append(tx.Tag, tx.Tags...) {
+		if strings.EqualFold(tag, "evaluate") {
+			method.Details = fftypes.JSONObject{"readonly": true}
+		}
+	}
+	for _, param := range tx.Parameters {
+		ffiParam, err := m.toFFIParam(ctx, param.Name, param.Schema)
+		if err != nil {
+			return nil, err
+		}
+		method.Params = append(method.Params, ffiParam)
+	}
+	if len(tx.Returns) > 0 {
+		// Returns are described by a single schema in current versions of the contract-api,
+		// and by a list of named parameters in older versions
+		var returnParams []*metadataParameter
+		if err := json.Unmarshal(tx.Returns, &returnParams); err != nil {
+			var schema interface{}
+			_ = json.Unmarshal(tx.Returns, &schema)
+			returnParams = []*metadataParameter{{Name: "result", Schema: schema}}
+		}
+		for _, param := range returnParams {
+			ffiParam, err := m.toFFIParam(ctx, param.Name, param.Schema)
+			if err != nil {
+				return nil, err
+			}
+			method.Returns = append(method.Returns, ffiParam)
+		}
+	}
+	return method, nil
+}
+
+func (m *contractMetadata) toFFIParam(ctx context.Context, name string, schema interface{}) (*fftypes.FFIParam, error) {
+	resolved, err := m.resolveSchema(ctx, schema, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	schemaBytes, _ := json.Marshal(resolved)
+	return &fftypes.FFIParam{
+		Name:   name,
+		Schema: fftypes.JSONAnyPtrBytes(schemaBytes),
+	}, nil
+}
+
+// resolveSchema returns a copy of a schema, with every reference to a schema under the components
+// of the metadata replaced by the referenced schema - so that each FFI param is self contained
+func (m *contractMetadata) resolveSchema(ctx context.Context, schema interface{}, resolving map[string]bool) (interface{}, error) {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		if ref, ok := s["$ref"].(string); ok {
+			return m.resolveRef(ctx, ref, resolving)
+		}
+		resolved := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			if k == "$id" {
+				continue
+			}
+			rv, err := m.resolveSchema(ctx, v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = rv
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(s))
+		for i, v := range s {
+			rv, err := m.resolveSchema(ctx, v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = rv
+		}
+		return resolved, nil
+	default:
+		return schema, nil
+	}
+}
+
+func (m *contractMetadata) resolveRef(ctx context.Context, ref string, resolving map[string]bool) (interface{}, error) {
+	name := strings.TrimPrefix(ref, componentSchemaRefBase)
+	var component interface{}
+	if m.Components != nil && name != ref {
+		component = m.Components.Schemas[name]
+	}
+	if component == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, fmt.Sprintf("unable to resolve schema reference '%s'", ref))
+	}
+	if resolving[name] {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, fmt.Sprintf("recursive schema reference '%s'", ref))
+	}
+	resolving[name] = true
+	defer delete(resolving, name)
+	return m.resolveSchema(ctx, component, resolving)
+}
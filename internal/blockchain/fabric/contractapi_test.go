@@ -0,0 +1,254 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testContractMetadata = `{
+	"info": {"title": "asset-transfer", "version": "1.0.0"},
+	"contracts": {
+		"AssetTransfer": {
+			"name": "AssetTransfer",
+			"info": {"title": "AssetTransfer", "description": "Manage assets", "version": "1.2.0"},
+			"transactions": [
+				{
+					"name": "CreateAsset",
+					"tag": ["submit", "SUBMIT"],
+					"parameters": [
+						{"name": "id", "schema": {"type": "string"}},
+						{"name": "asset", "schema": {"$ref": "#/components/schemas/Asset"}}
+					]
+				},
+				{
+					"name": "ReadAsset",
+					"tag": ["evaluate", "EVALUATE"],
+					"parameters": [
+						{"name": "id", "schema": {"type": "string"}}
+					],
+					"returns": {"$ref": "#/components/schemas/Asset"}
+				},
+				{
+					"name": "GetAllAssets",
+					"tags": ["EVALUATE"],
+					"returns": [{"name": "assets", "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Asset"}}}]
+				}
+			]
+		},
+		"org.hyperledger.fabric": {
+			"name": "org.hyperledger.fabric",
+			"transactions": [
+				{"name": "GetMetadata", "tag": ["evaluate"]}
+			]
+		}
+	},
+	"components": {
+		"schemas": {
+			"Asset": {
+				"$id": "Asset",
+				"type": "object",
+				"properties": {
+					"id": {"type": "string"},
+					"owner": {"$ref": "#/components/schemas/Owner"}
+				},
+				"required": ["id"]
+			},
+			"Owner": {
+				"$id": "Owner",
+				"type": "object",
+				"properties": {"name": {"type": "string"}}
+			}
+		}
+	}
+}`
+
+func testGenerationRequest(input string) *fftypes.FFIGenerationRequest {
+	return &fftypes.FFIGenerationRequest{
+		Namespace: "ns1",
+		Name:      "assets",
+		Input:     fftypes.JSONAnyPtr(input),
+	}
+}
+
+func TestGenerateFFIFromMetadata(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	ffi, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"metadata":`+testContractMetadata+`}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", ffi.Namespace)
+	assert.Equal(t, "assets", ffi.Name)
+	assert.Equal(t, "1.2.0", ffi.Version)
+	assert.Equal(t, "Manage assets", ffi.Description)
+	assert.Len(t, ffi.Methods, 3)
+
+	create := ffi.Methods[0]
+	assert.Equal(t, "CreateAsset", create.Name)
+	assert.Nil(t, create.Details)
+	assert.Len(t, create.Params, 2)
+	assert.JSONEq(t, `{"type": "string"}`, create.Params[0].Schema.String())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+		},
+		"required": ["id"]
+	}`, create.Params[1].Schema.String())
+	assert.Empty(t, create.Returns)
+
+	read := ffi.Methods[1]
+	assert.Equal(t, "ReadAsset", read.Name)
+	assert.Equal(t, true, read.Details["readonly"])
+	assert.Len(t, read.Returns, 1)
+	assert.Equal(t, "result", read.Returns[0].Name)
+	assert.Equal(t, create.Params[1].Schema.String(), read.Returns[0].Schema.String())
+
+	getAll := ffi.Methods[2]
+	assert.Equal(t, true, getAll.Details["readonly"])
+	assert.Equal(t, "assets", getAll.Returns[0].Name)
+	assert.JSONEq(t, `{"type": "array", "items": `+create.Params[1].Schema.String()+`}`, getAll.Returns[0].Schema.String())
+}
+
+func TestGenerateFFIMultipleContracts(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	metadata := `{
+		"info": {"title": "multi", "description": "Multiple contracts", "version": "2.0.0"},
+		"contracts": {
+			"Second": {"name": "Second", "transactions": [{"name": "Two"}]},
+			"First": {"name": "First", "transactions": [{"name": "One"}]}
+		}
+	}`
+	ffi, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"metadata":`+metadata+`}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", ffi.Version)
+	assert.Equal(t, "Multiple contracts", ffi.Description)
+	assert.Len(t, ffi.Methods, 2)
+	assert.Equal(t, "First:One", ffi.Methods[0].Name)
+	assert.Equal(t, "Second:Two", ffi.Methods[1].Name)
+
+	ffi, err = e.GenerateFFI(context.Background(), testGenerationRequest(`{"contract": "Second", "metadata":`+metadata+`}`))
+	assert.NoError(t, err)
+	assert.Len(t, ffi.Methods, 1)
+	assert.Equal(t, "Second:Two", ffi.Methods[0].Name)
+}
+
+func TestGenerateFFIFromChaincode(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	e.signer = "signer001"
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, "signer001", headers["signer"])
+			assert.Equal(t, "firefly", headers["channel"])
+			assert.Equal(t, "asset_transfer", headers["chaincode"])
+			assert.Equal(t, "org.hyperledger.fabric:GetMetadata", body["func"])
+			return httpmock.NewJsonResponderOrPanic(200, &fabQueryNamedOutput{Result: testContractMetadata})(req)
+		})
+
+	ffi, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"chaincode": "asset_transfer"}`))
+	assert.NoError(t, err)
+	assert.Len(t, ffi.Methods, 3)
+}
+
+func TestGenerateFFIFromChaincodeParsedResult(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "channel2", body["headers"].(map[string]interface{})["channel"])
+			return httpmock.NewJsonResponderOrPanic(200, &fabQueryNamedOutput{Result: fftypes.JSONAnyPtr(testContractMetadata).JSONObject()})(req)
+		})
+
+	ffi, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"channel": "channel2", "chaincode": "asset_transfer", "contract": "AssetTransfer"}`))
+	assert.NoError(t, err)
+	assert.Len(t, ffi.Methods, 3)
+	assert.Equal(t, "AssetTransfer:CreateAsset", ffi.Methods[0].Name)
+}
+
+func TestGenerateFFIFromChaincodeQueryFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewJsonResponderOrPanic(500, &fabError{Error: "pop"}))
+
+	_, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"chaincode": "asset_transfer"}`))
+	assert.Regexp(t, "FF10284.*pop", err)
+}
+
+func TestGenerateFFIFromChaincodeBadResponse(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewStringResponder(200, `[]`))
+
+	_, err := e.GenerateFFI(context.Background(), testGenerationRequest(`{"chaincode": "asset_transfer"}`))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestGenerateFFIErrors(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	for input, errRegexp := range map[string]string{
+		`{}`:                              "FF10346.*metadata",
+		`{"metadata": "!json"}`:           "FF10346.*metadata",
+		`{"metadata": {"contracts": {}}}`: "FF10346.*no contracts",
+		`{"metadata": {"contracts": {"org.hyperledger.fabric": {}}}}`: "FF10346.*no contracts",
+		`{"contract": "Missing", "metadata": {"contracts": {}}}`:      "FF10346.*Missing",
+		`{"metadata": {"contracts": {"c1": {"name": "c1", "transactions": [
+			{"name": "tx1", "parameters": [{"name": "p1", "schema": {"$ref": "#/components/schemas/Missing"}}]}
+		]}}}}`: "FF10346.*Missing",
+		`{"metadata": {"contracts": {"c1": {"name": "c1", "transactions": [
+			{"name": "tx1", "returns": {"$ref": "Other"}}
+		]}}, "components": {"schemas": {"Other": {}}}}}`: "FF10346.*Other",
+		`{"metadata": {"contracts": {"c1": {"name": "c1", "transactions": [
+			{"name": "tx1", "returns": [{"name": "r1", "schema": {"type": "array", "items": [{"$ref": "#/components/schemas/Loop"}]}}]}
+		]}}, "components": {"schemas": {"Loop": {"properties": {"next": {"$ref": "#/components/schemas/Loop"}}}}}}}`: "FF10346.*recursive",
+	} {
+		_, err := e.GenerateFFI(context.Background(), testGenerationRequest(input))
+		assert.Regexp(t, errRegexp, err, input)
+	}
+	_, err := e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{})
+	assert.Regexp(t, "FF10346", err)
+}
@@ -2789,7 +2789,7 @@ func TestGetFFIParamValidator(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestGenerateFFI(t *testing.T) {
+func TestGenerateFFIBadInput(t *testing.T) {
 	e, _ := newTestFabric()
 	_, err := e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{
 		Name:        "Simple",
@@ -2797,7 +2797,7 @@ func TestGenerateFFI(t *testing.T) {
 		Description: "desc",
 		Input:       fftypes.JSONAnyPtr(`[]`),
 	})
-	assert.Regexp(t, "FF10347", err)
+	assert.Regexp(t, "FF10346", err)
 }
 
 func TestGenerateEventSignature(t *testing.T) {
@@ -2838,7 +2838,7 @@ func TestHandleNetworkAction(t *testing.T) {
 		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
 	}
 
-	em.On("BlockchainNetworkAction", "terminate", mock.AnythingOfType("*fftypes.JSONAny"), mock.AnythingOfType("*blockchain.Event"), expectedSigningKeyRef).Return(nil)
+	em.On("BlockchainNetworkAction", "terminate", "", mock.AnythingOfType("*fftypes.JSONAny"), mock.AnythingOfType("*blockchain.Event"), expectedSigningKeyRef).Return(nil)
 
 	var events []interface{}
 	err := json.Unmarshal(data, &events)
@@ -2882,7 +2882,7 @@ func TestHandleNetworkActionFail(t *testing.T) {
 		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
 	}
 
-	em.On("BlockchainNetworkAction", "terminate", mock.AnythingOfType("*fftypes.JSONAny"), mock.AnythingOfType("*blockchain.Event"), expectedSigningKeyRef).Return(fmt.Errorf("pop"))
+	em.On("BlockchainNetworkAction", "terminate", "", mock.AnythingOfType("*fftypes.JSONAny"), mock.AnythingOfType("*blockchain.Event"), expectedSigningKeyRef).Return(fmt.Errorf("pop"))
 
 	var events []interface{}
 	err := json.Unmarshal(data, &events)
@@ -3077,7 +3077,37 @@ func TestSubmitNetworkAction(t *testing.T) {
 		"chaincode": "simplestorage",
 	}.String())
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, "", location)
+	assert.NoError(t, err)
+}
+
+func TestSubmitNetworkActionPayload(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "\"firefly:pause\"", (body["args"].(map[string]interface{}))["action"])
+			assert.Equal(t, "payload1", (body["args"].(map[string]interface{}))["payload"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(t, 2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionPause, "payload1", location)
 	assert.NoError(t, err)
 }
 
@@ -3109,7 +3139,7 @@ func TestSubmitNetworkActionV1(t *testing.T) {
 		"chaincode": "simplestorage",
 	}.String())
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, "", location)
 	assert.NoError(t, err)
 }
 
@@ -3136,7 +3166,7 @@ func TestSubmitNetworkActionBadLocation(t *testing.T) {
 		"bad": "location",
 	}.String())
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, "", location)
 	assert.Regexp(t, "FF10310", err)
 }
 
@@ -3157,7 +3187,7 @@ func TestSubmitNetworkActionVersionError(t *testing.T) {
 		"chaincode": "simplestorage",
 	}.String())
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, "", location)
 	assert.Regexp(t, "FF10284", err)
 }
 
@@ -214,6 +214,23 @@ func TestBroadcastMessageBadIdentity(t *testing.T) {
 	mim.AssertExpectations(t)
 }
 
+func TestBroadcastMessageEncrypted(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Encrypted: true,
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.Regexp(t, "FF10588", err)
+}
+
 func TestBroadcastPrepare(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -251,14 +251,14 @@ func TestPrepareAndRunUploadBlob(t *testing.T) {
 	addUploadBlobInputs(op, data.ID)
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
-	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
 	mdi := bm.database.(*databasemocks.Plugin)
 
 	reader := ioutil.NopCloser(strings.NewReader("some data"))
 	mdi.On("GetDataByID", mock.Anything, "ns1", data.ID, false).Return(data, nil)
 	mdi.On("GetBlobs", mock.Anything, bm.namespace.Name, mock.Anything).Return([]*core.Blob{blob}, nil, nil)
 	mps.On("UploadData", context.Background(), mock.Anything).Return("123", nil)
-	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
+	mdm.On("OpenBlob", context.Background(), blob).Return(reader, nil)
 	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.MatchedBy(func(update ffapi.Update) bool {
 		info, _ := update.Finalize()
 		assert.Equal(t, 1, len(info.SetOperations))
@@ -280,7 +280,7 @@ func TestPrepareAndRunUploadBlob(t *testing.T) {
 	assert.NoError(t, err)
 
 	mps.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 
 }
@@ -519,11 +519,11 @@ func TestRunOperationUploadBlobUpdateFail(t *testing.T) {
 	}
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
-	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
 	mdi := bm.database.(*databasemocks.Plugin)
 
 	reader := ioutil.NopCloser(strings.NewReader("some data"))
-	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
+	mdm.On("OpenBlob", context.Background(), blob).Return(reader, nil)
 	mps.On("UploadData", context.Background(), mock.Anything).Return("123", nil)
 	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(fmt.Errorf("pop"))
 
@@ -533,7 +533,7 @@ func TestRunOperationUploadBlobUpdateFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 	mps.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 }
 
@@ -578,10 +578,10 @@ func TestRunOperationUploadBlobUploadFail(t *testing.T) {
 	}
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
-	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
 
 	reader := ioutil.NopCloser(strings.NewReader("some data"))
-	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
+	mdm.On("OpenBlob", context.Background(), blob).Return(reader, nil)
 	mps.On("UploadData", context.Background(), mock.Anything).Return("", fmt.Errorf("pop"))
 
 	_, complete, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
@@ -590,7 +590,7 @@ func TestRunOperationUploadBlobUploadFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 	mps.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 }
 
 func TestRunOperationUploadValueUploadFail(t *testing.T) {
@@ -630,10 +630,10 @@ func TestRunOperationUploadBlobDownloadFail(t *testing.T) {
 	}
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
-	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
 
 	reader := ioutil.NopCloser(strings.NewReader("some data"))
-	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, fmt.Errorf("pop"))
+	mdm.On("OpenBlob", context.Background(), blob).Return(reader, fmt.Errorf("pop"))
 
 	_, complete, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
 
@@ -641,7 +641,7 @@ func TestRunOperationUploadBlobDownloadFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 	mps.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 }
 
 func TestOperationUpdate(t *testing.T) {
@@ -107,6 +107,7 @@ type Manager interface {
 type CInterface interface {
 	Get(key string) interface{}
 	Set(key string, val interface{})
+	Delete(key string)
 
 	GetString(key string) string
 	SetString(key string, val string)
@@ -141,6 +142,13 @@ func (c *CCache) Get(key string) interface{} {
 	return nil
 }
 
+func (c *CCache) Delete(key string) {
+	if !c.enabled {
+		return
+	}
+	c.cache.Delete(c.name + ":" + key)
+}
+
 func (c *CCache) SetString(key string, val string) {
 	c.Set(key, val)
 }
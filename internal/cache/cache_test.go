@@ -73,6 +73,9 @@ func TestTwoSeparateCacheWorksIndependently(t *testing.T) {
 	assert.Equal(t, nil, cache0.Get("string1"))
 	assert.Equal(t, "val1", cache1.GetString("string1"))
 	assert.Equal(t, "val1", cache1.Get("string1").(string))
+
+	cache1.Delete("string1")
+	assert.Equal(t, nil, cache1.Get("string1"))
 }
 
 func TestReturnsDummyCacheWhenCacheDisabled(t *testing.T) {
@@ -83,6 +86,7 @@ func TestReturnsDummyCacheWhenCacheDisabled(t *testing.T) {
 	cache0, _ := cacheManager.GetCache(NewCacheConfig(ctx, "cache.batch.limit", "cache.batch.ttl", ""))
 	cache0.SetInt("int0", 100)
 	assert.Equal(t, nil, cache0.Get("int0"))
+	cache0.Delete("int0")
 }
 
 func TestUmmanagedCacheInstance(t *testing.T) {
@@ -65,6 +65,18 @@ const (
 	NamespaceMultipartyContractLocation = "location"
 	// NamespaceMultipartyContractOptions is an object of additional blockchain-specific configuration
 	NamespaceMultipartyContractOptions = "options"
+	// NamespaceRetention contains the data retention policies for a namespace
+	NamespaceRetention = "retention"
+	// NamespaceRetentionMessages is how long to keep confirmed and rejected messages, and their data
+	NamespaceRetentionMessages = "messages"
+	// NamespaceRetentionEvents is how long to keep events
+	NamespaceRetentionEvents = "events"
+	// NamespaceRetentionOperations is how long to keep completed operations
+	NamespaceRetentionOperations = "operations"
+	// NamespaceRetentionBlockchainEvents is how long to keep blockchain events
+	NamespaceRetentionBlockchainEvents = "blockchainEvents"
+	// NamespaceRetentionTokenTransfers is how long to keep token transfers
+	NamespaceRetentionTokenTransfers = "tokenTransfers"
 )
 
 // The following keys can be access from the root configuration.
@@ -96,6 +108,14 @@ var (
 	BatchRetryInitDelay = ffc("batch.retry.initDelay")
 	// BatchRetryMaxDelay is the maximum delay between retry attempts
 	BatchRetryMaxDelay = ffc("batch.retry.maxDelay")
+	// BlobEncryptionEnabled enables envelope encryption of blobs uploaded to the local data exchange
+	BlobEncryptionEnabled = ffc("blobencryption.enabled")
+	// BlobEncryptionProvider is the key provider used to wrap the per-blob data keys
+	BlobEncryptionProvider = ffc("blobencryption.provider")
+	// BlobEncryptionKeyfilePath is the file containing the key encryption key, for the keyfile provider
+	BlobEncryptionKeyfilePath = ffc("blobencryption.keyfile.path")
+	// BlobUploadExpiry is how long a resumable blob upload is kept after its last chunk, before it is purged
+	BlobUploadExpiry = ffc("blobupload.expiry")
 	// BlobReceiverRetryInitDelay is the initial retry delay
 	BlobReceiverRetryInitDelay = ffc("blobreceiver.retry.initialDelay")
 	// BlobReceiverRetryMaxDelay is the maximum retry delay
@@ -186,6 +206,14 @@ var (
 	PrivateMessagingBatchPayloadLimit = ffc("privatemessaging.batch.payloadLimit")
 	// PrivateMessagingBatchTimeout is the timeout to wait for a batch to fill, before sending
 	PrivateMessagingBatchTimeout = ffc("privatemessaging.batch.timeout")
+	// PrivateMessagingEncryptionEnabled enables this node to receive private messages encrypted end-to-end, and publish its public key
+	PrivateMessagingEncryptionEnabled = ffc("privatemessaging.encryption.enabled")
+	// PrivateMessagingEncryptionKeyFile is the file containing the X25519 private key of this node
+	PrivateMessagingEncryptionKeyFile = ffc("privatemessaging.encryption.keyFile")
+	// PrivateMessagingReplyCheckInterval is how often to check for request messages that have passed their reply deadline without a reply
+	PrivateMessagingReplyCheckInterval = ffc("privatemessaging.replyCheck.interval")
+	// PrivateMessagingReplyCheckBatchSize is the maximum number of overdue request messages to process in each database round trip
+	PrivateMessagingReplyCheckBatchSize = ffc("privatemessaging.replyCheck.batchSize")
 	// PrivateMessagingRetryFactor the backoff factor to use for retry of database operations
 	PrivateMessagingRetryFactor = ffc("privatemessaging.retry.factor")
 	// PrivateMessagingRetryInitDelay the initial delay to use for retry of data base operations
@@ -310,8 +338,18 @@ var (
 	OrgDescription = ffc("org.description")
 	// OrchestratorStartupAttempts is how many time to attempt to connect to core infrastructure on startup
 	OrchestratorStartupAttempts = ffc("orchestrator.startupAttempts")
+	// RetentionInterval is how often the retention purger checks for expired records
+	RetentionInterval = ffc("retention.interval")
+	// RetentionBatchSize is the maximum number of records the retention purger deletes in each database round trip
+	RetentionBatchSize = ffc("retention.batchSize")
 	// SubscriptionDefaultsReadAhead default read ahead to enable for subscriptions that do not explicitly configure readahead
 	SubscriptionDefaultsReadAhead = ffc("subscription.defaults.batchSize")
+	// SubscriptionDefaultsBatchDeliverySize default maximum number of events in a batch, for subscriptions with batch delivery enabled
+	SubscriptionDefaultsBatchDeliverySize = ffc("subscription.defaults.batchDelivery.size")
+	// SubscriptionDefaultsBatchDeliveryTimeout default time to wait for a batch to fill, for subscriptions with batch delivery enabled
+	SubscriptionDefaultsBatchDeliveryTimeout = ffc("subscription.defaults.batchDelivery.timeout")
+	// SubscriptionReplayTimeout how long to wait for the transport to respond to the replay of a dead letter
+	SubscriptionReplayTimeout = ffc("subscription.replayTimeout")
 	// SubscriptionMax maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)
 	SubscriptionMax = ffc("subscription.max")
 	// SubscriptionsRetryInitialDelay is the initial retry delay
@@ -323,6 +361,8 @@ var (
 
 	// AssetManagerKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	AssetManagerKeyNormalization = ffc("asset.manager.keyNormalization")
+	// AssetManagerTransferBatchLimit the maximum number of items in a single batch of token transfers
+	AssetManagerTransferBatchLimit = ffc("asset.manager.transferBatchLimit")
 	// UIEnabled set to false to disable the UI (default is true, so UI will be enabled if ui.path is valid)
 	UIEnabled = ffc("ui.enabled")
 	// UIPath the path on which to serve the UI
@@ -339,6 +379,7 @@ func setDefaults() {
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIPassthroughHeaders), []string{})
 	viper.SetDefault(string(AssetManagerKeyNormalization), "blockchain_plugin")
+	viper.SetDefault(string(AssetManagerTransferBatchLimit), 1000)
 	viper.SetDefault(string(CacheBatchLimit), 100)
 	viper.SetDefault(string(CacheBatchTTL), "5m")
 	viper.SetDefault(string(BatchManagerReadPageSize), 100)
@@ -350,6 +391,9 @@ func setDefaults() {
 	viper.SetDefault(string(BatchRetryInitDelay), "250ms")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
+	viper.SetDefault(string(BlobEncryptionEnabled), false)
+	viper.SetDefault(string(BlobEncryptionProvider), "keyfile")
+	viper.SetDefault(string(BlobUploadExpiry), "24h")
 	viper.SetDefault(string(BlobReceiverRetryInitDelay), "250ms")
 	viper.SetDefault(string(BlobReceiverRetryMaxDelay), "1m")
 	viper.SetDefault(string(BlobReceiverRetryFactor), 2.0)
@@ -426,8 +470,16 @@ func setDefaults() {
 	viper.SetDefault(string(PrivateMessagingBatchSize), 200)
 	viper.SetDefault(string(PrivateMessagingBatchTimeout), "1s")
 	viper.SetDefault(string(PrivateMessagingBatchPayloadLimit), "800Kb")
+	viper.SetDefault(string(PrivateMessagingEncryptionEnabled), false)
+	viper.SetDefault(string(PrivateMessagingReplyCheckInterval), "5s")
+	viper.SetDefault(string(PrivateMessagingReplyCheckBatchSize), 50)
+	viper.SetDefault(string(RetentionInterval), "1h")
+	viper.SetDefault(string(RetentionBatchSize), 100)
 	viper.SetDefault(string(SubscriptionDefaultsReadAhead), 0)
+	viper.SetDefault(string(SubscriptionDefaultsBatchDeliverySize), 50)
+	viper.SetDefault(string(SubscriptionDefaultsBatchDeliveryTimeout), "50ms")
 	viper.SetDefault(string(SubscriptionMax), 500)
+	viper.SetDefault(string(SubscriptionReplayTimeout), "5m")
 	viper.SetDefault(string(SubscriptionsRetryInitialDelay), "250ms")
 	viper.SetDefault(string(SubscriptionsRetryMaxDelay), "30s")
 	viper.SetDefault(string(SubscriptionsRetryFactor), 2.0)
@@ -28,6 +28,9 @@ var (
 	APIParamsContractListenerNameOrID       = ffm("api.params.contractListenerNameOrID", "The contract listener name or ID")
 	APIParamsContractListenerID             = ffm("api.params.contractListenerID", "The contract listener ID")
 	APIParamsSubscriptionID                 = ffm("api.params.subscriptionID", "The subscription ID")
+	APIParamsDeadLetterID                   = ffm("api.params.deadLetterID", "The dead letter ID")
+	APIParamsDefinitionProposalID           = ffm("api.params.definitionProposalID", "The definition proposal ID")
+	APIParamsOrgApplicationID               = ffm("api.params.orgApplicationID", "The org application ID")
 	APIParamsBatchID                        = ffm("api.params.batchId", "The batch ID")
 	APIParamsBlockchainEventID              = ffm("api.params.blockchainEventID", "The blockchain event ID")
 	APIParamsCollectionID                   = ffm("api.params.collectionID", "The collection ID")
@@ -39,6 +42,8 @@ var (
 	APIParamsNSIncludeInitializing          = ffm("api.params.nsIncludeInitializing", "When set, the API will return namespaces even if they are not yet initialized, including in error cases where an initializationError is included")
 	APIParamsBlobID                         = ffm("api.params.blobID", "The blob ID")
 	APIParamsDataID                         = ffm("api.params.dataID", "The data item ID")
+	APIParamsUploadID                       = ffm("api.params.uploadID", "The resumable blob upload ID")
+	APIParamsUploadOffset                   = ffm("api.params.uploadOffset", "The offset of this chunk within the blob, which must equal the number of bytes received so far")
 	APIParamsDatatypeName                   = ffm("api.params.datatypeName", "The name of the datatype")
 	APIParamsDatatypeVersion                = ffm("api.params.datatypeVersion", "The version of the datatype")
 	APIParamsDataParentPath                 = ffm("api.params.dataParentPath", "The parent path to query")
@@ -53,6 +58,8 @@ var (
 	APIParamsNodeNameOrID                   = ffm("api.params.nodeNameOrID", "The name or ID of the node")
 	APIParamsOrgNameOrID                    = ffm("api.params.orgNameOrID", "The name or ID of the org")
 	APIParamsTokenAccountKey                = ffm("api.params.tokenAccountKey", "The key for the token account. The exact format may vary based on the token connector use")
+	APIParamsTokenBalanceSequence           = ffm("api.params.tokenBalanceSequence", "Return balances as of the last change with a blockchain event sequence at or before this sequence")
+	APIParamsTokenBalanceTimestamp          = ffm("api.params.tokenBalanceTimestamp", "Return balances as of the last change with a blockchain event at or before this time")
 	APIParamsTokenPoolNameOrID              = ffm("api.params.tokenPoolNameOrID", "The token pool name or ID")
 	APIParamsTokenTransferFromOrTo          = ffm("api.params.tokenTransferFromOrTo", "The sending or receiving token account for a token transfer")
 	APIParamsTokenTransferID                = ffm("api.params.tokenTransferID", "The token transfer ID")
@@ -80,6 +87,7 @@ var (
 	APIEndpointsDeleteContractInterface         = ffm("api.endpoints.deleteContractInterface", "Delete a contract interface")
 	APIEndpointsDeleteContractListener          = ffm("api.endpoints.deleteContractListener", "Deletes a contract listener referenced by its name or its ID")
 	APIEndpointsDeleteSubscription              = ffm("api.endpoints.deleteSubscription", "Deletes a subscription")
+	APIEndpointsDeleteDeadLetters               = ffm("api.endpoints.deleteSubscriptionDeadLetters", "Purges all the dead letters for a subscription")
 	APIEndpointsDeleteTokenPool                 = ffm("api.endpoints.deleteTokenPool", "Delete a token pool")
 	APIEndpointsGetBatchBbyID                   = ffm("api.endpoints.getBatchByID", "Gets a message batch")
 	APIEndpointsGetBatches                      = ffm("api.endpoints.getBatches", "Gets a list of message batches")
@@ -93,15 +101,17 @@ var (
 	APIEndpointsGetContractInterfaces           = ffm("api.endpoints.getContractInterfaces", "Gets a list of contract interfaces that have been published")
 	APIEndpointsGetContractListenerByNameOrID   = ffm("api.endpoints.getContractListenerByNameOrID", "Gets a contract listener by its name or ID")
 	APIEndpointsGetContractListeners            = ffm("api.endpoints.getContractListeners", "Gets a list of contract listeners")
-	APIEndpointsGetDataBlob                     = ffm("api.endpoints.getDataBlob", "Downloads the original file that was previously uploaded or received")
+	APIEndpointsGetDataBlob                     = ffm("api.endpoints.getDataBlob", "Downloads the original file that was previously uploaded or received. A single byte range can be requested with the Range header")
 	APIEndpointsGetDataValue                    = ffm("api.endpoints.getDataValue", "Downloads the JSON value of the data resource, without the associated metadata")
 	APIEndpointsGetDataByID                     = ffm("api.endpoints.getDataByID", "Gets a data item by its ID, including metadata about this item")
 	APIEndpointsDeleteData                      = ffm("api.endpoints.deleteData", "Deletes a data item by its ID, including metadata about this item")
 	APIEndpointsGetDataMsgs                     = ffm("api.endpoints.getDataMsgs", "Gets a list of the messages associated with a data item")
-	APIEndpointsGetData                         = ffm("api.endpoints.getData", "Gets a list of data items")
+	APIEndpointsGetData                         = ffm("api.endpoints.getData", "Gets a list of data items. Query parameters of the form value.<path> filter on a dot-separated JSON path within the value of the data")
 	APIEndpointsGetDataSubPaths                 = ffm("api.endpoints.getDataSubPaths", "Gets a list of path names of named blob data, underneath a given parent path ('/' path prefixes are automatically pre-prepended)")
 	APIEndpointsGetDatatypeByName               = ffm("api.endpoints.getDatatypeByName", "Gets a datatype by its name and version")
 	APIEndpointsGetDatatypes                    = ffm("api.endpoints.getDatatypes", "Gets a list of datatypes that have been published")
+	APIEndpointsGetDefinitionProposalByID       = ffm("api.endpoints.getDefinitionProposalByID", "Gets a definition proposal by its ID, including the votes recorded against it")
+	APIEndpointsGetDefinitionProposals          = ffm("api.endpoints.getDefinitionProposals", "Gets a list of definition proposals, which are held until a quorum of root organizations approve them")
 	APIEndpointsGetEventByID                    = ffm("api.endpoints.eventID", "Gets an event by its ID")
 	APIEndpointsGetEvents                       = ffm("api.endpoints.getEvents", "Gets a list of events")
 	APIEndpointsGetGroupByHash                  = ffm("api.endpoints.getGroupByHash", "Gets a group by its ID (hash)")
@@ -113,8 +123,9 @@ var (
 	APIEndpointsGetMsgByID                      = ffm("api.endpoints.getMsgByID", "Gets a message by its ID")
 	APIEndpointsGetMsgData                      = ffm("api.endpoints.getMsgData", "Gets the list of data items that are attached to a message")
 	APIEndpointsGetMsgEvents                    = ffm("api.endpoints.getMsgEvents", "Gets the list of events for a message")
+	APIEndpointsGetMsgReplies                   = ffm("api.endpoints.getMsgReplies", "Gets the list of reply messages correlated to a request message by their cid")
 	APIEndpointsGetMsgTxn                       = ffm("api.endpoints.getMsgTxn", "Gets the transaction for a message")
-	APIEndpointsGetMsgs                         = ffm("api.endpoints.getMsgs", "Gets a list of messages")
+	APIEndpointsGetMsgs                         = ffm("api.endpoints.getMsgs", "Gets a list of messages. Query parameters of the form value.<path> filter on a dot-separated JSON path within the value of the first data item of the message containing that path")
 	APIEndpointsGetNamespace                    = ffm("api.endpoints.getNamespace", "Gets a namespace")
 	APIEndpointsGetNamespaces                   = ffm("api.endpoints.getNamespaces", "Gets a list of namespaces")
 	APIEndpointsGetNetworkIdentityByDID         = ffm("api.endpoints.getNetworkIdentityByDID", "Gets an identity by its DID (deprecated - use /identities/{did} instead of /network/identities/{did})")
@@ -125,6 +136,9 @@ var (
 	APIEndpointsGetNetworkNodes                 = ffm("api.endpoints.getNetworkNodes", "Gets a list of nodes in the network")
 	APIEndpointsGetNetworkOrg                   = ffm("api.endpoints.getNetworkOrg", "Gets information about a specific org in the network")
 	APIEndpointsGetNetworkOrgs                  = ffm("api.endpoints.APIEndpointsGetNetworkOrgs", "Gets a list of orgs in the network")
+	APIEndpointsGetNetworkApplication           = ffm("api.endpoints.getNetworkApplication", "Gets an application from an org to join the network")
+	APIEndpointsGetNetworkApplications          = ffm("api.endpoints.getNetworkApplications", "Gets a list of applications from orgs to join the network")
+	APIEndpointsGetNetworkGovernance            = ffm("api.endpoints.getNetworkGovernance", "Gets the governance rules currently confirmed for the network")
 	APIEndpointsGetOpByID                       = ffm("api.endpoints.getOpByID", "Gets an operation by ID")
 	APIEndpointsGetOps                          = ffm("api.endpoints.getOps", "Gets a a list of operations")
 	APIEndpointsGetStatusBatchManager           = ffm("api.endpoints.getStatusBatchManager", "Gets the status of the batch manager")
@@ -134,14 +148,20 @@ var (
 	APIEndpointsGetStatus                       = ffm("api.endpoints.getStatus", "Gets the status of this namespace")
 	APIEndpointsGetSubscriptionByID             = ffm("api.endpoints.getSubscriptionByID", "Gets a subscription by its ID")
 	APIEndpointsGetSubscriptions                = ffm("api.endpoints.getSubscriptions", "Gets a list of subscriptions")
+	APIEndpointsGetDeadLetters                  = ffm("api.endpoints.getSubscriptionDeadLetters", "Gets a list of events that could not be delivered on a subscription")
 	APIEndpointsGetTokenAccountPools            = ffm("api.endpoints.getTokenAccountPools", "Gets a list of token pools that contain a given token account key")
 	APIEndpointsGetTokenAccounts                = ffm("api.endpoints.getTokenAccounts", "Gets a list of token accounts")
 	APIEndpointsGetTokenApprovals               = ffm("api.endpoints.getTokenApprovals", "Gets a list of token approvals")
+	APIEndpointsGetTokenBalanceHistory          = ffm("api.endpoints.getTokenBalanceHistory", "Gets the history of changes to token balances, with the balance after each confirmed transfer")
+	APIEndpointsGetTokenBalanceMismatches       = ffm("api.endpoints.getTokenBalanceMismatches", "Gets a list of token balances found by reconciliation not to match the token connector")
 	APIEndpointsGetTokenBalances                = ffm("api.endpoints.getTokenBalances", "Gets a list of token balances")
+	APIEndpointsGetTokenBalancesAt              = ffm("api.endpoints.getTokenBalancesAt", "Gets a list of token balances as they were at a point in the balance history")
 	APIEndpointsGetTokenConnectors              = ffm("api.endpoints.getTokenConnectors", "Gets the list of token connectors currently in use")
 	APIEndpointsGetTokenPoolByNameOrID          = ffm("api.endpoints.getTokenPoolByNameOrID", "Gets a token pool by its name or its ID")
+	APIEndpointsGetTokenPoolBalanceSnapshot     = ffm("api.endpoints.getTokenPoolBalanceSnapshot", "Gets a snapshot of all non-zero balances in a token pool at a point in the balance history")
 	APIEndpointsGetTokenPools                   = ffm("api.endpoints.getTokenPools", "Gets a list of token pools")
 	APIEndpointsGetTokenTransferByID            = ffm("api.endpoints.getTokenTransferByID", "Gets a token transfer by its ID")
+	APIEndpointsGetTokenTransferBatch           = ffm("api.endpoints.getTokenTransferBatch", "Gets the status of each transfer in a batch of token transfers, by the ID of its transaction")
 	APIEndpointsGetTokenTransfers               = ffm("api.endpoints.getTokenTransfers", "Gets a list of token transfers")
 	APIEndpointsGetTxnBlockchainEvents          = ffm("api.endpoints.getTxnBlockchainEvents", "Gets a list blockchain events for a specific transaction")
 	APIEndpointsGetTxnByID                      = ffm("api.endpoints.getTxnByID", "Gets a transaction by its ID")
@@ -164,46 +184,65 @@ var (
 	APIEndpointsPostData                        = ffm("api.endpoints.postData", "Creates a new data item in this FireFly node")
 	APIEndpointsPostDataValuePublish            = ffm("api.endpoints.postDataValuePublish", "Publishes the JSON value from the specified data resource, to shared storage")
 	APIEndpointsPostDataBlobPublish             = ffm("api.endpoints.postDataBlobPublish", "Publishes the binary blob attachment stored in your local data exchange, to shared storage")
+	APIEndpointsPostDataUpload                  = ffm("api.endpoints.postDataUpload", "Starts a resumable upload of a blob, which is then sent in chunks")
+	APIEndpointsGetDataUploadByID               = ffm("api.endpoints.getDataUploadByID", "Gets the progress of a resumable blob upload, including the offset of the next chunk, and the data item once it has been finalized")
+	APIEndpointsPutDataUploadChunk              = ffm("api.endpoints.putDataUploadChunk", "Uploads the next chunk of a resumable blob upload, as a multi-part form. The offset must equal the number of bytes received so far")
+	APIEndpointsPostDataUploadFinalize          = ffm("api.endpoints.postDataUploadFinalize", "Completes a resumable blob upload. The chunks are combined into a data item with the blob attached in the background - query the upload to find when the data item is ready")
+	APIEndpointsDeleteDataUpload                = ffm("api.endpoints.deleteDataUpload", "Cancels a resumable blob upload, deleting the chunks received so far")
 	APIEndpointsPostNewContractAPI              = ffm("api.endpoints.postNewContractAPI", "Creates and broadcasts a new custom smart contract API")
 	APIEndpointsPostNewContractInterface        = ffm("api.endpoints.postNewContractInterface", "Creates and broadcasts a new custom smart contract interface")
 	APIEndpointsPostNewContractListener         = ffm("api.endpoints.postNewContractListener", "Creates a new blockchain listener for events emitted by custom smart contracts")
 	APIEndpointsPostNewDatatype                 = ffm("api.endpoints.postNewDatatype", "Creates and broadcasts a new datatype")
 	APIEndpointsPostNewIdentity                 = ffm("api.endpoints.postNewIdentity", "Registers a new identity in the network")
+	APIEndpointsPostIdentityRevoke              = ffm("api.endpoints.postIdentityRevoke", "Revokes an identity and all of its verifiers, or a single verifier when a key is supplied. Messages signed by a revoked key after the revocation are rejected")
+	APIEndpointsPostIdentityRotateKey           = ffm("api.endpoints.postIdentityRotateKey", "Rotates the blockchain signing key of an identity, revoking its existing keys of the same type")
 	APIEndpointsPostNewMessageBroadcast         = ffm("api.endpoints.postNewMessageBroadcast", "Broadcasts a message to all members in the network")
 	APIEndpointsPostNewMessagePrivate           = ffm("api.endpoints.postNewMessagePrivate", "Privately sends a message to one or more members in the network")
-	APIEndpointsPostNewMessageRequestReply      = ffm("api.endpoints.postNewMessageRequestReply", "Sends a message with a blocking HTTP request, waits for a reply to that message, then sends the reply as the HTTP response.")
+	APIEndpointsPostNewMessageRequestReply      = ffm("api.endpoints.postNewMessageRequestReply", "Sends a message with a blocking HTTP request, waits for a reply to that message, then sends the reply as the HTTP response. With async=true the request message is returned as soon as it is sent, and replies can be queried later using its ID")
 	APIEndpointsPostNewNamespace                = ffm("api.endpoints.postNewNamespace", "Creates and broadcasts a new namespace")
 	APIEndpointsPostNodesSelf                   = ffm("api.endpoints.postNodesSelf", "Instructs this FireFly node to register itself on the network")
 	APIEndpointsPostNewOrganizationSelf         = ffm("api.endpoints.postNewOrganizationSelf", "Instructs this FireFly node to register its org on the network")
 	APIEndpointsPostNewOrganization             = ffm("api.endpoints.postNewOrganization", "Registers a new org in the network")
 	APIEndpointsPostNewSubscription             = ffm("api.endpoints.postNewSubscription", "Creates a new subscription for an application to receive events from FireFly")
 	APIEndpointsPostOpRetry                     = ffm("api.endpoints.postOpRetry", "Retries a failed operation")
+	APIEndpointsPostDeadLetterReplay            = ffm("api.endpoints.postSubscriptionDeadLetterReplay", "Replays delivery of a dead letter on a subscription, deleting the dead letter if it is delivered successfully")
 	APIEndpointsPostPinsRewind                  = ffm("api.endpoints.postPinsRewind", "Force a rewind of the event aggregator to a previous position, to re-evaluate (and possibly dispatch) that pin and others after it. Only accepts a sequence or batch ID for a currently undispatched pin")
 	APIEndpointsPostTokenApproval               = ffm("api.endpoints.postTokenApproval", "Creates a token approval")
 	APIEndpointsPostTokenBurn                   = ffm("api.endpoints.postTokenBurn", "Burns some tokens")
+	APIEndpointsPostTokenBurnBatch              = ffm("api.endpoints.postTokenBurnBatch", "Burns tokens from many accounts, in a single transaction")
 	APIEndpointsPostTokenMint                   = ffm("api.endpoints.postTokenMint", "Mints some tokens")
+	APIEndpointsPostTokenMintBatch              = ffm("api.endpoints.postTokenMintBatch", "Mints tokens to many accounts, in a single transaction")
 	APIEndpointsPostTokenPool                   = ffm("api.endpoints.postTokenPool", "Creates a new token pool")
 	APIEndpointsPostTokenPoolPublish            = ffm("api.endpoints.postTokenPoolPublish", "Publish a token pool to all other members of the multiparty network")
+	APIEndpointsPostTokenReconcile              = ffm("api.endpoints.postTokenReconcile", "Submits a background job to reconcile local token balances against the balances reported by the token connectors. Only the accounts this node already holds a local balance for are checked - accounts that have never been seen in a confirmed transfer are not discovered")
 	APIEndpointsPostTokenTransfer               = ffm("api.endpoints.postTokenTransfer", "Transfers some tokens")
+	APIEndpointsPostTokenTransferBatch          = ffm("api.endpoints.postTokenTransferBatch", "Performs many token transfers, in a single transaction")
 	APIEndpointsPutContractAPI                  = ffm("api.endpoints.putContractAPI", "Updates an existing contract API")
 	APIEndpointsPutSubscription                 = ffm("api.endpoints.putSubscription", "Update an existing subscription")
 	APIEndpointsGetContractAPIInterface         = ffm("api.endpoints.getContractAPIInterface", "Gets a contract interface for a contract API")
+	APIEndpointsPostDefinitionProposalVote      = ffm("api.endpoints.postDefinitionProposalVote", "Broadcasts a vote from the local root organization, to approve or reject a definition proposal")
 	APIEndpointsPostNetworkAction               = ffm("api.endpoints.postNetworkAction", "Notify all nodes in the network of a new governance action")
+	APIEndpointsPostNetworkApplication          = ffm("api.endpoints.postNetworkApplication", "Applies for the org and node configured on this FireFly node to join the network, sponsored by an existing root org. The org and node are only registered once the sponsor approves the application")
+	APIEndpointsPostNetworkApplicationReview    = ffm("api.endpoints.postNetworkApplicationReview", "Broadcasts a review from the local root organization, to approve or reject an application it sponsors")
+	APIEndpointsPostNetworkGovernance           = ffm("api.endpoints.postNetworkGovernance", "Broadcasts new governance rules for the network from the local root organization. When a definition quorum is already set, the change is held as a proposal until the root organizations approve it")
 	APIEndpointsPostVerifiersResolve            = ffm("api.endpoints.postVerifiersResolve", "Resolves an input key to a signing key")
 
-	APIFilterParamDesc         = ffm("api.filterParam", "Data filter field. Prefixes supported: > >= < <= @ ^ ! !@ !^")
-	APIFilterSortDesc          = ffm("api.filterSort", "Sort field. For multi-field sort use comma separated values (or multiple query values) with '-' prefix for descending")
-	APIFilterAscendingDesc     = ffm("api.filterAscending", "Ascending sort order (overrides all fields in a multi-field sort)")
-	APIFilterDescendingDesc    = ffm("api.filterDescending", "Descending sort order (overrides all fields in a multi-field sort)")
-	APIFilterSkipDesc          = ffm("api.filterSkip", "The number of records to skip (max: %d). Unsuitable for bulk operations")
-	APIFilterLimitDesc         = ffm("api.filterLimit", "The maximum number of records to return (max: %d)")
-	APIFilterCountDesc         = ffm("api.filterCount", "Return a total count as well as items (adds extra database processing)")
-	APIFetchDataDesc           = ffm("api.fetchData", "Fetch the data and include it in the messages returned")
-	APIConfirmQueryParam       = ffm("api.confirmQueryParam", "When true the HTTP request blocks until the message is confirmed")
-	APIPublishQueryParam       = ffm("api.publishQueryParam", "When true the definition will be published to all other members of the multiparty network")
-	APIHistogramStartTimeParam = ffm("api.histogramStartTime", "Start time of the data to be fetched")
-	APIHistogramEndTimeParam   = ffm("api.histogramEndTime", "End time of the data to be fetched")
-	APIHistogramBucketsParam   = ffm("api.histogramBuckets", "Number of buckets between start time and end time")
+	APIFilterParamDesc             = ffm("api.filterParam", "Data filter field. Prefixes supported: > >= < <= @ ^ ! !@ !^")
+	APIFilterSortDesc              = ffm("api.filterSort", "Sort field. For multi-field sort use comma separated values (or multiple query values) with '-' prefix for descending")
+	APIFilterAscendingDesc         = ffm("api.filterAscending", "Ascending sort order (overrides all fields in a multi-field sort)")
+	APIFilterDescendingDesc        = ffm("api.filterDescending", "Descending sort order (overrides all fields in a multi-field sort)")
+	APIFilterSkipDesc              = ffm("api.filterSkip", "The number of records to skip (max: %d). Unsuitable for bulk operations")
+	APIFilterLimitDesc             = ffm("api.filterLimit", "The maximum number of records to return (max: %d)")
+	APIFilterCountDesc             = ffm("api.filterCount", "Return a total count as well as items (adds extra database processing)")
+	APIFetchDataDesc               = ffm("api.fetchData", "Fetch the data and include it in the messages returned")
+	APIConfirmQueryParam           = ffm("api.confirmQueryParam", "When true the HTTP request blocks until the message is confirmed")
+	APIAsyncRequestReplyQueryParam = ffm("api.asyncRequestReplyQueryParam", "When true the HTTP request returns the request message as soon as it is sent, rather than waiting for a reply")
+	APIPublishQueryParam           = ffm("api.publishQueryParam", "When true the definition will be published to all other members of the multiparty network")
+	APIDefinitionGroupQueryParam   = ffm("api.definitionGroupQueryParam", "The hash of an existing privacy group, to share the definition privately with the members of that group rather than the whole network")
+	APIDatatypeGroupQueryParam     = ffm("api.datatypeGroupQueryParam", "The hash of a privacy group, to return the datatype shared with that group if there is one, rather than the one published to the whole network")
+	APIHistogramStartTimeParam     = ffm("api.histogramStartTime", "Start time of the data to be fetched")
+	APIHistogramEndTimeParam       = ffm("api.histogramEndTime", "End time of the data to be fetched")
+	APIHistogramBucketsParam       = ffm("api.histogramBuckets", "Number of buckets between start time and end time")
 
 	APISmartContractDetails      = ffm("api.smartContractDetails", "Additional smart contract details")
 	APISmartContractDetailsKey   = ffm("api.smartContractDetailsKey", "Key")
@@ -50,15 +50,20 @@ var (
 	ConfigAPIRequestMaxTimeout  = ffc("config.api.requestMaxTimeout", "The maximum amount of time that an HTTP client can specify in a `Request-Timeout` header to keep a specific request open", i18n.TimeDurationType)
 	ConfigAPIPassthroughHeaders = ffc("config.api.passthroughHeaders", "A list of HTTP request headers to pass through to dependency microservices", i18n.ArrayStringType)
 
-	ConfigAssetManagerKeyNormalization = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
+	ConfigAssetManagerKeyNormalization   = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
+	ConfigAssetManagerTransferBatchLimit = ffc("config.asset.manager.transferBatchLimit", "The maximum number of mints, burns or transfers in a single batch request", i18n.IntType)
 
 	ConfigBatchManagerMinimumPollDelay = ffc("config.batch.manager.minimumPollDelay", "The minimum time the batch manager waits between polls on the DB - to prevent thrashing", i18n.TimeDurationType)
 	ConfigBatchManagerPollTimeout      = ffc("config.batch.manager.pollTimeout", "How long to wait without any notifications of new messages before doing a page query", i18n.TimeDurationType)
 	ConfigBatchManagerReadPageSize     = ffc("config.batch.manager.readPageSize", "The size of each page of messages read from the database into memory when assembling batches", i18n.IntType)
 
+	ConfigBlobencryptionEnabled             = ffc("config.blobencryption.enabled", "Encrypt blobs before they are uploaded to the local data exchange, using a random data key per blob wrapped by a key encryption key", i18n.BooleanType)
+	ConfigBlobencryptionProvider            = ffc("config.blobencryption.provider", "The key provider that holds the key encryption key. The built-in provider is 'keyfile'", i18n.StringType)
+	ConfigBlobencryptionKeyfilePath         = ffc("config.blobencryption.keyfile.path", "A file containing the 256-bit key encryption key, hex encoded, for the 'keyfile' provider", i18n.StringType)
 	ConfigBlobreceiverWorkerBatchMaxInserts = ffc("config.blobreceiver.worker.batchMaxInserts", "The maximum number of items the blob receiver worker will insert in a batch", i18n.IntType)
 	ConfigBlobreceiverWorkerBatchTimeout    = ffc("config.blobreceiver.worker.batchTimeout", "The maximum amount of the the blob receiver worker will wait", i18n.TimeDurationType)
 	ConfigBlobreceiverWorkerCount           = ffc("config.blobreceiver.worker.count", "The number of blob receiver workers", i18n.IntType)
+	ConfigBlobuploadExpiry                  = ffc("config.blobupload.expiry", "How long a resumable blob upload is kept after it last changed, before it is purged along with any staged chunks", i18n.TimeDurationType)
 
 	ConfigBlockchainType = ffc("config.blockchain.type", "A string defining which type of blockchain plugin to use. This tells FireFly which type of configuration to load for the rest of the `blockchain` section", i18n.StringType)
 
@@ -132,17 +137,23 @@ var (
 	ConfigPluginDatabaseName = ffc("config.plugins.database[].name", "The name of the Database plugin", i18n.StringType)
 	ConfigPluginDatabaseType = ffc("config.plugins.database[].type", "The type of the configured Database plugin", i18n.StringType)
 
-	ConfigPluginDatabasePostgresMaxConnIdleTime = ffc("config.plugins.database[].postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigPluginDatabasePostgresMaxConnLifetime = ffc("config.plugins.database[].postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigPluginDatabasePostgresMaxConns        = ffc("config.plugins.database[].postgres.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigPluginDatabasePostgresMaxIdleConns    = ffc("config.plugins.database[].postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigPluginDatabasePostgresURL             = ffc("config.plugins.database[].postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
-
-	ConfigPluginDatabaseSqlite3MaxConnIdleTime = ffc("config.plugins.database[].sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigPluginDatabaseSqlite3MaxConnLifetime = ffc("config.plugins.database[].sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigPluginDatabaseSqlite3MaxConns        = ffc("config.plugins.database[].sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigPluginDatabaseSqlite3MaxIdleConns    = ffc("config.plugins.database[].sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigPluginDatabaseSqlite3URL             = ffc("config.plugins.database[].sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigPluginDatabasePostgresMaxConnIdleTime      = ffc("config.plugins.database[].postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresMaxConnLifetime      = ffc("config.plugins.database[].postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresMaxConns             = ffc("config.plugins.database[].postgres.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabasePostgresMaxIdleConns         = ffc("config.plugins.database[].postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabasePostgresURL                  = ffc("config.plugins.database[].postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
+	ConfigPluginDatabasePostgresValueIndexes         = ffc("config.plugins.database[].postgres.valueIndexes", "A list of paths within the value of data to create database indexes for, to speed up queries that filter on those paths. Each path is indexed both as text and as a number", "List "+i18n.StringType)
+	ConfigPluginDatabasePostgresValueIndexesDatatype = ffc("config.plugins.database[].postgres.valueIndexes[].datatype", "Optional name of a datatype, to only index data of that datatype", i18n.StringType)
+	ConfigPluginDatabasePostgresValueIndexesPath     = ffc("config.plugins.database[].postgres.valueIndexes[].path", "The dot-separated JSON path within the value of data to index, such as customer.id", i18n.StringType)
+
+	ConfigPluginDatabaseSqlite3MaxConnIdleTime      = ffc("config.plugins.database[].sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3MaxConnLifetime      = ffc("config.plugins.database[].sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3MaxConns             = ffc("config.plugins.database[].sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabaseSqlite3MaxIdleConns         = ffc("config.plugins.database[].sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabaseSqlite3URL                  = ffc("config.plugins.database[].sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigPluginDatabaseSqlite3ValueIndexes         = ffc("config.plugins.database[].sqlite3.valueIndexes", "A list of paths within the value of data to create database indexes for, to speed up queries that filter on those paths. Each path is indexed both as text and as a number", "List "+i18n.StringType)
+	ConfigPluginDatabaseSqlite3ValueIndexesDatatype = ffc("config.plugins.database[].sqlite3.valueIndexes[].datatype", "Optional name of a datatype, to only index data of that datatype", i18n.StringType)
+	ConfigPluginDatabaseSqlite3ValueIndexesPath     = ffc("config.plugins.database[].sqlite3.valueIndexes[].path", "The dot-separated JSON path within the value of data to index, such as customer.id", i18n.StringType)
 
 	ConfigPluginBlockchain     = ffc("config.plugins.blockchain", "The list of configured Blockchain plugins", i18n.StringType)
 	ConfigPluginBlockchainName = ffc("config.plugins.blockchain[].name", "The name of the configured Blockchain plugin", i18n.StringType)
@@ -181,6 +192,18 @@ var (
 	ConfigPluginBlockchainEthereumFFTMURL      = ffc("config.plugins.blockchain[].ethereum.fftm.url", "The URL of the FireFly Transaction Manager runtime, if enabled", i18n.StringType)
 	ConfigPluginBlockchainEthereumFFTMProxyURL = ffc("config.plugins.blockchain[].ethereum.fftm.proxy.url", "Optional HTTP proxy server to use when connecting to the Transaction Manager", i18n.StringType)
 
+	ConfigPluginBlockchainEVMRPCURL                     = ffc("config.plugins.blockchain[].evmrpc.rpc.url", "The URL of the JSON-RPC endpoint of the EVM node", "URL "+i18n.StringType)
+	ConfigPluginBlockchainEVMRPCProxyURL                = ffc("config.plugins.blockchain[].evmrpc.rpc.proxy.url", "Optional HTTP proxy server to use when connecting to the EVM node", "URL "+i18n.StringType)
+	ConfigPluginBlockchainEVMRPCSignerType              = ffc("config.plugins.blockchain[].evmrpc.signer.type", "How transactions are signed - 'node' submits them to the node with eth_sendTransaction, and 'jsonrpc' signs them with eth_signTransaction on a separate JSON-RPC signer", i18n.StringType)
+	ConfigPluginBlockchainEVMRPCSignerURL               = ffc("config.plugins.blockchain[].evmrpc.signer.url", "The URL of the JSON-RPC signer, when the signer type is 'jsonrpc'", "URL "+i18n.StringType)
+	ConfigPluginBlockchainEVMRPCSignerProxyURL          = ffc("config.plugins.blockchain[].evmrpc.signer.proxy.url", "Optional HTTP proxy server to use when connecting to the JSON-RPC signer", "URL "+i18n.StringType)
+	ConfigPluginBlockchainEVMRPCSignerGasEstimation     = ffc("config.plugins.blockchain[].evmrpc.signer.gasEstimationFactor", "The factor applied to the gas estimate from the node, when the signer type is 'jsonrpc' and no gas is supplied on the request", i18n.FloatType)
+	ConfigPluginBlockchainEVMRPCConfirmations           = ffc("config.plugins.blockchain[].evmrpc.confirmations", "The number of blocks that must be mined on top of a block, before its events and transaction receipts are processed", i18n.IntType)
+	ConfigPluginBlockchainEVMRPCEventsPollingInterval   = ffc("config.plugins.blockchain[].evmrpc.events.pollingInterval", "How often to poll the node for new blocks, and query the logs for each listener", i18n.TimeDurationType)
+	ConfigPluginBlockchainEVMRPCEventsCheckpointFile    = ffc("config.plugins.blockchain[].evmrpc.events.checkpointFile", "The file the checkpoint of each listener is persisted to, so that events are resumed from the last processed block after a restart. Each evmrpc plugin must use a different file", i18n.StringType)
+	ConfigPluginBlockchainEVMRPCEventsMaxBlockRange     = ffc("config.plugins.blockchain[].evmrpc.events.maxBlockRange", "The maximum number of blocks to query the logs for in a single request to the node", i18n.IntType)
+	ConfigPluginBlockchainEVMRPCReceiptsPollingInterval = ffc("config.plugins.blockchain[].evmrpc.receipts.pollingInterval", "How often to poll the node for the receipts of submitted transactions", i18n.TimeDurationType)
+
 	ConfigPluginBlockchainFabricFabconnectBackgroundStart             = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.enabled", "Start the fabric plugin in the background and enter retry loop if failed to start", i18n.BooleanType)
 	ConfigPluginBlockchainFabricFabconnectBackgroundStartInitialDelay = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the fabric plugin", i18n.TimeDurationType)
 	ConfigPluginBlockchainFabricFabconnectBackgroundStartMaxDelay     = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the fabric plugin", i18n.TimeDurationType)
@@ -203,17 +226,23 @@ var (
 
 	ConfigDatabaseType = ffc("config.database.type", "The type of the database interface plugin to use", i18n.IntType)
 
-	ConfigDatabasePostgresMaxConnIdleTime = ffc("config.database.postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigDatabasePostgresMaxConnLifetime = ffc("config.database.postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigDatabasePostgresMaxConns        = ffc("config.database.postgres.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigDatabasePostgresMaxIdleConns    = ffc("config.database.postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigDatabasePostgresURL             = ffc("config.database.postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
-
-	ConfigDatabaseSqlite3MaxConnIdleTime = ffc("config.database.sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigDatabaseSqlite3MaxConnLifetime = ffc("config.database.sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigDatabaseSqlite3MaxConns        = ffc("config.database.sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigDatabaseSqlite3MaxIdleConns    = ffc("config.database.sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigDatabaseSqlite3URL             = ffc("config.database.sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigDatabasePostgresMaxConnIdleTime      = ffc("config.database.postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigDatabasePostgresMaxConnLifetime      = ffc("config.database.postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigDatabasePostgresMaxConns             = ffc("config.database.postgres.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigDatabasePostgresMaxIdleConns         = ffc("config.database.postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigDatabasePostgresURL                  = ffc("config.database.postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
+	ConfigDatabasePostgresValueIndexes         = ffc("config.database.postgres.valueIndexes", "A list of paths within the value of data to create database indexes for, to speed up queries that filter on those paths. Each path is indexed both as text and as a number", "List "+i18n.StringType)
+	ConfigDatabasePostgresValueIndexesDatatype = ffc("config.database.postgres.valueIndexes[].datatype", "Optional name of a datatype, to only index data of that datatype", i18n.StringType)
+	ConfigDatabasePostgresValueIndexesPath     = ffc("config.database.postgres.valueIndexes[].path", "The dot-separated JSON path within the value of data to index, such as customer.id", i18n.StringType)
+
+	ConfigDatabaseSqlite3MaxConnIdleTime      = ffc("config.database.sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3MaxConnLifetime      = ffc("config.database.sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3MaxConns             = ffc("config.database.sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigDatabaseSqlite3MaxIdleConns         = ffc("config.database.sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigDatabaseSqlite3URL                  = ffc("config.database.sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigDatabaseSqlite3ValueIndexes         = ffc("config.database.sqlite3.valueIndexes", "A list of paths within the value of data to create database indexes for, to speed up queries that filter on those paths. Each path is indexed both as text and as a number", "List "+i18n.StringType)
+	ConfigDatabaseSqlite3ValueIndexesDatatype = ffc("config.database.sqlite3.valueIndexes[].datatype", "Optional name of a datatype, to only index data of that datatype", i18n.StringType)
+	ConfigDatabaseSqlite3ValueIndexesPath     = ffc("config.database.sqlite3.valueIndexes[].path", "The dot-separated JSON path within the value of data to index, such as customer.id", i18n.StringType)
 
 	ConfigDataexchangeType = ffc("config.dataexchange.type", "The Data Exchange plugin to use", i18n.StringType)
 
@@ -237,6 +266,24 @@ var (
 
 	ConfigPluginDataexchangeFfdxProxyURL = ffc("config.plugins.dataexchange[].ffdx.proxy.url", "Optional HTTP proxy server to use when connecting to the Data Exchange", "URL "+i18n.StringType)
 
+	ConfigPluginDataexchangeP2PEndpoint                  = ffc("config.plugins.dataexchange[].p2p.endpoint", "The HTTPS endpoint advertised to other members of the network. Defaults to the listener address", "URL "+i18n.StringType)
+	ConfigPluginDataexchangeP2PServerAddress             = ffc("config.plugins.dataexchange[].p2p.server.address", "The network interface the data exchange server listens on for deliveries from peers", i18n.StringType)
+	ConfigPluginDataexchangeP2PServerPort                = ffc("config.plugins.dataexchange[].p2p.server.port", "The port the data exchange server listens on for deliveries from peers", i18n.IntType)
+	ConfigPluginDataexchangeP2PTLSCertFile               = ffc("config.plugins.dataexchange[].p2p.tls.certFile", "The certificate presented to peers, in both directions. Its fingerprint identifies this node to the network", i18n.StringType)
+	ConfigPluginDataexchangeP2PTLSKeyFile                = ffc("config.plugins.dataexchange[].p2p.tls.keyFile", "The private key for the certificate presented to peers", i18n.StringType)
+	ConfigPluginDataexchangeP2PStoragePath               = ffc("config.plugins.dataexchange[].p2p.storage.path", "The local directory used to store blobs, and the queues of deliveries to peers", i18n.StringType)
+	ConfigPluginDataexchangeP2PManifestEnabled           = ffc("config.plugins.dataexchange[].p2p.manifestEnabled", "Determines whether to require+validate a manifest from other DX instances in the network", i18n.BooleanType)
+	ConfigPluginDataexchangeP2PAckTimeout                = ffc("config.plugins.dataexchange[].p2p.ackTimeout", "How long to wait for FireFly to process a delivery from a peer, before asking the peer to retry", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2PDeliveredRetention        = ffc("config.plugins.dataexchange[].p2p.deliveredRetention", "How long a record of each delivery received from a peer is kept, so that retries of the delivery are not passed to FireFly again", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2POutboundRequestTimeout    = ffc("config.plugins.dataexchange[].p2p.outbound.requestTimeout", "The timeout for each attempt to deliver a message to a peer", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2POutboundBlobTimeout       = ffc("config.plugins.dataexchange[].p2p.outbound.blobTimeout", "The timeout for each attempt to transfer a blob to a peer, including sending the content of the blob", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2POutboundRetryInitialDelay = ffc("config.plugins.dataexchange[].p2p.outbound.retry.initialDelay", "The initial delay before retrying a delivery to a peer", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2POutboundRetryMaxDelay     = ffc("config.plugins.dataexchange[].p2p.outbound.retry.maxDelay", "The maximum delay between retries of a delivery to a peer", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2POutboundRetryFactor       = ffc("config.plugins.dataexchange[].p2p.outbound.retry.factor", "The factor by which the delay increases when retrying a delivery to a peer", i18n.FloatType)
+	ConfigPluginDataexchangeP2PEventRetryInitialDelay    = ffc("config.plugins.dataexchange[].p2p.eventRetry.initialDelay", "The initial delay before retrying the dispatch of a received delivery to FireFly", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2PEventRetryMaxDelay        = ffc("config.plugins.dataexchange[].p2p.eventRetry.maxDelay", "The maximum delay between retries of the dispatch of a received delivery to FireFly", i18n.TimeDurationType)
+	ConfigPluginDataexchangeP2PEventRetryFactor          = ffc("config.plugins.dataexchange[].p2p.eventRetry.factor", "The factor by which the delay increases when retrying the dispatch of a received delivery to FireFly", i18n.FloatType)
+
 	ConfigDebugPort    = ffc("config.debug.port", "An HTTP port on which to enable the go debugger", i18n.IntType)
 	ConfigDebugAddress = ffc("config.debug.address", "The HTTP interface the go debugger binds to", i18n.StringType)
 
@@ -267,9 +314,11 @@ var (
 	ConfigHTTPReadTimeout  = ffc("config.http.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
 	ConfigHTTPWriteTimeout = ffc("config.http.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
 
-	ConfigPluginIdentity     = ffc("config.plugins.identity", "The list of available Identity plugins", i18n.StringType)
-	ConfigPluginIdentityType = ffc("config.plugins.identity[].type", "The type of a configured Identity plugin", i18n.StringType)
-	ConfigPluginIdentityName = ffc("config.plugins.identity[].name", "The name of a configured Identity plugin", i18n.StringType)
+	ConfigPluginIdentity               = ffc("config.plugins.identity", "The list of available Identity plugins", i18n.StringType)
+	ConfigPluginIdentityType           = ffc("config.plugins.identity[].type", "The type of a configured Identity plugin", i18n.StringType)
+	ConfigPluginIdentityName           = ffc("config.plugins.identity[].name", "The name of a configured Identity plugin", i18n.StringType)
+	ConfigPluginIdentityDIDWebURL      = ffc("config.plugins.identity[].did.web.url", "Optional URL of a server to resolve did:web DIDs against, instead of the domain of each DID. The domain is passed as the first segment of the path", "URL "+i18n.StringType)
+	ConfigPluginIdentityDIDWebProxyURL = ffc("config.plugins.identity[].did.web.proxy.url", "Optional HTTP proxy server to use when resolving did:web DIDs", "URL "+i18n.StringType)
 
 	ConfigIdentityManagerLegacySystemIdentitites = ffc("config.identity.manager.legacySystemIdentities", "Whether the identity manager should resolve legacy identities registered on the ff_system namespace", i18n.BooleanType)
 
@@ -314,6 +363,11 @@ var (
 	ConfigNamespacesMultipartyContractFirstEvent = ffc("config.namespaces.predefined[].multiparty.contract[].firstEvent", "The first event the contract should process. Valid options are `oldest` or `newest`", i18n.StringType)
 	ConfigNamespacesMultipartyContractLocation   = ffc("config.namespaces.predefined[].multiparty.contract[].location", "A blockchain-specific contract location. For example, an Ethereum contract address, or a Fabric chaincode name and channel", i18n.StringType)
 	ConfigNamespacesMultipartyContractOptions    = ffc("config.namespaces.predefined[].multiparty.contract[].options", "Blockchain-specific contract options", i18n.StringType)
+	ConfigNamespacesRetentionMessages            = ffc("config.namespaces.predefined[].retention.messages", "How long to keep confirmed and rejected messages after confirmation, along with any data and blobs only they reference. Messages are kept forever if not set", i18n.TimeDurationType)
+	ConfigNamespacesRetentionEvents              = ffc("config.namespaces.predefined[].retention.events", "How long to keep events that have been delivered to all durable subscriptions. Events are kept forever if not set", i18n.TimeDurationType)
+	ConfigNamespacesRetentionOperations          = ffc("config.namespaces.predefined[].retention.operations", "How long to keep succeeded and failed operations after their last update. Operations are kept forever if not set", i18n.TimeDurationType)
+	ConfigNamespacesRetentionBlockchainEvents    = ffc("config.namespaces.predefined[].retention.blockchainEvents", "How long to keep blockchain events. Blockchain events are kept forever if not set", i18n.TimeDurationType)
+	ConfigNamespacesRetentionTokenTransfers      = ffc("config.namespaces.predefined[].retention.tokenTransfers", "How long to keep token transfers. Token transfers are kept forever if not set", i18n.TimeDurationType)
 
 	ConfigNodeDescription = ffc("config.node.description", "The description of this FireFly node", i18n.StringType)
 	ConfigNodeName        = ffc("config.node.name", "The name of this FireFly node", i18n.StringType)
@@ -329,10 +383,17 @@ var (
 	ConfigOrgKey         = ffc("config.org.key", "The signing key allocated to the organization (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 	ConfigOrgName        = ffc("config.org.name", "The name of the organization to which this FireFly node belongs (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 
-	ConfigPrivatemessagingBatchAgentTimeout = ffc("config.privatemessaging.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.TimeDurationType)
-	ConfigPrivatemessagingBatchPayloadLimit = ffc("config.privatemessaging.batch.payloadLimit", "The maximum payload size of a private message Data Exchange payload", i18n.ByteSizeType)
-	ConfigPrivatemessagingBatchSize         = ffc("config.privatemessaging.batch.size", "The maximum number of messages in a batch for private messages", i18n.IntType)
-	ConfigPrivatemessagingBatchTimeout      = ffc("config.privatemessaging.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigPrivatemessagingBatchAgentTimeout   = ffc("config.privatemessaging.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.TimeDurationType)
+	ConfigPrivatemessagingBatchPayloadLimit   = ffc("config.privatemessaging.batch.payloadLimit", "The maximum payload size of a private message Data Exchange payload", i18n.ByteSizeType)
+	ConfigPrivatemessagingBatchSize           = ffc("config.privatemessaging.batch.size", "The maximum number of messages in a batch for private messages", i18n.IntType)
+	ConfigPrivatemessagingBatchTimeout        = ffc("config.privatemessaging.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigPrivatemessagingEncryptionEnabled   = ffc("config.privatemessaging.encryption.enabled", "Load the encryption key of this node, so it can receive private messages that are encrypted end-to-end, and publish its encryption public key on its node identity", i18n.BooleanType)
+	ConfigPrivatemessagingEncryptionKeyFile   = ffc("config.privatemessaging.encryption.keyFile", "A file containing the X25519 private key of this node, hex encoded, used to decrypt private message batches sent to it", i18n.StringType)
+	ConfigPrivatemessagingReplyCheckInterval  = ffc("config.privatemessaging.replyCheck.interval", "How often to check for request messages that have passed their reply deadline without a reply, and emit a message_reply_overdue event for them", i18n.TimeDurationType)
+	ConfigPrivatemessagingReplyCheckBatchSize = ffc("config.privatemessaging.replyCheck.batchSize", "The maximum number of overdue request messages to process in each database round trip", i18n.IntType)
+
+	ConfigRetentionInterval  = ffc("config.retention.interval", "How often to check each namespace for records that have passed their retention period, or messages that have passed their expiry time", i18n.TimeDurationType)
+	ConfigRetentionBatchSize = ffc("config.retention.batchSize", "The maximum number of records to delete in each database round trip when purging expired records", i18n.IntType)
 
 	ConfigSharedstorageType                = ffc("config.sharedstorage.type", "The Shared Storage plugin to use", i18n.StringType)
 	ConfigSharedstorageIpfsAPIURL          = ffc("config.sharedstorage.ipfs.api.url", "The URL for the IPFS API", "URL "+i18n.StringType)
@@ -347,9 +408,20 @@ var (
 	ConfigPluginSharedstorageIpfsAPIProxyURL     = ffc("config.plugins.sharedstorage[].ipfs.api.proxy.url", "Optional HTTP proxy server to use when connecting to the IPFS API", "URL "+i18n.StringType)
 	ConfigPluginSharedstorageIpfsGatewayURL      = ffc("config.plugins.sharedstorage[].ipfs.gateway.url", "The URL for the IPFS Gateway", "URL "+i18n.StringType)
 	ConfigPluginSharedstorageIpfsGatewayProxyURL = ffc("config.plugins.sharedstorage[].ipfs.gateway.proxy.url", "Optional HTTP proxy server to use when connecting to the IPFS Gateway", "URL "+i18n.StringType)
-
-	ConfigSubscriptionMax               = ffc("config.subscription.max", "The maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)", i18n.IntType)
-	ConfigSubscriptionDefaultsBatchSize = ffc("config.subscription.defaults.batchSize", "Default read ahead to enable for subscriptions that do not explicitly configure readahead", i18n.IntType)
+	ConfigPluginSharedstorageFilesystemPath      = ffc("config.plugins.sharedstorage[].filesystem.path", "The directory in which payloads are stored, which must be shared by all members using it", i18n.StringType)
+	ConfigPluginSharedstorageS3URL               = ffc("config.plugins.sharedstorage[].s3.url", "The URL of the S3 compatible object store endpoint. Requests use path style addressing", "URL "+i18n.StringType)
+	ConfigPluginSharedstorageS3ProxyURL          = ffc("config.plugins.sharedstorage[].s3.proxy.url", "Optional HTTP proxy server to use when connecting to the object store", "URL "+i18n.StringType)
+	ConfigPluginSharedstorageS3Bucket            = ffc("config.plugins.sharedstorage[].s3.bucket", "The bucket in which payloads are stored", i18n.StringType)
+	ConfigPluginSharedstorageS3Region            = ffc("config.plugins.sharedstorage[].s3.region", "The region used to sign requests to the object store", i18n.StringType)
+	ConfigPluginSharedstorageS3AccessKeyID       = ffc("config.plugins.sharedstorage[].s3.accessKeyID", "The access key ID used to sign requests to the object store. Requests are not signed if this is not set", i18n.StringType)
+	ConfigPluginSharedstorageS3SecretAccessKey   = ffc("config.plugins.sharedstorage[].s3.secretAccessKey", "The secret access key used to sign requests to the object store", i18n.StringType)
+	ConfigPluginSharedstorageS3TempDir           = ffc("config.plugins.sharedstorage[].s3.tempDir", "The directory in which each payload is staged while its hash is calculated, before it is uploaded. Defaults to the temporary directory of the system", i18n.StringType)
+
+	ConfigSubscriptionMax                          = ffc("config.subscription.max", "The maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)", i18n.IntType)
+	ConfigSubscriptionDefaultsBatchSize            = ffc("config.subscription.defaults.batchSize", "Default read ahead to enable for subscriptions that do not explicitly configure readahead", i18n.IntType)
+	ConfigSubscriptionDefaultsBatchDeliverySize    = ffc("config.subscription.defaults.batchDelivery.size", "Default maximum number of events in a batch, for subscriptions with batch delivery enabled", i18n.IntType)
+	ConfigSubscriptionDefaultsBatchDeliveryTimeout = ffc("config.subscription.defaults.batchDelivery.timeout", "Default maximum time to wait for a batch to fill before it is delivered, for subscriptions with batch delivery enabled", i18n.TimeDurationType)
+	ConfigSubscriptionReplayTimeout                = ffc("config.subscription.replayTimeout", "Maximum time to wait for the replay of a dead letter to be delivered, including any retries, before the dead letter is kept for a later replay", i18n.TimeDurationType)
 
 	ConfigTokensName     = ffc("config.tokens[].name", "A name to identify this token plugin", i18n.StringType)
 	ConfigTokensPlugin   = ffc("config.tokens[].plugin", "The type of the token plugin to use", i18n.StringType)
@@ -379,8 +451,14 @@ var (
 	ConfigPluginsAuthName = ffc("config.plugins.auth[].name", "The name of the auth plugin to use", i18n.StringType)
 	ConfigPluginsAuthType = ffc("config.plugins.auth[].type", "The type of the auth plugin to use", i18n.StringType)
 
+	ConfigPluginsEventKafkaClusterID            = ffc("config.events.kafka.clusterId", "The ID of the Kafka cluster, as used in the path of the Kafka REST Proxy v3 produce API", i18n.StringType)
+	ConfigPluginsEventKafkaURL                  = ffc("config.events.kafka.url", "The URL of the Kafka REST Proxy, which must support the v3 produce API", i18n.StringType)
+	ConfigPluginsEventKafkaTopic                = ffc("config.events.kafka.topic", "The default topic to publish events to, for subscriptions that do not set a topic in their options", i18n.StringType)
 	ConfigPluginsEventSystemReadAhead           = ffc("config.events.system.readAhead", "", i18n.IgnoredType)
 	ConfigPluginsEventWebhooksURL               = ffc("config.events.webhooks.url", "", i18n.IgnoredType)
+	ConfigPluginsEventWebhooksTLSConfigsName    = ffc("config.events.webhooks.tlsConfigs[].name", "The name of the TLS configuration, which subscriptions reference in their tlsConfigName option", i18n.StringType)
+	ConfigPluginsEventWebhooksSigningName       = ffc("config.events.webhooks.signingSecrets[].name", "The name of the signing secret, which subscriptions reference in their signing.secretName option", i18n.StringType)
+	ConfigPluginsEventWebhooksSigningSecret     = ffc("config.events.webhooks.signingSecrets[].secret", "The shared secret used as the HMAC key to sign webhook requests", i18n.StringType)
 	ConfigPluginsEventWebSocketsReadBufferSize  = ffc("config.events.websockets.readBufferSize", "WebSocket read buffer size", i18n.ByteSizeType)
 	ConfigPluginsEventWebSocketsWriteBufferSize = ffc("config.events.websockets.writeBufferSize", "WebSocket write buffer size", i18n.ByteSizeType)
 )
@@ -288,4 +288,136 @@ var (
 	MsgCannotDeletePublished              = ffe("FF10449", "Cannot delete an item that has been published", 409)
 	MsgAlreadyPublished                   = ffe("FF10450", "Item has already been published", 409)
 	MsgContractInterfaceNotPublished      = ffe("FF10451", "Contract interface '%s' has not been published", 409)
+	MsgKafkaTopicEmpty                    = ffe("FF10452", "Kafka subscription option 'topic' cannot be empty, unless a default topic is configured", 400)
+	MsgKafkaInvalidKeyField               = ffe("FF10453", "Kafka subscription option 'key' must be one of 'topic', 'type', 'reference', 'tx' or 'none': %s", 400)
+	MsgKafkaInvalidPartition              = ffe("FF10454", "Kafka subscription option 'partition' must be a non-negative integer: %v", 400)
+	MsgKafkaInvalidStringMap              = ffe("FF10455", "Kafka subscription option '%s' must be map of string values. %s=%T", 400)
+	MsgKafkaRESTErr                       = ffe("FF10456", "Error from Kafka REST proxy: %s")
+	MsgKafkaPublishFailed                 = ffe("FF10457", "Kafka publish failed with error code %d: %s")
+	MsgDeadLetterSubNotActive             = ffe("FF10458", "Subscription '%s' is not currently active on any connection, so its dead letters cannot be replayed", 409)
+	MsgWebhookInvalidRetryOption          = ffe("FF10459", "Invalid webhook retry option '%s': %v", 400)
+	MsgWebhookFailedStatus                = ffe("FF10460", "Webhook returned failure status %d")
+	MsgDeadLetterEventNotFound            = ffe("FF10461", "Event '%s' for dead letter '%s' not found", 404)
+	MsgWebhookTLSConfigNotFound           = ffe("FF10462", "TLS config '%s' is not configured on the webhooks plugin", 400)
+	MsgWebhookTLSConfigDuplicate          = ffe("FF10463", "Duplicate TLS config name '%s' in the webhooks plugin config")
+	MsgWebhookInvalidSigningAlgorithm     = ffe("FF10464", "Invalid webhook signing algorithm '%s'", 400)
+	MsgBatchDeliveryNotSupported          = ffe("FF10465", "Batch delivery not supported by transport '%s'", 400)
+	MsgInvalidSubscriptionBatchTimeout    = ffe("FF10466", "Invalid batch timeout '%s' in subscription options", 400)
+	MsgWebhookBatchReply                  = ffe("FF10467", "Webhook subscriptions cannot combine batch delivery with reply mode", 400)
+	MsgFilterExpressionUnexpected         = ffe("FF10468", "Invalid filter expression - unexpected '%s' at position %d", 400)
+	MsgFilterExpressionUnterminated       = ffe("FF10469", "Invalid filter expression - unterminated string at position %d", 400)
+	MsgFilterExpressionUnexpectedEnd      = ffe("FF10470", "Invalid filter expression - unexpected end of expression", 400)
+	MsgFilterExpressionRegexpLiteral      = ffe("FF10471", "Invalid filter expression - the right side of '=~' must be a string literal, at position %d", 400)
+	MsgFilterExpressionBadNumber          = ffe("FF10572", "Invalid filter expression - invalid number '%s' at position %d", 400)
+	MsgDIDInvalid                         = ffe("FF10472", "Invalid DID '%s'", 400)
+	MsgDIDKeyUnsupported                  = ffe("FF10473", "Unsupported public key type in did:key DID '%s'", 400)
+	MsgDIDWebRESTErr                      = ffe("FF10474", "Error resolving did:web DID")
+	MsgDIDDocumentMismatch                = ffe("FF10475", "Resolved DID document with id '%s' for DID '%s'")
+	MsgDIDKeyNotVerificationMethod        = ffe("FF10476", "Key '%s' is not a verification method of DID '%s'", 400)
+	MsgSharedStorageInvalidPayloadRef     = ffe("FF10477", "Invalid shared storage payload reference '%s'", 400)
+	MsgSharedStorageFileErr               = ffe("FF10478", "Shared storage file system error")
+	MsgSharedStoragePayloadNotFound       = ffe("FF10479", "Payload '%s' not found in shared storage", 404)
+	MsgS3RESTErr                          = ffe("FF10480", "Error from S3 shared storage")
+	MsgP2PDXTLSLoadFailed                 = ffe("FF10481", "Failed to load the TLS certificate and key for the p2p data exchange")
+	MsgP2PDXStorageErr                    = ffe("FF10482", "Data exchange storage error")
+	MsgP2PDXInvalidPeer                   = ffe("FF10483", "Invalid peer information for data exchange peer '%s'", 400)
+	MsgP2PDXDeliveryFailed                = ffe("FF10484", "Delivery to data exchange peer '%s' failed with status %d: %s")
+	MsgP2PDXInvalidPayloadRef             = ffe("FF10485", "Invalid data exchange blob reference '%s'", 400)
+	MsgP2PDXListenFailed                  = ffe("FF10486", "Failed to listen on '%s' for data exchange peers")
+	MsgP2PDXUnauthorizedPeer              = ffe("FF10487", "Request from unknown or unauthenticated data exchange peer '%s'", 403)
+	MsgP2PDXUnknownRecipient              = ffe("FF10488", "Data exchange recipient '%s' is not a local peer", 404)
+	MsgP2PDXAckTimeout                    = ffe("FF10489", "Timed out waiting for the data exchange event to be processed", 503)
+	MsgP2PDXInvalidMessage                = ffe("FF10490", "Invalid message from data exchange peer '%s'", 400)
+	MsgEVMRPCErr                          = ffe("FF10491", "Error from EVM JSON-RPC node: %s")
+	MsgEVMRPCSignerErr                    = ffe("FF10492", "Error from EVM transaction signer: %s")
+	MsgEVMRPCInvalidSignerType            = ffe("FF10493", "Invalid EVM transaction signer type '%s'")
+	MsgEVMRPCInvalidOption                = ffe("FF10494", "Invalid transaction option '%s': %v", 400)
+	MsgEVMRPCInvalidFirstEvent            = ffe("FF10495", "Invalid first event '%s' - must be 'oldest', 'newest' or a block number", 400)
+	MsgEVMRPCInvalidContract              = ffe("FF10496", "Invalid contract for deployment: %s", 400)
+	MsgEVMRPCListenerNotFound             = ffe("FF10497", "Contract listener '%s' not found", 404)
+	MsgEVMRPCTransactionReverted          = ffe("FF10498", "Transaction %s reverted in block %d")
+	MsgEVMRPCCallReverted                 = ffe("FF10499", "Call reverted: %s")
+	MsgInvalidTokenBalancePoint           = ffe("FF10504", "Invalid %s '%s' for a point in the token balance history", 400)
+	MsgTokenTransferBatchEmpty            = ffe("FF10505", "A batch of token transfers must contain at least one item", 400)
+	MsgTokenTransferBatchItemInvalid      = ffe("FF10506", "Invalid token transfer at index %d of the batch: %s", 400)
+	MsgTokenBalanceInvalid                = ffe("FF10507", "Token connector returned an invalid balance '%s'")
+	MsgTokenBalanceQueryNotSupported      = ffe("FF10508", "Token connector '%s' does not support querying balances", 400)
+	MsgXSDInvalid                         = ffe("FF10509", "Invalid XML schema: %s", 400)
+	MsgXSDUnsupported                     = ffe("FF10510", "XML schema feature '%s' is not supported", 400)
+	MsgXSDUnresolvedReference             = ffe("FF10511", "XML schema %s '%s' is not defined", 400)
+	MsgXMLParseFailed                     = ffe("FF10512", "Invalid XML document: %s", 400)
+	MsgXMLUnexpectedElement               = ffe("FF10513", "Unexpected element '%s' at '%s'", 400)
+	MsgXMLIncompleteContent               = ffe("FF10514", "Element '%s' is missing required child elements", 400)
+	MsgXMLUnexpectedAttribute             = ffe("FF10515", "Unexpected attribute '%s' at '%s'", 400)
+	MsgXMLMissingAttribute                = ffe("FF10516", "Missing required attribute '%s' at '%s'", 400)
+	MsgXMLUnexpectedText                  = ffe("FF10517", "Unexpected text content at '%s'", 400)
+	MsgXMLInvalidValue                    = ffe("FF10518", "Value '%s' at '%s' is not a valid '%s'", 400)
+	MsgXMLValueRestriction                = ffe("FF10519", "Value '%s' at '%s' does not satisfy the '%s' restriction '%s'", 400)
+	MsgXMLDataInvalidPerSchema            = ffe("FF10520", "Data does not conform to the XML schema of datatype '%s': %s", 400)
+	MsgXMLDataNotString                   = ffe("FF10521", "Data for datatype '%s' must be a JSON string containing an XML document", 400)
+	MsgXSDNotString                       = ffe("FF10522", "Datatype '%s' value must be a JSON string containing an XML schema", 400)
+	MsgProtobufDatatypeInvalid            = ffe("FF10523", "Datatype '%s' value must contain a base64 encoded protobuf FileDescriptorSet and a message name: %s", 400)
+	MsgProtobufDataInvalid                = ffe("FF10524", "Data does not conform to protobuf message '%s' of datatype '%s': %s", 400)
+	MsgBlobEncryptionProviderUnknown      = ffe("FF10525", "Unknown blob encryption key provider '%s'")
+	MsgBlobEncryptionKeyInvalid           = ffe("FF10526", "Invalid blob encryption key in '%s': %s")
+	MsgBlobEncryptionKeyNotFound          = ffe("FF10527", "Blob encryption key '%s' is not available from key provider '%s'")
+	MsgBlobEncryptionFailed               = ffe("FF10528", "Failed to encrypt blob: %s")
+	MsgBlobDecryptionFailed               = ffe("FF10529", "Failed to decrypt blob: %s")
+	MsgBlobEncryptionUnsupported          = ffe("FF10530", "Blob encryption algorithm '%s' is not supported")
+	MsgBlobEncryptionDisabled             = ffe("FF10531", "Blob is encrypted with key '%s', but blob encryption is not enabled")
+	MsgMessageEncryptionKeyInvalid        = ffe("FF10532", "Invalid private message encryption key in '%s': %s")
+	MsgMessageEncryptionPublicKeyInvalid  = ffe("FF10533", "Invalid encryption public key '%s'")
+	MsgMessageEncryptionNoRecipientKey    = ffe("FF10534", "Node '%s' has not published an encryption public key, so private messages cannot be encrypted for it")
+	MsgMessageEncryptionFailed            = ffe("FF10535", "Failed to encrypt private message transport: %s")
+	MsgMessageDecryptionFailed            = ffe("FF10536", "Failed to decrypt private message transport: %s")
+	MsgMessageEncryptionDisabled          = ffe("FF10537", "Private message transport is encrypted for key '%s', but private message encryption is not enabled")
+	MsgMessageEncryptionUnsupported       = ffe("FF10538", "Private message encryption algorithm '%s' is not supported")
+	MsgMessageEncryptionWrongRecipient    = ffe("FF10539", "Private message transport is encrypted for key '%s', which is not the encryption key of this node")
+	MsgMessageExpiryInPast                = ffe("FF10540", "Message expiry time '%s' must be after the message creation time", 400)
+	MsgDataValuePathInvalid               = ffe("FF10541", "Invalid data value path '%s' - each dot-separated segment must contain only alphanumeric characters, '_' or '-'", 400)
+	MsgDataValueQueryUnsupported          = ffe("FF10542", "Database plugin '%s' does not support queries on paths within data values", 400)
+	MsgBlobUploadSizeInvalid              = ffe("FF10543", "Invalid blob upload size %d", 400)
+	MsgBlobUploadOffsetInvalid            = ffe("FF10544", "Invalid chunk offset '%s'", 400)
+	MsgBlobUploadOffsetMismatch           = ffe("FF10545", "Chunk offset %d does not match the %d bytes received so far for upload '%s'", 409)
+	MsgBlobUploadConflict                 = ffe("FF10546", "Upload '%s' was updated by another request - query the upload to find the current offset", 409)
+	MsgBlobUploadSizeExceeded             = ffe("FF10547", "Chunk exceeds the declared size of %d bytes for upload '%s'", 400)
+	MsgBlobUploadIncomplete               = ffe("FF10548", "Upload '%s' has received %d of its declared size of %d bytes", 409)
+	MsgBlobUploadHashMismatch             = ffe("FF10549", "Hash '%s' of the uploaded content does not match the expected hash '%s'", 400)
+	MsgBlobUploadHashStateInvalid         = ffe("FF10550", "Failed to restore the hash state of upload '%s'")
+	MsgBlobRangeNotSatisfiable            = ffe("FF10551", "Range '%s' cannot be satisfied for a blob of %d bytes", 416)
+	MsgReplyTimeoutInvalid                = ffe("FF10552", "Reply timeout '%s' must be greater than zero", 400)
+	MsgIdentityRevoked                    = ffe("FF10553", "Identity '%s' (%s) has been revoked", 400)
+	MsgDefRejectedIdentityRevoked         = ffe("FF10554", "Rejected %s '%s' - identity has been revoked: %s")
+	MsgDefRejectedVerifierNotActive       = ffe("FF10555", "Rejected %s '%s' - verifier '%s' is not an active verifier of the identity")
+	MsgIdentityKeyNotActive               = ffe("FF10556", "Key '%s' is not an active verifier of identity '%s'", 400)
+	MsgNetworkActionContractRequired      = ffe("FF10557", "A contract with a location is required for network action '%s'", 400)
+	MsgNetworkActionContractUnchanged     = ffe("FF10558", "Contract for network action '%s' is already the active contract", 400)
+	MsgNamespacePaused                    = ffe("FF10559", "Namespace '%s' has been paused by a network action")
+	MsgDefinitionGovernanceDisabled       = ffe("FF10560", "Definition governance is not enabled for namespace '%s'", 400)
+	MsgDefinitionProposalClosed           = ffe("FF10561", "Definition proposal '%s' is already %s", 409)
+	MsgDefRejectedProposalNotFound        = ffe("FF10562", "Rejected %s '%s' - proposal not found: %s")
+	MsgDefRejectedProposalClosed          = ffe("FF10563", "Rejected %s '%s' - proposal '%s' is already %s")
+	MsgDefRejectedNotRootOrg              = ffe("FF10564", "Rejected %s '%s' - author is not a root organization: %s")
+	MsgDefRejectedDuplicateVote           = ffe("FF10565", "Rejected %s '%s' - organization '%s' has already voted on proposal '%s'")
+	MsgDefRejectedNotPrivateDefinition    = ffe("FF10566", "Rejected %s '%s' - definition cannot be shared privately with a group")
+	MsgDefRejectedSponsorNotRootOrg       = ffe("FF10567", "Rejected %s '%s' - sponsor is not a root organization: %s")
+	MsgDefRejectedApplicationNotFound     = ffe("FF10568", "Rejected %s '%s' - application not found: %s")
+	MsgDefRejectedApplicationClosed       = ffe("FF10569", "Rejected %s '%s' - application '%s' is already %s")
+	MsgOrgApplicationClosed               = ffe("FF10570", "Application '%s' is already %s", 409)
+	MsgNotOrgApplicationSponsor           = ffe("FF10571", "Application '%s' can only be reviewed by its sponsor '%s'", 403)
+	MsgInvalidDefinitionQuorum            = ffe("FF10574", "Invalid definition quorum %d - must be zero or greater", 400)
+	MsgDefRejectedOrgOnboardingRequired   = ffe("FF10575", "Rejected %s '%s' - root organization '%s' must join the network through an application")
+	MsgOrgOnboardingRequired              = ffe("FF10576", "Organization onboarding is enabled for namespace '%s' - a root organization can only join the network through an application to an existing root organization", 409)
+	MsgWebhookSigningSecretNotFound       = ffe("FF10577", "Signing secret '%s' is not configured on the webhooks plugin", 400)
+	MsgWebhookSigningSecretDuplicate      = ffe("FF10578", "Duplicate signing secret name '%s' in the webhooks plugin config")
+	MsgWebhookInlineSigningSecret         = ffe("FF10579", "Webhook signing secrets cannot be set inline on a subscription - configure them in events.webhooks.signingSecrets and reference them with signing.secretName", 400)
+	MsgTokenTransferBatchTooLarge         = ffe("FF10580", "Batch of %d token transfers exceeds the limit of %d", 400)
+	MsgDefRejectedPrivateGoverned         = ffe("FF10581", "Rejected %s '%s' - definitions cannot be shared privately while network governance requires %d approvals")
+	MsgPrivateDefinitionGoverned          = ffe("FF10582", "Definitions cannot be shared privately with a group in namespace '%s' - network governance requires definitions to be approved by the root organizations", 409)
+	MsgEVMRPCCheckpointsReadFailed        = ffe("FF10583", "Failed to read listener checkpoints from '%s'")
+	MsgEVMRPCCheckpointsWriteFailed       = ffe("FF10584", "Failed to write listener checkpoints to '%s'")
+	MsgBlobUploadInvalidState             = ffe("FF10585", "Upload '%s' is %s", 409)
+	MsgTokenTransferBatchNotSupported     = ffe("FF10586", "Token connector '%s' does not support submitting batches of transfers", 400)
+	MsgDeadLetterReplayTimeout            = ffe("FF10587", "Timed out after %s waiting for the replay of dead letter '%s' to be delivered")
+	MsgMessageEncryptionBroadcast         = ffe("FF10588", "End-to-end encryption is only supported for private messages", 400)
 )
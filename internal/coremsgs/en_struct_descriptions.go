@@ -59,6 +59,8 @@ var (
 	MessageHeaderTag       = ffm("MessageHeader.tag", "The message tag indicates the purpose of the message to the applications that process it")
 	MessageHeaderDataHash  = ffm("MessageHeader.datahash", "A single hash representing all data in the message. Derived from the array of data ids+hashes attached to this message")
 	MessageTxParent        = ffm("MessageHeader.txparent", "The parent transaction that originally triggered this message")
+	MessageHeaderExpires   = ffm("MessageHeader.expires", "Optional time after which the message, and any data only it references, can be deleted by the retention purger")
+	MessageHeaderEncrypted = ffm("MessageHeader.encrypted", "For private messages, set to true to encrypt the batch containing the message end-to-end for each recipient node. Every recipient node must have published an encryption public key")
 
 	// Message field descriptions
 	MessageHeader         = ffm("Message.header", "The message header contains all fields that are used to build the message hash")
@@ -71,10 +73,12 @@ var (
 	MessagePins           = ffm("Message.pins", "For private messages, a unique pin hash:nonce is assigned for each topic")
 	MessageTransactionID  = ffm("Message.txid", "The ID of the transaction used to order/deliver this message")
 	MessageIdempotencyKey = ffm("Message.idempotencyKey", "An optional unique identifier for a message. Cannot be duplicated within a namespace, thus allowing idempotent submission of messages to the API. Local only - not transferred when the message is sent to other members of the network")
+	MessageReplyDeadline  = ffm("Message.replyDeadline", "For request messages sent with a replyTimeout, the time by which a reply is expected. Cleared once the deadline has been checked, with a message_reply_overdue event emitted if no reply was confirmed in time. Local only - not transferred when the message is sent to other members of the network")
 
 	// MessageInOut field descriptions
-	MessageInOutData  = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
-	MessageInOutGroup = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutData         = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
+	MessageInOutGroup        = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutReplyTimeout = ffm("MessageInOut.replyTimeout", "For private messages, how long to wait for a reply correlated to this message by its cid. If no reply is confirmed in time, a message_reply_overdue event is emitted, and synchronous request/reply calls return an error")
 
 	// InputGroup field descriptions
 	InputGroupName    = ffm("InputGroup.name", "Optional name for the group. Allows you to have multiple separate groups with the same list of participants")
@@ -129,6 +133,30 @@ var (
 	DataBlob      = ffm("Data.blob", "An optional hash reference to a binary blob attachment")
 	DataPublic    = ffm("Data.public", "If the JSON value has been published to shared storage, this field is the id of the data in the shared storage plugin (IPFS hash etc.)")
 
+	// BlobUpload field descriptions
+	BlobUploadID        = ffm("BlobUpload.id", "The UUID of the resumable blob upload")
+	BlobUploadNamespace = ffm("BlobUpload.namespace", "The namespace of the blob upload")
+	BlobUploadState     = ffm("BlobUpload.state", "The state of the upload. Chunks are accepted while it is receiving, and once finalized it is finalizing until the data item is complete, or has failed")
+	BlobUploadSize      = ffm("BlobUpload.size", "The total size of the blob declared when the upload was created, if any")
+	BlobUploadReceived  = ffm("BlobUpload.received", "The number of bytes received so far, which is the offset of the next chunk")
+	BlobUploadData      = ffm("BlobUpload.data", "The ID of the data item the upload is finalized into, which exists once the upload is complete")
+	BlobUploadError     = ffm("BlobUpload.error", "The reason the upload failed to finalize. A failed upload can be finalized again")
+	BlobUploadCreated   = ffm("BlobUpload.created", "The time the upload was created")
+	BlobUploadUpdated   = ffm("BlobUpload.updated", "The time the upload last changed")
+	BlobUploadExpires   = ffm("BlobUpload.expires", "The time after which the upload is purged, along with any chunks. A complete upload remains until then, to report its data item")
+
+	// BlobUploadInput field descriptions
+	BlobUploadInputSize = ffm("BlobUploadInput.size", "The total size of the blob in bytes, if known. When set, chunks beyond this size are rejected, and the upload can only be finalized once all bytes are received")
+
+	// BlobUploadFinalize field descriptions
+	BlobUploadFinalizeValidator = ffm("BlobUploadFinalize.validator", "The data validator type to use for the data item")
+	BlobUploadFinalizeDatatype  = ffm("BlobUploadFinalize.datatype", "The optional datatype to use for validation of the data item")
+	BlobUploadFinalizeValue     = ffm("BlobUploadFinalize.value", "The JSON value to store in the data item, alongside the blob")
+	BlobUploadFinalizeHash      = ffm("BlobUploadFinalize.hash", "The expected SHA-256 hash of the whole blob. When set, the upload is rejected if the content received does not match")
+	BlobUploadFinalizeAutoMeta  = ffm("BlobUploadFinalize.autometa", "When set, FireFly will automatically generate JSON metadata with the filename and mimetype in the value of the data item")
+	BlobUploadFinalizeFilename  = ffm("BlobUploadFinalize.filename", "The filename to include in the automatically generated metadata")
+	BlobUploadFinalizeMimetype  = ffm("BlobUploadFinalize.mimetype", "The mimetype to include in the automatically generated metadata")
+
 	// DatatypeRef field descriptions
 	DatatypeRefName    = ffm("DatatypeRef.name", "The name of the datatype")
 	DatatypeRefVersion = ffm("DatatypeRef.version", "The version of the datatype. Semantic versioning is encouraged, such as v1.0.1")
@@ -136,6 +164,7 @@ var (
 	// Datatype field descriptions
 	DatatypeID        = ffm("Datatype.id", "The UUID of the datatype")
 	DatatypeMessage   = ffm("Datatype.message", "The UUID of the broadcast message that was used to publish this datatype to the network")
+	DatatypeGroup     = ffm("Datatype.group", "The privacy group the datatype was shared with, if it was not published to the whole network")
 	DatatypeValidator = ffm("Datatype.validator", "The validator that should be used to verify this datatype")
 	DatatypeNamespace = ffm("Datatype.namespace", "The namespace of the datatype. Data resources can only be created referencing datatypes in the same namespace")
 	DatatypeName      = ffm("Datatype.name", "The name of the datatype")
@@ -229,6 +258,7 @@ var (
 	ContractAPIName        = ffm("ContractAPI.name", "The name that is used in the URL to access the API")
 	ContractAPINetworkName = ffm("ContractAPI.networkName", "The published name of the API within the multiparty network")
 	ContractAPIMessage     = ffm("ContractAPI.message", "The UUID of the broadcast message that was used to publish this API to the network")
+	ContractAPIGroup       = ffm("ContractAPI.group", "The privacy group the API was shared with, if it was not published to the whole network")
 	ContractAPIURLs        = ffm("ContractAPI.urls", "The URLs to use to access the API")
 	ContractAPIPublished   = ffm("ContractAPI.published", "Indicates if the API is published to other members of the multiparty network")
 
@@ -321,6 +351,8 @@ var (
 	DIDDocumentID                 = ffm("DIDDocument.id", "See https://www.w3.org/TR/did-core/#did-document-properties")
 	DIDDocumentAuthentication     = ffm("DIDDocument.authentication", "See https://www.w3.org/TR/did-core/#did-document-properties")
 	DIDDocumentVerificationMethod = ffm("DIDDocument.verificationMethod", "See https://www.w3.org/TR/did-core/#did-document-properties")
+	DIDDocumentKeyAgreement       = ffm("DIDDocument.keyAgreement", "See https://www.w3.org/TR/did-core/#key-agreement")
+	DIDDocumentDeactivated        = ffm("DIDDocument.deactivated", "Set to true when the identity has been revoked. See https://www.w3.org/TR/did-core/#did-document-metadata")
 
 	// DIDVerificationMethod field descriptions
 	DIDVerificationMethodID                  = ffm("DIDVerificationMethod.id", "See https://www.w3.org/TR/did-core/#service-properties")
@@ -329,6 +361,8 @@ var (
 	DIDVerificationMethodBlockchainAccountID = ffm("DIDVerificationMethod.blockchainAcountId", "For blockchains like Ethereum that represent signing identities directly by their public key summarized in an account string")
 	DIDVerificationMethodMSPIdentityString   = ffm("DIDVerificationMethod.mspIdentityString", "For Hyperledger Fabric where the signing identity is represented by an MSP identifier (containing X509 certificate DN strings) that were validated by your local MSP")
 	DIDVerificationMethodDataExchangePeerID  = ffm("DIDVerificationMethod.dataExchangePeerID", "A string provided by your Data Exchange plugin, that it uses a technology specific mechanism to validate against when messages arrive from this identity")
+	DIDVerificationMethodPublicKeyMultibase  = ffm("DIDVerificationMethod.publicKeyMultibase", "For key agreement keys, such as the X25519 key a node uses to receive end-to-end encrypted private messages, the multibase encoded public key")
+	DIDVerificationMethodRevoked             = ffm("DIDVerificationMethod.revoked", "The time the verifier was revoked. Revoked verification methods are not listed for authentication or key agreement")
 
 	// Event field descriptions
 	EventID          = ffm("Event.id", "The UUID assigned to this event by your local FireFly node")
@@ -342,22 +376,25 @@ var (
 	EventCreated     = ffm("Event.created", "The time the event was emitted. Not guaranteed to be unique, or to increase between events in the same order as the final sequence events are delivered to your application. As such, the 'sequence' field should be used instead of the 'created' field for querying events in the exact order they are delivered to applications")
 
 	// EnrichedEvent field descriptions
-	EnrichedEventBlockchainEvent   = ffm("EnrichedEvent.blockchainEvent", "A blockchain event if referenced by the FireFly event")
-	EnrichedEventContractAPI       = ffm("EnrichedEvent.contractAPI", "A Contract API if referenced by the FireFly event")
-	EnrichedEventContractInterface = ffm("EnrichedEvent.contractInterface", "A Contract Interface (FFI) if referenced by the FireFly event")
-	EnrichedEventDatatype          = ffm("EnrichedEvent.datatype", "A Datatype if referenced by the FireFly event")
-	EnrichedEventIdentity          = ffm("EnrichedEvent.identity", "An Identity if referenced by the FireFly event")
-	EnrichedEventMessage           = ffm("EnrichedEvent.message", "A Message if  referenced by the FireFly event")
-	EnrichedEventNamespaceDetails  = ffm("EnrichedEvent.namespaceDetails", "Full resource detail of a Namespace if referenced by the FireFly event")
-	EnrichedEventTokenApproval     = ffm("EnrichedEvent.tokenApproval", "A Token Approval if referenced by the FireFly event")
-	EnrichedEventTokenPool         = ffm("EnrichedEvent.tokenPool", "A Token Pool if referenced by the FireFly event")
-	EnrichedEventTokenTransfer     = ffm("EnrichedEvent.tokenTransfer", "A Token Transfer if referenced by the FireFly event")
-	EnrichedEventTransaction       = ffm("EnrichedEvent.transaction", "A Transaction if associated with the FireFly event")
+	EnrichedEventBlockchainEvent      = ffm("EnrichedEvent.blockchainEvent", "A blockchain event if referenced by the FireFly event")
+	EnrichedEventContractAPI          = ffm("EnrichedEvent.contractAPI", "A Contract API if referenced by the FireFly event")
+	EnrichedEventContractInterface    = ffm("EnrichedEvent.contractInterface", "A Contract Interface (FFI) if referenced by the FireFly event")
+	EnrichedEventDatatype             = ffm("EnrichedEvent.datatype", "A Datatype if referenced by the FireFly event")
+	EnrichedEventDefinitionProposal   = ffm("EnrichedEvent.definitionProposal", "A definition proposal if referenced by the FireFly event")
+	EnrichedEventIdentity             = ffm("EnrichedEvent.identity", "An Identity if referenced by the FireFly event")
+	EnrichedEventMessage              = ffm("EnrichedEvent.message", "A Message if  referenced by the FireFly event")
+	EnrichedEventNamespaceDetails     = ffm("EnrichedEvent.namespaceDetails", "Full resource detail of a Namespace if referenced by the FireFly event")
+	EnrichedEventTokenApproval        = ffm("EnrichedEvent.tokenApproval", "A Token Approval if referenced by the FireFly event")
+	EnrichedEventTokenPool            = ffm("EnrichedEvent.tokenPool", "A Token Pool if referenced by the FireFly event")
+	EnrichedEventTokenTransfer        = ffm("EnrichedEvent.tokenTransfer", "A Token Transfer if referenced by the FireFly event")
+	EnrichedEventTokenBalanceMismatch = ffm("EnrichedEvent.tokenBalanceMismatch", "A Token Balance Mismatch if referenced by the FireFly event")
+	EnrichedEventTransaction          = ffm("EnrichedEvent.transaction", "A Transaction if associated with the FireFly event")
 
 	// IdentityMessages field descriptions
 	IdentityMessagesClaim        = ffm("IdentityMessages.claim", "The UUID of claim message")
 	IdentityMessagesVerification = ffm("IdentityMessages.verification", "The UUID of claim message. Unset for root organization identities")
 	IdentityMessagesUpdate       = ffm("IdentityMessages.update", "The UUID of the most recently applied update message. Unset if no updates have been confirmed")
+	IdentityMessagesRevocation   = ffm("IdentityMessages.revocation", "The UUID of the message that revoked this identity. Unset if the identity has not been revoked")
 
 	// Identity field descriptions
 	IdentityID        = ffm("Identity.id", "The UUID of the identity")
@@ -369,18 +406,26 @@ var (
 	IdentityMessages  = ffm("Identity.messages", "References to the broadcast messages that established this identity and proved ownership of the associated verifiers (keys)")
 	IdentityCreated   = ffm("Identity.created", "The creation time of the identity")
 	IdentityUpdated   = ffm("Identity.updated", "The last update time of the identity profile")
+	IdentityRevoked   = ffm("Identity.revoked", "The time the identity was revoked. Unset for active identities")
 
 	// IdentityProfile field descriptions
 	IdentityProfileProfile     = ffm("IdentityProfile.profile", "A set of metadata for the identity. Part of the updatable profile information of an identity")
 	IdentityProfileDescription = ffm("IdentityProfile.description", "A description of the identity. Part of the updatable profile information of an identity")
 
 	// IdentityWithVerifiers field descriptions
-	IdentityWithVerifiersVerifiers = ffm("IdentityWithVerifiers.verifiers", "The verifiers, such as blockchain signing keys, that have been bound to this identity and can be used to prove data orignates from that identity")
+	IdentityWithVerifiersVerifiers        = ffm("IdentityWithVerifiers.verifiers", "The verifiers, such as blockchain signing keys, that have been bound to this identity and can be used to prove data orignates from that identity")
+	IdentityWithVerifiersRevokedVerifiers = ffm("IdentityWithVerifiers.revokedVerifiers", "The verifiers that were previously bound to this identity, but have since been revoked")
 
 	// IdentityCreateDTO field descriptions
 	IdentityCreateDTOParent = ffm("IdentityCreateDTO.parent", "On input the parent can be specified directly as the UUID of and existing identity, or as a DID to resolve to that identity, or an organization name. The parent must already have been registered, and its blockchain signing key must be available to the local node to sign the verification")
 	IdentityCreateDTOKey    = ffm("IdentityCreateDTO.key", "The blockchain signing key to use to make the claim to the identity. Must be available to the local node to sign the identity claim. Will become a verifier on the established identity")
 
+	// IdentityRevokeDTO field descriptions
+	IdentityRevokeDTOKey = ffm("IdentityRevokeDTO.key", "A blockchain signing key of the identity to revoke. If unset, the identity itself and all of its verifiers are revoked")
+
+	// IdentityKeyRotationDTO field descriptions
+	IdentityKeyRotationDTOKey = ffm("IdentityKeyRotationDTO.key", "The new blockchain signing key for the identity. Replaces all existing keys of the same type, which are revoked")
+
 	// IdentityClaim field descriptions
 	IdentityClaimIdentity = ffm("IdentityClaim.identity", "The identity being claimed")
 
@@ -392,13 +437,23 @@ var (
 	IdentityUpdateIdentity = ffm("IdentityUpdate.identity", "The identity being updated")
 	IdentityUpdateProfile  = ffm("IdentityUpdate.profile", "The new profile, which is replaced in its entirety when the update is confirmed")
 
+	// IdentityRevocation field descriptions
+	IdentityRevocationIdentity = ffm("IdentityRevocation.identity", "The identity being revoked")
+	IdentityRevocationVerifier = ffm("IdentityRevocation.verifier", "The single verifier being revoked. If unset, the identity and all of its verifiers are revoked")
+
+	// IdentityKeyRotation field descriptions
+	IdentityKeyRotationIdentity = ffm("IdentityKeyRotation.identity", "The identity whose key is being rotated")
+	IdentityKeyRotationVerifier = ffm("IdentityKeyRotation.verifier", "The new verifier for the identity, replacing any existing verifiers of the same type")
+
 	// Verifier field descriptions
-	VerifierHash      = ffm("Verifier.hash", "Hash used as a globally consistent identifier for this namespace + type + value combination on every node in the network")
-	VerifierIdentity  = ffm("Verifier.identity", "The UUID of the parent identity that has claimed this verifier")
-	VerifierType      = ffm("Verifier.type", "The type of the verifier")
-	VerifierValue     = ffm("Verifier.value", "The verifier string, such as an Ethereum address, or Fabric MSP identifier")
-	VerifierNamespace = ffm("Verifier.namespace", "The namespace of the verifier")
-	VerifierCreated   = ffm("Verifier.created", "The time this verifier was created on this node")
+	VerifierHash            = ffm("Verifier.hash", "Hash used as a globally consistent identifier for this namespace + type + value combination on every node in the network")
+	VerifierIdentity        = ffm("Verifier.identity", "The UUID of the parent identity that has claimed this verifier")
+	VerifierType            = ffm("Verifier.type", "The type of the verifier")
+	VerifierValue           = ffm("Verifier.value", "The verifier string, such as an Ethereum address, or Fabric MSP identifier")
+	VerifierNamespace       = ffm("Verifier.namespace", "The namespace of the verifier")
+	VerifierCreated         = ffm("Verifier.created", "The time this verifier was created on this node")
+	VerifierRevoked         = ffm("Verifier.revoked", "The time this verifier was revoked on this node. Unset for active verifiers")
+	VerifierRevokedSequence = ffm("Verifier.revokedSequence", "The sequence of the pin that revoked this verifier. Messages signed by this verifier and sequenced after this point are rejected")
 
 	// Namespace field descriptions
 	NamespaceName                  = ffm("Namespace.name", "The local namespace name")
@@ -407,6 +462,7 @@ var (
 	NamespaceCreated               = ffm("Namespace.created", "The time the namespace was created")
 	MultipartyContractsActive      = ffm("MultipartyContracts.active", "The currently active FireFly smart contract")
 	MultipartyContractsTerminated  = ffm("MultipartyContracts.terminated", "Previously-terminated FireFly smart contracts")
+	MultipartyContractsPauses      = ffm("MultipartyContracts.pauses", "The network actions that have paused, and resumed, the acceptance of batch pins on this namespace")
 	MultipartyContractIndex        = ffm("MultipartyContract.index", "The index of this contract in the config file")
 	MultipartyContractVersion      = ffm("MultipartyContract.version", "The version of this multiparty contract")
 	MultipartyContractFinalEvent   = ffm("MultipartyContract.finalEvent", "The identifier for the final blockchain event received from this contract before termination")
@@ -414,7 +470,17 @@ var (
 	MultipartyContractLocation     = ffm("MultipartyContract.location", "A blockchain specific contract identifier. For example an Ethereum contract address, or a Fabric chaincode name and channel")
 	MultipartyContractSubscription = ffm("MultipartyContract.subscription", "The backend identifier of the subscription for the FireFly BatchPin contract")
 	MultipartyContractInfo         = ffm("MultipartyContract.info", "Additional info about the current status of the multi-party contract")
+	MultipartyContractOptions      = ffm("MultipartyContract.options", "Blockchain specific options for a contract that was announced by a migrate network action")
+	MultipartyContractMigration    = ffm("MultipartyContract.migrationEvent", "The identifier for the blockchain event of the migrate network action that made this contract active")
+	NetworkPauseEvent              = ffm("NetworkPause.event", "The identifier for the blockchain event of the pause network action")
+	NetworkPauseTimestamp          = ffm("NetworkPause.timestamp", "The blockchain timestamp of the pause network action")
+	NetworkPauseResumeEvent        = ffm("NetworkPause.resumeEvent", "The identifier for the blockchain event of the resume network action, if the namespace has been resumed")
+	NetworkPauseResumed            = ffm("NetworkPause.resumed", "The blockchain timestamp of the resume network action, if the namespace has been resumed")
 	NetworkActionType              = ffm("NetworkAction.type", "The action to be performed")
+	NetworkActionContract          = ffm("NetworkAction.contract", "The FireFly multiparty contract to move to. Required for the migrate action")
+	NetworkActionContractLocation  = ffm("NetworkActionContract.location", "A blockchain specific contract identifier. For example an Ethereum contract address, or a Fabric chaincode name and channel")
+	NetworkActionContractFirst     = ffm("NetworkActionContract.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors")
+	NetworkActionContractOptions   = ffm("NetworkActionContract.options", "Blockchain specific options for the contract, with the same format as the multiparty contract options in the config file")
 
 	// NamespaceWithInitStatus field descriptions
 	NamespaceWithInitStatusInitializing        = ffm("NamespaceWithInitStatus.initializing", "Set to true if the namespace is still initializing")
@@ -522,6 +588,7 @@ var (
 	SubscriptionFilterDeprecatedTag    = ffm("SubscriptionFilter.tag", "Deprecated: Please use 'message.tag' instead")
 	SubscriptionFilterDeprecatedGroup  = ffm("SubscriptionFilter.group", "Deprecated: Please use 'message.group' instead")
 	SubscriptionFilterDeprecatedAuthor = ffm("SubscriptionFilter.author", "Deprecated: Please use 'message.author' instead")
+	SubscriptionFilterExpression       = ffm("SubscriptionFilter.expression", "An expression evaluated against the enriched event, such as 'tokenTransfer.amount > 1000 and tokenTransfer.pool == \"<id>\"'. Fields of the event, including message data values under 'data' and blockchain event 'blockchainEvent.output' fields, are referenced by path")
 
 	// SubscriptionMessageFilter field descriptions
 	SubscriptionMessageFilterTag    = ffm("SubscriptionMessageFilter.tag", "Regular expression to apply to the message 'header.tag' field")
@@ -539,6 +606,11 @@ var (
 	SubscriptionCoreOptionsFirstEvent = ffm("SubscriptionCoreOptions.firstEvent", "Whether your application would like to receive events from the 'oldest' event emitted by your FireFly node (from the beginning of time), or the 'newest' event (from now), or a specific event sequence. Default is 'newest'")
 	SubscriptionCoreOptionsReadAhead  = ffm("SubscriptionCoreOptions.readAhead", "The number of events to stream ahead to your application, while waiting for confirmation of consumption of those events. At least once delivery semantics are used in FireFly, so if your application crashes/reconnects this is the maximum number of events you would expect to be redelivered after it restarts")
 	SubscriptionCoreOptionsWithData   = ffm("SubscriptionCoreOptions.withData", "Whether message events delivered over the subscription, should be packaged with the full data of those messages in-line as part of the event JSON payload. Or if the application should make separate REST calls to download that data. May not be supported on some transports.")
+	SubscriptionCoreOptionsBatch      = ffm("SubscriptionCoreOptions.batch", "Enables delivery of events in batches, on transports that support it. The whole batch can be acknowledged at once")
+
+	// SubscriptionBatchOptions field descriptions
+	SubscriptionBatchOptionsSize    = ffm("SubscriptionBatchOptions.size", "The maximum number of events to deliver in a single batch. Read ahead is increased to at least the batch size")
+	SubscriptionBatchOptionsTimeout = ffm("SubscriptionBatchOptions.timeout", "The maximum time to wait for a batch to fill, after the first event arrives, before delivering a partial batch")
 
 	// TokenApproval field descriptions
 	TokenApprovalLocalID         = ffm("TokenApproval.localId", "The UUID of this token approval, in the local FireFly node")
@@ -574,6 +646,56 @@ var (
 	TokenBalanceBalance    = ffm("TokenBalance.balance", "The numeric balance. For non-fungible tokens will always be 1. For fungible tokens, the number of decimals for the token pool should be considered when interpreting the balance. For example, with 18 decimals a fractional balance of 10.234 will be returned as 10,234,000,000,000,000,000")
 	TokenBalanceUpdated    = ffm("TokenBalance.updated", "The last time the balance was updated by applying a transfer event")
 
+	// TokenBalanceChange field descriptions
+	TokenBalanceChangeSequence                = ffm("TokenBalanceChange.sequence", "The sequence of the change in the balance history of the namespace, which increases as each transfer is confirmed")
+	TokenBalanceChangePool                    = ffm("TokenBalanceChange.pool", "The UUID the token pool this balance change applies to")
+	TokenBalanceChangeTokenIndex              = ffm("TokenBalanceChange.tokenIndex", "The index of the token within the pool that this balance change applies to")
+	TokenBalanceChangeURI                     = ffm("TokenBalanceChange.uri", "The URI of the token this balance change applies to")
+	TokenBalanceChangeConnector               = ffm("TokenBalanceChange.connector", "The token connector that is responsible for the token pool of this balance change")
+	TokenBalanceChangeNamespace               = ffm("TokenBalanceChange.namespace", "The namespace of the token pool for this balance change")
+	TokenBalanceChangeKey                     = ffm("TokenBalanceChange.key", "The blockchain signing identity whose balance changed")
+	TokenBalanceChangeAmount                  = ffm("TokenBalanceChange.amount", "The amount the balance changed by, which is negative for tokens transferred from the account")
+	TokenBalanceChangeBalance                 = ffm("TokenBalanceChange.balance", "The balance of the account after the change")
+	TokenBalanceChangeTransfer                = ffm("TokenBalanceChange.transfer", "The local ID of the token transfer that changed the balance")
+	TokenBalanceChangeBlockchainEvent         = ffm("TokenBalanceChange.blockchainEvent", "The UUID of the blockchain event for the token transfer")
+	TokenBalanceChangeBlockchainEventSequence = ffm("TokenBalanceChange.blockchainEventSequence", "The sequence of the blockchain event for the token transfer, which orders the changes as they were confirmed by the blockchain. For a change that is not from a blockchain event, such as a reconciliation correction, the sequence of the last blockchain event recorded before it. Not set for the opening balances of the history")
+	TokenBalanceChangeTimestamp               = ffm("TokenBalanceChange.timestamp", "The time of the blockchain event for the token transfer")
+	TokenBalanceChangeCreated                 = ffm("TokenBalanceChange.created", "The time the change was recorded by this FireFly node")
+
+	// TokenBalancePoint field descriptions
+	TokenBalancePointTimestamp = ffm("TokenBalancePoint.timestamp", "Take balances as of the last change with a blockchain event at or before this time")
+	TokenBalancePointSequence  = ffm("TokenBalancePoint.sequence", "Take balances as of the last change with a blockchain event sequence at or before this sequence, as reported in the blockchainEventSequence of each change in the balance history")
+
+	// TokenBalanceSnapshot field descriptions
+	TokenBalanceSnapshotPool      = ffm("TokenBalanceSnapshot.pool", "The UUID of the token pool")
+	TokenBalanceSnapshotTimestamp = ffm("TokenBalanceSnapshot.timestamp", "The timestamp the snapshot was taken at, if requested")
+	TokenBalanceSnapshotSequence  = ffm("TokenBalanceSnapshot.sequence", "The blockchain event sequence the snapshot was taken at, if requested")
+	TokenBalanceSnapshotBalances  = ffm("TokenBalanceSnapshot.balances", "The non-zero balances of every account in the pool at the point of the snapshot")
+
+	// TokenReconcileInput field descriptions
+	TokenReconcileInputPool    = ffm("TokenReconcileInput.pool", "The name or UUID of a token pool to reconcile. All confirmed pools in the namespace are reconciled when not set")
+	TokenReconcileInputCorrect = ffm("TokenReconcileInput.correct", "When true, local balances that do not match the token connector are corrected, and a token_balance_corrected event is emitted for each. A balance is only corrected when the token connector reports it as of the latest block this node has confirmed for the pool, otherwise the mismatch is only recorded")
+
+	// TokenReconciliation field descriptions
+	TokenReconciliationID      = ffm("TokenReconciliation.id", "The UUID of the transaction for this reconciliation run, which is recorded against each mismatch it finds. The status of the run is available from the token_reconcile operations of the transaction")
+	TokenReconciliationPools   = ffm("TokenReconciliation.pools", "The UUIDs of the token pools being reconciled")
+	TokenReconciliationSkipped = ffm("TokenReconciliation.skipped", "The UUIDs of token pools that were skipped, because their token connector does not support querying balances")
+	TokenReconciliationCorrect = ffm("TokenReconciliation.correct", "True if mismatched balances are corrected, where the token connector balance could be read at a block this node has already confirmed")
+	TokenReconciliationCreated = ffm("TokenReconciliation.created", "The time the reconciliation was submitted")
+
+	// TokenBalanceMismatch field descriptions
+	TokenBalanceMismatchID             = ffm("TokenBalanceMismatch.id", "The UUID of the mismatch")
+	TokenBalanceMismatchReconciliation = ffm("TokenBalanceMismatch.reconciliation", "The UUID of the transaction of the reconciliation run that found the mismatch")
+	TokenBalanceMismatchNamespace      = ffm("TokenBalanceMismatch.namespace", "The namespace of the token pool")
+	TokenBalanceMismatchPool           = ffm("TokenBalanceMismatch.pool", "The UUID of the token pool")
+	TokenBalanceMismatchTokenIndex     = ffm("TokenBalanceMismatch.tokenIndex", "The index of the token within the pool that the balance applies to")
+	TokenBalanceMismatchConnector      = ffm("TokenBalanceMismatch.connector", "The token connector that reported the on-chain balance")
+	TokenBalanceMismatchKey            = ffm("TokenBalanceMismatch.key", "The blockchain signing identity that owns the balance")
+	TokenBalanceMismatchLocalBalance   = ffm("TokenBalanceMismatch.localBalance", "The balance stored by this FireFly node when the mismatch was found")
+	TokenBalanceMismatchChainBalance   = ffm("TokenBalanceMismatch.chainBalance", "The balance reported by the token connector")
+	TokenBalanceMismatchCorrected      = ffm("TokenBalanceMismatch.corrected", "True if the local balance was corrected to match the token connector")
+	TokenBalanceMismatchCreated        = ffm("TokenBalanceMismatch.created", "The time the mismatch was found")
+
 	// TokenBalance field descriptions
 	TokenConnectorName = ffm("TokenConnector.name", "The name of the token connector, as configured in the FireFly core configuration file")
 
@@ -590,6 +712,7 @@ var (
 	TokenPoolDecimals        = ffm("TokenPool.decimals", "Number of decimal places that this token has")
 	TokenPoolConnector       = ffm("TokenPool.connector", "The name of the token connector, as specified in the FireFly core configuration file that is responsible for the token pool. Required on input when multiple token connectors are configured")
 	TokenPoolMessage         = ffm("TokenPool.message", "The UUID of the broadcast message used to inform the network to index this pool")
+	TokenPoolGroup           = ffm("TokenPool.group", "The privacy group the pool was shared with, if it was not published to the whole network")
 	TokenPoolState           = ffm("TokenPool.state", "The current state of the token pool")
 	TokenPoolCreated         = ffm("TokenPool.created", "The creation time of the pool")
 	TokenPoolConfig          = ffm("TokenPool.config", "Input only field, with token connector specific configuration of the pool, such as an existing Ethereum address and block number to used to index the pool. See your chosen token connector documentation for details")
@@ -628,6 +751,22 @@ var (
 	TokenTransferInputPool           = ffm("TokenTransferInput.pool", "The name or UUID of a token pool")
 	TokenTransferInputIdempotencyKey = ffm("TokenTransferInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
+	// TokenTransferBatchInput field descriptions
+	TokenTransferBatchInputItems          = ffm("TokenTransferBatchInput.items", "The transfers to submit together in a single FireFly transaction. Each is validated against its token pool before any are submitted")
+	TokenTransferBatchInputIdempotencyKey = ffm("TokenTransferBatchInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	TokenTransferBatchInputItemPool       = ffm("TokenTransferBatchInputItem.pool", "The name or UUID of a token pool")
+
+	// TokenTransferBatch field descriptions
+	TokenTransferBatchTX    = ffm("TokenTransferBatch.tx", "The FireFly transaction that all the transfers in the batch were submitted under")
+	TokenTransferBatchItems = ffm("TokenTransferBatch.items", "The transfers in the batch, with the status of each")
+
+	// TokenTransferBatchItem field descriptions
+	TokenTransferBatchItemTransfer  = ffm("TokenTransferBatchItem.transfer", "The transfer as submitted, or as recorded once it has been confirmed by the blockchain")
+	TokenTransferBatchItemOperation = ffm("TokenTransferBatchItem.operation", "The UUID of the operation that submitted this transfer to the token connector")
+	TokenTransferBatchItemStatus    = ffm("TokenTransferBatchItem.status", "The status of the operation that submitted this transfer")
+	TokenTransferBatchItemError     = ffm("TokenTransferBatchItem.error", "The error from the operation, if it failed")
+	TokenTransferBatchItemConfirmed = ffm("TokenTransferBatchItem.confirmed", "Whether the transfer has been confirmed by the blockchain")
+
 	// TransactionStatus field descriptions
 	TransactionStatusStatus  = ffm("TransactionStatus.status", "The overall computed status of the transaction, after analyzing the details during the API call")
 	TransactionStatusDetails = ffm("TransactionStatus.details", "A set of records describing the activities within the transaction known by the local FireFly node")
@@ -682,7 +821,7 @@ var (
 
 	WebhooksOptJSON         = ffm("WebhookSubOptions.json", "Webhooks only: Whether to assume the response body is JSON, regardless of the returned Content-Type")
 	WebhooksOptReply        = ffm("WebhookSubOptions.reply", "Webhooks only: Whether to automatically send a reply event, using the body returned by the webhook")
-	WebhooksOptHeaders      = ffm("WebhookSubOptions.headers", "Webhooks only: Static headers to set on the webhook request")
+	WebhooksOptHeaders      = ffm("WebhookSubOptions.headers", "Webhooks and Kafka only: Static headers to set on the webhook request, or on each Kafka record")
 	WebhooksOptQuery        = ffm("WebhookSubOptions.query", "Webhooks only: Static query params to set on the webhook request")
 	WebhooksOptInput        = ffm("WebhookSubOptions.input", "Webhooks only: A set of options to extract data from the first JSON input data in the incoming message. Only applies if withData=true")
 	WebhooksOptFastAck      = ffm("WebhookSubOptions.fastack", "Webhooks only: When true the event will be acknowledged before the webhook is invoked, allowing parallel invocations")
@@ -695,10 +834,98 @@ var (
 	WebhooksOptInputBody    = ffm("WebhookInputOptions.body", "A top-level property of the first data input, to use for the request body. Default is the whole first body")
 	WebhooksOptInputPath    = ffm("WebhookInputOptions.path", "A top-level property of the first data input, to use for a path to append with escaping to the webhook path")
 	WebhooksOptInputReplyTx = ffm("WebhookInputOptions.replytx", "A top-level property of the first data input, to use to dynamically set whether to pin the response (so the requester can choose)")
+	WebhooksOptRetry        = ffm("WebhookSubOptions.retry", "Webhooks only: The retry policy for failed webhook requests. When enabled, events that exhaust their retries are moved to the dead letter queue of the subscription, rather than blocking it")
+	WebhooksOptSigning      = ffm("WebhookSubOptions.signing", "Webhooks only: Signs each webhook request with a timestamped HMAC over the request body, so the receiver can verify it came from FireFly")
+	WebhooksOptTLSConfig    = ffm("WebhookSubOptions.tlsConfigName", "Webhooks only: The name of a TLS configuration from the webhooks plugin config, to use for the webhook request. Allows a client certificate to be presented for mutual TLS")
+
+	// WebhookSigningOptions field descriptions
+	WebhookSigningSecretName = ffm("WebhookSigningOptions.secretName", "The name of a signing secret configured in events.webhooks.signingSecrets, used as the HMAC key. Signing is enabled when this is set")
+	WebhookSigningHeader     = ffm("WebhookSigningOptions.header", "The header to set the signature in. Default=X-FireFly-Signature")
+	WebhookSigningAlgorithm  = ffm("WebhookSigningOptions.algorithm", "The HMAC hash algorithm - one of 'sha256' (default) or 'sha512'. The signature header has the format 't=<unix timestamp>,v1=<hex HMAC of timestamp+'.'+body>'")
+
+	// WebhookRetryOptions field descriptions
+	WebhookRetryEnabled      = ffm("WebhookRetryOptions.enabled", "Enables retry of failed webhook requests, with events that exhaust their retries moved to the dead letter queue")
+	WebhookRetryMaxAttempts  = ffm("WebhookRetryOptions.maxAttempts", "The maximum number of attempts to deliver each event, including the first attempt. Default=5")
+	WebhookRetryInitialDelay = ffm("WebhookRetryOptions.initialDelay", "The delay before the first retry, as a duration string such as '250ms'. Default=250ms")
+	WebhookRetryMaximumDelay = ffm("WebhookRetryOptions.maximumDelay", "The maximum delay between retries, as a duration string such as '30s'. Default=30s")
+	WebhookRetryFactor       = ffm("WebhookRetryOptions.factor", "The factor to increase the delay by on each retry. Default=2.0")
+	WebhookRetryStatusCodes  = ffm("WebhookRetryOptions.statusCodes", "The HTTP status codes that should be retried. Errors connecting to the webhook are always retried. Default is any 5xx status code")
+
+	// DeadLetter field descriptions
+	DeadLetterID           = ffm("DeadLetter.id", "The UUID of the dead letter")
+	DeadLetterNamespace    = ffm("DeadLetter.namespace", "The namespace of the dead letter")
+	DeadLetterSubscription = ffm("DeadLetter.subscription", "The subscription the event could not be delivered on")
+	DeadLetterEvent        = ffm("DeadLetter.event", "The UUID of the event that could not be delivered")
+	DeadLetterAttempts     = ffm("DeadLetter.attempts", "The total number of delivery attempts made for the event, including any replays")
+	DeadLetterError        = ffm("DeadLetter.error", "The error from the most recent delivery attempt")
+	DeadLetterCreated      = ffm("DeadLetter.created", "The time the event was moved to the dead letter queue")
+	DeadLetterUpdated      = ffm("DeadLetter.updated", "The time of the most recent replay of the dead letter")
+
+	// DefinitionProposal field descriptions
+	DefinitionProposalID        = ffm("DefinitionProposal.id", "The UUID of the definition proposal, which is the ID of the message that broadcast the definition")
+	DefinitionProposalNamespace = ffm("DefinitionProposal.namespace", "The namespace of the definition proposal")
+	DefinitionProposalTag       = ffm("DefinitionProposal.tag", "The system tag of the definition message, which identifies the type of definition")
+	DefinitionProposalTopic     = ffm("DefinitionProposal.topic", "The topic of the definition message. Votes are broadcast on the same topic")
+	DefinitionProposalAuthor    = ffm("DefinitionProposal.author", "The DID of the identity that broadcast the definition")
+	DefinitionProposalTX        = ffm("DefinitionProposal.tx", "The UUID of the transaction that broadcast the definition")
+	DefinitionProposalState     = ffm("DefinitionProposal.state", "The state of the proposal")
+	DefinitionProposalQuorum    = ffm("DefinitionProposal.quorum", "The number of root organization approvals required to confirm the definition, taken from the network governance rules when the definition was proposed")
+	DefinitionProposalVotes     = ffm("DefinitionProposal.votes", "The votes that have been recorded against the proposal")
+	DefinitionProposalError     = ffm("DefinitionProposal.error", "The error if the definition failed to apply once approved")
+	DefinitionProposalCreated   = ffm("DefinitionProposal.created", "The time the proposal was received")
+	DefinitionProposalUpdated   = ffm("DefinitionProposal.updated", "The time the most recent vote was recorded")
+
+	// DefinitionProposalVote field descriptions
+	DefinitionProposalVoteOrg     = ffm("DefinitionProposalVote.org", "The UUID of the root organization that voted")
+	DefinitionProposalVoteAuthor  = ffm("DefinitionProposalVote.author", "The DID of the root organization that voted")
+	DefinitionProposalVoteApprove = ffm("DefinitionProposalVote.approve", "True if the organization voted to approve the definition, false if it voted to reject it")
+	DefinitionProposalVoteMessage = ffm("DefinitionProposalVote.message", "The UUID of the message that broadcast the vote")
+	DefinitionProposalVoteCreated = ffm("DefinitionProposalVote.created", "The time the vote was recorded")
+
+	// DefinitionVote field descriptions
+	DefinitionVoteProposal = ffm("DefinitionVote.proposal", "The UUID of the definition proposal being voted on")
+	DefinitionVoteTopic    = ffm("DefinitionVote.topic", "The topic of the definition proposal, on which the vote is broadcast")
+	DefinitionVoteApprove  = ffm("DefinitionVote.approve", "Set to true to approve the definition, or false to reject it")
+
+	// OrgApplication field descriptions
+	OrgApplicationID        = ffm("OrgApplication.id", "The UUID of the application")
+	OrgApplicationNamespace = ffm("OrgApplication.namespace", "The namespace of the application")
+	OrgApplicationSponsor   = ffm("OrgApplication.sponsor", "The DID of the existing root organization that is asked to sponsor the application. Only the sponsor can approve or reject it")
+	OrgApplicationOrg       = ffm("OrgApplication.org", "The organization to be registered when the application is approved. Defaults to the root organization configured on the node")
+	OrgApplicationNode      = ffm("OrgApplication.node", "The node to be registered when the application is approved, including the peer information of its data exchange. Defaults to the node configured on the node")
+	OrgApplicationKey       = ffm("OrgApplication.key", "The blockchain signing key of the organization, which signs the application. Defaults to the root organization key configured on the node")
+	OrgApplicationState     = ffm("OrgApplication.state", "The state of the application")
+	OrgApplicationReason    = ffm("OrgApplication.reason", "The reason given by the sponsor for the decision, or the error if the organization and node could not be registered")
+	OrgApplicationMessage   = ffm("OrgApplication.message", "The UUID of the message that broadcast the application")
+	OrgApplicationReview    = ffm("OrgApplication.review", "The UUID of the message that broadcast the review of the sponsor")
+	OrgApplicationCreated   = ffm("OrgApplication.created", "The time the application was received")
+	OrgApplicationUpdated   = ffm("OrgApplication.updated", "The time the application was last updated")
+
+	// OrgApplicationIdentity field descriptions
+	OrgApplicationIdentityID   = ffm("OrgApplicationIdentity.id", "The UUID the identity will be registered with")
+	OrgApplicationIdentityName = ffm("OrgApplicationIdentity.name", "The name of the identity")
+
+	// OrgApplicationReview field descriptions
+	OrgApplicationReviewApplication = ffm("OrgApplicationReview.application", "The UUID of the application being reviewed")
+	OrgApplicationReviewApprove     = ffm("OrgApplicationReview.approve", "Set to true to approve the application and register the organization and node, or false to reject it")
+	OrgApplicationReviewReason      = ffm("OrgApplicationReview.reason", "An optional reason for the decision, which is recorded on the application")
+
+	KafkaOptTopic     = ffm("KafkaSubOptions.topic", "Kafka only: The topic to publish events to. Defaults to the topic configured on the Kafka plugin")
+	KafkaOptKey       = ffm("KafkaSubOptions.key", "Kafka only: The field of the event to use as the record key, which determines the partition - one of 'topic' (default), 'type', 'reference', 'tx' or 'none'")
+	KafkaOptPartition = ffm("KafkaSubOptions.partition", "Kafka only: An explicit partition to publish all events to, overriding the partitioning by key")
 
 	// PublishInput field descriptions
 	PublishInputIdempotencyKey = ffm("PublishInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
 	// DefinitionPublish field descriptions
 	DefinitionPublishNetworkName = ffm("DefinitionPublish.networkName", "An optional name to be used for publishing this definition to the multiparty network, which may differ from the local name")
+	DefinitionPublishGroup       = ffm("DefinitionPublish.group", "The hash of an existing privacy group. When set the definition is shared privately with the members of that group, rather than the whole network")
+
+	// NetworkGovernance field descriptions
+	NetworkGovernanceNamespace        = ffm("NetworkGovernance.namespace", "The namespace the governance rules apply to")
+	NetworkGovernanceDefinitionQuorum = ffm("NetworkGovernance.definitionQuorum", "The number of root organizations that must vote to approve a datatype, contract interface, contract API, token pool or governance definition before it is confirmed. Zero confirms definitions as soon as they are broadcast")
+	NetworkGovernanceOrgOnboarding    = ffm("NetworkGovernance.orgOnboarding", "When true, new root organizations can only join the network through an application that is approved by its sponsor, rather than by broadcasting their own identity claim")
+	NetworkGovernanceAuthor           = ffm("NetworkGovernance.author", "The DID of the root organization that broadcast the current governance rules")
+	NetworkGovernanceMessage          = ffm("NetworkGovernance.message", "The UUID of the message that broadcast the current governance rules")
+	NetworkGovernanceUpdated          = ffm("NetworkGovernance.updated", "The time the current governance rules were confirmed")
 )
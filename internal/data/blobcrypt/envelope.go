@@ -0,0 +1,92 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"context"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Encrypt generates a data key for a new blob, wraps it with the key provider, and returns
+// a reader of the blob encrypted with the data key
+func Encrypt(ctx context.Context, kp KeyProvider, plaintext io.Reader) (io.Reader, *core.BlobEncryption, error) {
+	dataKey, err := newDataKey()
+	if err != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionFailed, err)
+	}
+	keyID, wrapped, err := kp.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryption := &core.BlobEncryption{
+		Algorithm: Algorithm,
+		KeyID:     keyID,
+		DataKey:   wrapped,
+	}
+	return newEncryptReader(ctx, dataKey, plaintext), encryption, nil
+}
+
+// Decrypt unwraps the data key of a blob with the key provider, and returns a reader of the
+// plaintext, which fails if any part of the ciphertext has been modified, reordered or truncated.
+// Closing the returned reader closes the supplied reader.
+func Decrypt(ctx context.Context, kp KeyProvider, encryption *core.BlobEncryption, ciphertext io.ReadCloser) (io.ReadCloser, error) {
+	dataKey, err := unwrapDataKey(ctx, kp, encryption)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptReader(ctx, dataKey, ciphertext), nil
+}
+
+// SegmentStart returns the offset in the ciphertext of the segment containing an offset in the plaintext,
+// which is where the ciphertext must be read from to decrypt the plaintext from that offset
+func SegmentStart(offset int64) int64 {
+	return (offset / segmentSize) * (segmentSize + gcmOverhead)
+}
+
+// DecryptRange returns a reader of the plaintext from an offset, given a reader of the ciphertext from
+// SegmentStart(offset) to the end of the blob. The segments read are verified as they are by Decrypt.
+func DecryptRange(ctx context.Context, kp KeyProvider, encryption *core.BlobEncryption, ciphertext io.ReadCloser, offset int64) (io.ReadCloser, error) {
+	dataKey, err := unwrapDataKey(ctx, kp, encryption)
+	if err != nil {
+		return nil, err
+	}
+	dr := newDecryptReader(ctx, dataKey, ciphertext)
+	dr.index = uint64(offset / segmentSize)
+	// Skip forwards within the first segment, which has to be decrypted whole
+	if _, err := io.CopyN(io.Discard, dr, offset%segmentSize); err != nil {
+		return nil, err
+	}
+	return dr, nil
+}
+
+func unwrapDataKey(ctx context.Context, kp KeyProvider, encryption *core.BlobEncryption) ([]byte, error) {
+	if encryption.Algorithm != Algorithm {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionUnsupported, encryption.Algorithm)
+	}
+	dataKey, err := kp.UnwrapKey(ctx, encryption.KeyID, encryption.DataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataKey) != dataKeyLen {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobDecryptionFailed, "invalid data key length")
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecryptWithKeyfile(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+
+	ciphertext, encryption, err := Encrypt(context.Background(), kp, bytes.NewReader([]byte("hello")))
+	assert.NoError(t, err)
+	assert.Equal(t, Algorithm, encryption.Algorithm)
+	assert.NotEmpty(t, encryption.KeyID)
+	stored, err := io.ReadAll(ciphertext)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stored), "hello")
+
+	plaintext, err := Decrypt(context.Background(), kp, encryption, io.NopCloser(bytes.NewReader(stored)))
+	assert.NoError(t, err)
+	defer plaintext.Close()
+	result, err := io.ReadAll(plaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(result))
+}
+
+func TestEncryptDataKeyFail(t *testing.T) {
+	randReader = iotest.ErrReader(fmt.Errorf("pop"))
+	defer func() { randReader = rand.Reader }()
+	_, _, err := Encrypt(context.Background(), &testProvider{}, bytes.NewReader(nil))
+	assert.Regexp(t, "FF10528.*pop", err)
+}
+
+func TestEncryptWrapFail(t *testing.T) {
+	_, _, err := Encrypt(context.Background(), &testProvider{err: fmt.Errorf("pop")}, bytes.NewReader(nil))
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDecryptUnsupportedAlgorithm(t *testing.T) {
+	_, err := Decrypt(context.Background(), &testProvider{}, &core.BlobEncryption{Algorithm: "rot13"}, io.NopCloser(bytes.NewReader(nil)))
+	assert.Regexp(t, "FF10530.*rot13", err)
+}
+
+func TestDecryptUnwrapFail(t *testing.T) {
+	_, err := Decrypt(context.Background(), &testProvider{err: fmt.Errorf("pop")}, &core.BlobEncryption{Algorithm: Algorithm}, io.NopCloser(bytes.NewReader(nil)))
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDecryptBadDataKey(t *testing.T) {
+	_, err := Decrypt(context.Background(), &testProvider{}, &core.BlobEncryption{Algorithm: Algorithm, DataKey: []byte("short")}, io.NopCloser(bytes.NewReader(nil)))
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptRange(t *testing.T) {
+	ctx := context.Background()
+	kp := &testProvider{}
+	plaintext := make([]byte, 3*segmentSize+100)
+	_, err := rand.Read(plaintext)
+	assert.NoError(t, err)
+
+	ciphertext, encryption, err := Encrypt(ctx, kp, bytes.NewReader(plaintext))
+	assert.NoError(t, err)
+	stored, err := io.ReadAll(ciphertext)
+	assert.NoError(t, err)
+
+	for _, offset := range []int64{0, 10, segmentSize, segmentSize + 5, 3*segmentSize + 50} {
+		start := SegmentStart(offset)
+		reader, err := DecryptRange(ctx, kp, encryption, io.NopCloser(bytes.NewReader(stored[start:])), offset)
+		assert.NoError(t, err)
+		result, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext[offset:], result)
+	}
+}
+
+func TestDecryptRangeWrongSegment(t *testing.T) {
+	ctx := context.Background()
+	kp := &testProvider{}
+	ciphertext, encryption, err := Encrypt(ctx, kp, bytes.NewReader(make([]byte, 2*segmentSize)))
+	assert.NoError(t, err)
+	stored, err := io.ReadAll(ciphertext)
+	assert.NoError(t, err)
+
+	// The first segment cannot be decrypted as the second one
+	_, err = DecryptRange(ctx, kp, encryption, io.NopCloser(bytes.NewReader(stored)), segmentSize+5)
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptRangeUnwrapFail(t *testing.T) {
+	_, err := DecryptRange(context.Background(), &testProvider{err: fmt.Errorf("pop")}, &core.BlobEncryption{Algorithm: Algorithm}, io.NopCloser(bytes.NewReader(nil)), 0)
+	assert.Regexp(t, "pop", err)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const keyfileProviderName = "keyfile"
+
+// keyfileProvider wraps data keys with AES-256-GCM, using a key encryption key read
+// from a local file. The key ID is a fingerprint of the key encryption key.
+type keyfileProvider struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+func newKeyfileProvider(ctx context.Context) (KeyProvider, error) {
+	path := config.GetString(coreconfig.BlobEncryptionKeyfilePath)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionKeyInvalid, path, err)
+	}
+	kek, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionKeyInvalid, path, err)
+	}
+	if len(kek) != dataKeyLen {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionKeyInvalid, path, "key must be 32 bytes")
+	}
+	block, _ := aes.NewCipher(kek) // cannot fail for a 32 byte key
+	aead, _ := cipher.NewGCM(block)
+	fingerprint := sha256.Sum256(kek)
+	return &keyfileProvider{
+		keyID: hex.EncodeToString(fingerprint[0:8]),
+		aead:  aead,
+	}, nil
+}
+
+func (kp *keyfileProvider) Name() string {
+	return keyfileProviderName
+}
+
+func (kp *keyfileProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	nonce := make([]byte, kp.aead.NonceSize())
+	if _, err := io.ReadFull(randReader, nonce); err != nil {
+		return "", nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionFailed, err)
+	}
+	return kp.keyID, kp.aead.Seal(nonce, nonce, dataKey, []byte(kp.keyID)), nil
+}
+
+func (kp *keyfileProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != kp.keyID {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionKeyNotFound, keyID, keyfileProviderName)
+	}
+	nonceSize := kp.aead.NonceSize()
+	if len(wrapped) < nonceSize {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobDecryptionFailed, "wrapped data key is too short")
+	}
+	dataKey, err := kp.aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], []byte(keyID))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobDecryptionFailed, err)
+	}
+	return dataKey, nil
+}
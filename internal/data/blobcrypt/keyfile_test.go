@@ -0,0 +1,115 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKEK = "0x000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func newTestKeyfileProvider(t *testing.T, content string) (KeyProvider, error) {
+	coreconfig.Reset()
+	path := filepath.Join(t.TempDir(), "kek")
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.NoError(t, err)
+	config.Set(coreconfig.BlobEncryptionKeyfilePath, path)
+	return NewProvider(context.Background(), "keyfile")
+}
+
+func TestKeyfileWrapUnwrap(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK+"\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "keyfile", kp.Name())
+
+	dataKey, _ := newDataKey()
+	keyID, wrapped, err := kp.WrapKey(context.Background(), dataKey)
+	assert.NoError(t, err)
+	assert.Len(t, keyID, 16)
+	assert.NotContains(t, string(wrapped), string(dataKey))
+
+	unwrapped, err := kp.UnwrapKey(context.Background(), keyID, wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The key ID is stable for the same key encryption key
+	kp2, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+	keyID2, _, err := kp2.WrapKey(context.Background(), dataKey)
+	assert.NoError(t, err)
+	assert.Equal(t, keyID, keyID2)
+}
+
+func TestKeyfileMissing(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.BlobEncryptionKeyfilePath, filepath.Join(t.TempDir(), "missing"))
+	_, err := NewProvider(context.Background(), "keyfile")
+	assert.Regexp(t, "FF10526", err)
+}
+
+func TestKeyfileBadHex(t *testing.T) {
+	_, err := newTestKeyfileProvider(t, "not hex")
+	assert.Regexp(t, "FF10526", err)
+}
+
+func TestKeyfileBadLength(t *testing.T) {
+	_, err := newTestKeyfileProvider(t, "0011")
+	assert.Regexp(t, "FF10526.*32 bytes", err)
+}
+
+func TestKeyfileWrapRandFail(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+	randReader = iotest.ErrReader(fmt.Errorf("pop"))
+	defer func() { randReader = rand.Reader }()
+	_, _, err = kp.WrapKey(context.Background(), make([]byte, 32))
+	assert.Regexp(t, "FF10528.*pop", err)
+}
+
+func TestKeyfileUnwrapWrongKeyID(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+	_, err = kp.UnwrapKey(context.Background(), "otherkey", []byte{})
+	assert.Regexp(t, "FF10527.*otherkey", err)
+}
+
+func TestKeyfileUnwrapTooShort(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+	keyID, _, _ := kp.WrapKey(context.Background(), make([]byte, 32))
+	_, err = kp.UnwrapKey(context.Background(), keyID, []byte{0x01})
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestKeyfileUnwrapTampered(t *testing.T) {
+	kp, err := newTestKeyfileProvider(t, testKEK)
+	assert.NoError(t, err)
+	keyID, wrapped, _ := kp.WrapKey(context.Background(), make([]byte, 32))
+	wrapped[len(wrapped)-1] ^= 0xff
+	_, err = kp.UnwrapKey(context.Background(), keyID, wrapped)
+	assert.Regexp(t, "FF10529", err)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// KeyProvider holds the key encryption keys that wrap the data key of each blob.
+// An implementation can delegate to a key management service (KMS), so that the
+// key encryption key never leaves it.
+type KeyProvider interface {
+	Name() string
+
+	// WrapKey encrypts a data key, returning the ID of the key encryption key used
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key previously wrapped by the identified key encryption key
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) (dataKey []byte, err error)
+}
+
+// ProviderFactory constructs a KeyProvider from the blob encryption configuration
+type ProviderFactory func(ctx context.Context) (KeyProvider, error)
+
+var providers = map[string]ProviderFactory{
+	keyfileProviderName: newKeyfileProvider,
+}
+
+// RegisterProvider makes an additional key provider available by name
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers[name] = factory
+}
+
+// NewProvider constructs the named key provider
+func NewProvider(ctx context.Context, name string) (KeyProvider, error) {
+	factory, ok := providers[name]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionProviderUnknown, name)
+	}
+	return factory(ctx)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testProvider does not protect the data key, and can be told to fail
+type testProvider struct {
+	err error
+}
+
+func (tp *testProvider) Name() string { return "test" }
+
+func (tp *testProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	return "test", dataKey, tp.err
+}
+
+func (tp *testProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	return wrapped, tp.err
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider("test", func(ctx context.Context) (KeyProvider, error) {
+		return &testProvider{}, nil
+	})
+	defer delete(providers, "test")
+
+	kp, err := NewProvider(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", kp.Name())
+}
+
+func TestNewProviderUnknown(t *testing.T) {
+	_, err := NewProvider(context.Background(), "kms")
+	assert.Regexp(t, "FF10525.*kms", err)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blobcrypt provides envelope encryption of blob content, as it is streamed
+// to and from the data exchange.
+//
+// Each blob is encrypted with its own random 256-bit data key, and only the data key
+// wrapped by a key encryption key (KEK) held by a KeyProvider is stored alongside it.
+//
+// The content is split into fixed size segments, each sealed with AES-256-GCM. The nonce
+// of each segment is its index plus a flag marking the final segment, so segments cannot
+// be reordered, and the stream cannot be truncated without detection. As every data key
+// is used for exactly one blob, these deterministic nonces are never reused.
+package blobcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// Algorithm is the identifier recorded against blobs encrypted by this package
+const Algorithm = "aes-256-gcm-stream"
+
+const (
+	dataKeyLen  = 32
+	segmentSize = 64 * 1024
+	gcmOverhead = 16 // the authentication tag appended to each segment
+)
+
+var randReader = rand.Reader
+
+func newDataKey() ([]byte, error) {
+	key := make([]byte, dataKeyLen)
+	if _, err := io.ReadFull(randReader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// newGCM requires a key of dataKeyLen, with which it cannot fail
+func newGCM(key []byte) cipher.AEAD {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
+
+func segmentNonce(nonce []byte, index uint64, final bool) []byte {
+	for i := range nonce {
+		nonce[i] = 0
+	}
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], index)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// segmentReader reads the input in segments of a fixed size, reading ahead by
+// one byte to determine whether each segment is the final one
+type segmentReader struct {
+	r         io.Reader
+	buf       []byte
+	ahead     [1]byte
+	haveAhead bool
+}
+
+func (sr *segmentReader) next() (segment []byte, final bool, err error) {
+	n := 0
+	if sr.haveAhead {
+		sr.buf[0] = sr.ahead[0]
+		sr.haveAhead = false
+		n = 1
+	}
+	read, err := io.ReadFull(sr.r, sr.buf[n:])
+	n += read
+	switch err {
+	case nil:
+		_, err = io.ReadFull(sr.r, sr.ahead[:])
+		if err == io.EOF {
+			return sr.buf[:n], true, nil
+		}
+		sr.haveAhead = err == nil
+		return sr.buf[:n], false, err
+	case io.EOF, io.ErrUnexpectedEOF:
+		return sr.buf[:n], true, nil
+	default:
+		return nil, false, err
+	}
+}
+
+type encryptReader struct {
+	ctx   context.Context
+	aead  cipher.AEAD
+	src   segmentReader
+	nonce []byte
+	index uint64
+	out   []byte
+	done  bool
+}
+
+func newEncryptReader(ctx context.Context, dataKey []byte, plaintext io.Reader) io.Reader {
+	aead := newGCM(dataKey)
+	return &encryptReader{
+		ctx:   ctx,
+		aead:  aead,
+		src:   segmentReader{r: plaintext, buf: make([]byte, segmentSize)},
+		nonce: make([]byte, aead.NonceSize()),
+		out:   make([]byte, 0, segmentSize+aead.Overhead()),
+	}
+}
+
+func (er *encryptReader) Read(p []byte) (int, error) {
+	if len(er.out) == 0 {
+		if er.done {
+			return 0, io.EOF
+		}
+		segment, final, err := er.src.next()
+		if err != nil {
+			return 0, i18n.NewError(er.ctx, coremsgs.MsgBlobEncryptionFailed, err)
+		}
+		er.out = er.aead.Seal(er.out[:0], segmentNonce(er.nonce, er.index, final), segment, nil)
+		er.index++
+		er.done = final
+	}
+	n := copy(p, er.out)
+	er.out = er.out[n:]
+	return n, nil
+}
+
+type decryptReader struct {
+	ctx    context.Context
+	aead   cipher.AEAD
+	closer io.Closer
+	src    segmentReader
+	nonce  []byte
+	index  uint64
+	out    []byte
+	done   bool
+}
+
+func newDecryptReader(ctx context.Context, dataKey []byte, ciphertext io.ReadCloser) *decryptReader {
+	aead := newGCM(dataKey)
+	return &decryptReader{
+		ctx:    ctx,
+		aead:   aead,
+		closer: ciphertext,
+		src:    segmentReader{r: ciphertext, buf: make([]byte, segmentSize+aead.Overhead())},
+		nonce:  make([]byte, aead.NonceSize()),
+		out:    make([]byte, 0, segmentSize),
+	}
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.out) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		segment, final, err := dr.src.next()
+		if err != nil {
+			return 0, i18n.NewError(dr.ctx, coremsgs.MsgBlobDecryptionFailed, err)
+		}
+		dr.out, err = dr.aead.Open(dr.out[:0], segmentNonce(dr.nonce, dr.index, final), segment, nil)
+		if err != nil {
+			return 0, i18n.NewError(dr.ctx, coremsgs.MsgBlobDecryptionFailed, err)
+		}
+		dr.index++
+		dr.done = final
+	}
+	n := copy(p, dr.out)
+	dr.out = dr.out[n:]
+	return n, nil
+}
+
+func (dr *decryptReader) Close() error {
+	return dr.closer.Close()
+}
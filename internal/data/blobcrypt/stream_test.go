@@ -0,0 +1,144 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobcrypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encrypt(t *testing.T, key, plaintext []byte) []byte {
+	ciphertext, err := io.ReadAll(newEncryptReader(context.Background(), key, bytes.NewReader(plaintext)))
+	assert.NoError(t, err)
+	return ciphertext
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	dr := newDecryptReader(context.Background(), key, io.NopCloser(bytes.NewReader(ciphertext)))
+	defer dr.Close()
+	return io.ReadAll(dr)
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	key, err := newDataKey()
+	assert.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 17} {
+		t.Run(fmt.Sprintf("size_%d", size), func(t *testing.T) {
+			plaintext := make([]byte, size)
+			_, _ = rand.Read(plaintext)
+
+			ciphertext := encrypt(t, key, plaintext)
+			segments := (size + segmentSize - 1) / segmentSize
+			if segments == 0 {
+				segments = 1
+			}
+			assert.Equal(t, size+segments*16, len(ciphertext))
+
+			result, err := decrypt(key, ciphertext)
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, result)
+		})
+	}
+}
+
+func TestEncryptOneByteReads(t *testing.T) {
+	key, _ := newDataKey()
+	plaintext := bytes.Repeat([]byte("firefly"), segmentSize/3)
+
+	er := newEncryptReader(context.Background(), key, iotest.OneByteReader(bytes.NewReader(plaintext)))
+	ciphertext, err := io.ReadAll(iotest.OneByteReader(er))
+	assert.NoError(t, err)
+
+	dr := newDecryptReader(context.Background(), key, io.NopCloser(iotest.HalfReader(bytes.NewReader(ciphertext))))
+	result, err := io.ReadAll(iotest.OneByteReader(dr))
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, result)
+}
+
+func TestNewDataKeyFail(t *testing.T) {
+	randReader = iotest.ErrReader(fmt.Errorf("pop"))
+	defer func() { randReader = rand.Reader }()
+	_, err := newDataKey()
+	assert.Regexp(t, "pop", err)
+}
+
+func TestEncryptReadFail(t *testing.T) {
+	key, _ := newDataKey()
+	_, err := io.ReadAll(newEncryptReader(context.Background(), key, iotest.ErrReader(fmt.Errorf("pop"))))
+	assert.Regexp(t, "FF10528.*pop", err)
+}
+
+func TestEncryptReadAheadFail(t *testing.T) {
+	key, _ := newDataKey()
+	src := io.MultiReader(bytes.NewReader(make([]byte, segmentSize)), iotest.ErrReader(fmt.Errorf("pop")))
+	_, err := io.ReadAll(newEncryptReader(context.Background(), key, src))
+	assert.Regexp(t, "FF10528.*pop", err)
+}
+
+func TestDecryptReadFail(t *testing.T) {
+	key, _ := newDataKey()
+	_, err := io.ReadAll(newDecryptReader(context.Background(), key, io.NopCloser(iotest.ErrReader(fmt.Errorf("pop")))))
+	assert.Regexp(t, "FF10529.*pop", err)
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	key1, _ := newDataKey()
+	key2, _ := newDataKey()
+	_, err := decrypt(key2, encrypt(t, key1, []byte("hello")))
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptEmpty(t *testing.T) {
+	key, _ := newDataKey()
+	_, err := decrypt(key, []byte{})
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptTampered(t *testing.T) {
+	key, _ := newDataKey()
+	ciphertext := encrypt(t, key, []byte("hello"))
+	ciphertext[0] ^= 0xff
+	_, err := decrypt(key, ciphertext)
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptTruncatedAtSegment(t *testing.T) {
+	key, _ := newDataKey()
+	ciphertext := encrypt(t, key, make([]byte, 2*segmentSize+10))
+	_, err := decrypt(key, ciphertext[:2*(segmentSize+16)])
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestDecryptReordered(t *testing.T) {
+	key, _ := newDataKey()
+	plaintext := make([]byte, 2*segmentSize)
+	_, _ = rand.Read(plaintext)
+	ciphertext := encrypt(t, key, plaintext)
+	seg := segmentSize + 16
+	reordered := append(append(append([]byte{}, ciphertext[seg:2*seg]...), ciphertext[0:seg]...), ciphertext[2*seg:]...)
+	_, err := decrypt(key, reordered)
+	assert.Regexp(t, "FF10529", err)
+}
@@ -21,6 +21,8 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/docker/go-units"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -28,27 +30,56 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data/blobcrypt"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
 )
 
 type blobStore struct {
-	dm       *dataManager
-	database database.Plugin
-	exchange dataexchange.Plugin // optional
+	ctx           context.Context
+	dm            *dataManager
+	database      database.Plugin
+	exchange      dataexchange.Plugin   // optional
+	keys          blobcrypt.KeyProvider // set when blob encryption is enabled
+	uploadTimeout time.Duration         // how long a resumable upload is kept after its last change
+	finalizers    sync.WaitGroup        // uploads being finalized in the background
 }
 
-func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, reader io.Reader) (hash *fftypes.Bytes32, written int64, payloadRef string, err error) {
+// byteCounter counts the bytes written to it
+type byteCounter struct {
+	count int64
+}
+
+func (bc *byteCounter) Write(p []byte) (int, error) {
+	bc.count += int64(len(p))
+	return len(p), nil
+}
+
+// uploadVerifyBlob streams a blob to the data exchange, encrypting it on the way if blob encryption is enabled.
+// The hash and size returned are those of the plaintext, while those reported by the data exchange
+// are verified against the content it stored.
+func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, reader io.Reader) (hash *fftypes.Bytes32, written int64, payloadRef string, encryption *core.BlobEncryption, err error) {
 	hashCalc := sha256.New()
+	storedHashCalc := hashCalc
+	plaintextSize := &byteCounter{}
+	var stored io.Reader = io.TeeReader(reader, plaintextSize)
+	if bs.keys != nil {
+		stored, encryption, err = blobcrypt.Encrypt(ctx, bs.keys, io.TeeReader(stored, hashCalc))
+		if err != nil {
+			return nil, -1, "", nil, err
+		}
+		storedHashCalc = sha256.New()
+	}
 	dxReader, dx := io.Pipe()
-	storeAndHash := io.MultiWriter(hashCalc, dx)
+	storeAndHash := io.MultiWriter(storedHashCalc, dx)
 
+	var storedSize int64
 	copyDone := make(chan error, 1)
 	go func() {
 		var err error
-		written, err = io.Copy(storeAndHash, reader)
-		log.L(ctx).Debugf("Upload Blob streamed %d bytes (err=%v)", written, err)
+		storedSize, err = io.Copy(storeAndHash, stored)
+		log.L(ctx).Debugf("Upload Blob streamed %d bytes (err=%v)", storedSize, err)
 		_ = dx.Close()
 		copyDone <- err
 	}()
@@ -57,23 +88,25 @@ func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, rea
 	dxReader.Close()
 	copyErr := <-copyDone
 	if dxErr != nil {
-		return nil, -1, "", dxErr
+		return nil, -1, "", nil, dxErr
 	}
 	if copyErr != nil {
-		return nil, -1, "", i18n.WrapError(ctx, copyErr, coremsgs.MsgBlobStreamingFailed)
+		return nil, -1, "", nil, i18n.WrapError(ctx, copyErr, coremsgs.MsgBlobStreamingFailed)
 	}
 
 	hash = fftypes.HashResult(hashCalc)
-	log.L(ctx).Debugf("Upload Blob size=%d hashes: calculated=%s upload=%s (expected=%v) size=%d", written, hash, uploadHash, uploadSize, written)
+	storedHash := fftypes.HashResult(storedHashCalc)
+	written = plaintextSize.count
+	log.L(ctx).Debugf("Upload Blob size=%d hashes: calculated=%s stored=%s upload=%s (expected=%v) size=%d", written, hash, storedHash, uploadHash, uploadSize, storedSize)
 
-	if !uploadHash.Equals(hash) {
-		return nil, -1, "", i18n.NewError(ctx, coremsgs.MsgDXBadHash, uploadHash, hash)
+	if !uploadHash.Equals(storedHash) {
+		return nil, -1, "", nil, i18n.NewError(ctx, coremsgs.MsgDXBadHash, uploadHash, storedHash)
 	}
-	if uploadSize > 0 && uploadSize != written {
-		return nil, -1, "", i18n.NewError(ctx, coremsgs.MsgDXBadSize, uploadSize, written)
+	if uploadSize > 0 && uploadSize != storedSize {
+		return nil, -1, "", nil, i18n.NewError(ctx, coremsgs.MsgDXBadSize, uploadSize, storedSize)
 	}
 
-	return hash, written, payloadRef, nil
+	return hash, written, payloadRef, encryption, nil
 
 }
 
@@ -92,89 +125,262 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 		Value:     inData.Value,
 	}
 
-	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, mpart.Data)
+	hash, blobSize, payloadRef, encryption, err := bs.uploadVerifyBlob(ctx, data.ID, mpart.Data)
 	if err != nil {
 		return nil, err
 	}
-	data.Blob = &core.BlobRef{Hash: hash}
 
-	// autoMeta will create/update JSON metadata with the upload details
-	if autoMeta {
-		do := data.Value.JSONObject()
-		do["filename"] = mpart.Filename
-		do["mimetype"] = mpart.Mimetype
-		b, _ := json.Marshal(&do)
-		data.Value = fftypes.JSONAnyPtrBytes(b)
+	blob := &core.Blob{
+		Namespace:  bs.dm.namespace.Name,
+		DataID:     data.ID,
+		Hash:       hash,
+		Size:       blobSize,
+		PayloadRef: payloadRef,
+		Created:    fftypes.Now(),
+		Encryption: encryption,
 	}
-	if data.Validator == "" {
-		data.Validator = core.ValidatorTypeJSON
+	if err := bs.storeBlobData(ctx, data, blob, autoMeta, mpart.Filename, mpart.Mimetype); err != nil {
+		return nil, err
 	}
+	return data, nil
+}
 
-	blob := &core.Blob{
+// ImportBlob streams a blob received from outside of the local data exchange, such as from shared storage,
+// into the local data exchange. It is encrypted on the way if blob encryption is enabled.
+func (bs *blobStore) ImportBlob(ctx context.Context, dataID *fftypes.UUID, reader io.Reader) (*core.Blob, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	hash, blobSize, payloadRef, encryption, err := bs.uploadVerifyBlob(ctx, dataID, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Blob{
 		Namespace:  bs.dm.namespace.Name,
-		DataID:     data.ID,
+		DataID:     dataID,
 		Hash:       hash,
 		Size:       blobSize,
 		PayloadRef: payloadRef,
 		Created:    fftypes.Now(),
+		Encryption: encryption,
+	}, nil
+}
+
+// EncryptReceivedBlob encrypts a blob that a peer has transferred into the local data exchange, which the
+// data exchange stores as plaintext. The blob is re-stored encrypted, the plaintext copy is removed, and the
+// payload reference and encryption of the blob are updated. It does nothing if blob encryption is disabled.
+func (bs *blobStore) EncryptReceivedBlob(ctx context.Context, blob *core.Blob) error {
+	if bs.keys == nil || blob.Encryption != nil {
+		return nil
+	}
+	if bs.exchange == nil {
+		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	reader, err := bs.exchange.DownloadBlob(ctx, blob.PayloadRef)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	hash, blobSize, payloadRef, encryption, err := bs.uploadVerifyBlob(ctx, blob.DataID, reader)
+	if err != nil {
+		return err
+	}
+	if !hash.Equals(blob.Hash) {
+		if dxErr := bs.exchange.DeleteBlob(ctx, payloadRef); dxErr != nil {
+			log.L(ctx).Warnf("Failed to delete encrypted copy '%s' of received blob: %s", payloadRef, dxErr)
+		}
+		return i18n.NewError(ctx, coremsgs.MsgDXBadHash, hash, blob.Hash)
+	}
+	if err := bs.exchange.DeleteBlob(ctx, blob.PayloadRef); err != nil {
+		log.L(ctx).Warnf("Failed to delete plaintext copy '%s' of received blob: %s", blob.PayloadRef, err)
 	}
+	log.L(ctx).Infof("Encrypted received blob '%s' from '%s' to '%s'", blob.Hash, blob.PayloadRef, payloadRef)
+	blob.PayloadRef = payloadRef
+	blob.Size = blobSize
+	blob.Encryption = encryption
+	return nil
+}
 
-	err = bs.dm.checkValidation(ctx, data.Validator, data.Datatype, data.Value)
+// storeBlobData attaches a blob that has been stored in the data exchange to a new data item, and persists both.
+// If the data item is rejected, the stored blob is removed.
+func (bs *blobStore) storeBlobData(ctx context.Context, data *core.Data, blob *core.Blob, autoMeta bool, filename, mimetype string) error {
+	data.Blob = &core.BlobRef{Hash: blob.Hash}
+
+	// autoMeta will create/update JSON metadata with the upload details
+	if autoMeta {
+		do := data.Value.JSONObject()
+		do["filename"] = filename
+		do["mimetype"] = mimetype
+		b, _ := json.Marshal(&do)
+		data.Value = fftypes.JSONAnyPtrBytes(b)
+	}
+	if data.Validator == "" {
+		data.Validator = core.ValidatorTypeJSON
+	}
+
+	// Standalone data is not yet part of a message, so is validated against datatypes published to the whole network
+	err := bs.dm.checkValidation(ctx, data.Validator, data.Datatype, nil, data.Value, blob)
 	if err == nil {
 		err = data.Seal(ctx, blob)
 	}
 	if err != nil {
-		return nil, err
+		// The blob has already been stored, so remove it as the data is rejected
+		if dxErr := bs.exchange.DeleteBlob(ctx, blob.PayloadRef); dxErr != nil {
+			log.L(ctx).Warnf("Failed to delete rejected blob '%s': %s", blob.PayloadRef, dxErr)
+		}
+		return err
 	}
-	log.L(ctx).Infof("Uploaded Blob blobhash=%s hash=%s (%s)", data.Blob.Hash, data.Hash, units.HumanSizeWithPrecision(float64(blobSize), 2))
+	log.L(ctx).Infof("Uploaded Blob blobhash=%s hash=%s (%s)", data.Blob.Hash, data.Hash, units.HumanSizeWithPrecision(float64(blob.Size), 2))
 
-	err = bs.database.RunAsGroup(ctx, func(ctx context.Context) error {
+	return bs.database.RunAsGroup(ctx, func(ctx context.Context) error {
 		err := bs.database.UpsertData(ctx, data, database.UpsertOptimizationNew)
 		if err == nil {
 			err = bs.database.InsertBlob(ctx, blob)
 		}
 		return err
 	})
+}
+
+func (bs *blobStore) DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error) {
+	blob, err := bs.GetDataBlob(ctx, dataID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return data, nil
+	reader, err := bs.OpenBlob(ctx, blob)
+	return blob, reader, err
 }
 
-func (bs *blobStore) DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error) {
+// GetDataBlob returns the blob attached to a data item
+func (bs *blobStore) GetDataBlob(ctx context.Context, dataID string) (*core.Blob, error) {
 
 	if bs.exchange == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
 
 	id, err := fftypes.ParseUUID(ctx, dataID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	data, err := bs.database.GetDataByID(ctx, bs.dm.namespace.Name, id, false)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if data == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
 	}
 	if data.Blob == nil || data.Blob.Hash == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgDataDoesNotHaveBlob)
+		return nil, i18n.NewError(ctx, coremsgs.MsgDataDoesNotHaveBlob)
 	}
 	fb := database.BlobQueryFactory.NewFilter(ctx)
 	blobs, _, err := bs.database.GetBlobs(ctx, bs.dm.namespace.Name, fb.And(fb.Eq("data_id", data.ID), fb.Eq("hash", data.Blob.Hash)))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if len(blobs) == 0 || blobs[0] == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgBlobNotFound, data.Blob.Hash)
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobNotFound, data.Blob.Hash)
 	}
-	blob := blobs[0]
+	return blobs[0], nil
+}
 
+// OpenBlob streams the plaintext content of a blob from the local data exchange
+func (bs *blobStore) OpenBlob(ctx context.Context, blob *core.Blob) (io.ReadCloser, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	if blob.Encryption != nil && bs.keys == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionDisabled, blob.Encryption.KeyID)
+	}
 	reader, err := bs.exchange.DownloadBlob(ctx, blob.PayloadRef)
-	return blob, reader, err
+	if err != nil || blob.Encryption == nil {
+		return reader, err
+	}
+	plaintext, err := blobcrypt.Decrypt(ctx, bs.keys, blob.Encryption, reader)
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	return plaintext, nil
+}
+
+// blobRangeReader is a window of the plaintext of a blob
+type blobRangeReader struct {
+	io.Reader
+	io.Closer
+}
+
+// OpenBlobRange streams the plaintext of a blob from offset, for length bytes, downloading only that range from
+// the data exchange. For an encrypted blob the download starts at the encrypted segment containing the offset, and
+// runs to the end of the blob, so that each segment can be verified.
+func (bs *blobStore) OpenBlobRange(ctx context.Context, blob *core.Blob, offset, length int64) (io.ReadCloser, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	if blob.Encryption == nil {
+		return bs.exchange.DownloadBlobRange(ctx, blob.PayloadRef, offset, length)
+	}
+	if bs.keys == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobEncryptionDisabled, blob.Encryption.KeyID)
+	}
+	reader, err := bs.exchange.DownloadBlobRange(ctx, blob.PayloadRef, blobcrypt.SegmentStart(offset), -1)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := blobcrypt.DecryptRange(ctx, bs.keys, blob.Encryption, reader, offset)
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	return &blobRangeReader{Reader: io.LimitReader(plaintext, length), Closer: plaintext}, nil
+}
+
+// PrepareBlobTransfer returns the payload reference in the local data exchange to transfer to a peer.
+// The data exchange transfers the content it stores, so an encrypted blob is first staged as a plaintext
+// copy, which the caller must delete once the transfer has completed.
+func (bs *blobStore) PrepareBlobTransfer(ctx context.Context, blob *core.Blob, transferID *fftypes.UUID) (payloadRef string, staged bool, err error) {
+	if blob.Encryption == nil {
+		return blob.PayloadRef, false, nil
+	}
+	reader, err := bs.OpenBlob(ctx, blob)
+	if err != nil {
+		return "", false, err
+	}
+	defer reader.Close()
+	// The staged copy keeps the data ID as the last segment of its path, as that is what the recipient records
+	stagedNS := dataexchange.StagedBlobNamespace(bs.dm.namespace.NetworkName, transferID)
+	payloadRef, uploadHash, _, err := bs.exchange.UploadBlob(ctx, stagedNS, *blob.DataID, reader)
+	if err != nil {
+		return "", false, err
+	}
+	if !uploadHash.Equals(blob.Hash) {
+		if dxErr := bs.exchange.DeleteBlob(ctx, payloadRef); dxErr != nil {
+			log.L(ctx).Warnf("Failed to delete staged blob '%s': %s", payloadRef, dxErr)
+		}
+		return "", false, i18n.NewError(ctx, coremsgs.MsgDXBadHash, uploadHash, blob.Hash)
+	}
+	log.L(ctx).Infof("Staged decrypted copy '%s' of blob '%s' for transfer", payloadRef, blob.Hash)
+	return payloadRef, true, nil
+}
+
+// validateBlob streams the content of a stored blob through a validator
+func (bs *blobStore) validateBlob(ctx context.Context, bv BlobValidator, blob *core.Blob) error {
+	reader, err := bs.OpenBlob(ctx, blob)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return bv.ValidateBlob(ctx, reader)
+}
+
+func (bs *blobStore) validateDataBlob(ctx context.Context, bv BlobValidator, data *core.Data) error {
+	blob, err := bs.dm.resolveBlob(ctx, bs.dm.namespace.Name, data.Blob, data.ID)
+	if err != nil {
+		return err
+	}
+	if blob == nil {
+		return i18n.NewError(ctx, coremsgs.MsgBlobNotFound, data.Blob.Hash)
+	}
+	return bs.validateBlob(ctx, bv, blob)
 }
 
 func (bs *blobStore) DeleteBlob(ctx context.Context, blob *core.Blob) error {
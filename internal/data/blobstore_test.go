@@ -24,11 +24,13 @@ import (
 	"io"
 	"io/ioutil"
 	"math/rand"
+	"strings"
 	"testing"
 	"testing/iotest"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/data/blobcrypt"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -157,6 +159,7 @@ func TestUploadBlobBadValidator(t *testing.T) {
 		var hash fftypes.Bytes32 = sha256.Sum256(readBytes)
 		dxUpload.ReturnArguments = mock.Arguments{fmt.Sprintf("ns1/%s", uuid), &hash, int64(len(readBytes)), err}
 	}
+	mdx.On("DeleteBlob", ctx, mock.Anything).Return(fmt.Errorf("pop"))
 
 	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{
 		Value:     fftypes.JSONAnyPtr(`{"custom": "value1"}`),
@@ -563,3 +566,674 @@ func TestDeleteBlobBackwardCompatibilityFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 	mdb.AssertExpectations(t)
 }
+
+func TestUploadBlobXSDValidated(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	xml := []byte(`<order><sku>A</sku><quantity>1</quantity></order>`)
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("InsertBlob", mock.Anything, mock.Anything).Return(nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(readBytes)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(readBytes)), err}
+	}
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(xml)), nil)
+
+	data, err := dm.UploadBlob(ctx, &core.DataRefOrValue{
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+	}, &ffapi.Multipart{
+		Data:     bytes.NewReader(xml),
+		Filename: "order.xml",
+		Mimetype: "application/xml",
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ValidatorTypeXSD, data.Validator)
+	assert.Equal(t, "order.xml", data.Blob.Name)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+
+}
+
+func TestUploadBlobXSDRejected(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	xml := []byte(`<order><sku>A</sku></order>`)
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(readBytes)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(readBytes)), err}
+	}
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(xml)), nil)
+	mdx.On("DeleteBlob", ctx, "ns1/blob1").Return(nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+	}, &ffapi.Multipart{Data: bytes.NewReader(xml)}, false)
+	assert.Regexp(t, "FF10520.*FF10514", err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+
+}
+
+func TestValidateBlobDisabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	xv, err := newXSDValidator(ctx, "ns1", testXSDDatatype())
+	assert.NoError(t, err)
+	err = dm.validateBlob(ctx, xv, &core.Blob{PayloadRef: "ns1/blob1"})
+	assert.Regexp(t, "FF10414", err)
+
+}
+
+// testKeyProvider does not protect the data key, and can be told to fail
+type testKeyProvider struct {
+	err error
+}
+
+func (tkp *testKeyProvider) Name() string { return "test" }
+
+func (tkp *testKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	return "kek1", dataKey, tkp.err
+}
+
+func (tkp *testKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	return wrapped, tkp.err
+}
+
+func TestUploadDownloadBlobEncrypted(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte(strings.Repeat("some secret content ", 10000))
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	var storedBlob *core.Blob
+	mdi.On("InsertBlob", mock.Anything, mock.MatchedBy(func(blob *core.Blob) bool {
+		storedBlob = blob
+		return true
+	})).Return(nil)
+
+	var stored []byte
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		var err error
+		stored, err = ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(stored)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(stored)), err}
+	}
+
+	data, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	// The data exchange only has the ciphertext, but the blob describes the plaintext
+	assert.NotContains(t, string(stored), "secret")
+	assert.Greater(t, len(stored), len(b))
+	assert.Equal(t, [32]byte(sha256.Sum256(b)), [32]byte(*data.Blob.Hash))
+	assert.Equal(t, int64(len(b)), data.Blob.Size)
+	assert.Equal(t, int64(len(b)), storedBlob.Size)
+	assert.Equal(t, "aes-256-gcm-stream", storedBlob.Encryption.Algorithm)
+	assert.Equal(t, "kek1", storedBlob.Encryption.KeyID)
+
+	// Download decrypts the content
+	mdi.On("GetDataByID", ctx, "ns1", data.ID, false).Return(data, nil)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{storedBlob}, nil, nil)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(stored)), nil)
+
+	blob, reader, err := dm.DownloadBlob(ctx, data.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, data.Blob.Hash, blob.Hash)
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, b, content)
+	reader.Close()
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+
+}
+
+func TestUploadBlobEncryptFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{err: fmt.Errorf("pop")}
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader([]byte("hello"))}, false)
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestUploadBlobEncryptedBadSize(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte("hello")
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		stored, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(stored)
+		// The size of the plaintext is not what the data exchange should report
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(b)), err}
+	}
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.Regexp(t, "FF10323", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobEncryptionNotEnabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.OpenBlob(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{KeyID: "kek1"},
+	})
+	assert.Regexp(t, "FF10531.*kek1", err)
+
+}
+
+func TestOpenBlobDownloadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.OpenBlob(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{Algorithm: "aes-256-gcm-stream", KeyID: "kek1"},
+	})
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobDecryptFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader([]byte{})), nil)
+
+	_, err := dm.OpenBlob(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{Algorithm: "rot13", KeyID: "kek1"},
+	})
+	assert.Regexp(t, "FF10530", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobRangePlaintext(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", int64(6), int64(4)).Return(ioutil.NopCloser(bytes.NewReader([]byte("worl"))), nil)
+
+	reader, err := dm.OpenBlobRange(ctx, &core.Blob{PayloadRef: "ns1/blob1"}, 6, 4)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "worl", string(b))
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobRangeEncrypted(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	content := make([]byte, 200*1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	blob, stored := encryptedTestBlob(t, ctx, dm.keys, content)
+	offset := int64(100000)
+	segmentStart := blobcrypt.SegmentStart(offset)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", segmentStart, int64(-1)).Return(ioutil.NopCloser(bytes.NewReader(stored[segmentStart:])), nil)
+
+	reader, err := dm.OpenBlobRange(ctx, blob, offset, 50000)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, content[offset:offset+50000], b)
+	assert.NoError(t, reader.Close())
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobRangeDisabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.OpenBlobRange(ctx, &core.Blob{PayloadRef: "ns1/blob1"}, 0, 1)
+	assert.Regexp(t, "FF10414", err)
+
+}
+
+func TestOpenBlobRangeEncryptionNotEnabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.OpenBlobRange(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{KeyID: "kek1"},
+	}, 0, 1)
+	assert.Regexp(t, "FF10531.*kek1", err)
+
+}
+
+func TestOpenBlobRangeDownloadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", int64(0), int64(-1)).Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.OpenBlobRange(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{Algorithm: "aes-256-gcm-stream", KeyID: "kek1"},
+	}, 10, 1)
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestOpenBlobRangeDecryptFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", int64(0), int64(-1)).Return(ioutil.NopCloser(bytes.NewReader([]byte{})), nil)
+
+	_, err := dm.OpenBlobRange(ctx, &core.Blob{
+		PayloadRef: "ns1/blob1",
+		Encryption: &core.BlobEncryption{Algorithm: "rot13", KeyID: "kek1"},
+	}, 10, 1)
+	assert.Regexp(t, "FF10530", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func encryptedTestBlob(t *testing.T, ctx context.Context, kp blobcrypt.KeyProvider, content []byte) (*core.Blob, []byte) {
+	reader, encryption, err := blobcrypt.Encrypt(ctx, kp, bytes.NewReader(content))
+	assert.NoError(t, err)
+	stored, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	var hash fftypes.Bytes32 = sha256.Sum256(content)
+	return &core.Blob{
+		Hash:       &hash,
+		PayloadRef: "ns1/blob1",
+		DataID:     fftypes.NewUUID(),
+		Encryption: encryption,
+	}, stored
+}
+
+func TestPrepareBlobTransferNotEncrypted(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	payloadRef, staged, err := dm.PrepareBlobTransfer(ctx, &core.Blob{PayloadRef: "ns1/blob1"}, fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.False(t, staged)
+	assert.Equal(t, "ns1/blob1", payloadRef)
+
+}
+
+func TestPrepareBlobTransferStaged(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte("some secret content")
+	blob, stored := encryptedTestBlob(t, ctx, dm.keys, b)
+	transferID := fftypes.NewUUID()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(stored)), nil)
+	dxUpload := mdx.On("UploadBlob", ctx, "staged/"+transferID.String()+"/ns1", *blob.DataID, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		plaintext, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		assert.Equal(t, b, plaintext)
+		var hash fftypes.Bytes32 = sha256.Sum256(plaintext)
+		// The data exchange plugins store the blob at "namespace/id"
+		id := a[2].(fftypes.UUID)
+		payloadRef := fmt.Sprintf("%s/%s", a[1], &id)
+		dxUpload.ReturnArguments = mock.Arguments{payloadRef, &hash, int64(len(plaintext)), err}
+	}
+
+	payloadRef, staged, err := dm.PrepareBlobTransfer(ctx, blob, transferID)
+	assert.NoError(t, err)
+	assert.True(t, staged)
+
+	// The recipient takes the namespace and data ID from the last two segments of the path it receives
+	segments := strings.Split(payloadRef, "/")
+	assert.Len(t, segments, 4)
+	assert.Equal(t, "ns1", segments[len(segments)-2])
+	assert.Equal(t, blob.DataID.String(), segments[len(segments)-1])
+	assert.NotEqual(t, blob.PayloadRef, payloadRef)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestPrepareBlobTransferBadHash(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	blob, stored := encryptedTestBlob(t, ctx, dm.keys, []byte("some secret content"))
+	blob.Hash = fftypes.NewRandB32()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(stored)), nil)
+	dxUpload := mdx.On("UploadBlob", ctx, mock.Anything, *blob.DataID, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		plaintext, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(plaintext)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/staged1", &hash, int64(len(plaintext)), err}
+	}
+	mdx.On("DeleteBlob", ctx, "ns1/staged1").Return(fmt.Errorf("pop"))
+
+	_, _, err := dm.PrepareBlobTransfer(ctx, blob, fftypes.NewUUID())
+	assert.Regexp(t, "FF10238", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestPrepareBlobTransferUploadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	blob, stored := encryptedTestBlob(t, ctx, dm.keys, []byte("some secret content"))
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(stored)), nil)
+	mdx.On("UploadBlob", ctx, mock.Anything, *blob.DataID, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop"))
+
+	_, _, err := dm.PrepareBlobTransfer(ctx, blob, fftypes.NewUUID())
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestPrepareBlobTransferOpenFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, _, err := dm.PrepareBlobTransfer(ctx, &core.Blob{Encryption: &core.BlobEncryption{KeyID: "kek1"}}, fftypes.NewUUID())
+	assert.Regexp(t, "FF10531", err)
+
+}
+
+func TestImportBlobEncrypted(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte("some secret content")
+	dataID := fftypes.NewUUID()
+
+	var stored []byte
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", *dataID, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		var err error
+		stored, err = ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(stored)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(stored)), err}
+	}
+
+	blob, err := dm.ImportBlob(ctx, dataID, bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stored), "secret")
+	assert.Equal(t, [32]byte(sha256.Sum256(b)), [32]byte(*blob.Hash))
+	assert.Equal(t, int64(len(b)), blob.Size)
+	assert.Equal(t, "ns1/blob1", blob.PayloadRef)
+	assert.Equal(t, dataID, blob.DataID)
+	assert.Equal(t, "kek1", blob.Encryption.KeyID)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestImportBlobFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop"))
+
+	_, err := dm.ImportBlob(ctx, fftypes.NewUUID(), bytes.NewReader([]byte("hello")))
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestImportBlobDisabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.ImportBlob(ctx, fftypes.NewUUID(), bytes.NewReader([]byte("hello")))
+	assert.Regexp(t, "FF10414", err)
+
+}
+
+func TestEncryptReceivedBlobOk(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte("some secret content")
+	var hash fftypes.Bytes32 = sha256.Sum256(b)
+	blob := &core.Blob{
+		Hash:       &hash,
+		Size:       int64(len(b)),
+		PayloadRef: "received/peer1/ns1/blob1",
+		DataID:     fftypes.NewUUID(),
+	}
+
+	var stored []byte
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "received/peer1/ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(b)), nil)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", *blob.DataID, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		var err error
+		stored, err = ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(stored)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(stored)), err}
+	}
+	mdx.On("DeleteBlob", ctx, "received/peer1/ns1/blob1").Return(fmt.Errorf("pop"))
+
+	err := dm.EncryptReceivedBlob(ctx, blob)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stored), "secret")
+	assert.Equal(t, "ns1/blob1", blob.PayloadRef)
+	assert.Equal(t, int64(len(b)), blob.Size)
+	assert.Equal(t, "kek1", blob.Encryption.KeyID)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestEncryptReceivedBlobNotEnabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blob := &core.Blob{PayloadRef: "received/peer1/ns1/blob1"}
+	err := dm.EncryptReceivedBlob(ctx, blob)
+	assert.NoError(t, err)
+	assert.Equal(t, "received/peer1/ns1/blob1", blob.PayloadRef)
+	assert.Nil(t, blob.Encryption)
+
+}
+
+func TestEncryptReceivedBlobDisabled(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+	dm.exchange = nil
+
+	err := dm.EncryptReceivedBlob(ctx, &core.Blob{PayloadRef: "received/peer1/ns1/blob1"})
+	assert.Regexp(t, "FF10414", err)
+
+}
+
+func TestEncryptReceivedBlobBadHash(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	blob := &core.Blob{
+		Hash:       fftypes.NewRandB32(),
+		PayloadRef: "received/peer1/ns1/blob1",
+		DataID:     fftypes.NewUUID(),
+	}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "received/peer1/ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader([]byte("hello"))), nil)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", *blob.DataID, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		stored, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		var hash fftypes.Bytes32 = sha256.Sum256(stored)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(stored)), err}
+	}
+	mdx.On("DeleteBlob", ctx, "ns1/blob1").Return(fmt.Errorf("pop"))
+
+	err := dm.EncryptReceivedBlob(ctx, blob)
+	assert.Regexp(t, "FF10238", err)
+	assert.Equal(t, "received/peer1/ns1/blob1", blob.PayloadRef)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestEncryptReceivedBlobUploadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "received/peer1/ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader([]byte("hello"))), nil)
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop"))
+
+	err := dm.EncryptReceivedBlob(ctx, &core.Blob{PayloadRef: "received/peer1/ns1/blob1", DataID: fftypes.NewUUID()})
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
+
+func TestEncryptReceivedBlobDownloadFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "received/peer1/ns1/blob1").Return(nil, fmt.Errorf("pop"))
+
+	err := dm.EncryptReceivedBlob(ctx, &core.Blob{PayloadRef: "received/peer1/ns1/blob1"})
+	assert.Regexp(t, "pop", err)
+
+	mdx.AssertExpectations(t)
+
+}
@@ -0,0 +1,417 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// A resumable blob upload receives the content of a blob as a sequence of chunks, each in its own request.
+// Each chunk is staged as a separate blob in the data exchange, and the SHA-256 hash of the whole blob is
+// calculated incrementally - the state of the hash is stored on the upload between chunks. Finalizing the
+// upload streams the chunks in order into a single blob in the background, attached to a new data item.
+
+func restoreUploadHash(ctx context.Context, upload *core.BlobUpload) (hash.Hash, error) {
+	hashCalc := sha256.New()
+	if upload.HashState != "" {
+		b, err := base64.StdEncoding.DecodeString(upload.HashState)
+		if err == nil {
+			err = hashCalc.(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+		}
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadHashStateInvalid, upload.ID)
+		}
+	}
+	return hashCalc, nil
+}
+
+func saveUploadHash(hashCalc hash.Hash) string {
+	b, _ := hashCalc.(encoding.BinaryMarshaler).MarshalBinary()
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func (bs *blobStore) uploadExpiry(now *fftypes.FFTime) *fftypes.FFTime {
+	expires := fftypes.FFTime(time.Time(*now).Add(bs.uploadTimeout))
+	return &expires
+}
+
+func (bs *blobStore) CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	if input.Size < 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadSizeInvalid, input.Size)
+	}
+
+	now := fftypes.Now()
+	upload := &core.BlobUpload{
+		ID:        fftypes.NewUUID(),
+		Namespace: bs.dm.namespace.Name,
+		State:     core.BlobUploadStateReceiving,
+		Size:      input.Size,
+		Created:   now,
+		Updated:   now,
+		Expires:   bs.uploadExpiry(now),
+	}
+	if err := bs.database.InsertBlobUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+func (bs *blobStore) GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	id, err := fftypes.ParseUUID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := bs.database.GetBlobUploadByID(ctx, bs.dm.namespace.Name, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	return upload, nil
+}
+
+// UploadBlobChunk appends a chunk to an upload. The offset must match the number of bytes received so far,
+// so a client that loses a response can query the upload, and resume from where it left off.
+func (bs *blobStore) UploadBlobChunk(ctx context.Context, uploadID string, offset int64, reader io.Reader) (*core.BlobUpload, error) {
+	upload, err := bs.GetBlobUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.State != core.BlobUploadStateReceiving {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadInvalidState, upload.ID, upload.State)
+	}
+	if offset != upload.Received {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadOffsetMismatch, offset, upload.Received, upload.ID)
+	}
+	hashCalc, err := restoreUploadHash(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Size > 0 {
+		// Read at most one byte more than the upload can take, to detect a chunk that is too large
+		reader = io.LimitReader(reader, upload.Size-upload.Received+1)
+	}
+
+	_, written, payloadRef, encryption, err := bs.uploadVerifyBlob(ctx, fftypes.NewUUID(), io.TeeReader(reader, hashCalc))
+	if err != nil {
+		return nil, err
+	}
+	if written == 0 {
+		bs.deleteStagedChunk(ctx, payloadRef)
+		return upload, nil
+	}
+	if upload.Size > 0 && upload.Received+written > upload.Size {
+		bs.deleteStagedChunk(ctx, payloadRef)
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadSizeExceeded, upload.Size, upload.ID)
+	}
+
+	chunks := append(upload.Chunks, &core.BlobUploadChunk{
+		Offset:     upload.Received,
+		Size:       written,
+		PayloadRef: payloadRef,
+		Encryption: encryption,
+	})
+	chunksJSON, _ := json.Marshal(chunks)
+	hashState := saveUploadHash(hashCalc)
+	now := fftypes.Now()
+	expires := bs.uploadExpiry(now)
+
+	// The update only applies if no other chunk has been recorded since the upload was read
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	update := database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("received", upload.Received+written).
+		Set("chunks", fftypes.JSONAnyPtrBytes(chunksJSON)).
+		Set("hashstate", hashState).
+		Set("updated", now).
+		Set("expires", expires)
+	updated, err := bs.database.UpdateBlobUpload(ctx, bs.dm.namespace.Name, upload.ID, fb.And(
+		fb.Eq("state", core.BlobUploadStateReceiving),
+		fb.Eq("received", upload.Received),
+	), update)
+	if err == nil && !updated {
+		err = i18n.NewError(ctx, coremsgs.MsgBlobUploadConflict, upload.ID)
+	}
+	if err != nil {
+		bs.deleteStagedChunk(ctx, payloadRef)
+		return nil, err
+	}
+
+	upload.Received += written
+	upload.Chunks = chunks
+	upload.HashState = hashState
+	upload.Updated = now
+	upload.Expires = expires
+	log.L(ctx).Debugf("Upload %s received chunk of %d bytes (total=%d)", upload.ID, written, upload.Received)
+	return upload, nil
+}
+
+// FinalizeBlobUpload checks a complete upload against the expected hash, and then streams its chunks into a single
+// blob in the background, as that can take far longer than an API request for a large blob. The upload records the
+// ID of the data item that will be created, and its state shows when the data item is ready - or why it failed.
+func (bs *blobStore) FinalizeBlobUpload(ctx context.Context, uploadID string, input *core.BlobUploadFinalize) (*core.BlobUpload, error) {
+	upload, err := bs.GetBlobUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.State != core.BlobUploadStateReceiving && upload.State != core.BlobUploadStateFailed {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadInvalidState, upload.ID, upload.State)
+	}
+	if upload.Size > 0 && upload.Received != upload.Size {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadIncomplete, upload.ID, upload.Received, upload.Size)
+	}
+	hashCalc, err := restoreUploadHash(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	uploadHash := fftypes.HashResult(hashCalc)
+	if input.Hash != nil && !input.Hash.Equals(uploadHash) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadHashMismatch, uploadHash, input.Hash)
+	}
+
+	dataID := fftypes.NewUUID()
+	finalizeJSON, _ := json.Marshal(input)
+	now := fftypes.Now()
+	expires := bs.uploadExpiry(now)
+
+	// The update only applies if no other request has changed the upload since it was read
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	update := database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("state", core.BlobUploadStateFinalizing).
+		Set("data", dataID).
+		Set("error", "").
+		Set("finalize", fftypes.JSONAnyPtrBytes(finalizeJSON)).
+		Set("updated", now).
+		Set("expires", expires)
+	updated, err := bs.database.UpdateBlobUpload(ctx, bs.dm.namespace.Name, upload.ID, fb.And(
+		fb.Eq("state", upload.State),
+		fb.Eq("received", upload.Received),
+	), update)
+	if err == nil && !updated {
+		err = i18n.NewError(ctx, coremsgs.MsgBlobUploadConflict, upload.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	upload.State = core.BlobUploadStateFinalizing
+	upload.Data = dataID
+	upload.Error = ""
+	upload.Finalize = input
+	upload.Updated = now
+	upload.Expires = expires
+	bs.startFinalizer(upload)
+	return upload, nil
+}
+
+// resumeBlobUploads restarts the finalizing of any uploads that were interrupted by a shutdown
+func (bs *blobStore) resumeBlobUploads() {
+	fb := database.BlobUploadQueryFactory.NewFilter(bs.ctx)
+	uploads, _, err := bs.database.GetBlobUploads(bs.ctx, bs.dm.namespace.Name, fb.Eq("state", core.BlobUploadStateFinalizing))
+	if err != nil {
+		log.L(bs.ctx).Errorf("Failed to query blob uploads to resume: %s", err)
+		return
+	}
+	for _, upload := range uploads {
+		log.L(bs.ctx).Infof("Resuming finalize of upload %s", upload.ID)
+		bs.startFinalizer(upload)
+	}
+}
+
+func (bs *blobStore) startFinalizer(upload *core.BlobUpload) {
+	bs.finalizers.Add(1)
+	go func() {
+		defer bs.finalizers.Done()
+		bs.finalizeBlobUpload(upload)
+	}()
+}
+
+// finalizeBlobUpload stores the data item for an upload, and records the outcome on the upload. If the node is
+// shutting down the upload is left finalizing, so that it is resumed on restart.
+func (bs *blobStore) finalizeBlobUpload(upload *core.BlobUpload) {
+	ctx := log.WithLogField(bs.ctx, "upload", upload.ID.String())
+	err := bs.storeBlobUpload(ctx, upload)
+	if err != nil && ctx.Err() != nil {
+		log.L(ctx).Infof("Finalize of upload %s interrupted: %s", upload.ID, err)
+		return
+	}
+
+	now := fftypes.Now()
+	update := database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("updated", now).
+		Set("expires", bs.uploadExpiry(now))
+	if err != nil {
+		log.L(ctx).Errorf("Failed to finalize upload %s: %s", upload.ID, err)
+		update.Set("state", core.BlobUploadStateFailed).Set("error", err.Error())
+	} else {
+		// The staged chunks are deleted below, so the upload only remains to report the data item
+		update.Set("state", core.BlobUploadStateComplete).Set("chunks", nil)
+	}
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	if _, dbErr := bs.database.UpdateBlobUpload(ctx, bs.dm.namespace.Name, upload.ID, fb.Eq("state", core.BlobUploadStateFinalizing), update); dbErr != nil {
+		log.L(ctx).Errorf("Failed to record the outcome of finalizing upload %s: %s", upload.ID, dbErr)
+		return
+	}
+	if err == nil {
+		for _, chunk := range upload.Chunks {
+			bs.deleteStagedChunk(ctx, chunk.PayloadRef)
+		}
+		log.L(ctx).Infof("Finalized upload %s as data %s", upload.ID, upload.Data)
+	}
+}
+
+// storeBlobUpload streams the chunks of an upload into a single blob, and attaches it to the data item of the upload
+func (bs *blobStore) storeBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	// A previous attempt might have stored the data item, but not recorded the outcome on the upload
+	existing, err := bs.database.GetDataByID(ctx, bs.dm.namespace.Name, upload.Data, false)
+	if err != nil || existing != nil {
+		return err
+	}
+	hashCalc, err := restoreUploadHash(ctx, upload)
+	if err != nil {
+		return err
+	}
+	uploadHash := fftypes.HashResult(hashCalc)
+
+	input := upload.Finalize
+	data := &core.Data{
+		ID:        upload.Data,
+		Namespace: bs.dm.namespace.Name,
+		Created:   fftypes.Now(),
+		Validator: input.Validator,
+		Datatype:  input.Datatype,
+		Value:     input.Value,
+	}
+
+	chunks := &chunkReader{ctx: ctx, bs: bs, chunks: upload.Chunks}
+	hash, blobSize, payloadRef, encryption, err := bs.uploadVerifyBlob(ctx, data.ID, chunks)
+	chunks.Close()
+	if err != nil {
+		return err
+	}
+	if !hash.Equals(uploadHash) {
+		// The staged chunks no longer match what was received
+		bs.deleteStagedChunk(ctx, payloadRef)
+		return i18n.NewError(ctx, coremsgs.MsgBlobUploadHashMismatch, hash, uploadHash)
+	}
+
+	blob := &core.Blob{
+		Namespace:  bs.dm.namespace.Name,
+		DataID:     data.ID,
+		Hash:       hash,
+		Size:       blobSize,
+		PayloadRef: payloadRef,
+		Created:    fftypes.Now(),
+		Encryption: encryption,
+	}
+	return bs.storeBlobData(ctx, data, blob, input.AutoMeta, input.Filename, input.Mimetype)
+}
+
+func (bs *blobStore) CancelBlobUpload(ctx context.Context, uploadID string) error {
+	upload, err := bs.GetBlobUpload(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	if upload.State == core.BlobUploadStateFinalizing {
+		return i18n.NewError(ctx, coremsgs.MsgBlobUploadInvalidState, upload.ID, upload.State)
+	}
+	return bs.deleteBlobUpload(ctx, upload)
+}
+
+// PurgeBlobUpload deletes an upload that has expired without being finalized, along with its staged chunks
+func (bs *blobStore) PurgeBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	if bs.exchange == nil {
+		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	return bs.deleteBlobUpload(ctx, upload)
+}
+
+func (bs *blobStore) deleteBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	for _, chunk := range upload.Chunks {
+		bs.deleteStagedChunk(ctx, chunk.PayloadRef)
+	}
+	return bs.database.DeleteBlobUpload(ctx, bs.dm.namespace.Name, upload.ID)
+}
+
+func (bs *blobStore) deleteStagedChunk(ctx context.Context, payloadRef string) {
+	if err := bs.exchange.DeleteBlob(ctx, payloadRef); err != nil {
+		log.L(ctx).Warnf("Failed to delete staged chunk '%s': %s", payloadRef, err)
+	}
+}
+
+// chunkReader streams the plaintext of the staged chunks of an upload in order, opening each chunk only
+// once the previous one has been read
+type chunkReader struct {
+	ctx     context.Context
+	bs      *blobStore
+	chunks  core.BlobUploadChunks
+	current io.ReadCloser
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if cr.current == nil {
+			if len(cr.chunks) == 0 {
+				return 0, io.EOF
+			}
+			chunk := cr.chunks[0]
+			cr.chunks = cr.chunks[1:]
+			reader, err := cr.bs.OpenBlob(cr.ctx, &core.Blob{PayloadRef: chunk.PayloadRef, Encryption: chunk.Encryption})
+			if err != nil {
+				return 0, err
+			}
+			cr.current = reader
+		}
+		n, err := cr.current.Read(p)
+		if err == io.EOF {
+			_ = cr.current.Close()
+			cr.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (cr *chunkReader) Close() {
+	if cr.current != nil {
+		_ = cr.current.Close()
+		cr.current = nil
+	}
+}
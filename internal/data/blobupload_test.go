@@ -0,0 +1,751 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testBlobStorage emulates the blob storage of the data exchange in memory
+type testBlobStorage struct {
+	mux   sync.Mutex
+	blobs map[string][]byte
+}
+
+func newTestBlobStorage(t *testing.T, mdx *dataexchangemocks.Plugin) *testBlobStorage {
+	tbs := &testBlobStorage{blobs: make(map[string][]byte)}
+	dxUpload := mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		b, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.NoError(t, err)
+		payloadRef := fmt.Sprintf("ns1/%s", a[2].(fftypes.UUID))
+		tbs.mux.Lock()
+		tbs.blobs[payloadRef] = b
+		tbs.mux.Unlock()
+		var hash fftypes.Bytes32 = sha256.Sum256(b)
+		dxUpload.ReturnArguments = mock.Arguments{payloadRef, &hash, int64(len(b)), nil}
+	}
+	dxDownload := mdx.On("DownloadBlob", mock.Anything, mock.Anything)
+	dxDownload.RunFn = func(a mock.Arguments) {
+		tbs.mux.Lock()
+		b := tbs.blobs[a[1].(string)]
+		tbs.mux.Unlock()
+		dxDownload.ReturnArguments = mock.Arguments{ioutil.NopCloser(bytes.NewReader(b)), nil}
+	}
+	dxDelete := mdx.On("DeleteBlob", mock.Anything, mock.Anything)
+	dxDelete.RunFn = func(a mock.Arguments) {
+		tbs.mux.Lock()
+		delete(tbs.blobs, a[1].(string))
+		tbs.mux.Unlock()
+		dxDelete.ReturnArguments = mock.Arguments{nil}
+	}
+	return tbs
+}
+
+// matchUploadState matches an update that moves an upload to the supplied state
+func matchUploadState(state core.BlobUploadState) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		return uploadUpdateValue(update, "state") == string(state)
+	})
+}
+
+// matchUploadError matches an update that fails an upload with an error matching the supplied regexp
+func matchUploadError(errRegexp string) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		errMsg, _ := uploadUpdateValue(update, "error").(string)
+		return uploadUpdateValue(update, "state") == string(core.BlobUploadStateFailed) &&
+			regexp.MustCompile(errRegexp).MatchString(errMsg)
+	})
+}
+
+func uploadUpdateValue(update ffapi.Update, field string) interface{} {
+	info, err := update.Finalize()
+	if err != nil {
+		return nil
+	}
+	for _, op := range info.SetOperations {
+		if op.Field == field {
+			v, _ := op.Value.Value()
+			return v
+		}
+	}
+	return nil
+}
+
+func TestBlobUploadChunkedEncryptedOk(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.keys = &testKeyProvider{}
+
+	b := []byte(strings.Repeat("some secret content ", 1000))
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	var upload *core.BlobUpload
+	mdi.On("InsertBlobUpload", ctx, mock.MatchedBy(func(u *core.BlobUpload) bool {
+		upload = u
+		return true
+	})).Return(nil)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, id *fftypes.UUID) *core.BlobUpload {
+		return upload
+	}, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", mock.Anything, mock.Anything, matchUploadState(core.BlobUploadStateComplete)).Return(true, nil)
+	mdi.On("GetDataByID", mock.Anything, "ns1", mock.Anything, false).Return(nil, nil)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	var data *core.Data
+	mdi.On("UpsertData", mock.Anything, mock.MatchedBy(func(d *core.Data) bool {
+		data = d
+		return true
+	}), database.UpsertOptimizationNew).Return(nil)
+	var storedBlob *core.Blob
+	mdi.On("InsertBlob", mock.Anything, mock.MatchedBy(func(blob *core.Blob) bool {
+		storedBlob = blob
+		return true
+	})).Return(nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	tbs := newTestBlobStorage(t, mdx)
+
+	created, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: int64(len(b))})
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", created.Namespace)
+	assert.True(t, created.Expires.Time().After(*created.Created.Time()))
+
+	// Send the content in three chunks, with the last one taking it to the declared size
+	for _, r := range [][2]int{{0, 7000}, {7000, 14000}, {14000, len(b)}} {
+		upload, err := dm.UploadBlobChunk(ctx, created.ID.String(), int64(r[0]), bytes.NewReader(b[r[0]:r[1]]))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(r[1]), upload.Received)
+	}
+	assert.Len(t, upload.Chunks, 3)
+	assert.Len(t, tbs.blobs, 3)
+	for _, chunk := range upload.Chunks {
+		assert.NotNil(t, chunk.Encryption)
+		assert.NotContains(t, string(tbs.blobs[chunk.PayloadRef]), "secret")
+	}
+
+	// An empty chunk is accepted, but not recorded
+	upload, err = dm.UploadBlobChunk(ctx, created.ID.String(), int64(len(b)), bytes.NewReader([]byte{}))
+	assert.NoError(t, err)
+	assert.Len(t, upload.Chunks, 3)
+
+	var expectedHash fftypes.Bytes32 = sha256.Sum256(b)
+	finalizing, err := dm.FinalizeBlobUpload(ctx, created.ID.String(), &core.BlobUploadFinalize{
+		Hash:     &expectedHash,
+		AutoMeta: true,
+		Filename: "file.txt",
+		Mimetype: "text/plain",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStateFinalizing, finalizing.State)
+	assert.NotNil(t, finalizing.Data)
+
+	// The chunks are combined in the background
+	dm.finalizers.Wait()
+	assert.Equal(t, finalizing.Data, data.ID)
+	assert.Equal(t, expectedHash, *data.Blob.Hash)
+	assert.Equal(t, "file.txt", data.Value.JSONObject().GetString("filename"))
+	assert.Equal(t, int64(len(b)), storedBlob.Size)
+	assert.NotNil(t, storedBlob.Encryption)
+
+	// Only the final blob remains
+	assert.Len(t, tbs.blobs, 1)
+	reader, err := dm.OpenBlob(ctx, storedBlob)
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, b, content)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+
+}
+
+func TestBlobUploadNoSizeOk(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), Namespace: "ns1", State: core.BlobUploadStateReceiving}
+	mdi.On("GetBlobUploadByID", ctx, "ns1", upload.ID).Return(upload, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", upload.ID, mock.Anything, mock.Anything).Return(true, nil)
+	mdi.On("GetDataByID", mock.Anything, "ns1", mock.Anything, false).Return(nil, nil)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadState(core.BlobUploadStateComplete)).Return(false, fmt.Errorf("pop"))
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	tbs := newTestBlobStorage(t, mdx)
+
+	_, err := dm.UploadBlobChunk(ctx, upload.ID.String(), 0, strings.NewReader("hello"))
+	assert.NoError(t, err)
+	_, err = dm.UploadBlobChunk(ctx, upload.ID.String(), 5, strings.NewReader(" world"))
+	assert.NoError(t, err)
+
+	var expectedHash fftypes.Bytes32 = sha256.Sum256([]byte("hello world"))
+	finalizing, err := dm.FinalizeBlobUpload(ctx, upload.ID.String(), &core.BlobUploadFinalize{Hash: &expectedHash})
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStateFinalizing, finalizing.State)
+
+	// A failure to record the outcome leaves the staged chunks in place, alongside the blob
+	dm.finalizers.Wait()
+	assert.Len(t, tbs.blobs, 3)
+
+	mdi.AssertExpectations(t)
+
+}
+
+func TestCreateBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestCreateBlobUploadBadSize(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: -1})
+	assert.Regexp(t, "FF10543", err)
+}
+
+func TestCreateBlobUploadInsertFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertBlobUpload", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: 10})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.GetBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestGetBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.GetBlobUpload(ctx, "!uuid")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetBlobUploadLookupFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.GetBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.GetBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestUploadBlobChunkNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestUploadBlobChunkNotReceiving(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateFinalizing}, nil)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10585.*finalizing", err)
+}
+
+func TestUploadBlobChunkOffsetMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving, Received: 10}, nil)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 5, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10545", err)
+}
+
+func TestUploadBlobChunkBadHashState(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving, HashState: "!base64"}, nil)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestUploadBlobChunkUploadFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving}, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(0), fmt.Errorf("pop"))
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUploadBlobChunkSizeExceeded(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving, Size: 8, Received: 4}, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	tbs := newTestBlobStorage(t, mdx)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 4, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10547", err)
+	assert.Empty(t, tbs.blobs)
+}
+
+func TestUploadBlobChunkUpdateFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving}, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	tbs := newTestBlobStorage(t, mdx)
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, tbs.blobs)
+}
+
+func TestUploadBlobChunkConflict(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving}, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	var hash fftypes.Bytes32 = sha256.Sum256([]byte("hello"))
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("ns1/chunk1", &hash, int64(5), nil).
+		Run(func(a mock.Arguments) {
+			_, _ = ioutil.ReadAll(a[3].(io.Reader))
+		})
+	mdx.On("DeleteBlob", ctx, "ns1/chunk1").Return(fmt.Errorf("pop"))
+
+	_, err := dm.UploadBlobChunk(ctx, fftypes.NewUUID().String(), 0, strings.NewReader("hello"))
+	assert.Regexp(t, "FF10546", err)
+	mdx.AssertExpectations(t)
+}
+
+func TestChunkReaderDataWithEOF(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/chunk1").Return(ioutil.NopCloser(iotest.DataErrReader(strings.NewReader("hello"))), nil)
+	mdx.On("DownloadBlob", ctx, "ns1/chunk2").Return(ioutil.NopCloser(iotest.DataErrReader(strings.NewReader(" world"))), nil)
+
+	cr := &chunkReader{ctx: ctx, bs: &dm.blobStore, chunks: core.BlobUploadChunks{
+		{PayloadRef: "ns1/chunk1"},
+		{PayloadRef: "ns1/chunk2"},
+	}}
+	b, err := ioutil.ReadAll(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+	cr.Close()
+}
+
+func TestFinalizeBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestFinalizeBlobUploadAlreadyFinalizing(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateFinalizing}, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.Regexp(t, "FF10585.*finalizing", err)
+}
+
+func TestFinalizeBlobUploadIncomplete(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving, Size: 10, Received: 5}, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.Regexp(t, "FF10548", err)
+}
+
+func TestFinalizeBlobUploadBadHashState(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving, HashState: "aGVsbG8="}, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestFinalizeBlobUploadExpectedHashMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving}, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{Hash: fftypes.NewRandB32()})
+	assert.Regexp(t, "FF10549", err)
+}
+
+func TestFinalizeBlobUploadUpdateFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateFailed}, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", mock.Anything, mock.Anything, matchUploadState(core.BlobUploadStateFinalizing)).Return(false, fmt.Errorf("pop"))
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.EqualError(t, err, "pop")
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadConflict(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(&core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateReceiving}, nil)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", mock.Anything, mock.Anything, matchUploadState(core.BlobUploadStateFinalizing)).Return(false, nil)
+
+	_, err := dm.FinalizeBlobUpload(ctx, fftypes.NewUUID().String(), &core.BlobUploadFinalize{})
+	assert.Regexp(t, "FF10546", err)
+	mdi.AssertExpectations(t)
+}
+
+func newTestFinalizingUpload(chunks ...*core.BlobUploadChunk) *core.BlobUpload {
+	return &core.BlobUpload{
+		ID:       fftypes.NewUUID(),
+		State:    core.BlobUploadStateFinalizing,
+		Data:     fftypes.NewUUID(),
+		Chunks:   chunks,
+		Finalize: &core.BlobUploadFinalize{},
+	}
+}
+
+func TestFinalizeBlobUploadDataExists(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload(&core.BlobUploadChunk{PayloadRef: "ns1/chunk1"})
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(&core.Data{ID: upload.Data}, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadState(core.BlobUploadStateComplete)).Return(true, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", mock.Anything, "ns1/chunk1").Return(nil)
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadGetDataFail(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadState(core.BlobUploadStateFailed)).Return(true, nil)
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadInterrupted(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	cancel()
+	defer dm.WaitStop()
+
+	upload := newTestFinalizingUpload()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, fmt.Errorf("pop"))
+
+	// The upload is left finalizing, to be resumed on restart
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+	mdi.AssertNotCalled(t, "UpdateBlobUpload", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinalizeBlobUploadBackgroundBadHashState(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload()
+	upload.HashState = "aGVsbG8="
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadState(core.BlobUploadStateFailed)).Return(true, nil)
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadOpenChunkFail(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload(&core.BlobUploadChunk{PayloadRef: "ns1/chunk1", Encryption: &core.BlobEncryption{KeyID: "kek1"}})
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadError("FF10531")).Return(true, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	newTestBlobStorage(t, mdx)
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadUploadFail(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload(&core.BlobUploadChunk{PayloadRef: "ns1/chunk1"})
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadError("pop")).Return(true, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", mock.Anything, "ns1/chunk1").Return(ioutil.NopCloser(strings.NewReader("hello")), nil)
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(0), fmt.Errorf("pop"))
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadChunksChanged(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload(&core.BlobUploadChunk{PayloadRef: "ns1/chunk1"})
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadError("FF10549")).Return(true, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	tbs := newTestBlobStorage(t, mdx)
+	tbs.blobs["ns1/chunk1"] = []byte("hello")
+
+	dm.finalizeBlobUpload(upload)
+
+	// The chunk is kept, and the combined blob removed
+	assert.Len(t, tbs.blobs, 1)
+	mdi.AssertExpectations(t)
+}
+
+func TestFinalizeBlobUploadStoreFail(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(nil, nil)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadError("pop")).Return(true, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	newTestBlobStorage(t, mdx)
+
+	dm.finalizeBlobUpload(upload)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResumeBlobUploads(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := newTestFinalizingUpload()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{upload}, nil, nil)
+	mdi.On("GetDataByID", mock.Anything, "ns1", upload.Data, false).Return(&core.Data{ID: upload.Data}, nil)
+	mdi.On("UpdateBlobUpload", mock.Anything, "ns1", upload.ID, mock.Anything, matchUploadState(core.BlobUploadStateComplete)).Return(true, nil)
+
+	dm.resumeBlobUploads()
+	dm.finalizers.Wait()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResumeBlobUploadsQueryFail(t *testing.T) {
+	dm, _, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.ExpectedCalls = nil
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	dm.resumeBlobUploads()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCancelBlobUpload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), Chunks: core.BlobUploadChunks{
+		{PayloadRef: "ns1/chunk1"},
+		{PayloadRef: "ns1/chunk2"},
+	}}
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", upload.ID).Return(upload, nil)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", ctx, "ns1/chunk1").Return(fmt.Errorf("pop"))
+	mdx.On("DeleteBlob", ctx, "ns1/chunk2").Return(nil)
+
+	err := dm.CancelBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestCancelBlobUploadFinalizing(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), State: core.BlobUploadStateFinalizing}
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", upload.ID).Return(upload, nil)
+
+	err := dm.CancelBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10585.*finalizing", err)
+}
+
+func TestCancelBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	err := dm.CancelBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestPurgeBlobUpload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), Chunks: core.BlobUploadChunks{
+		{PayloadRef: "ns1/chunk1"},
+	}}
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(fmt.Errorf("pop"))
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", ctx, "ns1/chunk1").Return(nil)
+
+	err := dm.PurgeBlobUpload(ctx, upload)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestPurgeBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	err := dm.PurgeBlobUpload(ctx, &core.BlobUpload{})
+	assert.Regexp(t, "FF10414", err)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data/blobcrypt"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
@@ -36,7 +37,7 @@ import (
 
 type Manager interface {
 	CheckDatatype(ctx context.Context, datatype *core.Datatype) error
-	ValidateAll(ctx context.Context, data core.DataArray) (valid bool, err error)
+	ValidateAll(ctx context.Context, data core.DataArray, group *fftypes.Bytes32) (valid bool, err error)
 	GetMessageWithDataCached(ctx context.Context, msgID *fftypes.UUID, options ...CacheReadOption) (msg *core.Message, data core.DataArray, foundAllData bool, err error)
 	GetMessageDataCached(ctx context.Context, msg *core.Message, options ...CacheReadOption) (data core.DataArray, foundAll bool, err error)
 	PeekMessageCache(ctx context.Context, id *fftypes.UUID, options ...CacheReadOption) (msg *core.Message, data core.DataArray)
@@ -49,8 +50,21 @@ type Manager interface {
 
 	UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error)
 	UploadBlob(ctx context.Context, inData *core.DataRefOrValue, blob *ffapi.Multipart, autoMeta bool) (*core.Data, error)
+	CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error)
+	GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error)
+	UploadBlobChunk(ctx context.Context, uploadID string, offset int64, reader io.Reader) (*core.BlobUpload, error)
+	FinalizeBlobUpload(ctx context.Context, uploadID string, input *core.BlobUploadFinalize) (*core.BlobUpload, error)
+	CancelBlobUpload(ctx context.Context, uploadID string) error
+	PurgeBlobUpload(ctx context.Context, upload *core.BlobUpload) error
 	DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error)
+	GetDataBlob(ctx context.Context, dataID string) (*core.Blob, error)
+	OpenBlob(ctx context.Context, blob *core.Blob) (io.ReadCloser, error)
+	OpenBlobRange(ctx context.Context, blob *core.Blob, offset, length int64) (io.ReadCloser, error)
+	ImportBlob(ctx context.Context, dataID *fftypes.UUID, reader io.Reader) (*core.Blob, error)
+	EncryptReceivedBlob(ctx context.Context, blob *core.Blob) error
+	PrepareBlobTransfer(ctx context.Context, blob *core.Blob, transferID *fftypes.UUID) (payloadRef string, staged bool, err error)
 	DeleteData(ctx context.Context, dataID string) error
+	PurgeMessage(ctx context.Context, msg *core.Message) (dataDeleted int, err error)
 	HydrateBatch(ctx context.Context, persistedBatch *core.BatchPersisted) (*core.Batch, error)
 	Start()
 	WaitStop()
@@ -102,9 +116,19 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 		database:  di,
 	}
 	dm.blobStore = blobStore{
-		dm:       dm,
-		database: di,
-		exchange: dx,
+		ctx:           ctx,
+		dm:            dm,
+		database:      di,
+		exchange:      dx,
+		uploadTimeout: config.GetDuration(coreconfig.BlobUploadExpiry),
+	}
+
+	if config.GetBool(coreconfig.BlobEncryptionEnabled) {
+		keys, err := blobcrypt.NewProvider(ctx, config.GetString(coreconfig.BlobEncryptionProvider))
+		if err != nil {
+			return nil, err
+		}
+		dm.blobStore.keys = keys
 	}
 
 	validatorCache, err := cacheManager.GetCache(
@@ -142,6 +166,9 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 
 func (dm *dataManager) Start() {
 	dm.messageWriter.start()
+	if dm.exchange != nil {
+		dm.resumeBlobUploads()
+	}
 }
 
 func (dm *dataManager) BlobsEnabled() bool {
@@ -149,12 +176,13 @@ func (dm *dataManager) BlobsEnabled() bool {
 }
 
 func (dm *dataManager) CheckDatatype(ctx context.Context, datatype *core.Datatype) error {
-	_, err := newJSONValidator(ctx, dm.namespace.Name, datatype)
+	_, err := newValidator(ctx, dm.namespace.Name, datatype)
 	return err
 }
 
-// getValidatorForDatatype only returns database errors - not found (of all kinds) is a nil
-func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator core.ValidatorType, datatypeRef *core.DatatypeRef) (Validator, error) {
+// getValidatorForDatatype only returns database errors - not found (of all kinds) is a nil.
+// The group is that of the message carrying the data, where a datatype shared with the group is preferred.
+func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator core.ValidatorType, datatypeRef *core.DatatypeRef, group *fftypes.Bytes32) (Validator, error) {
 	if validator == "" {
 		validator = core.ValidatorTypeJSON
 	}
@@ -164,19 +192,23 @@ func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator co
 		return nil, nil
 	}
 
-	key := fmt.Sprintf("%s:%s:%s", validator, dm.namespace.Name, datatypeRef)
+	key := fmt.Sprintf("%s:%s:%s:%s", validator, dm.namespace.Name, datatypeRef, group)
 	if cachedValue := dm.validatorCache.Get(key); cachedValue != nil {
 		return cachedValue.(Validator), nil
 	}
 
-	datatype, err := dm.database.GetDatatypeByName(ctx, dm.namespace.Name, datatypeRef.Name, datatypeRef.Version)
+	datatype, err := dm.database.GetDatatypeByName(ctx, dm.namespace.Name, datatypeRef.Name, datatypeRef.Version, group)
 	if err != nil {
 		return nil, err
 	}
 	if datatype == nil {
 		return nil, nil
 	}
-	v, err := newJSONValidator(ctx, dm.namespace.Name, datatype)
+	if datatypeValidatorType(datatype) != validator {
+		log.L(ctx).Errorf("Datatype '%s:%s' has validator '%s' not '%s'", dm.namespace.Name, datatypeRef, datatypeValidatorType(datatype), validator)
+		return nil, nil
+	}
+	v, err := newValidator(ctx, dm.namespace.Name, datatype)
 	if err != nil {
 		log.L(ctx).Errorf("Invalid validator stored for '%s:%s:%s': %s", validator, dm.namespace.Name, datatypeRef, err)
 		return nil, nil
@@ -314,10 +346,10 @@ func (dm *dataManager) getMessageData(ctx context.Context, msg *core.Message) (d
 	return data, foundAll, nil
 }
 
-func (dm *dataManager) ValidateAll(ctx context.Context, data core.DataArray) (valid bool, err error) {
+func (dm *dataManager) ValidateAll(ctx context.Context, data core.DataArray, group *fftypes.Bytes32) (valid bool, err error) {
 	for _, d := range data {
 		if d.Datatype != nil && d.Validator != core.ValidatorTypeNone {
-			v, err := dm.getValidatorForDatatype(ctx, d.Validator, d.Datatype)
+			v, err := dm.getValidatorForDatatype(ctx, d.Validator, d.Datatype, group)
 			if err != nil {
 				return false, err
 			}
@@ -325,7 +357,11 @@ func (dm *dataManager) ValidateAll(ctx context.Context, data core.DataArray) (va
 				log.L(ctx).Errorf("Datatype %s:%s:%s not found", d.Validator, d.Namespace, d.Datatype)
 				return false, err
 			}
-			err = v.ValidateValue(ctx, d.Value, d.Hash)
+			if bv, ok := v.(BlobValidator); ok && d.Blob != nil {
+				err = dm.validateDataBlob(ctx, bv, d)
+			} else {
+				err = v.ValidateValue(ctx, d.Value, d.Hash)
+			}
 			if err != nil {
 				return false, err
 			}
@@ -370,7 +406,9 @@ func (dm *dataManager) resolveBlob(ctx context.Context, namespace string, blobRe
 	return nil, nil
 }
 
-func (dm *dataManager) checkValidation(ctx context.Context, validator core.ValidatorType, datatype *core.DatatypeRef, value *fftypes.JSONAny) error {
+// checkValidation verifies the payload conforms to the datatype, if one is specified. For validators that
+// support it, the content of the blob is validated in place of the value when a blob is attached.
+func (dm *dataManager) checkValidation(ctx context.Context, validator core.ValidatorType, datatype *core.DatatypeRef, group *fftypes.Bytes32, value *fftypes.JSONAny, blob *core.Blob) error {
 	if validator == "" {
 		validator = core.ValidatorTypeJSON
 	}
@@ -383,14 +421,18 @@ func (dm *dataManager) checkValidation(ctx context.Context, validator core.Valid
 			return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, datatype)
 		}
 		if validator != core.ValidatorTypeNone {
-			v, err := dm.getValidatorForDatatype(ctx, validator, datatype)
+			v, err := dm.getValidatorForDatatype(ctx, validator, datatype, group)
 			if err != nil {
 				return err
 			}
 			if v == nil {
 				return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, datatype)
 			}
-			err = v.ValidateValue(ctx, value, nil)
+			if bv, ok := v.(BlobValidator); ok && blob != nil {
+				err = dm.validateBlob(ctx, bv, blob)
+			} else {
+				err = v.ValidateValue(ctx, value, nil)
+			}
 			if err != nil {
 				return err
 			}
@@ -399,19 +441,19 @@ func (dm *dataManager) checkValidation(ctx context.Context, validator core.Valid
 	return nil
 }
 
-func (dm *dataManager) validateInputData(ctx context.Context, inData *core.DataRefOrValue) (data *core.Data, err error) {
+func (dm *dataManager) validateInputData(ctx context.Context, inData *core.DataRefOrValue, group *fftypes.Bytes32) (data *core.Data, err error) {
 
 	validator := inData.Validator
 	datatype := inData.Datatype
 	value := inData.Value
 	blobRef := inData.Blob
 
-	if err := dm.checkValidation(ctx, validator, datatype, value); err != nil {
+	blob, err := dm.resolveBlob(ctx, dm.namespace.Name, blobRef, inData.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	blob, err := dm.resolveBlob(ctx, dm.namespace.Name, blobRef, inData.ID)
-	if err != nil {
+	if err := dm.checkValidation(ctx, validator, datatype, group, value, blob); err != nil {
 		return nil, err
 	}
 
@@ -431,7 +473,8 @@ func (dm *dataManager) validateInputData(ctx context.Context, inData *core.DataR
 }
 
 func (dm *dataManager) UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error) {
-	data, err := dm.validateInputData(ctx, inData)
+	// Standalone data is not yet part of a message, so is validated against datatypes published to the whole network
+	data, err := dm.validateInputData(ctx, inData, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -470,7 +513,7 @@ func (dm *dataManager) ResolveInlineData(ctx context.Context, newMessage *NewMes
 			}
 		case dataOrValue.Value != nil || dataOrValue.Blob != nil:
 			// We've got a Value, so we can validate + store it
-			if d, err = dm.validateInputData(ctx, dataOrValue); err != nil {
+			if d, err = dm.validateInputData(ctx, dataOrValue, newMessage.Message.Header.Group); err != nil {
 				return err
 			}
 			newMessage.NewData = append(newMessage.NewData, d)
@@ -540,6 +583,7 @@ func (dm *dataManager) WriteNewMessage(ctx context.Context, newMsg *NewMessage)
 
 func (dm *dataManager) WaitStop() {
 	dm.messageWriter.close()
+	dm.finalizers.Wait()
 }
 
 func (dm *dataManager) DeleteData(ctx context.Context, dataID string) error {
@@ -555,6 +599,10 @@ func (dm *dataManager) DeleteData(ctx context.Context, dataID string) error {
 	if data == nil {
 		return i18n.NewError(ctx, coremsgs.Msg404NoResult)
 	}
+	return dm.deleteData(ctx, data)
+}
+
+func (dm *dataManager) deleteData(ctx context.Context, data *core.Data) error {
 	if data.Blob != nil && data.Blob.Hash != nil {
 		fb := database.BlobQueryFactory.NewFilter(ctx)
 		blobs, _, err := dm.database.GetBlobs(ctx, dm.namespace.Name, fb.And(fb.Eq("data_id", data.ID), fb.Eq("hash", data.Blob.Hash)))
@@ -582,3 +630,41 @@ func (dm *dataManager) DeleteData(ctx context.Context, dataID string) error {
 
 	return dm.database.DeleteData(ctx, data.Namespace, data.ID)
 }
+
+// PurgeMessage deletes a message that has passed its retention period or expiry time. Any data that is
+// no longer referenced by another message is deleted first, including the blobs of that data, and the
+// message itself is deleted last - so if the purge is interrupted, the message is still there for the
+// next pass to find and finish off. Returns the number of data items deleted.
+func (dm *dataManager) PurgeMessage(ctx context.Context, msg *core.Message) (dataDeleted int, err error) {
+	for _, dataRef := range msg.Data {
+		fb := database.MessageQueryFactory.NewFilter(ctx)
+		msgs, _, err := dm.database.GetMessagesForData(ctx, dm.namespace.Name, dataRef.ID, fb.And(
+			fb.Neq("id", msg.Header.ID),
+		).Limit(1))
+		if err != nil {
+			return dataDeleted, err
+		}
+		if len(msgs) > 0 {
+			log.L(ctx).Debugf("Data %s of purged message %s is still referenced by message %s", dataRef.ID, msg.Header.ID, msgs[0].Header.ID)
+			continue
+		}
+		data, err := dm.database.GetDataByID(ctx, dm.namespace.Name, dataRef.ID, false)
+		if err != nil {
+			return dataDeleted, err
+		}
+		if data == nil {
+			continue
+		}
+		if err := dm.deleteData(ctx, data); err != nil {
+			return dataDeleted, err
+		}
+		dataDeleted++
+	}
+
+	err = dm.database.DeleteMessage(ctx, dm.namespace.Name, msg.Header.ID)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return dataDeleted, err
+	}
+	dm.messageCache.Set(msg.Header.ID.String(), nil)
+	return dataDeleted, nil
+}
@@ -17,9 +17,14 @@
 package data
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -87,6 +92,7 @@ func newTestDataManager(t *testing.T) (*dataManager, context.Context, func()) {
 	mdi.On("Capabilities").Return(&database.Capabilities{
 		Concurrency: true,
 	})
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{}, nil, nil)
 	mdx := &dataexchangemocks.Plugin{}
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
 
@@ -163,12 +169,12 @@ func TestValidateE2E(t *testing.T) {
 		Name:      "customer",
 		Version:   "0.0.1",
 	}
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(dt, nil)
-	isValid, err := dm.ValidateAll(ctx, core.DataArray{data})
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(dt, nil)
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
 	assert.Regexp(t, "FF10198", err)
 	assert.False(t, isValid)
 
-	v, err := dm.getValidatorForDatatype(ctx, data.Validator, data.Datatype)
+	v, err := dm.getValidatorForDatatype(ctx, data.Validator, data.Datatype, nil)
 	err = v.Validate(ctx, data)
 	assert.Regexp(t, "FF10198", err)
 
@@ -177,7 +183,7 @@ func TestValidateE2E(t *testing.T) {
 	err = v.Validate(ctx, data)
 	assert.NoError(t, err)
 
-	isValid, err = dm.ValidateAll(ctx, core.DataArray{data})
+	isValid, err = dm.ValidateAll(ctx, core.DataArray{data}, nil)
 	assert.NoError(t, err)
 	assert.True(t, isValid)
 
@@ -197,7 +203,7 @@ func TestWriteNewMessageE2E(t *testing.T) {
 	}
 
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(dt, nil).Once()
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(dt, nil).Once()
 	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 		err := args[1].(func(context.Context) error)(ctx)
 		assert.NoError(t, err)
@@ -280,6 +286,32 @@ func TestInitBadDeps(t *testing.T) {
 	assert.Regexp(t, "FF10128", err)
 }
 
+func TestInitBlobEncryption(t *testing.T) {
+	coreconfig.Reset()
+	kekFile := filepath.Join(t.TempDir(), "kek")
+	err := os.WriteFile(kekFile, []byte(strings.Repeat("ab", 32)), 0600)
+	assert.NoError(t, err)
+	config.Set(coreconfig.BlobEncryptionEnabled, true)
+	config.Set(coreconfig.BlobEncryptionKeyfilePath, kekFile)
+
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	mdi := &databasemocks.Plugin{}
+	mdi.On("Capabilities").Return(&database.Capabilities{})
+	dm, err := NewDataManager(ctx, &core.Namespace{Name: "ns1"}, mdi, &dataexchangemocks.Plugin{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, "keyfile", dm.(*dataManager).keys.Name())
+}
+
+func TestInitBlobEncryptionBadProvider(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.BlobEncryptionEnabled, true)
+	config.Set(coreconfig.BlobEncryptionProvider, "unknown")
+	_, err := NewDataManager(context.Background(), &core.Namespace{Name: "ns1"}, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10525", err)
+}
+
 func TestValidatorLookupCached(t *testing.T) {
 	coreconfig.Reset()
 	dm, ctx, cancel := newTestDataManager(t)
@@ -296,17 +328,57 @@ func TestValidatorLookupCached(t *testing.T) {
 		Name:      "customer",
 		Namespace: "0.0.1",
 	}
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(dt, nil).Once()
-	lookup1, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(dt, nil).Once()
+	lookup1, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, "customer", lookup1.(*jsonValidator).datatype.Name)
 
-	lookup2, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref)
+	lookup2, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, lookup1, lookup2)
 
 }
 
+func TestValidatorLookupCachedByGroup(t *testing.T) {
+	coreconfig.Reset()
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	ref := &core.DatatypeRef{
+		Name:    "customer",
+		Version: "0.0.1",
+	}
+	group := fftypes.NewRandB32()
+	networkDT := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Name:      "customer",
+	}
+	groupDT := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Name:      "customer",
+		Group:     group,
+	}
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", (*fftypes.Bytes32)(nil)).Return(networkDT, nil).Once()
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", group).Return(groupDT, nil).Once()
+
+	lookup1, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref, nil)
+	assert.NoError(t, err)
+
+	lookup2, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref, group)
+	assert.NoError(t, err)
+	assert.NotSame(t, lookup1, lookup2)
+
+	lookup3, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref, group)
+	assert.NoError(t, err)
+	assert.Equal(t, lookup2, lookup3)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestValidateBadHash(t *testing.T) {
 
 	coreconfig.Reset()
@@ -330,8 +402,8 @@ func TestValidateBadHash(t *testing.T) {
 		Name:      "customer",
 		Namespace: "0.0.1",
 	}
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(dt, nil).Once()
-	_, err := dm.ValidateAll(ctx, core.DataArray{data})
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(dt, nil).Once()
+	_, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
 	assert.Regexp(t, "FF10201", err)
 
 }
@@ -607,7 +679,7 @@ func TestResolveInlineDataValueWithValidation(t *testing.T) {
 	mdi := dm.database.(*databasemocks.Plugin)
 
 	mdi.On("UpsertData", ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
-	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(&core.Datatype{
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1", mock.Anything).Return(&core.Datatype{
 		ID:        fftypes.NewUUID(),
 		Validator: core.ValidatorTypeJSON,
 		Namespace: "ns1",
@@ -654,6 +726,38 @@ func TestResolveInlineDataValueWithValidation(t *testing.T) {
 	assert.Regexp(t, "FF10198", err)
 }
 
+func TestResolveInlineDataValueGroupDatatype(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	_, _, newMsg := testNewMessage()
+	newMsg.Message.Header.Group = fftypes.NewRandB32()
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1", newMsg.Message.Header.Group).Return(&core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "customer",
+		Version:   "0.0.1",
+		Group:     newMsg.Message.Header.Group,
+		Value:     fftypes.JSONAnyPtr(`{"additionalProperties": false}`),
+	}, nil)
+	newMsg.Message.InlineData = core.InlineData{
+		{
+			Datatype: &core.DatatypeRef{
+				Name:    "customer",
+				Version: "0.0.1",
+			},
+			Value: fftypes.JSONAnyPtr(`{"field1":"value1"}`),
+		},
+	}
+
+	err := dm.ResolveInlineData(ctx, newMsg)
+	assert.Regexp(t, "FF10198", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveInlineDataNoRefOrValue(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -672,7 +776,7 @@ func TestUploadJSONLoadDatatypeFail(t *testing.T) {
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
 
-	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1", mock.Anything).Return(nil, fmt.Errorf("pop"))
 	_, err := dm.UploadJSON(ctx, &core.DataRefOrValue{
 		Datatype: &core.DatatypeRef{
 			Name:    "customer",
@@ -699,7 +803,7 @@ func TestValidateAndStoreLoadNilRef(t *testing.T) {
 	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
 		Validator: core.ValidatorTypeJSON,
 		Datatype:  nil,
-	})
+	}, nil)
 	assert.Regexp(t, "FF00109", err)
 }
 
@@ -708,14 +812,14 @@ func TestValidateAndStoreLoadValidatorUnknown(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, nil)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(nil, nil)
 	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
 		Validator: "wrong!",
 		Datatype: &core.DatatypeRef{
 			Name:    "customer",
 			Version: "0.0.1",
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF00108.*wrong", err)
 
 }
@@ -725,12 +829,12 @@ func TestValidateAndStoreLoadBadRef(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, nil)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(nil, nil)
 	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
 		Datatype: &core.DatatypeRef{
 			// Missing name
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10195", err)
 }
 
@@ -739,13 +843,13 @@ func TestValidateAndStoreNotFound(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, nil)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(nil, nil)
 	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
 		Datatype: &core.DatatypeRef{
 			Name:    "customer",
 			Version: "0.0.1",
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10195", err)
 }
 
@@ -760,7 +864,7 @@ func TestValidateAndStoreBlobError(t *testing.T) {
 		Blob: &core.BlobRef{
 			Hash: blobHash,
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -775,7 +879,7 @@ func TestValidateAndStoreBlobNotFound(t *testing.T) {
 		Blob: &core.BlobRef{
 			Hash: blobHash,
 		},
-	})
+	}, nil)
 	assert.Regexp(t, "FF10239", err)
 }
 
@@ -784,7 +888,7 @@ func TestValidateAllLookupError(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(nil, fmt.Errorf("pop"))
 	data := &core.Data{
 		Namespace: "ns1",
 		Validator: core.ValidatorTypeJSON,
@@ -795,7 +899,7 @@ func TestValidateAllLookupError(t *testing.T) {
 		Value: fftypes.JSONAnyPtr(`anything`),
 	}
 	data.Seal(ctx, nil)
-	_, err := dm.ValidateAll(ctx, core.DataArray{data})
+	_, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
 	assert.Regexp(t, "pop", err)
 
 }
@@ -804,7 +908,7 @@ func TestGetValidatorForDatatypeNilRef(t *testing.T) {
 
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
-	v, err := dm.getValidatorForDatatype(ctx, "", nil)
+	v, err := dm.getValidatorForDatatype(ctx, "", nil, nil)
 	assert.Nil(t, v)
 	assert.NoError(t, err)
 
@@ -815,7 +919,7 @@ func TestValidateAllStoredValidatorInvalid(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
 	mdi := dm.database.(*databasemocks.Plugin)
-	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(&core.Datatype{
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1", mock.Anything).Return(&core.Datatype{
 		Value: fftypes.JSONAnyPtr(`{"not": "a", "schema": true}`),
 	}, nil)
 	data := &core.Data{
@@ -825,7 +929,7 @@ func TestValidateAllStoredValidatorInvalid(t *testing.T) {
 			Version: "0.0.1",
 		},
 	}
-	isValid, err := dm.ValidateAll(ctx, core.DataArray{data})
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
 	assert.False(t, isValid)
 	assert.NoError(t, err)
 	mdi.AssertExpectations(t)
@@ -1379,3 +1483,355 @@ func TestDeleteDataFailGetMessages(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 	mdb.AssertExpectations(t)
 }
+
+func TestCheckDatatypeXSD(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	err := dm.CheckDatatype(ctx, testXSDDatatype())
+	assert.NoError(t, err)
+
+	dt := testXSDDatatype()
+	dt.Value = jsonString(`<not-a-schema/>`)
+	err = dm.CheckDatatype(ctx, dt)
+	assert.Regexp(t, "FF10196.*FF10509", err)
+}
+
+func TestGetValidatorForDatatypeMismatch(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	v, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, &core.DatatypeRef{Name: "order", Version: "0.0.1"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = dm.getValidatorForDatatype(ctx, core.ValidatorTypeXSD, &core.DatatypeRef{Name: "order", Version: "0.0.1"}, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &xsdValidator{}, v)
+	mdi.AssertExpectations(t)
+}
+
+func TestValidateAllXSDValue(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	value := jsonString(`<order><sku>A</sku><quantity>1</quantity></order>`)
+	data := &core.Data{
+		Namespace: "ns1",
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+		Value:     value,
+		Hash:      value.Hash(),
+	}
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.True(t, isValid)
+	assert.NoError(t, err)
+
+	data.Value = jsonString(`<order><sku>A</sku></order>`)
+	data.Hash = data.Value.Hash()
+	isValid, err = dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.False(t, isValid)
+	assert.Regexp(t, "FF10520.*FF10514", err)
+	mdi.AssertExpectations(t)
+}
+
+func TestValidateAllXSDBlob(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	blobHash := fftypes.NewRandB32()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{{
+		Hash:       blobHash,
+		PayloadRef: "ns1/blob1",
+	}}, nil, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(strings.NewReader(`<order><sku>A</sku><quantity>1</quantity></order>`)), nil).Once()
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(strings.NewReader(`<order><sku>A</sku><quantity>-1</quantity></order>`)), nil).Once()
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{"filename": "order.xml"}`),
+		Blob:      &core.BlobRef{Hash: blobHash},
+		Hash:      fftypes.NewRandB32(),
+	}
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.True(t, isValid)
+	assert.NoError(t, err)
+
+	isValid, err = dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.False(t, isValid)
+	assert.Regexp(t, "FF10520.*quantity", err)
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestValidateAllXSDBlobMissing(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Blob:      &core.BlobRef{},
+	}
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.False(t, isValid)
+	assert.Regexp(t, "FF10239", err)
+	mdi.AssertExpectations(t)
+}
+
+func TestValidateAllXSDBlobLookupFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "order", "0.0.1", mock.Anything).Return(testXSDDatatype(), nil)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Validator: core.ValidatorTypeXSD,
+		Datatype:  &core.DatatypeRef{Name: "order", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Blob:      &core.BlobRef{Hash: fftypes.NewRandB32()},
+	}
+	isValid, err := dm.ValidateAll(ctx, core.DataArray{data}, nil)
+	assert.False(t, isValid)
+	assert.Regexp(t, "pop", err)
+	mdi.AssertExpectations(t)
+}
+
+func TestValidateInputDataProtobufBlob(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "payment", "0.0.1", mock.Anything).Return(testProtobufDatatype(), nil)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{{
+		Hash:       blobHash,
+		PayloadRef: "ns1/blob1",
+		Size:       2,
+	}}, nil, nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader([]byte{0x0a, 0x00})), nil).Once()
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(nil, fmt.Errorf("pop")).Once()
+
+	inData := &core.DataRefOrValue{
+		DataRef:   core.DataRef{ID: dataID},
+		Validator: core.ValidatorTypeProtobuf,
+		Datatype:  &core.DatatypeRef{Name: "payment", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{"filename": "payment.bin"}`),
+		Blob:      &core.BlobRef{Hash: blobHash},
+	}
+	data, err := dm.validateInputData(ctx, inData, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "payment.bin", data.Blob.Name)
+
+	_, err = dm.validateInputData(ctx, inData, nil)
+	assert.Regexp(t, "pop", err)
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestPurgeMessage(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+
+	orphanID := fftypes.NewUUID()
+	sharedID := fftypes.NewUUID()
+	missingID := fftypes.NewUUID()
+	hash := fftypes.NewRandB32()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Data: core.DataRefs{
+			{ID: orphanID},
+			{ID: sharedID},
+			{ID: missingID},
+		},
+	}
+	dm.messageCache.Set(msg.Header.ID.String(), &messageCacheEntry{msg: msg})
+
+	mdb.On("DeleteMessage", ctx, "ns1", msg.Header.ID).Return(nil)
+	mdb.On("GetMessagesForData", ctx, "ns1", orphanID, mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetMessagesForData", ctx, "ns1", sharedID, mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdb.On("GetMessagesForData", ctx, "ns1", missingID, mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetDataByID", ctx, "ns1", orphanID, false).Return(&core.Data{
+		ID:        orphanID,
+		Namespace: "ns1",
+		Blob:      &core.BlobRef{Hash: hash},
+	}, nil)
+	mdb.On("GetDataByID", ctx, "ns1", missingID, false).Return(nil, nil)
+	mdb.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{
+		{Sequence: 12345, PayloadRef: "payloadRef", Hash: hash, DataID: orphanID},
+	}, nil, nil)
+	mdx.On("DeleteBlob", ctx, "payloadRef").Return(nil)
+	mdb.On("DeleteBlob", ctx, int64(12345)).Return(nil)
+	mdb.On("DeleteData", ctx, "ns1", orphanID).Return(nil)
+
+	deleted, err := dm.PurgeMessage(ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Nil(t, dm.messageCache.Get(msg.Header.ID.String()))
+
+	mdb.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestPurgeMessageAlreadyDeleted(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	mdb.On("DeleteMessage", ctx, "ns1", msg.Header.ID).Return(fftypes.DeleteRecordNotFound)
+
+	deleted, err := dm.PurgeMessage(ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestPurgeMessageDeleteFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	mdb.On("DeleteMessage", ctx, "ns1", msg.Header.ID).Return(fmt.Errorf("pop"))
+
+	_, err := dm.PurgeMessage(ctx, msg)
+	assert.Regexp(t, "pop", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestPurgeMessageDeletesDataFirst(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Data: core.DataRefs{{ID: dataID}},
+	}
+	dm.messageCache.Set(msg.Header.ID.String(), &messageCacheEntry{msg: msg})
+	var deletedData bool
+	mdb.On("GetMessagesForData", ctx, "ns1", dataID, mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Limit == 1 && strings.Contains(fi.String(), msg.Header.ID.String())
+	})).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetMessagesForData", ctx, "ns1", dataID, mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetDataByID", ctx, "ns1", dataID, false).Return(&core.Data{ID: dataID, Namespace: "ns1"}, nil)
+	mdb.On("DeleteData", ctx, "ns1", dataID).Run(func(args mock.Arguments) {
+		deletedData = true
+	}).Return(nil)
+	mdb.On("DeleteMessage", ctx, "ns1", msg.Header.ID).Run(func(args mock.Arguments) {
+		assert.True(t, deletedData)
+	}).Return(fmt.Errorf("pop"))
+
+	deleted, err := dm.PurgeMessage(ctx, msg)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 1, deleted)
+	assert.NotNil(t, dm.messageCache.Get(msg.Header.ID.String()))
+
+	mdb.AssertExpectations(t)
+}
+
+func TestPurgeMessageGetMessagesForDataFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Data: core.DataRefs{{ID: dataID}},
+	}
+	mdb.On("GetMessagesForData", ctx, "ns1", dataID, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := dm.PurgeMessage(ctx, msg)
+	assert.Regexp(t, "pop", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestPurgeMessageGetDataFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Data: core.DataRefs{{ID: dataID}},
+	}
+	mdb.On("GetMessagesForData", ctx, "ns1", dataID, mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetDataByID", ctx, "ns1", dataID, false).Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.PurgeMessage(ctx, msg)
+	assert.Regexp(t, "pop", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestPurgeMessageDeleteDataFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	dataID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Data: core.DataRefs{{ID: dataID}},
+	}
+	mdb.On("GetMessagesForData", ctx, "ns1", dataID, mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdb.On("GetDataByID", ctx, "ns1", dataID, false).Return(&core.Data{ID: dataID, Namespace: "ns1"}, nil)
+	mdb.On("DeleteData", ctx, "ns1", dataID).Return(fmt.Errorf("pop"))
+
+	deleted, err := dm.PurgeMessage(ctx, msg)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, deleted)
+
+	mdb.AssertExpectations(t)
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protobufDatatype is the value of a protobuf datatype. The descriptor set must include all
+// the imports of the message (protoc --include_imports --descriptor_set_out)
+type protobufDatatype struct {
+	DescriptorSet string `json:"descriptorSet"` // base64 encoded FileDescriptorSet
+	Message       string `json:"message"`       // fully qualified name of the message type
+}
+
+// protobufValidator validates data against a protobuf message type. The message is carried
+// in the protobuf JSON mapping in the value of the data, or in the binary wire format as
+// the content of a blob.
+type protobufValidator struct {
+	id       *fftypes.UUID
+	size     int64
+	ns       string
+	datatype *core.DatatypeRef
+	message  protoreflect.MessageDescriptor
+}
+
+func newProtobufValidator(ctx context.Context, ns string, datatype *core.Datatype) (*protobufValidator, error) {
+	pv := &protobufValidator{
+		id: datatype.ID,
+		ns: ns,
+		datatype: &core.DatatypeRef{
+			Name:    datatype.Name,
+			Version: datatype.Version,
+		},
+	}
+
+	message, err := loadProtobufMessage(datatype.Value)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgProtobufDatatypeInvalid, pv.datatype, err)
+	}
+	pv.message = message
+	pv.size = int64(len(*datatype.Value))
+
+	log.L(ctx).Debugf("Found protobuf validator for protobuf:%s:%s: %v (%s)", pv.ns, datatype, pv.id, message.FullName())
+	return pv, nil
+}
+
+func loadProtobufMessage(value *fftypes.JSONAny) (protoreflect.MessageDescriptor, error) {
+	var def protobufDatatype
+	if value == nil {
+		return nil, fmt.Errorf("missing value")
+	}
+	err := json.Unmarshal(value.Bytes(), &def)
+	var descriptorBytes []byte
+	if err == nil {
+		descriptorBytes, err = base64.StdEncoding.DecodeString(def.DescriptorSet)
+	}
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err == nil {
+		err = proto.Unmarshal(descriptorBytes, &descriptorSet)
+	}
+	var files *protoregistry.Files
+	if err == nil {
+		files, err = protodesc.NewFiles(&descriptorSet)
+	}
+	var descriptor protoreflect.Descriptor
+	if err == nil {
+		descriptor, err = files.FindDescriptorByName(protoreflect.FullName(def.Message))
+	}
+	if err != nil {
+		return nil, err
+	}
+	message, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a message", def.Message)
+	}
+	return message, nil
+}
+
+func (pv *protobufValidator) Validate(ctx context.Context, data *core.Data) error {
+	return pv.ValidateValue(ctx, data.Value, data.Hash)
+}
+
+func (pv *protobufValidator) ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error {
+	if value == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDataValueIsNull)
+	}
+
+	if expectedHash != nil {
+		hash := value.Hash()
+		if *hash != *expectedHash {
+			return i18n.NewError(ctx, coremsgs.MsgDataInvalidHash, hash, expectedHash)
+		}
+	}
+
+	msg := dynamicpb.NewMessage(pv.message)
+	if err := protojson.Unmarshal(value.Bytes(), msg); err != nil {
+		return pv.invalid(ctx, err)
+	}
+	return nil
+}
+
+func (pv *protobufValidator) ValidateBlob(ctx context.Context, reader io.Reader) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+	}
+	msg := dynamicpb.NewMessage(pv.message)
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return pv.invalid(ctx, err)
+	}
+	// The binary format silently retains fields that are not in the descriptor, which we reject
+	if hasUnknownFields(msg) {
+		return pv.invalid(ctx, fmt.Errorf("unknown fields"))
+	}
+	return nil
+}
+
+func (pv *protobufValidator) invalid(ctx context.Context, err error) error {
+	log.L(ctx).Warnf("Protobuf message %s %s [%v] validation failed: %s", pv.message.FullName(), pv.datatype, pv.id, err)
+	return i18n.NewError(ctx, coremsgs.MsgProtobufDataInvalid, pv.message.FullName(), pv.datatype, err)
+}
+
+func hasUnknownFields(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+	unknown := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					unknown = hasUnknownFields(mv.Message())
+					return !unknown
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len() && !unknown; i++ {
+					unknown = hasUnknownFields(v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			unknown = hasUnknownFields(v.Message())
+		}
+		return !unknown
+	})
+	return unknown
+}
+
+func (pv *protobufValidator) Size() int64 {
+	return pv.size
+}
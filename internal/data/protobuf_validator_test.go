@@ -0,0 +1,263 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testProtobufField(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, fieldType descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  label.Enum(),
+		Type:   fieldType.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// testDescriptorSet is the equivalent of compiling the following with --include_imports:
+//
+//	syntax = "proto3";
+//	package example;
+//	import "google/protobuf/timestamp.proto";
+//	enum Status { UNKNOWN = 0; }
+//	message Item { string sku = 1; }
+//	message Payment {
+//	  string id = 1;
+//	  int64 amount = 2;
+//	  repeated Item items = 3;
+//	  map<string, Item> tagged = 4;
+//	  google.protobuf.Timestamp created = 5;
+//	  Item primary = 6;
+//	  map<string, string> labels = 7;
+//	}
+func testDescriptorSet() []byte {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	mapEntry := func(name, valueType string) *descriptorpb.DescriptorProto {
+		valueField := testProtobufField("value", 2, optional, str, "")
+		if valueType != "" {
+			valueField = testProtobufField("value", 2, optional, msg, valueType)
+		}
+		return &descriptorpb.DescriptorProto{
+			Name:    proto.String(name),
+			Field:   []*descriptorpb.FieldDescriptorProto{testProtobufField("key", 1, optional, str, ""), valueField},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/payment.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:  proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)}},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{testProtobufField("sku", 1, optional, str, "")},
+			},
+			{
+				Name: proto.String("Payment"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testProtobufField("id", 1, optional, str, ""),
+					testProtobufField("amount", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+					testProtobufField("items", 3, repeated, msg, ".example.Item"),
+					testProtobufField("tagged", 4, repeated, msg, ".example.Payment.TaggedEntry"),
+					testProtobufField("created", 5, optional, msg, ".google.protobuf.Timestamp"),
+					testProtobufField("primary", 6, optional, msg, ".example.Item"),
+					testProtobufField("labels", 7, repeated, msg, ".example.Payment.LabelsEntry"),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					mapEntry("TaggedEntry", ".example.Item"),
+					mapEntry("LabelsEntry", ""),
+				},
+			},
+		},
+	}
+	b, _ := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			file,
+		},
+	})
+	return b
+}
+
+func testProtobufDatatypeValue(descriptorSet []byte, message string) *fftypes.JSONAny {
+	b, _ := json.Marshal(&protobufDatatype{
+		DescriptorSet: base64.StdEncoding.EncodeToString(descriptorSet),
+		Message:       message,
+	})
+	return fftypes.JSONAnyPtrBytes(b)
+}
+
+func testProtobufDatatype() *core.Datatype {
+	return &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeProtobuf,
+		Name:      "payment",
+		Version:   "0.0.1",
+		Value:     testProtobufDatatypeValue(testDescriptorSet(), "example.Payment"),
+	}
+}
+
+func withUnknownField(m protoreflect.Message) protoreflect.Message {
+	m.SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 99, protowire.VarintType), 1))
+	return m
+}
+
+func TestProtobufValidatorValue(t *testing.T) {
+
+	dt := testProtobufDatatype()
+	pv, err := newProtobufValidator(context.Background(), "ns1", dt)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(*dt.Value)), pv.Size())
+
+	value := fftypes.JSONAnyPtr(`{
+		"id": "pay-1",
+		"amount": "1000",
+		"items": [{"sku": "A"}],
+		"tagged": {"first": {"sku": "B"}},
+		"created": "2023-01-01T00:00:00Z",
+		"labels": {"a": "b"}
+	}`)
+	err = pv.ValidateValue(context.Background(), value, value.Hash())
+	assert.NoError(t, err)
+
+	err = pv.Validate(context.Background(), &core.Data{Value: value, Hash: value.Hash()})
+	assert.NoError(t, err)
+
+	err = pv.ValidateValue(context.Background(), fftypes.JSONAnyPtr(`{"id": "pay-1", "other": true}`), nil)
+	assert.Regexp(t, "FF10524.*example.Payment.*payment.*other", err)
+
+	err = pv.ValidateValue(context.Background(), fftypes.JSONAnyPtr(`{"amount": "lots"}`), nil)
+	assert.Regexp(t, "FF10524.*int64", err)
+
+	err = pv.ValidateValue(context.Background(), value, fftypes.NewRandB32())
+	assert.Regexp(t, "FF10201", err)
+
+	err = pv.ValidateValue(context.Background(), nil, nil)
+	assert.Regexp(t, "FF10199", err)
+
+}
+
+func TestProtobufValidatorBlob(t *testing.T) {
+
+	pv, err := newProtobufValidator(context.Background(), "ns1", testProtobufDatatype())
+	assert.NoError(t, err)
+
+	payment := pv.message
+	item := payment.Fields().ByName("primary").Message()
+	newItem := func(sku string) protoreflect.Message {
+		m := dynamicpb.NewMessage(item)
+		m.Set(item.Fields().ByName("sku"), protoreflect.ValueOfString(sku))
+		return m
+	}
+	newPayment := func() protoreflect.Message {
+		m := dynamicpb.NewMessage(payment)
+		m.Set(payment.Fields().ByName("id"), protoreflect.ValueOfString("pay-1"))
+		labels := m.Mutable(payment.Fields().ByName("labels")).Map()
+		labels.Set(protoreflect.ValueOfString("a").MapKey(), protoreflect.ValueOfString("b"))
+		return m
+	}
+	marshal := func(m protoreflect.Message) *bytes.Reader {
+		b, err := proto.Marshal(m.Interface())
+		assert.NoError(t, err)
+		return bytes.NewReader(b)
+	}
+
+	full := newPayment()
+	full.Mutable(payment.Fields().ByName("items")).List().Append(protoreflect.ValueOfMessage(newItem("A")))
+	full.Mutable(payment.Fields().ByName("tagged")).Map().Set(protoreflect.ValueOfString("t").MapKey(), protoreflect.ValueOfMessage(newItem("B")))
+	full.Set(payment.Fields().ByName("primary"), protoreflect.ValueOfMessage(newItem("C")))
+	assert.NoError(t, pv.ValidateBlob(context.Background(), marshal(full)))
+
+	err = pv.ValidateBlob(context.Background(), bytes.NewReader([]byte{0xff, 0xff}))
+	assert.Regexp(t, "FF10524", err)
+
+	err = pv.ValidateBlob(context.Background(), marshal(withUnknownField(newPayment())))
+	assert.Regexp(t, "FF10524.*unknown fields", err)
+
+	inList := newPayment()
+	inList.Mutable(payment.Fields().ByName("items")).List().Append(protoreflect.ValueOfMessage(withUnknownField(newItem("A"))))
+	assert.Regexp(t, "FF10524.*unknown fields", pv.ValidateBlob(context.Background(), marshal(inList)))
+
+	inMap := newPayment()
+	inMap.Mutable(payment.Fields().ByName("tagged")).Map().Set(protoreflect.ValueOfString("t").MapKey(), protoreflect.ValueOfMessage(withUnknownField(newItem("B"))))
+	assert.Regexp(t, "FF10524.*unknown fields", pv.ValidateBlob(context.Background(), marshal(inMap)))
+
+	inField := newPayment()
+	inField.Set(payment.Fields().ByName("primary"), protoreflect.ValueOfMessage(withUnknownField(newItem("C"))))
+	assert.Regexp(t, "FF10524.*unknown fields", pv.ValidateBlob(context.Background(), marshal(inField)))
+
+	err = pv.ValidateBlob(context.Background(), iotest.ErrReader(fmt.Errorf("pop")))
+	assert.Regexp(t, "FF10217.*pop", err)
+
+}
+
+func TestProtobufValidatorBadDatatype(t *testing.T) {
+
+	for name, value := range map[string]*fftypes.JSONAny{
+		"nil":           nil,
+		"notObject":     fftypes.JSONAnyPtr(`"string"`),
+		"notBase64":     fftypes.JSONAnyPtr(`{"descriptorSet": "!", "message": "example.Payment"}`),
+		"notDescriptor": testProtobufDatatypeValue([]byte{0xff, 0xff}, "example.Payment"),
+		"missingImport": testProtobufDatatypeValue(testDescriptorSetWithoutImports(), "example.Payment"),
+		"unknown":       testProtobufDatatypeValue(testDescriptorSet(), "example.Missing"),
+		"notMessage":    testProtobufDatatypeValue(testDescriptorSet(), "example.Status"),
+	} {
+		dt := testProtobufDatatype()
+		dt.Value = value
+		_, err := newProtobufValidator(context.Background(), "ns1", dt)
+		assert.Regexp(t, "FF10523.*payment", err, name)
+	}
+
+}
+
+func testDescriptorSetWithoutImports() []byte {
+	var fds descriptorpb.FileDescriptorSet
+	_ = proto.Unmarshal(testDescriptorSet(), &fds)
+	fds.File = fds.File[1:]
+	b, _ := proto.Marshal(&fds)
+	return b
+}
@@ -18,8 +18,10 @@ package data
 
 import (
 	"context"
+	"io"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
@@ -28,3 +30,45 @@ type Validator interface {
 	ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error
 	Size() int64 // for cache management
 }
+
+// BlobValidator is implemented by validators that can check the content of a blob attached to the data.
+// For data with a blob, the value is then metadata (such as the filename) and is not validated.
+type BlobValidator interface {
+	ValidateBlob(ctx context.Context, reader io.Reader) error
+}
+
+// validatorFactory builds the validator for a datatype, returning an error if the value of
+// the datatype is not a valid definition for the validator type
+type validatorFactory func(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error)
+
+var validatorFactories = map[core.ValidatorType]validatorFactory{
+	core.ValidatorTypeJSON: func(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error) {
+		return newJSONValidator(ctx, ns, datatype)
+	},
+	core.ValidatorTypeXSD: func(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error) {
+		return newXSDValidator(ctx, ns, datatype)
+	},
+	core.ValidatorTypeProtobuf: func(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error) {
+		return newProtobufValidator(ctx, ns, datatype)
+	},
+}
+
+func datatypeValidatorType(datatype *core.Datatype) core.ValidatorType {
+	if datatype.Validator == "" {
+		return core.ValidatorTypeJSON
+	}
+	return datatype.Validator
+}
+
+// newValidator builds the validator for a datatype, using the factory registered for its validator type
+func newValidator(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error) {
+	factory, ok := validatorFactories[datatypeValidatorType(datatype)]
+	if !ok {
+		return nil, i18n.NewError(ctx, i18n.MsgUnknownValidatorType, datatype.Validator)
+	}
+	v, err := factory(ctx, ns, datatype)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
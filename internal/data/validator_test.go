@@ -0,0 +1,66 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValidatorByType(t *testing.T) {
+
+	v, err := newValidator(context.Background(), "ns1", &core.Datatype{
+		Value: fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.NoError(t, err)
+	assert.IsType(t, &jsonValidator{}, v)
+
+	v, err = newValidator(context.Background(), "ns1", testXSDDatatype())
+	assert.NoError(t, err)
+	assert.IsType(t, &xsdValidator{}, v)
+	assert.Implements(t, (*BlobValidator)(nil), v)
+
+	v, err = newValidator(context.Background(), "ns1", testProtobufDatatype())
+	assert.NoError(t, err)
+	assert.IsType(t, &protobufValidator{}, v)
+	assert.Implements(t, (*BlobValidator)(nil), v)
+
+}
+
+func TestNewValidatorUnknownType(t *testing.T) {
+
+	_, err := newValidator(context.Background(), "ns1", &core.Datatype{
+		Validator: core.ValidatorTypeNone,
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF00108.*none", err)
+
+}
+
+func TestNewValidatorFail(t *testing.T) {
+
+	dt := testXSDDatatype()
+	dt.Value = fftypes.JSONAnyPtr(`{}`)
+	v, err := newValidator(context.Background(), "ns1", dt)
+	assert.Regexp(t, "FF10522", err)
+	assert.Nil(t, v)
+
+}
@@ -0,0 +1,154 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	xmlnsPrefix = "xmlns"
+	xmlNS       = "http://www.w3.org/XML/1998/namespace"
+)
+
+// node is an element of a parsed XML document, with names resolved to their namespaces
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+	ns       map[string]string // in-scope namespace prefixes, with "" for the default namespace
+}
+
+func parseDocument(ctx context.Context, r io.Reader) (*node, error) {
+	d := xml.NewDecoder(r)
+	var root *node
+	var stack []*node
+	var text []*strings.Builder
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgXMLParseFailed, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, i18n.NewError(ctx, coremsgs.MsgXMLParseFailed, "multiple root elements")
+			}
+			n := &node{name: t.Name, attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				n.ns = parent.ns
+			} else {
+				root = n
+				n.ns = map[string]string{"xml": xmlNS}
+			}
+			n.ns = declareNamespaces(n.ns, t.Attr)
+			stack = append(stack, n)
+			text = append(text, &strings.Builder{})
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			n.text = text[len(text)-1].String()
+			stack = stack[:len(stack)-1]
+			text = text[:len(text)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				text[len(text)-1].Write(t)
+			} else if strings.TrimSpace(string(t)) != "" {
+				return nil, i18n.NewError(ctx, coremsgs.MsgXMLParseFailed, "text outside of the root element")
+			}
+		}
+	}
+	if root == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgXMLParseFailed, "no root element")
+	}
+	return root, nil
+}
+
+// declareNamespaces returns the namespace prefixes in scope for an element, only
+// copying the parent's map when the element declares new prefixes
+func declareNamespaces(parent map[string]string, attrs []xml.Attr) map[string]string {
+	ns := parent
+	copied := false
+	for _, a := range attrs {
+		prefix := ""
+		switch {
+		case a.Name.Space == xmlnsPrefix:
+			prefix = a.Name.Local
+		case a.Name.Space == "" && a.Name.Local == xmlnsPrefix:
+		default:
+			continue
+		}
+		if !copied {
+			ns = make(map[string]string, len(parent)+1)
+			for k, v := range parent {
+				ns[k] = v
+			}
+			copied = true
+		}
+		ns[prefix] = a.Value
+	}
+	return ns
+}
+
+func isNamespaceDecl(a xml.Attr) bool {
+	return a.Name.Space == xmlnsPrefix || (a.Name.Space == "" && a.Name.Local == xmlnsPrefix)
+}
+
+// attr returns the value of an unqualified attribute
+func (n *node) attr(local string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// qname resolves a prefixed name used in an attribute value, such as type="xs:string"
+func (n *node) qname(ctx context.Context, v string) (xml.Name, error) {
+	prefix, local := "", strings.TrimSpace(v)
+	if i := strings.IndexByte(local, ':'); i >= 0 {
+		prefix, local = local[:i], local[i+1:]
+	}
+	space, ok := n.ns[prefix]
+	if !ok && prefix != "" {
+		return xml.Name{}, i18n.NewError(ctx, coremsgs.MsgXSDInvalid, "undeclared namespace prefix '"+prefix+"'")
+	}
+	return xml.Name{Space: space, Local: local}, nil
+}
+
+// elements returns the child elements in the XML Schema namespace, skipping annotations
+func (n *node) elements() []*node {
+	children := make([]*node, 0, len(n.children))
+	for _, c := range n.children {
+		if c.name.Space == xsdNS && c.name.Local != "annotation" {
+			children = append(children, c)
+		}
+	}
+	return children
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocumentNamespaces(t *testing.T) {
+	doc, err := parseDocument(context.Background(), strings.NewReader(`<?xml version="1.0"?>
+		<!-- comment -->
+		<a:root xmlns:a="urn:a" xmlns="urn:default">
+			<child attr="1" xmlns:a="urn:other">text<a:inner/>more</child>
+		</a:root>`))
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Name{Space: "urn:a", Local: "root"}, doc.name)
+	assert.Equal(t, "urn:default", doc.ns[""])
+	assert.Equal(t, xmlNS, doc.ns["xml"])
+	child := doc.children[0]
+	assert.Equal(t, "textmore", child.text)
+	assert.Equal(t, "urn:other", child.ns["a"])
+	assert.Equal(t, "urn:a", doc.ns["a"])
+	assert.Equal(t, xml.Name{Space: "urn:other", Local: "inner"}, child.children[0].name)
+	v, ok := child.attr("attr")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	_, ok = child.attr("missing")
+	assert.False(t, ok)
+
+	name, err := child.qname(context.Background(), "a:thing")
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Name{Space: "urn:other", Local: "thing"}, name)
+	name, err = child.qname(context.Background(), "thing")
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Name{Space: "urn:default", Local: "thing"}, name)
+	_, err = child.qname(context.Background(), "b:thing")
+	assert.Regexp(t, "FF10509.*'b'", err)
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	for doc, expected := range map[string]string{
+		``:                   "no root element",
+		`<a/><b/>`:           "multiple root elements",
+		`<a/>text`:           "text outside of the root element",
+		`<a><b></a>`:         "FF10512",
+		`<a>&undefined;</a>`: "FF10512",
+	} {
+		_, err := parseDocument(context.Background(), strings.NewReader(doc))
+		assert.Regexp(t, expected, err, doc)
+	}
+}
@@ -0,0 +1,479 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"encoding/base64"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+type whitespace int
+
+const (
+	wsPreserve whitespace = iota
+	wsReplace
+	wsCollapse
+)
+
+// valueClass determines how the facets of a type are applied to its values
+type valueClass int
+
+const (
+	classOther valueClass = iota
+	classString
+	classDecimal
+	classFloat
+	classHexBinary
+	classBase64Binary
+	classList
+)
+
+type builtinType struct {
+	class valueClass
+	ws    whitespace
+	valid func(v string) bool
+}
+
+// simpleType is a built-in type, or a restriction, list or union of other simple types
+type simpleType struct {
+	name    string
+	builtin *builtinType
+	base    *simpleType
+	item    *simpleType
+	members []*simpleType
+	ws      whitespace
+	facets  facets
+}
+
+type facets struct {
+	enumeration    []string
+	patternSources []string
+	pattern        *regexp.Regexp
+	length         int
+	minLength      int
+	maxLength      int
+	totalDigits    int
+	fractionDigits int
+	minInclusive   *big.Rat
+	maxInclusive   *big.Rat
+	minExclusive   *big.Rat
+	maxExclusive   *big.Rat
+	raw            map[string]string
+}
+
+var (
+	reDecimal    = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	reInteger    = regexp.MustCompile(`^[+-]?\d+$`)
+	reFloat      = regexp.MustCompile(`^([+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|[+-]?INF|NaN)$`)
+	reHex        = regexp.MustCompile(`^([0-9a-fA-F]{2})*$`)
+	reDateTime   = regexp.MustCompile(`^-?(\d{4,})-(\d{2})-(\d{2})T(\d{2}):(\d{2}):(\d{2})(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	reDate       = regexp.MustCompile(`^-?(\d{4,})-(\d{2})-(\d{2})(Z|[+-]\d{2}:\d{2})?$`)
+	reTime       = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	reGYear      = regexp.MustCompile(`^-?\d{4,}(Z|[+-]\d{2}:\d{2})?$`)
+	reGYearMonth = regexp.MustCompile(`^-?\d{4,}-(0[1-9]|1[0-2])(Z|[+-]\d{2}:\d{2})?$`)
+	reGMonth     = regexp.MustCompile(`^--(0[1-9]|1[0-2])(Z|[+-]\d{2}:\d{2})?$`)
+	reGDay       = regexp.MustCompile(`^---(0[1-9]|[12]\d|3[01])(Z|[+-]\d{2}:\d{2})?$`)
+	reGMonthDay  = regexp.MustCompile(`^--(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])(Z|[+-]\d{2}:\d{2})?$`)
+	reDuration   = regexp.MustCompile(`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+	reNCName     = regexp.MustCompile(`^[\pL_][\pL\pN_.\-]*$`)
+	reName       = regexp.MustCompile(`^[\pL_:][\pL\pN_.\-:]*$`)
+	reQName      = regexp.MustCompile(`^([\pL_][\pL\pN_.\-]*:)?[\pL_][\pL\pN_.\-]*$`)
+	reNMToken    = regexp.MustCompile(`^[\pL\pN_.\-:]+$`)
+	reLanguage   = regexp.MustCompile(`^[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*$`)
+)
+
+func anyValue(string) bool { return true }
+
+func matches(re *regexp.Regexp) func(string) bool {
+	return re.MatchString
+}
+
+func integerRange(min, max string) func(string) bool {
+	var lo, hi *big.Int
+	if min != "" {
+		lo, _ = new(big.Int).SetString(min, 10)
+	}
+	if max != "" {
+		hi, _ = new(big.Int).SetString(max, 10)
+	}
+	return func(v string) bool {
+		if !reInteger.MatchString(v) {
+			return false
+		}
+		i, _ := new(big.Int).SetString(strings.TrimPrefix(v, "+"), 10)
+		return (lo == nil || i.Cmp(lo) >= 0) && (hi == nil || i.Cmp(hi) <= 0)
+	}
+}
+
+func validDateTime(v string) bool {
+	m := reDateTime.FindStringSubmatch(v)
+	return m != nil && validDate(m[1], m[2], m[3]) && validTime(m[4], m[5], m[6], m[7])
+}
+
+func validDateOnly(v string) bool {
+	m := reDate.FindStringSubmatch(v)
+	return m != nil && validDate(m[1], m[2], m[3])
+}
+
+func validTimeOnly(v string) bool {
+	m := reTime.FindStringSubmatch(v)
+	return m != nil && validTime(m[1], m[2], m[3], m[4])
+}
+
+func validDate(y, m, d string) bool {
+	year, _ := strconv.Atoi(y)
+	month, _ := strconv.Atoi(m)
+	day, _ := strconv.Atoi(d)
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	days := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}[month-1]
+	if month == 2 && year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		days = 29
+	}
+	return day <= days
+}
+
+func validTime(h, m, s, frac string) bool {
+	hour, _ := strconv.Atoi(h)
+	minute, _ := strconv.Atoi(m)
+	second, _ := strconv.Atoi(s)
+	if hour == 24 {
+		return minute == 0 && second == 0 && strings.Trim(frac, ".0") == ""
+	}
+	return hour < 24 && minute < 60 && second < 60
+}
+
+func validDuration(v string) bool {
+	return reDuration.MatchString(v) && !strings.HasSuffix(v, "P") && !strings.HasSuffix(v, "T")
+}
+
+func validBoolean(v string) bool {
+	return v == "true" || v == "false" || v == "1" || v == "0"
+}
+
+func validBase64(v string) bool {
+	_, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(v, " ", ""))
+	return err == nil
+}
+
+func builtinList(item *builtinType) func(string) bool {
+	return func(v string) bool {
+		items := strings.Fields(v)
+		for _, i := range items {
+			if !item.valid(i) {
+				return false
+			}
+		}
+		return len(items) > 0
+	}
+}
+
+var builtinTypes = map[string]*builtinType{}
+
+func init() {
+	str := func(ws whitespace, valid func(string) bool) *builtinType {
+		return &builtinType{class: classString, ws: ws, valid: valid}
+	}
+	other := func(valid func(string) bool) *builtinType {
+		return &builtinType{class: classOther, ws: wsCollapse, valid: valid}
+	}
+	integer := func(min, max string) *builtinType {
+		return &builtinType{class: classDecimal, ws: wsCollapse, valid: integerRange(min, max)}
+	}
+	for name, bt := range map[string]*builtinType{
+		"anySimpleType":      str(wsPreserve, anyValue),
+		"string":             str(wsPreserve, anyValue),
+		"normalizedString":   str(wsReplace, anyValue),
+		"token":              str(wsCollapse, anyValue),
+		"language":           str(wsCollapse, matches(reLanguage)),
+		"Name":               str(wsCollapse, matches(reName)),
+		"NCName":             str(wsCollapse, matches(reNCName)),
+		"ID":                 str(wsCollapse, matches(reNCName)),
+		"IDREF":              str(wsCollapse, matches(reNCName)),
+		"ENTITY":             str(wsCollapse, matches(reNCName)),
+		"NMTOKEN":            str(wsCollapse, matches(reNMToken)),
+		"QName":              str(wsCollapse, matches(reQName)),
+		"anyURI":             str(wsCollapse, anyValue),
+		"boolean":            other(validBoolean),
+		"dateTime":           other(validDateTime),
+		"date":               other(validDateOnly),
+		"time":               other(validTimeOnly),
+		"duration":           other(validDuration),
+		"gYear":              other(matches(reGYear)),
+		"gYearMonth":         other(matches(reGYearMonth)),
+		"gMonth":             other(matches(reGMonth)),
+		"gDay":               other(matches(reGDay)),
+		"gMonthDay":          other(matches(reGMonthDay)),
+		"hexBinary":          {class: classHexBinary, ws: wsCollapse, valid: matches(reHex)},
+		"base64Binary":       {class: classBase64Binary, ws: wsCollapse, valid: validBase64},
+		"decimal":            {class: classDecimal, ws: wsCollapse, valid: matches(reDecimal)},
+		"float":              {class: classFloat, ws: wsCollapse, valid: matches(reFloat)},
+		"double":             {class: classFloat, ws: wsCollapse, valid: matches(reFloat)},
+		"integer":            integer("", ""),
+		"nonNegativeInteger": integer("0", ""),
+		"positiveInteger":    integer("1", ""),
+		"nonPositiveInteger": integer("", "0"),
+		"negativeInteger":    integer("", "-1"),
+		"long":               integer("-9223372036854775808", "9223372036854775807"),
+		"int":                integer("-2147483648", "2147483647"),
+		"short":              integer("-32768", "32767"),
+		"byte":               integer("-128", "127"),
+		"unsignedLong":       integer("0", "18446744073709551615"),
+		"unsignedInt":        integer("0", "4294967295"),
+		"unsignedShort":      integer("0", "65535"),
+		"unsignedByte":       integer("0", "255"),
+	} {
+		builtinTypes[name] = bt
+	}
+	for list, item := range map[string]string{"NMTOKENS": "NMTOKEN", "IDREFS": "IDREF", "ENTITIES": "ENTITY"} {
+		builtinTypes[list] = &builtinType{class: classList, ws: wsCollapse, valid: builtinList(builtinTypes[item])}
+	}
+}
+
+func newBuiltinType(local string) *simpleType {
+	bt, ok := builtinTypes[local]
+	if !ok {
+		return nil
+	}
+	return &simpleType{name: "xs:" + local, builtin: bt, ws: bt.ws, facets: noFacets()}
+}
+
+func noFacets() facets {
+	return facets{length: -1, minLength: -1, maxLength: -1, totalDigits: -1, fractionDigits: -1}
+}
+
+func normalizeWhitespace(ws whitespace, v string) string {
+	switch ws {
+	case wsReplace:
+		return strings.Map(func(r rune) rune {
+			if r == '\t' || r == '\n' || r == '\r' {
+				return ' '
+			}
+			return r
+		}, v)
+	case wsCollapse:
+		return strings.Join(strings.Fields(v), " ")
+	default:
+		return v
+	}
+}
+
+// class returns how facets apply to the values of the type
+func (st *simpleType) class() valueClass {
+	switch {
+	case st.builtin != nil:
+		return st.builtin.class
+	case st.item != nil:
+		return classList
+	case st.base != nil:
+		return st.base.class()
+	default:
+		return classOther
+	}
+}
+
+func (st *simpleType) validate(ctx context.Context, path, raw string) error {
+	return st.check(ctx, path, normalizeWhitespace(st.ws, raw))
+}
+
+func (st *simpleType) check(ctx context.Context, path, v string) error {
+	switch {
+	case st.builtin != nil:
+		if !st.builtin.valid(v) {
+			return i18n.NewError(ctx, coremsgs.MsgXMLInvalidValue, v, path, st.name)
+		}
+	case st.item != nil:
+		for _, item := range strings.Fields(v) {
+			if err := st.item.validate(ctx, path, item); err != nil {
+				return err
+			}
+		}
+	case st.members != nil:
+		valid := false
+		for _, m := range st.members {
+			if m.validate(ctx, path, v) == nil {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return i18n.NewError(ctx, coremsgs.MsgXMLInvalidValue, v, path, st.name)
+		}
+	default:
+		if err := st.base.check(ctx, path, v); err != nil {
+			return err
+		}
+	}
+	return st.checkFacets(ctx, path, v)
+}
+
+func (st *simpleType) checkFacets(ctx context.Context, path, v string) error {
+	f := &st.facets
+	restriction := func(facet string) error {
+		return i18n.NewError(ctx, coremsgs.MsgXMLValueRestriction, v, path, facet, f.raw[facet])
+	}
+	if len(f.enumeration) > 0 {
+		found := false
+		for _, e := range f.enumeration {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return restriction("enumeration")
+		}
+	}
+	if f.pattern != nil && !f.pattern.MatchString(v) {
+		return restriction("pattern")
+	}
+	if f.length >= 0 || f.minLength >= 0 || f.maxLength >= 0 {
+		l := st.valueLength(v)
+		switch {
+		case f.length >= 0 && l != f.length:
+			return restriction("length")
+		case f.minLength >= 0 && l < f.minLength:
+			return restriction("minLength")
+		case f.maxLength >= 0 && l > f.maxLength:
+			return restriction("maxLength")
+		}
+	}
+	if f.totalDigits >= 0 || f.fractionDigits >= 0 {
+		total, fraction := decimalDigits(v)
+		switch {
+		case f.totalDigits >= 0 && total > f.totalDigits:
+			return restriction("totalDigits")
+		case f.fractionDigits >= 0 && fraction > f.fractionDigits:
+			return restriction("fractionDigits")
+		}
+	}
+	for _, check := range []struct {
+		facet string
+		bound *big.Rat
+		ok    func(cmp int) bool
+	}{
+		{"minInclusive", f.minInclusive, func(cmp int) bool { return cmp >= 0 }},
+		{"maxInclusive", f.maxInclusive, func(cmp int) bool { return cmp <= 0 }},
+		{"minExclusive", f.minExclusive, func(cmp int) bool { return cmp > 0 }},
+		{"maxExclusive", f.maxExclusive, func(cmp int) bool { return cmp < 0 }},
+	} {
+		if check.bound != nil {
+			cmp, comparable := compareNumber(st.class(), v, check.bound)
+			if !comparable || !check.ok(cmp) {
+				return restriction(check.facet)
+			}
+		}
+	}
+	return nil
+}
+
+func (st *simpleType) valueLength(v string) int {
+	switch st.class() {
+	case classList:
+		return len(strings.Fields(v))
+	case classHexBinary:
+		return len(v) / 2
+	case classBase64Binary:
+		b, _ := base64.StdEncoding.DecodeString(strings.ReplaceAll(v, " ", ""))
+		return len(b)
+	default:
+		return len([]rune(v))
+	}
+}
+
+// decimalDigits returns the significant digits, and the digits after the decimal point, of a decimal value
+func decimalDigits(v string) (total, fraction int) {
+	v = strings.TrimLeft(v, "+-")
+	intPart, fracPart := v, ""
+	if i := strings.IndexByte(v, '.'); i >= 0 {
+		intPart, fracPart = v[:i], v[i+1:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	return len(intPart) + len(fracPart), len(fracPart)
+}
+
+// compareNumber compares a decimal or floating point value with a bound, returning false if they cannot be compared (NaN)
+func compareNumber(class valueClass, v string, bound *big.Rat) (int, bool) {
+	if class != classFloat {
+		r, ok := new(big.Rat).SetString(strings.TrimPrefix(v, "+"))
+		if !ok {
+			return 0, false
+		}
+		return r.Cmp(bound), true
+	}
+	f, err := strconv.ParseFloat(strings.Replace(v, "INF", "Inf", 1), 64)
+	switch {
+	case err != nil || math.IsNaN(f):
+		return 0, false
+	case math.IsInf(f, 0):
+		return int(math.Copysign(1, f)), true
+	default:
+		return new(big.Rat).SetFloat64(f).Cmp(bound), true
+	}
+}
+
+// translatePattern converts an XML Schema regular expression, which is implicitly anchored and has no
+// special meaning for '^' and '$', into a Go regular expression. The XML name escapes \i and \c
+// are approximated with ASCII character classes, and character class subtraction is not supported.
+func translatePattern(pattern string) string {
+	var b strings.Builder
+	b.WriteString(`^(?:`)
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			switch pattern[i] {
+			case 'i':
+				b.WriteString(`[_:A-Za-z]`)
+			case 'I':
+				b.WriteString(`[^_:A-Za-z]`)
+			case 'c':
+				b.WriteString(`[\-._:A-Za-z0-9]`)
+			case 'C':
+				b.WriteString(`[^\-._:A-Za-z0-9]`)
+			default:
+				b.WriteByte(c)
+				b.WriteByte(pattern[i])
+			}
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+		case c == ']':
+			inClass = false
+			b.WriteByte(c)
+		case (c == '^' && !(inClass && i > 0 && pattern[i-1] == '[')) || c == '$':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteString(`)$`)
+	return b.String()
+}
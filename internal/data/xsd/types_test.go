@@ -0,0 +1,184 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinTypes(t *testing.T) {
+	for typeName, values := range map[string]struct {
+		valid   []string
+		invalid []string
+	}{
+		"string":             {[]string{"", " any\tthing "}, nil},
+		"token":              {[]string{"  a   b  "}, nil},
+		"normalizedString":   {[]string{"a\tb\nc"}, nil},
+		"language":           {[]string{"en", "en-GB"}, []string{"toolongtag", ""}},
+		"Name":               {[]string{"a:b", "_x"}, []string{"1a"}},
+		"NCName":             {[]string{"a-b.c"}, []string{"a:b"}},
+		"QName":              {[]string{"p:local", "local"}, []string{"p:q:r"}},
+		"NMTOKEN":            {[]string{"123"}, []string{"a b"}},
+		"NMTOKENS":           {[]string{"a b c"}, []string{"", "a,b"}},
+		"IDREFS":             {[]string{"a b"}, []string{"1"}},
+		"boolean":            {[]string{"true", "0", " false "}, []string{"TRUE", "yes"}},
+		"dateTime":           {[]string{"2023-01-01T00:00:00", "2023-12-31T24:00:00Z", "-0044-03-15T12:00:00.5-05:00"}, []string{"2023-13-01T00:00:00", "2023-01-01T24:00:01", "2023-01-01T23:60:00", "2023-01-01"}},
+		"date":               {[]string{"2000-02-29", "2023-01-31Z"}, []string{"1900-02-29", "2023-04-31", "2023-00-10", "23-01-01"}},
+		"time":               {[]string{"13:20:00", "23:59:59.999+02:00"}, []string{"25:00:00", "1:00:00"}},
+		"duration":           {[]string{"P1Y2M3DT4H5M6.7S", "-PT1M", "P1D"}, []string{"P", "PT", "P1DT", "1D"}},
+		"gYear":              {[]string{"2023", "2023Z"}, []string{"23"}},
+		"gYearMonth":         {[]string{"2023-12"}, []string{"2023-13"}},
+		"gMonth":             {[]string{"--05"}, []string{"--13"}},
+		"gDay":               {[]string{"---31"}, []string{"---32"}},
+		"gMonthDay":          {[]string{"--02-29"}, []string{"--02-32"}},
+		"hexBinary":          {[]string{"", "0fA9"}, []string{"abc", "zz"}},
+		"base64Binary":       {[]string{"aGVsbG8=", "aGVs bG8="}, []string{"a"}},
+		"decimal":            {[]string{"1", "-1.5", "+.5", "2."}, []string{"1e5", ".", "INF"}},
+		"float":              {[]string{"1e5", "-INF", "NaN", "1.5E-3"}, []string{"1e", "inf"}},
+		"double":             {[]string{"INF"}, []string{"abc"}},
+		"integer":            {[]string{"123456789012345678901234567890", "+1"}, []string{"1.0"}},
+		"nonNegativeInteger": {[]string{"0"}, []string{"-1"}},
+		"positiveInteger":    {[]string{"1"}, []string{"0"}},
+		"nonPositiveInteger": {[]string{"0"}, []string{"1"}},
+		"negativeInteger":    {[]string{"-1"}, []string{"0"}},
+		"long":               {[]string{"9223372036854775807"}, []string{"9223372036854775808"}},
+		"int":                {[]string{"-2147483648"}, []string{"-2147483649"}},
+		"short":              {[]string{"32767"}, []string{"32768"}},
+		"byte":               {[]string{"-128"}, []string{"128"}},
+		"unsignedLong":       {[]string{"18446744073709551615"}, []string{"18446744073709551616"}},
+		"unsignedInt":        {[]string{"4294967295"}, []string{"-1"}},
+		"unsignedShort":      {[]string{"65535"}, []string{"65536"}},
+		"unsignedByte":       {[]string{"255"}, []string{"256"}},
+		"anyURI":             {[]string{"https://example.com/a?b=c"}, nil},
+	} {
+		st := newBuiltinType(typeName)
+		for _, v := range values.valid {
+			assert.NoError(t, st.validate(context.Background(), "/v", v), "%s '%s'", typeName, v)
+		}
+		for _, v := range values.invalid {
+			assert.Regexp(t, "FF10518.*xs:"+typeName, st.validate(context.Background(), "/v", v), "%s '%s'", typeName, v)
+		}
+	}
+	assert.Nil(t, newBuiltinType("nope"))
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	assert.Equal(t, " a\tb\n", normalizeWhitespace(wsPreserve, " a\tb\n"))
+	assert.Equal(t, " a b ", normalizeWhitespace(wsReplace, " a\tb\r"))
+	assert.Equal(t, "a b", normalizeWhitespace(wsCollapse, " a \t b\n"))
+}
+
+func TestFacetLengths(t *testing.T) {
+	ctx := context.Background()
+	hex := &simpleType{name: "hex", base: newBuiltinType("hexBinary"), ws: wsCollapse, facets: noFacets()}
+	hex.facets.length = 2
+	hex.facets.raw = map[string]string{"length": "2"}
+	assert.NoError(t, hex.validate(ctx, "/v", "0a0b"))
+	assert.Regexp(t, "FF10519.*length", hex.validate(ctx, "/v", "0a"))
+
+	b64 := &simpleType{name: "b64", base: newBuiltinType("base64Binary"), ws: wsCollapse, facets: noFacets()}
+	b64.facets.maxLength = 3
+	b64.facets.raw = map[string]string{"maxLength": "3"}
+	assert.NoError(t, b64.validate(ctx, "/v", "YWJj"))
+	assert.Regexp(t, "FF10519.*maxLength", b64.validate(ctx, "/v", "YWJjZA=="))
+
+	list := &simpleType{name: "list", item: newBuiltinType("int"), ws: wsCollapse, facets: noFacets()}
+	list.facets.minLength = 2
+	list.facets.raw = map[string]string{"minLength": "2"}
+	assert.NoError(t, list.validate(ctx, "/v", "1 2"))
+	assert.Regexp(t, "FF10519.*minLength", list.validate(ctx, "/v", "1"))
+
+	str := &simpleType{name: "str", base: newBuiltinType("string"), ws: wsPreserve, facets: noFacets()}
+	str.facets.maxLength = 2
+	str.facets.raw = map[string]string{"maxLength": "2"}
+	assert.NoError(t, str.validate(ctx, "/v", "üü"))
+	assert.Equal(t, classOther, (&simpleType{members: []*simpleType{str}}).class())
+}
+
+func TestFacetBounds(t *testing.T) {
+	ctx := context.Background()
+	bounded := func(base string, facet func(f *facets)) *simpleType {
+		st := &simpleType{name: "bounded", base: newBuiltinType(base), ws: wsCollapse, facets: noFacets()}
+		st.facets.raw = map[string]string{}
+		facet(&st.facets)
+		return st
+	}
+	ten := big.NewRat(10, 1)
+	maxInc := bounded("decimal", func(f *facets) { f.maxInclusive = ten })
+	assert.NoError(t, maxInc.validate(ctx, "/v", "10.0"))
+	assert.Regexp(t, "FF10519.*maxInclusive", maxInc.validate(ctx, "/v", "10.01"))
+	minExc := bounded("integer", func(f *facets) { f.minExclusive = ten })
+	assert.NoError(t, minExc.validate(ctx, "/v", "11"))
+	assert.Regexp(t, "FF10519.*minExclusive", minExc.validate(ctx, "/v", "10"))
+	maxExc := bounded("double", func(f *facets) { f.maxExclusive = ten })
+	assert.NoError(t, maxExc.validate(ctx, "/v", "9.99e0"))
+	assert.NoError(t, maxExc.validate(ctx, "/v", "-INF"))
+	assert.Regexp(t, "FF10519.*maxExclusive", maxExc.validate(ctx, "/v", "1e1"))
+	assert.Regexp(t, "FF10519.*maxExclusive", maxExc.validate(ctx, "/v", "NaN"))
+	minInc := bounded("float", func(f *facets) { f.minInclusive = ten })
+	assert.NoError(t, minInc.validate(ctx, "/v", "INF"))
+	assert.Regexp(t, "FF10519.*minInclusive", minInc.validate(ctx, "/v", "1e400"))
+
+	_, comparable := compareNumber(classDecimal, "x", ten)
+	assert.False(t, comparable)
+}
+
+func TestDecimalDigits(t *testing.T) {
+	total, fraction := decimalDigits("-00123.4500")
+	assert.Equal(t, 5, total)
+	assert.Equal(t, 2, fraction)
+	total, fraction = decimalDigits("0.0")
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 0, fraction)
+}
+
+func TestTranslatePattern(t *testing.T) {
+	for pattern, test := range map[string]struct {
+		match   []string
+		nomatch []string
+	}{
+		`[A-Z]{3}`:       {[]string{"EUR"}, []string{"EURO", "eur"}},
+		`a^b$c`:          {[]string{"a^b$c"}, []string{"abc"}},
+		`[^0-9]+`:        {[]string{"abc"}, []string{"a1"}},
+		`[a^]`:           {[]string{"^"}, []string{"b"}},
+		`\i\c*`:          {[]string{"_a-1", "x"}, []string{"1a"}},
+		`\I\C`:           {[]string{"1 "}, []string{"ab"}},
+		`\d{2}\.\d+`:     {[]string{"12.5"}, []string{"12x5"}},
+		`[+]{0,1}[0-9]*`: {[]string{"+1", ""}, []string{"-1"}},
+		`a|b`:            {[]string{"a", "b"}, []string{"ab"}},
+		`trailing\`:      {[]string{`trailing\`}, []string{"trailing"}},
+	} {
+		re, err := regexp.Compile(translatePattern(pattern))
+		if strings.HasSuffix(pattern, `\`) {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err, pattern)
+		for _, v := range test.match {
+			assert.True(t, re.MatchString(v), "%s should match '%s'", pattern, v)
+		}
+		for _, v := range test.nomatch {
+			assert.False(t, re.MatchString(v), "%s should not match '%s'", pattern, v)
+		}
+	}
+}
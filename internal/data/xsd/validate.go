@@ -0,0 +1,319 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+type validator struct {
+	ctx    context.Context
+	schema *Schema
+}
+
+// contentMatch tracks the matching of the child elements of one element against its content model
+type contentMatch struct {
+	children []*node
+	matched  []*particle
+	furthest int
+}
+
+// Validate checks that an XML document conforms to the schema
+func (s *Schema) Validate(ctx context.Context, r io.Reader) error {
+	doc, err := parseDocument(ctx, r)
+	if err != nil {
+		return err
+	}
+	decl, ok := s.elements[doc.name]
+	if !ok {
+		return i18n.NewError(ctx, coremsgs.MsgXMLUnexpectedElement, doc.name.Local, "/")
+	}
+	v := &validator{ctx: ctx, schema: s}
+	return v.element(doc, decl, "/"+doc.name.Local)
+}
+
+func (v *validator) element(n *node, decl *element, path string) error {
+	nilled := false
+	for _, a := range n.attrs {
+		if a.Name.Space == xsiNS && a.Name.Local == "nil" {
+			if !decl.nillable {
+				return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedAttribute, "xsi:nil", path)
+			}
+			nilled = a.Value == "true" || a.Value == "1"
+		}
+	}
+	if nilled && (len(n.children) > 0 || n.text != "") {
+		return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedText, path)
+	}
+
+	ct := decl.typ.complex
+	if ct == nil {
+		if err := v.attributes(n, &complexType{}, path); err != nil {
+			return err
+		}
+		if nilled {
+			return nil
+		}
+		return v.simpleValue(n, decl.typ.simple, decl.fixed, path)
+	}
+	if ct.anyType {
+		return nil
+	}
+	if err := v.attributes(n, ct, path); err != nil {
+		return err
+	}
+	switch {
+	case nilled:
+		return nil
+	case ct.simpleContent != nil:
+		return v.simpleValue(n, ct.simpleContent, decl.fixed, path)
+	case !ct.mixed && strings.TrimSpace(n.text) != "":
+		return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedText, path)
+	default:
+		return v.content(n, ct.content, path)
+	}
+}
+
+func (v *validator) simpleValue(n *node, st *simpleType, fixed *string, path string) error {
+	if len(n.children) > 0 {
+		return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedElement, n.children[0].name.Local, path)
+	}
+	if err := st.validate(v.ctx, path, n.text); err != nil {
+		return err
+	}
+	return checkFixed(v.ctx, st, fixed, n.text, path)
+}
+
+func checkFixed(ctx context.Context, st *simpleType, fixed *string, value, path string) error {
+	if fixed != nil && normalizeWhitespace(st.ws, value) != normalizeWhitespace(st.ws, *fixed) {
+		return i18n.NewError(ctx, coremsgs.MsgXMLValueRestriction, value, path, "fixed", *fixed)
+	}
+	return nil
+}
+
+func (v *validator) attributes(n *node, ct *complexType, path string) error {
+	found := make(map[*attribute]bool, len(ct.attributes))
+	for _, a := range n.attrs {
+		if isNamespaceDecl(a) || a.Name.Space == xsiNS {
+			continue
+		}
+		var decl *attribute
+		for _, candidate := range ct.attributes {
+			if candidate.name == a.Name {
+				decl = candidate
+				break
+			}
+		}
+		if decl == nil {
+			if ct.anyAttribute != nil && ct.anyAttribute.allows(a.Name.Space) {
+				continue
+			}
+			return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedAttribute, a.Name.Local, path)
+		}
+		found[decl] = true
+		attrPath := path + "/@" + a.Name.Local
+		if err := decl.typ.validate(v.ctx, attrPath, a.Value); err != nil {
+			return err
+		}
+		if err := checkFixed(v.ctx, decl.typ, decl.fixed, a.Value, attrPath); err != nil {
+			return err
+		}
+	}
+	for _, decl := range ct.attributes {
+		if decl.required && !found[decl] {
+			return i18n.NewError(v.ctx, coremsgs.MsgXMLMissingAttribute, decl.name.Local, path)
+		}
+	}
+	return nil
+}
+
+// content matches the child elements against the content model, then validates each child
+func (v *validator) content(n *node, model *particle, path string) error {
+	if model == nil {
+		if len(n.children) > 0 {
+			return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedElement, n.children[0].name.Local, path)
+		}
+		return nil
+	}
+	m := &contentMatch{children: n.children, matched: make([]*particle, len(n.children))}
+	ends := m.repeat(model, []int{0})
+	complete := false
+	for _, end := range ends {
+		if end == len(n.children) {
+			complete = true
+		}
+	}
+	if !complete {
+		if m.furthest < len(n.children) {
+			return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedElement, n.children[m.furthest].name.Local, path)
+		}
+		return i18n.NewError(v.ctx, coremsgs.MsgXMLIncompleteContent, path)
+	}
+	for i, child := range n.children {
+		childPath := path + "/" + child.name.Local
+		var err error
+		if p := m.matched[i]; p.kind == particleElement {
+			err = v.element(child, p.element, childPath)
+		} else {
+			err = v.wildcardElement(child, p.wildcard, childPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wildcardElement validates an element matched by xs:any, according to its processContents
+func (v *validator) wildcardElement(n *node, w *wildcard, path string) error {
+	if w.process == "skip" {
+		return nil
+	}
+	decl, ok := v.schema.elements[n.name]
+	if !ok {
+		if w.process == "strict" {
+			return i18n.NewError(v.ctx, coremsgs.MsgXMLUnexpectedElement, n.name.Local, path)
+		}
+		return nil
+	}
+	return v.element(n, decl, path)
+}
+
+// repeat returns the positions that can be reached by matching a particle between its
+// minimum and maximum number of times, from any of the starting positions
+func (m *contentMatch) repeat(p *particle, start []int) []int {
+	var result []int
+	if p.min == 0 {
+		result = append(result, start...)
+	}
+	seen := positions(start)
+	frontier := start
+	for count := 1; p.max < 0 || count <= p.max; count++ {
+		var next []int
+		for _, pos := range frontier {
+			next = append(next, m.once(p, pos)...)
+		}
+		next = dedup(next)
+		if len(next) == 0 {
+			break
+		}
+		if count >= p.min {
+			result = append(result, next...)
+			// Once the minimum is satisfied, only positions not already reached can lead anywhere new
+			var unseen []int
+			for _, pos := range next {
+				if !seen[pos] {
+					unseen = append(unseen, pos)
+				}
+			}
+			if len(unseen) == 0 {
+				break
+			}
+			next = unseen
+		}
+		for _, pos := range next {
+			seen[pos] = true
+		}
+		frontier = next
+	}
+	return dedup(result)
+}
+
+// once returns the positions that can be reached by matching a particle exactly once from a position
+func (m *contentMatch) once(p *particle, pos int) []int {
+	switch p.kind {
+	case particleElement, particleAny:
+		if pos < len(m.children) {
+			child := m.children[pos]
+			if (p.kind == particleElement && child.name == p.element.name) ||
+				(p.kind == particleAny && p.wildcard.allows(child.name.Space)) {
+				m.matched[pos] = p
+				if pos+1 > m.furthest {
+					m.furthest = pos + 1
+				}
+				return []int{pos + 1}
+			}
+		}
+		return nil
+	case particleSequence:
+		current := []int{pos}
+		for _, c := range p.children {
+			if current = m.repeat(c, current); len(current) == 0 {
+				return nil
+			}
+		}
+		return current
+	case particleChoice:
+		var result []int
+		for _, c := range p.children {
+			result = append(result, m.repeat(c, []int{pos})...)
+		}
+		return dedup(result)
+	default:
+		return m.all(p, pos)
+	}
+}
+
+// all matches the elements of an xs:all group, which can appear in any order
+func (m *contentMatch) all(p *particle, pos int) []int {
+	used := make(map[*particle]bool, len(p.children))
+	for pos < len(m.children) {
+		var matched *particle
+		for _, c := range p.children {
+			if !used[c] && len(m.once(c, pos)) > 0 {
+				matched = c
+				break
+			}
+		}
+		if matched == nil {
+			break
+		}
+		used[matched] = true
+		pos++
+	}
+	for _, c := range p.children {
+		if c.min > 0 && !used[c] {
+			return nil
+		}
+	}
+	return []int{pos}
+}
+
+func positions(list []int) map[int]bool {
+	set := make(map[int]bool, len(list))
+	for _, pos := range list {
+		set[pos] = true
+	}
+	return set
+}
+
+func dedup(list []int) []int {
+	set := positions(list)
+	result := make([]int, 0, len(set))
+	for _, pos := range list {
+		if set[pos] {
+			result = append(result, pos)
+			delete(set, pos)
+		}
+	}
+	return result
+}
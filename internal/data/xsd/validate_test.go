@@ -0,0 +1,301 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePaymentDocument(t *testing.T) {
+	s := testSchema(t, testPaymentSchema)
+	err := s.Validate(context.Background(), strings.NewReader(testPaymentDocument))
+	assert.NoError(t, err)
+}
+
+func TestValidatePaymentDocumentErrors(t *testing.T) {
+	s := testSchema(t, testPaymentSchema)
+	for name, test := range map[string]struct {
+		from, to string
+		err      string
+	}{
+		"badXML":           {`</Document>`, ``, "FF10512"},
+		"wrongNamespace":   {`<Document xmlns="urn:example:pain.001"`, `<Document xmlns="urn:other"`, "FF10513.*Document"},
+		"fixedAttr":        {`instance" version="1.0"`, `instance" version="2.0"`, "FF10519.*2.0.*/Document/@version.*fixed"},
+		"missingRequired":  {`<MsgId>MSG-0001</MsgId>`, ``, "FF10513.*CreDtTm.*/Document/GrpHdr"},
+		"incomplete":       {`<InitgPty>Org A</InitgPty>`, ``, "FF10514.*/Document/GrpHdr"},
+		"unexpected":       {`<InitgPty>Org A</InitgPty>`, `<InitgPty>Org A</InitgPty><FwdgAgt>B</FwdgAgt>`, "FF10513.*FwdgAgt"},
+		"maxLength":        {`MSG-0001`, strings.Repeat("x", 36), "FF10519.*/Document/GrpHdr/MsgId.*maxLength.*35"},
+		"minLength":        {`<MsgId>MSG-0001</MsgId>`, `<MsgId></MsgId>`, "FF10519.*minLength"},
+		"pattern":          {`<NbOfTxs>2</NbOfTxs>`, `<NbOfTxs>two</NbOfTxs>`, "FF10519.*pattern.*0-9"},
+		"dateTime":         {`2023-02-28T10:15:30.123+01:00`, `2023-02-30T10:15:30Z`, "FF10518.*xs:dateTime"},
+		"enumeration":      {`<PmtMtd>TRF</PmtMtd>`, `<PmtMtd>XXX</PmtMtd>`, "FF10519.*XXX.*enumeration.*CHK,TRF"},
+		"date":             {`2024-02-29`, `2023-02-29`, "FF10518.*xs:date"},
+		"decimal":          {`>1000.50<`, `>ten<`, "FF10518.*ten.*xs:decimal"},
+		"fractionDigits":   {`>1000.50<`, `>1.123456<`, "FF10519.*fractionDigits"},
+		"totalDigits":      {`>1000.50<`, `>1234567890123456789<`, "FF10519.*totalDigits"},
+		"minInclusive":     {`>1000.50<`, `>-1<`, "FF10519.*minInclusive"},
+		"missingAttr":      {`<Amt Ccy="EUR">`, `<Amt>`, "FF10516.*Ccy.*/Document/PmtInf/Amt"},
+		"badAttr":          {`<Amt Ccy="EUR">`, `<Amt Ccy="eur">`, "FF10519.*/Document/PmtInf/Amt/@Ccy.*pattern"},
+		"unexpectedAttr":   {`<Amt Ccy="EUR">`, `<Amt Ccy="EUR" x="y">`, "FF10515.*x"},
+		"simpleChildren":   {`<Amt Ccy="EUR">1000.50</Amt>`, `<Amt Ccy="EUR"><x/></Amt>`, "FF10513.*x.*/Document/PmtInf/Amt"},
+		"list":             {`internal </Tags>`, `internal 9lives</Tags>`, "FF10518.*9lives.*xs:NCName"},
+		"union":            {`<Prty>HIGH</Prty>`, `<Prty>LOW</Prty>`, "FF10518.*LOW.*PriorityOrNumber"},
+		"requiredAttr":     {`<PmtInf seq="2">`, `<PmtInf>`, "FF10516.*seq"},
+		"positiveInteger":  {`<PmtInf seq="2">`, `<PmtInf seq="0">`, "FF10518.*xs:positiveInteger"},
+		"refAttr":          {`p:origin="api"`, `p:origin="a b"`, "FF10518.*xs:NCName"},
+		"allMissing":       {`<Ustrd>Invoice 123</Ustrd>`, ``, "FF10514.*/Document/PmtInf/Rmt"},
+		"allDuplicate":     {`<Ref>R1</Ref>`, `<Ref>R1</Ref><Ref>R2</Ref>`, "FF10513.*Ref"},
+		"nilContent":       {`<Rmt xsi:nil="true"/>`, `<Rmt xsi:nil="true">x</Rmt>`, "FF10517.*/Document/PmtInf/Rmt"},
+		"notNillable":      {`<Prty>-3</Prty>`, `<Prty xsi:nil="true"/>`, "FF10515.*xsi:nil"},
+		"text":             {`<GrpHdr>`, `<GrpHdr>text`, "FF10517.*/Document/GrpHdr"},
+		"anyNamespace":     {`<ext:Envlp><ext:Anything/></ext:Envlp>`, `<Envlp/>`, "FF10513.*Envlp"},
+		"anyLaxKnown":      {`<ext:Envlp><ext:Anything/></ext:Envlp>`, `<Document xmlns="urn:example:pain.001"/>`, "FF10513.*Document"},
+		"unexpectedSimple": {`<CreDtTm>`, `<CreDtTm x="1">`, "FF10515.*x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			doc := strings.Replace(testPaymentDocument, test.from, test.to, 1)
+			assert.NotEqual(t, testPaymentDocument, doc)
+			err := s.Validate(context.Background(), strings.NewReader(doc))
+			assert.Regexp(t, test.err, err)
+		})
+	}
+}
+
+func TestValidateUnknownRoot(t *testing.T) {
+	s := testSchema(t, testPaymentSchema)
+	err := s.Validate(context.Background(), strings.NewReader(`<Other xmlns="urn:example:pain.001"/>`))
+	assert.Regexp(t, "FF10513.*Other.*'/'", err)
+}
+
+func TestValidateLaxWildcardKnownElement(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+		<xs:element name="wrapper">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:any processContents="lax" maxOccurs="unbounded"/>
+				</xs:sequence>
+			</xs:complexType>
+		</xs:element>
+		<xs:element name="count" type="xs:int"/>
+	</xs:schema>`)
+	assert.NoError(t, s.Validate(context.Background(), strings.NewReader(`<wrapper><unknown/><count>1</count></wrapper>`)))
+	err := s.Validate(context.Background(), strings.NewReader(`<wrapper><count>x</count></wrapper>`))
+	assert.Regexp(t, "FF10518.*/wrapper/count", err)
+}
+
+func TestValidateWildcards(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a" xmlns:a="urn:a">
+		<xs:element name="strict">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:any namespace="##targetNamespace ##local" maxOccurs="unbounded"/>
+				</xs:sequence>
+			</xs:complexType>
+		</xs:element>
+		<xs:element name="skip">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:any namespace="urn:b" processContents="skip"/>
+				</xs:sequence>
+				<xs:anyAttribute namespace="urn:b"/>
+			</xs:complexType>
+		</xs:element>
+		<xs:element name="count" type="xs:int"/>
+	</xs:schema>`)
+	ctx := context.Background()
+	assert.NoError(t, s.Validate(ctx, strings.NewReader(`<strict xmlns="urn:a"><count>1</count></strict>`)))
+	assert.Regexp(t, "FF10513.*other", s.Validate(ctx, strings.NewReader(`<strict xmlns="urn:a"><other/></strict>`)))
+	assert.Regexp(t, "FF10513.*count", s.Validate(ctx, strings.NewReader(`<strict xmlns="urn:a"><count xmlns="urn:c"/></strict>`)))
+	assert.NoError(t, s.Validate(ctx, strings.NewReader(`<skip xmlns="urn:a" xmlns:b="urn:b" b:x="1"><b:anything a="1"/></skip>`)))
+	assert.Regexp(t, "FF10515.*x", s.Validate(ctx, strings.NewReader(`<skip xmlns="urn:a" xmlns:c="urn:c" c:x="1"><anything xmlns="urn:b"/></skip>`)))
+}
+
+func TestValidateDerivedComplexTypes(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xml="http://www.w3.org/XML/1998/namespace">
+		<xs:complexType name="Base">
+			<xs:sequence>
+				<xs:element name="id" type="xs:int"/>
+			</xs:sequence>
+			<xs:attribute name="kind" type="xs:string"/>
+			<xs:attribute name="legacy" type="xs:string"/>
+		</xs:complexType>
+		<xs:complexType name="Extended">
+			<xs:complexContent>
+				<xs:extension base="Base">
+					<xs:sequence>
+						<xs:element name="name" type="xs:string"/>
+					</xs:sequence>
+					<xs:attribute ref="xml:lang"/>
+				</xs:extension>
+			</xs:complexContent>
+		</xs:complexType>
+		<xs:complexType name="AttributesOnly">
+			<xs:complexContent>
+				<xs:extension base="Base">
+					<xs:attribute name="extra" type="xs:boolean"/>
+				</xs:extension>
+			</xs:complexContent>
+		</xs:complexType>
+		<xs:complexType name="Restricted">
+			<xs:complexContent>
+				<xs:restriction base="Base">
+					<xs:sequence>
+						<xs:element name="id" type="xs:byte"/>
+					</xs:sequence>
+					<xs:attribute name="kind" type="xs:string" use="required"/>
+					<xs:attribute name="legacy" use="prohibited"/>
+				</xs:restriction>
+			</xs:complexContent>
+		</xs:complexType>
+		<xs:complexType name="Mixed" mixed="true">
+			<xs:sequence>
+				<xs:element name="b" type="xs:string" minOccurs="0" maxOccurs="unbounded"/>
+			</xs:sequence>
+		</xs:complexType>
+		<xs:complexType name="Empty"/>
+		<xs:complexType name="Code">
+			<xs:simpleContent>
+				<xs:extension base="xs:string">
+					<xs:attribute name="scheme" type="xs:string"/>
+				</xs:extension>
+			</xs:simpleContent>
+		</xs:complexType>
+		<xs:complexType name="ShortCode">
+			<xs:simpleContent>
+				<xs:restriction base="Code">
+					<xs:maxLength value="4"/>
+					<xs:pattern value="[A-Z]+"/>
+					<xs:pattern value="[0-9]+"/>
+				</xs:restriction>
+			</xs:simpleContent>
+		</xs:complexType>
+		<xs:complexType name="InlineCode">
+			<xs:simpleContent>
+				<xs:restriction base="Code">
+					<xs:simpleType>
+						<xs:restriction base="xs:string">
+							<xs:length value="2"/>
+						</xs:restriction>
+					</xs:simpleType>
+				</xs:restriction>
+			</xs:simpleContent>
+		</xs:complexType>
+		<xs:element name="doc">
+			<xs:complexType>
+				<xs:choice maxOccurs="unbounded">
+					<xs:element name="ext" type="Extended"/>
+					<xs:element name="attrs" type="AttributesOnly"/>
+					<xs:element name="res" type="Restricted"/>
+					<xs:element name="mixed" type="Mixed"/>
+					<xs:element name="empty" type="Empty"/>
+					<xs:element name="code" type="ShortCode"/>
+					<xs:element name="inline" type="InlineCode"/>
+					<xs:element name="any"/>
+					<xs:element name="fixed" type="xs:int" fixed="42"/>
+				</xs:choice>
+			</xs:complexType>
+		</xs:element>
+	</xs:schema>`)
+	ctx := context.Background()
+	assert.NoError(t, s.Validate(ctx, strings.NewReader(`<doc>
+		<ext kind="a" xml:lang="en"><id>1</id><name>x</name></ext>
+		<attrs extra="true"><id>2</id></attrs>
+		<res kind="b"><id>3</id></res>
+		<mixed>some <b>bold</b> text</mixed>
+		<empty/>
+		<code scheme="s">ABCD</code>
+		<code>1234</code>
+		<inline>AB</inline>
+		<any anything="goes"><x>y</x></any>
+		<fixed> 42 </fixed>
+	</doc>`)))
+	for doc, expected := range map[string]string{
+		`<doc><ext><id>1</id></ext></doc>`:                     "FF10514.*/doc/ext",
+		`<doc><ext><name>x</name><id>1</id></ext></doc>`:       "FF10513.*name.*/doc/ext",
+		`<doc><attrs extra="maybe"><id>1</id></attrs></doc>`:   "FF10518.*xs:boolean",
+		`<doc><res><id>1</id></res></doc>`:                     "FF10516.*kind",
+		`<doc><res kind="a" legacy="x"><id>1</id></res></doc>`: "FF10515.*legacy",
+		`<doc><res kind="a"><id>1000</id></res></doc>`:         "FF10518.*xs:byte",
+		`<doc><empty><x/></empty></doc>`:                       "FF10513.*x.*/doc/empty",
+		`<doc><code>ABCDE</code></doc>`:                        "FF10519.*maxLength",
+		`<doc><code>AB12</code></doc>`:                         "FF10519.*pattern.*\\[A-Z\\]\\+\\|\\[0-9\\]\\+",
+		`<doc><inline>ABC</inline></doc>`:                      "FF10519.*length",
+		`<doc><fixed>41</fixed></doc>`:                         "FF10519.*fixed.*42",
+		`<doc/>`:                                               "FF10514.*/doc",
+	} {
+		assert.Regexp(t, expected, s.Validate(ctx, strings.NewReader(doc)), doc)
+	}
+}
+
+func TestValidateOccurrences(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+		<xs:element name="doc">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:sequence minOccurs="2" maxOccurs="3">
+						<xs:element name="a" type="xs:string" minOccurs="0"/>
+						<xs:element name="b" type="xs:string"/>
+					</xs:sequence>
+					<xs:choice minOccurs="0">
+						<xs:element name="c" type="xs:string"/>
+						<xs:element name="d" type="xs:string" minOccurs="0"/>
+					</xs:choice>
+				</xs:sequence>
+			</xs:complexType>
+		</xs:element>
+	</xs:schema>`)
+	ctx := context.Background()
+	for doc, expected := range map[string]string{
+		`<doc><b/><b/></doc>`:                 "",
+		`<doc><a/><b/><b/><c/></doc>`:         "",
+		`<doc><a/><b/><a/><b/><b/><d/></doc>`: "",
+		`<doc><b/></doc>`:                     "FF10514",
+		`<doc><b/><b/><b/><b/></doc>`:         "FF10513.*b",
+		`<doc><b/><b/><c/><d/></doc>`:         "FF10513.*d",
+		`<doc><a/><a/></doc>`:                 "FF10513.*a",
+	} {
+		err := s.Validate(ctx, strings.NewReader(doc))
+		if expected == "" {
+			assert.NoError(t, err, doc)
+		} else {
+			assert.Regexp(t, expected, err, doc)
+		}
+	}
+}
+
+func TestValidateUnqualifiedLocalElements(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a" attributeFormDefault="qualified">
+		<xs:element name="doc">
+			<xs:complexType>
+				<xs:sequence>
+					<xs:element name="local" type="xs:string"/>
+					<xs:element name="qualified" type="xs:string" form="qualified"/>
+				</xs:sequence>
+				<xs:attribute name="qa" type="xs:string"/>
+				<xs:attribute name="ua" type="xs:string" form="unqualified"/>
+			</xs:complexType>
+		</xs:element>
+	</xs:schema>`)
+	ctx := context.Background()
+	assert.NoError(t, s.Validate(ctx, strings.NewReader(`<a:doc xmlns:a="urn:a" a:qa="1" ua="2"><local/><a:qualified/></a:doc>`)))
+	assert.Regexp(t, "FF10513.*local", s.Validate(ctx, strings.NewReader(`<doc xmlns="urn:a"><local/><qualified/></doc>`)))
+	assert.Regexp(t, "FF10515.*qa", s.Validate(ctx, strings.NewReader(`<a:doc xmlns:a="urn:a" qa="1"><local/><a:qualified/></a:doc>`)))
+}
@@ -0,0 +1,858 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xsd implements validation of XML documents against an XML Schema (XSD 1.0), for
+// use as a FireFly datatype. The schema must be a single self-contained document, as it is
+// broadcast as the value of the datatype.
+//
+// The supported subset covers the structures used by common message standards, such as
+// ISO 20022: global and local elements and types, sequence, choice and all model groups,
+// named groups and attribute groups, minOccurs/maxOccurs, wildcards, nillable and fixed
+// values, complex and simple content derived by extension and restriction, simple type
+// restrictions, lists and unions, and the built-in data types with their facets.
+//
+// Schemas using import, include, redefine or substitution groups are rejected. Identity
+// constraints (key, keyref and unique) are accepted but not enforced, and xsi:type is
+// not used to select the type of an element.
+package xsd
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	xsdNS = "http://www.w3.org/2001/XMLSchema"
+	xsiNS = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// Schema is a compiled XML schema, that can be used to validate many documents
+type Schema struct {
+	targetNS string
+	elements map[xml.Name]*element
+}
+
+type element struct {
+	name     xml.Name
+	typ      *typeDef
+	nillable bool
+	fixed    *string
+}
+
+// typeDef is either a simple or a complex type. Named complex types are referenced before they
+// are complete, to allow recursive structures.
+type typeDef struct {
+	simple   *simpleType
+	complex  *complexType
+	building bool
+}
+
+type complexType struct {
+	anyType       bool
+	mixed         bool
+	content       *particle
+	simpleContent *simpleType
+	attributes    []*attribute
+	anyAttribute  *wildcard
+}
+
+type attribute struct {
+	name     xml.Name
+	typ      *simpleType
+	required bool
+	fixed    *string
+}
+
+type particleKind int
+
+const (
+	particleElement particleKind = iota
+	particleAny
+	particleSequence
+	particleChoice
+	particleAll
+)
+
+type particle struct {
+	kind     particleKind
+	min      int
+	max      int // -1 for unbounded
+	element  *element
+	wildcard *wildcard
+	children []*particle
+}
+
+type wildcard struct {
+	anyNamespace bool
+	other        bool
+	namespaces   map[string]bool
+	targetNS     string
+	process      string
+}
+
+func (w *wildcard) allows(space string) bool {
+	switch {
+	case w.anyNamespace:
+		return true
+	case w.other:
+		return space != w.targetNS && space != ""
+	default:
+		return w.namespaces[space]
+	}
+}
+
+type parser struct {
+	ctx                 context.Context
+	targetNS            string
+	qualifiedElements   bool
+	qualifiedAttributes bool
+	elementNodes        map[xml.Name]*node
+	typeNodes           map[xml.Name]*node
+	groupNodes          map[xml.Name]*node
+	attributeGroupNodes map[xml.Name]*node
+	attributeNodes      map[xml.Name]*node
+	elements            map[xml.Name]*element
+	types               map[xml.Name]*typeDef
+	resolving           map[*node]bool
+}
+
+// Parse compiles an XML schema document
+func Parse(ctx context.Context, r io.Reader) (*Schema, error) {
+	root, err := parseDocument(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if root.name.Space != xsdNS || root.name.Local != "schema" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgXSDInvalid, "root element must be an XML Schema 'schema'")
+	}
+	p := &parser{
+		ctx:                 ctx,
+		elementNodes:        map[xml.Name]*node{},
+		typeNodes:           map[xml.Name]*node{},
+		groupNodes:          map[xml.Name]*node{},
+		attributeGroupNodes: map[xml.Name]*node{},
+		attributeNodes:      map[xml.Name]*node{},
+		elements:            map[xml.Name]*element{},
+		types:               map[xml.Name]*typeDef{},
+		resolving:           map[*node]bool{},
+	}
+	p.targetNS, _ = root.attr("targetNamespace")
+	efd, _ := root.attr("elementFormDefault")
+	p.qualifiedElements = efd == "qualified"
+	afd, _ := root.attr("attributeFormDefault")
+	p.qualifiedAttributes = afd == "qualified"
+
+	for _, n := range root.elements() {
+		var nodes map[xml.Name]*node
+		switch n.name.Local {
+		case "element":
+			nodes = p.elementNodes
+		case "complexType", "simpleType":
+			nodes = p.typeNodes
+		case "group":
+			nodes = p.groupNodes
+		case "attributeGroup":
+			nodes = p.attributeGroupNodes
+		case "attribute":
+			nodes = p.attributeNodes
+		case "notation":
+			continue
+		default:
+			return nil, i18n.NewError(ctx, coremsgs.MsgXSDUnsupported, n.name.Local)
+		}
+		name, _ := n.attr("name")
+		if name == "" {
+			return nil, i18n.NewError(ctx, coremsgs.MsgXSDInvalid, "top level '"+n.name.Local+"' must have a name")
+		}
+		nodes[xml.Name{Space: p.targetNS, Local: name}] = n
+	}
+
+	// Resolve everything up front, so errors anywhere in the schema are reported when it is compiled
+	for name := range p.typeNodes {
+		if _, err := p.resolveType(root, name); err != nil {
+			return nil, err
+		}
+	}
+	for name := range p.elementNodes {
+		if _, err := p.globalElement(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range p.groupNodes {
+		if _, err := p.groupParticle(n); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.elements) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgXSDInvalid, "no top level elements are declared")
+	}
+	return &Schema{targetNS: p.targetNS, elements: p.elements}, nil
+}
+
+func (p *parser) unresolved(kind string, name xml.Name) error {
+	return i18n.NewError(p.ctx, coremsgs.MsgXSDUnresolvedReference, kind, name.Local)
+}
+
+func (p *parser) invalid(n *node, reason string) error {
+	name, _ := n.attr("name")
+	if name != "" {
+		reason = n.name.Local + " '" + name + "': " + reason
+	}
+	return i18n.NewError(p.ctx, coremsgs.MsgXSDInvalid, reason)
+}
+
+// resolveType returns a built-in type, or a type declared at the top level of the schema
+func (p *parser) resolveType(n *node, name xml.Name) (*typeDef, error) {
+	if name.Space == xsdNS {
+		if name.Local == "anyType" {
+			return &typeDef{complex: &complexType{anyType: true}}, nil
+		}
+		if st := newBuiltinType(name.Local); st != nil {
+			return &typeDef{simple: st}, nil
+		}
+		return nil, p.unresolved("type", name)
+	}
+	if td, ok := p.types[name]; ok {
+		return td, nil
+	}
+	tn, ok := p.typeNodes[name]
+	if !ok {
+		return nil, p.unresolved("type", name)
+	}
+	td := &typeDef{building: true}
+	p.types[name] = td
+	var err error
+	if tn.name.Local == "simpleType" {
+		td.simple, err = p.simpleType(tn, name.Local)
+	} else {
+		td.complex, err = p.complexType(tn)
+	}
+	td.building = false
+	return td, err
+}
+
+func (p *parser) resolveSimpleType(n *node, qname string) (*simpleType, error) {
+	name, err := n.qname(p.ctx, qname)
+	if err != nil {
+		return nil, err
+	}
+	td, err := p.resolveType(n, name)
+	if err != nil {
+		return nil, err
+	}
+	if td.simple == nil {
+		if td.building {
+			return nil, p.invalid(n, "circular definition of '"+name.Local+"'")
+		}
+		return nil, p.invalid(n, "'"+name.Local+"' is not a simple type")
+	}
+	return td.simple, nil
+}
+
+func (p *parser) globalElement(name xml.Name) (*element, error) {
+	if e, ok := p.elements[name]; ok {
+		return e, nil
+	}
+	n, ok := p.elementNodes[name]
+	if !ok {
+		return nil, p.unresolved("element", name)
+	}
+	e := &element{name: name}
+	p.elements[name] = e
+	return e, p.elementDecl(n, e)
+}
+
+func (p *parser) elementDecl(n *node, e *element) error {
+	for _, unsupported := range []string{"substitutionGroup", "abstract"} {
+		if v, ok := n.attr(unsupported); ok && v != "false" {
+			return i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, unsupported)
+		}
+	}
+	nillable, _ := n.attr("nillable")
+	e.nillable = nillable == "true" || nillable == "1"
+	if fixed, ok := n.attr("fixed"); ok {
+		e.fixed = &fixed
+	}
+	if typeName, ok := n.attr("type"); ok {
+		name, err := n.qname(p.ctx, typeName)
+		if err == nil {
+			e.typ, err = p.resolveType(n, name)
+		}
+		return err
+	}
+	for _, c := range n.elements() {
+		switch c.name.Local {
+		case "complexType":
+			ct, err := p.complexType(c)
+			if err != nil {
+				return err
+			}
+			e.typ = &typeDef{complex: ct}
+			return nil
+		case "simpleType":
+			st, err := p.simpleType(c, e.name.Local)
+			if err != nil {
+				return err
+			}
+			e.typ = &typeDef{simple: st}
+			return nil
+		}
+	}
+	// An element with no type accepts any content
+	e.typ = &typeDef{complex: &complexType{anyType: true}}
+	return nil
+}
+
+func (p *parser) occurs(n *node) (min, max int, err error) {
+	min, max = 1, 1
+	if v, ok := n.attr("minOccurs"); ok {
+		if min, err = strconv.Atoi(v); err != nil || min < 0 {
+			return 0, 0, p.invalid(n, "invalid minOccurs '"+v+"'")
+		}
+	}
+	if v, ok := n.attr("maxOccurs"); ok {
+		if v == "unbounded" {
+			max = -1
+		} else if max, err = strconv.Atoi(v); err != nil || max < min {
+			return 0, 0, p.invalid(n, "invalid maxOccurs '"+v+"'")
+		}
+	}
+	return min, max, nil
+}
+
+// particle parses an element, wildcard, model group or group reference within a content model
+func (p *parser) particle(n *node) (*particle, error) {
+	min, max, err := p.occurs(n)
+	if err != nil {
+		return nil, err
+	}
+	pt := &particle{min: min, max: max}
+	switch n.name.Local {
+	case "element":
+		pt.kind = particleElement
+		pt.element, err = p.localElement(n)
+	case "any":
+		pt.kind = particleAny
+		pt.wildcard = p.wildcard(n)
+	case "sequence", "choice", "all":
+		pt.kind = map[string]particleKind{"sequence": particleSequence, "choice": particleChoice, "all": particleAll}[n.name.Local]
+		for _, c := range n.elements() {
+			child, err := p.particle(c)
+			if err != nil {
+				return nil, err
+			}
+			pt.children = append(pt.children, child)
+		}
+	case "group":
+		var ref xml.Name
+		if ref, err = p.refName(n); err != nil {
+			return nil, err
+		}
+		gn, ok := p.groupNodes[ref]
+		if !ok {
+			return nil, p.unresolved("group", ref)
+		}
+		var group *particle
+		if group, err = p.groupParticle(gn); err != nil {
+			return nil, err
+		}
+		pt.kind, pt.children = group.kind, group.children
+	default:
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, n.name.Local)
+	}
+	return pt, err
+}
+
+func (p *parser) refName(n *node) (xml.Name, error) {
+	ref, ok := n.attr("ref")
+	if !ok {
+		return xml.Name{}, p.invalid(n, "'"+n.name.Local+"' must have a ref")
+	}
+	return n.qname(p.ctx, ref)
+}
+
+// groupParticle returns the model group defined by a named group
+func (p *parser) groupParticle(gn *node) (*particle, error) {
+	if p.resolving[gn] {
+		return nil, p.invalid(gn, "circular group reference")
+	}
+	p.resolving[gn] = true
+	defer delete(p.resolving, gn)
+	children := gn.elements()
+	if len(children) != 1 {
+		return nil, p.invalid(gn, "a group must contain a single sequence, choice or all")
+	}
+	return p.particle(children[0])
+}
+
+func (p *parser) localElement(n *node) (*element, error) {
+	if _, ok := n.attr("ref"); ok {
+		ref, err := p.refName(n)
+		if err != nil {
+			return nil, err
+		}
+		return p.globalElement(ref)
+	}
+	name, _ := n.attr("name")
+	if name == "" {
+		return nil, p.invalid(n, "a local element must have a name or ref")
+	}
+	e := &element{name: xml.Name{Local: name}}
+	form, _ := n.attr("form")
+	if form == "qualified" || (form == "" && p.qualifiedElements) {
+		e.name.Space = p.targetNS
+	}
+	return e, p.elementDecl(n, e)
+}
+
+func (p *parser) wildcard(n *node) *wildcard {
+	w := &wildcard{targetNS: p.targetNS, process: "strict", namespaces: map[string]bool{}}
+	if process, ok := n.attr("processContents"); ok {
+		w.process = process
+	}
+	namespace, ok := n.attr("namespace")
+	if !ok {
+		namespace = "##any"
+	}
+	for _, ns := range strings.Fields(namespace) {
+		switch ns {
+		case "##any":
+			w.anyNamespace = true
+		case "##other":
+			w.other = true
+		case "##local":
+			w.namespaces[""] = true
+		case "##targetNamespace":
+			w.namespaces[p.targetNS] = true
+		default:
+			w.namespaces[ns] = true
+		}
+	}
+	return w
+}
+
+func (p *parser) complexType(n *node) (*complexType, error) {
+	ct := &complexType{}
+	mixed, _ := n.attr("mixed")
+	ct.mixed = mixed == "true" || mixed == "1"
+	for _, c := range n.elements() {
+		var err error
+		switch c.name.Local {
+		case "simpleContent":
+			err = p.simpleContent(c, ct)
+		case "complexContent":
+			err = p.complexContent(c, ct)
+		default:
+			err = p.contentOrAttribute(c, ct)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ct, nil
+}
+
+// contentOrAttribute adds a model group or an attribute declaration to a complex type
+func (p *parser) contentOrAttribute(n *node, ct *complexType) (err error) {
+	switch n.name.Local {
+	case "sequence", "choice", "all", "group":
+		if ct.content != nil {
+			return p.invalid(n, "a complex type can only have one model group")
+		}
+		ct.content, err = p.particle(n)
+	case "attribute", "attributeGroup", "anyAttribute":
+		ct.attributes, err = p.attributes(n, ct.attributes, &ct.anyAttribute)
+	case "key", "keyref", "unique":
+		// Identity constraints are not enforced
+	default:
+		err = i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, n.name.Local)
+	}
+	return err
+}
+
+func (p *parser) derivationBase(n *node) (*node, *typeDef, error) {
+	children := n.elements()
+	if len(children) != 1 || (children[0].name.Local != "extension" && children[0].name.Local != "restriction") {
+		return nil, nil, p.invalid(n, "'"+n.name.Local+"' must contain an extension or restriction")
+	}
+	derivation := children[0]
+	baseName, ok := derivation.attr("base")
+	if !ok {
+		return nil, nil, p.invalid(derivation, "a base type is required")
+	}
+	name, err := derivation.qname(p.ctx, baseName)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := p.resolveType(derivation, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if base.building {
+		return nil, nil, p.invalid(derivation, "circular definition of '"+name.Local+"'")
+	}
+	return derivation, base, nil
+}
+
+func (p *parser) complexContent(n *node, ct *complexType) error {
+	if mixed, ok := n.attr("mixed"); ok {
+		ct.mixed = mixed == "true" || mixed == "1"
+	}
+	derivation, base, err := p.derivationBase(n)
+	if err != nil {
+		return err
+	}
+	if base.complex == nil || base.complex.simpleContent != nil {
+		return p.invalid(derivation, "the base of complex content must be a complex type")
+	}
+	isExtension := derivation.name.Local == "extension"
+	if isExtension {
+		ct.attributes = append(ct.attributes, base.complex.attributes...)
+		ct.anyAttribute = base.complex.anyAttribute
+	} else {
+		ct.attributes = inheritAttributes(base.complex.attributes, derivation)
+	}
+	for _, c := range derivation.elements() {
+		if err := p.contentOrAttribute(c, ct); err != nil {
+			return err
+		}
+	}
+	if isExtension && base.complex.content != nil {
+		if ct.content == nil {
+			ct.content = base.complex.content
+		} else {
+			ct.content = &particle{kind: particleSequence, min: 1, max: 1, children: []*particle{base.complex.content, ct.content}}
+		}
+	}
+	return nil
+}
+
+func (p *parser) simpleContent(n *node, ct *complexType) error {
+	derivation, base, err := p.derivationBase(n)
+	if err != nil {
+		return err
+	}
+	var baseSimple *simpleType
+	switch {
+	case base.simple != nil && derivation.name.Local == "extension":
+		baseSimple = base.simple
+	case base.complex != nil && base.complex.simpleContent != nil:
+		baseSimple = base.complex.simpleContent
+		ct.anyAttribute = base.complex.anyAttribute
+		ct.attributes = inheritAttributes(base.complex.attributes, derivation)
+	default:
+		return p.invalid(derivation, "invalid base type for simple content")
+	}
+	ct.simpleContent = baseSimple
+	var restriction *simpleType
+	if derivation.name.Local == "restriction" {
+		restriction = &simpleType{name: baseSimple.name, base: baseSimple, ws: baseSimple.ws, facets: noFacets()}
+		ct.simpleContent = restriction
+	}
+	for _, c := range derivation.elements() {
+		switch c.name.Local {
+		case "attribute", "attributeGroup", "anyAttribute":
+			if ct.attributes, err = p.attributes(c, ct.attributes, &ct.anyAttribute); err != nil {
+				return err
+			}
+		case "simpleType":
+			if restriction == nil {
+				return p.invalid(c, "unexpected simpleType in an extension")
+			}
+			if restriction.base, err = p.simpleType(c, baseSimple.name); err != nil {
+				return err
+			}
+		default:
+			if restriction == nil {
+				return i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, c.name.Local)
+			}
+			if err := p.facet(c, restriction); err != nil {
+				return err
+			}
+		}
+	}
+	if restriction != nil {
+		return p.compilePattern(derivation, restriction)
+	}
+	return nil
+}
+
+// inheritAttributes returns the attributes of a base type that are not redeclared or prohibited by a restriction
+func inheritAttributes(base []*attribute, derivation *node) []*attribute {
+	redeclared := map[string]bool{}
+	for _, c := range derivation.elements() {
+		if c.name.Local == "attribute" {
+			name, _ := c.attr("name")
+			if name == "" {
+				ref, _ := c.attr("ref")
+				name = ref[strings.IndexByte(ref, ':')+1:]
+			}
+			redeclared[name] = true
+		}
+	}
+	attributes := make([]*attribute, 0, len(base))
+	for _, a := range base {
+		if !redeclared[a.name.Local] {
+			attributes = append(attributes, a)
+		}
+	}
+	return attributes
+}
+
+// attributes adds an attribute, the contents of an attribute group, or an attribute wildcard to a list of attributes
+func (p *parser) attributes(n *node, attributes []*attribute, anyAttribute **wildcard) ([]*attribute, error) {
+	switch n.name.Local {
+	case "anyAttribute":
+		*anyAttribute = p.wildcard(n)
+	case "attributeGroup":
+		ref, err := p.refName(n)
+		if err != nil {
+			return nil, err
+		}
+		gn, ok := p.attributeGroupNodes[ref]
+		if !ok {
+			return nil, p.unresolved("attributeGroup", ref)
+		}
+		if p.resolving[gn] {
+			return nil, p.invalid(gn, "circular attributeGroup reference")
+		}
+		p.resolving[gn] = true
+		defer delete(p.resolving, gn)
+		for _, c := range gn.elements() {
+			if attributes, err = p.attributes(c, attributes, anyAttribute); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		a, err := p.attribute(n)
+		if err != nil || a == nil {
+			return attributes, err
+		}
+		attributes = append(attributes, a)
+	}
+	return attributes, nil
+}
+
+// attribute parses an attribute declaration, returning nil if the use of the attribute is prohibited
+func (p *parser) attribute(n *node) (*attribute, error) {
+	use, _ := n.attr("use")
+	if use == "prohibited" {
+		return nil, nil
+	}
+	a := &attribute{required: use == "required"}
+	decl := n
+	if _, ok := n.attr("ref"); ok {
+		ref, err := p.refName(n)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Space == xmlNS {
+			// Attributes in the xml namespace, such as xml:lang, are accepted as strings
+			a.name, a.typ = ref, newBuiltinType("string")
+			return a, nil
+		}
+		if decl, ok = p.attributeNodes[ref]; !ok {
+			return nil, p.unresolved("attribute", ref)
+		}
+		a.name = ref
+	} else {
+		name, _ := n.attr("name")
+		if name == "" {
+			return nil, p.invalid(n, "an attribute must have a name or ref")
+		}
+		a.name = xml.Name{Local: name}
+		form, _ := n.attr("form")
+		if form == "qualified" || (form == "" && p.qualifiedAttributes) {
+			a.name.Space = p.targetNS
+		}
+	}
+	if fixed, ok := n.attr("fixed"); ok {
+		a.fixed = &fixed
+	} else if fixed, ok := decl.attr("fixed"); ok {
+		a.fixed = &fixed
+	}
+	var err error
+	if typeName, ok := decl.attr("type"); ok {
+		a.typ, err = p.resolveSimpleType(decl, typeName)
+		return a, err
+	}
+	for _, c := range decl.elements() {
+		if c.name.Local == "simpleType" {
+			a.typ, err = p.simpleType(c, a.name.Local)
+			return a, err
+		}
+	}
+	a.typ = newBuiltinType("anySimpleType")
+	return a, nil
+}
+
+func (p *parser) simpleType(n *node, name string) (*simpleType, error) {
+	children := n.elements()
+	if len(children) != 1 {
+		return nil, p.invalid(n, "a simple type must contain a single restriction, list or union")
+	}
+	c := children[0]
+	st := &simpleType{name: name, ws: wsCollapse, facets: noFacets()}
+	var err error
+	switch c.name.Local {
+	case "restriction":
+		if baseName, ok := c.attr("base"); ok {
+			st.base, err = p.resolveSimpleType(c, baseName)
+		} else if inline := c.elements(); len(inline) > 0 && inline[0].name.Local == "simpleType" {
+			st.base, err = p.simpleType(inline[0], name)
+		} else {
+			err = p.invalid(c, "a restriction must have a base type")
+		}
+		if err != nil {
+			return nil, err
+		}
+		st.ws = st.base.ws
+		for _, f := range c.elements() {
+			if f.name.Local != "simpleType" {
+				if err := p.facet(f, st); err != nil {
+					return nil, err
+				}
+			}
+		}
+		err = p.compilePattern(c, st)
+	case "list":
+		if itemName, ok := c.attr("itemType"); ok {
+			st.item, err = p.resolveSimpleType(c, itemName)
+		} else if inline := c.elements(); len(inline) == 1 {
+			st.item, err = p.simpleType(inline[0], name)
+		} else {
+			err = p.invalid(c, "a list must have an item type")
+		}
+	case "union":
+		if memberTypes, ok := c.attr("memberTypes"); ok {
+			for _, memberName := range strings.Fields(memberTypes) {
+				member, err := p.resolveSimpleType(c, memberName)
+				if err != nil {
+					return nil, err
+				}
+				st.members = append(st.members, member)
+			}
+		}
+		for _, inline := range c.elements() {
+			member, err := p.simpleType(inline, name)
+			if err != nil {
+				return nil, err
+			}
+			st.members = append(st.members, member)
+		}
+		if len(st.members) == 0 {
+			err = p.invalid(c, "a union must have member types")
+		}
+	default:
+		err = i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, c.name.Local)
+	}
+	return st, err
+}
+
+// facet applies a constraining facet, such as a pattern or length, to a restriction
+func (p *parser) facet(n *node, st *simpleType) error {
+	value, ok := n.attr("value")
+	if !ok {
+		return p.invalid(n, "the '"+n.name.Local+"' facet must have a value")
+	}
+	f := &st.facets
+	if f.raw == nil {
+		f.raw = map[string]string{}
+	}
+	f.raw[n.name.Local] = value
+	var err error
+	switch n.name.Local {
+	case "enumeration":
+		f.enumeration = append(f.enumeration, normalizeWhitespace(st.ws, value))
+		f.raw["enumeration"] = strings.Join(f.enumeration, ",")
+	case "pattern":
+		// Multiple patterns in the same restriction are alternatives, compiled once all the facets are parsed
+		f.patternSources = append(f.patternSources, value)
+		f.raw["pattern"] = strings.Join(f.patternSources, "|")
+	case "length":
+		f.length, err = p.facetInt(n, value)
+	case "minLength":
+		f.minLength, err = p.facetInt(n, value)
+	case "maxLength":
+		f.maxLength, err = p.facetInt(n, value)
+	case "totalDigits":
+		f.totalDigits, err = p.facetInt(n, value)
+	case "fractionDigits":
+		f.fractionDigits, err = p.facetInt(n, value)
+	case "minInclusive":
+		f.minInclusive, err = p.facetBound(n, st, value)
+	case "maxInclusive":
+		f.maxInclusive, err = p.facetBound(n, st, value)
+	case "minExclusive":
+		f.minExclusive, err = p.facetBound(n, st, value)
+	case "maxExclusive":
+		f.maxExclusive, err = p.facetBound(n, st, value)
+	case "whiteSpace":
+		ws, ok := map[string]whitespace{"preserve": wsPreserve, "replace": wsReplace, "collapse": wsCollapse}[value]
+		if !ok {
+			return p.invalidFacet(n, value)
+		}
+		st.ws = ws
+	default:
+		err = i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, n.name.Local)
+	}
+	return err
+}
+
+func (p *parser) invalidFacet(n *node, value string) error {
+	return p.invalid(n, "invalid "+n.name.Local+" value '"+value+"'")
+}
+
+func (p *parser) facetInt(n *node, value string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		return -1, p.invalidFacet(n, value)
+	}
+	return i, nil
+}
+
+func (p *parser) facetBound(n *node, st *simpleType, value string) (*big.Rat, error) {
+	if class := st.class(); class != classDecimal && class != classFloat {
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, n.name.Local+" on '"+st.name+"'")
+	}
+	if !reFloat.MatchString(value) {
+		return nil, p.invalidFacet(n, value)
+	}
+	bound, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgXSDUnsupported, n.name.Local+" '"+value+"'")
+	}
+	return bound, nil
+}
+
+// compilePattern compiles the pattern facets of a restriction, once all of its facets have been parsed
+func (p *parser) compilePattern(n *node, st *simpleType) (err error) {
+	if sources := st.facets.patternSources; len(sources) > 0 {
+		st.facets.pattern, err = regexp.Compile(translatePattern(strings.Join(sources, "|")))
+		if err != nil {
+			return p.invalid(n, "invalid pattern '"+st.facets.raw["pattern"]+"': "+err.Error())
+		}
+	}
+	return nil
+}
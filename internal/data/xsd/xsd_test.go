@@ -0,0 +1,353 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xsd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A cut down ISO 20022 style schema, exercising the common structures
+const testPaymentSchema = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+	xmlns="urn:example:pain.001"
+	targetNamespace="urn:example:pain.001"
+	elementFormDefault="qualified">
+	<xs:annotation><xs:documentation>Payment initiation</xs:documentation></xs:annotation>
+	<xs:element name="Document" type="Document"/>
+	<xs:complexType name="Document">
+		<xs:sequence>
+			<xs:element name="GrpHdr" type="GroupHeader"/>
+			<xs:element name="PmtInf" type="PaymentInstruction" maxOccurs="unbounded"/>
+			<xs:element name="SplmtryData" type="SupplementaryData" minOccurs="0"/>
+		</xs:sequence>
+		<xs:attribute name="version" type="xs:string" fixed="1.0"/>
+	</xs:complexType>
+	<xs:complexType name="GroupHeader">
+		<xs:sequence>
+			<xs:element name="MsgId" type="Max35Text"/>
+			<xs:element name="CreDtTm" type="xs:dateTime"/>
+			<xs:element name="NbOfTxs" type="Max15NumericText"/>
+			<xs:group ref="Parties"/>
+		</xs:sequence>
+	</xs:complexType>
+	<xs:group name="Parties">
+		<xs:choice>
+			<xs:element name="InitgPty" type="xs:string"/>
+			<xs:element name="FwdgAgt" type="xs:string"/>
+		</xs:choice>
+	</xs:group>
+	<xs:complexType name="PaymentInstruction">
+		<xs:sequence>
+			<xs:element name="PmtInfId" type="Max35Text"/>
+			<xs:element name="PmtMtd" type="PaymentMethodCode"/>
+			<xs:element name="ReqdExctnDt" type="xs:date"/>
+			<xs:element name="Amt" type="ActiveCurrencyAndAmount"/>
+			<xs:element name="Tags" type="TagList" minOccurs="0"/>
+			<xs:element name="Prty" type="PriorityOrNumber" minOccurs="0"/>
+			<xs:element name="Rmt" nillable="true" minOccurs="0">
+				<xs:complexType>
+					<xs:all>
+						<xs:element name="Ustrd" type="xs:string"/>
+						<xs:element name="Ref" type="xs:string" minOccurs="0"/>
+					</xs:all>
+				</xs:complexType>
+			</xs:element>
+		</xs:sequence>
+		<xs:attributeGroup ref="Audit"/>
+	</xs:complexType>
+	<xs:attributeGroup name="Audit">
+		<xs:attribute name="seq" type="xs:positiveInteger" use="required"/>
+		<xs:attribute ref="origin"/>
+	</xs:attributeGroup>
+	<xs:attribute name="origin" type="xs:NCName"/>
+	<xs:complexType name="SupplementaryData">
+		<xs:sequence>
+			<xs:any namespace="##other" processContents="lax" maxOccurs="unbounded"/>
+		</xs:sequence>
+		<xs:anyAttribute namespace="##any"/>
+	</xs:complexType>
+	<xs:simpleType name="Max35Text">
+		<xs:restriction base="xs:string">
+			<xs:minLength value="1"/>
+			<xs:maxLength value="35"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:simpleType name="Max15NumericText">
+		<xs:restriction base="xs:string">
+			<xs:pattern value="[0-9]{1,15}"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:simpleType name="PaymentMethodCode">
+		<xs:restriction base="xs:string">
+			<xs:enumeration value="CHK"/>
+			<xs:enumeration value="TRF"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:simpleType name="ActiveCurrencyAndAmount_SimpleType">
+		<xs:restriction base="xs:decimal">
+			<xs:fractionDigits value="5"/>
+			<xs:totalDigits value="18"/>
+			<xs:minInclusive value="0"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:complexType name="ActiveCurrencyAndAmount">
+		<xs:simpleContent>
+			<xs:extension base="ActiveCurrencyAndAmount_SimpleType">
+				<xs:attribute name="Ccy" type="ActiveCurrencyCode" use="required"/>
+			</xs:extension>
+		</xs:simpleContent>
+	</xs:complexType>
+	<xs:simpleType name="ActiveCurrencyCode">
+		<xs:restriction base="xs:string">
+			<xs:pattern value="[A-Z]{3,3}"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:simpleType name="TagList">
+		<xs:list itemType="xs:NCName"/>
+	</xs:simpleType>
+	<xs:simpleType name="PriorityOrNumber">
+		<xs:union memberTypes="xs:int">
+			<xs:simpleType>
+				<xs:restriction base="xs:token">
+					<xs:enumeration value="HIGH"/>
+					<xs:enumeration value="NORM"/>
+				</xs:restriction>
+			</xs:simpleType>
+		</xs:union>
+	</xs:simpleType>
+</xs:schema>`
+
+const testPaymentDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:example:pain.001" xmlns:p="urn:example:pain.001" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" version="1.0">
+	<GrpHdr>
+		<MsgId>MSG-0001</MsgId>
+		<CreDtTm>2023-02-28T10:15:30.123+01:00</CreDtTm>
+		<NbOfTxs>2</NbOfTxs>
+		<InitgPty>Org A</InitgPty>
+	</GrpHdr>
+	<PmtInf seq="1" p:origin="api">
+		<PmtInfId>PMT-1</PmtInfId>
+		<PmtMtd>TRF</PmtMtd>
+		<ReqdExctnDt>2024-02-29</ReqdExctnDt>
+		<Amt Ccy="EUR">1000.50</Amt>
+		<Tags> urgent  internal </Tags>
+		<Prty>HIGH</Prty>
+		<Rmt><Ref>R1</Ref><Ustrd>Invoice 123</Ustrd></Rmt>
+	</PmtInf>
+	<PmtInf seq="2">
+		<PmtInfId>PMT-2</PmtInfId>
+		<PmtMtd>CHK</PmtMtd>
+		<ReqdExctnDt>2024-03-01</ReqdExctnDt>
+		<Amt Ccy="USD">5</Amt>
+		<Prty>-3</Prty>
+		<Rmt xsi:nil="true"/>
+	</PmtInf>
+	<SplmtryData xmlns:ext="urn:example:ext" ext:any="attr">
+		<ext:Envlp><ext:Anything/></ext:Envlp>
+	</SplmtryData>
+</Document>`
+
+func testSchema(t *testing.T, schema string) *Schema {
+	s, err := Parse(context.Background(), strings.NewReader(schema))
+	assert.NoError(t, err)
+	return s
+}
+
+func TestParsePaymentSchema(t *testing.T) {
+	s := testSchema(t, testPaymentSchema)
+	assert.Equal(t, "urn:example:pain.001", s.targetNS)
+	assert.Len(t, s.elements, 1)
+}
+
+func TestParseRecursiveType(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+		<xs:element name="node" type="Node"/>
+		<xs:complexType name="Node">
+			<xs:sequence>
+				<xs:element name="node" type="Node" minOccurs="0" maxOccurs="unbounded"/>
+			</xs:sequence>
+		</xs:complexType>
+	</xs:schema>`)
+	err := s.Validate(context.Background(), strings.NewReader(`<node><node><node/></node><node/></node>`))
+	assert.NoError(t, err)
+}
+
+func TestParseDefaultNamespaceIsXMLSchema(t *testing.T) {
+	s := testSchema(t, `<schema xmlns="http://www.w3.org/2001/XMLSchema">
+		<element name="count" type="int"/>
+	</schema>`)
+	assert.NoError(t, s.Validate(context.Background(), strings.NewReader(`<count>12</count>`)))
+}
+
+func TestParseErrors(t *testing.T) {
+	const xs = `xmlns:xs="http://www.w3.org/2001/XMLSchema"`
+	for name, test := range map[string]struct {
+		schema string
+		err    string
+	}{
+		"badXML":                      {`<xs:schema`, "FF10512"},
+		"notSchema":                   {`<schema/>`, "FF10509"},
+		"noElements":                  {`<xs:schema ` + xs + `/>`, "FF10509.*no top level"},
+		"import":                      {`<xs:schema ` + xs + `><xs:import namespace="urn:a"/></xs:schema>`, "FF10510.*import"},
+		"noName":                      {`<xs:schema ` + xs + `><xs:element type="xs:string"/></xs:schema>`, "FF10509"},
+		"unknownType":                 {`<xs:schema ` + xs + `><xs:element name="a" type="Missing"/></xs:schema>`, "FF10511.*type.*Missing"},
+		"unknownBuiltin":              {`<xs:schema ` + xs + `><xs:element name="a" type="xs:nope"/></xs:schema>`, "FF10511.*type.*nope"},
+		"unknownPrefix":               {`<xs:schema ` + xs + `><xs:element name="a" type="p:x"/></xs:schema>`, "FF10509.*prefix"},
+		"unknownRef":                  {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence><xs:element ref="b"/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "FF10511.*element.*b"},
+		"noRef":                       {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:group/></xs:complexType></xs:element></xs:schema>`, "FF10509.*ref"},
+		"unknownGroup":                {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:group ref="g"/></xs:complexType></xs:element></xs:schema>`, "FF10511.*group"},
+		"emptyGroup":                  {`<xs:schema ` + xs + `><xs:group name="g"/><xs:element name="a"/></xs:schema>`, "FF10509.*single"},
+		"circularGroup":               {`<xs:schema ` + xs + `><xs:group name="g"><xs:sequence><xs:group ref="g"/></xs:sequence></xs:group><xs:element name="a"/></xs:schema>`, "FF10509.*circular"},
+		"twoGroups":                   {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence/><xs:choice/></xs:complexType></xs:element></xs:schema>`, "FF10509.*one model group"},
+		"localNoName":                 {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence><xs:element/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "FF10509.*name or ref"},
+		"badMinOccurs":                {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence minOccurs="x"/></xs:complexType></xs:element></xs:schema>`, "FF10509.*minOccurs"},
+		"badMaxOccurs":                {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence minOccurs="2" maxOccurs="1"/></xs:complexType></xs:element></xs:schema>`, "FF10509.*maxOccurs"},
+		"badParticle":                 {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence><xs:attribute name="x"/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "FF10510.*attribute"},
+		"badParticleChild":            {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence><xs:element name="b" type="c"/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "FF10511"},
+		"substitution":                {`<xs:schema ` + xs + `><xs:element name="a" substitutionGroup="b"/></xs:schema>`, "FF10510.*substitutionGroup"},
+		"badComplexChild":             {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:element name="b"/></xs:complexType></xs:element></xs:schema>`, "FF10510.*element"},
+		"badInlineComplex":            {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:element name="b"/></xs:complexType></xs:element></xs:schema>`, "FF10510"},
+		"badInlineSimple":             {`<xs:schema ` + xs + `><xs:element name="a"><xs:simpleType/></xs:element></xs:schema>`, "FF10509.*single"},
+		"badDerivation":               {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*extension or restriction"},
+		"noBase":                      {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension/></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*base type"},
+		"baseBadPrefix":               {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension base="p:q"/></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*prefix"},
+		"baseUnknown":                 {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension base="q"/></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10511"},
+		"baseCircular":                {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension base="t"/></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*circular"},
+		"complexBaseSimple":           {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension base="xs:string"/></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*complex type"},
+		"complexExtBad":               {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:extension base="xs:anyType"><xs:element name="x"/></xs:extension></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10510"},
+		"simpleContentBase":           {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:simpleContent><xs:restriction base="xs:string"/></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*simple content"},
+		"simpleContentBad":            {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:simpleContent/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509"},
+		"simpleExtFacet":              {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:simpleContent><xs:extension base="xs:string"><xs:length value="1"/></xs:extension></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10510.*length"},
+		"simpleExtType":               {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:simpleContent><xs:extension base="xs:string"><xs:simpleType/></xs:extension></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*unexpected simpleType"},
+		"simpleExtAttr":               {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:simpleContent><xs:extension base="xs:string"><xs:attribute/></xs:extension></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*name or ref"},
+		"attrGroupNoRef":              {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:attributeGroup/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*ref"},
+		"attrGroupUnknown":            {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:attributeGroup ref="g"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10511.*attributeGroup"},
+		"attrGroupCircular":           {`<xs:schema ` + xs + `><xs:attributeGroup name="g"><xs:attributeGroup ref="g"/></xs:attributeGroup><xs:complexType name="t"><xs:attributeGroup ref="g"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*circular"},
+		"attrRefBadPrefix":            {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:attribute ref="p:q"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*prefix"},
+		"attrRefUnknown":              {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:attribute ref="q"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10511.*attribute"},
+		"attrBadType":                 {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:attribute name="b" type="t"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*circular"},
+		"attrComplexType":             {`<xs:schema ` + xs + `><xs:complexType name="c"/><xs:complexType name="t"><xs:attribute name="b" type="c"/></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*not a simple type"},
+		"restrictionNoBase":           {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*base type"},
+		"restrictionBadBase":          {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="x"/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10511"},
+		"restrictionInline":           {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction><xs:simpleType/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*single"},
+		"restrictionFacet":            {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:string"><xs:bogus value="1"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10510.*bogus"},
+		"listNoItem":                  {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:list/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*item type"},
+		"unionNoMembers":              {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:union/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*member types"},
+		"unionBadMember":              {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:union memberTypes="x"/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10511"},
+		"unionBadInline":              {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:union><xs:simpleType/></xs:union></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*single"},
+		"simpleBadChild":              {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:element/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10510.*element"},
+		"facetNoValue":                {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:string"><xs:length/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*must have a value"},
+		"facetBadInt":                 {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:string"><xs:maxLength value="-1"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*invalid maxLength"},
+		"facetBadBound":               {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:int"><xs:maxInclusive value="x"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*invalid maxInclusive"},
+		"facetInfBound":               {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:double"><xs:maxInclusive value="INF"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10510.*maxInclusive"},
+		"facetDateBound":              {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:date"><xs:minInclusive value="2020-01-01"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10510.*minInclusive on 's'"},
+		"facetBadWS":                  {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:string"><xs:whiteSpace value="x"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*whiteSpace"},
+		"facetBadPattern":             {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:restriction base="xs:string"><xs:pattern value="[a-z"/></xs:restriction></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*invalid pattern"},
+		"simpleRestrictionBadPattern": {`<xs:schema ` + xs + `><xs:complexType name="b"><xs:simpleContent><xs:extension base="xs:string"/></xs:simpleContent></xs:complexType><xs:complexType name="t"><xs:simpleContent><xs:restriction base="b"><xs:pattern value="("/></xs:restriction></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*invalid pattern"},
+		"simpleRestrictionBadType":    {`<xs:schema ` + xs + `><xs:complexType name="b"><xs:simpleContent><xs:extension base="xs:string"/></xs:simpleContent></xs:complexType><xs:complexType name="t"><xs:simpleContent><xs:restriction base="b"><xs:simpleType/></xs:restriction></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*single"},
+		"simpleRestrictionBadFacet":   {`<xs:schema ` + xs + `><xs:complexType name="b"><xs:simpleContent><xs:extension base="xs:string"/></xs:simpleContent></xs:complexType><xs:complexType name="t"><xs:simpleContent><xs:restriction base="b"><xs:length value="x"/></xs:restriction></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10509.*invalid length"},
+		"simpleRestrictionBadAttr":    {`<xs:schema ` + xs + `><xs:complexType name="b"><xs:simpleContent><xs:extension base="xs:string"/></xs:simpleContent></xs:complexType><xs:complexType name="t"><xs:simpleContent><xs:restriction base="b"><xs:attribute ref="x"/></xs:restriction></xs:simpleContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10511"},
+		"complexContentBadChild":      {`<xs:schema ` + xs + `><xs:complexType name="t"><xs:complexContent><xs:restriction base="xs:anyType"><xs:sequence><xs:bogus/></xs:sequence></xs:restriction></xs:complexContent></xs:complexType><xs:element name="a"/></xs:schema>`, "FF10510.*bogus"},
+		"badGroupInSchema":            {`<xs:schema ` + xs + `><xs:group name="g"><xs:sequence><xs:element ref="x"/></xs:sequence></xs:group><xs:element name="a"/></xs:schema>`, "FF10511"},
+		"badGroupInRef":               {`<xs:schema ` + xs + `><xs:group name="g"><xs:bogus/></xs:group><xs:element name="a"><xs:complexType><xs:group ref="g"/></xs:complexType></xs:element></xs:schema>`, "FF10510"},
+		"badGroupRefPrefix":           {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:group ref="p:g"/></xs:complexType></xs:element></xs:schema>`, "FF10509.*prefix"},
+		"badElementRefPrefix":         {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:sequence><xs:element ref="p:b"/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "FF10509.*prefix"},
+		"badAttrGroupRefPrefix":       {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:attributeGroup ref="p:b"/></xs:complexType></xs:element></xs:schema>`, "FF10509.*prefix"},
+		"badAttrGroupContent":         {`<xs:schema ` + xs + `><xs:attributeGroup name="g"><xs:attribute/></xs:attributeGroup><xs:element name="a"><xs:complexType><xs:attributeGroup ref="g"/></xs:complexType></xs:element></xs:schema>`, "FF10509.*name or ref"},
+		"badAttrInlineType":           {`<xs:schema ` + xs + `><xs:element name="a"><xs:complexType><xs:attribute name="b"><xs:simpleType/></xs:attribute></xs:complexType></xs:element></xs:schema>`, "FF10509.*single"},
+		"badListItem":                 {`<xs:schema ` + xs + `><xs:simpleType name="s"><xs:list itemType="p:x"/></xs:simpleType><xs:element name="a"/></xs:schema>`, "FF10509.*prefix"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(context.Background(), strings.NewReader(test.schema))
+			assert.Regexp(t, test.err, err)
+		})
+	}
+}
+
+func TestParseMiscellaneousConstructs(t *testing.T) {
+	s := testSchema(t, `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+		<xs:notation name="gif" public="image/gif"/>
+		<xs:attribute name="unit" type="xs:token" fixed="kg"/>
+		<xs:element name="item" type="xs:string" nillable="true"/>
+		<xs:element name="doc">
+			<xs:complexType>
+				<xs:complexContent mixed="true">
+					<xs:restriction base="xs:anyType">
+						<xs:sequence>
+							<xs:element ref="item"/>
+							<xs:element ref="item" minOccurs="0"/>
+							<xs:element name="code">
+								<xs:simpleType>
+									<xs:restriction base="xs:string">
+										<xs:whiteSpace value="collapse"/>
+										<xs:enumeration value="A B"/>
+									</xs:restriction>
+								</xs:simpleType>
+							</xs:element>
+							<xs:element name="ratio">
+								<xs:simpleType>
+									<xs:restriction base="xs:decimal">
+										<xs:minExclusive value="0"/>
+										<xs:maxExclusive value="1"/>
+									</xs:restriction>
+								</xs:simpleType>
+							</xs:element>
+							<xs:element name="codes">
+								<xs:simpleType>
+									<xs:list>
+										<xs:simpleType>
+											<xs:restriction base="xs:int"/>
+										</xs:simpleType>
+									</xs:list>
+								</xs:simpleType>
+							</xs:element>
+						</xs:sequence>
+						<xs:attribute ref="unit"/>
+						<xs:attribute name="note"/>
+					</xs:restriction>
+				</xs:complexContent>
+			</xs:complexType>
+			<xs:key name="itemKey">
+				<xs:selector xpath="item"/>
+				<xs:field xpath="."/>
+			</xs:key>
+		</xs:element>
+	</xs:schema>`)
+	ctx := context.Background()
+	valid := `<doc xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" unit="kg" note="anything">
+		mixed <item xsi:nil="true"/> text <item>x</item>
+		<code>  A
+		B </code>
+		<ratio>0.5</ratio>
+		<codes>1 2 3</codes>
+	</doc>`
+	assert.NoError(t, s.Validate(ctx, strings.NewReader(valid)))
+	for from, to := range map[string]string{
+		`unit="kg"`: `unit="lb"`,
+		`0.5`:       `1`,
+		`1 2 3`:     `1 two`,
+		`A`:         `C`,
+		`<item>x`:   `<item xsi:nil="true">x`,
+	} {
+		doc := strings.Replace(valid, from, to, 1)
+		assert.Error(t, s.Validate(ctx, strings.NewReader(doc)), to)
+	}
+}
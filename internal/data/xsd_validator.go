@@ -0,0 +1,106 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data/xsd"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// xsdValidator validates XML documents against an XML schema, carried as a JSON string in
+// the value of the datatype. The XML document is carried as a JSON string in the value of
+// the data, or as the content of a blob.
+type xsdValidator struct {
+	id       *fftypes.UUID
+	size     int64
+	ns       string
+	datatype *core.DatatypeRef
+	schema   *xsd.Schema
+}
+
+func newXSDValidator(ctx context.Context, ns string, datatype *core.Datatype) (*xsdValidator, error) {
+	xv := &xsdValidator{
+		id: datatype.ID,
+		ns: ns,
+		datatype: &core.DatatypeRef{
+			Name:    datatype.Name,
+			Version: datatype.Version,
+		},
+	}
+
+	var schemaText string
+	if datatype.Value == nil || json.Unmarshal(datatype.Value.Bytes(), &schemaText) != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgXSDNotString, xv.datatype)
+	}
+	schema, err := xsd.Parse(ctx, strings.NewReader(schemaText))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSchemaLoadFailed, xv.datatype)
+	}
+	xv.schema = schema
+	xv.size = int64(len(*datatype.Value))
+
+	log.L(ctx).Debugf("Found XML schema validator for xsd:%s:%s: %v", xv.ns, datatype, xv.id)
+	return xv, nil
+}
+
+func (xv *xsdValidator) Validate(ctx context.Context, data *core.Data) error {
+	return xv.ValidateValue(ctx, data.Value, data.Hash)
+}
+
+func (xv *xsdValidator) ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error {
+	if value == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDataValueIsNull)
+	}
+
+	if expectedHash != nil {
+		hash := value.Hash()
+		if *hash != *expectedHash {
+			return i18n.NewError(ctx, coremsgs.MsgDataInvalidHash, hash, expectedHash)
+		}
+	}
+
+	var xmlText string
+	if err := json.Unmarshal(value.Bytes(), &xmlText); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgXMLDataNotString, xv.datatype)
+	}
+	return xv.validateXML(ctx, strings.NewReader(xmlText))
+}
+
+func (xv *xsdValidator) ValidateBlob(ctx context.Context, reader io.Reader) error {
+	return xv.validateXML(ctx, reader)
+}
+
+func (xv *xsdValidator) validateXML(ctx context.Context, reader io.Reader) error {
+	if err := xv.schema.Validate(ctx, reader); err != nil {
+		log.L(ctx).Warnf("XML schema %s [%v] validation failed: %s", xv.datatype, xv.id, err)
+		return i18n.NewError(ctx, coremsgs.MsgXMLDataInvalidPerSchema, xv.datatype, err)
+	}
+	return nil
+}
+
+func (xv *xsdValidator) Size() int64 {
+	return xv.size
+}
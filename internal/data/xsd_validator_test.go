@@ -0,0 +1,110 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+const testXSD = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+	<xs:element name="order">
+		<xs:complexType>
+			<xs:sequence>
+				<xs:element name="sku" type="xs:string"/>
+				<xs:element name="quantity" type="xs:positiveInteger"/>
+			</xs:sequence>
+		</xs:complexType>
+	</xs:element>
+</xs:schema>`
+
+func jsonString(s string) *fftypes.JSONAny {
+	b, _ := json.Marshal(s)
+	return fftypes.JSONAnyPtrBytes(b)
+}
+
+func testXSDDatatype() *core.Datatype {
+	return &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeXSD,
+		Name:      "order",
+		Version:   "0.0.1",
+		Value:     jsonString(testXSD),
+	}
+}
+
+func TestXSDValidator(t *testing.T) {
+
+	dt := testXSDDatatype()
+	xv, err := newXSDValidator(context.Background(), "ns1", dt)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(*dt.Value)), xv.Size())
+
+	value := jsonString(`<order><sku>ABC-1</sku><quantity>3</quantity></order>`)
+	err = xv.ValidateValue(context.Background(), value, value.Hash())
+	assert.NoError(t, err)
+
+	err = xv.Validate(context.Background(), &core.Data{Value: value, Hash: value.Hash()})
+	assert.NoError(t, err)
+
+	err = xv.ValidateValue(context.Background(), jsonString(`<order><sku>ABC-1</sku><quantity>0</quantity></order>`), nil)
+	assert.Regexp(t, "FF10520.*order.*FF10518.*quantity", err)
+
+	err = xv.ValidateValue(context.Background(), fftypes.JSONAnyPtr(`{"sku": "ABC-1"}`), nil)
+	assert.Regexp(t, "FF10521", err)
+
+	err = xv.ValidateValue(context.Background(), value, fftypes.NewRandB32())
+	assert.Regexp(t, "FF10201", err)
+
+	err = xv.ValidateValue(context.Background(), nil, nil)
+	assert.Regexp(t, "FF10199", err)
+
+	err = xv.ValidateBlob(context.Background(), strings.NewReader(`<order><sku>ABC-1</sku><quantity>3</quantity></order>`))
+	assert.NoError(t, err)
+
+	err = xv.ValidateBlob(context.Background(), strings.NewReader(`<order><quantity>3</quantity></order>`))
+	assert.Regexp(t, "FF10520.*FF10513.*quantity", err)
+
+}
+
+func TestXSDValidatorNotString(t *testing.T) {
+
+	dt := testXSDDatatype()
+	dt.Value = fftypes.JSONAnyPtr(`{"schema": "<xs:schema/>"}`)
+	_, err := newXSDValidator(context.Background(), "ns1", dt)
+	assert.Regexp(t, "FF10522.*order", err)
+
+	dt.Value = nil
+	_, err = newXSDValidator(context.Background(), "ns1", dt)
+	assert.Regexp(t, "FF10522.*order", err)
+
+}
+
+func TestXSDValidatorBadSchema(t *testing.T) {
+
+	dt := testXSDDatatype()
+	dt.Value = jsonString(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:include schemaLocation="other.xsd"/></xs:schema>`)
+	_, err := newXSDValidator(context.Background(), "ns1", dt)
+	assert.Regexp(t, "FF10196.*FF10510.*include", err)
+
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"database/sql"
 
@@ -89,6 +90,18 @@ func (psql *Postgres) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, re
 	return insert.Suffix(suffix), true
 }
 
+// JSONExtract uses the JSONB path operator, which returns the value at the path as text
+func (psql *Postgres) JSONExtract(column string, path []string) string {
+	return fmt.Sprintf("(%s::jsonb #>> '{%s}')", column, strings.Join(path, ","))
+}
+
+// JSONExtractNumber casts the value at the path to numeric, only when it is a JSON number
+func (psql *Postgres) JSONExtractNumber(column string, path []string) string {
+	jsonPath := strings.Join(path, ",")
+	return fmt.Sprintf("(CASE WHEN jsonb_typeof(%s::jsonb #> '{%s}') = 'number' THEN (%s::jsonb #>> '{%s}')::numeric END)",
+		column, jsonPath, column, jsonPath)
+}
+
 func (psql *Postgres) Open(url string) (*sql.DB, error) {
 	return sql.Open(psql.Name(), url)
 }
@@ -51,3 +51,15 @@ func TestPostgresProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  ON CONFLICT DO NOTHING RETURNING seq", sql)
 	assert.True(t, query)
 }
+
+func TestPostgresJSONExtract(t *testing.T) {
+	psql := &Postgres{}
+	assert.Equal(t, "(value::jsonb #>> '{customer,id}')", psql.JSONExtract("value", []string{"customer", "id"}))
+}
+
+func TestPostgresJSONExtractNumber(t *testing.T) {
+	psql := &Postgres{}
+	assert.Equal(t,
+		"(CASE WHEN jsonb_typeof(value::jsonb #> '{order,total}') = 'number' THEN (value::jsonb #>> '{order,total}')::numeric END)",
+		psql.JSONExtractNumber("value", []string{"order", "total"}))
+}
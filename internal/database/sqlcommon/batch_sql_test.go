@@ -119,6 +119,17 @@ func TestBatch2EWithDB(t *testing.T) {
 	assert.Equal(t, 1, len(batches))
 	assert.Equal(t, int64(1), *res.TotalCount)
 
+	// Delete
+	err = s.DeleteBatch(ctx, "ns1", batchID)
+	assert.NoError(t, err)
+	batchRead, err = s.GetBatchByID(ctx, "ns1", batchID)
+	assert.NoError(t, err)
+	assert.Nil(t, batchRead)
+
+	// Delete again
+	err = s.DeleteBatch(ctx, "ns1", batchID)
+	assert.Equal(t, fftypes.DeleteRecordNotFound, err)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -231,3 +242,21 @@ func TestBatchUpdateFail(t *testing.T) {
 	err := s.UpdateBatch(context.Background(), "ns1", fftypes.NewUUID(), u)
 	assert.Regexp(t, "FF00178", err)
 }
+
+func TestDeleteBatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBatch(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBatch(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
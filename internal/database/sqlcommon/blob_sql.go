@@ -37,6 +37,7 @@ var (
 		"peer",
 		"size",
 		"data_id",
+		"encryption",
 	}
 	blobFilterFieldMap = map[string]string{
 		"payloadref": "payload_ref",
@@ -69,6 +70,7 @@ func (s *SQLCommon) setBlobInsertValues(query sq.InsertBuilder, blob *core.Blob)
 		blob.Peer,
 		blob.Size,
 		blob.DataID,
+		blob.Encryption,
 	)
 }
 
@@ -125,6 +127,7 @@ func (s *SQLCommon) blobResult(ctx context.Context, row *sql.Rows) (*core.Blob,
 		&blob.Peer,
 		&blob.Size,
 		&blob.DataID,
+		&blob.Encryption,
 		&blob.Sequence,
 	)
 	if err != nil {
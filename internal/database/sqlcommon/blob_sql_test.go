@@ -48,6 +48,11 @@ func TestBlobsE2EWithDB(t *testing.T) {
 		Peer:       "peer1",
 		Created:    fftypes.Now(),
 		DataID:     dataID,
+		Encryption: &core.BlobEncryption{
+			Algorithm: "aes-256-gcm-stream",
+			KeyID:     "kek1",
+			DataKey:   []byte("wrapped"),
+		},
 	}
 	err := s.InsertBlob(ctx, blob)
 	assert.NoError(t, err)
@@ -0,0 +1,216 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	blobUploadColumns = []string{
+		"id",
+		"namespace",
+		"state",
+		"size",
+		"received",
+		"data_id",
+		"error",
+		"chunks",
+		"hash_state",
+		"finalize",
+		"created",
+		"updated",
+		"expires",
+	}
+	blobUploadFilterFieldMap = map[string]string{
+		"data":      "data_id",
+		"hashstate": "hash_state",
+	}
+)
+
+const blobUploadsTable = "blobuploads"
+
+func (s *SQLCommon) InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, blobUploadsTable, tx,
+		sq.Insert(blobUploadsTable).
+			Columns(blobUploadColumns...).
+			Values(
+				upload.ID,
+				upload.Namespace,
+				upload.State,
+				upload.Size,
+				upload.Received,
+				upload.Data,
+				upload.Error,
+				upload.Chunks,
+				upload.HashState,
+				finalizeJSON(upload.Finalize),
+				upload.Created,
+				upload.Updated,
+				upload.Expires,
+			),
+		nil, // no change events for blob uploads
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(blobUploadsTable), update, blobUploadFilterFieldMap)
+	if err != nil {
+		return false, err
+	}
+
+	if filter != nil {
+		query, err = s.FilterUpdate(ctx, query, filter, blobUploadFilterFieldMap)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	ra, err := s.UpdateTx(ctx, blobUploadsTable, tx, query, nil /* no change events for blob uploads */)
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) blobUploadResult(ctx context.Context, row *sql.Rows) (*core.BlobUpload, error) {
+	var upload core.BlobUpload
+	var finalize fftypes.JSONAny
+	err := row.Scan(
+		&upload.ID,
+		&upload.Namespace,
+		&upload.State,
+		&upload.Size,
+		&upload.Received,
+		&upload.Data,
+		&upload.Error,
+		&upload.Chunks,
+		&upload.HashState,
+		&finalize,
+		&upload.Created,
+		&upload.Updated,
+		&upload.Expires,
+	)
+	if err == nil && !finalize.IsNil() {
+		err = finalize.Unmarshal(ctx, &upload.Finalize)
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, blobUploadsTable)
+	}
+	return &upload, nil
+}
+
+// finalizeJSON stores the finalize request of an upload as JSON, as the request has a Value field
+// of its own and so cannot implement driver.Valuer
+func finalizeJSON(finalize *core.BlobUploadFinalize) *fftypes.JSONAny {
+	if finalize == nil {
+		return nil
+	}
+	b, _ := json.Marshal(finalize)
+	return fftypes.JSONAnyPtrBytes(b)
+}
+
+func (s *SQLCommon) GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BlobUpload, error) {
+	rows, _, err := s.Query(ctx, blobUploadsTable,
+		sq.Select(blobUploadColumns...).
+			From(blobUploadsTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Blob upload '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.blobUploadResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(blobUploadColumns...).From(blobUploadsTable),
+		filter, blobUploadFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, blobUploadsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	uploads := []*core.BlobUpload{}
+	for rows.Next() {
+		upload, err := s.blobUploadResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, s.QueryRes(ctx, blobUploadsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, blobUploadsTable, tx, sq.Delete(blobUploadsTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        id,
+	}), nil /* no change events for blob uploads */)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,286 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobUploadsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new upload session
+	upload := &core.BlobUpload{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     core.BlobUploadStateReceiving,
+		Size:      100,
+		Created:   fftypes.Now(),
+		Updated:   fftypes.Now(),
+		Expires:   fftypes.Now(),
+		HashState: "state0",
+	}
+	err := s.InsertBlobUpload(ctx, upload)
+	assert.NoError(t, err)
+	uploadJson, _ := json.Marshal(&upload)
+
+	// Query back the upload (by ID)
+	uploadRead, err := s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	uploadReadJson, _ := json.Marshal(uploadRead)
+	assert.Equal(t, string(uploadJson), string(uploadReadJson))
+	assert.Equal(t, "state0", uploadRead.HashState)
+
+	// Record a chunk, guarded on the received count
+	chunks := core.BlobUploadChunks{{Offset: 0, Size: 10, PayloadRef: "ns1/chunk1"}}
+	chunksJSON, _ := json.Marshal(chunks)
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	up := database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("received", int64(10)).
+		Set("chunks", fftypes.JSONAnyPtrBytes(chunksJSON)).
+		Set("hashstate", "state1")
+	updated, err := s.UpdateBlobUpload(ctx, "ns1", upload.ID, fb.Eq("received", int64(0)), up)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	// A second update with the stale count is rejected
+	updated, err = s.UpdateBlobUpload(ctx, "ns1", upload.ID, fb.Eq("received", int64(0)), up)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// Query back the upload (by query filter)
+	uploads, res, err := s.GetBlobUploads(ctx, "ns1", fb.And(
+		fb.Eq("received", int64(10)),
+		fb.Eq("size", int64(100)),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(uploads))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	assert.Equal(t, chunks, uploads[0].Chunks)
+	assert.Equal(t, "state1", uploads[0].HashState)
+
+	// Move the upload to finalizing, with the finalize request stored against it
+	finalize := &core.BlobUploadFinalize{Filename: "file.txt", Value: fftypes.JSONAnyPtr(`{"some":"value"}`)}
+	finalizeJSON, _ := json.Marshal(finalize)
+	dataID := fftypes.NewUUID()
+	up = database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("state", core.BlobUploadStateFinalizing).
+		Set("data", dataID).
+		Set("finalize", fftypes.JSONAnyPtrBytes(finalizeJSON))
+	updated, err = s.UpdateBlobUpload(ctx, "ns1", upload.ID, fb.Eq("state", core.BlobUploadStateReceiving), up)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	uploads, _, err = s.GetBlobUploads(ctx, "ns1", fb.Eq("state", core.BlobUploadStateFinalizing))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(uploads))
+	assert.Equal(t, dataID, uploads[0].Data)
+	assert.Equal(t, finalize, uploads[0].Finalize)
+
+	// Complete the upload, clearing the chunks
+	up = database.BlobUploadQueryFactory.NewUpdate(ctx).
+		Set("state", core.BlobUploadStateComplete).
+		Set("chunks", nil)
+	updated, err = s.UpdateBlobUpload(ctx, "ns1", upload.ID, fb.Eq("state", core.BlobUploadStateFinalizing), up)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStateComplete, uploadRead.State)
+	assert.Empty(t, uploadRead.Chunks)
+
+	// Not found
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns2", upload.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, uploadRead)
+
+	// Delete, including a repeat delete
+	err = s.DeleteBlobUpload(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	err = s.DeleteBlobUpload(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, uploadRead)
+}
+
+func TestBlobUploadFinalizeStored(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	upload := &core.BlobUpload{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     core.BlobUploadStateFailed,
+		Data:      fftypes.NewUUID(),
+		Error:     "pop",
+		Finalize:  &core.BlobUploadFinalize{Mimetype: "text/plain"},
+		Created:   fftypes.Now(),
+		Updated:   fftypes.Now(),
+		Expires:   fftypes.Now(),
+	}
+	err := s.InsertBlobUpload(ctx, upload)
+	assert.NoError(t, err)
+
+	uploadRead, err := s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, upload.Data, uploadRead.Data)
+	assert.Equal(t, "pop", uploadRead.Error)
+	assert.Equal(t, upload.Finalize, uploadRead.Finalize)
+
+	// A finalize request that cannot be restored fails the read
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	up := database.BlobUploadQueryFactory.NewUpdate(ctx).Set("finalize", fftypes.JSONAnyPtr(`"bad"`))
+	_, err = s.UpdateBlobUpload(ctx, "ns1", upload.ID, fb.And(), up)
+	assert.NoError(t, err)
+	_, err = s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.Regexp(t, "FF10121", err)
+}
+
+func TestInsertBlobUploadFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlobUploadFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlobUploadFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBlobUploadBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("received", int64(1))
+	_, err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateBlobUploadBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("id", map[bool]bool{true: false})
+	_, err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestUpdateBlobUploadFilterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("received", int64(1))
+	_, err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), f, u)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestUpdateBlobUploadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("received", int64(1))
+	_, err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetBlobUploadByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBlobUploadByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetBlobUploadByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestGetBlobUploadsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlobUploadFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBlobUpload(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlobUploadFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBlobUpload(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
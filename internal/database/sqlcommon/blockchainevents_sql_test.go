@@ -115,6 +115,13 @@ func TestBlockchainEventsE2EWithDB(t *testing.T) {
 	existing, err = s.InsertOrGetBlockchainEvent(ctx, event4)
 	assert.NoError(t, err)
 	assert.Equal(t, event3.ID, existing.ID)
+
+	// Delete the events
+	err = s.DeleteBlockchainEvents(ctx, "ns", []*fftypes.UUID{event.ID, event3.ID})
+	assert.NoError(t, err)
+	existing, err = s.GetBlockchainEventByID(ctx, "ns", event3.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
 }
 
 func TestInsertBlockchainEventFailBegin(t *testing.T) {
@@ -195,3 +202,21 @@ func TestGetBlockchainEventsScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteBlockchainEventsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBlockchainEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlockchainEventsFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBlockchainEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -38,6 +38,12 @@ const (
 	SQLConfMaxIdleConns = "maxIdleConns"
 	// SQLConfMaxConnLifetime maximum connections to the database
 	SQLConfMaxConnLifetime = "maxConnLifetime"
+	// SQLConfValueIndexes is an array of paths within the value of data, to create database indexes for
+	SQLConfValueIndexes = "valueIndexes"
+	// SQLConfValueIndexDatatype optionally restricts an index to data of the named datatype
+	SQLConfValueIndexDatatype = "datatype"
+	// SQLConfValueIndexPath is the dot-separated JSON path within the value of data to index
+	SQLConfValueIndexPath = "path"
 )
 
 const (
@@ -52,4 +58,14 @@ func (s *SQLCommon) InitConfig(provider dbsql.Provider, config config.Section) {
 	config.AddKnownKey(SQLConfMaxConnIdleTime, "1m")
 	config.AddKnownKey(SQLConfMaxIdleConns) // defaults to the max connections
 	config.AddKnownKey(SQLConfMaxConnLifetime)
+	initDataValueIndexesArray(config)
+}
+
+// The known keys of array entries are held on the array section, so this is used
+// both at config initialization and when reading the entries
+func initDataValueIndexesArray(config config.Section) config.ArraySection {
+	indexes := config.SubArray(SQLConfValueIndexes)
+	indexes.AddKnownKey(SQLConfValueIndexDatatype)
+	indexes.AddKnownKey(SQLConfValueIndexPath)
+	return indexes
 }
@@ -41,8 +41,10 @@ var (
 		"namespace",
 		"message_id",
 		"published",
+		"group_hash",
 	}
 	contractAPIsFilterFieldMap = map[string]string{
+		"group":       "group_hash",
 		"interface":   "interface_id",
 		"message":     "message_id",
 		"networkname": "network_name",
@@ -68,6 +70,7 @@ func (s *SQLCommon) attemptContractAPIUpdate(ctx context.Context, tx *dbsql.TXWr
 			Set("network_name", networkName).
 			Set("message_id", api.Message).
 			Set("published", api.Published).
+			Set("group_hash", api.Group).
 			Where(sq.Eq{"id": api.ID}),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionContractAPIs, core.ChangeEventTypeUpdated, api.Namespace, api.ID)
@@ -93,6 +96,7 @@ func (s *SQLCommon) setContractAPIInsertValues(query sq.InsertBuilder, api *core
 		api.Namespace,
 		api.Message,
 		api.Published,
+		api.Group,
 	)
 }
 
@@ -113,7 +117,7 @@ func (s *SQLCommon) contractAPIExists(ctx context.Context, tx *dbsql.TXWrapper,
 			},
 			sq.Or{
 				sq.Eq{"name": api.Name},
-				sq.Eq{"network_name": api.NetworkName},
+				sq.Eq{"network_name": api.NetworkName, "group_hash": api.Group},
 			},
 		}),
 	)
@@ -142,7 +146,7 @@ func (s *SQLCommon) InsertOrGetContractAPI(ctx context.Context, api *core.Contra
 		sq.Or{
 			sq.Eq{"id": api.ID},
 			sq.Eq{"name": api.Name},
-			sq.Eq{"network_name": api.NetworkName},
+			sq.Eq{"network_name": api.NetworkName, "group_hash": api.Group},
 		},
 	})
 	if queryErr != nil || existing != nil {
@@ -201,6 +205,7 @@ func (s *SQLCommon) contractAPIResult(ctx context.Context, row *sql.Rows) (*core
 		&api.Namespace,
 		&api.Message,
 		&api.Published,
+		&api.Group,
 	)
 	if networkName != nil {
 		api.NetworkName = *networkName
@@ -270,8 +275,8 @@ func (s *SQLCommon) GetContractAPIByName(ctx context.Context, namespace, name st
 	return s.getContractAPIPred(ctx, namespace+":"+name, sq.Eq{"namespace": namespace, "name": name})
 }
 
-func (s *SQLCommon) GetContractAPIByNetworkName(ctx context.Context, namespace, networkName string) (*core.ContractAPI, error) {
-	return s.getContractAPIPred(ctx, namespace+":"+networkName, sq.Eq{"namespace": namespace, "network_name": networkName})
+func (s *SQLCommon) GetContractAPIByNetworkName(ctx context.Context, namespace, networkName string, group *fftypes.Bytes32) (*core.ContractAPI, error) {
+	return s.getContractAPIPred(ctx, namespace+":"+networkName, sq.Eq{"namespace": namespace, "network_name": networkName, "group_hash": group})
 }
 
 func (s *SQLCommon) DeleteContractAPI(ctx context.Context, namespace string, id *fftypes.UUID) error {
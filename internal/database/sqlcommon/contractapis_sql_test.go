@@ -84,7 +84,7 @@ func TestContractAPIE2EWithDB(t *testing.T) {
 	assert.NotNil(t, dataRead)
 	assert.Equal(t, *apiID, *dataRead.ID)
 
-	dataRead, err = s.GetContractAPIByNetworkName(ctx, "ns1", "banana-net")
+	dataRead, err = s.GetContractAPIByNetworkName(ctx, "ns1", "banana-net", nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, dataRead)
 	assert.Equal(t, *apiID, *dataRead.ID)
@@ -111,6 +111,29 @@ func TestContractAPIE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, contractAPI.ID, existing.ID)
 
+	// The same network name can be used within a private group
+	privateAPI := &core.ContractAPI{
+		ID:          fftypes.NewUUID(),
+		Name:        "banana-1",
+		NetworkName: "banana-net",
+		Namespace:   "ns1",
+		Interface: &fftypes.FFIReference{
+			ID: interfaceID,
+		},
+		Group: fftypes.NewRandB32(),
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionContractAPIs, core.ChangeEventTypeCreated, "ns1", privateAPI.ID, mock.Anything).Return()
+	existing, err = s.InsertOrGetContractAPI(ctx, privateAPI)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	dataRead, err = s.GetContractAPIByNetworkName(ctx, "ns1", "banana-net", privateAPI.Group)
+	assert.NoError(t, err)
+	assert.Equal(t, *privateAPI.ID, *dataRead.ID)
+	assert.Equal(t, privateAPI.Group, dataRead.Group)
+	dataRead, err = s.GetContractAPIByNetworkName(ctx, "ns1", "banana-net", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, *apiID, *dataRead.ID)
+
 	// Delete the API
 	err = s.DeleteContractAPI(ctx, "ns1", contractAPI.ID)
 	assert.NoError(t, err)
@@ -213,8 +236,8 @@ func TestContractAPIDBNoRows(t *testing.T) {
 func TestGetContractAPIs(t *testing.T) {
 	fb := database.ContractAPIQueryFactory.NewFilter(context.Background())
 	s, mock := newMockProvider().init()
-	rows := sqlmock.NewRows([]string{"id", "interface_id", "location", "name", "network_name", "namespace", "message_id", "published"}).
-		AddRow("7e2c001c-e270-4fd7-9e82-9dacee843dc2", "8fcc4938-7d8b-4c00-a71b-1b46837c8ab1", nil, "banana", "banana", "ns1", "acfe07a2-117f-46b7-8d47-e3beb7cc382f", true)
+	rows := sqlmock.NewRows([]string{"id", "interface_id", "location", "name", "network_name", "namespace", "message_id", "published", "group_hash"}).
+		AddRow("7e2c001c-e270-4fd7-9e82-9dacee843dc2", "8fcc4938-7d8b-4c00-a71b-1b46837c8ab1", nil, "banana", "banana", "ns1", "acfe07a2-117f-46b7-8d47-e3beb7cc382f", true, nil)
 	mock.ExpectQuery("SELECT .*").WillReturnRows(rows)
 	_, _, err := s.GetContractAPIs(context.Background(), "ns1", fb.And())
 	assert.NoError(t, err)
@@ -251,8 +274,8 @@ func TestGetContractAPIsQueryResultFail(t *testing.T) {
 
 func TestGetContractAPIByName(t *testing.T) {
 	s, mock := newMockProvider().init()
-	rows := sqlmock.NewRows([]string{"id", "interface_id", "location", "name", "network_name", "namespace", "message_id", "published"}).
-		AddRow("7e2c001c-e270-4fd7-9e82-9dacee843dc2", "8fcc4938-7d8b-4c00-a71b-1b46837c8ab1", nil, "banana", "banana", "ns1", "acfe07a2-117f-46b7-8d47-e3beb7cc382f", true)
+	rows := sqlmock.NewRows([]string{"id", "interface_id", "location", "name", "network_name", "namespace", "message_id", "published", "group_hash"}).
+		AddRow("7e2c001c-e270-4fd7-9e82-9dacee843dc2", "8fcc4938-7d8b-4c00-a71b-1b46837c8ab1", nil, "banana", "banana", "ns1", "acfe07a2-117f-46b7-8d47-e3beb7cc382f", true, nil)
 	mock.ExpectQuery("SELECT .*").WillReturnRows(rows)
 	api, err := s.GetContractAPIByName(context.Background(), "ns1", "banana")
 	assert.NotNil(t, api)
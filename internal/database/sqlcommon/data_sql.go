@@ -300,9 +300,13 @@ func (s *SQLCommon) GetDataByID(ctx context.Context, namespace string, id *fftyp
 
 func (s *SQLCommon) GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error) {
 
+	fieldMap, err := s.dataValueFieldMap(ctx, filter, dataFilterFieldMap, "value", func(expr string) string { return expr })
+	if err != nil {
+		return nil, nil, err
+	}
 	query, fop, fi, err := s.FilterSelect(
 		ctx, "", sq.Select(dataColumnsWithValue...).From(dataTable),
-		filter, dataFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+		filter, fieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -42,9 +42,11 @@ var (
 		"hash",
 		"created",
 		"value",
+		"group_hash",
 	}
 	datatypeFilterFieldMap = map[string]string{
 		"message": "message_id",
+		"group":   "group_hash",
 	}
 )
 
@@ -87,6 +89,7 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 				Set("hash", datatype.Hash).
 				Set("created", datatype.Created).
 				Set("value", datatype.Value).
+				Set("group_hash", datatype.Group).
 				Where(sq.Eq{"id": datatype.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeUpdated, datatype.Namespace, datatype.ID)
@@ -108,6 +111,7 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 					datatype.Hash,
 					datatype.Created,
 					datatype.Value,
+					datatype.Group,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeCreated, datatype.Namespace, datatype.ID)
@@ -132,6 +136,7 @@ func (s *SQLCommon) datatypeResult(ctx context.Context, row *sql.Rows) (*core.Da
 		&datatype.Hash,
 		&datatype.Created,
 		&datatype.Value,
+		&datatype.Group,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, datatypesTable)
@@ -168,8 +173,15 @@ func (s *SQLCommon) GetDatatypeByID(ctx context.Context, namespace string, id *f
 	return s.getDatatypeEq(ctx, sq.Eq{"id": id, "namespace": namespace}, id.String())
 }
 
-func (s *SQLCommon) GetDatatypeByName(ctx context.Context, ns, name, version string) (message *core.Datatype, err error) {
-	return s.getDatatypeEq(ctx, sq.Eq{"namespace": ns, "name": name, "version": version}, fmt.Sprintf("%s:%s", ns, name))
+func (s *SQLCommon) GetDatatypeByName(ctx context.Context, ns, name, version string, group *fftypes.Bytes32) (message *core.Datatype, err error) {
+	// A datatype shared privately with the group takes precedence over one published to the whole network
+	if group != nil {
+		datatype, err := s.getDatatypeEq(ctx, sq.Eq{"namespace": ns, "name": name, "version": version, "group_hash": group}, fmt.Sprintf("%s:%s:%s", ns, name, group))
+		if err != nil || datatype != nil {
+			return datatype, err
+		}
+	}
+	return s.getDatatypeEq(ctx, sq.Eq{"namespace": ns, "name": name, "version": version, "group_hash": nil}, fmt.Sprintf("%s:%s", ns, name))
 }
 
 func (s *SQLCommon) GetDatatypes(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Datatype, res *ffapi.FilterResult, err error) {
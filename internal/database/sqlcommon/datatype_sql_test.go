@@ -116,6 +116,38 @@ func TestDatatypeE2EWithDB(t *testing.T) {
 	datatypeReadJson, _ = json.Marshal(datatypes[0])
 	assert.Equal(t, string(datatypeJson), string(datatypeReadJson))
 
+	// The same name and version can be shared privately with a group,
+	// which takes precedence when looked up by name for that group
+	privateDatatype := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Message:   fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "customer",
+		Version:   "0.0.1",
+		Group:     fftypes.NewRandB32(),
+		Hash:      randB32,
+		Created:   fftypes.Now(),
+		Value:     fftypes.JSONAnyPtr(val.String()),
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDataTypes, core.ChangeEventTypeCreated, "ns1", privateDatatype.ID, mock.Anything).Return()
+	err = s.UpsertDatatype(ctx, privateDatatype, false)
+	assert.NoError(t, err)
+	datatypeRead, err = s.GetDatatypeByName(ctx, "ns1", "customer", "0.0.1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, datatypeID, datatypeRead.ID)
+	datatypeRead, err = s.GetDatatypeByName(ctx, "ns1", "customer", "0.0.1", privateDatatype.Group)
+	assert.NoError(t, err)
+	assert.Equal(t, privateDatatype.ID, datatypeRead.ID)
+	// Any other group falls back to the network-wide datatype
+	datatypeRead, err = s.GetDatatypeByName(ctx, "ns1", "customer", "0.0.1", fftypes.NewRandB32())
+	assert.NoError(t, err)
+	assert.Equal(t, datatypeID, datatypeRead.ID)
+	datatypes, _, err = s.GetDatatypes(ctx, "ns1", fb.Eq("group", privateDatatype.Group))
+	assert.NoError(t, err)
+	assert.Len(t, datatypes, 1)
+	assert.Equal(t, privateDatatype.ID, datatypes[0].ID)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -196,11 +228,29 @@ func TestGetDatatypeByIDNotFound(t *testing.T) {
 func TestGetDatatypeByNameNotFound(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
-	msg, err := s.GetDatatypeByName(context.Background(), "ns1", "name1", "0.0.1")
+	msg, err := s.GetDatatypeByName(context.Background(), "ns1", "name1", "0.0.1", nil)
 	assert.NoError(t, err)
 	assert.Nil(t, msg)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetDatatypeByNameGroupNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	msg, err := s.GetDatatypeByName(context.Background(), "ns1", "name1", "0.0.1", fftypes.NewRandB32())
+	assert.NoError(t, err)
+	assert.Nil(t, msg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDatatypeByNameGroupQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDatatypeByName(context.Background(), "ns1", "name1", "0.0.1", fftypes.NewRandB32())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 func TestGetDatatypeByIDScanFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	datatypeID := fftypes.NewUUID()
@@ -0,0 +1,190 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// JSONProvider is an optional interface for providers that can extract values from JSON columns,
+// which enables filtering, sorting and indexing on paths within the value of data
+type JSONProvider interface {
+	// JSONExtract returns an SQL expression for the scalar at the path within the JSON column as text,
+	// or NULL if the path does not exist. Path segments are pre-validated to be safe to embed in SQL.
+	JSONExtract(column string, path []string) string
+
+	// JSONExtractNumber returns an SQL expression for the number at the path within the JSON column,
+	// or NULL if the path does not exist or is not a number
+	JSONExtractNumber(column string, path []string) string
+}
+
+var dataValuePathSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (s *SQLCommon) dataValueExpression(ctx context.Context, column, path string, number bool) (string, error) {
+	if s.jsonProvider == nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgDataValueQueryUnsupported, s.providerName)
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if !dataValuePathSegmentRegex.MatchString(segment) {
+			return "", i18n.NewError(ctx, coremsgs.MsgDataValuePathInvalid, path)
+		}
+	}
+	if number {
+		return s.jsonProvider.JSONExtractNumber(column, segments), nil
+	}
+	return s.jsonProvider.JSONExtract(column, segments), nil
+}
+
+// dataValueField returns the path within the value of data that a filter field refers to, if any,
+// and whether it is compared as a number
+func dataValueField(field string) (path string, number, ok bool) {
+	lower := strings.ToLower(field)
+	switch {
+	case strings.HasPrefix(lower, database.DataValueFieldPrefix):
+		return field[len(database.DataValueFieldPrefix):], false, true
+	case strings.HasPrefix(lower, database.DataValueNumberFieldPrefix):
+		return field[len(database.DataValueNumberFieldPrefix):], true, true
+	default:
+		return "", false, false
+	}
+}
+
+func collectFilterFields(fi *ffapi.FilterInfo, fields []string) []string {
+	if fi.Field != "" {
+		fields = append(fields, fi.Field)
+	}
+	for _, child := range fi.Children {
+		fields = collectFilterFields(child, fields)
+	}
+	for _, sf := range fi.Sort {
+		fields = append(fields, sf.Field)
+	}
+	return fields
+}
+
+func hasDataValueCondition(fi *ffapi.FilterInfo) bool {
+	if _, _, isDataValue := dataValueField(fi.Field); isDataValue {
+		return true
+	}
+	for _, child := range fi.Children {
+		if hasDataValueCondition(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// finalizedFilter presents an already finalized filter (or part of one), so that it can be built into SQL on its own
+type finalizedFilter struct {
+	ffapi.Filter
+	fi *ffapi.FilterInfo
+}
+
+func (f *finalizedFilter) Finalize() (*ffapi.FilterInfo, error) {
+	return f.fi, nil
+}
+
+// dataValueFieldMap extends the field map for a query with SQL expressions for any filter or
+// sort fields that reference a path within the value of data. The wrap function allows queries
+// on other tables to build a sub-query around the expression.
+func (s *SQLCommon) dataValueFieldMap(ctx context.Context, filter ffapi.Filter, fieldMap map[string]string, column string, wrap func(expr string) string) (map[string]string, error) {
+	fi, err := filter.Finalize()
+	if err != nil {
+		// The same error will be reported when the query is built
+		return fieldMap, nil
+	}
+	var extended map[string]string
+	for _, field := range collectFilterFields(fi, nil) {
+		path, number, ok := dataValueField(field)
+		if !ok {
+			continue
+		}
+		expr, err := s.dataValueExpression(ctx, column, path, number)
+		if err != nil {
+			return nil, err
+		}
+		if extended == nil {
+			extended = make(map[string]string, len(fieldMap)+1)
+			for k, v := range fieldMap {
+				extended[k] = v
+			}
+		}
+		extended[field] = wrap(expr)
+	}
+	if extended == nil {
+		return fieldMap, nil
+	}
+	return extended, nil
+}
+
+func dataValueIndexName(datatype, path string, number bool) string {
+	hash := sha256.Sum256([]byte(datatype + "/" + path))
+	if number {
+		return "data_valuenum_" + hex.EncodeToString(hash[0:8])
+	}
+	return "data_value_" + hex.EncodeToString(hash[0:8])
+}
+
+// createDataValueIndexes creates any expression indexes configured for paths within the value of data.
+// Each path is indexed both as text and as a number, as range comparisons against a number use the
+// number expression (see DataValueNumberFieldPrefix), which cannot use an index on the text.
+// Indexes are only ever created, so an index that is removed from the configuration must be dropped manually.
+func (s *SQLCommon) createDataValueIndexes(ctx context.Context, conf config.Section) error {
+	indexes := initDataValueIndexesArray(conf)
+	for i := 0; i < indexes.ArraySize(); i++ {
+		indexConf := indexes.ArrayEntry(i)
+		datatype := indexConf.GetString(SQLConfValueIndexDatatype)
+		path := indexConf.GetString(SQLConfValueIndexPath)
+		if path == "" {
+			return i18n.NewError(ctx, coremsgs.MsgDataValuePathInvalid, path)
+		}
+		if datatype != "" {
+			if err := fftypes.ValidateFFNameField(ctx, datatype, "datatype"); err != nil {
+				return err
+			}
+		}
+		log.L(ctx).Infof("Ensuring data value indexes on path '%s' for datatype '%s'", path, datatype)
+		for _, number := range []bool{false, true} {
+			expr, err := s.dataValueExpression(ctx, "value", path, number)
+			if err != nil {
+				return err
+			}
+			ddl := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s ((%s))", dataValueIndexName(datatype, path, number), dataTable, expr)
+			if datatype != "" {
+				ddl = fmt.Sprintf("%s WHERE datatype_name = '%s'", ddl, datatype)
+			}
+			if _, err := s.DB().ExecContext(ctx, ddl); err != nil {
+				return i18n.WrapError(ctx, err, coremsgs.MsgDBQueryFailed)
+			}
+		}
+	}
+	return nil
+}
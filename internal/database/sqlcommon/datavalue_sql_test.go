@@ -0,0 +1,270 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// noJSONProvider hides the JSONProvider implementation of the wrapped provider
+type noJSONProvider struct {
+	dbsql.Provider
+}
+
+func newTestDataValue(s *sqliteGoTestProvider, t *testing.T, value string) *core.Data {
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Hash:      fftypes.NewRandB32(),
+		Created:   fftypes.Now(),
+		Value:     fftypes.JSONAnyPtr(value),
+	}
+	err := s.UpsertData(context.Background(), data, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestDataValueE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	data1 := newTestDataValue(s, t, `{"customer":{"id":"123"},"amount":"20","paid":true,"lines":["a","b"],"total":9}`)
+	data2 := newTestDataValue(s, t, `{"customer":{"id":"456"},"amount":"10","paid":false,"lines":["c"],"total":10.5}`)
+	data3 := newTestDataValue(s, t, `{"note":"no customer","total":"100"}`)
+	data4 := newTestDataValue(s, t, `{"customer":{"id":"789"}}`)
+
+	msg1 := &core.Message{
+		LocalNamespace: "ns1",
+		Header:         core.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.MessageTypeBroadcast, Created: fftypes.Now(), DataHash: fftypes.NewRandB32()},
+		Hash:           fftypes.NewRandB32(),
+		Data:           core.DataRefs{{ID: data3.ID, Hash: data3.Hash}, {ID: data1.ID, Hash: data1.Hash}},
+	}
+	msg2 := &core.Message{
+		LocalNamespace: "ns1",
+		Header:         core.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.MessageTypeBroadcast, Created: fftypes.Now(), DataHash: fftypes.NewRandB32()},
+		Hash:           fftypes.NewRandB32(),
+		Data:           core.DataRefs{{ID: data4.ID, Hash: data4.Hash}, {ID: data2.ID, Hash: data2.Hash}},
+	}
+	err := s.UpsertMessage(ctx, msg1, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+	err = s.UpsertMessage(ctx, msg2, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+
+	fb := database.WithDataValueFields(database.DataQueryFactory, "customer.id", "amount", "paid", "lines.1", "total").NewFilter(ctx)
+
+	// Equality on a nested path
+	data, res, err := s.GetData(ctx, "ns1", fb.And(fb.Eq("value.customer.id", "123")).Count(true))
+	assert.NoError(t, err)
+	assert.Len(t, data, 1)
+	assert.Equal(t, *data1.ID, *data[0].ID)
+	assert.Equal(t, int64(1), *res.TotalCount)
+
+	// Sorting on a path, with data missing the path excluded
+	data, _, err = s.GetData(ctx, "ns1", fb.And(fb.Neq("value.amount", nil)).Sort("value.amount"))
+	assert.NoError(t, err)
+	assert.Len(t, data, 2)
+	assert.Equal(t, *data2.ID, *data[0].ID)
+	assert.Equal(t, *data1.ID, *data[1].ID)
+
+	// Booleans and array indexes
+	data, _, err = s.GetData(ctx, "ns1", fb.And(fb.Eq("value.paid", "true"), fb.Eq("value.lines.1", "b")))
+	assert.NoError(t, err)
+	assert.Len(t, data, 1)
+	assert.Equal(t, *data1.ID, *data[0].ID)
+
+	// Numbers compare numerically, and only match JSON numbers
+	data, _, err = s.GetData(ctx, "ns1", fb.And(fb.Gt("valuenum.total", "8.5")).Sort("value.total"))
+	assert.NoError(t, err)
+	assert.Len(t, data, 2)
+	assert.Equal(t, *data2.ID, *data[0].ID)
+	assert.Equal(t, *data1.ID, *data[1].ID)
+
+	// Messages match if any of their data items match
+	mfb := database.WithDataValueFields(database.MessageQueryFactory, "customer.id", "total").NewFilter(ctx)
+	msgs, res, err := s.GetMessages(ctx, "ns1", mfb.And(mfb.In("value.customer.id", []driver.Value{"123", "999"})).Count(true))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, *msg1.Header.ID, *msgs[0].Header.ID)
+	assert.Equal(t, int64(1), *res.TotalCount)
+
+	msgs, _, err = s.GetMessages(ctx, "ns1", mfb.And(mfb.StartsWith("value.customer.id", "4")))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, *msg2.Header.ID, *msgs[0].Header.ID)
+
+	msgs, _, err = s.GetMessages(ctx, "ns1", mfb.And(
+		mfb.Or(mfb.Gte("valuenum.total", "10"), mfb.Eq("id", msg1.Header.ID)),
+		mfb.Eq("type", core.MessageTypeBroadcast),
+	).Sort("created").Limit(1))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, *msg1.Header.ID, *msgs[0].Header.ID)
+
+	msgs, _, err = s.GetMessages(ctx, "ns1", mfb.And(mfb.Gte("valuenum.total", "10")))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, *msg2.Header.ID, *msgs[0].Header.ID)
+}
+
+func TestGetDataValueInvalidPath(t *testing.T) {
+	s, _ := newMockProvider().init()
+	fb := database.WithDataValueFields(database.DataQueryFactory, "a'b").NewFilter(context.Background())
+	_, _, err := s.GetData(context.Background(), "ns1", fb.Eq("value.a'b", "c"))
+	assert.Regexp(t, "FF10541", err)
+}
+
+func TestGetMessagesDataValueInvalidPath(t *testing.T) {
+	s, _ := newMockProvider().init()
+	fb := database.WithDataValueFields(database.MessageQueryFactory, "a..b").NewFilter(context.Background())
+	_, _, err := s.GetMessages(context.Background(), "ns1", fb.And().Sort("value.a..b"))
+	assert.Regexp(t, "FF10541", err)
+}
+
+func TestGetMessagesDataValueQuery(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT .* FROM messages WHERE \(namespace_local = \$1 AND EXISTS \(SELECT 1 FROM messages_data md JOIN data d .* AND json_text\(d.value, 'customer.id'\) = \$2\) AND \(1=1\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	fb := database.WithDataValueFields(database.MessageQueryFactory, "customer.id").NewFilter(context.Background())
+	_, _, err := s.GetMessages(context.Background(), "ns1", fb.Eq("value.customer.id", "123"))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDataValueUnsupported(t *testing.T) {
+	mp := newMockProvider()
+	err := mp.SQLCommon.Init(context.Background(), &noJSONProvider{Provider: mp}, mp.config, mp.capabilities)
+	assert.NoError(t, err)
+	fb := database.WithDataValueFields(database.DataQueryFactory, "a").NewFilter(context.Background())
+	_, _, err = mp.GetData(context.Background(), "ns1", fb.Eq("value.a", "b"))
+	assert.Regexp(t, "FF10542.*mockdb", err)
+}
+
+func setTestValueIndexes(t *testing.T, indexes string) {
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+unittest:
+  db:
+    valueIndexes:` + indexes))
+	assert.NoError(t, err)
+}
+
+func TestCreateDataValueIndexes(t *testing.T) {
+	mp := newMockProvider()
+	setTestValueIndexes(t, `
+    - path: customer.id
+      datatype: invoice
+    - path: amount`)
+	mp.mdb.ExpectExec(`CREATE INDEX IF NOT EXISTS data_value_[0-9a-f]{16} ON data \(\(json_text\(value, 'customer.id'\)\)\) WHERE datatype_name = 'invoice'`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mp.mdb.ExpectExec(`CREATE INDEX IF NOT EXISTS data_valuenum_[0-9a-f]{16} ON data \(\(json_number\(value, 'customer.id'\)\)\) WHERE datatype_name = 'invoice'`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mp.mdb.ExpectExec(`CREATE INDEX IF NOT EXISTS data_value_[0-9a-f]{16} ON data \(\(json_text\(value, 'amount'\)\)\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mp.mdb.ExpectExec(`CREATE INDEX IF NOT EXISTS data_valuenum_[0-9a-f]{16} ON data \(\(json_number\(value, 'amount'\)\)\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.NoError(t, err)
+	assert.NoError(t, mp.mdb.ExpectationsWereMet())
+}
+
+func TestCreateDataValueIndexesUsedWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	setTestValueIndexes(t, `
+    - path: total`)
+	err := s.createDataValueIndexes(ctx, s.config)
+	assert.NoError(t, err)
+
+	// Both text comparisons, and range comparisons against a number, can use an index
+	for _, number := range []bool{false, true} {
+		expr, err := s.dataValueExpression(ctx, "value", "total", number)
+		assert.NoError(t, err)
+		rows, err := s.DB().QueryContext(ctx, fmt.Sprintf("EXPLAIN QUERY PLAN SELECT id FROM data WHERE %s > ?", expr), 10)
+		assert.NoError(t, err)
+		plan := ""
+		for rows.Next() {
+			var id, parent, notUsed int
+			var detail string
+			err = rows.Scan(&id, &parent, &notUsed, &detail)
+			assert.NoError(t, err)
+			plan += detail
+		}
+		rows.Close()
+		assert.Contains(t, plan, dataValueIndexName("", "total", number))
+	}
+}
+
+func TestCreateDataValueIndexesExecFail(t *testing.T) {
+	mp := newMockProvider()
+	setTestValueIndexes(t, `
+    - path: amount`)
+	mp.mdb.ExpectExec("CREATE INDEX .*").WillReturnError(fmt.Errorf("pop"))
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.Regexp(t, "FF10115.*pop", err)
+}
+
+func TestCreateDataValueIndexesBadDatatype(t *testing.T) {
+	mp := newMockProvider()
+	setTestValueIndexes(t, `
+    - path: amount
+      datatype: bad'name`)
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.Regexp(t, "FF00140.*datatype", err)
+}
+
+func TestCreateDataValueIndexesMissingPath(t *testing.T) {
+	mp := newMockProvider()
+	setTestValueIndexes(t, `
+    - datatype: invoice`)
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.Regexp(t, "FF10541", err)
+}
+
+func TestCreateDataValueIndexesUnsupported(t *testing.T) {
+	mp := newMockProvider()
+	setTestValueIndexes(t, `
+    - path: amount`)
+	err := mp.SQLCommon.Init(context.Background(), &noJSONProvider{Provider: mp}, mp.config, mp.capabilities)
+	assert.Regexp(t, "FF10542", err)
+}
+
+func TestInitFailMissingURL(t *testing.T) {
+	mp := newMockProvider()
+	mp.config.Set(SQLConfDatasourceURL, "")
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.Regexp(t, "FF00183", err)
+}
@@ -0,0 +1,180 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	definitionProposalColumns = []string{
+		"id",
+		"namespace",
+		"tag",
+		"topic",
+		"author",
+		"tx_id",
+		"state",
+		"quorum",
+		"votes",
+		"error",
+		"created",
+		"updated",
+	}
+	definitionProposalFilterFieldMap = map[string]string{
+		"tx": "tx_id",
+	}
+)
+
+const definitionproposalsTable = "definitionproposals"
+
+func (s *SQLCommon) InsertDefinitionProposal(ctx context.Context, proposal *core.DefinitionProposal) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, definitionproposalsTable, tx,
+		sq.Insert(definitionproposalsTable).
+			Columns(definitionProposalColumns...).
+			Values(
+				proposal.ID,
+				proposal.Namespace,
+				proposal.Tag,
+				proposal.Topic,
+				proposal.Author,
+				proposal.TX,
+				proposal.State,
+				proposal.Quorum,
+				proposal.Votes,
+				proposal.Error,
+				proposal.Created,
+				proposal.Updated,
+			),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionDefinitionProposals, core.ChangeEventTypeCreated, proposal.Namespace, proposal.ID)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) definitionProposalResult(ctx context.Context, row *sql.Rows) (*core.DefinitionProposal, error) {
+	var proposal core.DefinitionProposal
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.Namespace,
+		&proposal.Tag,
+		&proposal.Topic,
+		&proposal.Author,
+		&proposal.TX,
+		&proposal.State,
+		&proposal.Quorum,
+		&proposal.Votes,
+		&proposal.Error,
+		&proposal.Created,
+		&proposal.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, definitionproposalsTable)
+	}
+	return &proposal, nil
+}
+
+func (s *SQLCommon) GetDefinitionProposalByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DefinitionProposal, error) {
+	rows, _, err := s.Query(ctx, definitionproposalsTable,
+		sq.Select(definitionProposalColumns...).
+			From(definitionproposalsTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Definition proposal '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.definitionProposalResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetDefinitionProposals(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(definitionProposalColumns...).From(definitionproposalsTable),
+		filter, definitionProposalFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, definitionproposalsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	proposals := []*core.DefinitionProposal{}
+	for rows.Next() {
+		proposal, err := s.definitionProposalResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, s.QueryRes(ctx, definitionproposalsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) UpdateDefinitionProposal(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(definitionproposalsTable), update, definitionProposalFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	ra, err := s.UpdateTx(ctx, definitionproposalsTable, tx, query, func() {
+		s.callbacks.UUIDCollectionNSEvent(database.CollectionDefinitionProposals, core.ChangeEventTypeUpdated, namespace, id)
+	})
+	if err != nil {
+		return err
+	}
+	if ra < 1 {
+		return i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,223 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitionProposalsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new definition proposal
+	proposal := &core.DefinitionProposal{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Tag:       core.SystemTagDefineDatatype,
+		Topic:     "topic1",
+		Author:    "did:firefly:org/org1",
+		TX:        fftypes.NewUUID(),
+		State:     core.DefinitionProposalStateProposed,
+		Quorum:    2,
+		Votes:     core.DefinitionProposalVotes{},
+		Created:   fftypes.Now(),
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDefinitionProposals, core.ChangeEventTypeCreated, "ns1", proposal.ID).Return()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDefinitionProposals, core.ChangeEventTypeUpdated, "ns1", proposal.ID).Return()
+
+	err := s.InsertDefinitionProposal(ctx, proposal)
+	assert.NoError(t, err)
+	proposalJson, _ := json.Marshal(&proposal)
+
+	// Query back the proposal (by ID)
+	proposalRead, err := s.GetDefinitionProposalByID(ctx, "ns1", proposal.ID)
+	assert.NoError(t, err)
+	proposalReadJson, _ := json.Marshal(proposalRead)
+	assert.Equal(t, string(proposalJson), string(proposalReadJson))
+
+	// Query back the proposal (by query filter)
+	fb := database.DefinitionProposalQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("state", core.DefinitionProposalStateProposed),
+		fb.Eq("tx", proposal.TX),
+	)
+	proposals, res, err := s.GetDefinitionProposals(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(proposals))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	proposalReadJson, _ = json.Marshal(proposals[0])
+	assert.Equal(t, string(proposalJson), string(proposalReadJson))
+
+	// Record a vote and confirm the proposal
+	proposal.Votes = append(proposal.Votes, &core.DefinitionProposalVote{
+		Org:     fftypes.NewUUID(),
+		Author:  "did:firefly:org/org2",
+		Approve: true,
+		Message: fftypes.NewUUID(),
+		Created: fftypes.Now(),
+	})
+	proposal.State = core.DefinitionProposalStateConfirmed
+	proposal.Updated = fftypes.Now()
+	votesJSON, _ := json.Marshal(proposal.Votes)
+	up := database.DefinitionProposalQueryFactory.NewUpdate(ctx).
+		Set("votes", fftypes.JSONAnyPtrBytes(votesJSON)).
+		Set("state", proposal.State).
+		Set("updated", proposal.Updated)
+	err = s.UpdateDefinitionProposal(ctx, "ns1", proposal.ID, up)
+	assert.NoError(t, err)
+	proposalRead, err = s.GetDefinitionProposalByID(ctx, "ns1", proposal.ID)
+	assert.NoError(t, err)
+	proposalJson, _ = json.Marshal(&proposal)
+	proposalReadJson, _ = json.Marshal(proposalRead)
+	assert.Equal(t, string(proposalJson), string(proposalReadJson))
+
+	// No longer matches the original filter
+	proposals, _, err = s.GetDefinitionProposals(ctx, "ns1", filter)
+	assert.NoError(t, err)
+	assert.Empty(t, proposals)
+}
+
+func TestInsertDefinitionProposalFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDefinitionProposal(context.Background(), &core.DefinitionProposal{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDefinitionProposalFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertDefinitionProposal(context.Background(), &core.DefinitionProposal{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDefinitionProposalFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDefinitionProposal(context.Background(), &core.DefinitionProposal{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDefinitionProposalByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDefinitionProposalByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDefinitionProposalByIDNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	proposal, err := s.GetDefinitionProposalByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Nil(t, proposal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDefinitionProposalByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetDefinitionProposalByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDefinitionProposalsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DefinitionProposalQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, _, err := s.GetDefinitionProposals(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestGetDefinitionProposalsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DefinitionProposalQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetDefinitionProposals(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDefinitionProposalsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.DefinitionProposalQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetDefinitionProposals(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDefinitionProposalFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.DefinitionProposalQueryFactory.NewUpdate(context.Background()).Set("quorum", 1)
+	err := s.UpdateDefinitionProposal(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDefinitionProposalFailFilter(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.DefinitionProposalQueryFactory.NewUpdate(context.Background()).Set("wrong", 1)
+	err := s.UpdateDefinitionProposal(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00142", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDefinitionProposalFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.DefinitionProposalQueryFactory.NewUpdate(context.Background()).Set("quorum", 1)
+	err := s.UpdateDefinitionProposal(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDefinitionProposalNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectRollback()
+	u := database.DefinitionProposalQueryFactory.NewUpdate(context.Background()).Set("quorum", 1)
+	err := s.UpdateDefinitionProposal(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF10143", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
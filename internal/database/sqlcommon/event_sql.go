@@ -225,3 +225,21 @@ func (s *SQLCommon) GetEvents(ctx context.Context, namespace string, filter ffap
 	return events, s.QueryRes(ctx, eventsTable, tx, fop, fi), err
 
 }
+
+func (s *SQLCommon) DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, eventsTable, tx, sq.Delete(eventsTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        ids,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -86,6 +86,17 @@ func TestEventE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(events))
 
+	// Delete the event
+	err = s.DeleteEvents(ctx, "ns1", []*fftypes.UUID{eventRead.ID})
+	assert.NoError(t, err)
+	eventRead, err = s.GetEventByID(ctx, "ns1", eventRead.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, eventRead)
+
+	// Delete again is a no-op
+	err = s.DeleteEvents(ctx, "ns1", []*fftypes.UUID{event.ID})
+	assert.NoError(t, err)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -253,3 +264,21 @@ func TestGettEventsReadMessageFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteEventsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEventsFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -43,6 +43,7 @@ var (
 		"published",
 	}
 	ffiFilterFieldMap = map[string]string{
+		"group":       "group_hash",
 		"message":     "message_id",
 		"networkname": "network_name",
 	}
@@ -50,7 +51,7 @@ var (
 
 const ffiTable = "ffi"
 
-func (s *SQLCommon) attemptFFIUpdate(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI) (int64, error) {
+func (s *SQLCommon) attemptFFIUpdate(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI, group *fftypes.Bytes32) (int64, error) {
 	var networkName *string
 	if ffi.NetworkName != "" {
 		networkName = &ffi.NetworkName
@@ -63,6 +64,7 @@ func (s *SQLCommon) attemptFFIUpdate(ctx context.Context, tx *dbsql.TXWrapper, f
 			Set("description", ffi.Description).
 			Set("message_id", ffi.Message).
 			Set("published", ffi.Published).
+			Set("group_hash", group).
 			Where(sq.Eq{"id": ffi.ID}),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionFFIs, core.ChangeEventTypeUpdated, ffi.Namespace, ffi.ID)
@@ -70,7 +72,7 @@ func (s *SQLCommon) attemptFFIUpdate(ctx context.Context, tx *dbsql.TXWrapper, f
 	)
 }
 
-func (s *SQLCommon) setFFIInsertValues(query sq.InsertBuilder, ffi *fftypes.FFI) sq.InsertBuilder {
+func (s *SQLCommon) setFFIInsertValues(query sq.InsertBuilder, ffi *fftypes.FFI, group *fftypes.Bytes32) sq.InsertBuilder {
 	var networkName *string
 	if ffi.NetworkName != "" {
 		networkName = &ffi.NetworkName
@@ -84,19 +86,21 @@ func (s *SQLCommon) setFFIInsertValues(query sq.InsertBuilder, ffi *fftypes.FFI)
 		ffi.Description,
 		ffi.Message,
 		ffi.Published,
+		group,
 	)
 }
 
-func (s *SQLCommon) attemptFFIInsert(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI, requestConflictEmptyResult bool) error {
+func (s *SQLCommon) attemptFFIInsert(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI, group *fftypes.Bytes32, requestConflictEmptyResult bool) error {
+	// The group is not part of the FFI itself, so is not returned on query
 	_, err := s.InsertTxExt(ctx, ffiTable, tx,
-		s.setFFIInsertValues(sq.Insert(ffiTable).Columns(ffiColumns...), ffi),
+		s.setFFIInsertValues(sq.Insert(ffiTable).Columns(append(ffiColumns, "group_hash")...), ffi, group),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionFFIs, core.ChangeEventTypeCreated, ffi.Namespace, ffi.ID)
 		}, requestConflictEmptyResult)
 	return err
 }
 
-func (s *SQLCommon) ffiExists(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI) (bool, error) {
+func (s *SQLCommon) ffiExists(ctx context.Context, tx *dbsql.TXWrapper, ffi *fftypes.FFI, group *fftypes.Bytes32) (bool, error) {
 	rows, _, err := s.QueryTx(ctx, ffiTable, tx,
 		sq.Select("id").From(ffiTable).Where(sq.And{
 			sq.Eq{
@@ -105,7 +109,7 @@ func (s *SQLCommon) ffiExists(ctx context.Context, tx *dbsql.TXWrapper, ffi *fft
 			},
 			sq.Or{
 				sq.Eq{"name": ffi.Name},
-				sq.Eq{"network_name": ffi.NetworkName},
+				sq.Eq{"network_name": ffi.NetworkName, "group_hash": group},
 			},
 		}),
 	)
@@ -116,14 +120,14 @@ func (s *SQLCommon) ffiExists(ctx context.Context, tx *dbsql.TXWrapper, ffi *fft
 	return rows.Next(), nil
 }
 
-func (s *SQLCommon) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI) (existing *fftypes.FFI, err error) {
+func (s *SQLCommon) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32) (existing *fftypes.FFI, err error) {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer s.RollbackTx(ctx, tx, autoCommit)
 
-	insertErr := s.attemptFFIInsert(ctx, tx, ffi, true /* we want a failure here we can progress past */)
+	insertErr := s.attemptFFIInsert(ctx, tx, ffi, group, true /* we want a failure here we can progress past */)
 	if insertErr == nil {
 		return nil, s.CommitTx(ctx, tx, autoCommit)
 	}
@@ -134,7 +138,7 @@ func (s *SQLCommon) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI) (exist
 		sq.Or{
 			sq.Eq{"id": ffi.ID},
 			sq.Eq{"name": ffi.Name},
-			sq.Eq{"network_name": ffi.NetworkName},
+			sq.Eq{"network_name": ffi.NetworkName, "group_hash": group},
 		},
 	})
 	if queryErr != nil || existing != nil {
@@ -145,7 +149,7 @@ func (s *SQLCommon) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI) (exist
 	return nil, insertErr
 }
 
-func (s *SQLCommon) UpsertFFI(ctx context.Context, ffi *fftypes.FFI, optimization database.UpsertOptimization) error {
+func (s *SQLCommon) UpsertFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32, optimization database.UpsertOptimization) error {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
 		return err
@@ -154,24 +158,24 @@ func (s *SQLCommon) UpsertFFI(ctx context.Context, ffi *fftypes.FFI, optimizatio
 
 	optimized := false
 	if optimization == database.UpsertOptimizationNew {
-		opErr := s.attemptFFIInsert(ctx, tx, ffi, true /* we want a failure here we can progress past */)
+		opErr := s.attemptFFIInsert(ctx, tx, ffi, group, true /* we want a failure here we can progress past */)
 		optimized = opErr == nil
 	} else if optimization == database.UpsertOptimizationExisting {
-		rowsAffected, opErr := s.attemptFFIUpdate(ctx, tx, ffi)
+		rowsAffected, opErr := s.attemptFFIUpdate(ctx, tx, ffi, group)
 		optimized = opErr == nil && rowsAffected == 1
 	}
 
 	if !optimized {
 		// Do a select within the transaction to determine if the FFI already exists
-		exists, err := s.ffiExists(ctx, tx, ffi)
+		exists, err := s.ffiExists(ctx, tx, ffi, group)
 		if err != nil {
 			return err
 		} else if exists {
-			if _, err := s.attemptFFIUpdate(ctx, tx, ffi); err != nil {
+			if _, err := s.attemptFFIUpdate(ctx, tx, ffi, group); err != nil {
 				return err
 			}
 		}
-		if err := s.attemptFFIInsert(ctx, tx, ffi, false); err != nil {
+		if err := s.attemptFFIInsert(ctx, tx, ffi, group, false); err != nil {
 			return err
 		}
 	}
@@ -264,11 +268,12 @@ func (s *SQLCommon) GetFFI(ctx context.Context, namespace, name, version string)
 	})
 }
 
-func (s *SQLCommon) GetFFIByNetworkName(ctx context.Context, namespace, networkName, version string) (*fftypes.FFI, error) {
+func (s *SQLCommon) GetFFIByNetworkName(ctx context.Context, namespace, networkName, version string, group *fftypes.Bytes32) (*fftypes.FFI, error) {
 	return s.getFFIPred(ctx, namespace+":"+networkName+":"+version, sq.Eq{
 		"namespace":    namespace,
 		"network_name": networkName,
 		"version":      version,
+		"group_hash":   group,
 	})
 }
 
@@ -74,7 +74,7 @@ func TestFFIE2EWithDB(t *testing.T) {
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionFFIs, core.ChangeEventTypeUpdated, "ns1", ffi.ID).Return()
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionFFIs, core.ChangeEventTypeDeleted, "ns1", ffi.ID).Return()
 
-	_, err := s.InsertOrGetFFI(ctx, ffi)
+	_, err := s.InsertOrGetFFI(ctx, ffi, nil)
 	assert.NoError(t, err)
 
 	// Check we get the correct fields back
@@ -88,7 +88,7 @@ func TestFFIE2EWithDB(t *testing.T) {
 	assert.Equal(t, ffi.Message, dataRead.Message)
 
 	ffi.Version = "v1.1.0"
-	err = s.UpsertFFI(ctx, ffi, database.UpsertOptimizationExisting)
+	err = s.UpsertFFI(ctx, ffi, nil, database.UpsertOptimizationExisting)
 	assert.NoError(t, err)
 
 	// Check we get the correct fields back
@@ -101,7 +101,7 @@ func TestFFIE2EWithDB(t *testing.T) {
 	assert.Equal(t, ffi.Version, dataRead.Version)
 	assert.Equal(t, ffi.Message, dataRead.Message)
 
-	dataRead, err = s.GetFFIByNetworkName(ctx, "ns1", "math", "v1.1.0")
+	dataRead, err = s.GetFFIByNetworkName(ctx, "ns1", "math", "v1.1.0", nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, dataRead)
 	assert.Equal(t, ffi.ID, dataRead.ID)
@@ -116,7 +116,7 @@ func TestFFIE2EWithDB(t *testing.T) {
 		Name:      "math",
 		Version:   "v1.1.0",
 		Namespace: "ns1",
-	})
+	}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, ffi.ID, existing.ID)
 	existing, err = s.InsertOrGetFFI(ctx, &fftypes.FFI{
@@ -124,10 +124,35 @@ func TestFFIE2EWithDB(t *testing.T) {
 		NetworkName: "math",
 		Version:     "v1.1.0",
 		Namespace:   "ns1",
-	})
+	}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, ffi.ID, existing.ID)
 
+	// The same network name can be used within a private group
+	group := fftypes.NewRandB32()
+	privateFFI := &fftypes.FFI{
+		ID:          fftypes.NewUUID(),
+		Name:        "math-1",
+		NetworkName: "math",
+		Version:     "v1.1.0",
+		Namespace:   "ns1",
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionFFIs, core.ChangeEventTypeCreated, "ns1", privateFFI.ID).Return()
+	existing, err = s.InsertOrGetFFI(ctx, privateFFI, group)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	dataRead, err = s.GetFFIByNetworkName(ctx, "ns1", "math", "v1.1.0", group)
+	assert.NoError(t, err)
+	assert.Equal(t, privateFFI.ID, dataRead.ID)
+	dataRead, err = s.GetFFIByNetworkName(ctx, "ns1", "math", "v1.1.0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ffi.ID, dataRead.ID)
+	fb := database.FFIQueryFactory.NewFilter(ctx)
+	ffis, _, err := s.GetFFIs(ctx, "ns1", fb.Eq("group", group))
+	assert.NoError(t, err)
+	assert.Len(t, ffis, 1)
+	assert.Equal(t, privateFFI.ID, ffis[0].ID)
+
 	// Delete the FFI
 	err = s.DeleteFFI(ctx, "ns1", ffi.ID)
 	assert.NoError(t, err)
@@ -136,7 +161,7 @@ func TestFFIE2EWithDB(t *testing.T) {
 func TestFFIDBFailBeginTransaction(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
-	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, database.UpsertOptimizationNew)
+	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, nil, database.UpsertOptimizationNew)
 	assert.Regexp(t, "FF00175", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -145,7 +170,7 @@ func TestFFIDBFailSelect(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
-	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, database.UpsertOptimizationNew)
+	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, nil, database.UpsertOptimizationNew)
 	assert.Regexp(t, "pop", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -155,7 +180,7 @@ func TestFFIDBFailUpsert(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
 	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
-	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, database.UpsertOptimizationNew)
+	err := s.UpsertFFI(context.Background(), &fftypes.FFI{}, nil, database.UpsertOptimizationNew)
 	assert.Regexp(t, "pop", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -163,7 +188,7 @@ func TestFFIDBFailUpsert(t *testing.T) {
 func TestFFIDBInsertFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
-	_, err := s.InsertOrGetFFI(context.Background(), &fftypes.FFI{})
+	_, err := s.InsertOrGetFFI(context.Background(), &fftypes.FFI{}, nil)
 	assert.Regexp(t, "FF00175", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -177,7 +202,7 @@ func TestFFIDBFailInsert(t *testing.T) {
 	ffi := &fftypes.FFI{
 		ID: fftypes.NewUUID(),
 	}
-	_, err := s.InsertOrGetFFI(context.Background(), ffi)
+	_, err := s.InsertOrGetFFI(context.Background(), ffi, nil)
 	assert.Regexp(t, "FF00177", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -192,7 +217,7 @@ func TestFFIDBFailUpdate(t *testing.T) {
 	ffi := &fftypes.FFI{
 		ID: fftypes.NewUUID(),
 	}
-	err := s.UpsertFFI(context.Background(), ffi, database.UpsertOptimizationNew)
+	err := s.UpsertFFI(context.Background(), ffi, nil, database.UpsertOptimizationNew)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -44,8 +44,10 @@ var (
 		"messages_claim",
 		"messages_verification",
 		"messages_update",
+		"messages_revocation",
 		"created",
 		"updated",
+		"revoked",
 	}
 	identityFilterFieldMap = map[string]string{
 		"identity":              "identity_id",
@@ -53,6 +55,7 @@ var (
 		"messages.claim":        "messages_claim",
 		"messages.verification": "messages_verification",
 		"messages.update":       "messages_update",
+		"messages.revocation":   "messages_revocation",
 	}
 )
 
@@ -71,7 +74,9 @@ func (s *SQLCommon) attemptIdentityUpdate(ctx context.Context, tx *dbsql.TXWrapp
 			Set("messages_claim", identity.Messages.Claim).
 			Set("messages_verification", identity.Messages.Verification).
 			Set("messages_update", identity.Messages.Update).
+			Set("messages_revocation", identity.Messages.Revocation).
 			Set("updated", identity.Updated).
+			Set("revoked", identity.Revoked).
 			Where(sq.Eq{
 				"id":        identity.ID,
 				"namespace": identity.Namespace,
@@ -99,8 +104,10 @@ func (s *SQLCommon) attemptIdentityInsert(ctx context.Context, tx *dbsql.TXWrapp
 				identity.Messages.Claim,
 				identity.Messages.Verification,
 				identity.Messages.Update,
+				identity.Messages.Revocation,
 				identity.Created,
 				identity.Updated,
+				identity.Revoked,
 			),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionIdentities, core.ChangeEventTypeCreated, identity.Namespace, identity.ID)
@@ -166,8 +173,10 @@ func (s *SQLCommon) identityResult(ctx context.Context, row *sql.Rows) (*core.Id
 		&identity.Messages.Claim,
 		&identity.Messages.Verification,
 		&identity.Messages.Update,
+		&identity.Messages.Revocation,
 		&identity.Created,
 		&identity.Updated,
+		&identity.Revoked,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, identitiesTable)
@@ -89,8 +89,10 @@ func TestIdentitiesE2EWithDB(t *testing.T) {
 			Claim:        fftypes.NewUUID(),
 			Verification: fftypes.NewUUID(),
 			Update:       fftypes.NewUUID(),
+			Revocation:   fftypes.NewUUID(),
 		},
 		Created: identity.Created,
+		Revoked: fftypes.Now(),
 	}
 	err = s.UpsertIdentity(context.Background(), identityUpdated, database.UpsertOptimizationExisting)
 	assert.NoError(t, err)
@@ -56,6 +56,9 @@ var (
 		"tx_parent_id",
 		"batch_id",
 		"idempotency_key",
+		"expires",
+		"reply_deadline",
+		"encrypted",
 	}
 	msgFilterFieldMap = map[string]string{
 		"type":           "mtype",
@@ -66,6 +69,7 @@ var (
 		"batch":          "batch_id",
 		"group":          "group_hash",
 		"idempotencykey": "idempotency_key",
+		"replydeadline":  "reply_deadline",
 	}
 )
 
@@ -101,6 +105,8 @@ func (s *SQLCommon) attemptMessageUpdate(ctx context.Context, tx *dbsql.TXWrappe
 			Set("tx_parent_id", txParentID).
 			Set("batch_id", message.BatchID).
 			Set("idempotency_key", message.IdempotencyKey).
+			Set("expires", message.Header.Expires).
+			Set("encrypted", message.Header.Encrypted).
 			Where(sq.Eq{
 				"id":              message.Header.ID,
 				"hash":            message.Hash,
@@ -143,6 +149,9 @@ func (s *SQLCommon) setMessageInsertValues(query sq.InsertBuilder, message *core
 		txParentID,
 		message.BatchID,
 		message.IdempotencyKey,
+		message.Header.Expires,
+		message.ReplyDeadline,
+		message.Header.Encrypted,
 	)
 }
 
@@ -456,6 +465,9 @@ func (s *SQLCommon) msgResult(ctx context.Context, row *sql.Rows) (*core.Message
 		&txParent.ID,
 		&msg.BatchID,
 		&msg.IdempotencyKey,
+		&msg.Header.Expires,
+		&msg.ReplyDeadline,
+		&msg.Header.Encrypted,
 		// Must be added to the list of columns in all selects
 		&msg.Sequence,
 	)
@@ -590,14 +602,81 @@ func (s *SQLCommon) queryBatchIDs(ctx context.Context, query sq.SelectBuilder) (
 	return batchIDs, nil
 }
 
+// msgDataValueSubQuery selects the value at a path within the data of each message, from the first data
+// item (in the order attached to the message) in which the path is set. This is only used for sorting.
+func msgDataValueSubQuery(expr string) string {
+	return fmt.Sprintf("(SELECT %s FROM %s md JOIN %s d ON d.namespace = md.namespace AND d.id = md.data_id "+
+		"WHERE md.namespace = %s.namespace_local AND md.message_id = %s.id AND %s IS NOT NULL ORDER BY md.data_idx LIMIT 1)",
+		expr, messagesDataJoinTable, dataTable, messagesTable, messagesTable, expr)
+}
+
+// msgFilterCondition builds the condition for a finalized message filter, where each condition on a path within
+// data values matches if it is true for any of the data items of the message
+func (s *SQLCommon) msgFilterCondition(ctx context.Context, filter ffapi.Filter, fi *ffapi.FilterInfo) (sq.Sqlizer, error) {
+	switch fi.Op {
+	case ffapi.FilterOpAnd, ffapi.FilterOpOr:
+		conditions := make([]sq.Sqlizer, len(fi.Children))
+		for i, child := range fi.Children {
+			condition, err := s.msgFilterCondition(ctx, filter, child)
+			if err != nil {
+				return nil, err
+			}
+			conditions[i] = condition
+		}
+		if fi.Op == ffapi.FilterOpAnd {
+			return sq.And(conditions), nil
+		}
+		return sq.Or(conditions), nil
+	}
+
+	path, number, isDataValue := dataValueField(fi.Field)
+	if !isDataValue {
+		_, condition, _, err := s.FilterSelect(ctx, "", sq.Select(), &finalizedFilter{Filter: filter, fi: fi}, msgFilterFieldMap, nil)
+		return condition, err
+	}
+	expr, err := s.dataValueExpression(ctx, "d.value", path, number)
+	if err != nil {
+		return nil, err
+	}
+	_, condition, _, err := s.FilterSelect(ctx, "", sq.Select(), &finalizedFilter{Filter: filter, fi: fi}, map[string]string{fi.Field: expr}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sq.Expr(fmt.Sprintf("EXISTS (SELECT 1 FROM %s md JOIN %s d ON d.namespace = md.namespace AND d.id = md.data_id "+
+		"WHERE md.namespace = %s.namespace_local AND md.message_id = %s.id AND ?)",
+		messagesDataJoinTable, dataTable, messagesTable, messagesTable), condition), nil
+}
+
 func (s *SQLCommon) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, fr *ffapi.FilterResult, err error) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, s.SequenceColumn())
-	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(messagesTable), filter, msgFilterFieldMap,
+	fieldMap, err := s.dataValueFieldMap(ctx, filter, msgFilterFieldMap, "d.value", msgDataValueSubQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	preconditions := []sq.Sqlizer{sq.Eq{"namespace_local": namespace}}
+	if fi, err := filter.Finalize(); err == nil && hasDataValueCondition(fi) {
+		// Conditions on data values are built separately, and the filter only supplies the sort and pagination
+		condition, err := s.msgFilterCondition(ctx, filter, fi)
+		if err != nil {
+			return nil, nil, err
+		}
+		preconditions = append(preconditions, condition)
+		paging := &ffapi.FilterInfo{
+			Op:        ffapi.FilterOpAnd,
+			Sort:      fi.Sort,
+			Skip:      fi.Skip,
+			Limit:     fi.Limit,
+			Count:     fi.Count,
+			CountExpr: fi.CountExpr,
+		}
+		filter = &finalizedFilter{Filter: filter, fi: paging}
+	}
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(messagesTable), filter, fieldMap,
 		[]interface{}{
 			&ffapi.SortField{Field: "confirmed", Descending: true, Nulls: ffapi.NullsFirst},
 			&ffapi.SortField{Field: "created", Descending: true},
-		}, sq.Eq{"namespace_local": namespace})
+		}, preconditions...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -651,3 +730,29 @@ func (s *SQLCommon) UpdateMessages(ctx context.Context, namespace string, filter
 
 	return s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteMessage(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, messagesDataJoinTable, tx, sq.Delete(messagesDataJoinTable).Where(sq.Eq{
+		"namespace":  namespace,
+		"message_id": id,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	err = s.DeleteTx(ctx, messagesTable, tx, sq.Delete(messagesTable).Where(sq.Eq{
+		"namespace_local": namespace,
+		"id":              id,
+	}), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -65,10 +65,13 @@ func TestUpsertE2EWithDB(t *testing.T) {
 				Type: core.TransactionTypeTokenTransfer,
 				ID:   fftypes.NewUUID(),
 			},
+			Expires:   fftypes.Now(),
+			Encrypted: true,
 		},
-		Hash:      fftypes.NewRandB32(),
-		State:     core.MessageStateStaged,
-		Confirmed: nil,
+		Hash:          fftypes.NewRandB32(),
+		State:         core.MessageStateStaged,
+		Confirmed:     nil,
+		ReplyDeadline: fftypes.Now(),
 		Data: []*core.DataRef{
 			{ID: dataID1, Hash: rand1},
 			{ID: dataID2, Hash: rand2},
@@ -146,7 +149,9 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	<-hookCalled
 	assert.NoError(t, err)
 
-	// Check we get the exact same message back - note the removal of one of the data elements
+	// Check we get the exact same message back - note the removal of one of the data elements,
+	// and that the local reply deadline is not changed by an upsert
+	msgUpdated.ReplyDeadline = msg.ReplyDeadline
 	msgRead, err = s.GetMessageByID(ctx, "ns12345", msgID)
 	// The generated sequence will have been added
 	msgUpdated.Sequence = msgRead.Sequence
@@ -167,6 +172,7 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		fb.Eq("idempotencykey", msgUpdated.IdempotencyKey),
 		fb.Gt("created", "0"),
 		fb.Gt("confirmed", "0"),
+		fb.Gt("replydeadline", "0"),
 	)
 	msgs, res, err := s.GetMessages(ctx, "ns12345", filter.Count(true))
 	assert.NoError(t, err)
@@ -214,7 +220,8 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	bid2 := fftypes.NewUUID()
 	up := database.MessageQueryFactory.NewUpdate(ctx).
 		Set("group", gid2).
-		Set("batch", bid2)
+		Set("batch", bid2).
+		Set("replydeadline", nil)
 	err = s.UpdateMessage(ctx, "ns12345", msgID, up)
 	assert.NoError(t, err)
 
@@ -227,9 +234,11 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(msgs))
 	assert.Equal(t, *bid2, *msgs[0].BatchID)
+	assert.Nil(t, msgs[0].ReplyDeadline)
 
 	// Bump and Update - this is for a ready transition
 	msgUpdated.State = core.MessageStateReady
+	msgUpdated.ReplyDeadline = nil
 	err = s.ReplaceMessage(context.Background(), msgUpdated)
 	assert.NoError(t, err)
 	msgRead, err = s.GetMessageByID(ctx, "ns12345", msgUpdated.Header.ID)
@@ -237,6 +246,20 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	msgReadJson, _ = json.Marshal(msgRead)
 	assert.Equal(t, string(msgJson), string(msgReadJson))
 
+	// Delete the message, along with its data refs
+	err = s.DeleteMessage(ctx, "ns12345", msgID)
+	assert.NoError(t, err)
+	msgRead, err = s.GetMessageByID(ctx, "ns12345", msgID)
+	assert.NoError(t, err)
+	assert.Nil(t, msgRead)
+	msgs, _, err = s.GetMessagesForData(ctx, "ns12345", dataID1, database.MessageQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// Delete again is a not found error
+	err = s.DeleteMessage(ctx, "ns12345", msgID)
+	assert.Equal(t, fftypes.DeleteRecordNotFound, err)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -565,7 +588,7 @@ func TestGetMessageByIDLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "pin", nil, "", nil, nil, "bob", nil, nil, false, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetMessageByID(context.Background(), "ns1", msgID)
 	assert.Regexp(t, "FF00176", err)
@@ -612,7 +635,7 @@ func TestGetMessagesLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "pin", nil, "", nil, nil, "bob", nil, nil, false, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Gt("confirmed", "0")
 	_, _, err := s.GetMessages(context.Background(), "ns1", f)
@@ -698,3 +721,32 @@ func TestGetBatchIDsForMessagesScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteMessageFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteMessage(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessageFailDeleteRefs(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessage(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessageFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(driver.RowsAffected(1))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessage(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	networkGovernanceColumns = []string{
+		"namespace",
+		"definition_quorum",
+		"org_onboarding",
+		"author",
+		"message_id",
+		"updated",
+	}
+)
+
+const networkgovernanceTable = "networkgovernance"
+
+func (s *SQLCommon) UpsertNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	governanceRows, _, err := s.QueryTx(ctx, networkgovernanceTable, tx,
+		sq.Select("seq").
+			From(networkgovernanceTable).
+			Where(sq.Eq{"namespace": governance.Namespace}),
+	)
+	if err != nil {
+		return err
+	}
+	existing := governanceRows.Next()
+	governanceRows.Close()
+
+	if existing {
+		if _, err = s.UpdateTx(ctx, networkgovernanceTable, tx,
+			sq.Update(networkgovernanceTable).
+				Set("definition_quorum", governance.DefinitionQuorum).
+				Set("org_onboarding", governance.OrgOnboarding).
+				Set("author", governance.Author).
+				Set("message_id", governance.Message).
+				Set("updated", governance.Updated).
+				Where(sq.Eq{"namespace": governance.Namespace}),
+			nil,
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err = s.InsertTx(ctx, networkgovernanceTable, tx,
+			sq.Insert(networkgovernanceTable).
+				Columns(networkGovernanceColumns...).
+				Values(
+					governance.Namespace,
+					governance.DefinitionQuorum,
+					governance.OrgOnboarding,
+					governance.Author,
+					governance.Message,
+					governance.Updated,
+				),
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) networkGovernanceResult(ctx context.Context, row *sql.Rows) (*core.NetworkGovernance, error) {
+	var governance core.NetworkGovernance
+	err := row.Scan(
+		&governance.Namespace,
+		&governance.DefinitionQuorum,
+		&governance.OrgOnboarding,
+		&governance.Author,
+		&governance.Message,
+		&governance.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, networkgovernanceTable)
+	}
+	return &governance, nil
+}
+
+func (s *SQLCommon) GetNetworkGovernance(ctx context.Context, namespace string) (*core.NetworkGovernance, error) {
+	rows, _, err := s.Query(ctx, networkgovernanceTable,
+		sq.Select(networkGovernanceColumns...).
+			From(networkgovernanceTable).
+			Where(sq.Eq{"namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Network governance for namespace '%s' not found", namespace)
+		return nil, nil
+	}
+
+	return s.networkGovernanceResult(ctx, rows)
+}
@@ -0,0 +1,144 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkGovernanceE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Nothing recorded yet
+	governanceRead, err := s.GetNetworkGovernance(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Nil(t, governanceRead)
+
+	// Record the governance rules
+	governance := &core.NetworkGovernance{
+		Namespace:        "ns1",
+		DefinitionQuorum: 2,
+		Author:           "did:firefly:org/org1",
+		Message:          fftypes.NewUUID(),
+		Updated:          fftypes.Now(),
+	}
+	err = s.UpsertNetworkGovernance(ctx, governance)
+	assert.NoError(t, err)
+	governanceJson, _ := json.Marshal(&governance)
+
+	governanceRead, err = s.GetNetworkGovernance(ctx, "ns1")
+	assert.NoError(t, err)
+	governanceReadJson, _ := json.Marshal(governanceRead)
+	assert.Equal(t, string(governanceJson), string(governanceReadJson))
+
+	// Replace the rules
+	governance.DefinitionQuorum = 0
+	governance.OrgOnboarding = true
+	governance.Author = "did:firefly:org/org2"
+	governance.Message = fftypes.NewUUID()
+	governance.Updated = fftypes.Now()
+	err = s.UpsertNetworkGovernance(ctx, governance)
+	assert.NoError(t, err)
+	governanceJson, _ = json.Marshal(&governance)
+
+	governanceRead, err = s.GetNetworkGovernance(ctx, "ns1")
+	assert.NoError(t, err)
+	governanceReadJson, _ = json.Marshal(governanceRead)
+	assert.Equal(t, string(governanceJson), string(governanceReadJson))
+
+	// Other namespaces are unaffected
+	governanceRead, err = s.GetNetworkGovernance(ctx, "ns2")
+	assert.NoError(t, err)
+	assert.Nil(t, governanceRead)
+}
+
+func TestUpsertNetworkGovernanceFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertNetworkGovernance(context.Background(), &core.NetworkGovernance{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNetworkGovernanceFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNetworkGovernance(context.Background(), &core.NetworkGovernance{})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNetworkGovernanceFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNetworkGovernance(context.Background(), &core.NetworkGovernance{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNetworkGovernanceFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(1))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNetworkGovernance(context.Background(), &core.NetworkGovernance{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNetworkGovernanceFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertNetworkGovernance(context.Background(), &core.NetworkGovernance{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNetworkGovernanceSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetNetworkGovernance(context.Background(), "ns1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNetworkGovernanceScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetNetworkGovernance(context.Background(), "ns1")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -120,6 +120,13 @@ func TestOperationE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(operations))
 
+	// Delete the operation
+	err = s.DeleteOperations(ctx, "ns1", []*fftypes.UUID{operation.ID})
+	assert.NoError(t, err)
+	opRead, err := s.GetOperationByID(ctx, "ns1", operation.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, opRead)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -241,3 +248,21 @@ func TestOperationUpdateFilterFail(t *testing.T) {
 	_, err := s.UpdateOperation(context.Background(), "ns1", fftypes.NewUUID(), f, u)
 	assert.Regexp(t, "FF00143", err)
 }
+
+func TestDeleteOperationsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteOperations(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationsFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteOperations(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
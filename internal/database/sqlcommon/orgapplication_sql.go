@@ -0,0 +1,181 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	orgApplicationColumns = []string{
+		"id",
+		"namespace",
+		"sponsor",
+		"org",
+		"node",
+		"key",
+		"state",
+		"reason",
+		"message_id",
+		"review_id",
+		"created",
+		"updated",
+	}
+	orgApplicationFilterFieldMap = map[string]string{
+		"message": "message_id",
+		"review":  "review_id",
+	}
+)
+
+const orgapplicationsTable = "orgapplications"
+
+func (s *SQLCommon) InsertOrgApplication(ctx context.Context, application *core.OrgApplication) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, orgapplicationsTable, tx,
+		sq.Insert(orgapplicationsTable).
+			Columns(orgApplicationColumns...).
+			Values(
+				application.ID,
+				application.Namespace,
+				application.Sponsor,
+				application.Org,
+				application.Node,
+				application.Key,
+				application.State,
+				application.Reason,
+				application.Message,
+				application.Review,
+				application.Created,
+				application.Updated,
+			),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionOrgApplications, core.ChangeEventTypeCreated, application.Namespace, application.ID)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) orgApplicationResult(ctx context.Context, row *sql.Rows) (*core.OrgApplication, error) {
+	var application core.OrgApplication
+	err := row.Scan(
+		&application.ID,
+		&application.Namespace,
+		&application.Sponsor,
+		&application.Org,
+		&application.Node,
+		&application.Key,
+		&application.State,
+		&application.Reason,
+		&application.Message,
+		&application.Review,
+		&application.Created,
+		&application.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, orgapplicationsTable)
+	}
+	return &application, nil
+}
+
+func (s *SQLCommon) GetOrgApplicationByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.OrgApplication, error) {
+	rows, _, err := s.Query(ctx, orgapplicationsTable,
+		sq.Select(orgApplicationColumns...).
+			From(orgapplicationsTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Org application '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.orgApplicationResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetOrgApplications(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.OrgApplication, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(orgApplicationColumns...).From(orgapplicationsTable),
+		filter, orgApplicationFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, orgapplicationsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applications := []*core.OrgApplication{}
+	for rows.Next() {
+		application, err := s.orgApplicationResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		applications = append(applications, application)
+	}
+
+	return applications, s.QueryRes(ctx, orgapplicationsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) UpdateOrgApplication(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(orgapplicationsTable), update, orgApplicationFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	ra, err := s.UpdateTx(ctx, orgapplicationsTable, tx, query, func() {
+		s.callbacks.UUIDCollectionNSEvent(database.CollectionOrgApplications, core.ChangeEventTypeUpdated, namespace, id)
+	})
+	if err != nil {
+		return err
+	}
+	if ra < 1 {
+		return i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
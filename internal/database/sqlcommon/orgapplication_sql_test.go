@@ -0,0 +1,230 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgApplicationsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new org application
+	application := &core.OrgApplication{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Sponsor:   "did:firefly:org/org1",
+		Org: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "org2",
+			IdentityProfile: core.IdentityProfile{
+				Description: "Org 2",
+			},
+		},
+		Node: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "node2",
+			IdentityProfile: core.IdentityProfile{
+				Profile: fftypes.JSONObject{"id": "peer2"},
+			},
+		},
+		Key:     "0x12345",
+		State:   core.OrgApplicationStatePending,
+		Message: fftypes.NewUUID(),
+		Created: fftypes.Now(),
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOrgApplications, core.ChangeEventTypeCreated, "ns1", application.ID).Return()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOrgApplications, core.ChangeEventTypeUpdated, "ns1", application.ID).Return()
+
+	err := s.InsertOrgApplication(ctx, application)
+	assert.NoError(t, err)
+	applicationJson, _ := json.Marshal(&application)
+
+	// Query back the application (by ID)
+	applicationRead, err := s.GetOrgApplicationByID(ctx, "ns1", application.ID)
+	assert.NoError(t, err)
+	applicationReadJson, _ := json.Marshal(applicationRead)
+	assert.Equal(t, string(applicationJson), string(applicationReadJson))
+
+	// Query back the application (by query filter)
+	fb := database.OrgApplicationQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("state", core.OrgApplicationStatePending),
+		fb.Eq("sponsor", "did:firefly:org/org1"),
+		fb.Eq("message", application.Message),
+	)
+	applications, res, err := s.GetOrgApplications(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(applications))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	applicationReadJson, _ = json.Marshal(applications[0])
+	assert.Equal(t, string(applicationJson), string(applicationReadJson))
+
+	// Approve the application
+	application.State = core.OrgApplicationStateApproved
+	application.Reason = "welcome"
+	application.Review = fftypes.NewUUID()
+	application.Updated = fftypes.Now()
+	up := database.OrgApplicationQueryFactory.NewUpdate(ctx).
+		Set("state", application.State).
+		Set("reason", application.Reason).
+		Set("review", application.Review).
+		Set("updated", application.Updated)
+	err = s.UpdateOrgApplication(ctx, "ns1", application.ID, up)
+	assert.NoError(t, err)
+	applicationRead, err = s.GetOrgApplicationByID(ctx, "ns1", application.ID)
+	assert.NoError(t, err)
+	applicationJson, _ = json.Marshal(&application)
+	applicationReadJson, _ = json.Marshal(applicationRead)
+	assert.Equal(t, string(applicationJson), string(applicationReadJson))
+
+	// No longer matches the original filter
+	applications, _, err = s.GetOrgApplications(ctx, "ns1", filter)
+	assert.NoError(t, err)
+	assert.Empty(t, applications)
+}
+
+func TestInsertOrgApplicationFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertOrgApplication(context.Background(), &core.OrgApplication{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertOrgApplicationFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertOrgApplication(context.Background(), &core.OrgApplication{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertOrgApplicationFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertOrgApplication(context.Background(), &core.OrgApplication{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrgApplicationByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetOrgApplicationByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrgApplicationByIDNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	application, err := s.GetOrgApplicationByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Nil(t, application)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrgApplicationByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetOrgApplicationByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrgApplicationsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.OrgApplicationQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, _, err := s.GetOrgApplications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestGetOrgApplicationsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.OrgApplicationQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetOrgApplications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrgApplicationsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.OrgApplicationQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetOrgApplications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOrgApplicationFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.OrgApplicationQueryFactory.NewUpdate(context.Background()).Set("reason", "r1")
+	err := s.UpdateOrgApplication(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOrgApplicationFailFilter(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.OrgApplicationQueryFactory.NewUpdate(context.Background()).Set("wrong", 1)
+	err := s.UpdateOrgApplication(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00142", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOrgApplicationFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.OrgApplicationQueryFactory.NewUpdate(context.Background()).Set("reason", "r1")
+	err := s.UpdateOrgApplication(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOrgApplicationNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectRollback()
+	u := database.OrgApplicationQueryFactory.NewUpdate(context.Background()).Set("reason", "r1")
+	err := s.UpdateOrgApplication(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF10143", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	sq "github.com/Masterminds/squirrel"
@@ -100,6 +101,14 @@ func (mp *mockProvider) ApplyInsertQueryCustomizations(insert sq.InsertBuilder,
 	return insert, false
 }
 
+func (mp *mockProvider) JSONExtract(column string, path []string) string {
+	return fmt.Sprintf("json_text(%s, '%s')", column, strings.Join(path, "."))
+}
+
+func (mp *mockProvider) JSONExtractNumber(column string, path []string) string {
+	return fmt.Sprintf("json_number(%s, '%s')", column, strings.Join(path, "."))
+}
+
 func (mp *mockProvider) Open(url string) (*sql.DB, error) {
 	return mp.mockDB, mp.openError
 }
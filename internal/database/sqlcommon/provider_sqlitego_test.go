@@ -19,8 +19,10 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"testing"
 
 	sq "github.com/Masterminds/squirrel"
@@ -98,6 +100,30 @@ func (tp *sqliteGoTestProvider) ApplyInsertQueryCustomizations(insert sq.InsertB
 	return insert, false
 }
 
+func testJSONPath(path []string) string {
+	jsonPath := "$"
+	for _, segment := range path {
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			jsonPath += "[" + segment + "]"
+		} else {
+			jsonPath += `."` + segment + `"`
+		}
+	}
+	return jsonPath
+}
+
+func (tp *sqliteGoTestProvider) JSONExtract(column string, path []string) string {
+	jsonPath := testJSONPath(path)
+	return fmt.Sprintf("(CASE json_type(%s, '%s') WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(%s, '%s') AS TEXT) END)",
+		column, jsonPath, column, jsonPath)
+}
+
+func (tp *sqliteGoTestProvider) JSONExtractNumber(column string, path []string) string {
+	jsonPath := testJSONPath(path)
+	return fmt.Sprintf("(CASE WHEN json_type(%s, '%s') IN ('integer', 'real') THEN json_extract(%s, '%s') END)",
+		column, jsonPath, column, jsonPath)
+}
+
 func (tp *sqliteGoTestProvider) Open(url string) (*sql.DB, error) {
 	return sql.Open("sqlite3", url)
 }
@@ -34,6 +34,8 @@ type SQLCommon struct {
 	dbsql.Database
 	capabilities *database.Capabilities
 	callbacks    callbacks
+	providerName string
+	jsonProvider JSONProvider
 }
 
 type callbacks struct {
@@ -79,7 +81,14 @@ func (cb *callbacks) HashCollectionNSEvent(resType database.HashCollectionNS, ev
 
 func (s *SQLCommon) Init(ctx context.Context, provider dbsql.Provider, config config.Section, capabilities *database.Capabilities) (err error) {
 	s.capabilities = capabilities
-	return s.Database.Init(ctx, provider, config)
+	if provider != nil {
+		s.providerName = provider.Name()
+		s.jsonProvider, _ = provider.(JSONProvider)
+	}
+	if err = s.Database.Init(ctx, provider, config); err != nil {
+		return err
+	}
+	return s.createDataValueIndexes(ctx, config)
 }
 
 func (s *SQLCommon) SetHandler(namespace string, handler database.Callbacks) {
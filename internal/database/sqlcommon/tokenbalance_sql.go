@@ -49,7 +49,7 @@ var (
 	}
 )
 
-func (s *SQLCommon) addTokenBalance(ctx context.Context, tx *dbsql.TXWrapper, transfer *core.TokenTransfer, key string, negate bool) error {
+func (s *SQLCommon) addTokenBalance(ctx context.Context, tx *dbsql.TXWrapper, transfer *core.TokenTransfer, timestamp *fftypes.FFTime, key string, negate bool) error {
 	balance, err := s.GetTokenBalance(ctx, transfer.Namespace, transfer.Pool, transfer.TokenIndex, key)
 	if err != nil {
 		return err
@@ -61,18 +61,21 @@ func (s *SQLCommon) addTokenBalance(ctx context.Context, tx *dbsql.TXWrapper, tr
 	} else {
 		total = &fftypes.FFBigInt{}
 	}
+	var amount fftypes.FFBigInt
 	if negate {
-		total.Int().Sub(total.Int(), transfer.Amount.Int())
+		amount.Int().Neg(transfer.Amount.Int())
 	} else {
-		total.Int().Add(total.Int(), transfer.Amount.Int())
+		amount.Int().Set(transfer.Amount.Int())
 	}
+	total.Int().Add(total.Int(), amount.Int())
+	now := fftypes.Now()
 
 	if balance != nil {
 		if _, err = s.UpdateTx(ctx, tokenbalanceTable, tx,
 			sq.Update(tokenbalanceTable).
 				Set("uri", transfer.URI).
 				Set("balance", total).
-				Set("updated", fftypes.Now()).
+				Set("updated", now).
 				Where(sq.Eq{
 					"namespace":   balance.Namespace,
 					"pool_id":     balance.Pool,
@@ -95,7 +98,7 @@ func (s *SQLCommon) addTokenBalance(ctx context.Context, tx *dbsql.TXWrapper, tr
 					transfer.Namespace,
 					key,
 					total,
-					fftypes.Now(),
+					now,
 				),
 			nil,
 		); err != nil {
@@ -103,10 +106,26 @@ func (s *SQLCommon) addTokenBalance(ctx context.Context, tx *dbsql.TXWrapper, tr
 		}
 	}
 
-	return nil
+	if timestamp == nil {
+		timestamp = now
+	}
+	return s.insertTokenBalanceChange(ctx, tx, &core.TokenBalanceChange{
+		Pool:            transfer.Pool,
+		TokenIndex:      transfer.TokenIndex,
+		URI:             transfer.URI,
+		Connector:       transfer.Connector,
+		Namespace:       transfer.Namespace,
+		Key:             key,
+		Amount:          amount,
+		Balance:         *total,
+		Transfer:        transfer.LocalID,
+		BlockchainEvent: transfer.BlockchainEvent,
+		Timestamp:       timestamp,
+		Created:         now,
+	})
 }
 
-func (s *SQLCommon) UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer) (err error) {
+func (s *SQLCommon) UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer, timestamp *fftypes.FFTime) (err error) {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
 		return err
@@ -114,12 +133,12 @@ func (s *SQLCommon) UpdateTokenBalances(ctx context.Context, transfer *core.Toke
 	defer s.RollbackTx(ctx, tx, autoCommit)
 
 	if transfer.From != "" {
-		if err := s.addTokenBalance(ctx, tx, transfer, transfer.From, true); err != nil {
+		if err := s.addTokenBalance(ctx, tx, transfer, timestamp, transfer.From, true); err != nil {
 			return err
 		}
 	}
 	if transfer.To != "" {
-		if err := s.addTokenBalance(ctx, tx, transfer, transfer.To, false); err != nil {
+		if err := s.addTokenBalance(ctx, tx, transfer, timestamp, transfer.To, false); err != nil {
 			return err
 		}
 	}
@@ -275,5 +294,13 @@ func (s *SQLCommon) DeleteTokenBalances(ctx context.Context, namespace string, p
 		return err
 	}
 
+	err = s.DeleteTx(ctx, tokenbalancehistoryTable, tx, sq.Delete(tokenbalancehistoryTable).Where(sq.Eq{
+		"namespace": namespace,
+		"pool_id":   poolID,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
 	return s.CommitTx(ctx, tx, autoCommit)
 }
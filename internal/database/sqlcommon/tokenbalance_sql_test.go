@@ -57,7 +57,7 @@ func TestTokenBalanceE2EWithDB(t *testing.T) {
 	}
 	balanceJson, _ := json.Marshal(&balance)
 
-	err := s.UpdateTokenBalances(ctx, transfer)
+	err := s.UpdateTokenBalances(ctx, transfer, fftypes.Now())
 	assert.NoError(t, err)
 
 	// Query back the token balance (by pool ID and identity)
@@ -89,7 +89,7 @@ func TestTokenBalanceE2EWithDB(t *testing.T) {
 	transfer.From = "0x0"
 	transfer.To = "0x1"
 	transfer.Amount = *fftypes.NewFFBigInt(5)
-	err = s.UpdateTokenBalances(ctx, transfer)
+	err = s.UpdateTokenBalances(ctx, transfer, nil)
 	assert.NoError(t, err)
 
 	// Query back the token balance (by pool ID and identity)
@@ -140,7 +140,7 @@ func TestTokenBalanceE2EWithDB(t *testing.T) {
 func TestUpdateTokenBalancesFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{}, nil)
 	assert.Regexp(t, "FF00175", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -149,7 +149,7 @@ func TestUpdateTokenBalancesFailSelect(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"}, nil)
 	assert.Regexp(t, "FF00176", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -160,7 +160,7 @@ func TestUpdateTokenBalancesFailInsert(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
 	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{From: "0x0"})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{From: "0x0"}, nil)
 	assert.Regexp(t, "FF00177", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -171,7 +171,7 @@ func TestUpdateTokenBalancesFailInsert2(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
 	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"}, nil)
 	assert.Regexp(t, "FF00177", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -182,18 +182,31 @@ func TestUpdateTokenBalancesFailUpdate(t *testing.T) {
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(tokenBalanceColumns).AddRow(fftypes.NewUUID().String(), "1", "", "", "", "0x0", "0", 0))
 	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
 	mock.ExpectRollback()
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"}, nil)
 	assert.Regexp(t, "FF00178", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateTokenBalancesFailInsertHistory(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"}, nil)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateTokenBalancesFailCommit(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
 	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
-	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"})
+	err := s.UpdateTokenBalances(context.Background(), &core.TokenTransfer{To: "0x0"}, nil)
 	assert.Regexp(t, "FF00180", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -307,3 +320,14 @@ func TestDeleteTokenBalancesFailDelete(t *testing.T) {
 	assert.Regexp(t, "FF00179", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteTokenBalancesFailDeleteHistory(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteTokenBalances(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,202 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const tokenbalancehistoryTable = "tokenbalancehistory"
+
+var (
+	tokenBalanceChangeColumns = []string{
+		"pool_id",
+		"token_index",
+		"uri",
+		"connector",
+		"namespace",
+		"key",
+		"amount",
+		"balance",
+		"transfer_id",
+		"blockchain_event",
+		"timestamp",
+		"created",
+	}
+	tokenBalanceChangeFilterFieldMap = map[string]string{
+		"sequence":        "seq",
+		"pool":            "pool_id",
+		"tokenindex":      "token_index",
+		"transfer":        "transfer_id",
+		"blockchainevent": "blockchain_event",
+	}
+	// The opening entries of the history have no blockchain event sequence
+	tokenBalanceChangeEventSeqColumn = "COALESCE(blockchain_event_seq, 0)"
+	// Balances at a point in history are read from the history table, using the filter fields of token balances
+	tokenBalanceAtColumns = []string{
+		"pool_id",
+		"token_index",
+		"uri",
+		"connector",
+		"namespace",
+		"key",
+		"balance",
+		"timestamp",
+	}
+	tokenBalanceAtFilterFieldMap = map[string]string{
+		"pool":       "pool_id",
+		"tokenindex": "token_index",
+		"updated":    "timestamp",
+	}
+)
+
+func (s *SQLCommon) insertTokenBalanceChange(ctx context.Context, tx *dbsql.TXWrapper, change *core.TokenBalanceChange) (err error) {
+	// The sequence of the blockchain event is stored with the change, so that the history can be queried as of a
+	// point in the sequence even once the event has been purged. A change that is not from a blockchain event
+	// (such as a reconciliation correction) is ordered after all of the blockchain events recorded so far - or
+	// after all of the changes in the history if those events have been purged - so it always has a sequence.
+	eventSeq := sq.Expr("COALESCE("+
+		"(SELECT MAX(seq) FROM "+blockchaineventsTable+" WHERE namespace = ?), "+
+		"(SELECT MAX(blockchain_event_seq) FROM "+tokenbalancehistoryTable+" WHERE namespace = ?), 0)",
+		change.Namespace, change.Namespace)
+	if change.BlockchainEvent != nil {
+		eventSeq = sq.Expr("(SELECT seq FROM "+blockchaineventsTable+" WHERE id = ?)", change.BlockchainEvent)
+	}
+	change.Sequence, err = s.InsertTx(ctx, tokenbalancehistoryTable, tx,
+		sq.Insert(tokenbalancehistoryTable).
+			Columns(append(tokenBalanceChangeColumns, "blockchain_event_seq")...).
+			Values(
+				change.Pool,
+				change.TokenIndex,
+				change.URI,
+				change.Connector,
+				change.Namespace,
+				change.Key,
+				change.Amount,
+				change.Balance,
+				change.Transfer,
+				change.BlockchainEvent,
+				change.Timestamp,
+				change.Created,
+				eventSeq,
+			),
+		nil,
+	)
+	return err
+}
+
+func (s *SQLCommon) tokenBalanceChangeResult(ctx context.Context, row *sql.Rows) (*core.TokenBalanceChange, error) {
+	change := core.TokenBalanceChange{}
+	err := row.Scan(
+		&change.Sequence,
+		&change.Pool,
+		&change.TokenIndex,
+		&change.URI,
+		&change.Connector,
+		&change.Namespace,
+		&change.Key,
+		&change.Amount,
+		&change.Balance,
+		&change.Transfer,
+		&change.BlockchainEvent,
+		&change.Timestamp,
+		&change.Created,
+		&change.BlockchainEventSequence,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalancehistoryTable)
+	}
+	return &change, nil
+}
+
+func (s *SQLCommon) GetTokenBalanceHistory(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error) {
+	cols := append([]string{"seq"}, tokenBalanceChangeColumns...)
+	cols = append(cols, tokenBalanceChangeEventSeqColumn)
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(tokenbalancehistoryTable),
+		filter, tokenBalanceChangeFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalancehistoryTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	changes := []*core.TokenBalanceChange{}
+	for rows.Next() {
+		change, err := s.tokenBalanceChangeResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, s.QueryRes(ctx, tokenbalancehistoryTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) GetTokenBalancesAt(ctx context.Context, namespace string, point *core.TokenBalancePoint, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	// The balance of each account at the point is the one recorded by its last change up to that point.
+	// The sequence of the point is that of the blockchain event of each change, which orders the changes as
+	// they were confirmed by the blockchain. Opening entries of the history precede every point.
+	latest := sq.Select("MAX(seq)").
+		From(tokenbalancehistoryTable).
+		Where(sq.Eq{"namespace": namespace}).
+		GroupBy("pool_id", "token_index", "key")
+	if point.Timestamp != nil {
+		latest = latest.Where(sq.LtOrEq{"timestamp": point.Timestamp})
+	}
+	if point.Sequence > 0 {
+		latest = latest.Where(sq.Or{sq.Eq{"opening": true}, sq.LtOrEq{"blockchain_event_seq": point.Sequence}})
+	}
+	latestSQL, latestArgs, _ := latest.ToSql()
+
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenBalanceAtColumns...).From(tokenbalancehistoryTable),
+		filter, tokenBalanceAtFilterFieldMap, []interface{}{"seq"}, sq.And{
+			sq.Eq{"namespace": namespace},
+			sq.Expr("seq IN ("+latestSQL+")", latestArgs...),
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalancehistoryTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	balances := []*core.TokenBalance{}
+	for rows.Next() {
+		balance, err := s.tokenBalanceResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, s.QueryRes(ctx, tokenbalancehistoryTable, tx, fop, fi), err
+}
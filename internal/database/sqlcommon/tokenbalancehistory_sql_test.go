@@ -0,0 +1,285 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTokenBalanceHistoryE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t1 := fftypes.UnixTime(1000000000)
+	t2 := fftypes.UnixTime(2000000000)
+	t3 := fftypes.UnixTime(3000000000)
+
+	// Each transfer is confirmed by a blockchain event
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+	newEvent := func(protocolID string) *fftypes.UUID {
+		event := &core.BlockchainEvent{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns1",
+			Name:       "Transfer",
+			ProtocolID: protocolID,
+			Timestamp:  fftypes.Now(),
+		}
+		_, err := s.InsertOrGetBlockchainEvent(ctx, event)
+		assert.NoError(t, err)
+		return event.ID
+	}
+
+	// Mint 10 to 0x0, then transfer 4 to 0x1, then burn 1 from 0x1
+	transfer := &core.TokenTransfer{
+		LocalID:         fftypes.NewUUID(),
+		Pool:            fftypes.NewUUID(),
+		TokenIndex:      "1",
+		Connector:       "erc1155",
+		Namespace:       "ns1",
+		To:              "0x0",
+		Amount:          *fftypes.NewFFBigInt(10),
+		BlockchainEvent: newEvent("000000000001/000000/000000"),
+	}
+	err := s.UpdateTokenBalances(ctx, transfer, t1)
+	assert.NoError(t, err)
+
+	transfer.LocalID = fftypes.NewUUID()
+	transfer.From = "0x0"
+	transfer.To = "0x1"
+	transfer.Amount = *fftypes.NewFFBigInt(4)
+	transfer.BlockchainEvent = newEvent("000000000002/000000/000000")
+	err = s.UpdateTokenBalances(ctx, transfer, t2)
+	assert.NoError(t, err)
+
+	transfer.LocalID = fftypes.NewUUID()
+	transfer.From = "0x1"
+	transfer.To = ""
+	transfer.Amount = *fftypes.NewFFBigInt(1)
+	transfer.BlockchainEvent = newEvent("000000000003/000000/000000")
+	err = s.UpdateTokenBalances(ctx, transfer, t3)
+	assert.NoError(t, err)
+
+	// Query the full history of an account
+	fb := database.TokenBalanceChangeQueryFactory.NewFilter(ctx)
+	changes, res, err := s.GetTokenBalanceHistory(ctx, "ns1", fb.And(
+		fb.Eq("pool", transfer.Pool),
+		fb.Eq("key", "0x1"),
+	).Sort("sequence").Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), *res.TotalCount)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, int64(4), changes[0].Amount.Int().Int64())
+	assert.Equal(t, int64(4), changes[0].Balance.Int().Int64())
+	assert.Equal(t, t2.String(), changes[0].Timestamp.String())
+	assert.Equal(t, int64(-1), changes[1].Amount.Int().Int64())
+	assert.Equal(t, int64(3), changes[1].Balance.Int().Int64())
+	assert.Equal(t, *transfer.LocalID, *changes[1].Transfer)
+	assert.Equal(t, *transfer.BlockchainEvent, *changes[1].BlockchainEvent)
+	assert.Greater(t, changes[1].Sequence, changes[0].Sequence)
+	assert.Greater(t, changes[1].BlockchainEventSequence, changes[0].BlockchainEventSequence)
+
+	// Balances as of the second timestamp
+	bfb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	balances, _, err := s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Timestamp: t2}, bfb.And().Sort("key"))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, "0x0", balances[0].Key)
+	assert.Equal(t, int64(6), balances[0].Balance.Int().Int64())
+	assert.Equal(t, "0x1", balances[1].Key)
+	assert.Equal(t, int64(4), balances[1].Balance.Int().Int64())
+	assert.Equal(t, t2.String(), balances[1].Updated.String())
+
+	// Balances as of the blockchain event before the transfer to 0x1, which is the mint
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: changes[0].BlockchainEventSequence - 1}, bfb.And())
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, "0x0", balances[0].Key)
+	assert.Equal(t, int64(10), balances[0].Balance.Int().Int64())
+
+	// Latest balances, filtered by key
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{}, bfb.And(bfb.Eq("key", "0x1")))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, int64(3), balances[0].Balance.Int().Int64())
+
+	// Nothing before the first timestamp
+	t0 := fftypes.UnixTime(1)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Timestamp: t0}, bfb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, balances)
+
+	// A correction without a blockchain event is ordered after the events recorded so far
+	lastEventSeq := changes[1].BlockchainEventSequence
+	correction := &core.TokenTransfer{
+		Pool:       transfer.Pool,
+		TokenIndex: "1",
+		Connector:  "erc1155",
+		Namespace:  "ns1",
+		To:         "0x1",
+		Amount:     *fftypes.NewFFBigInt(2),
+	}
+	err = s.UpdateTokenBalances(ctx, correction, nil)
+	assert.NoError(t, err)
+	changes, _, err = s.GetTokenBalanceHistory(ctx, "ns1", fb.And(fb.Eq("key", "0x1")).Sort("sequence"))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, lastEventSeq, changes[2].BlockchainEventSequence)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: lastEventSeq - 1}, bfb.And(bfb.Eq("key", "0x1")))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, int64(4), balances[0].Balance.Int().Int64())
+
+	// Points in the sequence still apply once the blockchain events have been purged
+	events, _, err := s.GetBlockchainEvents(ctx, "ns1", database.BlockchainEventQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	eventIDs := make([]*fftypes.UUID, len(events))
+	for i, e := range events {
+		eventIDs[i] = e.ID
+	}
+	err = s.DeleteBlockchainEvents(ctx, "ns1", eventIDs)
+	assert.NoError(t, err)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: changes[0].BlockchainEventSequence}, bfb.And().Sort("key"))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, int64(6), balances[0].Balance.Int().Int64())
+	assert.Equal(t, int64(4), balances[1].Balance.Int().Int64())
+
+	// Opening entries of the history precede every point in the sequence
+	_, err = s.DB().Exec(`INSERT INTO tokenbalancehistory (namespace, pool_id, token_index, uri, connector, key, amount, balance, timestamp, created, opening)
+		VALUES ('ns1', ?, '1', '', 'erc1155', '0x2', '7', '7', 0, 0, true)`, transfer.Pool.String())
+	assert.NoError(t, err)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: 1}, bfb.And(bfb.Eq("key", "0x2")))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, int64(7), balances[0].Balance.Int().Int64())
+
+	// Deleting the balances of the pool removes the history
+	err = s.DeleteTokenBalances(ctx, "ns1", transfer.Pool)
+	assert.NoError(t, err)
+	changes, _, err = s.GetTokenBalanceHistory(ctx, "ns1", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestTokenBalanceHistoryCorrectionSequence(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	pool := fftypes.NewUUID()
+	fb := database.TokenBalanceChangeQueryFactory.NewFilter(ctx)
+	bfb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	correct := func(key string, amount int64) {
+		err := s.UpdateTokenBalances(ctx, &core.TokenTransfer{
+			Pool:       pool,
+			TokenIndex: "1",
+			Connector:  "erc1155",
+			Namespace:  "ns1",
+			To:         key,
+			Amount:     *fftypes.NewFFBigInt(amount),
+		}, nil)
+		assert.NoError(t, err)
+	}
+
+	// A correction before any blockchain event is at the start of the sequence, so is in every point
+	correct("0x0", 5)
+	changes, _, err := s.GetTokenBalanceHistory(ctx, "ns1", fb.And(fb.Eq("key", "0x0")))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, int64(0), changes[0].BlockchainEventSequence)
+	balances, _, err := s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: 1}, bfb.And(bfb.Eq("key", "0x0")))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+	assert.Equal(t, int64(5), balances[0].Balance.Int().Int64())
+
+	// A correction once the blockchain events are purged follows the last change in the history
+	_, err = s.DB().Exec(`UPDATE tokenbalancehistory SET blockchain_event_seq = 42`)
+	assert.NoError(t, err)
+	correct("0x1", 3)
+	changes, _, err = s.GetTokenBalanceHistory(ctx, "ns1", fb.And(fb.Eq("key", "0x1")))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, int64(42), changes[0].BlockchainEventSequence)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: 41}, bfb.And(bfb.Eq("key", "0x1")))
+	assert.NoError(t, err)
+	assert.Empty(t, balances)
+	balances, _, err = s.GetTokenBalancesAt(ctx, "ns1", &core.TokenBalancePoint{Sequence: 42}, bfb.And(bfb.Eq("key", "0x1")))
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+}
+
+func TestGetTokenBalanceHistoryQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBalanceChangeQueryFactory.NewFilter(context.Background()).Eq("pool", "")
+	_, _, err := s.GetTokenBalanceHistory(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceHistoryBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBalanceChangeQueryFactory.NewFilter(context.Background()).Eq("pool", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBalanceHistory(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*pool", err)
+}
+
+func TestGetTokenBalanceHistoryScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow("only one"))
+	f := database.TokenBalanceChangeQueryFactory.NewFilter(context.Background()).Eq("pool", "")
+	_, _, err := s.GetTokenBalanceHistory(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalancesAtQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("pool", "")
+	_, _, err := s.GetTokenBalancesAt(context.Background(), "ns1", &core.TokenBalancePoint{Sequence: 1}, f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalancesAtBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("pool", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBalancesAt(context.Background(), "ns1", &core.TokenBalancePoint{}, f)
+	assert.Regexp(t, "FF00143.*pool", err)
+}
+
+func TestGetTokenBalancesAtScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"pool"}).AddRow("only one"))
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).Eq("pool", "")
+	_, _, err := s.GetTokenBalancesAt(context.Background(), "ns1", &core.TokenBalancePoint{}, f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	tokenBalanceMismatchColumns = []string{
+		"id",
+		"reconciliation_id",
+		"namespace",
+		"pool_id",
+		"token_index",
+		"connector",
+		"key",
+		"local_balance",
+		"chain_balance",
+		"corrected",
+		"created",
+	}
+	tokenBalanceMismatchFilterFieldMap = map[string]string{
+		"reconciliation": "reconciliation_id",
+		"pool":           "pool_id",
+		"tokenindex":     "token_index",
+		"localbalance":   "local_balance",
+		"chainbalance":   "chain_balance",
+	}
+)
+
+const tokenbalancemismatchTable = "tokenbalancemismatch"
+
+func (s *SQLCommon) InsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, tokenbalancemismatchTable, tx,
+		sq.Insert(tokenbalancemismatchTable).
+			Columns(tokenBalanceMismatchColumns...).
+			Values(
+				mismatch.ID,
+				mismatch.Reconciliation,
+				mismatch.Namespace,
+				mismatch.Pool,
+				mismatch.TokenIndex,
+				mismatch.Connector,
+				mismatch.Key,
+				mismatch.LocalBalance,
+				mismatch.ChainBalance,
+				mismatch.Corrected,
+				mismatch.Created,
+			),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenMismatches, core.ChangeEventTypeCreated, mismatch.Namespace, mismatch.ID)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenBalanceMismatchResult(ctx context.Context, row *sql.Rows) (*core.TokenBalanceMismatch, error) {
+	var mismatch core.TokenBalanceMismatch
+	err := row.Scan(
+		&mismatch.ID,
+		&mismatch.Reconciliation,
+		&mismatch.Namespace,
+		&mismatch.Pool,
+		&mismatch.TokenIndex,
+		&mismatch.Connector,
+		&mismatch.Key,
+		&mismatch.LocalBalance,
+		&mismatch.ChainBalance,
+		&mismatch.Corrected,
+		&mismatch.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalancemismatchTable)
+	}
+	return &mismatch, nil
+}
+
+func (s *SQLCommon) GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error) {
+	rows, _, err := s.Query(ctx, tokenbalancemismatchTable,
+		sq.Select(tokenBalanceMismatchColumns...).
+			From(tokenbalancemismatchTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token balance mismatch '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenBalanceMismatchResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(tokenBalanceMismatchColumns...).From(tokenbalancemismatchTable),
+		filter, tokenBalanceMismatchFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalancemismatchTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	mismatches := []*core.TokenBalanceMismatch{}
+	for rows.Next() {
+		mismatch, err := s.tokenBalanceMismatchResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+
+	return mismatches, s.QueryRes(ctx, tokenbalancemismatchTable, tx, fop, fi), err
+}
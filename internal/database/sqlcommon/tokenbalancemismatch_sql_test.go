@@ -0,0 +1,152 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBalanceMismatchesE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Record a new mismatch
+	mismatch := &core.TokenBalanceMismatch{
+		ID:             fftypes.NewUUID(),
+		Reconciliation: fftypes.NewUUID(),
+		Namespace:      "ns1",
+		Pool:           fftypes.NewUUID(),
+		TokenIndex:     "1",
+		Connector:      "erc1155",
+		Key:            "0x0",
+		LocalBalance:   *fftypes.NewFFBigInt(10),
+		ChainBalance:   *fftypes.NewFFBigInt(15),
+		Corrected:      true,
+		Created:        fftypes.Now(),
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenMismatches, core.ChangeEventTypeCreated, "ns1", mismatch.ID).Return()
+
+	err := s.InsertTokenBalanceMismatch(ctx, mismatch)
+	assert.NoError(t, err)
+	mismatchJson, _ := json.Marshal(&mismatch)
+
+	// Query back the mismatch (by ID)
+	mismatchRead, err := s.GetTokenBalanceMismatchByID(ctx, "ns1", mismatch.ID)
+	assert.NoError(t, err)
+	mismatchReadJson, _ := json.Marshal(mismatchRead)
+	assert.Equal(t, string(mismatchJson), string(mismatchReadJson))
+
+	// Query back the mismatch (by query filter)
+	fb := database.TokenBalanceMismatchQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("reconciliation", mismatch.Reconciliation),
+		fb.Eq("pool", mismatch.Pool),
+		fb.Eq("tokenindex", "1"),
+		fb.Eq("corrected", true),
+	)
+	mismatches, res, err := s.GetTokenBalanceMismatches(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mismatches))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	mismatchReadJson, _ = json.Marshal(mismatches[0])
+	assert.Equal(t, string(mismatchJson), string(mismatchReadJson))
+
+	// Not found
+	mismatchRead, err = s.GetTokenBalanceMismatchByID(ctx, "ns2", mismatch.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, mismatchRead)
+}
+
+func TestInsertTokenBalanceMismatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenBalanceMismatchFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenBalanceMismatchFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenBalanceMismatchByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenBalanceMismatchByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestGetTokenBalanceMismatchesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -54,9 +54,11 @@ var (
 		"methods",
 		"published",
 		"plugin_data",
+		"group_hash",
 	}
 	tokenPoolFilterFieldMap = map[string]string{
 		"message":         "message_id",
+		"group":           "group_hash",
 		"tx.type":         "tx_type",
 		"tx.id":           "tx_id",
 		"interfaceformat": "interface_format",
@@ -95,6 +97,7 @@ func (s *SQLCommon) attemptTokenPoolUpdate(ctx context.Context, tx *dbsql.TXWrap
 			Set("methods", pool.Methods).
 			Set("published", pool.Published).
 			Set("plugin_data", pool.PluginData).
+			Set("group_hash", pool.Group).
 			Where(sq.Eq{"id": pool.ID}),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenPools, core.ChangeEventTypeUpdated, pool.Namespace, pool.ID)
@@ -133,6 +136,7 @@ func (s *SQLCommon) setTokenPoolInsertValues(query sq.InsertBuilder, pool *core.
 		pool.Methods,
 		pool.Published,
 		pool.PluginData,
+		pool.Group,
 	)
 }
 
@@ -155,7 +159,7 @@ func (s *SQLCommon) tokenPoolExists(ctx context.Context, tx *dbsql.TXWrapper, po
 			sq.Eq{"namespace": pool.Namespace},
 			sq.Or{
 				sq.Eq{"name": pool.Name},
-				sq.Eq{"network_name": pool.NetworkName},
+				sq.Eq{"network_name": pool.NetworkName, "group_hash": pool.Group},
 			},
 		}),
 	)
@@ -184,7 +188,7 @@ func (s *SQLCommon) InsertOrGetTokenPool(ctx context.Context, pool *core.TokenPo
 		sq.Or{
 			sq.Eq{"id": pool.ID},
 			sq.Eq{"name": pool.Name},
-			sq.Eq{"network_name": pool.NetworkName},
+			sq.Eq{"network_name": pool.NetworkName, "group_hash": pool.Group},
 		},
 	})
 	if queryErr != nil || existing != nil {
@@ -254,6 +258,7 @@ func (s *SQLCommon) tokenPoolResult(ctx context.Context, row *sql.Rows) (*core.T
 		&pool.Methods,
 		&pool.Published,
 		&pool.PluginData,
+		&pool.Group,
 	)
 	if iface.ID != nil {
 		pool.Interface = &iface
@@ -299,8 +304,8 @@ func (s *SQLCommon) GetTokenPoolByID(ctx context.Context, namespace string, id *
 	return s.getTokenPoolPred(ctx, id.String(), sq.Eq{"id": id, "namespace": namespace})
 }
 
-func (s *SQLCommon) GetTokenPoolByNetworkName(ctx context.Context, namespace, networkName string) (*core.TokenPool, error) {
-	return s.getTokenPoolPred(ctx, networkName, sq.Eq{"namespace": namespace, "network_name": networkName})
+func (s *SQLCommon) GetTokenPoolByNetworkName(ctx context.Context, namespace, networkName string, group *fftypes.Bytes32) (*core.TokenPool, error) {
+	return s.getTokenPoolPred(ctx, networkName, sq.Eq{"namespace": namespace, "network_name": networkName, "group_hash": group})
 }
 
 func (s *SQLCommon) GetTokenPools(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.TokenPool, fr *ffapi.FilterResult, err error) {
@@ -91,7 +91,7 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 	assert.Equal(t, string(poolJson), string(poolReadJson))
 
 	// Query back the token pool (by network name)
-	poolRead, err = s.GetTokenPoolByNetworkName(ctx, pool.Namespace, pool.NetworkName)
+	poolRead, err = s.GetTokenPoolByNetworkName(ctx, pool.Namespace, pool.NetworkName, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, poolRead)
 	poolReadJson, _ = json.Marshal(&poolRead)
@@ -135,6 +135,27 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, pool.ID, existing.ID)
 
+	// The same network name can be used within a private group
+	privatePool := &core.TokenPool{
+		ID:          fftypes.NewUUID(),
+		Name:        "my-pool-1",
+		NetworkName: "my-pool",
+		Namespace:   "ns1",
+		Group:       fftypes.NewRandB32(),
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenPools, core.ChangeEventTypeCreated, "ns1", privatePool.ID, mock.Anything).
+		Return().Once()
+	existing, err = s.InsertOrGetTokenPool(ctx, privatePool)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	poolRead, err = s.GetTokenPoolByNetworkName(ctx, "ns1", "my-pool", privatePool.Group)
+	assert.NoError(t, err)
+	assert.Equal(t, privatePool.ID, poolRead.ID)
+	assert.Equal(t, privatePool.Group, poolRead.Group)
+	poolRead, err = s.GetTokenPoolByNetworkName(ctx, "ns1", "my-pool", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, poolRead.ID)
+
 	// Update the token pool
 	pool.Locator = "67890"
 	pool.Type = core.TokenTypeNonFungible
@@ -114,6 +114,13 @@ func TestTokenTransferE2EWithDB(t *testing.T) {
 	transferReadJson, _ = json.Marshal(&transferRead)
 	assert.Equal(t, string(transferJson), string(transferReadJson))
 
+	// Delete the token transfer by ID
+	err = s.DeleteTokenTransfersByID(ctx, "ns1", []*fftypes.UUID{transfer.LocalID})
+	assert.NoError(t, err)
+	transferRead, err = s.GetTokenTransferByID(ctx, "ns1", transfer.LocalID)
+	assert.NoError(t, err)
+	assert.Nil(t, transferRead)
+
 	// Delete the token transfer
 	err = s.DeleteTokenTransfers(ctx, "ns1", transfer.Pool)
 	assert.NoError(t, err)
@@ -236,3 +243,21 @@ func TestDeleteTokenTransfersFailDelete(t *testing.T) {
 	assert.Regexp(t, "FF00179", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteTokenTransfersByIDFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteTokenTransfersByID(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokenTransfersByIDFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteTokenTransfersByID(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -65,9 +65,11 @@ func TestVerifiersE2EWithDB(t *testing.T) {
 	// Update the verifier (this is testing what's possible at the database layer,
 	// and does not account for the verification that happens at the higher level)
 	verifierUpdated := &core.Verifier{
-		Identity:  fftypes.NewUUID(),
-		Created:   verifier.Created,
-		Namespace: "ns1",
+		Identity:        fftypes.NewUUID(),
+		Created:         verifier.Created,
+		Revoked:         fftypes.Now(),
+		RevokedSequence: 12345,
+		Namespace:       "ns1",
 		VerifierRef: core.VerifierRef{
 			Type:  core.VerifierTypeEthAddress,
 			Value: "0x12345",
@@ -88,6 +90,7 @@ func TestVerifiersE2EWithDB(t *testing.T) {
 	fb := database.VerifierQueryFactory.NewFilter(ctx)
 	filter := fb.And(
 		fb.Eq("value", string(verifierUpdated.Value)),
+		fb.Eq("revokedsequence", 12345),
 	)
 	verifierRes, res, err := s.GetVerifiers(ctx, "ns1", filter.Count(true))
 	assert.NoError(t, err)
@@ -21,6 +21,9 @@ package sqlite3
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"database/sql"
 
@@ -89,6 +92,35 @@ func (sqlite *SQLite3) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, r
 	return insert, false
 }
 
+// JSONExtract uses json_extract, with booleans mapped to text in the same way as PostgreSQL
+// (json_extract returns them as integers). Numeric path segments index into arrays.
+func (sqlite *SQLite3) JSONExtract(column string, path []string) string {
+	jsonPath := jsonPath(path)
+	return fmt.Sprintf("(CASE json_type(%s, '%s') WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(%s, '%s') AS TEXT) END)",
+		column, jsonPath, column, jsonPath)
+}
+
+// JSONExtractNumber returns the value at the path only when it is a JSON number, which json_extract
+// returns as an integer or real for numeric comparison
+func (sqlite *SQLite3) JSONExtractNumber(column string, path []string) string {
+	jsonPath := jsonPath(path)
+	return fmt.Sprintf("(CASE WHEN json_type(%s, '%s') IN ('integer', 'real') THEN json_extract(%s, '%s') END)",
+		column, jsonPath, column, jsonPath)
+}
+
+func jsonPath(path []string) string {
+	buff := strings.Builder{}
+	buff.WriteString("$")
+	for _, segment := range path {
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			buff.WriteString("[" + segment + "]")
+		} else {
+			buff.WriteString(`."` + segment + `"`)
+		}
+	}
+	return buff.String()
+}
+
 func (sqlite *SQLite3) Open(url string) (*sql.DB, error) {
 	return sql.Open("sqlite3_ff", url)
 }
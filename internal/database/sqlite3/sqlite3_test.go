@@ -61,3 +61,17 @@ func TestSQLite3GoProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)", sql)
 	assert.False(t, query)
 }
+
+func TestSQLite3JSONExtract(t *testing.T) {
+	sqlite := &SQLite3{}
+	assert.Equal(t,
+		`(CASE json_type(value, '$."lines"[0]."id"') WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(value, '$."lines"[0]."id"') AS TEXT) END)`,
+		sqlite.JSONExtract("value", []string{"lines", "0", "id"}))
+}
+
+func TestSQLite3JSONExtractNumber(t *testing.T) {
+	sqlite := &SQLite3{}
+	assert.Equal(t,
+		`(CASE WHEN json_type(value, '$."lines"[0]."qty"') IN ('integer', 'real') THEN json_extract(value, '$."lines"[0]."qty"') END)`,
+		sqlite.JSONExtractNumber("value", []string{"lines", "0", "qty"}))
+}
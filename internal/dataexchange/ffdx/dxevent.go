@@ -165,10 +165,10 @@ func (h *FFDX) dispatchEvent(msg *wsEvent) {
 		switch {
 		case err != nil:
 			err = fmt.Errorf("invalid transmission from peer '%s': %s", msg.Sender, err)
-		case wrapper.Batch == nil:
+		case wrapper.Batch == nil && wrapper.Encrypted == nil:
 			err = fmt.Errorf("invalid transmission from peer '%s': nil batch", msg.Sender)
 		default:
-			namespace = wrapper.Batch.Namespace
+			namespace = wrapper.Namespace()
 			e.dxType = dataexchange.DXEventTypeMessageReceived
 			e.messageReceived = &dataexchange.MessageReceived{
 				PeerID:    msg.Sender,
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 
@@ -393,6 +394,43 @@ func (h *FFDX) DownloadBlob(ctx context.Context, payloadRef string) (content io.
 	return res.RawBody(), nil
 }
 
+// rangeReader is a window of a blob being downloaded
+type rangeReader struct {
+	io.Reader
+	io.Closer
+}
+
+// DownloadBlobRange requests the range with an HTTP Range header. A data exchange that does not support ranges
+// returns the whole blob, in which case the content before the range is skipped.
+func (h *FFDX) DownloadBlobRange(ctx context.Context, payloadRef string, offset, length int64) (content io.ReadCloser, err error) {
+	rangeHeader := fmt.Sprintf("bytes=%d-", offset)
+	if length >= 0 {
+		rangeHeader = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+	res, err := h.client.R().SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader("Range", rangeHeader).
+		Get(fmt.Sprintf("/api/v1/blobs/%s", payloadRef))
+	ffresty.OnAfterResponse(h.client, res) // required using SetDoNotParseResponse
+	if err != nil || !res.IsSuccess() {
+		if err == nil {
+			_ = res.RawBody().Close()
+		}
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+	}
+	body := res.RawBody()
+	if res.StatusCode() != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			_ = body.Close()
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+		}
+	}
+	if length < 0 {
+		return body, nil
+	}
+	return &rangeReader{Reader: io.LimitReader(body, length), Closer: body}, nil
+}
+
 func (h *FFDX) DeleteBlob(ctx context.Context, payloadRef string) (err error) {
 	res, err := h.client.R().SetContext(ctx).
 		SetDoNotParseResponse(true).
@@ -101,6 +101,13 @@ func TestSplitBlobPath(t *testing.T) {
 	assert.Equal(t, "/root/test", prefix)
 	assert.Equal(t, "ns1", namespace)
 	assert.Equal(t, "123", id)
+
+	// A copy staged for a transfer is received with the same namespace and ID as the original
+	transferID := fftypes.NewUUID()
+	prefix, namespace, id = splitBlobPath("peer1/" + joinBlobPath(dataexchange.StagedBlobNamespace("ns1", transferID), "123"))
+	assert.Equal(t, "peer1/staged/"+transferID.String(), prefix)
+	assert.Equal(t, "ns1", namespace)
+	assert.Equal(t, "123", id)
 }
 
 func TestJoinBlobPath(t *testing.T) {
@@ -334,6 +341,60 @@ func TestDownloadBlob(t *testing.T) {
 	assert.Equal(t, `some data`, string(b))
 }
 
+func TestDownloadBlobRange(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "bytes=2-5", req.Header.Get("Range"))
+			return httpmock.NewBytesResponse(206, []byte(`me dat`)), nil
+		})
+
+	rc, err := h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 2, 4)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, `me d`, string(b))
+}
+
+func TestDownloadBlobRangeNotSupported(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Regexp(t, "^bytes=[0-9]+-$", req.Header.Get("Range"))
+			return httpmock.NewBytesResponse(200, []byte(`some data`)), nil
+		})
+
+	rc, err := h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 5, -1)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, `data`, string(b))
+
+	_, err = h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 100, -1)
+	assert.Regexp(t, "FF10217", err)
+}
+
+func TestDownloadBlobRangeError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/bad", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.DownloadBlobRange(context.Background(), "bad", 0, 10)
+	assert.Regexp(t, "FF10229", err)
+}
+
 func TestDownloadBlobError(t *testing.T) {
 	h, _, _, httpURL, done := newTestFFDX(t, false)
 	defer done()
@@ -593,6 +654,15 @@ func TestMessageEvents(t *testing.T) {
 	msg = <-toServer
 	assert.Equal(t, `{"action":"ack","id":"4","manifest":"{\"manifest\":true}"}`, string(msg))
 
+	mcb.On("DXEvent", h, mock.MatchedBy(func(ev dataexchange.DXEvent) bool {
+		return ev.EventID() == "5" &&
+			ev.Type() == dataexchange.DXEventTypeMessageReceived &&
+			ev.MessageReceived().Transport.Encrypted.Namespace == "ns1"
+	})).Run(manifestAcker(``)).Return(nil)
+	fromServer <- `{"id":"5","type":"message-received","sender":"peer2","recipient":"peer1","message":"{\"encrypted\":{\"namespace\":\"ns1\"}}"}`
+	msg = <-toServer
+	assert.Equal(t, `{"action":"ack","id":"5"}`, string(msg))
+
 	h.SetHandler("ns1", "node1", nil)
 	assert.Empty(t, h.callbacks.handlers)
 	h.SetOperationHandler("ns1", nil)
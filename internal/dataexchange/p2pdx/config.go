@@ -0,0 +1,81 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// P2PConfEndpoint is the URL advertised to peers for connecting to this node. Defaults to the listen address
+	P2PConfEndpoint = "endpoint"
+	// P2PConfServerAddress is the local address to listen on for connections from peers
+	P2PConfServerAddress = "server.address"
+	// P2PConfServerPort is the local port to listen on for connections from peers
+	P2PConfServerPort = "server.port"
+	// P2PConfTLSCertFile is the certificate presented to peers, as both a server and a client
+	P2PConfTLSCertFile = "tls.certFile"
+	// P2PConfTLSKeyFile is the private key for the certificate
+	P2PConfTLSKeyFile = "tls.keyFile"
+	// P2PConfStoragePath is the directory used to store blobs, and the queue of outbound deliveries
+	P2PConfStoragePath = "storage.path"
+	// P2PConfManifestEnabled determines whether to require+validate a manifest from the receiving node
+	P2PConfManifestEnabled = "manifestEnabled"
+	// P2PConfAckTimeout is how long an inbound request waits for the event to be processed, before the peer must retry
+	P2PConfAckTimeout = "ackTimeout"
+	// P2PConfDeliveredRetention is how long the record of a completed inbound delivery is kept, to recognize retries of it
+	P2PConfDeliveredRetention = "deliveredRetention"
+
+	P2PConfOutboundRequestTimeout    = "outbound.requestTimeout"
+	P2PConfOutboundBlobTimeout       = "outbound.blobTimeout"
+	P2PConfOutboundRetryInitialDelay = "outbound.retry.initialDelay"
+	P2PConfOutboundRetryMaxDelay     = "outbound.retry.maxDelay"
+	P2PConfOutboundRetryFactor       = "outbound.retry.factor"
+	P2PConfEventRetryInitialDelay    = "eventRetry.initialDelay"
+	P2PConfEventRetryMaxDelay        = "eventRetry.maxDelay"
+	P2PConfEventRetryFactor          = "eventRetry.factor"
+	defaultServerPort                = 5443
+	defaultAckTimeout                = "30s"
+	defaultDeliveredRetention        = "24h"
+	defaultOutboundRequestTimeout    = "2m"
+	defaultOutboundBlobTimeout       = "1h"
+	defaultOutboundRetryInitialDelay = "250ms"
+	defaultOutboundRetryMaxDelay     = "1m"
+	defaultOutboundRetryFactor       = 2.0
+)
+
+func (p *P2PDX) InitConfig(config config.Section) {
+	config.AddKnownKey(P2PConfEndpoint)
+	config.AddKnownKey(P2PConfServerAddress, "0.0.0.0")
+	config.AddKnownKey(P2PConfServerPort, defaultServerPort)
+	config.AddKnownKey(P2PConfTLSCertFile)
+	config.AddKnownKey(P2PConfTLSKeyFile)
+	config.AddKnownKey(P2PConfStoragePath)
+	config.AddKnownKey(P2PConfManifestEnabled, true)
+	config.AddKnownKey(P2PConfAckTimeout, defaultAckTimeout)
+	config.AddKnownKey(P2PConfDeliveredRetention, defaultDeliveredRetention)
+	config.AddKnownKey(P2PConfOutboundRequestTimeout, defaultOutboundRequestTimeout)
+	config.AddKnownKey(P2PConfOutboundBlobTimeout, defaultOutboundBlobTimeout)
+	config.AddKnownKey(P2PConfOutboundRetryInitialDelay, defaultOutboundRetryInitialDelay)
+	config.AddKnownKey(P2PConfOutboundRetryMaxDelay, defaultOutboundRetryMaxDelay)
+	config.AddKnownKey(P2PConfOutboundRetryFactor, defaultOutboundRetryFactor)
+	config.AddKnownKey(P2PConfEventRetryInitialDelay, 50*time.Millisecond)
+	config.AddKnownKey(P2PConfEventRetryMaxDelay, 30*time.Second)
+	config.AddKnownKey(P2PConfEventRetryFactor, 2.0)
+}
@@ -0,0 +1,203 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// delivery tracks an event received from a peer, from when it first arrives until FireFly acks it.
+// Retries of the same delivery from the sender wait on the same event, rather than dispatching a new one.
+type delivery struct {
+	key        string
+	event      *dxEvent
+	result     *deliveryResult
+	waiters    int
+	dispatched bool
+	cancel     context.CancelFunc
+}
+
+// findDelivery returns the delivery in flight for a key, or the result of one that already completed
+func (p *P2PDX) findDelivery(key string) (*delivery, *deliveryResult) {
+	p.deliveriesMutex.Lock()
+	defer p.deliveriesMutex.Unlock()
+	return p.joinDeliveryLocked(key)
+}
+
+func (p *P2PDX) joinDeliveryLocked(key string) (*delivery, *deliveryResult) {
+	if result := p.getDelivered(key); result != nil {
+		return nil, result
+	}
+	d, ok := p.deliveries[key]
+	if ok {
+		d.waiters++
+	}
+	return d, nil
+}
+
+// startDelivery dispatches a new event to FireFly, unless a delivery with the same key was started
+// while the event was being prepared - in which case that one is joined instead
+func (p *P2PDX) startDelivery(key, namespace, recipient string, e *dxEvent, result *deliveryResult) (*delivery, *deliveryResult) {
+	p.deliveriesMutex.Lock()
+	defer p.deliveriesMutex.Unlock()
+	if d, completed := p.joinDeliveryLocked(key); d != nil || completed != nil {
+		return d, completed
+	}
+
+	d := &delivery{
+		key:     key,
+		event:   e,
+		result:  result,
+		waiters: 1,
+	}
+	var ctx context.Context
+	ctx, d.cancel = context.WithCancel(p.ctx)
+	e.acked = make(chan struct{})
+	e.onAck = func(manifest string) { p.deliveryAcked(d, manifest) }
+	p.deliveries[key] = d
+	go p.callbackWithRetry(ctx, namespace, recipient, d)
+	return d, nil
+}
+
+func (p *P2PDX) callbackWithRetry(ctx context.Context, namespace, recipient string, d *delivery) {
+	err := p.eventRetry.Do(ctx, "dispatch p2p dx event", func(attempt int) (retry bool, err error) {
+		// Return until success, or the context closes.
+		return true, p.callbacks.DXEvent(ctx, namespace, recipient, d.event)
+	})
+	if err == nil {
+		p.deliveriesMutex.Lock()
+		d.dispatched = true
+		p.deliveriesMutex.Unlock()
+	}
+}
+
+// waitForAck waits for FireFly to ack a delivery, so the sender knows the delivery is complete.
+// If the wait times out, the sender retries the delivery.
+func (p *P2PDX) waitForAck(ctx context.Context, d *delivery) (*deliveryResult, error) {
+	timer := time.NewTimer(p.ackTimeout)
+	defer timer.Stop()
+	select {
+	case <-d.event.acked:
+		return d.result, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	p.abandonDelivery(d)
+	return nil, i18n.NewError(ctx, coremsgs.MsgP2PDXAckTimeout)
+}
+
+// abandonDelivery is called when a request stops waiting for a delivery. If FireFly has not yet accepted
+// the event, and no other request is waiting, the dispatch is cancelled - so nothing carries on
+// redelivering an event the sender has given up on. An event FireFly has accepted is left to be acked,
+// so that the retry from the sender gets the result without the event being passed to FireFly twice.
+func (p *P2PDX) abandonDelivery(d *delivery) {
+	p.deliveriesMutex.Lock()
+	defer p.deliveriesMutex.Unlock()
+	d.waiters--
+	if d.waiters == 0 && !d.dispatched {
+		d.cancel()
+		if p.deliveries[d.key] == d {
+			delete(p.deliveries, d.key)
+		}
+	}
+}
+
+func (p *P2PDX) deliveryAcked(d *delivery, manifest string) {
+	p.deliveriesMutex.Lock()
+	defer p.deliveriesMutex.Unlock()
+	d.result.Manifest = manifest
+	d.cancel()
+	if p.deliveries[d.key] == d {
+		delete(p.deliveries, d.key)
+	}
+	p.recordDelivered(d.key, d.result)
+}
+
+// deliveredPath returns the location on disk of the record of a completed delivery. Completed deliveries are
+// stored, so that a delivery the sender retries after missing the response - including after either node
+// restarts - is answered without the event going to FireFly again.
+func (p *P2PDX) deliveredPath(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(p.storagePath, "delivered", hex.EncodeToString(hash[:])+".json")
+}
+
+func (p *P2PDX) getDelivered(key string) *deliveryResult {
+	resultBytes, err := os.ReadFile(p.deliveredPath(key))
+	if err != nil {
+		return nil
+	}
+	var result deliveryResult
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return nil
+	}
+	return &result
+}
+
+func (p *P2PDX) recordDelivered(key string, result *deliveryResult) {
+	path := p.deliveredPath(key)
+	resultBytes, _ := json.Marshal(result)
+	// Write to a hidden file first, so a partial record is never read
+	tmpName := filepath.Join(filepath.Dir(path), "."+filepath.Base(path))
+	err := os.WriteFile(tmpName, resultBytes, 0600)
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		// The delivery is complete, but a retry from the sender will be passed to FireFly again
+		log.L(p.ctx).Errorf("Failed to record completed DX delivery %s: %s", key, err)
+	}
+}
+
+// pruneDelivered removes the records of deliveries completed longer ago than the sender could still be retrying them
+func (p *P2PDX) pruneDelivered() {
+	dir := filepath.Join(p.storagePath, "delivered")
+	entries, _ := os.ReadDir(dir)
+	cutoff := time.Now().Add(-p.deliveredRetention)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil && !strings.HasPrefix(entry.Name(), ".") && info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+func (p *P2PDX) deliveredPruner() {
+	if p.deliveredRetention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.deliveredRetention / 2)
+	defer ticker.Stop()
+	for {
+		p.pruneDelivered()
+		select {
+		case <-ticker.C:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"sync"
+
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+)
+
+type dxEvent struct {
+	id                  string
+	dxType              dataexchange.DXEventType
+	messageReceived     *dataexchange.MessageReceived
+	privateBlobReceived *dataexchange.PrivateBlobReceived
+	ackOnce             sync.Once
+	acked               chan struct{}
+	onAck               func(manifest string)
+}
+
+func (e *dxEvent) EventID() string {
+	return e.id
+}
+
+func (e *dxEvent) Type() dataexchange.DXEventType {
+	return e.dxType
+}
+
+func (e *dxEvent) AckWithManifest(manifest string) {
+	// Only the first ack is returned to the sending peer
+	e.ackOnce.Do(func() {
+		if e.onAck != nil {
+			e.onAck(manifest)
+		}
+		close(e.acked)
+	})
+}
+
+func (e *dxEvent) Ack() {
+	e.AckWithManifest("")
+}
+
+func (e *dxEvent) MessageReceived() *dataexchange.MessageReceived {
+	return e.messageReceived
+}
+
+func (e *dxEvent) PrivateBlobReceived() *dataexchange.PrivateBlobReceived {
+	return e.privateBlobReceived
+}
@@ -0,0 +1,309 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// expectContinueTimeout is how long a blob transfer waits for the peer to ask for the content, before sending it anyway
+const expectContinueTimeout = 5 * time.Second
+
+// outboundEntry is a message or blob transfer, stored in the queue for a peer until it has been delivered.
+// The delivery ID is sent with every attempt, so the peer can recognize a retry of a delivery it has seen.
+// It is the ID of the operation, so the same delivery is recognized if the operation is submitted again.
+type outboundEntry struct {
+	NSOpID     string             `json:"nsOpId"`
+	DeliveryID *fftypes.UUID      `json:"deliveryId,omitempty"`
+	Recipient  fftypes.JSONObject `json:"recipient"`
+	Sender     string             `json:"sender"`
+	Message    string             `json:"message,omitempty"`
+	BlobRef    string             `json:"blobRef,omitempty"`
+}
+
+// queueDir returns the directory for the queue of deliveries to a peer
+func (p *P2PDX) queueDir(peerID string) string {
+	hash := sha256.Sum256([]byte(peerID))
+	return filepath.Join(p.storagePath, "outbound", hex.EncodeToString(hash[:]))
+}
+
+// nextSeq returns an increasing sequence number, used to order the entries in the queues across restarts
+func (p *P2PDX) nextSeq() int64 {
+	for {
+		last := atomic.LoadInt64(&p.lastSeq)
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&p.lastSeq, last, next) {
+			return next
+		}
+	}
+}
+
+// operationDeliveryID returns the delivery ID for an operation
+func operationDeliveryID(ctx context.Context, nsOpID string) *fftypes.UUID {
+	if _, opID, err := core.ParseNamespacedOpID(ctx, nsOpID); err == nil {
+		return opID
+	}
+	return fftypes.NewUUID()
+}
+
+func (p *P2PDX) enqueue(ctx context.Context, entry *outboundEntry) error {
+	dir := p.queueDir(entry.Recipient.GetString("id"))
+	entry.DeliveryID = operationDeliveryID(ctx, entry.NSOpID)
+	entryBytes, _ := json.Marshal(entry)
+	name := fmt.Sprintf("%020d.json", p.nextSeq())
+	// Write to a hidden file first, so the worker never reads a partial entry
+	tmpName := filepath.Join(dir, "."+name)
+	err := os.MkdirAll(dir, 0755)
+	if err == nil {
+		err = os.WriteFile(tmpName, entryBytes, 0600)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, filepath.Join(dir, name))
+	}
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	log.L(ctx).Debugf("Queued DX delivery %s to peer '%s'", entry.NSOpID, entry.Recipient.GetString("id"))
+	p.kickWorker(dir)
+	return nil
+}
+
+// kickWorker ensures there is a worker running for a queue, and notifies it of new entries
+func (p *P2PDX) kickWorker(dir string) {
+	p.workersMutex.Lock()
+	defer p.workersMutex.Unlock()
+	if !p.started {
+		return
+	}
+	kick, ok := p.workers[dir]
+	if !ok {
+		kick = make(chan struct{}, 1)
+		p.workers[dir] = kick
+		go p.outboundWorker(dir, kick)
+	}
+	select {
+	case kick <- struct{}{}:
+	default:
+	}
+}
+
+// startOutbound starts a worker for every queue persisted before a restart
+func (p *P2PDX) startOutbound() error {
+	p.workersMutex.Lock()
+	p.started = true
+	p.workersMutex.Unlock()
+
+	outboundDir := filepath.Join(p.storagePath, "outbound")
+	dirs, err := os.ReadDir(outboundDir)
+	if err != nil {
+		return i18n.WrapError(p.ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	for _, dir := range dirs {
+		p.kickWorker(filepath.Join(outboundDir, dir.Name()))
+	}
+	return nil
+}
+
+func nextOutbound(dir string) (string, bool) {
+	entries, _ := os.ReadDir(dir) // sorted by name, so in sequence order
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			return filepath.Join(dir, entry.Name()), true
+		}
+	}
+	return "", false
+}
+
+func (p *P2PDX) outboundWorker(dir string, kick chan struct{}) {
+	for {
+		for file, ok := nextOutbound(dir); ok; file, ok = nextOutbound(dir) {
+			if !p.processOutbound(file) {
+				return
+			}
+		}
+		select {
+		case <-kick:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// processOutbound delivers one entry from a queue, retrying until it succeeds or fails with an error that
+// cannot be retried, and then removes it from the queue. Returns false if the plugin is shutting down.
+func (p *P2PDX) processOutbound(file string) bool {
+	var entry outboundEntry
+	entryBytes, err := os.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(entryBytes, &entry)
+	}
+	if err != nil {
+		log.L(p.ctx).Errorf("Discarding invalid DX queue entry %s: %s", file, err)
+		_ = os.Remove(file)
+		return true
+	}
+	if entry.DeliveryID == nil {
+		// Queued before delivery IDs were assigned
+		entry.DeliveryID = operationDeliveryID(p.ctx, entry.NSOpID)
+	}
+
+	var result *deliveryResult
+	err = p.outboundRetry.Do(p.ctx, "deliver to DX peer", func(attempt int) (retry bool, err error) {
+		result, retry, err = p.deliver(&entry)
+		return retry, err
+	})
+	if err != nil && p.ctx.Err() != nil {
+		return false // the entry will be delivered after a restart
+	}
+
+	update := &core.OperationUpdate{
+		Plugin:         p.Name(),
+		NamespacedOpID: entry.NSOpID,
+	}
+	if err != nil {
+		update.Status = core.OpStatusFailed
+		update.ErrorMessage = err.Error()
+	} else {
+		update.Status = core.OpStatusSucceeded
+		update.VerifyManifest = p.capabilities.Manifest
+		update.DXManifest = result.Manifest
+		update.DXHash = result.Hash
+	}
+	done := make(chan struct{})
+	update.OnComplete = func() { close(done) }
+	p.callbacks.OperationUpdate(p.ctx, update)
+	select {
+	case <-done:
+	case <-p.ctx.Done():
+		return false
+	}
+	_ = os.Remove(file)
+	return true
+}
+
+// peerClient returns an HTTP client that presents our certificate, and only accepts the pinned certificate of the peer
+func (p *P2PDX) peerClient(fingerprint string) *http.Client {
+	p.nodesMutex.Lock()
+	defer p.nodesMutex.Unlock()
+	client, ok := p.clients[fingerprint]
+	if !ok {
+		// Timeouts are set on each request, as blob transfers need longer than messages
+		client = &http.Client{
+			Transport: &http.Transport{
+				// Blob transfers wait for the peer before sending the content, so a peer
+				// that already has the blob from an earlier attempt does not receive it again
+				ExpectContinueTimeout: expectContinueTimeout,
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{p.cert},
+					MinVersion:   tls.VersionTLS12,
+					// The standard verification is replaced by the check of the pinned certificate below
+					InsecureSkipVerify: true, //nolint:gosec
+					VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+						if len(rawCerts) == 0 || certFingerprint(rawCerts[0]) != fingerprint {
+							return i18n.NewError(p.ctx, coremsgs.MsgP2PDXUnauthorizedPeer, fingerprint)
+						}
+						return nil
+					},
+				},
+			},
+		}
+		p.clients[fingerprint] = client
+	}
+	return client
+}
+
+// deliver makes a single attempt to deliver an entry to the peer, returning whether a failure can be retried
+func (p *P2PDX) deliver(entry *outboundEntry) (result *deliveryResult, retry bool, err error) {
+	peer, err := parsePeer(p.ctx, entry.Recipient)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var req *http.Request
+	if entry.BlobRef != "" {
+		ctx, cancel := context.WithTimeout(p.ctx, p.blobTimeout)
+		defer cancel()
+		blob, err := p.DownloadBlob(ctx, entry.BlobRef)
+		if err != nil {
+			return nil, false, err
+		}
+		defer blob.Close()
+		req, err = http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v1/blobs/%s", peer.endpoint, transferPath(entry.BlobRef)), blob)
+		if err != nil {
+			return nil, false, err
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Expect", "100-continue")
+	} else {
+		ctx, cancel := context.WithTimeout(p.ctx, p.requestTimeout)
+		defer cancel()
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/messages", peer.endpoint), bytes.NewReader([]byte(entry.Message)))
+		if err != nil {
+			return nil, false, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(headerSender, entry.Sender)
+	req.Header.Set(headerRecipient, peer.id)
+	if entry.DeliveryID != nil {
+		req.Header.Set(headerDeliveryID, entry.DeliveryID.String())
+	}
+
+	res, err := p.peerClient(peer.fingerprint).Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, true, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		// Server errors and timeouts are retried, as the peer might be unavailable or not yet ready
+		retry = res.StatusCode >= 500 || res.StatusCode == http.StatusRequestTimeout || res.StatusCode == http.StatusTooManyRequests
+		return nil, retry, i18n.NewError(p.ctx, coremsgs.MsgP2PDXDeliveryFailed, peer.id, res.StatusCode, body)
+	}
+	result = &deliveryResult{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, true, err
+	}
+	log.L(p.ctx).Infof("Delivered %s to DX peer '%s'", entry.NSOpID, peer.id)
+	return result, false, nil
+}
@@ -0,0 +1,260 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func writeTestEntry(t *testing.T, p *P2PDX, peerID string, entry *outboundEntry) string {
+	dir := p.queueDir(peerID)
+	err := os.MkdirAll(dir, 0755)
+	assert.NoError(t, err)
+	file := filepath.Join(dir, "00000000000000000001.json")
+	b, err := json.Marshal(entry)
+	assert.NoError(t, err)
+	err = os.WriteFile(file, b, 0644)
+	assert.NoError(t, err)
+	return file
+}
+
+func TestNextSeqIncreasing(t *testing.T) {
+	p := &P2PDX{lastSeq: time.Now().Add(1 * time.Hour).UnixNano()}
+	first := p.nextSeq()
+	assert.Greater(t, p.nextSeq(), first)
+}
+
+func TestEnqueueStorageFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	outboundDir := filepath.Join(tn.a.storagePath, "outbound")
+	err := os.RemoveAll(outboundDir)
+	assert.NoError(t, err)
+	err = os.WriteFile(outboundDir, []byte{}, 0644)
+	assert.NoError(t, err)
+
+	err = tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, []byte("{}"))
+	assert.Regexp(t, "FF10482", err)
+	err = tn.a.Start()
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestProcessOutboundInvalidEntry(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	file := writeTestEntry(t, tn.a, "peer1", nil)
+	err := os.WriteFile(file, []byte("!json"), 0644)
+	assert.NoError(t, err)
+	assert.True(t, tn.a.processOutbound(file))
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestProcessOutboundInvalidRecipient(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	file := writeTestEntry(t, tn.a, "peer1", &outboundEntry{
+		NSOpID:    "ns1:" + fftypes.NewUUID().String(),
+		Recipient: fftypes.JSONObject{"id": "peer1"},
+		Message:   "{}",
+	})
+	assert.True(t, tn.a.processOutbound(file))
+	update := <-updates
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Regexp(t, "FF10483", update.ErrorMessage)
+}
+
+func TestProcessOutboundBadEndpoint(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	payloadRef, _, _, err := tn.a.UploadBlob(context.Background(), "ns1", *fftypes.NewUUID(), bytes.NewReader([]byte("data")))
+	assert.NoError(t, err)
+	updates := captureOpUpdates(tn.a)
+	peer := fftypes.JSONObject{"id": "peer1", "endpoint": "https://bad\x7f", "cert": tn.peerB["cert"]}
+	for _, entry := range []*outboundEntry{
+		{NSOpID: "ns1:" + fftypes.NewUUID().String(), Recipient: peer, Message: "{}"},
+		{NSOpID: "ns1:" + fftypes.NewUUID().String(), Recipient: peer, BlobRef: payloadRef},
+	} {
+		file := writeTestEntry(t, tn.a, "peer1", entry)
+		assert.True(t, tn.a.processOutbound(file))
+		update := <-updates
+		assert.Equal(t, core.OpStatusFailed, update.Status)
+		assert.Regexp(t, "invalid control character", update.ErrorMessage)
+	}
+}
+
+func TestProcessOutboundCancelledDuringRetry(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	assert.NoError(t, tn.b.Start())
+
+	// Node B presents a certificate that does not match the one pinned for the peer, so delivery is retried
+	peer := fftypes.JSONObject{"id": tn.b.GetPeerID(tn.peerB), "endpoint": tn.peerB["endpoint"], "cert": tn.peerA["cert"]}
+	file := writeTestEntry(t, tn.a, "peer1", &outboundEntry{
+		NSOpID:    "ns1:" + fftypes.NewUUID().String(),
+		Recipient: peer,
+		Message:   "{}",
+	})
+	result := make(chan bool)
+	go func() {
+		result <- tn.a.processOutbound(file)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	tn.a.cancelCtx()
+	assert.False(t, <-result)
+	_, err := os.Stat(file)
+	assert.NoError(t, err)
+}
+
+func TestProcessOutboundCancelledBeforeComplete(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	captureDXEvents(tn.b, "")
+	assert.NoError(t, tn.b.Start())
+
+	updates := make(chan *core.OperationUpdate, 1)
+	mcb := &coremocks.OperationCallbacks{}
+	mcb.On("OperationUpdate", mock.Anything).Run(func(args mock.Arguments) {
+		updates <- args[0].(*core.OperationUpdate)
+	})
+	tn.a.SetOperationHandler("ns1", mcb)
+
+	_, data := testTransportWrapper(t)
+	file := writeTestEntry(t, tn.a, tn.b.GetPeerID(tn.peerB), &outboundEntry{
+		NSOpID:    "ns1:" + fftypes.NewUUID().String(),
+		Recipient: tn.peerB,
+		Sender:    tn.a.GetPeerID(tn.peerA),
+		Message:   string(data),
+	})
+	result := make(chan bool)
+	go func() {
+		result <- tn.a.processOutbound(file)
+	}()
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+	tn.a.cancelCtx()
+	assert.False(t, <-result)
+}
+
+func TestDeliverBadResponses(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("!json"))
+	}))
+	defer server.Close()
+
+	entry := &outboundEntry{
+		NSOpID: "ns1:" + fftypes.NewUUID().String(),
+		Recipient: fftypes.JSONObject{
+			"id":       "peer1",
+			"endpoint": server.URL,
+			"cert":     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		},
+		Message: "{}",
+	}
+	_, retry, err := tn.a.deliver(entry)
+	assert.True(t, retry)
+	assert.Error(t, err)
+
+	status = http.StatusBadRequest
+	_, retry, err = tn.a.deliver(entry)
+	assert.False(t, retry)
+	assert.Regexp(t, "FF10484.*400", err)
+
+	status = http.StatusTooManyRequests
+	_, retry, err = tn.a.deliver(entry)
+	assert.True(t, retry)
+	assert.Regexp(t, "FF10484.*429", err)
+}
+
+func TestEnqueueDeliveryIDFromOperation(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	opID := fftypes.NewUUID()
+	err := tn.a.SendMessage(context.Background(), "ns1:"+opID.String(), tn.peerB, tn.peerA, []byte("{}"))
+	assert.NoError(t, err)
+	err = tn.a.SendMessage(context.Background(), "bad", tn.peerB, tn.peerA, []byte("{}"))
+	assert.NoError(t, err)
+
+	var entries []*outboundEntry
+	dir := tn.a.queueDir(tn.b.GetPeerID(tn.peerB))
+	for file, ok := nextOutbound(dir); ok; file, ok = nextOutbound(dir) {
+		var entry outboundEntry
+		b, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &entry))
+		entries = append(entries, &entry)
+		assert.NoError(t, os.Remove(file))
+	}
+	assert.Len(t, entries, 2)
+	assert.Equal(t, opID, entries[0].DeliveryID)
+	assert.NotNil(t, entries[1].DeliveryID)
+	assert.NotEqual(t, opID, entries[1].DeliveryID)
+}
+
+func TestDeliverBlobTimeout(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	payloadRef, _, _, err := tn.a.UploadBlob(context.Background(), "ns1", *fftypes.NewUUID(), bytes.NewReader([]byte("data")))
+	assert.NoError(t, err)
+	recipient := fftypes.JSONObject{
+		"id":       "peer1",
+		"endpoint": server.URL,
+		"cert":     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+	}
+
+	// Messages are subject to the request timeout, and blobs to the longer blob timeout
+	tn.a.requestTimeout = 10 * time.Millisecond
+	tn.a.blobTimeout = 5 * time.Second
+	_, retry, err := tn.a.deliver(&outboundEntry{NSOpID: "ns1:" + fftypes.NewUUID().String(), Recipient: recipient, Message: "{}"})
+	assert.True(t, retry)
+	assert.Regexp(t, "deadline", err)
+	_, _, err = tn.a.deliver(&outboundEntry{NSOpID: "ns1:" + fftypes.NewUUID().String(), Recipient: recipient, BlobRef: payloadRef})
+	assert.NoError(t, err)
+}
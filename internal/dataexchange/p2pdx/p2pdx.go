@@ -0,0 +1,453 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+)
+
+// P2PDX is a data exchange plugin that runs inside FireFly, and talks directly to the same plugin in
+// peer FireFly nodes over mutually authenticated HTTPS - so a separate data exchange process is not required.
+//
+// Each node presents its certificate in its peer info, and every connection is authenticated by pinning
+// the certificate of the peer at the other end, so self-signed certificates can be used. Outbound messages
+// and blob transfers are stored in a durable queue for each peer, and retried until the peer accepts them.
+type P2PDX struct {
+	ctx                context.Context
+	cancelCtx          context.CancelFunc
+	capabilities       *dataexchange.Capabilities
+	callbacks          callbacks
+	cert               tls.Certificate
+	certPEM            string
+	fingerprint        string
+	endpoint           string
+	listener           net.Listener
+	server             *http.Server
+	storagePath        string
+	ackTimeout         time.Duration
+	deliveredRetention time.Duration
+	requestTimeout     time.Duration
+	blobTimeout        time.Duration
+	eventRetry         *retry.Retry
+	outboundRetry      *retry.Retry
+	nodesMutex         sync.Mutex
+	nodes              map[string]*dxNode
+	peers              map[string]fftypes.JSONObject
+	clients            map[string]*http.Client
+	workersMutex       sync.Mutex
+	started            bool
+	workers            map[string]chan struct{}
+	lastSeq            int64
+
+	deliveriesMutex sync.Mutex
+	deliveries      map[string]*delivery
+}
+
+type dxNode struct {
+	Name string
+	Peer fftypes.JSONObject
+}
+
+type peerInfo struct {
+	id          string
+	endpoint    string
+	fingerprint string
+}
+
+type callbacks struct {
+	plugin     *P2PDX
+	writeLock  sync.Mutex
+	handlers   map[string]dataexchange.Callbacks
+	opHandlers map[string]core.OperationCallbacks
+}
+
+func (cb *callbacks) OperationUpdate(ctx context.Context, update *core.OperationUpdate) {
+	namespace, _, _ := core.ParseNamespacedOpID(ctx, update.NamespacedOpID)
+	cb.writeLock.Lock()
+	handler, ok := cb.opHandlers[namespace]
+	cb.writeLock.Unlock()
+	if ok {
+		handler.OperationUpdate(update)
+	} else {
+		log.L(ctx).Errorf("No handler found for DX operation '%s'", update.NamespacedOpID)
+		update.OnComplete()
+	}
+}
+
+func (cb *callbacks) DXEvent(ctx context.Context, namespace, recipient string, event dataexchange.DXEvent) error {
+	node := cb.plugin.findNode(namespace, recipient)
+	if node != nil {
+		key := namespace + ":" + node.Name
+		cb.writeLock.Lock()
+		handler, ok := cb.handlers[key]
+		cb.writeLock.Unlock()
+		if ok {
+			return handler.DXEvent(cb.plugin, event)
+		}
+		log.L(ctx).Errorf("No handler found for DX event '%s' namespace=%s node=%s", event.EventID(), namespace, node.Name)
+		event.Ack()
+	} else {
+		log.L(ctx).Errorf("Unknown local node for DX event '%s' recipient=%s", event.EventID(), recipient)
+		event.Ack()
+	}
+	return nil
+}
+
+func certFingerprint(der []byte) string {
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:])
+}
+
+func (p *P2PDX) Name() string {
+	return "p2p"
+}
+
+func (p *P2PDX) Init(ctx context.Context, cancelCtx context.CancelFunc, config config.Section) (err error) {
+	p.ctx = log.WithLogField(ctx, "dx", "p2p")
+	p.cancelCtx = cancelCtx
+	p.callbacks = callbacks{
+		plugin:     p,
+		handlers:   make(map[string]dataexchange.Callbacks),
+		opHandlers: make(map[string]core.OperationCallbacks),
+	}
+	p.nodes = make(map[string]*dxNode)
+	p.peers = make(map[string]fftypes.JSONObject)
+	p.clients = make(map[string]*http.Client)
+	p.workers = make(map[string]chan struct{})
+	p.deliveries = make(map[string]*delivery)
+
+	for _, key := range []string{P2PConfTLSCertFile, P2PConfTLSKeyFile, P2PConfStoragePath} {
+		if config.GetString(key) == "" {
+			return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(key), "dataexchange.p2p")
+		}
+	}
+
+	p.cert, err = tls.LoadX509KeyPair(config.GetString(P2PConfTLSCertFile), config.GetString(P2PConfTLSKeyFile))
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgP2PDXTLSLoadFailed)
+	}
+	p.certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.cert.Certificate[0]}))
+	p.fingerprint = certFingerprint(p.cert.Certificate[0])
+
+	p.storagePath = config.GetString(P2PConfStoragePath)
+	for _, dir := range []string{"blobs", "outbound", "delivered"} {
+		if err := os.MkdirAll(filepath.Join(p.storagePath, dir), 0755); err != nil {
+			return i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+		}
+	}
+
+	address := net.JoinHostPort(config.GetString(P2PConfServerAddress), config.GetString(P2PConfServerPort))
+	p.listener, err = net.Listen("tcp", address)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgP2PDXListenFailed, address)
+	}
+	p.endpoint = config.GetString(P2PConfEndpoint)
+	if p.endpoint == "" {
+		p.endpoint = fmt.Sprintf("https://%s", p.listener.Addr())
+	}
+	p.server = &http.Server{
+		Handler: p.router(),
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{p.cert},
+			// Peers present self-signed certificates, which are checked against their peer info for each request
+			ClientAuth: tls.RequireAnyClientCert,
+			MinVersion: tls.VersionTLS12,
+		},
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	p.capabilities = &dataexchange.Capabilities{
+		Manifest: config.GetBool(P2PConfManifestEnabled),
+	}
+	p.ackTimeout = config.GetDuration(P2PConfAckTimeout)
+	p.deliveredRetention = config.GetDuration(P2PConfDeliveredRetention)
+	p.requestTimeout = config.GetDuration(P2PConfOutboundRequestTimeout)
+	p.blobTimeout = config.GetDuration(P2PConfOutboundBlobTimeout)
+	p.eventRetry = &retry.Retry{
+		InitialDelay: config.GetDuration(P2PConfEventRetryInitialDelay),
+		MaximumDelay: config.GetDuration(P2PConfEventRetryMaxDelay),
+		Factor:       config.GetFloat64(P2PConfEventRetryFactor),
+	}
+	p.outboundRetry = &retry.Retry{
+		InitialDelay: config.GetDuration(P2PConfOutboundRetryInitialDelay),
+		MaximumDelay: config.GetDuration(P2PConfOutboundRetryMaxDelay),
+		Factor:       config.GetFloat64(P2PConfOutboundRetryFactor),
+	}
+	return nil
+}
+
+func (p *P2PDX) SetHandler(networkNamespace, nodeName string, handler dataexchange.Callbacks) {
+	p.callbacks.writeLock.Lock()
+	defer p.callbacks.writeLock.Unlock()
+	key := networkNamespace + ":" + nodeName
+	if handler == nil {
+		delete(p.callbacks.handlers, key)
+	} else {
+		p.callbacks.handlers[key] = handler
+	}
+}
+
+func (p *P2PDX) SetOperationHandler(namespace string, handler core.OperationCallbacks) {
+	p.callbacks.writeLock.Lock()
+	defer p.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(p.callbacks.opHandlers, namespace)
+	} else {
+		p.callbacks.opHandlers[namespace] = handler
+	}
+}
+
+func (p *P2PDX) Start() error {
+	go p.serve()
+	go p.deliveredPruner()
+	return p.startOutbound()
+}
+
+func (p *P2PDX) serve() {
+	go func() {
+		<-p.ctx.Done()
+		_ = p.server.Close()
+	}()
+	log.L(p.ctx).Infof("Data exchange listening on %s for peers (endpoint=%s)", p.listener.Addr(), p.endpoint)
+	err := p.server.ServeTLS(p.listener, "", "")
+	if err != http.ErrServerClosed {
+		log.L(p.ctx).Errorf("Data exchange server failed: %s", err)
+		p.cancelCtx()
+	}
+}
+
+func (p *P2PDX) Capabilities() *dataexchange.Capabilities {
+	return p.capabilities
+}
+
+func (p *P2PDX) GetPeerID(peer fftypes.JSONObject) string {
+	return peer.GetString("id")
+}
+
+func (p *P2PDX) GetEndpointInfo(ctx context.Context, nodeName string) (peer fftypes.JSONObject, err error) {
+	return fftypes.JSONObject{
+		"id":       fmt.Sprintf("%s/%s", p.fingerprint, nodeName),
+		"endpoint": p.endpoint,
+		"cert":     p.certPEM,
+	}, nil
+}
+
+func (p *P2PDX) AddNode(ctx context.Context, networkNamespace, nodeName string, peer fftypes.JSONObject) (err error) {
+	p.nodesMutex.Lock()
+	defer p.nodesMutex.Unlock()
+
+	peerID := p.GetPeerID(peer)
+	p.nodes[networkNamespace+":"+peerID] = &dxNode{
+		Peer: peer,
+		Name: nodeName,
+	}
+	p.peers[peerID] = peer
+	return nil
+}
+
+func (p *P2PDX) findNode(namespace, recipient string) *dxNode {
+	p.nodesMutex.Lock()
+	defer p.nodesMutex.Unlock()
+	node := p.nodes[namespace+":"+recipient]
+	if node == nil {
+		// Fall back to nodes registered on the legacy system namespace
+		// (further verification of the off-chain identity will be performed by the event handler)
+		node = p.nodes[core.LegacySystemNamespace+":"+recipient]
+	}
+	return node
+}
+
+func (p *P2PDX) getPeer(peerID string) fftypes.JSONObject {
+	p.nodesMutex.Lock()
+	defer p.nodesMutex.Unlock()
+	return p.peers[peerID]
+}
+
+// parsePeer extracts the endpoint, and the fingerprint of the certificate, from the peer info of a node
+func parsePeer(ctx context.Context, peer fftypes.JSONObject) (*peerInfo, error) {
+	info := &peerInfo{
+		id:       peer.GetString("id"),
+		endpoint: strings.TrimSuffix(peer.GetString("endpoint"), "/"),
+	}
+	block, _ := pem.Decode([]byte(peer.GetString("cert")))
+	if info.id == "" || info.endpoint == "" || block == nil || block.Type != "CERTIFICATE" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgP2PDXInvalidPeer, info.id)
+	}
+	info.fingerprint = certFingerprint(block.Bytes)
+	return info, nil
+}
+
+// blobPath returns the location on disk of a blob. Local blobs have a payload reference of "namespace/id",
+// and blobs received from peers "received/peerID/namespace/id" (with the peer ID escaped)
+func (p *P2PDX) blobPath(ctx context.Context, payloadRef string) (string, error) {
+	segments := strings.Split(payloadRef, "/")
+	for _, s := range segments {
+		if s == "" || s == "." || s == ".." || strings.Contains(s, `\`) {
+			return "", i18n.NewError(ctx, coremsgs.MsgP2PDXInvalidPayloadRef, payloadRef)
+		}
+	}
+	return filepath.Join(append([]string{p.storagePath, "blobs"}, segments...)...), nil
+}
+
+// transferPath returns the "namespace/id" path a local blob is sent to the peer as, or an empty string if the blob
+// is not local. Local blobs are either "namespace/id", or a copy staged for a transfer "staged/transferID/namespace/id"
+func transferPath(payloadRef string) string {
+	segments := strings.Split(payloadRef, "/")
+	switch {
+	case len(segments) == 2:
+		return payloadRef
+	case len(segments) == 4 && segments[0] == dataexchange.StagedBlobPrefix:
+		return strings.Join(segments[2:], "/")
+	default:
+		return ""
+	}
+}
+
+func (p *P2PDX) writeBlob(ctx context.Context, payloadRef string, content io.Reader) (hash *fftypes.Bytes32, size int64, err error) {
+	path, err := p.blobPath(ctx, payloadRef)
+	if err != nil {
+		return nil, -1, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, -1, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	// Write to a temporary file while calculating the hash, then move it into place
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return nil, -1, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	hasher := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmpFile, hasher), content)
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return nil, -1, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	return fftypes.HashResult(hasher), size, nil
+}
+
+func (p *P2PDX) UploadBlob(ctx context.Context, ns string, id fftypes.UUID, content io.Reader) (payloadRef string, hash *fftypes.Bytes32, size int64, err error) {
+	payloadRef = fmt.Sprintf("%s/%s", ns, id.String())
+	if hash, size, err = p.writeBlob(ctx, payloadRef, content); err != nil {
+		return "", nil, -1, err
+	}
+	return payloadRef, hash, size, nil
+}
+
+func (p *P2PDX) DownloadBlob(ctx context.Context, payloadRef string) (content io.ReadCloser, err error) {
+	path, err := p.blobPath(ctx, payloadRef)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	return f, nil
+}
+
+// rangeReader is a window of a blob file
+type rangeReader struct {
+	io.Reader
+	io.Closer
+}
+
+func (p *P2PDX) DownloadBlobRange(ctx context.Context, payloadRef string, offset, length int64) (content io.ReadCloser, err error) {
+	path, err := p.blobPath(ctx, payloadRef)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return &rangeReader{Reader: io.LimitReader(f, length), Closer: f}, nil
+}
+
+func (p *P2PDX) DeleteBlob(ctx context.Context, payloadRef string) (err error) {
+	path, err := p.blobPath(ctx, payloadRef)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgP2PDXStorageErr)
+	}
+	return nil
+}
+
+func (p *P2PDX) SendMessage(ctx context.Context, nsOpID string, peer, sender fftypes.JSONObject, data []byte) (err error) {
+	if _, err := parsePeer(ctx, peer); err != nil {
+		return err
+	}
+	return p.enqueue(ctx, &outboundEntry{
+		NSOpID:    nsOpID,
+		Recipient: peer,
+		Sender:    p.GetPeerID(sender),
+		Message:   string(data),
+	})
+}
+
+func (p *P2PDX) TransferBlob(ctx context.Context, nsOpID string, peer, sender fftypes.JSONObject, payloadRef string) (err error) {
+	if _, err := parsePeer(ctx, peer); err != nil {
+		return err
+	}
+	// Only local blobs can be transferred
+	if _, err := p.blobPath(ctx, payloadRef); err != nil || transferPath(payloadRef) == "" {
+		return i18n.NewError(ctx, coremsgs.MsgP2PDXInvalidPayloadRef, payloadRef)
+	}
+	return p.enqueue(ctx, &outboundEntry{
+		NSOpID:    nsOpID,
+		Recipient: peer,
+		Sender:    p.GetPeerID(sender),
+		BlobRef:   payloadRef,
+	})
+}
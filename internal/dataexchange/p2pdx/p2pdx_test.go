@@ -0,0 +1,693 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var utConfig = config.RootSection("p2pdx_unit_tests")
+var utConfigPeer = config.RootSection("p2pdx_unit_tests_peer")
+
+func resetConf() {
+	coreconfig.Reset()
+	p := &P2PDX{}
+	p.InitConfig(utConfig)
+	p.InitConfig(utConfigPeer)
+}
+
+func generateTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "p2pdx"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	assert.NoError(t, err)
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	assert.NoError(t, err)
+	return certFile, keyFile
+}
+
+func setTestConfig(t *testing.T, conf config.Section) {
+	dir := t.TempDir()
+	certFile, keyFile := generateTestCert(t, dir)
+	conf.Set(P2PConfServerAddress, "127.0.0.1")
+	conf.Set(P2PConfServerPort, 0)
+	conf.Set(P2PConfTLSCertFile, certFile)
+	conf.Set(P2PConfTLSKeyFile, keyFile)
+	conf.Set(P2PConfStoragePath, filepath.Join(dir, "data"))
+	conf.Set(P2PConfAckTimeout, "5s")
+	conf.Set(P2PConfOutboundRequestTimeout, "5s")
+	conf.Set(P2PConfOutboundRetryInitialDelay, "1ms")
+	conf.Set(P2PConfOutboundRetryMaxDelay, "10ms")
+	conf.Set(P2PConfEventRetryInitialDelay, "1ms")
+	conf.Set(P2PConfEventRetryMaxDelay, "10ms")
+}
+
+func newTestP2PDX(t *testing.T, conf config.Section) (*P2PDX, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &P2PDX{}
+	err := p.Init(ctx, cancel, conf)
+	assert.NoError(t, err)
+	return p, func() {
+		cancel()
+		_ = p.listener.Close()
+	}
+}
+
+type testNodes struct {
+	a, b         *P2PDX
+	peerA, peerB fftypes.JSONObject
+}
+
+// newTestNodes creates two nodes on loopback, that know about each other in namespace "ns1"
+func newTestNodes(t *testing.T) (*testNodes, func()) {
+	resetConf()
+	setTestConfig(t, utConfig)
+	setTestConfig(t, utConfigPeer)
+	a, doneA := newTestP2PDX(t, utConfig)
+	b, doneB := newTestP2PDX(t, utConfigPeer)
+	tn := &testNodes{a: a, b: b}
+	tn.peerA, _ = a.GetEndpointInfo(context.Background(), "node1")
+	tn.peerB, _ = b.GetEndpointInfo(context.Background(), "node2")
+	for _, p := range []*P2PDX{a, b} {
+		_ = p.AddNode(context.Background(), "ns1", "node1", tn.peerA)
+		_ = p.AddNode(context.Background(), "ns1", "node2", tn.peerB)
+	}
+	return tn, func() {
+		doneA()
+		doneB()
+	}
+}
+
+func captureOpUpdates(p *P2PDX) chan *core.OperationUpdate {
+	updates := make(chan *core.OperationUpdate, 10)
+	mcb := &coremocks.OperationCallbacks{}
+	mcb.On("OperationUpdate", mock.Anything).Run(func(args mock.Arguments) {
+		update := args[0].(*core.OperationUpdate)
+		updates <- update
+		update.OnComplete()
+	})
+	p.SetOperationHandler("ns1", mcb)
+	return updates
+}
+
+func captureDXEvents(p *P2PDX, manifest string) chan dataexchange.DXEvent {
+	events := make(chan dataexchange.DXEvent, 10)
+	mcb := &dataexchangemocks.Callbacks{}
+	mcb.On("DXEvent", p, mock.Anything).Run(func(args mock.Arguments) {
+		e := args[1].(dataexchange.DXEvent)
+		events <- e
+		e.AckWithManifest(manifest)
+	}).Return(nil)
+	p.SetHandler("ns1", "node2", mcb)
+	return events
+}
+
+func testTransportWrapper(t *testing.T) (*core.TransportWrapper, []byte) {
+	wrapper := &core.TransportWrapper{
+		Batch: &core.Batch{
+			BatchHeader: core.BatchHeader{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+			},
+		},
+	}
+	b, err := json.Marshal(wrapper)
+	assert.NoError(t, err)
+	return wrapper, b
+}
+
+func TestInit(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	assert.Equal(t, "p2p", tn.a.Name())
+	assert.True(t, tn.a.Capabilities().Manifest)
+	assert.Equal(t, "https://"+tn.a.listener.Addr().String(), tn.peerA.GetString("endpoint"))
+	assert.Equal(t, tn.a.fingerprint+"/node1", tn.a.GetPeerID(tn.peerA))
+
+	tn.a.SetHandler("ns1", "node1", &dataexchangemocks.Callbacks{})
+	tn.a.SetHandler("ns1", "node1", nil)
+	assert.Empty(t, tn.a.callbacks.handlers)
+	tn.a.SetOperationHandler("ns1", &coremocks.OperationCallbacks{})
+	tn.a.SetOperationHandler("ns1", nil)
+	assert.Empty(t, tn.a.callbacks.opHandlers)
+}
+
+func TestInitEndpointConfigured(t *testing.T) {
+	resetConf()
+	setTestConfig(t, utConfig)
+	utConfig.Set(P2PConfEndpoint, "https://dx.example.com:5443")
+	p, done := newTestP2PDX(t, utConfig)
+	defer done()
+	peer, err := p.GetEndpointInfo(context.Background(), "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dx.example.com:5443", peer.GetString("endpoint"))
+}
+
+func TestInitMissingConfig(t *testing.T) {
+	resetConf()
+	p := &P2PDX{}
+	err := p.Init(context.Background(), func() {}, utConfig)
+	assert.Regexp(t, "FF10138.*certFile", err)
+}
+
+func TestInitBadCert(t *testing.T) {
+	resetConf()
+	setTestConfig(t, utConfig)
+	utConfig.Set(P2PConfTLSKeyFile, utConfig.GetString(P2PConfTLSCertFile))
+	p := &P2PDX{}
+	err := p.Init(context.Background(), func() {}, utConfig)
+	assert.Regexp(t, "FF10481", err)
+}
+
+func TestInitBadStoragePath(t *testing.T) {
+	resetConf()
+	setTestConfig(t, utConfig)
+	utConfig.Set(P2PConfStoragePath, filepath.Join(utConfig.GetString(P2PConfTLSCertFile), "data"))
+	p := &P2PDX{}
+	err := p.Init(context.Background(), func() {}, utConfig)
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestInitListenFail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	resetConf()
+	setTestConfig(t, utConfig)
+	utConfig.Set(P2PConfServerPort, l.Addr().(*net.TCPAddr).Port)
+	p := &P2PDX{}
+	err = p.Init(context.Background(), func() {}, utConfig)
+	assert.Regexp(t, "FF10486", err)
+}
+
+func TestServeFail(t *testing.T) {
+	resetConf()
+	setTestConfig(t, utConfig)
+	p, done := newTestP2PDX(t, utConfig)
+	defer done()
+
+	_ = p.listener.Close()
+	err := p.Start()
+	assert.NoError(t, err)
+	<-p.ctx.Done()
+}
+
+func TestSendMessage(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	events := captureDXEvents(tn.b, "manifest1")
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	wrapper, data := testTransportWrapper(t)
+	opID := fftypes.NewUUID()
+	err := tn.a.SendMessage(context.Background(), "ns1:"+opID.String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+
+	e := <-events
+	assert.NotEmpty(t, e.EventID())
+	assert.Equal(t, dataexchange.DXEventTypeMessageReceived, e.Type())
+	assert.Equal(t, tn.a.GetPeerID(tn.peerA), e.MessageReceived().PeerID)
+	assert.Equal(t, wrapper.Batch.ID, e.MessageReceived().Transport.Batch.ID)
+	assert.Nil(t, e.PrivateBlobReceived())
+
+	update := <-updates
+	assert.Equal(t, "p2p", update.Plugin)
+	assert.Equal(t, "ns1:"+opID.String(), update.NamespacedOpID)
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+	assert.True(t, update.VerifyManifest)
+	assert.Equal(t, "manifest1", update.DXManifest)
+}
+
+func TestSendMessageEncrypted(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	events := captureDXEvents(tn.b, "")
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	data, err := json.Marshal(&core.TransportWrapper{
+		Encrypted: &core.EncryptedTransport{
+			Namespace: "ns1",
+			Payload:   []byte("sealed"),
+		},
+	})
+	assert.NoError(t, err)
+	opID := fftypes.NewUUID()
+	err = tn.a.SendMessage(context.Background(), "ns1:"+opID.String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+
+	e := <-events
+	assert.Equal(t, dataexchange.DXEventTypeMessageReceived, e.Type())
+	assert.Nil(t, e.MessageReceived().Transport.Batch)
+	assert.Equal(t, []byte("sealed"), e.MessageReceived().Transport.Encrypted.Payload)
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+}
+
+func TestTransferBlob(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	events := captureDXEvents(tn.b, "")
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	content := []byte("some blob content")
+	dataID := fftypes.NewUUID()
+	payloadRef, hash, size, err := tn.a.UploadBlob(context.Background(), "ns1", *dataID, bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1/"+dataID.String(), payloadRef)
+	assert.Equal(t, fftypes.HashString(string(content)), hash)
+	assert.Equal(t, int64(len(content)), size)
+
+	opID := fftypes.NewUUID()
+	err = tn.a.TransferBlob(context.Background(), "ns1:"+opID.String(), tn.peerB, tn.peerA, payloadRef)
+	assert.NoError(t, err)
+
+	e := <-events
+	assert.Equal(t, dataexchange.DXEventTypePrivateBlobReceived, e.Type())
+	received := e.PrivateBlobReceived()
+	assert.Equal(t, "ns1", received.Namespace)
+	assert.Equal(t, tn.a.GetPeerID(tn.peerA), received.PeerID)
+	assert.Equal(t, *hash, received.Hash)
+	assert.Equal(t, size, received.Size)
+	assert.Equal(t, dataID.String(), received.DataID)
+	assert.Nil(t, e.MessageReceived())
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+	assert.Equal(t, hash.String(), update.DXHash)
+
+	r, err := tn.b.DownloadBlob(context.Background(), received.PayloadRef)
+	assert.NoError(t, err)
+	downloaded, err := io.ReadAll(r)
+	r.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+
+	err = tn.b.DeleteBlob(context.Background(), received.PayloadRef)
+	assert.NoError(t, err)
+	_, err = tn.b.DownloadBlob(context.Background(), received.PayloadRef)
+	assert.Regexp(t, "FF10482", err)
+	err = tn.b.DeleteBlob(context.Background(), received.PayloadRef)
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestTransferBlobAlreadyDelivered(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	events := captureDXEvents(tn.b, "")
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	payloadRef, hash, _, err := tn.a.UploadBlob(context.Background(), "ns1", *fftypes.NewUUID(), bytes.NewReader([]byte("some blob content")))
+	assert.NoError(t, err)
+
+	// Submitting the same operation again is recognized by the peer as the same delivery
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	for i := 0; i < 2; i++ {
+		err = tn.a.TransferBlob(context.Background(), nsOpID, tn.peerB, tn.peerA, payloadRef)
+		assert.NoError(t, err)
+		update := <-updates
+		assert.Equal(t, core.OpStatusSucceeded, update.Status)
+		assert.Equal(t, hash.String(), update.DXHash)
+	}
+	assert.Len(t, events, 1)
+}
+
+func TestTransferStagedBlob(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	events := captureDXEvents(tn.b, "")
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	content := []byte("some decrypted blob content")
+	dataID := fftypes.NewUUID()
+	opID := fftypes.NewUUID()
+	payloadRef, _, _, err := tn.a.UploadBlob(context.Background(), dataexchange.StagedBlobNamespace("ns1", opID), *dataID, bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "staged/"+opID.String()+"/ns1/"+dataID.String(), payloadRef)
+
+	err = tn.a.TransferBlob(context.Background(), "ns1:"+opID.String(), tn.peerB, tn.peerA, payloadRef)
+	assert.NoError(t, err)
+
+	// The recipient sees the data ID, not the ID of the transfer
+	e := <-events
+	received := e.PrivateBlobReceived()
+	assert.Equal(t, "ns1", received.Namespace)
+	assert.Equal(t, dataID.String(), received.DataID)
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+
+	err = tn.a.DeleteBlob(context.Background(), payloadRef)
+	assert.NoError(t, err)
+}
+
+func TestSendMessageUnknownSender(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	delete(tn.b.peers, tn.a.GetPeerID(tn.peerA))
+	updates := captureOpUpdates(tn.a)
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	_, data := testTransportWrapper(t)
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Regexp(t, "FF10484.*403.*FF10487", update.ErrorMessage)
+}
+
+func TestSendMessageUnknownRecipient(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	peer := fftypes.JSONObject{"id": "other/node2", "endpoint": tn.peerB["endpoint"], "cert": tn.peerB["cert"]}
+	_, data := testTransportWrapper(t)
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), peer, tn.peerA, data)
+	assert.NoError(t, err)
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Regexp(t, "FF10484.*404.*FF10488", update.ErrorMessage)
+}
+
+func TestSendMessageNoHandlers(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	// Deliveries are acked by the receiver if there is no handler, or the recipient is not a known node
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	_, data := testTransportWrapper(t)
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+	peer := fftypes.JSONObject{"id": tn.b.fingerprint + "/node3", "endpoint": tn.peerB["endpoint"], "cert": tn.peerB["cert"]}
+	err = tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), peer, tn.peerA, data)
+	assert.NoError(t, err)
+
+	// The queues are emptied once the (unhandled) operation updates complete
+	for _, peerID := range []string{tn.b.GetPeerID(tn.peerB), tn.b.fingerprint + "/node3"} {
+		dir := tn.a.queueDir(peerID)
+		for {
+			if _, ok := nextOutbound(dir); !ok {
+				break
+			}
+			time.Sleep(1 * time.Millisecond)
+		}
+	}
+}
+
+func TestSendMessageRetryUntilAck(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	tn.b.ackTimeout = 10 * time.Millisecond
+
+	updates := captureOpUpdates(tn.a)
+	events := make(chan dataexchange.DXEvent, 10)
+	mcb := &dataexchangemocks.Callbacks{}
+	mcb.On("DXEvent", tn.b, mock.Anything).Run(func(args mock.Arguments) {
+		e := args[1].(dataexchange.DXEvent)
+		events <- e
+		go func() {
+			// Ack after the first attempt from the sender has timed out
+			time.Sleep(50 * time.Millisecond)
+			e.AckWithManifest("manifest1")
+			e.Ack()
+		}()
+	}).Return(nil)
+	tn.b.SetHandler("ns1", "node2", mcb)
+	assert.NoError(t, tn.a.Start())
+	assert.NoError(t, tn.b.Start())
+
+	_, data := testTransportWrapper(t)
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+
+	// The retries wait for the event already dispatched, rather than dispatching it again
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+	assert.Equal(t, "manifest1", update.DXManifest)
+	assert.Len(t, events, 1)
+}
+
+func TestSendMessageQueuedAcrossRestart(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	// Queue the message before the sender is started, and then restart it
+	_, data := testTransportWrapper(t)
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, data)
+	assert.NoError(t, err)
+	_ = tn.a.listener.Close()
+	tn.a.cancelCtx()
+
+	a2, doneA2 := newTestP2PDX(t, utConfig)
+	defer doneA2()
+	assert.Equal(t, tn.a.fingerprint, a2.fingerprint)
+	updates := captureOpUpdates(a2)
+	events := captureDXEvents(tn.b, "manifest1")
+	assert.NoError(t, tn.b.Start())
+	assert.NoError(t, a2.Start())
+
+	<-events
+	update := <-updates
+	assert.Equal(t, core.OpStatusSucceeded, update.Status)
+}
+
+func TestSendMessageInvalidPeer(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	err := tn.a.SendMessage(context.Background(), "ns1:"+fftypes.NewUUID().String(), fftypes.JSONObject{"id": "peer1"}, tn.peerA, []byte("{}"))
+	assert.Regexp(t, "FF10483.*peer1", err)
+}
+
+func TestTransferBlobInvalidPeer(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	err := tn.a.TransferBlob(context.Background(), "ns1:"+fftypes.NewUUID().String(), fftypes.JSONObject{"id": "peer1", "endpoint": "https://peer1", "cert": "bad"}, tn.peerA, "ns1/id1")
+	assert.Regexp(t, "FF10483.*peer1", err)
+}
+
+func TestTransferBlobInvalidRef(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	for _, payloadRef := range []string{"ns1/../id1", "received/peer1/ns1/id1", "staged/ns1/id1", "id1", `ns1/a\b`} {
+		err := tn.a.TransferBlob(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, payloadRef)
+		assert.Regexp(t, "FF10485", err, payloadRef)
+	}
+}
+
+func TestTransferBlobMissing(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	updates := captureOpUpdates(tn.a)
+	assert.NoError(t, tn.a.Start())
+
+	err := tn.a.TransferBlob(context.Background(), "ns1:"+fftypes.NewUUID().String(), tn.peerB, tn.peerA, "ns1/"+fftypes.NewUUID().String())
+	assert.NoError(t, err)
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Regexp(t, "FF10482", update.ErrorMessage)
+}
+
+func TestUploadBlobInvalidNamespace(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	_, _, _, err := tn.a.UploadBlob(context.Background(), "..", *fftypes.NewUUID(), bytes.NewReader([]byte("data")))
+	assert.Regexp(t, "FF10485", err)
+}
+
+func TestUploadBlobStorageFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	blobsDir := filepath.Join(tn.a.storagePath, "blobs")
+	err := os.RemoveAll(blobsDir)
+	assert.NoError(t, err)
+	err = os.WriteFile(blobsDir, []byte{}, 0644)
+	assert.NoError(t, err)
+
+	_, _, _, err = tn.a.UploadBlob(context.Background(), "ns1", *fftypes.NewUUID(), bytes.NewReader([]byte("data")))
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestUploadBlobCreateFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	// The destination already exists as a directory
+	id := fftypes.NewUUID()
+	err := os.MkdirAll(filepath.Join(tn.a.storagePath, "blobs", "ns1", id.String(), "sub"), 0755)
+	assert.NoError(t, err)
+
+	_, _, _, err = tn.a.UploadBlob(context.Background(), "ns1", *id, bytes.NewReader([]byte("data")))
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestUploadBlobTempFileFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	ctx := context.Background()
+	path, err := tn.a.blobPath(ctx, "ns1/id1")
+	assert.NoError(t, err)
+	err = os.MkdirAll(filepath.Dir(path), 0555)
+	assert.NoError(t, err)
+	err = os.Chmod(filepath.Dir(path), 0555)
+	assert.NoError(t, err)
+	if os.Geteuid() == 0 {
+		// Permissions are not enforced for root, so remove the directory after it is created instead
+		err = os.RemoveAll(filepath.Dir(path))
+		assert.NoError(t, err)
+		err = os.WriteFile(filepath.Dir(path), []byte{}, 0644)
+		assert.NoError(t, err)
+	}
+	_, _, err = tn.a.writeBlob(ctx, "ns1/id1", bytes.NewReader([]byte("data")))
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestDownloadBlobRange(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	ctx := context.Background()
+	payloadRef, _, _, err := tn.a.UploadBlob(ctx, "ns1", *fftypes.NewUUID(), bytes.NewReader([]byte("some data")))
+	assert.NoError(t, err)
+
+	r, err := tn.a.DownloadBlobRange(ctx, payloadRef, 2, 4)
+	assert.NoError(t, err)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "me d", string(b))
+	r.Close()
+
+	r, err = tn.a.DownloadBlobRange(ctx, payloadRef, 5, -1)
+	assert.NoError(t, err)
+	b, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(b))
+	r.Close()
+
+	_, err = tn.a.DownloadBlobRange(ctx, payloadRef, -1, -1)
+	assert.Regexp(t, "FF10482", err)
+	_, err = tn.a.DownloadBlobRange(ctx, "ns1/missing", 0, -1)
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestDownloadDeleteBlobInvalidRef(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	_, err := tn.a.DownloadBlob(context.Background(), "../id1")
+	assert.Regexp(t, "FF10485", err)
+	_, err = tn.a.DownloadBlobRange(context.Background(), "../id1", 0, -1)
+	assert.Regexp(t, "FF10485", err)
+	err = tn.a.DeleteBlob(context.Background(), "ns1//id1")
+	assert.Regexp(t, "FF10485", err)
+}
+
+func TestOperationUpdateNoHandler(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	completed := false
+	tn.a.callbacks.OperationUpdate(context.Background(), &core.OperationUpdate{
+		NamespacedOpID: "ns2:" + fftypes.NewUUID().String(),
+		OnComplete:     func() { completed = true },
+	})
+	assert.True(t, completed)
+}
+
+func TestFindNodeLegacyNamespace(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	_ = tn.a.AddNode(context.Background(), core.LegacySystemNamespace, "node3", fftypes.JSONObject{"id": "peer3"})
+	node := tn.a.findNode("ns1", "peer3")
+	assert.Equal(t, "node3", node.Name)
+}
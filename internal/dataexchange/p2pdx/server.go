@@ -0,0 +1,202 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+)
+
+const (
+	headerSender     = "X-FireFly-DX-Sender"
+	headerRecipient  = "X-FireFly-DX-Recipient"
+	headerDeliveryID = "X-FireFly-DX-Delivery"
+)
+
+// deliveryResult is returned to the sending peer once the receiving FireFly has processed a delivery
+type deliveryResult struct {
+	Manifest string `json:"manifest,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (p *P2PDX) router() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/api/v1/messages", p.handleMessage).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/blobs/{ns}/{id}", p.handleBlob).Methods(http.MethodPut)
+	return r
+}
+
+func replyJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (p *P2PDX) replyError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	log.L(ctx).Errorf("Data exchange request failed [%d]: %s", status, err)
+	replyJSON(w, status, &errorResponse{Error: err.Error()})
+}
+
+// authenticate checks the sender is a known peer that presented the certificate from its peer info,
+// and that the recipient is one of the local nodes
+func (p *P2PDX) authenticate(ctx context.Context, r *http.Request) (sender, recipient string, status int, err error) {
+	sender = r.Header.Get(headerSender)
+	recipient = r.Header.Get(headerRecipient)
+	var fingerprint string
+	if peer := p.getPeer(sender); peer != nil {
+		if info, err := parsePeer(ctx, peer); err == nil {
+			fingerprint = info.fingerprint
+		}
+	}
+	if fingerprint == "" || certFingerprint(r.TLS.PeerCertificates[0].Raw) != fingerprint {
+		return "", "", http.StatusForbidden, i18n.NewError(ctx, coremsgs.MsgP2PDXUnauthorizedPeer, sender)
+	}
+	if !strings.HasPrefix(recipient, p.fingerprint+"/") {
+		return "", "", http.StatusNotFound, i18n.NewError(ctx, coremsgs.MsgP2PDXUnknownRecipient, recipient)
+	}
+	return sender, recipient, http.StatusOK, nil
+}
+
+// deliveryKey identifies a delivery across the retries of the sender, using the ID it sends with every attempt
+func deliveryKey(ctx context.Context, r *http.Request, sender string) (id, key string, err error) {
+	deliveryID := fftypes.NewUUID()
+	if header := r.Header.Get(headerDeliveryID); header != "" {
+		if deliveryID, err = fftypes.ParseUUID(ctx, header); err != nil {
+			return "", "", err
+		}
+	}
+	return deliveryID.String(), sender + "/" + deliveryID.String(), nil
+}
+
+func (p *P2PDX) handleMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sender, recipient, status, err := p.authenticate(ctx, r)
+	if err != nil {
+		p.replyError(ctx, w, status, err)
+		return
+	}
+
+	deliveryID, key, err := deliveryKey(ctx, r, sender)
+	if err != nil {
+		p.replyError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var wrapper *core.TransportWrapper
+	err = json.NewDecoder(r.Body).Decode(&wrapper)
+	if err != nil || wrapper == nil || (wrapper.Batch == nil && wrapper.Encrypted == nil) {
+		p.replyError(ctx, w, http.StatusBadRequest, i18n.NewError(ctx, coremsgs.MsgP2PDXInvalidMessage, sender))
+		return
+	}
+	log.L(ctx).Debugf("Received message from DX peer sender=%s recipient=%s namespace=%s encrypted=%t delivery=%s", sender, recipient, wrapper.Namespace(), wrapper.Encrypted != nil, deliveryID)
+
+	d, result := p.startDelivery(key, wrapper.Namespace(), recipient, &dxEvent{
+		id:     deliveryID,
+		dxType: dataexchange.DXEventTypeMessageReceived,
+		messageReceived: &dataexchange.MessageReceived{
+			PeerID:    sender,
+			Transport: wrapper,
+		},
+	}, &deliveryResult{})
+	if d != nil {
+		result, err = p.waitForAck(ctx, d)
+	}
+	if err != nil {
+		p.replyError(ctx, w, http.StatusServiceUnavailable, err)
+		return
+	}
+	replyJSON(w, http.StatusOK, result)
+}
+
+func (p *P2PDX) handleBlob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sender, recipient, status, err := p.authenticate(ctx, r)
+	if err != nil {
+		p.replyError(ctx, w, status, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	ns := vars["ns"]
+	dataID, err := fftypes.ParseUUID(ctx, vars["id"])
+	if err == nil {
+		err = fftypes.ValidateFFNameField(ctx, ns, "namespace")
+	}
+	var deliveryID, key string
+	if err == nil {
+		deliveryID, key, err = deliveryKey(ctx, r, sender)
+	}
+	if err != nil {
+		p.replyError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	// A retry of a blob that is already with FireFly does not need to be written again
+	d, result := p.findDelivery(key)
+	if d == nil && result == nil {
+		if d, result, err = p.receiveBlob(ctx, sender, recipient, ns, dataID, deliveryID, key, r.Body); err != nil {
+			p.replyError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if d != nil {
+		if result, err = p.waitForAck(ctx, d); err != nil {
+			p.replyError(ctx, w, http.StatusServiceUnavailable, err)
+			return
+		}
+	}
+	replyJSON(w, http.StatusOK, &deliveryResult{Hash: result.Hash})
+}
+
+func (p *P2PDX) receiveBlob(ctx context.Context, sender, recipient, ns string, dataID *fftypes.UUID, deliveryID, key string, content io.Reader) (*delivery, *deliveryResult, error) {
+	payloadRef := strings.Join([]string{"received", url.PathEscape(sender), ns, dataID.String()}, "/")
+	hash, size, err := p.writeBlob(ctx, payloadRef, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.L(ctx).Debugf("Received blob from DX peer sender=%s recipient=%s payloadRef=%s hash=%s delivery=%s", sender, recipient, payloadRef, hash, deliveryID)
+
+	d, result := p.startDelivery(key, ns, recipient, &dxEvent{
+		id:     deliveryID,
+		dxType: dataexchange.DXEventTypePrivateBlobReceived,
+		privateBlobReceived: &dataexchange.PrivateBlobReceived{
+			Namespace:  ns,
+			PeerID:     sender,
+			Hash:       *hash,
+			Size:       size,
+			PayloadRef: payloadRef,
+			DataID:     dataID.String(),
+		},
+	}, &deliveryResult{Hash: hash.String()})
+	return d, result, nil
+}
@@ -0,0 +1,332 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2pdx
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestRequest builds a request from node A to node B, as if it had arrived over mutual TLS
+func newTestRequest(t *testing.T, tn *testNodes, method, path string, body io.Reader) *http.Request {
+	cert, err := x509.ParseCertificate(tn.a.cert.Certificate[0])
+	assert.NoError(t, err)
+	req := httptest.NewRequest(method, path, body)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	req.Header.Set(headerSender, tn.a.GetPeerID(tn.peerA))
+	req.Header.Set(headerRecipient, tn.b.GetPeerID(tn.peerB))
+	return req
+}
+
+func TestHandleMessageInvalidBody(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	for _, body := range []string{"!json", "null", "{}"} {
+		res := httptest.NewRecorder()
+		tn.b.router().ServeHTTP(res, newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader([]byte(body))))
+		assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		assert.Regexp(t, "FF10490", res.Body.String(), body)
+	}
+}
+
+func TestHandleBlobUnauthorized(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	req := newTestRequest(t, tn, http.MethodPut, "/api/v1/blobs/ns1/"+fftypes.NewUUID().String(), bytes.NewReader([]byte("data")))
+	req.Header.Set(headerSender, "unknown/node1")
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, req)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	assert.Regexp(t, "FF10487", res.Body.String())
+}
+
+func TestHandleBlobBadPath(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	for _, path := range []string{"/api/v1/blobs/ns1/bad", "/api/v1/blobs/_ns1/" + fftypes.NewUUID().String()} {
+		res := httptest.NewRecorder()
+		tn.b.router().ServeHTTP(res, newTestRequest(t, tn, http.MethodPut, path, bytes.NewReader([]byte("data"))))
+		assert.Equal(t, http.StatusBadRequest, res.Code, path)
+	}
+}
+
+func TestHandleBlobStorageFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	blobsDir := filepath.Join(tn.b.storagePath, "blobs")
+	err := os.RemoveAll(blobsDir)
+	assert.NoError(t, err)
+	err = os.WriteFile(blobsDir, []byte{}, 0644)
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, newTestRequest(t, tn, http.MethodPut, "/api/v1/blobs/ns1/"+fftypes.NewUUID().String(), bytes.NewReader([]byte("data"))))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Regexp(t, "FF10482", res.Body.String())
+}
+
+func TestHandleBlobAckTimeout(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	tn.b.ackTimeout = 1 * time.Millisecond
+
+	// The event is dispatched, but never acknowledged
+	mcb := &dataexchangemocks.Callbacks{}
+	mcb.On("DXEvent", tn.b, mock.Anything).Return(nil).Maybe()
+	tn.b.SetHandler("ns1", "node2", mcb)
+
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, newTestRequest(t, tn, http.MethodPut, "/api/v1/blobs/ns1/"+fftypes.NewUUID().String(), bytes.NewReader([]byte("data"))))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Regexp(t, "FF10489", res.Body.String())
+}
+
+func TestHandleMessageRequestCancelled(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	_, data := testTransportWrapper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader(data)).WithContext(ctx)
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, req)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Regexp(t, "FF10489", res.Body.String())
+}
+
+func TestHandleMessageRetryAfterComplete(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	events := captureDXEvents(tn.b, "manifest1")
+
+	_, data := testTransportWrapper(t)
+	deliveryID := fftypes.NewUUID().String()
+	for i := 0; i < 2; i++ {
+		req := newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader(data))
+		req.Header.Set(headerDeliveryID, deliveryID)
+		res := httptest.NewRecorder()
+		tn.b.router().ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"manifest":"manifest1"}`, res.Body.String())
+	}
+	assert.Len(t, events, 1)
+	e := <-events
+	assert.Equal(t, deliveryID, e.EventID())
+}
+
+func TestHandleMessageAbandonedDispatchCancelled(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	tn.b.ackTimeout = 1 * time.Millisecond
+
+	dispatched := make(chan struct{}, 100)
+	mcb := &dataexchangemocks.Callbacks{}
+	mcb.On("DXEvent", tn.b, mock.Anything).Run(func(args mock.Arguments) {
+		dispatched <- struct{}{}
+	}).Return(fmt.Errorf("pop"))
+	tn.b.SetHandler("ns1", "node2", mcb)
+
+	_, data := testTransportWrapper(t)
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader(data)))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	// Once the request is abandoned, the event is no longer redelivered
+	<-dispatched
+	tn.b.deliveriesMutex.Lock()
+	assert.Empty(t, tn.b.deliveries)
+	tn.b.deliveriesMutex.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	count := len(dispatched)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, count, len(dispatched))
+}
+
+func TestHandleMessageBadDeliveryID(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	_, data := testTransportWrapper(t)
+	req := newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader(data))
+	req.Header.Set(headerDeliveryID, "!uuid")
+	res := httptest.NewRecorder()
+	tn.b.router().ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Regexp(t, "FF00138", res.Body.String())
+}
+
+func TestHandleBlobRetryInFlight(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	// The first delivery is dispatched, and acked while the retry is waiting
+	dispatched := make(chan dataexchange.DXEvent, 10)
+	mcb := &dataexchangemocks.Callbacks{}
+	mcb.On("DXEvent", tn.b, mock.Anything).Run(func(args mock.Arguments) {
+		dispatched <- args[1].(dataexchange.DXEvent)
+	}).Return(nil)
+	tn.b.SetHandler("ns1", "node2", mcb)
+
+	deliveryID := fftypes.NewUUID().String()
+	path := "/api/v1/blobs/ns1/" + fftypes.NewUUID().String()
+	sendBlob := func(ackTimeout time.Duration) *httptest.ResponseRecorder {
+		tn.b.ackTimeout = ackTimeout
+		req := newTestRequest(t, tn, http.MethodPut, path, bytes.NewReader([]byte("data")))
+		req.Header.Set(headerDeliveryID, deliveryID)
+		res := httptest.NewRecorder()
+		tn.b.router().ServeHTTP(res, req)
+		return res
+	}
+	res := sendBlob(1 * time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	e := <-dispatched
+	for {
+		tn.b.deliveriesMutex.Lock()
+		d := tn.b.deliveries[tn.a.GetPeerID(tn.peerA)+"/"+deliveryID]
+		isDispatched := d != nil && d.dispatched
+		tn.b.deliveriesMutex.Unlock()
+		if isDispatched {
+			break
+		}
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		e.Ack()
+	}()
+	res = sendBlob(1 * time.Minute)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Regexp(t, `"hash":`, res.Body.String())
+	assert.Empty(t, dispatched)
+}
+
+func TestStartDeliveryJoinsExisting(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	existing := &delivery{key: "key1", event: &dxEvent{}, waiters: 1}
+	tn.b.deliveries["key1"] = existing
+	d, result := tn.b.startDelivery("key1", "ns1", "node2", &dxEvent{}, &deliveryResult{})
+	assert.Equal(t, existing, d)
+	assert.Nil(t, result)
+	assert.Equal(t, 2, existing.waiters)
+}
+
+func TestDeliveryAckedRecorded(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	d := &delivery{key: "key1", result: &deliveryResult{}, cancel: func() {}}
+	tn.b.deliveries["key1"] = d
+	tn.b.deliveryAcked(d, "manifest")
+	assert.Empty(t, tn.b.deliveries)
+	d2, result := tn.b.findDelivery("key1")
+	assert.Nil(t, d2)
+	assert.Equal(t, "manifest", result.Manifest)
+}
+
+func TestDeliveryAckedRecordFail(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	err := os.RemoveAll(filepath.Join(tn.b.storagePath, "delivered"))
+	assert.NoError(t, err)
+	d := &delivery{key: "key1", result: &deliveryResult{}, cancel: func() {}}
+	tn.b.deliveryAcked(d, "manifest")
+	assert.Nil(t, tn.b.getDelivered("key1"))
+}
+
+func TestGetDeliveredInvalidRecord(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	err := os.WriteFile(tn.b.deliveredPath("key1"), []byte("!json"), 0600)
+	assert.NoError(t, err)
+	assert.Nil(t, tn.b.getDelivered("key1"))
+}
+
+func TestPruneDelivered(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+
+	tn.b.recordDelivered("old", &deliveryResult{})
+	tn.b.recordDelivered("new", &deliveryResult{})
+	old := time.Now().Add(-2 * tn.b.deliveredRetention)
+	err := os.Chtimes(tn.b.deliveredPath("old"), old, old)
+	assert.NoError(t, err)
+
+	tn.b.pruneDelivered()
+	assert.Nil(t, tn.b.getDelivered("old"))
+	assert.NotNil(t, tn.b.getDelivered("new"))
+
+	// Pruning is disabled without a retention
+	tn.b.deliveredRetention = 0
+	tn.b.deliveredPruner()
+}
+
+func TestHandleMessageRetryAfterRestart(t *testing.T) {
+	tn, done := newTestNodes(t)
+	defer done()
+	events := captureDXEvents(tn.b, "manifest1")
+
+	_, data := testTransportWrapper(t)
+	deliveryID := fftypes.NewUUID().String()
+	sendMessage := func() {
+		req := newTestRequest(t, tn, http.MethodPost, "/api/v1/messages", bytes.NewReader(data))
+		req.Header.Set(headerDeliveryID, deliveryID)
+		res := httptest.NewRecorder()
+		tn.b.router().ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"manifest":"manifest1"}`, res.Body.String())
+	}
+	sendMessage()
+	<-events
+
+	// The retry is answered by the restarted node, without the event going to FireFly again
+	_ = tn.b.listener.Close()
+	tn.b.cancelCtx()
+	b2, doneB2 := newTestP2PDX(t, utConfigPeer)
+	defer doneB2()
+	_ = b2.AddNode(context.Background(), "ns1", "node1", tn.peerA)
+	_ = b2.AddNode(context.Background(), "ns1", "node2", tn.peerB)
+	events = captureDXEvents(b2, "manifest2")
+	tn.b = b2
+	sendMessage()
+	assert.Empty(t, events)
+}
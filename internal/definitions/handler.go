@@ -78,6 +78,46 @@ func newDefinitionHandler(ctx context.Context, ns *core.Namespace, multiparty bo
 func (dh *definitionHandler) HandleDefinitionBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray, tx *fftypes.UUID) (msgAction HandlerResult, err error) {
 	l := log.L(ctx)
 	l.Infof("Processing system definition '%s' [%s]", msg.Header.Tag, msg.Header.ID)
+	if msg.Header.Type == core.MessageTypeDefinitionPrivate {
+		return dh.handlePrivateDefinition(ctx, state, msg, data, tx)
+	}
+	quorum, err := dh.definitionQuorum(ctx, msg.Header.Tag)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if quorum > 0 {
+		return dh.handleDefinitionProposal(ctx, state, msg, tx, quorum)
+	}
+	return dh.applyDefinitionBroadcast(ctx, state, msg, data, tx)
+}
+
+// privateDefinitionTags are the definitions that can be shared privately with the members of a group.
+// Identity and governance definitions must always be visible to the whole network.
+var privateDefinitionTags = map[string]bool{
+	core.SystemTagDefineDatatype:    true,
+	core.SystemTagDefinePool:        true,
+	core.SystemTagDefineFFI:         true,
+	core.SystemTagDefineContractAPI: true,
+}
+
+// handlePrivateDefinition applies a definition that was delivered only to the members of a group.
+// Most root orgs of the network cannot see a private definition, so it cannot go through the proposal and
+// vote flow - it is rejected instead while the network governance requires a quorum of approvals.
+func (dh *definitionHandler) handlePrivateDefinition(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray, tx *fftypes.UUID) (HandlerResult, error) {
+	if msg.Header.Group == nil || !privateDefinitionTags[msg.Header.Tag] {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedNotPrivateDefinition, msg.Header.Tag, msg.Header.ID)
+	}
+	quorum, err := dh.definitionQuorum(ctx, msg.Header.Tag)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if quorum > 0 {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedPrivateGoverned, msg.Header.Tag, msg.Header.ID, quorum)
+	}
+	return dh.applyDefinitionBroadcast(ctx, state, msg, data, tx)
+}
+
+func (dh *definitionHandler) applyDefinitionBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray, tx *fftypes.UUID) (msgAction HandlerResult, err error) {
 	switch msg.Header.Tag {
 	case core.SystemTagDefineDatatype:
 		return dh.handleDatatypeBroadcast(ctx, state, msg, data, tx)
@@ -91,12 +131,24 @@ func (dh *definitionHandler) HandleDefinitionBroadcast(ctx context.Context, stat
 		return dh.handleIdentityVerificationBroadcast(ctx, state, msg, data)
 	case core.SystemTagIdentityUpdate:
 		return dh.handleIdentityUpdateBroadcast(ctx, state, msg, data)
+	case core.SystemTagIdentityRevoke:
+		return dh.handleIdentityRevocationBroadcast(ctx, state, msg, data)
+	case core.SystemTagIdentityKeyRotation:
+		return dh.handleIdentityKeyRotationBroadcast(ctx, state, msg, data)
 	case core.SystemTagDefinePool:
 		return dh.handleTokenPoolBroadcast(ctx, state, msg, data)
 	case core.SystemTagDefineFFI:
 		return dh.handleFFIBroadcast(ctx, state, msg, data, tx)
 	case core.SystemTagDefineContractAPI:
 		return dh.handleContractAPIBroadcast(ctx, state, msg, data, tx)
+	case core.SystemTagDefinitionVote:
+		return dh.handleDefinitionVoteBroadcast(ctx, state, msg, data)
+	case core.SystemTagOrgApplication:
+		return dh.handleOrgApplicationBroadcast(ctx, state, msg, data)
+	case core.SystemTagOrgApplicationReview:
+		return dh.handleOrgApplicationReviewBroadcast(ctx, state, msg, data)
+	case core.SystemTagDefineNetworkGovernance:
+		return dh.handleNetworkGovernanceBroadcast(ctx, state, msg, data)
 	default:
 		return HandlerResult{Action: core.ActionReject}, fmt.Errorf("unknown system tag '%s' for definition ID '%s'", msg.Header.Tag, msg.Header.ID)
 	}
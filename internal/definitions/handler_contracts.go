@@ -28,14 +28,14 @@ import (
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
-func (dh *definitionHandler) persistFFI(ctx context.Context, ffi *fftypes.FFI, isAuthor bool) (retry bool, err error) {
+func (dh *definitionHandler) persistFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32, isAuthor bool) (retry bool, err error) {
 	for i := 1; ; i++ {
 		if err = dh.contracts.ResolveFFI(ctx, ffi); err != nil {
 			return false, i18n.WrapError(ctx, err, coremsgs.MsgDefRejectedValidateFail, "contract interface", ffi.ID)
 		}
 
 		// Check if this conflicts with an existing FFI
-		existing, err := dh.database.InsertOrGetFFI(ctx, ffi)
+		existing, err := dh.database.InsertOrGetFFI(ctx, ffi, group)
 		if err != nil {
 			return true, err
 		}
@@ -48,7 +48,7 @@ func (dh *definitionHandler) persistFFI(ctx context.Context, ffi *fftypes.FFI, i
 		if ffi.Published {
 			if existing.ID.Equals(ffi.ID) {
 				// ID conflict - check if this matches (or should overwrite) the existing record
-				return dh.reconcilePublishedFFI(ctx, existing, ffi, isAuthor)
+				return dh.reconcilePublishedFFI(ctx, existing, ffi, group, isAuthor)
 			}
 
 			if existing.Name == ffi.Name && existing.Version == ffi.Version {
@@ -81,7 +81,7 @@ func (dh *definitionHandler) persistFFI(ctx context.Context, ffi *fftypes.FFI, i
 	return false, nil
 }
 
-func (dh *definitionHandler) reconcilePublishedFFI(ctx context.Context, existing, ffi *fftypes.FFI, isAuthor bool) (retry bool, err error) {
+func (dh *definitionHandler) reconcilePublishedFFI(ctx context.Context, existing, ffi *fftypes.FFI, group *fftypes.Bytes32, isAuthor bool) (retry bool, err error) {
 	if existing.Message.Equals(ffi.Message) {
 		// Message already recorded
 		return false, nil
@@ -90,7 +90,7 @@ func (dh *definitionHandler) reconcilePublishedFFI(ctx context.Context, existing
 	if existing.Message == nil && isAuthor {
 		// FFI was previously unpublished - if it was now published by this node, upsert the new version
 		ffi.Name = existing.Name
-		if err := dh.database.UpsertFFI(ctx, ffi, database.UpsertOptimizationExisting); err != nil {
+		if err := dh.database.UpsertFFI(ctx, ffi, group, database.UpsertOptimizationExisting); err != nil {
 			return true, err
 		}
 		return false, nil
@@ -170,14 +170,14 @@ func (dh *definitionHandler) handleFFIBroadcast(ctx context.Context, state *core
 	ffi.Message = msg.Header.ID
 	ffi.Name = ffi.NetworkName
 	ffi.Published = true
-	return dh.handleFFIDefinition(ctx, state, &ffi, tx, isAuthor)
+	return dh.handleFFIDefinition(ctx, state, &ffi, msg.Header.Group, tx, isAuthor)
 }
 
-func (dh *definitionHandler) handleFFIDefinition(ctx context.Context, state *core.BatchState, ffi *fftypes.FFI, tx *fftypes.UUID, isAuthor bool) (HandlerResult, error) {
+func (dh *definitionHandler) handleFFIDefinition(ctx context.Context, state *core.BatchState, ffi *fftypes.FFI, group *fftypes.Bytes32, tx *fftypes.UUID, isAuthor bool) (HandlerResult, error) {
 	l := log.L(ctx)
 
 	ffi.Namespace = dh.namespace.Name
-	if retry, err := dh.persistFFI(ctx, ffi, isAuthor); err != nil {
+	if retry, err := dh.persistFFI(ctx, ffi, group, isAuthor); err != nil {
 		if retry {
 			return HandlerResult{Action: core.ActionRetry}, err
 		}
@@ -205,6 +205,7 @@ func (dh *definitionHandler) handleContractAPIBroadcast(ctx context.Context, sta
 	isAuthor := org.DID == msg.Header.Author
 
 	api.Message = msg.Header.ID
+	api.Group = msg.Header.Group
 	api.Name = api.NetworkName
 	api.Published = true
 	return dh.handleContractAPIDefinition(ctx, state, "", &api, tx, isAuthor)
@@ -112,7 +112,7 @@ func TestHandleFFIBroadcastOk(t *testing.T) {
 		Value: fftypes.JSONAnyPtrBytes(b),
 	}
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIEvent", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIError", mock.Anything, mock.Anything).Return(nil)
@@ -135,6 +135,42 @@ func TestHandleFFIBroadcastOk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHandleFFIPrivateGroup(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	b, err := json.Marshal(testFFI())
+	assert.NoError(t, err)
+	data := &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+	group := fftypes.NewRandB32()
+
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, group).Return(nil, nil)
+	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("UpsertFFIEvent", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("UpsertFFIError", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
+	dh.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypeDefinitionPrivate,
+			Tag:   core.SystemTagDefineFFI,
+			Group: group,
+		},
+	}, core.DataArray{data}, fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
 func TestHandleFFIBroadcastUpdate(t *testing.T) {
 	dh, bs := newTestDefinitionHandler(t)
 	defer dh.cleanup(t)
@@ -151,7 +187,7 @@ func TestHandleFFIBroadcastUpdate(t *testing.T) {
 		Message: ffi.Message,
 	}
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(existing, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(existing, nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
 	dh.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
@@ -189,10 +225,10 @@ func TestHandleFFIBroadcastNameExists(t *testing.T) {
 
 	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.MatchedBy(func(f *fftypes.FFI) bool {
 		return f.Name == "math"
-	})).Return(existing, nil)
+	}), mock.Anything).Return(existing, nil)
 	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.MatchedBy(func(f *fftypes.FFI) bool {
 		return f.Name == "math-1"
-	})).Return(nil, nil)
+	}), mock.Anything).Return(nil, nil)
 	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIEvent", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIError", mock.Anything, mock.Anything).Return(nil)
@@ -228,10 +264,10 @@ func TestHandleFFILocalNameExists(t *testing.T) {
 
 	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.MatchedBy(func(f *fftypes.FFI) bool {
 		return f.Name == "math"
-	})).Return(existing, nil)
+	}), mock.Anything).Return(existing, nil)
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
 
-	action, err := dh.handleFFIDefinition(context.Background(), &bs.BatchState, ffi, fftypes.NewUUID(), true)
+	action, err := dh.handleFFIDefinition(context.Background(), &bs.BatchState, ffi, nil, fftypes.NewUUID(), true)
 	assert.Regexp(t, "FF10407", err)
 	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
 	err = bs.RunFinalize(context.Background())
@@ -243,7 +279,7 @@ func TestPersistFFIValidateFFIFail(t *testing.T) {
 	defer dh.cleanup(t)
 
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
-	retry, err := dh.persistFFI(context.Background(), testFFI(), true)
+	retry, err := dh.persistFFI(context.Background(), testFFI(), nil, true)
 	assert.Regexp(t, "FF10403", err)
 	assert.False(t, retry)
 }
@@ -267,9 +303,9 @@ func TestPersistFFIUpsertFFIFail(t *testing.T) {
 	dh, _ := newTestDefinitionHandler(t)
 	defer dh.cleanup(t)
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	retry, err := dh.persistFFI(context.Background(), testFFI(), true)
+	retry, err := dh.persistFFI(context.Background(), testFFI(), nil, true)
 	assert.Regexp(t, "pop", err)
 	assert.True(t, retry)
 }
@@ -278,10 +314,10 @@ func TestPersistFFIUpsertFFIMethodFail(t *testing.T) {
 	dh, _ := newTestDefinitionHandler(t)
 	defer dh.cleanup(t)
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	retry, err := dh.persistFFI(context.Background(), testFFI(), true)
+	retry, err := dh.persistFFI(context.Background(), testFFI(), nil, true)
 	assert.Regexp(t, "pop", err)
 	assert.True(t, retry)
 }
@@ -290,11 +326,11 @@ func TestPersistFFIUpsertFFIEventFail(t *testing.T) {
 	dh, _ := newTestDefinitionHandler(t)
 	defer dh.cleanup(t)
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	retry, err := dh.persistFFI(context.Background(), testFFI(), true)
+	retry, err := dh.persistFFI(context.Background(), testFFI(), nil, true)
 	assert.Regexp(t, "pop", err)
 	assert.True(t, retry)
 }
@@ -303,12 +339,12 @@ func TestPersistFFIUpsertFFIErrorFail(t *testing.T) {
 	dh, _ := newTestDefinitionHandler(t)
 	defer dh.cleanup(t)
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	dh.mdi.On("UpsertFFIMethod", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIEvent", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("UpsertFFIError", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	retry, err := dh.persistFFI(context.Background(), testFFI(), true)
+	retry, err := dh.persistFFI(context.Background(), testFFI(), nil, true)
 	assert.Regexp(t, "pop", err)
 	assert.True(t, retry)
 }
@@ -323,11 +359,11 @@ func TestPersistFFILocalPublish(t *testing.T) {
 		ID: published.ID,
 	}
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(existing, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(existing, nil)
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("UpsertFFI", mock.Anything, published, database.UpsertOptimizationExisting).Return(nil)
+	dh.mdi.On("UpsertFFI", mock.Anything, published, (*fftypes.Bytes32)(nil), database.UpsertOptimizationExisting).Return(nil)
 
-	retry, err := dh.persistFFI(context.Background(), published, true)
+	retry, err := dh.persistFFI(context.Background(), published, nil, true)
 	assert.NoError(t, err)
 	assert.False(t, retry)
 }
@@ -342,11 +378,11 @@ func TestPersistFFILocalPublishUpsertFail(t *testing.T) {
 		ID: published.ID,
 	}
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(existing, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(existing, nil)
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("UpsertFFI", mock.Anything, published, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+	dh.mdi.On("UpsertFFI", mock.Anything, published, (*fftypes.Bytes32)(nil), database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
 
-	retry, err := dh.persistFFI(context.Background(), published, true)
+	retry, err := dh.persistFFI(context.Background(), published, nil, true)
 	assert.EqualError(t, err, "pop")
 	assert.True(t, retry)
 }
@@ -362,10 +398,10 @@ func TestPersistFFIWrongMessage(t *testing.T) {
 		Message: fftypes.NewUUID(),
 	}
 
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(existing, nil)
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(existing, nil)
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
 
-	retry, err := dh.persistFFI(context.Background(), published, true)
+	retry, err := dh.persistFFI(context.Background(), published, nil, true)
 	assert.Regexp(t, "FF10407", err)
 	assert.False(t, retry)
 }
@@ -404,7 +440,7 @@ func TestHandleFFIBroadcastPersistFail(t *testing.T) {
 	data := &core.Data{
 		Value: fftypes.JSONAnyPtrBytes(b),
 	}
-	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	dh.mdi.On("InsertOrGetFFI", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
 	dh.mcm.On("ResolveFFI", mock.Anything, mock.Anything).Return(nil)
 	dh.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
 		IdentityBase: core.IdentityBase{
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 func (dh *definitionHandler) handleDatatypeBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray, tx *fftypes.UUID) (HandlerResult, error) {
@@ -32,6 +33,7 @@ func (dh *definitionHandler) handleDatatypeBroadcast(ctx context.Context, state
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "datatype", msg.Header.ID)
 	}
 	dt.Namespace = dh.namespace.Name
+	dt.Group = msg.Header.Group
 	if err := dt.Validate(ctx, true); err != nil {
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedValidateFail, "datatype", dt.ID, err)
 	}
@@ -39,11 +41,17 @@ func (dh *definitionHandler) handleDatatypeBroadcast(ctx context.Context, state
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedSchemaFail, "datatype", dt.ID, err)
 	}
 
-	existing, err := dh.database.GetDatatypeByName(ctx, dt.Namespace, dt.Name, dt.Version)
+	// Names only need to be unique within the group the datatype is shared with (or across the network if broadcast)
+	fb := database.DatatypeQueryFactory.NewFilter(ctx)
+	existing, _, err := dh.database.GetDatatypes(ctx, dt.Namespace, fb.And(
+		fb.Eq("name", dt.Name),
+		fb.Eq("version", dt.Version),
+		fb.Eq("group", dt.Group),
+	).Limit(1))
 	if err != nil {
 		return HandlerResult{Action: core.ActionRetry}, err
-	} else if existing != nil {
-		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "datatype", dt.ID, existing.ID)
+	} else if len(existing) > 0 {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "datatype", dt.ID, existing[0].ID)
 	}
 
 	if err = dh.database.UpsertDatatype(ctx, &dt, false); err != nil {
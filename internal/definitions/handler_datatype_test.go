@@ -20,8 +20,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +50,7 @@ func TestHandleDefinitionBroadcastDatatypeOk(t *testing.T) {
 	}
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
@@ -83,7 +85,7 @@ func TestHandleDefinitionBroadcastDatatypeEventFail(t *testing.T) {
 	}
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
@@ -200,7 +202,7 @@ func TestHandleDefinitionBroadcastDatatypeLookupFail(t *testing.T) {
 	}
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, fmt.Errorf("pop"))
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
 		Header: core.MessageHeader{
 			Namespace: "ns1",
@@ -233,7 +235,7 @@ func TestHandleDefinitionBroadcastUpsertFail(t *testing.T) {
 	}
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(fmt.Errorf("pop"))
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
 		Header: core.MessageHeader{
@@ -266,7 +268,7 @@ func TestHandleDefinitionBroadcastDatatypeDuplicate(t *testing.T) {
 	}
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
-	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(dt, nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{dt}, nil, nil)
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
 		Header: core.MessageHeader{
 			Tag: core.SystemTagDefineDatatype,
@@ -277,3 +279,113 @@ func TestHandleDefinitionBroadcastDatatypeDuplicate(t *testing.T) {
 
 	bs.assertNoFinalizers()
 }
+
+func TestHandleDefinitionBroadcastDatatypePrivateOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "name1",
+		Version:   "ver1",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	}
+	dt.Hash = dt.Value.Hash()
+	b, err := json.Marshal(&dt)
+	assert.NoError(t, err)
+	data := &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	group := fftypes.NewRandB32()
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(&core.NetworkGovernance{DefinitionQuorum: 0}, nil)
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), group.String())
+	})).Return([]*core.Datatype{}, nil, nil)
+	dh.mdi.On("UpsertDatatype", mock.Anything, mock.MatchedBy(func(dt *core.Datatype) bool {
+		return dt.Group.Equals(group)
+	}), false).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypeDefinitionPrivate,
+			Tag:   core.SystemTagDefineDatatype,
+			Group: group,
+		},
+	}, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionBroadcastPrivateGoverned(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(&core.NetworkGovernance{DefinitionQuorum: 2}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypeDefinitionPrivate,
+			Tag:   core.SystemTagDefineDatatype,
+			Group: fftypes.NewRandB32(),
+		},
+	}, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10581", err)
+}
+
+func TestHandleDefinitionBroadcastPrivateGovernanceFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypeDefinitionPrivate,
+			Tag:   core.SystemTagDefineDatatype,
+			Group: fftypes.NewRandB32(),
+		},
+	}, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleDefinitionBroadcastPrivateNoGroup(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type: core.MessageTypeDefinitionPrivate,
+			Tag:  core.SystemTagDefineDatatype,
+		},
+	}, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10566", err)
+}
+
+func TestHandleDefinitionBroadcastPrivateIdentityClaim(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypeDefinitionPrivate,
+			Tag:   core.SystemTagIdentityClaim,
+			Group: fftypes.NewRandB32(),
+		},
+	}, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10566", err)
+}
@@ -0,0 +1,214 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// governedDefinitionTags are the definitions that are held as proposals, when the network governance sets a quorum
+var governedDefinitionTags = map[string]bool{
+	core.SystemTagDefineDatatype:          true,
+	core.SystemTagDefinePool:              true,
+	core.SystemTagDefineFFI:               true,
+	core.SystemTagDefineContractAPI:       true,
+	core.SystemTagDefineNetworkGovernance: true,
+}
+
+// definitionQuorum returns the number of root org approvals required before a definition is applied (0 if none).
+// The quorum is taken from the governance rules confirmed on the ledger, so every member holds the same definitions.
+func (dh *definitionHandler) definitionQuorum(ctx context.Context, tag string) (int, error) {
+	if !dh.multiparty || !governedDefinitionTags[tag] {
+		return 0, nil
+	}
+	governance, err := dh.database.GetNetworkGovernance(ctx, dh.namespace.Name)
+	if err != nil || governance == nil {
+		return 0, err
+	}
+	return governance.DefinitionQuorum, nil
+}
+
+func (dh *definitionHandler) handleDefinitionProposal(ctx context.Context, state *core.BatchState, msg *core.Message, tx *fftypes.UUID, quorum int) (HandlerResult, error) {
+	if len(msg.Header.Topics) != 1 {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "definition proposal", msg.Header.ID)
+	}
+
+	existing, err := dh.database.GetDefinitionProposalByID(ctx, dh.namespace.Name, msg.Header.ID)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if existing != nil {
+		// Proposal already recorded
+		return HandlerResult{Action: core.ActionConfirm}, nil
+	}
+
+	// The message is confirmed, but the definition itself is only applied once a quorum of votes is received.
+	// The quorum is recorded on the proposal, so that later governance changes do not affect it.
+	proposal := &core.DefinitionProposal{
+		ID:        msg.Header.ID,
+		Namespace: dh.namespace.Name,
+		Tag:       msg.Header.Tag,
+		Topic:     msg.Header.Topics[0],
+		Author:    msg.Header.Author,
+		TX:        tx,
+		State:     core.DefinitionProposalStateProposed,
+		Quorum:    quorum,
+		Votes:     core.DefinitionProposalVotes{},
+		Created:   fftypes.Now(),
+	}
+	if err := dh.database.InsertDefinitionProposal(ctx, proposal); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+
+	log.L(ctx).Infof("Definition '%s' [%s] proposed - awaiting %d approvals", proposal.Tag, proposal.ID, proposal.Quorum)
+	state.AddFinalize(func(ctx context.Context) error {
+		event := core.NewEvent(core.EventTypeDefinitionProposed, proposal.Namespace, proposal.ID, tx, core.SystemTopicDefinitions)
+		return dh.database.InsertEvent(ctx, event)
+	})
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+func (dh *definitionHandler) handleDefinitionVoteBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var vote core.DefinitionVote
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &vote); !valid || vote.Proposal == nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "definition vote", msg.Header.ID)
+	}
+
+	// Only root orgs can vote
+	org, retryable, err := dh.identity.CachedIdentityLookupNilOK(ctx, msg.Header.Author)
+	switch {
+	case err != nil && retryable:
+		return HandlerResult{Action: core.ActionRetry}, err
+	case err != nil:
+		return HandlerResult{Action: core.ActionReject}, err
+	case org == nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityNotFound, "definition vote", msg.Header.ID, msg.Header.Author)
+	case org.Type != core.IdentityTypeOrg || org.Parent != nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedNotRootOrg, "definition vote", msg.Header.ID, msg.Header.Author)
+	}
+
+	proposal, err := dh.database.GetDefinitionProposalByID(ctx, dh.namespace.Name, vote.Proposal)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if proposal == nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedProposalNotFound, "definition vote", msg.Header.ID, vote.Proposal)
+	}
+	if proposal.State != core.DefinitionProposalStateProposed {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedProposalClosed, "definition vote", msg.Header.ID, proposal.ID, proposal.State)
+	}
+	for _, v := range proposal.Votes {
+		if v.Org.Equals(org.ID) {
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedDuplicateVote, "definition vote", msg.Header.ID, org.DID, proposal.ID)
+		}
+	}
+
+	proposal.Votes = append(proposal.Votes, &core.DefinitionProposalVote{
+		Org:     org.ID,
+		Author:  msg.Header.Author,
+		Approve: vote.Approve,
+		Message: msg.Header.ID,
+		Created: fftypes.Now(),
+	})
+	if proposal.Votes.Count(true) >= proposal.Quorum {
+		if err := dh.applyDefinitionProposal(ctx, state, proposal); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	} else {
+		// The proposal is rejected as soon as there are not enough root orgs left that could approve it
+		rootOrgs, err := dh.countRootOrgs(ctx)
+		if err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+		if rootOrgs-proposal.Votes.Count(false) < proposal.Quorum {
+			dh.rejectDefinitionProposal(ctx, state, proposal, nil)
+		}
+	}
+
+	votesJSON, _ := json.Marshal(proposal.Votes)
+	proposal.Updated = fftypes.Now()
+	update := database.DefinitionProposalQueryFactory.NewUpdate(ctx).
+		Set("votes", fftypes.JSONAnyPtrBytes(votesJSON)).
+		Set("state", proposal.State).
+		Set("error", proposal.Error).
+		Set("updated", proposal.Updated)
+	if err := dh.database.UpdateDefinitionProposal(ctx, proposal.Namespace, proposal.ID, update); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+// countRootOrgs returns the number of root orgs in the network that have not been revoked, which are the orgs that can vote
+func (dh *definitionHandler) countRootOrgs(ctx context.Context) (int, error) {
+	fb := database.IdentityQueryFactory.NewFilter(ctx)
+	orgs, _, err := dh.database.GetIdentities(ctx, dh.namespace.Name, fb.And(
+		fb.Eq("type", core.IdentityTypeOrg),
+		fb.Eq("parent", nil),
+		fb.Eq("revoked", nil),
+	))
+	if err != nil {
+		return 0, err
+	}
+	return len(orgs), nil
+}
+
+// applyDefinitionProposal processes the original definition broadcast, once the proposal has reached a quorum of approvals.
+// A definition that fails to apply rejects the proposal, but the vote itself is still confirmed - so an error
+// is only returned if the vote should be retried.
+func (dh *definitionHandler) applyDefinitionProposal(ctx context.Context, state *core.BatchState, proposal *core.DefinitionProposal) error {
+	msg, data, foundAll, err := dh.data.GetMessageWithDataCached(ctx, proposal.ID)
+	if err != nil {
+		return err
+	}
+	if msg == nil || !foundAll {
+		dh.rejectDefinitionProposal(ctx, state, proposal, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "definition proposal", proposal.ID))
+		return nil
+	}
+
+	result, err := dh.applyDefinitionBroadcast(ctx, state, msg, data, proposal.TX)
+	switch result.Action {
+	case core.ActionRetry:
+		return err
+	case core.ActionReject:
+		dh.rejectDefinitionProposal(ctx, state, proposal, err)
+	default:
+		// Includes token pools, which are confirmed once they are activated by the connector
+		log.L(ctx).Infof("Definition '%s' [%s] approved", proposal.Tag, proposal.ID)
+		proposal.State = core.DefinitionProposalStateConfirmed
+	}
+	return nil
+}
+
+func (dh *definitionHandler) rejectDefinitionProposal(ctx context.Context, state *core.BatchState, proposal *core.DefinitionProposal, err error) {
+	log.L(ctx).Warnf("Definition '%s' [%s] rejected: %v", proposal.Tag, proposal.ID, err)
+	proposal.State = core.DefinitionProposalStateRejected
+	if err != nil {
+		proposal.Error = err.Error()
+	}
+	state.AddFinalize(func(ctx context.Context) error {
+		event := core.NewEvent(core.EventTypeDefinitionRejected, proposal.Namespace, proposal.ID, proposal.TX, core.SystemTopicDefinitions)
+		return dh.database.InsertEvent(ctx, event)
+	})
+}
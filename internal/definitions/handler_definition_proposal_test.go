@@ -0,0 +1,628 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestGovernedDefinitionHandler(t *testing.T, quorum int) (*testDefinitionHandler, *testDefinitionBatchState) {
+	dh, bs := newTestDefinitionHandler(t)
+	dh.multiparty = true
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(&core.NetworkGovernance{
+		Namespace:        "ns1",
+		DefinitionQuorum: quorum,
+	}, nil).Maybe()
+	return dh, bs
+}
+
+func (dh *testDefinitionHandler) mockRootOrgs(count int) {
+	orgs := make([]*core.Identity, count)
+	for i := range orgs {
+		orgs[i] = newTestRootOrg()
+	}
+	dh.mdi.On("GetIdentities", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( type == 'org' ) && ( parent == null ) && ( revoked == null )"
+	})).Return(orgs, nil, nil)
+}
+
+func newTestDatatypeProposal(t *testing.T) (*core.Message, core.DataArray) {
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Name:      "name1",
+		Version:   "ver1",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	}
+	dt.Hash = dt.Value.Hash()
+	b, err := json.Marshal(&dt)
+	assert.NoError(t, err)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Tag:    core.SystemTagDefineDatatype,
+			Topics: fftypes.FFStringArray{dt.Topic()},
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org1",
+			},
+		},
+	}
+	return msg, core.DataArray{{Value: fftypes.JSONAnyPtrBytes(b)}}
+}
+
+func newTestDefinitionVote(t *testing.T, proposal *core.DefinitionProposal, approve bool) (*core.Message, core.DataArray) {
+	vote := &core.DefinitionVote{
+		Proposal:      proposal.ID,
+		ProposalTopic: proposal.Topic,
+		Approve:       approve,
+	}
+	b, err := json.Marshal(&vote)
+	assert.NoError(t, err)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Tag:    core.SystemTagDefinitionVote,
+			Topics: fftypes.FFStringArray{proposal.Topic},
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org2",
+			},
+		},
+	}
+	return msg, core.DataArray{{Value: fftypes.JSONAnyPtrBytes(b)}}
+}
+
+func newTestRootOrg() *core.Identity {
+	return &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:   fftypes.NewUUID(),
+			DID:  "did:firefly:org/org2",
+			Type: core.IdentityTypeOrg,
+		},
+	}
+}
+
+func TestHandleDefinitionProposalOk(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	msg, data := newTestDatatypeProposal(t)
+	tx := fftypes.NewUUID()
+
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", msg.Header.ID).Return(nil, nil)
+	dh.mdi.On("InsertDefinitionProposal", mock.Anything, mock.MatchedBy(func(proposal *core.DefinitionProposal) bool {
+		return proposal.ID.Equals(msg.Header.ID) &&
+			proposal.Tag == core.SystemTagDefineDatatype &&
+			proposal.Topic == msg.Header.Topics[0] &&
+			proposal.Author == "did:firefly:org/org1" &&
+			proposal.TX.Equals(tx) &&
+			proposal.State == core.DefinitionProposalStateProposed &&
+			proposal.Quorum == 2
+	})).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDefinitionProposed && event.Reference.Equals(msg.Header.ID)
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, tx)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionProposalNotGoverned(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Tag: core.SystemTagIdentityUpdate,
+		},
+	}, core.DataArray{}, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionProposalGovernanceFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	msg, data := newTestDatatypeProposal(t)
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionProposalNoGovernance(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	// Until governance rules are confirmed on the ledger, definitions are applied as soon as they are received
+	msg, data := newTestDatatypeProposal(t)
+	dh.mdi.On("GetNetworkGovernance", mock.Anything, "ns1").Return(nil, nil)
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
+	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDatatypeConfirmed
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionProposalBadTopics(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	msg, data := newTestDatatypeProposal(t)
+	msg.Header.Topics = fftypes.FFStringArray{"topic1", "topic2"}
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionProposalLookupFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	msg, data := newTestDatatypeProposal(t)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", msg.Header.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionProposalExisting(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	msg, data := newTestDatatypeProposal(t)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", msg.Header.ID).Return(&core.DefinitionProposal{}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionProposalInsertFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	msg, data := newTestDatatypeProposal(t)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", msg.Header.ID).Return(nil, nil)
+	dh.mdi.On("InsertDefinitionProposal", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteRecorded(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+	org := newTestRootOrg()
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(org, false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mockRootOrgs(3)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateProposed, proposal.State)
+	assert.Len(t, proposal.Votes, 1)
+	assert.Equal(t, org.ID, proposal.Votes[0].Org)
+	assert.Equal(t, msg.Header.ID, proposal.Votes[0].Message)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteApproved(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposalMsg, proposalData := newTestDatatypeProposal(t)
+	proposal := newTestProposal()
+	proposal.ID = proposalMsg.Header.ID
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdm.On("GetMessageWithDataCached", mock.Anything, proposal.ID).Return(proposalMsg, proposalData, true, nil)
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
+	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDatatypeConfirmed && event.Transaction.Equals(proposal.TX)
+	})).Return(nil)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateConfirmed, proposal.State)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionVoteApprovedApplyFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposalMsg, proposalData := newTestDatatypeProposal(t)
+	proposal := newTestProposal()
+	proposal.ID = proposalMsg.Header.ID
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdm.On("GetMessageWithDataCached", mock.Anything, proposal.ID).Return(proposalMsg, proposalData, true, nil)
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return([]*core.Datatype{&core.Datatype{ID: fftypes.NewUUID()}}, nil, nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDefinitionRejected && event.Reference.Equals(proposal.ID)
+	})).Return(nil)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateRejected, proposal.State)
+	assert.Regexp(t, "FF10407", proposal.Error)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionVoteApprovedApplyRetry(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposalMsg, proposalData := newTestDatatypeProposal(t)
+	proposal := newTestProposal()
+	proposal.ID = proposalMsg.Header.ID
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdm.On("GetMessageWithDataCached", mock.Anything, proposal.ID).Return(proposalMsg, proposalData, true, nil)
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypes", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteApprovedMessageMissing(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdm.On("GetMessageWithDataCached", mock.Anything, proposal.ID).Return(nil, nil, false, nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateRejected, proposal.State)
+	assert.Regexp(t, "FF10400", proposal.Error)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionVoteApprovedMessageFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdm.On("GetMessageWithDataCached", mock.Anything, proposal.ID).Return(nil, nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteRejected(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	proposal.Quorum = 1
+	msg, data := newTestDefinitionVote(t, proposal, false)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mockRootOrgs(1)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDefinitionRejected
+	})).Return(nil)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateRejected, proposal.State)
+	assert.Empty(t, proposal.Error)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionVoteRejectedApprovalUnreachable(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	// With three root orgs and a quorum of two, the second rejection leaves too few orgs to approve
+	proposal := newTestProposal()
+	proposal.Votes = core.DefinitionProposalVotes{{Org: fftypes.NewUUID(), Approve: false}}
+	msg, data := newTestDefinitionVote(t, proposal, false)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mockRootOrgs(3)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDefinitionRejected
+	})).Return(nil)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateRejected, proposal.State)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionVoteRejectionRecorded(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	// With three root orgs and a quorum of two, a single rejection still allows approval
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, false)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mockRootOrgs(3)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionProposalStateProposed, proposal.State)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteCountOrgsFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, false)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mdi.On("GetIdentities", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteUpdateFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+	dh.mockRootOrgs(3)
+	dh.mdi.On("UpdateDefinitionProposal", mock.Anything, "ns1", proposal.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleDefinitionVoteDuplicate(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	org := newTestRootOrg()
+	proposal := newTestProposal()
+	proposal.Votes = core.DefinitionProposalVotes{{Org: org.ID, Approve: true}}
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(org, false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10565", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteProposalClosed(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	proposal.State = core.DefinitionProposalStateConfirmed
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(proposal, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10563", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteProposalNotFound(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(nil, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10562", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteProposalLookupFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", proposal.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteNotRootOrg(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+	org := newTestRootOrg()
+	org.Parent = fftypes.NewUUID()
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(org, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10564", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteIdentityNotFound(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteIdentityFail(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteIdentityRetry(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	proposal := newTestProposal()
+	msg, data := newTestDefinitionVote(t, proposal, true)
+
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionVoteBadPayload(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 2)
+	defer dh.cleanup(t)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Tag: core.SystemTagDefinitionVote,
+		},
+	}, core.DataArray{{Value: fftypes.JSONAnyPtr(`{}`)}}, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+	bs.assertNoFinalizers()
+}
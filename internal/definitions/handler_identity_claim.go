@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
@@ -54,6 +55,17 @@ func (dh *definitionHandler) handleIdentityClaimBroadcast(ctx context.Context, s
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "identity claim", msg.Header.ID)
 	}
 	claim.Identity.Messages.Claim = msg.Header.ID
+
+	// Once org onboarding is enabled, new root orgs can only join through an approved application
+	if dh.multiparty && claim.Identity.Type == core.IdentityTypeOrg && claim.Identity.Parent == nil {
+		governance, err := dh.database.GetNetworkGovernance(ctx, dh.namespace.Name)
+		if err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+		if governance != nil && governance.OrgOnboarding {
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedOrgOnboardingRequired, "identity claim", msg.Header.ID, claim.Identity.DID)
+		}
+	}
 	return dh.handleIdentityClaim(ctx, state, buildIdentityMsgInfo(msg, verifyMsgID), &claim)
 }
 
@@ -101,6 +113,38 @@ func (dh *definitionHandler) getClaimVerifier(msg *identityMsgInfo, identity *co
 	return verifier
 }
 
+// checkEncryptionVerifier validates the encryption public key published in the profile of a node, and returns
+// the verifier to store for it - nil if there is no key, or the key is already stored against this node
+func (dh *definitionHandler) checkEncryptionVerifier(ctx context.Context, identity *core.Identity, profile fftypes.JSONObject) (*core.Verifier, HandlerResult, error) {
+	publicKey := msgcrypt.ProfilePublicKey(profile)
+	if identity.Type != core.IdentityTypeNode || publicKey == "" {
+		return nil, HandlerResult{}, nil
+	}
+	if _, err := msgcrypt.ParsePublicKey(ctx, publicKey); err != nil {
+		return nil, HandlerResult{Action: core.ActionReject}, err
+	}
+	verifier := (&core.Verifier{
+		Identity:  identity.ID,
+		Namespace: identity.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeX25519PublicKey,
+			Value: publicKey,
+		},
+	}).Seal()
+	existingVerifier, err := dh.database.GetVerifierByValue(ctx, verifier.Type, identity.Namespace, verifier.Value)
+	if err != nil {
+		return nil, HandlerResult{Action: core.ActionRetry}, err // retry database errors
+	}
+	if existingVerifier != nil {
+		if !existingVerifier.Identity.Equals(identity.ID) {
+			verifierLabel := fmt.Sprintf("%s:%s", verifier.Type, verifier.Value)
+			return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity verifier", verifierLabel, existingVerifier.Identity)
+		}
+		return nil, HandlerResult{}, nil
+	}
+	return verifier, HandlerResult{}, nil
+}
+
 func (dh *definitionHandler) confirmVerificationForClaim(ctx context.Context, state *core.BatchState, msg *identityMsgInfo, identity, parent *core.Identity) (*fftypes.UUID, error) {
 	// Query for messages on the topic for this DID, signed by the right identity
 	idTopic := identity.Topic()
@@ -151,28 +195,57 @@ func (dh *definitionHandler) confirmVerificationForClaim(ctx context.Context, st
 	return nil, nil
 }
 
+// identityClaimUpdate is a validated identity claim, with the records that need to be stored to confirm it
+type identityClaimUpdate struct {
+	identity           *core.Identity
+	existingIdentity   *core.Identity
+	verifier           *core.Verifier
+	existingVerifier   *core.Verifier
+	encryptionVerifier *core.Verifier
+}
+
 func (dh *definitionHandler) handleIdentityClaim(ctx context.Context, state *core.BatchState, msg *identityMsgInfo, identityClaim *core.IdentityClaim) (HandlerResult, error) {
+	update, result, err := dh.checkIdentityClaim(ctx, state, msg, identityClaim, nil)
+	if update == nil {
+		return result, err
+	}
+	return dh.storeIdentityClaim(ctx, state, update)
+}
+
+// checkIdentityClaim validates an identity claim without writing anything, returning the update to store if it is valid.
+// A nil update is returned with a result, if the claim cannot be stored yet (or at all).
+// The pendingParent is set when the parent is being registered alongside the identity, so is not yet stored.
+func (dh *definitionHandler) checkIdentityClaim(ctx context.Context, state *core.BatchState, msg *identityMsgInfo, identityClaim *core.IdentityClaim, pendingParent *core.Identity) (*identityClaimUpdate, HandlerResult, error) {
 	l := log.L(ctx)
 
 	identity := identityClaim.Identity
 	identity.Namespace = dh.namespace.Name
-	parent, retryable, err := dh.identity.VerifyIdentityChain(ctx, identity)
-	if err != nil {
-		if retryable {
-			return HandlerResult{Action: core.ActionRetry}, err
+	parent := pendingParent
+	if pendingParent != nil {
+		if err := identity.Validate(ctx); err != nil {
+			return nil, HandlerResult{Action: core.ActionReject}, err
+		}
+	} else {
+		var retryable bool
+		var err error
+		parent, retryable, err = dh.identity.VerifyIdentityChain(ctx, identity)
+		if err != nil {
+			if retryable {
+				return nil, HandlerResult{Action: core.ActionRetry}, err
+			}
+			// This cannot be processed as something in the identity chain is invalid.
+			// We treat this as a park - because we don't know if the parent identity
+			// will be processed after this message and generate a rewind.
+			// They are on separate topics, so there is not ordering assurance between the two messages.
+			l.Infof("Unable to process identity claim (parked) %s: %s", msg.claimMsg.ID, err)
+			return nil, HandlerResult{Action: core.ActionWait}, nil
 		}
-		// This cannot be processed as something in the identity chain is invalid.
-		// We treat this as a park - because we don't know if the parent identity
-		// will be processed after this message and generate a rewind.
-		// They are on separate topics, so there is not ordering assurance between the two messages.
-		l.Infof("Unable to process identity claim (parked) %s: %s", msg.claimMsg.ID, err)
-		return HandlerResult{Action: core.ActionWait}, nil
 	}
 
 	// For multi-party namespaces, check that the claim message was appropriately signed
 	if dh.multiparty {
 		if err := dh.verifyClaimSignature(ctx, msg, identity, parent); err != nil {
-			return HandlerResult{Action: core.ActionReject}, err
+			return nil, HandlerResult{Action: core.ActionReject}, err
 		}
 	}
 
@@ -181,23 +254,29 @@ func (dh *definitionHandler) handleIdentityClaim(ctx context.Context, state *cor
 		existingIdentity, err = dh.database.GetIdentityByID(ctx, dh.namespace.Name, identity.ID)
 	}
 	if err != nil {
-		return HandlerResult{Action: core.ActionRetry}, err // retry database errors
+		return nil, HandlerResult{Action: core.ActionRetry}, err // retry database errors
 	}
 	if existingIdentity != nil && !existingIdentity.IdentityBase.Equals(ctx, &identity.IdentityBase) {
 		// If the existing one matches - this is just idempotent replay. No action needed, just confirm
-		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity claim", identity.ID, existingIdentity.ID)
+		return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity claim", identity.ID, existingIdentity.ID)
 	}
 
 	// Check uniqueness of verifier
 	verifier := dh.getClaimVerifier(msg, identity)
 	existingVerifier, err := dh.database.GetVerifierByValue(ctx, verifier.Type, identity.Namespace, verifier.Value)
 	if err != nil {
-		return HandlerResult{Action: core.ActionRetry}, err // retry database errors
+		return nil, HandlerResult{Action: core.ActionRetry}, err // retry database errors
 	}
 	if existingVerifier != nil && !existingVerifier.Identity.Equals(identity.ID) {
 		verifierLabel := fmt.Sprintf("%s:%s", verifier.Type, verifier.Value)
 		existingVerifierLabel := fmt.Sprintf("%s:%s", verifier.Type, verifier.Value)
-		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity verifier", verifierLabel, existingVerifierLabel)
+		return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity verifier", verifierLabel, existingVerifierLabel)
+	}
+
+	// Nodes can also publish a public key, for end-to-end encryption of private messages
+	encryptionVerifier, result, err := dh.checkEncryptionVerifier(ctx, identity, identity.Profile)
+	if err != nil {
+		return nil, result, err
 	}
 
 	// For child identities in multi-party namespaces, check that the parent signed a verification message
@@ -208,26 +287,43 @@ func (dh *definitionHandler) handleIdentityClaim(ctx context.Context, state *cor
 			// Search for a corresponding verification message on the same topic
 			msg.verifyMsg.ID, err = dh.confirmVerificationForClaim(ctx, state, msg, identity, parent)
 			if err != nil {
-				return HandlerResult{Action: core.ActionRetry}, err // retry database errors
+				return nil, HandlerResult{Action: core.ActionRetry}, err // retry database errors
 			}
 		}
 		if msg.verifyMsg.ID == nil {
 			// Ok, we still confirm the message as it's valid, and we do not want to block the context.
 			// But we do NOT go on to create the identity - we will be called back
 			log.L(ctx).Infof("Identity %s (%s) awaiting verification claim='%s'", identity.DID, identity.ID, msg.claimMsg.ID)
-			return HandlerResult{Action: core.ActionConfirm}, nil
+			return nil, HandlerResult{Action: core.ActionConfirm}, nil
 		}
 		log.L(ctx).Infof("Identity %s (%s) verified claim='%s' verification='%s'", identity.DID, identity.ID, msg.claimMsg.ID, msg.verifyMsg.ID)
 		identity.Messages.Verification = msg.verifyMsg.ID
 	}
 
-	if existingVerifier == nil {
-		if err = dh.database.UpsertVerifier(ctx, verifier, database.UpsertOptimizationNew); err != nil {
+	return &identityClaimUpdate{
+		identity:           identity,
+		existingIdentity:   existingIdentity,
+		verifier:           verifier,
+		existingVerifier:   existingVerifier,
+		encryptionVerifier: encryptionVerifier,
+	}, HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+// storeIdentityClaim stores an identity claim that has been validated by checkIdentityClaim
+func (dh *definitionHandler) storeIdentityClaim(ctx context.Context, state *core.BatchState, update *identityClaimUpdate) (HandlerResult, error) {
+	identity := update.identity
+	if update.existingVerifier == nil {
+		if err := dh.database.UpsertVerifier(ctx, update.verifier, database.UpsertOptimizationNew); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	}
+	if update.encryptionVerifier != nil {
+		if err := dh.database.UpsertVerifier(ctx, update.encryptionVerifier, database.UpsertOptimizationNew); err != nil {
 			return HandlerResult{Action: core.ActionRetry}, err
 		}
 	}
-	if existingIdentity == nil {
-		if err = dh.database.UpsertIdentity(ctx, identity, database.UpsertOptimizationNew); err != nil {
+	if update.existingIdentity == nil {
+		if err := dh.database.UpsertIdentity(ctx, identity, database.UpsertOptimizationNew); err != nil {
 			return HandlerResult{Action: core.ActionRetry}, err
 		}
 	}
@@ -539,3 +539,233 @@ func TestHandleDefinitionIdentityClaimBadData(t *testing.T) {
 
 	bs.assertNoFinalizers()
 }
+
+const testEncryptionPublicKey = "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo="
+
+func testNodeClaimWithEncryptionKey(t *testing.T, publicKey string) (*core.Identity, *core.Identity) {
+	org1 := testOrgIdentity(t, "org1")
+	node1 := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			Type:      core.IdentityTypeNode,
+			Namespace: "ns1",
+			Name:      "node1",
+			Parent:    org1.ID,
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id":                  "peer1",
+				"encryptionPublicKey": publicKey,
+			},
+		},
+	}
+	var err error
+	node1.DID, err = node1.GenerateDID(context.Background())
+	assert.NoError(t, err)
+	return node1, org1
+}
+
+func TestHandleIdentityClaimNodeEncryptionKey(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", node1.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", testEncryptionPublicKey).Return(nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Type == core.VerifierTypeFFDXPeerID
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Type == core.VerifierTypeX25519PublicKey &&
+			verifier.Value == testEncryptionPublicKey &&
+			verifier.Identity.Equals(node1.ID) &&
+			verifier.Hash != nil
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertIdentity", ctx, node1, database.UpsertOptimizationNew).Return(nil)
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleIdentityClaimNodeEncryptionKeyExisting(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(node1, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(&core.Verifier{Identity: node1.ID}, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", testEncryptionPublicKey).Return(&core.Verifier{Identity: node1.ID}, nil)
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleIdentityClaimNodeEncryptionKeyInvalid(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, "!!!")
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", node1.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(nil, nil)
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10533", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleIdentityClaimNodeEncryptionKeyClash(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", node1.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", testEncryptionPublicKey).Return(&core.Verifier{Identity: fftypes.NewUUID()}, nil)
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10407", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleIdentityClaimNodeEncryptionKeyLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", node1.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", testEncryptionPublicKey).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleIdentityClaimNodeEncryptionKeyInsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	node1, org1 := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+
+	dh.mim.On("VerifyIdentityChain", ctx, node1).Return(org1, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, node1.Type, node1.Namespace, node1.Name).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", node1.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", node1.Profile).Return("peer1")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer1").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", testEncryptionPublicKey).Return(nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Type == core.VerifierTypeFFDXPeerID
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Type == core.VerifierTypeX25519PublicKey
+	}), database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	action, err := dh.handleIdentityClaim(ctx, &bs.BatchState, &identityMsgInfo{}, &core.IdentityClaim{Identity: node1})
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func testRootOrgClaimMsg(t *testing.T, org *core.Identity) (*core.Message, *core.Data) {
+	b, err := json.Marshal(&core.IdentityClaim{Identity: org})
+	assert.NoError(t, err)
+	claimMsg := &core.Message{
+		Header: core.MessageHeader{
+			Namespace: "ns1",
+			ID:        org.Messages.Claim,
+			Type:      core.MessageTypeDefinition,
+			Tag:       core.SystemTagIdentityClaim,
+			Topics:    fftypes.FFStringArray{org.Topic()},
+			SignerRef: core.SignerRef{
+				Author: org.DID,
+				Key:    "0x12345",
+			},
+		},
+	}
+	return claimMsg, &core.Data{Value: fftypes.JSONAnyPtrBytes(b)}
+}
+
+func TestHandleDefinitionIdentityClaimRootOrgOnboardingRequired(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	ctx := context.Background()
+	claimMsg, claimData := testRootOrgClaimMsg(t, testOrgIdentity(t, "org1"))
+	dh.mdi.On("GetNetworkGovernance", ctx, "ns1").Return(&core.NetworkGovernance{OrgOnboarding: true}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10575", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityClaimRootOrgGovernanceFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	ctx := context.Background()
+	claimMsg, claimData := testRootOrgClaimMsg(t, testOrgIdentity(t, "org1"))
+	dh.mdi.On("GetNetworkGovernance", ctx, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityClaimRootOrgOnboardingDisabled(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+
+	ctx := context.Background()
+	org1 := testOrgIdentity(t, "org1")
+	claimMsg, claimData := testRootOrgClaimMsg(t, org1)
+	dh.mdi.On("GetNetworkGovernance", ctx, "ns1").Return(&core.NetworkGovernance{DefinitionQuorum: 1}, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, false, fmt.Errorf("wrong"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionWait}, action)
+	assert.NoError(t, err)
+
+	bs.assertNoFinalizers()
+}
@@ -0,0 +1,78 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (dh *definitionHandler) handleIdentityKeyRotationBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var rotation core.IdentityKeyRotation
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &rotation); !valid {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "identity key rotation", msg.Header.ID)
+	}
+	return dh.handleIdentityKeyRotation(ctx, state, &identityUpdateMsgInfo{
+		ID:     msg.Header.ID,
+		Author: msg.Header.Author,
+	}, &rotation)
+}
+
+func (dh *definitionHandler) handleIdentityKeyRotation(ctx context.Context, state *core.BatchState, msg *identityUpdateMsgInfo, rotation *core.IdentityKeyRotation) (HandlerResult, error) {
+	identity, result, err := dh.resolveIdentityForChange(ctx, "identity key rotation", msg, &rotation.Identity)
+	if identity == nil {
+		return result, err
+	}
+
+	verifierLabel := fmt.Sprintf("%s:%s", rotation.Verifier.Type, rotation.Verifier.Value)
+	if rotation.Verifier.Type == "" || rotation.Verifier.Value == "" {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedValidateFail, "identity key rotation", verifierLabel)
+	}
+
+	// The new verifier must not have been used before, by this or any other identity
+	existingVerifier, err := dh.database.GetVerifierByValue(ctx, rotation.Verifier.Type, identity.Namespace, rotation.Verifier.Value)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if existingVerifier != nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity verifier", verifierLabel, existingVerifier.Identity)
+	}
+
+	// Revoke the keys being replaced, then establish the new one
+	if err := dh.revokeActiveVerifiers(ctx, state, identity, rotation.Verifier.Type); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	verifier := (&core.Verifier{
+		Identity:    identity.ID,
+		Namespace:   identity.Namespace,
+		VerifierRef: rotation.Verifier,
+	}).Seal()
+	if err := dh.database.UpsertVerifier(ctx, verifier, database.UpsertOptimizationNew); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+
+	state.AddFinalize(func(ctx context.Context) error {
+		event := core.NewEvent(core.EventTypeIdentityUpdated, identity.Namespace, identity.ID, nil, core.SystemTopicDefinitions)
+		return dh.database.InsertEvent(ctx, event)
+	})
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
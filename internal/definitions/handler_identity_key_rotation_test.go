@@ -0,0 +1,216 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testIdentityKeyRotation(t *testing.T, newKey string) (*core.Identity, *core.Message, *core.Data) {
+	org1 := testOrgIdentity(t, "org1")
+
+	ik := &core.IdentityKeyRotation{
+		Identity: org1.IdentityBase,
+		Verifier: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: newKey,
+		},
+	}
+	b, err := json.Marshal(&ik)
+	assert.NoError(t, err)
+	rotateData := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	rotateMsg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagIdentityKeyRotation,
+			Topics: fftypes.FFStringArray{org1.Topic()},
+			SignerRef: core.SignerRef{
+				Author: org1.DID,
+				Key:    "0x12345",
+			},
+		},
+	}
+
+	return org1, rotateMsg, rotateData
+}
+
+func TestHandleDefinitionIdentityKeyRotationOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+	bs.PinSequence = 10
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+	v1 := testVerifier(org1, "0x12345")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		f, _ := filter.Finalize()
+		return f.String() == fmt.Sprintf("( identity == '%s' ) && ( type == 'ethereum_address' )", org1.ID)
+	})).Return([]*core.Verifier{v1}, nil, nil)
+	revokedVerifier := mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Value == "0x12345" && verifier.Revoked != nil && verifier.RevokedSequence == 10
+	})
+	dh.mdi.On("UpsertVerifier", ctx, revokedVerifier, database.UpsertOptimizationExisting).Return(nil)
+	dh.mim.On("InvalidateCachedVerifier", revokedVerifier).Return().Twice()
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Value == "0x23456" && verifier.Identity.Equals(org1.ID) && verifier.Hash != nil
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeIdentityUpdated
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	assert.Nil(t, v1.Revoked)
+
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+	bs.RunCleanup(ctx)
+
+	dh.mim.AssertExpectations(t)
+	dh.mdi.AssertExpectations(t)
+}
+
+func TestHandleDefinitionIdentityKeyRotationInsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationRevokeFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationConflict(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(testVerifier(testOrgIdentity(t, "org2"), "0x23456"), nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10407", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationLookupVerifierFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationMissingKey(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, rotateMsg, rotateData := testIdentityKeyRotation(t, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(nil, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{rotateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityKeyRotationMissingData(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, rotateMsg, _ := testIdentityKeyRotation(t, "0x23456")
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, rotateMsg, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+
+	bs.assertNoFinalizers()
+}
@@ -0,0 +1,160 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (dh *definitionHandler) handleIdentityRevocationBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var revocation core.IdentityRevocation
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &revocation); !valid {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "identity revocation", msg.Header.ID)
+	}
+	return dh.handleIdentityRevocation(ctx, state, &identityUpdateMsgInfo{
+		ID:     msg.Header.ID,
+		Author: msg.Header.Author,
+	}, &revocation)
+}
+
+// resolveIdentityForChange looks up the confirmed identity that is the subject of a change, and checks
+// the identity has not been revoked. In multiparty mode the chain of trust is verified, and the change
+// must be signed by the same identity that would be required to sign an update.
+func (dh *definitionHandler) resolveIdentityForChange(ctx context.Context, desc string, msg *identityUpdateMsgInfo, base *core.IdentityBase) (*core.Identity, HandlerResult, error) {
+	if err := base.Validate(ctx); err != nil {
+		return nil, HandlerResult{Action: core.ActionReject}, i18n.WrapError(ctx, err, coremsgs.MsgDefRejectedValidateFail, desc, base.ID)
+	}
+
+	identity, err := dh.identity.CachedIdentityLookupByID(ctx, base.ID)
+	if err != nil {
+		return nil, HandlerResult{Action: core.ActionRetry}, err
+	}
+	if identity == nil {
+		return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityNotFound, desc, base.ID, base.ID)
+	}
+	if identity.Revoked != nil {
+		return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityRevoked, desc, base.ID, identity.DID)
+	}
+
+	if dh.multiparty {
+
+		parent, retryable, err := dh.identity.VerifyIdentityChain(ctx, identity)
+		if err != nil && retryable {
+			return nil, HandlerResult{Action: core.ActionRetry}, err
+		} else if err != nil {
+			log.L(ctx).Infof("Unable to process %s (parked) %s: %s", desc, msg.ID, err)
+			return nil, HandlerResult{Action: core.ActionWait}, nil
+		}
+
+		// Check the author matches
+		expectedSigner := dh.getExpectedSigner(identity, parent)
+		if expectedSigner.DID != msg.Author {
+			return nil, HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedWrongAuthor, desc, base.ID, msg.Author)
+		}
+
+	}
+	return identity, HandlerResult{}, nil
+}
+
+// revokeVerifier marks a verifier as revoked from the sequence of the pin currently being processed.
+// The update is made to a copy, as the cached object is shared. The verifier is dropped from the cache now,
+// so subsequent messages in the batch see the revocation, and again once the batch completes in case it rolled back.
+func (dh *definitionHandler) revokeVerifier(ctx context.Context, state *core.BatchState, verifier *core.Verifier) error {
+	revoked := *verifier
+	revoked.Revoked = fftypes.Now()
+	revoked.RevokedSequence = state.PinSequence
+	if err := dh.database.UpsertVerifier(ctx, &revoked, database.UpsertOptimizationExisting); err != nil {
+		return err
+	}
+	dh.identity.InvalidateCachedVerifier(&revoked)
+	state.AddCleanup(func(ctx context.Context) {
+		dh.identity.InvalidateCachedVerifier(&revoked)
+	})
+	return nil
+}
+
+// revokeActiveVerifiers revokes all of the active verifiers of an identity, optionally restricted to a single type
+func (dh *definitionHandler) revokeActiveVerifiers(ctx context.Context, state *core.BatchState, identity *core.Identity, vType core.VerifierType) error {
+	fb := database.VerifierQueryFactory.NewFilter(ctx)
+	filter := fb.And(fb.Eq("identity", identity.ID))
+	if vType != "" {
+		filter = filter.Condition(fb.Eq("type", vType))
+	}
+	verifiers, _, err := dh.database.GetVerifiers(ctx, identity.Namespace, filter)
+	if err != nil {
+		return err
+	}
+	for _, verifier := range verifiers {
+		if verifier.Revoked == nil {
+			if err := dh.revokeVerifier(ctx, state, verifier); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (dh *definitionHandler) handleIdentityRevocation(ctx context.Context, state *core.BatchState, msg *identityUpdateMsgInfo, revocation *core.IdentityRevocation) (HandlerResult, error) {
+	identity, result, err := dh.resolveIdentityForChange(ctx, "identity revocation", msg, &revocation.Identity)
+	if identity == nil {
+		return result, err
+	}
+
+	if revocation.Verifier != nil {
+		// Revoke a single verifier, leaving the identity itself active
+		verifier, err := dh.identity.CachedVerifierLookup(ctx, revocation.Verifier)
+		if err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+		if verifier == nil || !verifier.Identity.Equals(identity.ID) || verifier.Revoked != nil {
+			verifierLabel := fmt.Sprintf("%s:%s", revocation.Verifier.Type, revocation.Verifier.Value)
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedVerifierNotActive, "identity revocation", revocation.Identity.ID, verifierLabel)
+		}
+		if err := dh.revokeVerifier(ctx, state, verifier); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	} else {
+		// Retire the identity, and all of its verifiers
+		if err := dh.revokeActiveVerifiers(ctx, state, identity, ""); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+		revoked := *identity
+		revoked.Revoked = fftypes.Now()
+		revoked.Messages.Revocation = msg.ID
+		if err := dh.database.UpsertIdentity(ctx, &revoked, database.UpsertOptimizationExisting); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+		dh.identity.InvalidateCachedIdentity(&revoked)
+		state.AddCleanup(func(ctx context.Context) {
+			dh.identity.InvalidateCachedIdentity(&revoked)
+		})
+	}
+
+	state.AddFinalize(func(ctx context.Context) error {
+		event := core.NewEvent(core.EventTypeIdentityUpdated, identity.Namespace, identity.ID, nil, core.SystemTopicDefinitions)
+		return dh.database.InsertEvent(ctx, event)
+	})
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
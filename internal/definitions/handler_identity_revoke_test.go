@@ -0,0 +1,388 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testIdentityRevocation(t *testing.T, verifier *core.VerifierRef) (*core.Identity, *core.Message, *core.Data) {
+	org1 := testOrgIdentity(t, "org1")
+
+	ir := &core.IdentityRevocation{
+		Identity: org1.IdentityBase,
+		Verifier: verifier,
+	}
+	b, err := json.Marshal(&ir)
+	assert.NoError(t, err)
+	revokeData := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	revokeMsg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagIdentityRevoke,
+			Topics: fftypes.FFStringArray{org1.Topic()},
+			SignerRef: core.SignerRef{
+				Author: org1.DID,
+				Key:    "0x12345",
+			},
+		},
+	}
+
+	return org1, revokeMsg, revokeData
+}
+
+func testVerifier(identity *core.Identity, value string) *core.Verifier {
+	return (&core.Verifier{
+		Identity:  identity.ID,
+		Namespace: identity.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: value,
+		},
+	}).Seal()
+}
+
+func TestHandleDefinitionIdentityRevokeOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+	bs.PinSequence = 10
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+	v1 := testVerifier(org1, "0x12345")
+	v2 := testVerifier(org1, "0x23456")
+	v3 := testVerifier(org1, "0x34567")
+	v3.Revoked = fftypes.Now()
+
+	revokedVerifier := func(v *core.Verifier) interface{} {
+		return mock.MatchedBy(func(revoked *core.Verifier) bool {
+			return revoked.Hash.Equals(v.Hash) && revoked.Revoked != nil && revoked.RevokedSequence == 10
+		})
+	}
+	revokedIdentity := mock.MatchedBy(func(identity *core.Identity) bool {
+		return identity.ID.Equals(org1.ID) && identity.Revoked != nil && identity.Messages.Revocation.Equals(revokeMsg.Header.ID)
+	})
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{v1, v2, v3}, nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, revokedVerifier(v1), database.UpsertOptimizationExisting).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, revokedVerifier(v2), database.UpsertOptimizationExisting).Return(nil)
+	dh.mim.On("InvalidateCachedVerifier", revokedVerifier(v1)).Return().Twice()
+	dh.mim.On("InvalidateCachedVerifier", revokedVerifier(v2)).Return().Twice()
+	dh.mdi.On("UpsertIdentity", ctx, revokedIdentity, database.UpsertOptimizationExisting).Return(nil)
+	dh.mim.On("InvalidateCachedIdentity", revokedIdentity).Return().Twice()
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeIdentityUpdated
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	// The objects that were looked up (and might be cached) are not modified
+	assert.Nil(t, org1.Revoked)
+	assert.Nil(t, v1.Revoked)
+	assert.Nil(t, v2.Revoked)
+	assert.Zero(t, v3.RevokedSequence)
+
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+	bs.RunCleanup(ctx)
+
+	dh.mim.AssertExpectations(t)
+	dh.mdi.AssertExpectations(t)
+}
+
+func TestHandleDefinitionIdentityRevokeVerifierOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	bs.PinSequence = 10
+
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, verifierRef)
+	v1 := testVerifier(org1, "0x23456")
+
+	revokedVerifier := mock.MatchedBy(func(revoked *core.Verifier) bool {
+		return revoked.Hash.Equals(v1.Hash) && revoked.Revoked != nil && revoked.RevokedSequence == 10
+	})
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("CachedVerifierLookup", ctx, verifierRef).Return(v1, nil)
+	dh.mdi.On("UpsertVerifier", ctx, revokedVerifier, database.UpsertOptimizationExisting).Return(nil)
+	dh.mim.On("InvalidateCachedVerifier", revokedVerifier).Return().Once()
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	assert.Nil(t, v1.Revoked)
+	assert.Nil(t, org1.Revoked)
+
+	// The batch rolls back, and the verifier is dropped from the cache again so it is re-read as active
+	dh.mim.On("InvalidateCachedVerifier", revokedVerifier).Return().Once()
+	bs.RunCleanup(ctx)
+
+	dh.mim.AssertExpectations(t)
+	dh.mdi.AssertExpectations(t)
+}
+
+func TestHandleDefinitionIdentityRevokeVerifierNotActive(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, verifierRef)
+	v1 := testVerifier(org1, "0x23456")
+	v1.Identity = fftypes.NewUUID()
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("CachedVerifierLookup", ctx, verifierRef).Return(v1, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10555", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeVerifierLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, verifierRef)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("CachedVerifierLookup", ctx, verifierRef).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeVerifierUpsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, verifierRef)
+	v1 := testVerifier(org1, "0x23456")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("CachedVerifierLookup", ctx, verifierRef).Return(v1, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeGetVerifiersFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeUpsertVerifiersFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+	v1 := testVerifier(org1, "0x12345")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{v1}, nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeUpsertIdentityFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	dh.mdi.On("UpsertIdentity", ctx, mock.Anything, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeAlreadyRevoked(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+	org1.Revoked = fftypes.Now()
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10554", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeWrongAuthor(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+	revokeMsg.Header.Author = "wrong"
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10409", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeVerifyFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeVerifyWait(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionWait}, action)
+	assert.NoError(t, err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(nil, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, revokeMsg, revokeData := testIdentityRevocation(t, nil)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeValidateFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, revokeMsg, _ := testIdentityRevocation(t, nil)
+	revokeData := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"identity":{"did":"wrong"}}`),
+	}
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{revokeData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityRevokeMissingData(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, revokeMsg, _ := testIdentityRevocation(t, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, revokeMsg, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+
+	bs.assertNoFinalizers()
+}
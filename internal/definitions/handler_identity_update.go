@@ -75,6 +75,12 @@ func (dh *definitionHandler) handleIdentityUpdate(ctx context.Context, state *co
 
 	}
 
+	// A node might publish a new (rotated) encryption key in its profile
+	encryptionVerifier, result, err := dh.checkEncryptionVerifier(ctx, identity, update.Updates.Profile)
+	if err != nil {
+		return result, err
+	}
+
 	// Update the profile
 	identity.IdentityProfile = update.Updates
 	identity.Messages.Update = msg.ID
@@ -82,6 +88,11 @@ func (dh *definitionHandler) handleIdentityUpdate(ctx context.Context, state *co
 	if err != nil {
 		return HandlerResult{Action: core.ActionRetry}, err
 	}
+	if encryptionVerifier != nil {
+		if err = dh.database.UpsertVerifier(ctx, encryptionVerifier, database.UpsertOptimizationNew); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	}
 
 	state.AddFinalize(func(ctx context.Context) error {
 		event := core.NewEvent(core.EventTypeIdentityUpdated, identity.Namespace, identity.ID, nil, core.SystemTopicDefinitions)
@@ -250,3 +250,95 @@ func TestHandleDefinitionIdentityMissingData(t *testing.T) {
 
 	bs.assertNoFinalizers()
 }
+
+func testNodeIdentityUpdate(t *testing.T, publicKey string) (*core.Identity, *core.Message, *core.Data) {
+	node1, _ := testNodeClaimWithEncryptionKey(t, testEncryptionPublicKey)
+	iu := &core.IdentityUpdate{
+		Identity: node1.IdentityBase,
+		Updates: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id":                  "peer1",
+				"encryptionPublicKey": publicKey,
+			},
+		},
+	}
+	b, err := json.Marshal(&iu)
+	assert.NoError(t, err)
+	updateData := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+	updateMsg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagIdentityUpdate,
+			Topics: fftypes.FFStringArray{node1.Topic()},
+		},
+	}
+	return node1, updateMsg, updateData
+}
+
+func TestHandleDefinitionIdentityUpdateNodeEncryptionKey(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	rotatedKey := "3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08="
+	node1, updateMsg, updateData := testNodeIdentityUpdate(t, rotatedKey)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, node1.ID).Return(node1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", rotatedKey).Return(nil, nil)
+	dh.mdi.On("UpsertIdentity", ctx, mock.MatchedBy(func(identity *core.Identity) bool {
+		return identity.Profile.GetString("encryptionPublicKey") == rotatedKey
+	}), database.UpsertOptimizationExisting).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Type == core.VerifierTypeX25519PublicKey &&
+			verifier.Value == rotatedKey &&
+			verifier.Identity.Equals(node1.ID)
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeIdentityUpdated
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, updateMsg, core.DataArray{updateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionIdentityUpdateNodeEncryptionKeyInvalid(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	node1, updateMsg, updateData := testNodeIdentityUpdate(t, "!!!")
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, node1.ID).Return(node1, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, updateMsg, core.DataArray{updateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10533", err)
+	assert.Equal(t, testEncryptionPublicKey, node1.Profile.GetString("encryptionPublicKey"))
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityUpdateNodeEncryptionKeyInsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	rotatedKey := "3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08="
+	node1, updateMsg, updateData := testNodeIdentityUpdate(t, rotatedKey)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, node1.ID).Return(node1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeX25519PublicKey, "ns1", rotatedKey).Return(nil, nil)
+	dh.mdi.On("UpsertIdentity", ctx, mock.Anything, database.UpsertOptimizationExisting).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, updateMsg, core.DataArray{updateData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
@@ -0,0 +1,60 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+func (dh *definitionHandler) handleNetworkGovernanceBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var governance core.NetworkGovernance
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &governance); !valid {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "network governance", msg.Header.ID)
+	}
+	if err := governance.Validate(ctx); err != nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.WrapError(ctx, err, coremsgs.MsgDefRejectedValidateFail, "network governance", msg.Header.ID)
+	}
+
+	// Only root orgs can change the governance rules
+	org, retryable, err := dh.identity.CachedIdentityLookupNilOK(ctx, msg.Header.Author)
+	switch {
+	case err != nil && retryable:
+		return HandlerResult{Action: core.ActionRetry}, err
+	case err != nil:
+		return HandlerResult{Action: core.ActionReject}, err
+	case org == nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityNotFound, "network governance", msg.Header.ID, msg.Header.Author)
+	case org.Type != core.IdentityTypeOrg || org.Parent != nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedNotRootOrg, "network governance", msg.Header.ID, msg.Header.Author)
+	}
+
+	governance.Namespace = dh.namespace.Name
+	governance.Author = msg.Header.Author
+	governance.Message = msg.Header.ID
+	governance.Updated = fftypes.Now()
+	if err := dh.database.UpsertNetworkGovernance(ctx, &governance); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	log.L(ctx).Infof("Network governance updated by %s: definitionQuorum=%d", governance.Author, governance.DefinitionQuorum)
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
@@ -0,0 +1,178 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestNetworkGovernance(t *testing.T, quorum int) (*core.Message, core.DataArray) {
+	governance := &core.NetworkGovernance{
+		DefinitionQuorum: quorum,
+	}
+	b, err := json.Marshal(&governance)
+	assert.NoError(t, err)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Tag:    core.SystemTagDefineNetworkGovernance,
+			Topics: fftypes.FFStringArray{governance.Topic()},
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org2",
+			},
+		},
+	}
+	return msg, core.DataArray{{Value: fftypes.JSONAnyPtrBytes(b)}}
+}
+
+func TestHandleNetworkGovernanceOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 2)
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("UpsertNetworkGovernance", mock.Anything, mock.MatchedBy(func(governance *core.NetworkGovernance) bool {
+		return governance.Namespace == "ns1" &&
+			governance.DefinitionQuorum == 2 &&
+			governance.Author == "did:firefly:org/org2" &&
+			governance.Message.Equals(msg.Header.ID)
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceProposed(t *testing.T) {
+	dh, bs := newTestGovernedDefinitionHandler(t, 1)
+	defer dh.cleanup(t)
+
+	// Changes to the governance rules need the approval of the current quorum
+	msg, data := newTestNetworkGovernance(t, 0)
+	dh.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns1", msg.Header.ID).Return(nil, nil)
+	dh.mdi.On("InsertDefinitionProposal", mock.Anything, mock.MatchedBy(func(proposal *core.DefinitionProposal) bool {
+		return proposal.Tag == core.SystemTagDefineNetworkGovernance && proposal.Quorum == 1
+	})).Return(nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	err = bs.RunFinalize(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandleNetworkGovernanceBadPayload(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, _ := newTestNetworkGovernance(t, 1)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, core.DataArray{}, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceBadQuorum(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, -1)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403.*FF10574", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceLookupRetry(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 1)
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 1)
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceAuthorNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 1)
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(nil, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceNotRootOrg(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 1)
+	org := newTestRootOrg()
+	org.Parent = fftypes.NewUUID()
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(org, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10564", err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleNetworkGovernanceUpsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := newTestNetworkGovernance(t, 1)
+	dh.mim.On("CachedIdentityLookupNilOK", mock.Anything, "did:firefly:org/org2").Return(newTestRootOrg(), false, nil)
+	dh.mdi.On("UpsertNetworkGovernance", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, data, nil)
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+	bs.assertNoFinalizers()
+}
@@ -0,0 +1,169 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (dh *definitionHandler) handleOrgApplicationBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var application core.OrgApplication
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &application); !valid {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "org application", msg.Header.ID)
+	}
+	application.Namespace = dh.namespace.Name
+	if err := application.Validate(ctx); err != nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.WrapError(ctx, err, coremsgs.MsgDefRejectedValidateFail, "org application", application.ID)
+	}
+
+	// The applicant signs with the key of the org, as the DID the org will be registered with
+	if msg.Header.Author != application.OrgDID() || msg.Header.Key != application.Key {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedSignatureMismatch, "org application", msg.Header.ID)
+	}
+
+	existing, err := dh.database.GetOrgApplicationByID(ctx, dh.namespace.Name, application.ID)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if existing != nil {
+		if existing.Message.Equals(msg.Header.ID) {
+			// Idempotent replay of the same application
+			return HandlerResult{Action: core.ActionConfirm}, nil
+		}
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "org application", application.ID, existing.Message)
+	}
+
+	// Only root orgs can sponsor an application
+	sponsor, retryable, err := dh.identity.CachedIdentityLookupNilOK(ctx, application.Sponsor)
+	switch {
+	case err != nil && retryable:
+		return HandlerResult{Action: core.ActionRetry}, err
+	case err != nil:
+		return HandlerResult{Action: core.ActionReject}, err
+	case sponsor == nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityNotFound, "org application", application.ID, application.Sponsor)
+	case sponsor.Type != core.IdentityTypeOrg || sponsor.Parent != nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedSponsorNotRootOrg, "org application", application.ID, application.Sponsor)
+	}
+
+	application.Sponsor = sponsor.DID
+	application.State = core.OrgApplicationStatePending
+	application.Reason = ""
+	application.Review = nil
+	application.Created = fftypes.Now()
+	application.Updated = nil
+	if err := dh.database.InsertOrgApplication(ctx, &application); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	log.L(ctx).Infof("Org application %s for org=%s node=%s awaiting review by sponsor %s", application.ID, application.Org.Name, application.Node.Name, application.Sponsor)
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+func (dh *definitionHandler) handleOrgApplicationReviewBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var review core.OrgApplicationReview
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &review); !valid || review.Application == nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "org application review", msg.Header.ID)
+	}
+
+	application, err := dh.database.GetOrgApplicationByID(ctx, dh.namespace.Name, review.Application)
+	switch {
+	case err != nil:
+		return HandlerResult{Action: core.ActionRetry}, err
+	case application == nil:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedApplicationNotFound, "org application review", msg.Header.ID, review.Application)
+	case application.State != core.OrgApplicationStatePending:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedApplicationClosed, "org application review", msg.Header.ID, application.ID, application.State)
+	case msg.Header.Author != application.Sponsor:
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedWrongAuthor, "org application review", msg.Header.ID, msg.Header.Author)
+	}
+
+	application.State = core.OrgApplicationStateRejected
+	application.Reason = review.Reason
+	if review.Approve {
+		result, err := dh.registerOrgApplication(ctx, state, application)
+		switch result.Action {
+		case core.ActionRetry, core.ActionWait:
+			// The review is parked until the registration can be completed
+			return result, err
+		case core.ActionConfirm:
+			application.State = core.OrgApplicationStateApproved
+		default:
+			log.L(ctx).Errorf("Unable to register org and node for approved application %s: %v", application.ID, err)
+			if err != nil {
+				application.Reason = err.Error()
+			}
+		}
+	}
+
+	update := database.OrgApplicationQueryFactory.NewUpdate(ctx).
+		Set("state", application.State).
+		Set("reason", application.Reason).
+		Set("review", msg.Header.ID).
+		Set("updated", fftypes.Now())
+	if err := dh.database.UpdateOrgApplication(ctx, dh.namespace.Name, application.ID, update); err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	log.L(ctx).Infof("Org application %s reviewed by sponsor %s: %s", application.ID, application.Sponsor, application.State)
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+// registerOrgApplication registers the org and node of an approved application, exactly as if they had been
+// claimed by the applicant. Both claims are attributed to the application message, which the org key signed.
+// Both claims are checked before either is stored, so the org is never registered without its node.
+func (dh *definitionHandler) registerOrgApplication(ctx context.Context, state *core.BatchState, application *core.OrgApplication) (HandlerResult, error) {
+	msgInfo := &identityMsgInfo{
+		SignerRef: core.SignerRef{
+			Author: application.OrgDID(),
+			Key:    application.Key,
+		},
+	}
+	msgInfo.claimMsg.ID = application.Message
+
+	org := application.OrgIdentity()
+	orgUpdate, result, err := dh.checkIdentityClaim(ctx, state, msgInfo, &core.IdentityClaim{Identity: org}, nil)
+	if orgUpdate == nil {
+		return orgApplicationClaimResult(result, err)
+	}
+	// The node is checked against the org it is being registered with, as the org is not stored yet
+	nodeUpdate, result, err := dh.checkIdentityClaim(ctx, state, msgInfo, &core.IdentityClaim{Identity: application.NodeIdentity()}, org)
+	if nodeUpdate == nil {
+		return orgApplicationClaimResult(result, err)
+	}
+
+	for _, update := range []*identityClaimUpdate{orgUpdate, nodeUpdate} {
+		if result, err := dh.storeIdentityClaim(ctx, state, update); result.Action != core.ActionConfirm {
+			return result, err
+		}
+	}
+	return HandlerResult{Action: core.ActionConfirm}, nil
+}
+
+// orgApplicationClaimResult maps the result of a claim that could not be stored to the result of the registration.
+// A claim that is valid but would not be stored yet cannot happen for an org or node, so is treated as a rejection.
+func orgApplicationClaimResult(result HandlerResult, err error) (HandlerResult, error) {
+	if result.Action == core.ActionConfirm {
+		return HandlerResult{Action: core.ActionReject}, err
+	}
+	return result, err
+}
@@ -0,0 +1,569 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testOrgApplication() *core.OrgApplication {
+	return &core.OrgApplication{
+		ID:      fftypes.NewUUID(),
+		Sponsor: "org1",
+		Org: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "org2",
+		},
+		Node: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "node2",
+			IdentityProfile: core.IdentityProfile{
+				Profile: fftypes.JSONObject{"id": "peer2"},
+			},
+		},
+		Key: "0x23456",
+	}
+}
+
+func testOrgApplicationMsg(t *testing.T, application *core.OrgApplication) (*core.Message, *core.Data) {
+	b, err := json.Marshal(application)
+	assert.NoError(t, err)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagOrgApplication,
+			Topics: fftypes.FFStringArray{application.Topic()},
+			SignerRef: core.SignerRef{
+				Author: application.OrgDID(),
+				Key:    application.Key,
+			},
+		},
+	}, &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+}
+
+func testOrgApplicationReviewMsg(t *testing.T, review *core.OrgApplicationReview) (*core.Message, *core.Data) {
+	b, err := json.Marshal(review)
+	assert.NoError(t, err)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagOrgApplicationReview,
+			Topics: fftypes.FFStringArray{review.Topic()},
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org1",
+				Key:    "0x12345",
+			},
+		},
+	}, &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+}
+
+func testPendingOrgApplication(msg *core.Message) *core.OrgApplication {
+	application := testOrgApplication()
+	application.Namespace = "ns1"
+	application.Sponsor = "did:firefly:org/org1"
+	application.State = core.OrgApplicationStatePending
+	application.Message = msg.Header.ID
+	return application
+}
+
+func updateValue(info *ffapi.UpdateInfo, idx int) interface{} {
+	val, _ := info.SetOperations[idx].Value.Value()
+	return val
+}
+
+func TestHandleOrgApplicationOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	org1 := testOrgIdentity(t, "org1")
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(org1, false, nil)
+	dh.mdi.On("InsertOrgApplication", ctx, mock.MatchedBy(func(a *core.OrgApplication) bool {
+		return a.ID.Equals(application.ID) &&
+			a.Namespace == "ns1" &&
+			a.Sponsor == org1.DID &&
+			a.State == core.OrgApplicationStatePending &&
+			a.Message.Equals(msg.Header.ID) &&
+			a.Created != nil
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleOrgApplicationBadPayload(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+}
+
+func TestHandleOrgApplicationInvalid(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	application := testOrgApplication()
+	application.Sponsor = ""
+	msg, data := testOrgApplicationMsg(t, application)
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403.*FF00112", err)
+}
+
+func TestHandleOrgApplicationSignatureMismatch(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	msg.Header.Key = "0x99999"
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10402", err)
+}
+
+func TestHandleOrgApplicationLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationReplay(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(testPendingOrgApplication(msg), nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleOrgApplicationConflict(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	existing := testPendingOrgApplication(msg)
+	existing.Message = fftypes.NewUUID()
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(existing, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10407", err)
+}
+
+func TestHandleOrgApplicationSponsorLookupRetry(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationSponsorLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationSponsorNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(nil, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+}
+
+func TestHandleOrgApplicationSponsorNotRootOrg(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	org1 := testOrgIdentity(t, "org1")
+	org1.Parent = fftypes.NewUUID()
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(org1, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10567", err)
+}
+
+func TestHandleOrgApplicationInsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	application := testOrgApplication()
+	msg, data := testOrgApplicationMsg(t, application)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(nil, nil)
+	dh.mim.On("CachedIdentityLookupNilOK", ctx, "org1").Return(testOrgIdentity(t, "org1"), false, nil)
+	dh.mdi.On("InsertOrgApplication", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationReviewApproveOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true, Reason: "welcome"}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.MatchedBy(func(identity *core.Identity) bool {
+		return identity.Type == core.IdentityTypeOrg
+	})).Return(nil, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeOrg, "ns1", "org2").Return(nil, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeNode, "ns1", "node2").Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", application.Org.ID).Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", application.Node.ID).Return(nil, nil)
+	dh.mdx.On("GetPeerID", application.Node.Profile).Return("peer2")
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeFFDXPeerID, "ns1", "peer2").Return(nil, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil).Twice()
+	dh.mdi.On("UpsertIdentity", ctx, mock.MatchedBy(func(identity *core.Identity) bool {
+		return identity.Type == core.IdentityTypeOrg && identity.DID == "did:firefly:org/org2" &&
+			identity.Messages.Claim.Equals(appMsg.Header.ID)
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertIdentity", ctx, mock.MatchedBy(func(identity *core.Identity) bool {
+		return identity.Type == core.IdentityTypeNode && identity.Parent.Equals(application.Org.ID)
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdx.On("AddNode", ctx, "ns1", "node2", application.Node.Profile).Return(nil)
+	dh.mdi.On("InsertEvent", ctx, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeIdentityConfirmed
+	})).Return(nil).Twice()
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return updateValue(info, 0) == "approved" &&
+			updateValue(info, 1) == "welcome"
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"did:firefly:org/org2", "did:firefly:node/node2"}, bs.ConfirmedDIDClaims)
+
+	err = bs.RunPreFinalize(ctx)
+	assert.NoError(t, err)
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+}
+
+func TestHandleOrgApplicationReviewApproveConflict(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeOrg, "ns1", "org2").Return(testOrgIdentity(t, "org2"), nil)
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return updateValue(info, 0) == "rejected" &&
+			updateValue(info, 1) != ""
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleOrgApplicationReviewApproveNodeConflict(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	// The org is valid, but the node name is taken - so nothing is stored for the org either
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeOrg, "ns1", "org2").Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", application.Org.ID).Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeNode, "ns1", "node2").Return(&core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeNode, Name: "node2"},
+	}, nil)
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return updateValue(info, 0) == "rejected" &&
+			updateValue(info, 1) != ""
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	assert.Empty(t, bs.ConfirmedDIDClaims)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleOrgApplicationReviewApproveBadNode(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	dh.multiparty = true
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	application.Node.Name = "bad name"
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, false, nil)
+	dh.mdi.On("GetIdentityByName", ctx, core.IdentityTypeOrg, "ns1", "org2").Return(nil, nil)
+	dh.mdi.On("GetIdentityByID", ctx, "ns1", application.Org.ID).Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x23456").Return(nil, nil)
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return updateValue(info, 0) == "rejected" &&
+			updateValue(info, 1) != ""
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleOrgApplicationReviewApproveWait(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	// The review is parked rather than recorded as a rejection with no reason
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionWait}, action)
+	assert.NoError(t, err)
+	bs.assertNoFinalizers()
+}
+
+func TestHandleOrgApplicationReviewApproveRetry(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: true}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, mock.Anything).Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationReviewReject(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: false, Reason: "unknown"}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return updateValue(info, 0) == "rejected" &&
+			updateValue(info, 1) == "unknown"
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+}
+
+func TestHandleOrgApplicationReviewUpdateFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	review := &core.OrgApplicationReview{Application: application.ID, Approve: false}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+	dh.mdi.On("UpdateOrgApplication", ctx, "ns1", application.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationReviewBadPayload(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	msg, data := testOrgApplicationReviewMsg(t, &core.OrgApplicationReview{})
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+}
+
+func TestHandleOrgApplicationReviewLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	review := &core.OrgApplicationReview{Application: fftypes.NewUUID()}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", review.Application).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestHandleOrgApplicationReviewNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	review := &core.OrgApplicationReview{Application: fftypes.NewUUID()}
+	msg, data := testOrgApplicationReviewMsg(t, review)
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", review.Application).Return(nil, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10568", err)
+}
+
+func TestHandleOrgApplicationReviewClosed(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	application.State = core.OrgApplicationStateApproved
+	msg, data := testOrgApplicationReviewMsg(t, &core.OrgApplicationReview{Application: application.ID})
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10569", err)
+}
+
+func TestHandleOrgApplicationReviewWrongAuthor(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+	ctx := context.Background()
+
+	appMsg, _ := testOrgApplicationMsg(t, testOrgApplication())
+	application := testPendingOrgApplication(appMsg)
+	msg, data := testOrgApplicationReviewMsg(t, &core.OrgApplicationReview{Application: application.ID})
+	msg.Header.Author = "did:firefly:org/org3"
+	dh.mdi.On("GetOrgApplicationByID", ctx, "ns1", application.ID).Return(application, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, msg, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10409", err)
+}
@@ -49,6 +49,7 @@ func (dh *definitionHandler) handleTokenPoolBroadcast(ctx context.Context, state
 	isAuthor := org.DID == msg.Header.Author
 
 	pool.Message = msg.Header.ID
+	pool.Group = msg.Header.Group
 	pool.Name = pool.NetworkName
 	pool.Published = true
 	return dh.handleTokenPoolDefinition(ctx, state, pool, isAuthor)
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -40,13 +41,19 @@ type Sender interface {
 
 	ClaimIdentity(ctx context.Context, def *core.IdentityClaim, signingIdentity *core.SignerRef, parentSigner *core.SignerRef) error
 	UpdateIdentity(ctx context.Context, identity *core.Identity, def *core.IdentityUpdate, signingIdentity *core.SignerRef, waitConfirm bool) error
-	DefineDatatype(ctx context.Context, datatype *core.Datatype, waitConfirm bool) error
+	RevokeIdentity(ctx context.Context, def *core.IdentityRevocation, signingIdentity *core.SignerRef, waitConfirm bool) error
+	RotateIdentityKey(ctx context.Context, def *core.IdentityKeyRotation, signingIdentity *core.SignerRef, waitConfirm bool) error
+	DefineDatatype(ctx context.Context, datatype *core.Datatype, group *fftypes.Bytes32, waitConfirm bool) error
 	DefineTokenPool(ctx context.Context, pool *core.TokenPool, waitConfirm bool) error
-	PublishTokenPool(ctx context.Context, poolNameOrID, networkName string, waitConfirm bool) (*core.TokenPool, error)
+	PublishTokenPool(ctx context.Context, poolNameOrID, networkName string, group *fftypes.Bytes32, waitConfirm bool) (*core.TokenPool, error)
 	DefineFFI(ctx context.Context, ffi *fftypes.FFI, waitConfirm bool) error
-	PublishFFI(ctx context.Context, name, version, networkName string, waitConfirm bool) (*fftypes.FFI, error)
+	PublishFFI(ctx context.Context, name, version, networkName string, group *fftypes.Bytes32, waitConfirm bool) (*fftypes.FFI, error)
 	DefineContractAPI(ctx context.Context, httpServerURL string, api *core.ContractAPI, waitConfirm bool) error
-	PublishContractAPI(ctx context.Context, httpServerURL, name, networkName string, waitConfirm bool) (api *core.ContractAPI, err error)
+	PublishContractAPI(ctx context.Context, httpServerURL, name, networkName string, group *fftypes.Bytes32, waitConfirm bool) (api *core.ContractAPI, err error)
+	VoteDefinitionProposal(ctx context.Context, id *fftypes.UUID, vote *core.DefinitionVote, waitConfirm bool) (*core.DefinitionProposal, error)
+	SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) error
+	ReviewOrgApplication(ctx context.Context, review *core.OrgApplicationReview, waitConfirm bool) error
+	DefineNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance, waitConfirm bool) error
 }
 
 type definitionSender struct {
@@ -54,7 +61,8 @@ type definitionSender struct {
 	namespace           string
 	multiparty          bool
 	database            database.Plugin
-	broadcast           broadcast.Manager // optional
+	broadcast           broadcast.Manager        // optional
+	messaging           privatemessaging.Manager // optional
 	identity            identity.Manager
 	data                data.Manager
 	contracts           contracts.Manager // optional
@@ -66,6 +74,7 @@ type definitionSender struct {
 // Definitions that get processed immediately will create a temporary batch state and then finalize it inline
 func fakeBatch(ctx context.Context, handler func(context.Context, *core.BatchState) (HandlerResult, error)) (err error) {
 	var state core.BatchState
+	defer state.RunCleanup(ctx)
 	_, err = handler(ctx, &state)
 	if err == nil {
 		err = state.RunPreFinalize(ctx)
@@ -76,7 +85,7 @@ func fakeBatch(ctx context.Context, handler func(context.Context, *core.BatchSta
 	return err
 }
 
-func NewDefinitionSender(ctx context.Context, ns *core.Namespace, multiparty bool, di database.Plugin, bi blockchain.Plugin, dx dataexchange.Plugin, bm broadcast.Manager, im identity.Manager, dm data.Manager, am assets.Manager, cm contracts.Manager, tokenBroadcastNames map[string]string) (Sender, Handler, error) {
+func NewDefinitionSender(ctx context.Context, ns *core.Namespace, multiparty bool, di database.Plugin, bi blockchain.Plugin, dx dataexchange.Plugin, bm broadcast.Manager, pm privatemessaging.Manager, im identity.Manager, dm data.Manager, am assets.Manager, cm contracts.Manager, tokenBroadcastNames map[string]string) (Sender, Handler, error) {
 	if di == nil || im == nil || dm == nil {
 		return nil, nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "DefinitionSender")
 	}
@@ -86,6 +95,7 @@ func NewDefinitionSender(ctx context.Context, ns *core.Namespace, multiparty boo
 		multiparty:          multiparty,
 		database:            di,
 		broadcast:           bm,
+		messaging:           pm,
 		identity:            im,
 		data:                dm,
 		contracts:           cm,
@@ -141,6 +151,41 @@ func (ds *definitionSender) getSenderDefault(ctx context.Context, def core.Defin
 	}, tag)
 }
 
+// getSenderForGroup sends the definition privately to the members of an existing group when one is
+// specified, and otherwise broadcasts it to the whole network
+func (ds *definitionSender) getSenderForGroup(ctx context.Context, def core.Definition, tag string, group *fftypes.Bytes32) *sendWrapper {
+	if group == nil {
+		return ds.getSenderDefault(ctx, def, tag)
+	}
+	// Every member would reject a private definition that the network governance requires approval for
+	if ds.multiparty {
+		governance, err := ds.database.GetNetworkGovernance(ctx, ds.namespace)
+		if err != nil {
+			return wrapSendError(err)
+		}
+		if governance != nil && governance.DefinitionQuorum > 0 {
+			return wrapSendError(i18n.NewError(ctx, coremsgs.MsgPrivateDefinitionGoverned, ds.namespace))
+		}
+	}
+	org, err := ds.identity.GetMultipartyRootOrg(ctx)
+	if err != nil {
+		return wrapSendError(err)
+	}
+	message, err := ds.newDefinitionMessage(ctx, def, &core.SignerRef{ /* resolve to node default */
+		Author: org.DID,
+	}, tag)
+	if err != nil {
+		return wrapSendError(err)
+	}
+	// The private messaging manager resolves the signing identity and the group when the message is sent
+	message.Header.Type = core.MessageTypeDefinitionPrivate
+	message.Header.Group = group
+	return &sendWrapper{
+		message: &message.Message,
+		sender:  ds.messaging.NewMessage(message),
+	}
+}
+
 func (ds *definitionSender) getSender(ctx context.Context, def core.Definition, signingIdentity *core.SignerRef, tag string) *sendWrapper {
 	err := ds.identity.ResolveInputSigningIdentity(ctx, signingIdentity)
 	if err != nil {
@@ -150,9 +195,20 @@ func (ds *definitionSender) getSender(ctx context.Context, def core.Definition,
 }
 
 func (ds *definitionSender) getSenderResolved(ctx context.Context, def core.Definition, signingIdentity *core.SignerRef, tag string) *sendWrapper {
+	message, err := ds.newDefinitionMessage(ctx, def, signingIdentity, tag)
+	if err != nil {
+		return wrapSendError(err)
+	}
+	return &sendWrapper{
+		message: &message.Message,
+		sender:  ds.broadcast.NewBroadcast(message),
+	}
+}
+
+func (ds *definitionSender) newDefinitionMessage(ctx context.Context, def core.Definition, signingIdentity *core.SignerRef, tag string) (*core.MessageInOut, error) {
 	b, err := json.Marshal(&def)
 	if err != nil {
-		return wrapSendError(i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed))
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
 	}
 	dataValue := fftypes.JSONAnyPtrBytes(b)
 	message := &core.MessageInOut{
@@ -169,8 +225,5 @@ func (ds *definitionSender) getSenderResolved(ctx context.Context, def core.Defi
 			&core.DataRefOrValue{Value: dataValue},
 		},
 	}
-	return &sendWrapper{
-		message: &message.Message,
-		sender:  ds.broadcast.NewBroadcast(message),
-	}
+	return message, nil
 }
@@ -43,14 +43,14 @@ func (ds *definitionSender) DefineFFI(ctx context.Context, ffi *fftypes.FFI, wai
 		if !ds.multiparty {
 			return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 		}
-		_, err := ds.getFFISender(ctx, ffi).send(ctx, waitConfirm)
+		_, err := ds.getFFISender(ctx, ffi, nil).send(ctx, waitConfirm)
 		return err
 	}
 
 	ffi.NetworkName = ""
 
 	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
-		hr, err := ds.handler.handleFFIDefinition(ctx, state, ffi, nil, true)
+		hr, err := ds.handler.handleFFIDefinition(ctx, state, ffi, nil, nil, true)
 		if err != nil {
 			if innerErr := errors.Unwrap(err); innerErr != nil {
 				return hr, innerErr
@@ -60,7 +60,7 @@ func (ds *definitionSender) DefineFFI(ctx context.Context, ffi *fftypes.FFI, wai
 	})
 }
 
-func (ds *definitionSender) getFFISender(ctx context.Context, ffi *fftypes.FFI) *sendWrapper {
+func (ds *definitionSender) getFFISender(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32) *sendWrapper {
 	if err := ds.contracts.ResolveFFI(ctx, ffi); err != nil {
 		return wrapSendError(err)
 	}
@@ -69,7 +69,7 @@ func (ds *definitionSender) getFFISender(ctx context.Context, ffi *fftypes.FFI)
 		ffi.NetworkName = ffi.Name
 	}
 
-	existing, err := ds.database.GetFFIByNetworkName(ctx, ds.namespace, ffi.NetworkName, ffi.Version)
+	existing, err := ds.database.GetFFIByNetworkName(ctx, ds.namespace, ffi.NetworkName, ffi.Version, group)
 	if err != nil {
 		return wrapSendError(err)
 	} else if existing != nil {
@@ -82,7 +82,7 @@ func (ds *definitionSender) getFFISender(ctx context.Context, ffi *fftypes.FFI)
 	ffi.Namespace = ""
 	ffi.Published = true
 
-	sender := ds.getSenderDefault(ctx, ffi, core.SystemTagDefineFFI)
+	sender := ds.getSenderForGroup(ctx, ffi, core.SystemTagDefineFFI, group)
 	if sender.message != nil {
 		ffi.Message = sender.message.Header.ID
 	}
@@ -92,7 +92,7 @@ func (ds *definitionSender) getFFISender(ctx context.Context, ffi *fftypes.FFI)
 	return sender
 }
 
-func (ds *definitionSender) PublishFFI(ctx context.Context, name, version, networkName string, waitConfirm bool) (ffi *fftypes.FFI, err error) {
+func (ds *definitionSender) PublishFFI(ctx context.Context, name, version, networkName string, group *fftypes.Bytes32, waitConfirm bool) (ffi *fftypes.FFI, err error) {
 	if !ds.multiparty {
 		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
@@ -106,7 +106,7 @@ func (ds *definitionSender) PublishFFI(ctx context.Context, name, version, netwo
 			return i18n.NewError(ctx, coremsgs.MsgAlreadyPublished)
 		}
 		ffi.NetworkName = networkName
-		sender = ds.getFFISender(ctx, ffi)
+		sender = ds.getFFISender(ctx, ffi, group)
 		if sender.err != nil {
 			return sender.err
 		}
@@ -130,18 +130,19 @@ func (ds *definitionSender) DefineContractAPI(ctx context.Context, httpServerURL
 		if !ds.multiparty {
 			return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 		}
-		_, err := ds.getContractAPISender(ctx, httpServerURL, api).send(ctx, waitConfirm)
+		_, err := ds.getContractAPISender(ctx, httpServerURL, api, nil).send(ctx, waitConfirm)
 		return err
 	}
 
 	api.NetworkName = ""
+	api.Group = nil
 
 	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
 		return ds.handler.handleContractAPIDefinition(ctx, state, httpServerURL, api, nil, true)
 	})
 }
 
-func (ds *definitionSender) getContractAPISender(ctx context.Context, httpServerURL string, api *core.ContractAPI) *sendWrapper {
+func (ds *definitionSender) getContractAPISender(ctx context.Context, httpServerURL string, api *core.ContractAPI, group *fftypes.Bytes32) *sendWrapper {
 	if err := ds.contracts.ResolveContractAPI(ctx, httpServerURL, api); err != nil {
 		return wrapSendError(err)
 	}
@@ -150,7 +151,7 @@ func (ds *definitionSender) getContractAPISender(ctx context.Context, httpServer
 		api.NetworkName = api.Name
 	}
 
-	existing, err := ds.database.GetContractAPIByNetworkName(ctx, ds.namespace, api.NetworkName)
+	existing, err := ds.database.GetContractAPIByNetworkName(ctx, ds.namespace, api.NetworkName, group)
 	if err != nil {
 		return wrapSendError(err)
 	} else if existing != nil {
@@ -174,7 +175,7 @@ func (ds *definitionSender) getContractAPISender(ctx context.Context, httpServer
 	api.Namespace = ""
 	api.Published = true
 
-	sender := ds.getSenderDefault(ctx, api, core.SystemTagDefineContractAPI)
+	sender := ds.getSenderForGroup(ctx, api, core.SystemTagDefineContractAPI, group)
 	if sender.message != nil {
 		api.Message = sender.message.Header.ID
 	}
@@ -184,7 +185,7 @@ func (ds *definitionSender) getContractAPISender(ctx context.Context, httpServer
 	return sender
 }
 
-func (ds *definitionSender) PublishContractAPI(ctx context.Context, httpServerURL, name, networkName string, waitConfirm bool) (api *core.ContractAPI, err error) {
+func (ds *definitionSender) PublishContractAPI(ctx context.Context, httpServerURL, name, networkName string, group *fftypes.Bytes32, waitConfirm bool) (api *core.ContractAPI, err error) {
 	if !ds.multiparty {
 		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
@@ -198,7 +199,7 @@ func (ds *definitionSender) PublishContractAPI(ctx context.Context, httpServerUR
 			return i18n.NewError(ctx, coremsgs.MsgAlreadyPublished)
 		}
 		api.NetworkName = networkName
-		sender = ds.getContractAPISender(ctx, httpServerURL, api)
+		sender = ds.getContractAPISender(ctx, httpServerURL, api, group)
 		if sender.err != nil {
 			return sender.err
 		}
@@ -59,7 +59,7 @@ func TestDefineFFIFail(t *testing.T) {
 		Published: true,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0").Return(nil, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
 
@@ -78,7 +78,7 @@ func TestDefineFFIExists(t *testing.T) {
 		Published: true,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0").Return(&fftypes.FFI{}, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0", (*fftypes.Bytes32)(nil)).Return(&fftypes.FFI{}, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 
 	err := ds.DefineFFI(context.Background(), ffi, false)
@@ -96,7 +96,7 @@ func TestDefineFFIQueryFail(t *testing.T) {
 		Published: true,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0").Return(nil, fmt.Errorf("pop"))
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, fmt.Errorf("pop"))
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 
 	err := ds.DefineFFI(context.Background(), ffi, false)
@@ -114,7 +114,7 @@ func TestDefineFFIOk(t *testing.T) {
 		Published: true,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0").Return(nil, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
 		IdentityBase: core.IdentityBase{
@@ -144,7 +144,7 @@ func TestDefineFFIConfirm(t *testing.T) {
 		Published: true,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0").Return(nil, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
 		IdentityBase: core.IdentityBase{
@@ -188,7 +188,7 @@ func TestDefineFFINonMultiparty(t *testing.T) {
 	}
 
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
-	ds.mdi.On("InsertOrGetFFI", context.Background(), ffi).Return(nil, nil)
+	ds.mdi.On("InsertOrGetFFI", context.Background(), ffi, (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mdi.On("InsertEvent", context.Background(), mock.Anything).Return(nil)
 
 	err := ds.DefineFFI(context.Background(), ffi, false)
@@ -240,7 +240,7 @@ func TestDefineContractAPIFail(t *testing.T) {
 
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "banana").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "banana", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.EqualError(t, err, "pop")
@@ -264,7 +264,7 @@ func TestDefineContractAPIOk(t *testing.T) {
 		},
 	}, nil)
 	ds.mim.On("ResolveInputSigningIdentity", context.Background(), mock.Anything).Return(nil)
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "banana").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "banana", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 
 	mms := &syncasyncmocks.Sender{}
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
@@ -320,7 +320,7 @@ func TestPublishFFI(t *testing.T) {
 		Published: false,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1-shared", "1.0").Return(nil, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1-shared", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("GetFFI", context.Background(), "ffi1", "1.0").Return(ffi, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
@@ -334,7 +334,7 @@ func TestPublishFFI(t *testing.T) {
 	mms.On("Send", context.Background()).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	result, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	result, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.NoError(t, err)
 	assert.Equal(t, ffi, result)
 	assert.True(t, ffi.Published)
@@ -357,7 +357,7 @@ func TestPublishFFIAlreadyPublished(t *testing.T) {
 	ds.mcm.On("GetFFI", context.Background(), "ffi1", "1.0").Return(ffi, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.Regexp(t, "FF10450", err)
 }
 
@@ -369,7 +369,7 @@ func TestPublishFFIQueryFail(t *testing.T) {
 	ds.mcm.On("GetFFI", context.Background(), "ffi1", "1.0").Return(nil, fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -389,7 +389,7 @@ func TestPublishFFIResolveFail(t *testing.T) {
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -407,7 +407,7 @@ func TestPublishFFIPrepareFail(t *testing.T) {
 		Published: false,
 	}
 
-	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1-shared", "1.0").Return(nil, nil)
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1-shared", "1.0", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("GetFFI", context.Background(), "ffi1", "1.0").Return(ffi, nil)
 	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
@@ -420,7 +420,7 @@ func TestPublishFFIPrepareFail(t *testing.T) {
 	mms.On("Prepare", context.Background()).Return(fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 
 	mms.AssertExpectations(t)
@@ -431,7 +431,7 @@ func TestPublishFFINonMultiparty(t *testing.T) {
 	defer ds.cleanup(t)
 	ds.multiparty = false
 
-	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", false)
+	_, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", nil, false)
 	assert.Regexp(t, "FF10414", err)
 }
 
@@ -449,7 +449,7 @@ func TestPublishContractAPI(t *testing.T) {
 		Published: false,
 	}
 
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
@@ -463,7 +463,7 @@ func TestPublishContractAPI(t *testing.T) {
 	mms.On("Send", context.Background()).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	result, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	result, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.NoError(t, err)
 	assert.Equal(t, api, result)
 	assert.True(t, api.Published)
@@ -486,7 +486,7 @@ func TestPublishContractAPIAlreadyPublished(t *testing.T) {
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.Regexp(t, "FF10450", err)
 }
 
@@ -500,7 +500,7 @@ func TestPublishContractAPIQueryFail(t *testing.T) {
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(nil, fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -520,7 +520,7 @@ func TestPublishContractAPIResolveFail(t *testing.T) {
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -531,7 +531,7 @@ func TestPublishContractAPINonMultiparty(t *testing.T) {
 
 	url := "http://firefly"
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.Regexp(t, "FF10414", err)
 }
 
@@ -547,12 +547,12 @@ func TestPublishContractAPINetworkNameFail(t *testing.T) {
 		Published: false,
 	}
 
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(nil, fmt.Errorf("pop"))
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(nil, fmt.Errorf("pop"))
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -568,12 +568,12 @@ func TestPublishContractAPINetworkNameConflict(t *testing.T) {
 		Published: false,
 	}
 
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(&core.ContractAPI{}, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(&core.ContractAPI{}, nil)
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.Regexp(t, "FF10448", err)
 }
 
@@ -593,12 +593,12 @@ func TestPublishContractAPIInterfaceFail(t *testing.T) {
 	}
 
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", api.Interface.ID).Return(nil, fmt.Errorf("pop"))
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -618,12 +618,12 @@ func TestPublishContractAPIInterfaceNotFound(t *testing.T) {
 	}
 
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", api.Interface.ID).Return(nil, nil)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.Regexp(t, "FF10303", err)
 }
 
@@ -643,13 +643,54 @@ func TestPublishContractAPIInterfaceNotPublished(t *testing.T) {
 	}
 
 	ds.mcm.On("GetContractAPI", context.Background(), url, "api").Return(api, nil)
-	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared").Return(nil, nil)
+	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "api-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", api.Interface.ID).Return(&fftypes.FFI{
 		Published: false,
 	}, nil)
 
-	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
+	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", nil, false)
 	assert.Regexp(t, "FF10451", err)
 }
+
+func TestPublishFFIToGroup(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	mms := &syncasyncmocks.Sender{}
+	group := fftypes.NewRandB32()
+
+	ffi := &fftypes.FFI{
+		Name:      "ffi1",
+		Version:   "1.0",
+		Namespace: "ns1",
+		Published: false,
+	}
+
+	ds.mdi.On("GetFFIByNetworkName", context.Background(), "ns1", "ffi1-shared", "1.0", group).Return(nil, nil)
+	ds.mcm.On("GetFFI", context.Background(), "ffi1", "1.0").Return(ffi, nil)
+	ds.mcm.On("ResolveFFI", context.Background(), ffi).Return(nil)
+	ds.mdi.On("GetNetworkGovernance", context.Background(), "ns1").Return(nil, nil)
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mpm.On("NewMessage", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Type == core.MessageTypeDefinitionPrivate &&
+			msg.Header.Group.Equals(group) &&
+			msg.Header.Tag == core.SystemTagDefineFFI
+	})).Return(mms)
+	mms.On("Prepare", context.Background()).Return(nil)
+	mms.On("Send", context.Background()).Return(nil)
+	mockRunAsGroupPassthrough(ds.mdi)
+
+	result, err := ds.PublishFFI(context.Background(), "ffi1", "1.0", "ffi1-shared", group, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ffi, result)
+	assert.True(t, ffi.Published)
+
+	mms.AssertExpectations(t)
+}
@@ -25,7 +25,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
-func (ds *definitionSender) DefineDatatype(ctx context.Context, datatype *core.Datatype, waitConfirm bool) error {
+func (ds *definitionSender) DefineDatatype(ctx context.Context, datatype *core.Datatype, group *fftypes.Bytes32, waitConfirm bool) error {
 	// Validate the input data definition data
 	datatype.ID = fftypes.NewUUID()
 	datatype.Created = fftypes.Now()
@@ -44,7 +44,7 @@ func (ds *definitionSender) DefineDatatype(ctx context.Context, datatype *core.D
 		}
 
 		datatype.Namespace = ""
-		msg, err := ds.getSenderDefault(ctx, datatype, core.SystemTagDefineDatatype).send(ctx, waitConfirm)
+		msg, err := ds.getSenderForGroup(ctx, datatype, core.SystemTagDefineDatatype, group).send(ctx, waitConfirm)
 		if msg != nil {
 			datatype.Message = msg.Header.ID
 		}
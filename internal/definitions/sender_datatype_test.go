@@ -34,7 +34,7 @@ func TestDefineDatatypeBadType(t *testing.T) {
 	ds.multiparty = true
 	err := ds.DefineDatatype(context.Background(), &core.Datatype{
 		Validator: core.ValidatorType("wrong"),
-	}, false)
+	}, nil, false)
 	assert.Regexp(t, "FF00111.*validator", err)
 }
 
@@ -56,7 +56,7 @@ func TestBroadcastDatatypeBadValue(t *testing.T) {
 		Name:      "ent1",
 		Version:   "0.0.1",
 		Value:     fftypes.JSONAnyPtr(`!unparsable`),
-	}, false)
+	}, nil, false)
 	assert.Regexp(t, "FF10137.*value", err)
 }
 
@@ -72,7 +72,7 @@ func TestDefineDatatypeInvalid(t *testing.T) {
 		Name:      "ent1",
 		Version:   "0.0.1",
 		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
-	}, false)
+	}, nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -97,7 +97,7 @@ func TestBroadcastOk(t *testing.T) {
 		Name:      "ent1",
 		Version:   "0.0.1",
 		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
-	}, false)
+	}, nil, false)
 	assert.NoError(t, err)
 
 	mms.AssertExpectations(t)
@@ -113,6 +113,41 @@ func TestDefineDatatypeNonMultiparty(t *testing.T) {
 		Name:      "ent1",
 		Version:   "0.0.1",
 		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
-	}, false)
+	}, nil, false)
 	assert.Regexp(t, "FF10414", err)
 }
+
+func TestDefineDatatypeToGroup(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	mms := &syncasyncmocks.Sender{}
+	group := fftypes.NewRandB32()
+
+	ds.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	ds.mdi.On("GetNetworkGovernance", context.Background(), "ns1").Return(nil, nil)
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mpm.On("NewMessage", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Type == core.MessageTypeDefinitionPrivate &&
+			msg.Header.Group.Equals(group) &&
+			msg.Header.Tag == core.SystemTagDefineDatatype
+	})).Return(mms)
+	mms.On("Send", context.Background()).Return(nil)
+
+	datatype := &core.Datatype{
+		Namespace: "ns1",
+		Name:      "ent1",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
+	}
+	err := ds.DefineDatatype(context.Background(), datatype, group, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", datatype.Namespace)
+
+	mms.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+func (ds *definitionSender) VoteDefinitionProposal(ctx context.Context, id *fftypes.UUID, vote *core.DefinitionVote, waitConfirm bool) (*core.DefinitionProposal, error) {
+	// Voting remains open on proposals raised under an earlier quorum, so only multiparty mode is required
+	if !ds.multiparty {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDefinitionGovernanceDisabled, ds.namespace)
+	}
+
+	proposal, err := ds.database.GetDefinitionProposalByID(ctx, ds.namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	if proposal.State != core.DefinitionProposalStateProposed {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDefinitionProposalClosed, proposal.ID, proposal.State)
+	}
+
+	// The vote is sent on the topic of the proposal, so it is sequenced after the proposal on every member
+	vote.Proposal = proposal.ID
+	vote.ProposalTopic = proposal.Topic
+	if _, err := ds.getSenderDefault(ctx, vote, core.SystemTagDefinitionVote).send(ctx, waitConfirm); err != nil {
+		return nil, err
+	}
+	if waitConfirm {
+		return ds.database.GetDefinitionProposalByID(ctx, ds.namespace, id)
+	}
+	return proposal, nil
+}
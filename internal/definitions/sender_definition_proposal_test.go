@@ -0,0 +1,158 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestProposal() *core.DefinitionProposal {
+	return &core.DefinitionProposal{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Tag:       core.SystemTagDefineDatatype,
+		Topic:     "topic1",
+		Author:    "did:firefly:org/org1",
+		TX:        fftypes.NewUUID(),
+		State:     core.DefinitionProposalStateProposed,
+		Quorum:    2,
+		Votes:     core.DefinitionProposalVotes{},
+	}
+}
+
+func TestVoteDefinitionProposalOk(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	proposal := newTestProposal()
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", proposal.ID).Return(proposal, nil)
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	ds.mbm.On("NewBroadcast", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Tag == core.SystemTagDefinitionVote && msg.Header.Topics[0] == "topic1"
+	})).Return(mms)
+	mms.On("Send", context.Background()).Return(nil)
+
+	vote := &core.DefinitionVote{Approve: true}
+	result, err := ds.VoteDefinitionProposal(context.Background(), proposal.ID, vote, false)
+	assert.NoError(t, err)
+	assert.Equal(t, proposal, result)
+	assert.Equal(t, proposal.ID, vote.Proposal)
+	assert.Equal(t, "topic1", vote.ProposalTopic)
+
+	mms.AssertExpectations(t)
+}
+
+func TestVoteDefinitionProposalConfirm(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	proposal := newTestProposal()
+	confirmed := newTestProposal()
+	confirmed.State = core.DefinitionProposalStateConfirmed
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", proposal.ID).Return(proposal, nil).Once()
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", proposal.ID).Return(confirmed, nil).Once()
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("SendAndWait", context.Background()).Return(nil)
+
+	result, err := ds.VoteDefinitionProposal(context.Background(), proposal.ID, &core.DefinitionVote{Approve: true}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, confirmed, result)
+
+	mms.AssertExpectations(t)
+}
+
+func TestVoteDefinitionProposalSendFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	proposal := newTestProposal()
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", proposal.ID).Return(proposal, nil)
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ds.VoteDefinitionProposal(context.Background(), proposal.ID, &core.DefinitionVote{Approve: true}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestVoteDefinitionProposalClosed(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	proposal := newTestProposal()
+	proposal.State = core.DefinitionProposalStateRejected
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", proposal.ID).Return(proposal, nil)
+
+	_, err := ds.VoteDefinitionProposal(context.Background(), proposal.ID, &core.DefinitionVote{Approve: true}, false)
+	assert.Regexp(t, "FF10561", err)
+}
+
+func TestVoteDefinitionProposalNotFound(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	id := fftypes.NewUUID()
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", id).Return(nil, nil)
+
+	_, err := ds.VoteDefinitionProposal(context.Background(), id, &core.DefinitionVote{Approve: true}, false)
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestVoteDefinitionProposalLookupFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	id := fftypes.NewUUID()
+	ds.mdi.On("GetDefinitionProposalByID", context.Background(), "ns1", id).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ds.VoteDefinitionProposal(context.Background(), id, &core.DefinitionVote{Approve: true}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestVoteDefinitionProposalGovernanceDisabled(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = false
+
+	_, err := ds.VoteDefinitionProposal(context.Background(), fftypes.NewUUID(), &core.DefinitionVote{Approve: true}, false)
+	assert.Regexp(t, "FF10560", err)
+}
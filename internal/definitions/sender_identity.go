@@ -75,3 +75,25 @@ func (ds *definitionSender) UpdateIdentity(ctx context.Context, identity *core.I
 		return ds.handler.handleIdentityUpdate(ctx, state, &identityUpdateMsgInfo{}, def)
 	})
 }
+
+func (ds *definitionSender) RevokeIdentity(ctx context.Context, def *core.IdentityRevocation, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	if ds.multiparty {
+		_, err := ds.getSender(ctx, def, signingIdentity, core.SystemTagIdentityRevoke).send(ctx, waitConfirm)
+		return err
+	}
+
+	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
+		return ds.handler.handleIdentityRevocation(ctx, state, &identityUpdateMsgInfo{}, def)
+	})
+}
+
+func (ds *definitionSender) RotateIdentityKey(ctx context.Context, def *core.IdentityKeyRotation, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	if ds.multiparty {
+		_, err := ds.getSender(ctx, def, signingIdentity, core.SystemTagIdentityKeyRotation).send(ctx, waitConfirm)
+		return err
+	}
+
+	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
+		return ds.handler.handleIdentityKeyRotation(ctx, state, &identityUpdateMsgInfo{}, def)
+	})
+}
@@ -200,3 +200,79 @@ func TestUpdateIdentityNonMultiparty(t *testing.T) {
 	}, false)
 	assert.Regexp(t, "FF10403", err)
 }
+
+func TestRevokeIdentity(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	mms := &syncasyncmocks.Sender{}
+
+	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("Send", mock.Anything).Return(nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.MatchedBy(func(signer *core.SignerRef) bool {
+		return signer.Key == "0x1234"
+	})).Return(nil)
+
+	ds.multiparty = true
+
+	err := ds.RevokeIdentity(ds.ctx, &core.IdentityRevocation{
+		Identity: core.IdentityBase{},
+	}, &core.SignerRef{
+		Key: "0x1234",
+	}, false)
+	assert.NoError(t, err)
+
+	mms.AssertExpectations(t)
+}
+
+func TestRevokeIdentityNonMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.multiparty = false
+
+	err := ds.RevokeIdentity(ds.ctx, &core.IdentityRevocation{
+		Identity: core.IdentityBase{},
+	}, &core.SignerRef{
+		Key: "0x1234",
+	}, false)
+	assert.Regexp(t, "FF10403", err)
+}
+
+func TestRotateIdentityKey(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	mms := &syncasyncmocks.Sender{}
+
+	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("Send", mock.Anything).Return(nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.MatchedBy(func(signer *core.SignerRef) bool {
+		return signer.Key == "0x1234"
+	})).Return(nil)
+
+	ds.multiparty = true
+
+	err := ds.RotateIdentityKey(ds.ctx, &core.IdentityKeyRotation{
+		Identity: core.IdentityBase{},
+	}, &core.SignerRef{
+		Key: "0x1234",
+	}, false)
+	assert.NoError(t, err)
+
+	mms.AssertExpectations(t)
+}
+
+func TestRotateIdentityKeyNonMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.multiparty = false
+
+	err := ds.RotateIdentityKey(ds.ctx, &core.IdentityKeyRotation{
+		Identity: core.IdentityBase{},
+	}, &core.SignerRef{
+		Key: "0x1234",
+	}, false)
+	assert.Regexp(t, "FF10403", err)
+}
@@ -0,0 +1,37 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+func (ds *definitionSender) DefineNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance, waitConfirm bool) error {
+	if !ds.multiparty {
+		return i18n.NewError(ctx, coremsgs.MsgDefinitionGovernanceDisabled, ds.namespace)
+	}
+	governance.Namespace = ds.namespace
+	if err := governance.Validate(ctx); err != nil {
+		return err
+	}
+	_, err := ds.getSenderDefault(ctx, governance, core.SystemTagDefineNetworkGovernance).send(ctx, waitConfirm)
+	return err
+}
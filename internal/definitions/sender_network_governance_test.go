@@ -0,0 +1,81 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDefineNetworkGovernanceOk(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	governance := &core.NetworkGovernance{DefinitionQuorum: 2}
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	ds.mbm.On("NewBroadcast", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Tag == core.SystemTagDefineNetworkGovernance && msg.Header.Topics[0] == governance.Topic()
+	})).Return(mms)
+	mms.On("SendAndWait", context.Background()).Return(nil)
+
+	err := ds.DefineNetworkGovernance(context.Background(), governance, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", governance.Namespace)
+
+	mms.AssertExpectations(t)
+}
+
+func TestDefineNetworkGovernanceSendFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
+
+	err := ds.DefineNetworkGovernance(context.Background(), &core.NetworkGovernance{DefinitionQuorum: 2}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDefineNetworkGovernanceBadQuorum(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	err := ds.DefineNetworkGovernance(context.Background(), &core.NetworkGovernance{DefinitionQuorum: -1}, false)
+	assert.Regexp(t, "FF10574", err)
+}
+
+func TestDefineNetworkGovernanceNotMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	err := ds.DefineNetworkGovernance(context.Background(), &core.NetworkGovernance{DefinitionQuorum: 2}, false)
+	assert.Regexp(t, "FF10560", err)
+}
@@ -0,0 +1,60 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// SubmitOrgApplication is a special form of definition where the signing identity is not registered yet.
+// The blockchain "key" of the org will be normalized, and the "author" is the DID the org will be registered with.
+func (ds *definitionSender) SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) (err error) {
+	if !ds.multiparty {
+		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	application.Key, err = ds.identity.ResolveInputSigningKey(ctx, application.Key, identity.KeyNormalizationBlockchainPlugin)
+	if err != nil {
+		return err
+	}
+
+	application.Namespace = ""
+	msg, err := ds.getSenderResolved(ctx, application, &core.SignerRef{
+		Author: application.OrgDID(),
+		Key:    application.Key,
+	}, core.SystemTagOrgApplication).send(ctx, waitConfirm)
+	if msg != nil {
+		application.Message = msg.Header.ID
+	}
+	application.Namespace = ds.namespace
+	return err
+}
+
+func (ds *definitionSender) ReviewOrgApplication(ctx context.Context, review *core.OrgApplicationReview, waitConfirm bool) error {
+	if !ds.multiparty {
+		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	// The review is sent on the topic of the application, so it is sequenced after the application on every member
+	_, err := ds.getSenderDefault(ctx, review, core.SystemTagOrgApplicationReview).send(ctx, waitConfirm)
+	return err
+}
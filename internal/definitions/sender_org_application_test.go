@@ -0,0 +1,124 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubmitOrgApplicationOk(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	application := testOrgApplication()
+	msgID := fftypes.NewUUID()
+	ds.mim.On("ResolveInputSigningKey", context.Background(), "0x23456", identity.KeyNormalizationBlockchainPlugin).Return("0x23456", nil)
+	ds.mbm.On("NewBroadcast", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Tag == core.SystemTagOrgApplication &&
+			msg.Header.Author == "did:firefly:org/org2" &&
+			msg.Header.Key == "0x23456" &&
+			msg.Header.Topics[0] == application.Topic()
+	})).Run(func(args mock.Arguments) {
+		args[0].(*core.MessageInOut).Header.ID = msgID
+	}).Return(mms)
+	mms.On("SendAndWait", context.Background()).Return(nil)
+
+	err := ds.SubmitOrgApplication(context.Background(), application, true)
+	assert.NoError(t, err)
+	assert.Equal(t, msgID, application.Message)
+	assert.Equal(t, "ns1", application.Namespace)
+
+	mms.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationSendFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	ds.mim.On("ResolveInputSigningKey", context.Background(), "0x23456", identity.KeyNormalizationBlockchainPlugin).Return("0x23456", nil)
+	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("Send", context.Background()).Return(fmt.Errorf("pop"))
+
+	err := ds.SubmitOrgApplication(context.Background(), testOrgApplication(), false)
+	assert.EqualError(t, err, "pop")
+
+	mms.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationBadKey(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	ds.mim.On("ResolveInputSigningKey", context.Background(), "0x23456", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+
+	err := ds.SubmitOrgApplication(context.Background(), testOrgApplication(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSubmitOrgApplicationNonMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	err := ds.SubmitOrgApplication(context.Background(), testOrgApplication(), false)
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestReviewOrgApplicationOk(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+	mms := &syncasyncmocks.Sender{}
+
+	review := &core.OrgApplicationReview{Application: fftypes.NewUUID(), Approve: true}
+	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "did:firefly:org/org1",
+		},
+	}, nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	ds.mbm.On("NewBroadcast", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Tag == core.SystemTagOrgApplicationReview && msg.Header.Topics[0] == review.Topic()
+	})).Return(mms)
+	mms.On("Send", context.Background()).Return(nil)
+
+	err := ds.ReviewOrgApplication(context.Background(), review, false)
+	assert.NoError(t, err)
+
+	mms.AssertExpectations(t)
+}
+
+func TestReviewOrgApplicationNonMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	err := ds.ReviewOrgApplication(context.Background(), &core.OrgApplicationReview{}, false)
+	assert.Regexp(t, "FF10414", err)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/mocks/assetmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
@@ -29,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +45,7 @@ type testDefinitionSender struct {
 	mbi    *blockchainmocks.Plugin
 	mdx    *dataexchangemocks.Plugin
 	mbm    *broadcastmocks.Manager
+	mpm    *privatemessagingmocks.Manager
 	mim    *identitymanagermocks.Manager
 	mdm    *datamocks.Manager
 	mam    *assetmocks.Manager
@@ -55,6 +58,7 @@ func (tds *testDefinitionSender) cleanup(t *testing.T) {
 	tds.mbi.AssertExpectations(t)
 	tds.mdx.AssertExpectations(t)
 	tds.mbm.AssertExpectations(t)
+	tds.mpm.AssertExpectations(t)
 	tds.mim.AssertExpectations(t)
 	tds.mdm.AssertExpectations(t)
 	tds.mam.AssertExpectations(t)
@@ -66,6 +70,7 @@ func newTestDefinitionSender(t *testing.T) *testDefinitionSender {
 	mbi := &blockchainmocks.Plugin{}
 	mdx := &dataexchangemocks.Plugin{}
 	mbm := &broadcastmocks.Manager{}
+	mpm := &privatemessagingmocks.Manager{}
 	mim := &identitymanagermocks.Manager{}
 	mdm := &datamocks.Manager{}
 	mam := &assetmocks.Manager{}
@@ -75,7 +80,7 @@ func newTestDefinitionSender(t *testing.T) *testDefinitionSender {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	ds, _, err := NewDefinitionSender(ctx, ns, false, mdi, mbi, mdx, mbm, mim, mdm, mam, mcm, tokenBroadcastNames)
+	ds, _, err := NewDefinitionSender(ctx, ns, false, mdi, mbi, mdx, mbm, mpm, mim, mdm, mam, mcm, tokenBroadcastNames)
 	assert.NoError(t, err)
 
 	return &testDefinitionSender{
@@ -85,6 +90,7 @@ func newTestDefinitionSender(t *testing.T) *testDefinitionSender {
 		mbi:              mbi,
 		mdx:              mdx,
 		mbm:              mbm,
+		mpm:              mpm,
 		mim:              mim,
 		mdm:              mdm,
 		mam:              mam,
@@ -101,7 +107,7 @@ func mockRunAsGroupPassthrough(mdi *databasemocks.Plugin) {
 }
 
 func TestInitSenderFail(t *testing.T) {
-	_, _, err := NewDefinitionSender(context.Background(), &core.Namespace{}, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, _, err := NewDefinitionSender(context.Background(), &core.Namespace{}, false, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -178,3 +184,85 @@ func TestCreateDefinitionBadIdentity(t *testing.T) {
 	}, core.SystemTagDefineDatatype).send(ds.ctx, false)
 	assert.Regexp(t, "pop", err)
 }
+
+func TestCreateDefinitionForGroupConfirm(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	mms := &syncasyncmocks.Sender{}
+	group := fftypes.NewRandB32()
+
+	ds.mim.On("GetMultipartyRootOrg", ds.ctx).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mpm.On("NewMessage", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Type == core.MessageTypeDefinitionPrivate &&
+			msg.Header.Group.Equals(group) &&
+			msg.Header.Author == "firefly:org1" &&
+			msg.Header.Tag == core.SystemTagDefineDatatype &&
+			msg.Header.TxType == core.TransactionTypeBatchPin
+	})).Return(mms)
+	mms.On("SendAndWait", mock.Anything).Return(nil)
+	ds.mdi.On("GetNetworkGovernance", ds.ctx, "ns1").Return(nil, nil)
+
+	ds.multiparty = true
+	msg, err := ds.getSenderForGroup(ds.ctx, &core.Datatype{}, core.SystemTagDefineDatatype, group).send(ds.ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, group, msg.Header.Group)
+
+	mms.AssertExpectations(t)
+}
+
+func TestCreateDefinitionForGroupGoverned(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.mdi.On("GetNetworkGovernance", ds.ctx, "ns1").Return(&core.NetworkGovernance{DefinitionQuorum: 1}, nil)
+
+	ds.multiparty = true
+	_, err := ds.getSenderForGroup(ds.ctx, &core.Datatype{}, core.SystemTagDefineDatatype, fftypes.NewRandB32()).send(ds.ctx, false)
+	assert.Regexp(t, "FF10582", err)
+}
+
+func TestCreateDefinitionForGroupGovernanceFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.mdi.On("GetNetworkGovernance", ds.ctx, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	ds.multiparty = true
+	_, err := ds.getSenderForGroup(ds.ctx, &core.Datatype{}, core.SystemTagDefineDatatype, fftypes.NewRandB32()).send(ds.ctx, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateDefinitionForGroupNoOrg(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.mim.On("GetMultipartyRootOrg", ds.ctx).Return(nil, fmt.Errorf("pop"))
+	ds.mdi.On("GetNetworkGovernance", ds.ctx, "ns1").Return(nil, nil)
+
+	ds.multiparty = true
+	_, err := ds.getSenderForGroup(ds.ctx, &core.Datatype{}, core.SystemTagDefineDatatype, fftypes.NewRandB32()).send(ds.ctx, false)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestCreateDefinitionForGroupBadValue(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.mim.On("GetMultipartyRootOrg", ds.ctx).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			DID: "firefly:org1",
+		},
+	}, nil)
+	ds.mdi.On("GetNetworkGovernance", ds.ctx, "ns1").Return(nil, nil)
+
+	ds.multiparty = true
+	_, err := ds.getSenderForGroup(ds.ctx, &core.Datatype{
+		Value: fftypes.JSONAnyPtr(`!unparsable`),
+	}, core.SystemTagDefineDatatype, fftypes.NewRandB32()).send(ds.ctx, false)
+	assert.Regexp(t, "FF10137", err)
+}
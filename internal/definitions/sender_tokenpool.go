@@ -20,13 +20,14 @@ import (
 	"context"
 	"errors"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
-func (ds *definitionSender) PublishTokenPool(ctx context.Context, poolNameOrID, networkName string, waitConfirm bool) (pool *core.TokenPool, err error) {
+func (ds *definitionSender) PublishTokenPool(ctx context.Context, poolNameOrID, networkName string, group *fftypes.Bytes32, waitConfirm bool) (pool *core.TokenPool, err error) {
 	if !ds.multiparty {
 		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
@@ -40,7 +41,7 @@ func (ds *definitionSender) PublishTokenPool(ctx context.Context, poolNameOrID,
 			return i18n.NewError(ctx, coremsgs.MsgAlreadyPublished)
 		}
 		pool.NetworkName = networkName
-		sender = ds.getTokenPoolSender(ctx, pool)
+		sender = ds.getTokenPoolSender(ctx, pool, group)
 		if sender.err != nil {
 			return sender.err
 		}
@@ -54,7 +55,7 @@ func (ds *definitionSender) PublishTokenPool(ctx context.Context, poolNameOrID,
 	return pool, err
 }
 
-func (ds *definitionSender) getTokenPoolSender(ctx context.Context, pool *core.TokenPool) *sendWrapper {
+func (ds *definitionSender) getTokenPoolSender(ctx context.Context, pool *core.TokenPool, group *fftypes.Bytes32) *sendWrapper {
 	// Map token connector name -> broadcast name
 	if broadcastName, exists := ds.tokenBroadcastNames[pool.Connector]; exists {
 		pool.Connector = broadcastName
@@ -71,7 +72,7 @@ func (ds *definitionSender) getTokenPoolSender(ctx context.Context, pool *core.T
 	if err := pool.Validate(ctx); err != nil {
 		return wrapSendError(err)
 	}
-	existing, err := ds.database.GetTokenPoolByNetworkName(ctx, ds.namespace, pool.NetworkName)
+	existing, err := ds.database.GetTokenPoolByNetworkName(ctx, ds.namespace, pool.NetworkName, group)
 	if err != nil {
 		return wrapSendError(err)
 	} else if existing != nil {
@@ -97,7 +98,7 @@ func (ds *definitionSender) getTokenPoolSender(ctx context.Context, pool *core.T
 	pool.State = core.TokenPoolStatePending
 	definition := &core.TokenPoolDefinition{Pool: pool}
 
-	sender := ds.getSenderDefault(ctx, definition, core.SystemTagDefinePool)
+	sender := ds.getSenderForGroup(ctx, definition, core.SystemTagDefinePool, group)
 	if sender.message != nil {
 		pool.Message = sender.message.Header.ID
 	}
@@ -113,11 +114,12 @@ func (ds *definitionSender) DefineTokenPool(ctx context.Context, pool *core.Toke
 		if !ds.multiparty {
 			return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 		}
-		_, err := ds.getTokenPoolSender(ctx, pool).send(ctx, waitConfirm)
+		_, err := ds.getTokenPoolSender(ctx, pool, nil).send(ctx, waitConfirm)
 		return err
 	}
 
 	pool.NetworkName = ""
+	pool.Group = nil
 
 	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
 		hr, err := ds.handler.handleTokenPoolDefinition(ctx, state, pool, true)
@@ -131,7 +131,7 @@ func TestDefineTokenPoolOk(t *testing.T) {
 	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Send", ds.ctx).Return(nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", ds.ctx, "ns1", "mypool").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", ds.ctx, "ns1", "mypool", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 
 	err := ds.DefineTokenPool(ds.ctx, pool, false)
 	assert.NoError(t, err)
@@ -232,10 +232,10 @@ func TestPublishTokenPool(t *testing.T) {
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Prepare", context.Background()).Return(nil)
 	mms.On("Send", context.Background()).Return(nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	result, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	result, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.NoError(t, err)
 	assert.Equal(t, pool, result)
 	assert.True(t, pool.Published)
@@ -248,7 +248,7 @@ func TestPublishTokenPoolNonMultiparty(t *testing.T) {
 	defer ds.cleanup(t)
 	ds.multiparty = false
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.Regexp(t, "FF10414", err)
 }
 
@@ -271,7 +271,7 @@ func TestPublishTokenPoolAlreadyPublished(t *testing.T) {
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.Regexp(t, "FF10450", err)
 }
 
@@ -283,7 +283,7 @@ func TestPublishTokenPoolQueryFail(t *testing.T) {
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(nil, fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -304,10 +304,10 @@ func TestPublishTokenPoolNetworkNameError(t *testing.T) {
 	}
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, fmt.Errorf("pop"))
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, fmt.Errorf("pop"))
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -328,10 +328,10 @@ func TestPublishTokenPoolNetworkNameConflict(t *testing.T) {
 	}
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(&core.TokenPool{}, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(&core.TokenPool{}, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.Regexp(t, "FF10448", err)
 }
 
@@ -353,10 +353,10 @@ func TestPublishTokenPoolResolveFail(t *testing.T) {
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
 	ds.mim.On("GetMultipartyRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -387,10 +387,10 @@ func TestPublishTokenPoolPrepareFail(t *testing.T) {
 	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Prepare", context.Background()).Return(fmt.Errorf("pop"))
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 
 	mms.AssertExpectations(t)
@@ -424,10 +424,10 @@ func TestPublishTokenPoolSendFail(t *testing.T) {
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Prepare", context.Background()).Return(nil)
 	mms.On("Send", context.Background()).Return(fmt.Errorf("pop"))
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 
 	mms.AssertExpectations(t)
@@ -461,10 +461,10 @@ func TestPublishTokenPoolConfirm(t *testing.T) {
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Prepare", context.Background()).Return(nil)
 	mms.On("SendAndWait", context.Background()).Return(nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", true)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, true)
 	assert.NoError(t, err)
 	assert.True(t, pool.Published)
 
@@ -491,11 +491,11 @@ func TestPublishTokenPoolInterfaceFail(t *testing.T) {
 	}
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", pool.Interface.ID).Return(nil, fmt.Errorf("pop"))
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -519,11 +519,11 @@ func TestPublishTokenPoolInterfaceNotFound(t *testing.T) {
 	}
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", pool.Interface.ID).Return(nil, nil)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.Regexp(t, "FF10303", err)
 }
 
@@ -547,12 +547,12 @@ func TestPublishTokenPoolInterfaceNotPublished(t *testing.T) {
 	}
 
 	ds.mam.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(pool, nil)
-	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared").Return(nil, nil)
+	ds.mdi.On("GetTokenPoolByNetworkName", mock.Anything, "ns1", "pool-shared", (*fftypes.Bytes32)(nil)).Return(nil, nil)
 	mockRunAsGroupPassthrough(ds.mdi)
 	ds.mdi.On("GetFFIByID", context.Background(), "ns1", pool.Interface.ID).Return(&fftypes.FFI{
 		Published: false,
 	}, nil)
 
-	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", false)
+	_, err := ds.PublishTokenPool(context.Background(), "pool1", "pool-shared", nil, false)
 	assert.Regexp(t, "FF10451", err)
 }
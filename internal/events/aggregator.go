@@ -215,6 +215,7 @@ func (ag *aggregator) rewindOffchainBatches() (bool, int64) {
 
 func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, state *batchState) error) error {
 	state := newBatchState(ag)
+	defer state.RunCleanup(ag.ctx)
 
 	err := ag.database.RunAsGroup(ag.ctx, func(ctx context.Context) (err error) {
 		if err := callback(ctx, state); err != nil {
@@ -432,9 +433,10 @@ func (ag *aggregator) checkOnchainConsistency(ctx context.Context, msg *core.Mes
 		switch {
 		case msg.Header.Type == core.MessageTypeDefinition &&
 			(msg.Header.Tag == core.SystemTagIdentityClaim ||
+				msg.Header.Tag == core.SystemTagOrgApplication ||
 				msg.Header.Tag == core.DeprecatedSystemTagDefineNode ||
 				msg.Header.Tag == core.DeprecatedSystemTagDefineOrganization):
-			// Identity claims can have an unregistered identity at this point
+			// Identity claims and org applications can have an unregistered identity at this point
 			// We defer detailed checking of the identity to the system handler
 			return core.ActionConfirm, nil
 
@@ -453,6 +455,16 @@ func (ag *aggregator) checkOnchainConsistency(ctx context.Context, msg *core.Mes
 		return core.ActionReject, nil // This is not retryable. Reject this message
 
 	}
+
+	// Messages sequenced after the signing key was revoked are no longer trusted
+	verifier, err := ag.identity.CachedVerifierLookup(ctx, verifierRef)
+	if err != nil {
+		return core.ActionRetry, err
+	}
+	if verifier != nil && verifier.Revoked != nil && pin.Sequence > verifier.RevokedSequence {
+		l.Errorf("Invalid message '%s'. Key '%s' of author '%s' was revoked at sequence %d", msg.Header.ID, verifierRef.Value, msg.Header.Author, verifier.RevokedSequence)
+		return core.ActionReject, nil // This is not retryable. Reject this message
+	}
 	return core.ActionConfirm, nil
 }
 
@@ -610,10 +622,11 @@ func (ag *aggregator) readyForDispatch(ctx context.Context, msg *core.Message, d
 
 	// Validate the message data
 	switch {
-	case msg.Header.Type == core.MessageTypeDefinition:
+	case msg.Header.Type == core.MessageTypeDefinition || msg.Header.Type == core.MessageTypeDefinitionPrivate:
 		// We handle definition events in-line on the aggregator, as it would be confusing for apps to be
 		// dispatched subsequent events before we have processed the definition events they depend on.
 		var handlerResult definitions.HandlerResult
+		state.PinSequence = pin.Sequence
 		handlerResult, err = ag.definitions.HandleDefinitionBroadcast(ctx, &state.BatchState, msg, data, tx)
 		log.L(ctx).Infof("Result of definition broadcast '%s' [%s]: %s", msg.Header.Tag, msg.Header.ID, handlerResult.Action)
 		if handlerResult.Action == core.ActionReject {
@@ -629,7 +642,7 @@ func (ag *aggregator) readyForDispatch(ctx context.Context, msg *core.Message, d
 
 	case len(msg.Data) > 0:
 		var valid bool
-		valid, err = ag.data.ValidateAll(ctx, data)
+		valid, err = ag.data.ValidateAll(ctx, data, msg.Header.Group)
 		action = core.ActionReject
 		if err != nil {
 			action = core.ActionRetry
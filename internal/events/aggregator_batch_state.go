@@ -80,7 +80,7 @@ type dispatchedMessage struct {
 
 // batchState is the object that tracks the in-memory state that builds up while processing a batch of pins,
 // that needs to be reconciled at the point the batch closes.
-// There are three phases:
+// There are four phases:
 //  1. Dispatch: Determines if messages are blocked, or can be dispatched. Calls the appropriate dispatch
 //     actions for that message type. Reads initial `pin` state for contexts from the DB, and then
 //     updates this in-memory throughout the batch, ready for flushing in the Finalize phase.
@@ -92,6 +92,8 @@ type dispatchedMessage struct {
 //     during phase (1) or (2).
 //     Runs in a database operation group/tranaction, which will be the same as phase (1) if there
 //     are no pre-finalize handlers registered.
+//  4. Cleanup: Runs any Cleanup callbacks registered by the handlers, such as to drop cache entries for
+//     state updated in the batch. Runs after the above, whether the batch was committed or rolled back.
 type batchState struct {
 	core.BatchState
 
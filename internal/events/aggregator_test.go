@@ -241,6 +241,7 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 		Type:  core.VerifierTypeEthAddress,
 		Value: member2key,
 	}).Return(member2org, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	batch := &core.Batch{
 		BatchHeader: core.BatchHeader{
@@ -304,7 +305,7 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 	})).Return(nil).Once()
 	// Validate the message is ok
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, batch.Payload.Messages[0].Header.ID, data.CRORequirePins).Return(batch.Payload.Messages[0], core.DataArray{}, true, nil)
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(true, nil)
 	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, mock.Anything, core.MessageStateConfirmed, mock.Anything).Return()
 	// Insert the confirmed event
 	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
@@ -379,6 +380,7 @@ func TestAggregationMaskedNextSequenceMatch(t *testing.T) {
 		Type:  core.VerifierTypeEthAddress,
 		Value: member2key,
 	}).Return(member2org, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	rag := ag.mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
@@ -422,7 +424,7 @@ func TestAggregationMaskedNextSequenceMatch(t *testing.T) {
 	}, nil).Once()
 	// Validate the message is ok
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, batch.Payload.Messages[0].Header.ID, data.CRORequirePins).Return(batch.Payload.Messages[0], core.DataArray{}, true, nil)
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(true, nil)
 	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, mock.Anything, core.MessageStateConfirmed, mock.Anything).Return()
 	// Insert the confirmed event
 	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
@@ -482,6 +484,7 @@ func TestAggregationBroadcast(t *testing.T) {
 		Type:  core.VerifierTypeEthAddress,
 		Value: member1key,
 	}).Return(member1org, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	batch := &core.Batch{
 		BatchHeader: core.BatchHeader{
@@ -514,7 +517,7 @@ func TestAggregationBroadcast(t *testing.T) {
 	ag.mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
 	// Validate the message is ok
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, batch.Payload.Messages[0].Header.ID, data.CRORequirePublicBlobRefs).Return(batch.Payload.Messages[0], core.DataArray{}, true, nil)
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(true, nil)
 	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, mock.Anything, core.MessageStateConfirmed, mock.Anything).Return()
 	// Insert the confirmed event
 	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
@@ -563,6 +566,7 @@ func TestAggregationMigratedBroadcast(t *testing.T) {
 		Type:  core.VerifierTypeEthAddress,
 		Value: member1key,
 	}).Return(member1org, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	batch := &core.Batch{
 		BatchHeader: core.BatchHeader{
@@ -601,7 +605,7 @@ func TestAggregationMigratedBroadcast(t *testing.T) {
 	ag.mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
 	// Validate the message is ok
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, batch.Payload.Messages[0].Header.ID, data.CRORequirePublicBlobRefs).Return(batch.Payload.Messages[0], core.DataArray{}, true, nil)
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(true, nil)
 	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, mock.Anything, core.MessageStateConfirmed, mock.Anything).Return()
 	// Insert the confirmed event
 	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
@@ -902,6 +906,7 @@ func TestProcessSkipDupMsg(t *testing.T) {
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything, data.CRORequirePublicBlobRefs).Return(batch.Payload.Messages[0], nil, true, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	err := ag.processPins(ag.ctx, []*core.Pin{
 		{Sequence: 12345, Batch: batchID, Index: 0, Hash: fftypes.NewRandB32(), Signer: "key1"},
@@ -946,6 +951,7 @@ func TestProcessMsgFailGetPins(t *testing.T) {
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything, data.CRORequirePublicBlobRefs).Return(batch.Payload.Messages[0], nil, true, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	err := ag.processPins(ag.ctx, []*core.Pin{
 		{Sequence: 12345, Batch: batchID, Index: 0, Hash: fftypes.NewRandB32(), Signer: "key1"},
@@ -1009,6 +1015,7 @@ func TestProcessMsgFailMissingGroup(t *testing.T) {
 	}, nil, true, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	err := ag.processMessage(ag.ctx, &core.BatchManifest{},
 		&core.Pin{Masked: true, Sequence: 12345, Signer: "key1"},
@@ -1090,6 +1097,7 @@ func TestProcessMsgFailBadPin(t *testing.T) {
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything, data.CRORequirePins).Return(msg, nil, true, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	err := ag.processMessage(ag.ctx, &core.BatchManifest{},
 		&core.Pin{Masked: true, Sequence: 12345, Signer: "key1"},
@@ -1128,6 +1136,7 @@ func TestProcessMsgFailGetNextPins(t *testing.T) {
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything, data.CRORequirePins).Return(msg, nil, true, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	err := ag.processMessage(ag.ctx, &core.BatchManifest{},
 		&core.Pin{Masked: true, Sequence: 12345, Signer: "key1"},
@@ -1169,6 +1178,7 @@ func TestProcessMsgFailPinUpdate(t *testing.T) {
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0x12345",
 	}).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 	ag.mdi.On("GetNextPinsForContext", ag.ctx, "ns1", mock.Anything).Return([]*core.NextPin{
 		{Context: fftypes.NewRandB32(), Hash: pin, Identity: org1.DID},
 	}, nil)
@@ -1507,7 +1517,7 @@ func TestReadyForDispatchFailValidateData(t *testing.T) {
 	defer ag.cleanup(t)
 
 	org1 := newTestOrg("org1")
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(false, fmt.Errorf("pop"))
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
 
 	_, _, err := ag.readyForDispatch(ag.ctx, &core.Message{
 		Header: core.MessageHeader{ID: fftypes.NewUUID(), SignerRef: core.SignerRef{Key: "0x12345", Author: org1.DID}},
@@ -1749,6 +1759,7 @@ func TestDispatchBroadcastQueuesLaterDispatch(t *testing.T) {
 	msg1, msg2, org1, manifest := newTestManifest(core.MessageTypeDefinition, nil)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	data1 := core.DataArray{}
 	data2 := core.DataArray{}
@@ -1789,11 +1800,12 @@ func TestDispatchPrivateQueuesLaterDispatch(t *testing.T) {
 	msg1, msg2, org1, manifest := newTestManifest(core.MessageTypePrivate, groupID)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	data1 := core.DataArray{}
 	data2 := core.DataArray{{Namespace: "ns1", Blob: &core.BlobRef{Hash: fftypes.NewRandB32()}}}
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg1.Header.ID, data.CRORequirePins).Return(msg1, data1, true, nil).Once()
-	ag.mdm.On("ValidateAll", ag.ctx, data1).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, data1, groupID).Return(true, nil)
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg2.Header.ID, data.CRORequirePins).Return(msg2, data2, true, nil).Once()
 
 	initNPG := &nextPinGroupState{topic: "topic1", groupID: groupID}
@@ -1833,10 +1845,11 @@ func TestDispatchPrivateNextPinIncremented(t *testing.T) {
 	msg1, msg2, org1, manifest := newTestManifest(core.MessageTypePrivate, groupID)
 
 	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(org1, nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, nil)
 
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg1.Header.ID, data.CRORequirePins).Return(msg1, core.DataArray{}, true, nil).Once()
 	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg2.Header.ID, data.CRORequirePins).Return(msg2, core.DataArray{}, true, nil).Once()
-	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything, mock.Anything).Return(true, nil)
 
 	initNPG := &nextPinGroupState{topic: "topic1", groupID: groupID}
 	member1NonceOne := initNPG.calcPinHash(org1.DID, 1)
@@ -1874,6 +1887,20 @@ func TestDefinitionBroadcastActionRetry(t *testing.T) {
 
 }
 
+func TestPrivateDefinitionActionRetry(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg1, _, _, _ := newTestManifest(core.MessageTypeDefinitionPrivate, nil)
+
+	ag.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, msg1, mock.Anything, mock.Anything).
+		Return(definitions.HandlerResult{Action: core.ActionRetry}, fmt.Errorf("pop"))
+
+	_, _, err := ag.readyForDispatch(ag.ctx, msg1, nil, nil, &batchState{}, &core.Pin{Signer: "0x12345"})
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestDefinitionBroadcastActionReject(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -1902,6 +1929,43 @@ func TestDefinitionBroadcastRejectSignerLookupWrongOrg(t *testing.T) {
 
 }
 
+func TestCheckOnchainConsistencyRevokedKey(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg1, _, _, _ := newTestManifest(core.MessageTypeBroadcast, nil)
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(newTestOrg("org1"), nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"}).Return(&core.Verifier{
+		Revoked:         fftypes.Now(),
+		RevokedSequence: 100,
+	}, nil)
+
+	action, err := ag.checkOnchainConsistency(ag.ctx, msg1, &core.Pin{Signer: "0x12345", Sequence: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+
+	action, err = ag.checkOnchainConsistency(ag.ctx, msg1, &core.Pin{Signer: "0x12345", Sequence: 101})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+
+}
+
+func TestCheckOnchainConsistencyVerifierLookupFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg1, _, _, _ := newTestManifest(core.MessageTypeBroadcast, nil)
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(newTestOrg("org1"), nil)
+	ag.mim.On("CachedVerifierLookup", ag.ctx, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	action, err := ag.checkOnchainConsistency(ag.ctx, msg1, &core.Pin{Signer: "0x12345"})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, core.ActionRetry, action)
+
+}
+
 func TestDefinitionBroadcastParkUnregisteredSignerIdentity(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -1931,6 +1995,21 @@ func TestDefinitionBroadcastRootUnregistered(t *testing.T) {
 
 }
 
+func TestOrgApplicationUnregisteredSigner(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg1, _, _, _ := newTestManifest(core.MessageTypeDefinition, nil)
+	msg1.Header.Tag = core.SystemTagOrgApplication
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(nil, nil)
+
+	action, err := ag.checkOnchainConsistency(ag.ctx, msg1, &core.Pin{Signer: "0x12345"})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+
+}
+
 func TestDefinitionBroadcastActionWait(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -1939,8 +2018,10 @@ func TestDefinitionBroadcastActionWait(t *testing.T) {
 
 	ag.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(definitions.HandlerResult{Action: core.ActionWait}, nil)
 
-	_, _, err := ag.readyForDispatch(ag.ctx, msg1, nil, nil, &batchState{}, &core.Pin{Signer: "0x12345"})
+	bs := &batchState{}
+	_, _, err := ag.readyForDispatch(ag.ctx, msg1, nil, nil, bs, &core.Pin{Signer: "0x12345", Sequence: 12345})
 	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), bs.PinSequence)
 
 }
 
@@ -2229,6 +2310,24 @@ func TestProcessWithBatchActionsFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestProcessWithBatchCleanupAfterRollback(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	rag := ag.mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+
+	cleanedUp := false
+	err := ag.processWithBatchState(func(ctx context.Context, actions *batchState) error {
+		actions.AddCleanup(func(ctx context.Context) { cleanedUp = true })
+		return fmt.Errorf("pop")
+	})
+	assert.EqualError(t, err, "pop")
+	assert.True(t, cleanedUp)
+}
+
 func TestExtractManifestFail(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
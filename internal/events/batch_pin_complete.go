@@ -18,11 +18,13 @@ package events
 
 import (
 	"context"
+	"database/sql/driver"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 // BatchPinComplete is called in-line with a particular ledger's stream of events, so while we
@@ -44,11 +46,16 @@ func (em *eventManager) BatchPinComplete(namespace string, batchPin *blockchain.
 		log.L(em.ctx).Debugf("Ignoring batch pin from different namespace '%s'", namespace)
 		return nil // move on
 	}
-
 	if batchPin.TransactionType == "" {
 		batchPin.TransactionType = core.TransactionTypeBatchPin
 	}
 
+	if em.multiparty.IsPausedAt(batchPin.Event.ProtocolID) {
+		// The pause is recorded against its position in the ledger, so every member rejects the same pins
+		log.L(em.ctx).Warnf("Rejecting batch pin for batch %s at '%s' while namespace '%s' is paused", batchPin.BatchID, batchPin.Event.ProtocolID, namespace)
+		return em.rejectBatchPin(batchPin)
+	}
+
 	log.L(em.ctx).Infof("-> BatchPinComplete batch=%s txn=%s signingIdentity=%s", batchPin.BatchID, batchPin.Event.ProtocolID, signingKey.Value)
 	defer func() {
 		log.L(em.ctx).Infof("<- BatchPinComplete batch=%s txn=%s signingIdentity=%s", batchPin.BatchID, batchPin.Event.ProtocolID, signingKey.Value)
@@ -97,6 +104,76 @@ func (em *eventManager) BatchPinComplete(namespace string, batchPin *blockchain.
 	})
 }
 
+// rejectBatchPin records the transaction and blockchain event of a batch pin that was sequenced while the namespace
+// was paused, without recording its pins. Any messages of the batch held locally (such as those sent by this node)
+// are rejected, as they will never be confirmed.
+func (em *eventManager) rejectBatchPin(batchPin *blockchain.BatchPin) error {
+	return em.retry.Do(em.ctx, "reject batch pin", func(attempt int) (bool, error) {
+		err := em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			if err := em.persistBatchTransaction(ctx, batchPin); err != nil {
+				return err
+			}
+			chainEvent := buildBlockchainEvent(em.namespace.Name, nil, &batchPin.Event, &core.BlockchainTransactionRef{
+				Type:         batchPin.TransactionType,
+				ID:           batchPin.TransactionID,
+				BlockchainID: batchPin.Event.BlockchainTXID,
+			})
+			if err := em.maybePersistBlockchainEvent(ctx, chainEvent, nil); err != nil {
+				return err
+			}
+			return em.rejectBatchMessages(ctx, batchPin)
+		})
+		return err != nil, err // retry indefinitely (until context closes)
+	})
+}
+
+func (em *eventManager) rejectBatchMessages(ctx context.Context, batchPin *blockchain.BatchPin) error {
+	batch, err := em.database.GetBatchByID(ctx, em.namespace.Name, batchPin.BatchID)
+	if err != nil {
+		return err
+	}
+	if batch == nil || !batch.Hash.Equals(batchPin.BatchHash) {
+		return nil
+	}
+	var manifest core.BatchManifest
+	if err := batch.Manifest.Unmarshal(ctx, &manifest); err != nil {
+		log.L(ctx).Errorf("Unable to reject messages of batch %s with invalid manifest: %s", batch.ID, err)
+		return nil
+	}
+	msgIDs := make([]driver.Value, len(manifest.Messages))
+	for i, mm := range manifest.Messages {
+		msgIDs[i] = mm.ID
+	}
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	msgs, _, err := em.database.GetMessages(ctx, em.namespace.Name, fb.And(
+		fb.In("id", msgIDs),
+		fb.In("state", []driver.Value{core.MessageStateSent, core.MessageStatePending}),
+	))
+	if err != nil {
+		return err
+	}
+
+	rejectTime := fftypes.Now()
+	for _, msg := range msgs {
+		log.L(ctx).Infof("Rejecting message %s of batch %s pinned while paused", msg.Header.ID, batch.ID)
+		update := database.MessageQueryFactory.NewUpdate(ctx).
+			Set("confirmed", rejectTime).
+			Set("state", core.MessageStateRejected)
+		if err := em.database.UpdateMessage(ctx, em.namespace.Name, msg.Header.ID, update); err != nil {
+			return err
+		}
+		em.data.UpdateMessageStateIfCached(ctx, msg.Header.ID, core.MessageStateRejected, rejectTime)
+		for _, topic := range msg.Header.Topics {
+			event := core.NewEvent(core.EventTypeMessageRejected, em.namespace.Name, msg.Header.ID, batchPin.TransactionID, topic)
+			event.Correlator = msg.Header.CID
+			if err := em.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (em *eventManager) persistBatchTransaction(ctx context.Context, batchPin *blockchain.BatchPin) error {
 	_, err := em.txHelper.PersistTransaction(ctx, batchPin.TransactionID, batchPin.TransactionType, batchPin.Event.BlockchainTXID)
 	return err
@@ -131,6 +131,8 @@ func TestBatchPinCompleteOkBroadcast(t *testing.T) {
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
 	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef).Return(nil)
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0x12345",
@@ -194,6 +196,8 @@ func TestBatchPinCompleteOkBroadcastExistingBatch(t *testing.T) {
 	em.mdi.On("InsertPins", mock.Anything, mock.Anything).Return(nil).Once()
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(batchPersisted, nil)
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0x12345",
@@ -225,6 +229,8 @@ func TestBatchPinCompleteOkPrivate(t *testing.T) {
 	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0xffffeeee",
@@ -262,6 +268,8 @@ func TestBatchPinCompleteInsertPinsFail(t *testing.T) {
 	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, mock.Anything).Return(nil, nil)
 	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0xffffeeee",
@@ -293,6 +301,8 @@ func TestBatchPinCompleteGetBatchByIDFails(t *testing.T) {
 	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("batch lookup failed"))
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0xffffeeee",
@@ -326,6 +336,8 @@ func TestSequencedBroadcastInitiateDownloadFail(t *testing.T) {
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
 	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef).Return(fmt.Errorf("pop"))
 
+	em.mmp.On("IsPausedAt", mock.Anything).Return(false)
+
 	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
 		Type:  core.VerifierTypeEthAddress,
 		Value: "0xffffeeee",
@@ -364,6 +376,175 @@ func TestBatchPinCompleteWrongNamespace(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func newTestPausedBatch(t *testing.T) (*core.BatchPersisted, *blockchain.BatchPin, *core.Message) {
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			CID:    fftypes.NewUUID(),
+			Topics: fftypes.FFStringArray{"topic1", "topic2"},
+		},
+		State: core.MessageStateSent,
+	}
+	batch := &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Hash: fftypes.NewRandB32(),
+	}
+	batch.Manifest = fftypes.JSONAnyPtr(batch.GenManifest([]*core.Message{msg}, core.DataArray{}).String())
+	batchPin := &blockchain.BatchPin{
+		TransactionID: fftypes.NewUUID(),
+		BatchID:       batch.ID,
+		BatchHash:     batch.Hash,
+		Event: blockchain.Event{
+			BlockchainTXID: "0x12345",
+			ProtocolID:     "000000000010/000000/000000",
+		},
+	}
+	return batch, batchPin, msg
+}
+
+func TestBatchPinCompletePaused(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, msg := newTestPausedBatch(t)
+
+	em.mmp.On("IsPausedAt", "000000000010/000000/000000").Return(true)
+	em.mth.On("PersistTransaction", mock.Anything, batchPin.TransactionID, core.TransactionTypeBatchPin, "0x12345").Return(true, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlockchainEventReceived
+	})).Return(nil).Once()
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batch.ID).Return(batch, nil)
+	em.mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	em.mdi.On("UpdateMessage", mock.Anything, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	em.mdm.On("UpdateMessageStateIfCached", mock.Anything, msg.Header.ID, core.MessageStateRejected, mock.Anything).Return()
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeMessageRejected && e.Reference.Equals(msg.Header.ID) &&
+			e.Correlator.Equals(msg.Header.CID) && e.Transaction.Equals(batchPin.TransactionID)
+	})).Return(nil).Twice()
+
+	err := em.BatchPinComplete("ns1", batchPin, &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	})
+	assert.NoError(t, err)
+}
+
+func TestRejectBatchMessagesNotLocal(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, batchPin, _ := newTestPausedBatch(t)
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(nil, nil)
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.NoError(t, err)
+}
+
+func TestRejectBatchMessagesHashMismatch(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, _ := newTestPausedBatch(t)
+	batchPin.BatchHash = fftypes.NewRandB32()
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(batch, nil)
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.NoError(t, err)
+}
+
+func TestRejectBatchMessagesBadManifest(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, _ := newTestPausedBatch(t)
+	batch.Manifest = fftypes.JSONAnyPtr("!json")
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(batch, nil)
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.NoError(t, err)
+}
+
+func TestRejectBatchMessagesGetBatchFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, batchPin, _ := newTestPausedBatch(t)
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(nil, fmt.Errorf("pop"))
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRejectBatchMessagesGetMessagesFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, _ := newTestPausedBatch(t)
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(batch, nil)
+	em.mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRejectBatchMessagesUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, msg := newTestPausedBatch(t)
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(batch, nil)
+	em.mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	em.mdi.On("UpdateMessage", mock.Anything, "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRejectBatchMessagesInsertEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, batchPin, msg := newTestPausedBatch(t)
+	em.mdi.On("GetBatchByID", mock.Anything, "ns1", batchPin.BatchID).Return(batch, nil)
+	em.mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	em.mdi.On("UpdateMessage", mock.Anything, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	em.mdm.On("UpdateMessageStateIfCached", mock.Anything, msg.Header.ID, core.MessageStateRejected, mock.Anything).Return()
+	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.rejectBatchMessages(em.ctx, batchPin)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRejectBatchPinPersistFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel()
+
+	_, batchPin, _ := newTestPausedBatch(t)
+	batchPin.TransactionType = core.TransactionTypeBatchPin
+	em.mth.On("PersistTransaction", mock.Anything, batchPin.TransactionID, core.TransactionTypeBatchPin, "0x12345").Return(false, fmt.Errorf("pop"))
+
+	err := em.rejectBatchPin(batchPin)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestRejectBatchPinEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel()
+
+	_, batchPin, _ := newTestPausedBatch(t)
+	batchPin.TransactionType = core.TransactionTypeBatchPin
+	em.mth.On("PersistTransaction", mock.Anything, batchPin.TransactionID, core.TransactionTypeBatchPin, "0x12345").Return(true, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := em.rejectBatchPin(batchPin)
+	assert.Regexp(t, "FF00154", err)
+}
+
 func TestBatchPinCompleteNonMultiparty(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -38,6 +38,10 @@ func (bc *boundCallbacks) DeliveryResponse(connID string, inflight *core.EventDe
 	bc.sm.deliveryResponse(bc.ei, connID, inflight)
 }
 
+func (bc *boundCallbacks) DeadLetter(connID string, deadLetter *core.DeadLetter) {
+	bc.sm.deadLetter(bc.ei, connID, deadLetter)
+}
+
 func (bc *boundCallbacks) ConnectionClosed(connID string) {
 	bc.sm.connectionClosed(bc.ei, connID)
 }
@@ -187,7 +187,19 @@ func (em *eventManager) messageReceived(dx dataexchange.Plugin, event dataexchan
 	mr := event.MessageReceived()
 	l.Infof("Private batch received from %s peer '%s'", dx.Name(), mr.PeerID)
 
-	manifestString, err := em.privateBatchReceived(mr.PeerID, mr.Transport.Batch, mr.Transport.Group)
+	transport := mr.Transport
+	if transport.Encrypted != nil {
+		// Decrypt end-to-end encrypted batches before processing, as nothing in the wrapper can be trusted until then
+		decrypted, err := em.messaging.DecryptTransport(em.ctx, transport.Encrypted)
+		if err != nil {
+			l.Errorf("Invalid transmission from peer '%s': %s", mr.PeerID, err)
+			event.AckWithManifest("")
+			return
+		}
+		transport = decrypted
+	}
+
+	manifestString, err := em.privateBatchReceived(mr.PeerID, transport.Batch, transport.Group)
 	if err != nil {
 		l.Warnf("Exited while persisting batch: %s", err)
 		// We do NOT ack here as we broke out of the retry
@@ -217,17 +229,29 @@ func (em *eventManager) privateBlobReceived(dx dataexchange.Plugin, event dataex
 		return
 	}
 
+	blob := &core.Blob{
+		Namespace:  em.namespace.Name,
+		Peer:       br.PeerID,
+		PayloadRef: br.PayloadRef,
+		Hash:       &br.Hash,
+		Size:       br.Size,
+		Created:    fftypes.Now(),
+		DataID:     dataID,
+	}
+
+	// The data exchange stores the blob as it was transferred, so encrypt it at rest if enabled
+	err = em.retry.Do(em.ctx, "encrypt received blob", func(attempt int) (bool, error) {
+		return true, em.data.EncryptReceivedBlob(em.ctx, blob)
+	})
+	if err != nil {
+		log.L(em.ctx).Warnf("Exited while encrypting blob: %s", err)
+		// We do NOT ack here as we broke out of the retry
+		return
+	}
+
 	// Dispatch to the blob receiver for efficient batch DB operations
 	em.blobReceiver.blobReceived(em.ctx, &blobNotification{
-		blob: &core.Blob{
-			Namespace:  em.namespace.Name,
-			Peer:       br.PeerID,
-			PayloadRef: br.PayloadRef,
-			Hash:       &br.Hash,
-			Size:       br.Size,
-			Created:    fftypes.Now(),
-			DataID:     dataID,
-		},
+		blob: blob,
 		onComplete: func() {
 			event.Ack()
 		},
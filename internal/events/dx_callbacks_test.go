@@ -173,6 +173,59 @@ func TestPinnedReceiveOK(t *testing.T) {
 	mdx.AssertExpectations(t)
 }
 
+func TestEncryptedReceiveOK(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	encrypted := &core.EncryptedTransport{Namespace: "ns1", Payload: []byte("sealed")}
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	batch.Node = node1.ID
+
+	mdx := &dataexchangemocks.Plugin{}
+	em.mpm.On("DecryptTransport", em.ctx, encrypted).Return(b, nil)
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: "peer1",
+	}).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, "signingOrg").Return(org1, false, nil)
+	em.mim.On("GetLocalNode", mock.Anything).Return(testNode, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, mock.Anything, mock.Anything).Return(true, nil)
+
+	em.mdi.On("InsertOrGetBatch", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertDataArray", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertMessages", em.ctx, mock.Anything, mock.AnythingOfType("database.PostCompletionHook")).Return(nil, nil).Run(func(args mock.Arguments) {
+		args[2].(database.PostCompletionHook)()
+	})
+	mdx.On("Name").Return("utdx").Maybe()
+	em.mdm.On("UpdateMessageCache", mock.Anything, mock.Anything).Return()
+
+	mde := newMessageReceived("peer1", &core.TransportWrapper{Encrypted: encrypted}, batch.Payload.Manifest(batch.ID).String())
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestEncryptedReceiveDecryptFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	encrypted := &core.EncryptedTransport{Namespace: "ns1", Payload: []byte("sealed")}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx").Maybe()
+	em.mpm.On("DecryptTransport", em.ctx, encrypted).Return(nil, fmt.Errorf("pop"))
+
+	mde := newMessageReceived("peer1", &core.TransportWrapper{Encrypted: encrypted}, "")
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
 func TestMessageReceiveOkBadBatchIgnored(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -415,6 +468,7 @@ func TestPrivateBlobReceivedTriggersRewindOk(t *testing.T) {
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
 
+	em.mdm.On("EncryptReceivedBlob", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
 	em.mdi.On("InsertBlobs", em.ctx, mock.Anything).Return(nil)
 
@@ -432,6 +486,57 @@ func TestPrivateBlobReceivedTriggersRewindOk(t *testing.T) {
 	mde.AssertExpectations(t)
 }
 
+func TestPrivateBlobReceivedEncrypted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	hash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	encryption := &core.BlobEncryption{Algorithm: "AES-256-GCM", KeyID: "kek1"}
+	em.mdm.On("EncryptReceivedBlob", em.ctx, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.PayloadRef == "ns1/peer1/path1" && blob.Hash.Equals(hash) && blob.DataID.Equals(dataID)
+	})).Run(func(args mock.Arguments) {
+		blob := args[1].(*core.Blob)
+		blob.PayloadRef = "ns1/" + dataID.String()
+		blob.Encryption = encryption
+	}).Return(nil)
+	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
+	em.mdi.On("InsertBlobs", em.ctx, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 1 && blobs[0].PayloadRef == "ns1/"+dataID.String() && blobs[0].Encryption == encryption
+	})).Return(nil)
+
+	done := make(chan struct{})
+	mde := newPrivateBlobReceivedNoAck("peer1", hash, 12345, "ns1/peer1/path1", dataID)
+	mde.On("Ack").Run(func(args mock.Arguments) {
+		close(done)
+	})
+	em.DXEvent(mdx, mde)
+	<-done
+
+	mde.AssertExpectations(t)
+}
+
+func TestPrivateBlobReceivedEncryptFails(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // retryable error
+	hash := fftypes.NewRandB32()
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mdm.On("EncryptReceivedBlob", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newPrivateBlobReceivedNoAck("peer1", hash, 12345, "ns1/path1", fftypes.NewUUID())
+	em.privateBlobReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+}
+
 func TestPrivateBlobReceivedBadEvent(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -466,6 +571,7 @@ func TestPrivateBlobReceivedInsertBlobFails(t *testing.T) {
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
 
+	em.mdm.On("EncryptReceivedBlob", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
 	em.mdi.On("InsertBlobs", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
 
@@ -485,6 +591,7 @@ func TestPrivateBlobReceivedGetBlobsFails(t *testing.T) {
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
 
+	em.mdm.On("EncryptReceivedBlob", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
 	// no ack as we are simulating termination mid retry
@@ -17,9 +17,12 @@
 package events
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -48,6 +51,12 @@ type ackNack struct {
 	offset int64
 }
 
+type deadLetterReplay struct {
+	deadLetter *core.DeadLetter
+	event      *core.Event
+	cancelCtx  func()
+}
+
 type eventDispatcher struct {
 	acksNacks     chan ackNack
 	cancelCtx     func()
@@ -63,10 +72,15 @@ type eventDispatcher struct {
 	elected       bool
 	eventPoller   *eventPoller
 	inflight      map[fftypes.UUID]*core.Event
+	replays       map[fftypes.UUID]*deadLetterReplay
+	replayTimeout time.Duration
 	eventDelivery chan *core.EventDelivery
 	mux           sync.Mutex
 	namespace     string
 	readAhead     int
+	batch         bool
+	batchSize     int
+	batchTimeout  time.Duration
 	subscription  *subscription
 	txHelper      txcommon.Helper
 }
@@ -77,6 +91,24 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 	if sub.definition.Options.ReadAhead != nil {
 		readAhead = uint(*sub.definition.Options.ReadAhead)
 	}
+	batch := sub.definition.Options.Batch != nil
+	batchSize := config.GetInt(coreconfig.SubscriptionDefaultsBatchDeliverySize)
+	batchTimeout := config.GetDuration(coreconfig.SubscriptionDefaultsBatchDeliveryTimeout)
+	if batch {
+		if sub.definition.Options.Batch.Size != nil && *sub.definition.Options.Batch.Size > 0 {
+			batchSize = int(*sub.definition.Options.Batch.Size)
+		}
+		if sub.definition.Options.Batch.Timeout != nil {
+			// The timeout is validated when the subscription is parsed
+			if d, err := fftypes.ParseDurationString(*sub.definition.Options.Batch.Timeout, time.Millisecond); err == nil {
+				batchTimeout = time.Duration(d)
+			}
+		}
+		// We need to read ahead enough events to fill a batch
+		if readAhead < uint(batchSize) {
+			readAhead = uint(batchSize)
+		}
+	}
 	if readAhead > maxReadAhead {
 		readAhead = maxReadAhead
 	}
@@ -95,8 +127,13 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		subscription:  sub,
 		namespace:     sub.definition.Namespace,
 		inflight:      make(map[fftypes.UUID]*core.Event),
+		replays:       make(map[fftypes.UUID]*deadLetterReplay),
+		replayTimeout: config.GetDuration(coreconfig.SubscriptionReplayTimeout),
 		eventDelivery: make(chan *core.EventDelivery, readAhead+1),
 		readAhead:     int(readAhead),
+		batch:         batch,
+		batchSize:     batchSize,
+		batchTimeout:  batchTimeout,
 		acksNacks:     make(chan ackNack),
 		closed:        make(chan struct{}),
 		txHelper:      txHelper,
@@ -258,6 +295,36 @@ func (ed *eventDispatcher) filterEvents(candidates []*core.EventDelivery) []*cor
 	return matchingEvents
 }
 
+// filterEventsByExpression evaluates the filter expression of the subscription against the JSON representation
+// of each event. Message data is only loaded if the expression refers to it.
+func (ed *eventDispatcher) filterEventsByExpression(candidates []*core.EventDelivery) ([]*core.EventDelivery, error) {
+	matchingEvents := make([]*core.EventDelivery, 0, len(candidates))
+	for _, event := range candidates {
+		input := toFilterValue(event).(map[string]interface{})
+		if ed.subscription.expression.References("data") && event.Message != nil {
+			data, _, err := ed.data.GetMessageDataCached(ed.ctx, event.Message)
+			if err != nil {
+				return nil, err
+			}
+			input["data"] = toFilterValue(data)
+		}
+		if ed.subscription.expression.Matches(input) {
+			matchingEvents = append(matchingEvents, event)
+		}
+	}
+	return matchingEvents, nil
+}
+
+// toFilterValue converts a value to the generic JSON form that filter expressions are evaluated against,
+// preserving the precision of numbers
+func toFilterValue(v interface{}) (value interface{}) {
+	b, _ := json.Marshal(v)
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	_ = d.Decode(&value)
+	return value
+}
+
 func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (bool, error) {
 	// At this point, the page of messages we've been given are loaded from the DB into memory,
 	// but we can only make them in-flight and push them to the client up to the maximum
@@ -277,6 +344,11 @@ func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (boo
 	}
 
 	matching := ed.filterEvents(candidates)
+	if ed.subscription.expression != nil {
+		if matching, err = ed.filterEventsByExpression(matching); err != nil {
+			return false, err
+		}
+	}
 	matchCount := len(matching)
 	dispatched := 0
 
@@ -365,28 +437,84 @@ func (ed *eventDispatcher) handleAckOffsetUpdate(ack ackNack) {
 }
 
 func (ed *eventDispatcher) deliverEvents() {
-	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	if ed.batch {
+		ed.deliverBatchedEvents()
+		return
+	}
 	for {
 		select {
 		case event, ok := <-ed.eventDelivery:
 			if !ok {
 				return
 			}
-			log.L(ed.ctx).Debugf("Dispatching %s event: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
-			var data []*core.Data
-			var err error
-			if withData && event.Message != nil {
-				data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message)
+			ed.deliverEvent(ed.ctx, event)
+		case <-ed.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverBatchedEvents gathers events into batches, delivering each batch when it is full
+// or when the batch timeout expires after the first event of the batch arrived
+func (ed *eventDispatcher) deliverBatchedEvents() {
+	var batch []*core.EventDelivery
+	var batchTimeout <-chan time.Time
+	for {
+		select {
+		case event, ok := <-ed.eventDelivery:
+			if !ok {
+				return
 			}
-			if err == nil {
-				err = ed.transport.DeliveryRequest(ed.connID, ed.subscription.definition, event, data)
+			if len(batch) == 0 {
+				batchTimeout = time.After(ed.batchTimeout)
 			}
-			if err != nil {
-				ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID, Rejected: true})
+			batch = append(batch, event)
+			if len(batch) < ed.batchSize {
+				continue
 			}
+		case <-batchTimeout:
 		case <-ed.ctx.Done():
 			return
 		}
+		ed.deliverBatch(batch)
+		batch = nil
+		batchTimeout = nil
+	}
+}
+
+func (ed *eventDispatcher) deliverBatch(batch []*core.EventDelivery) {
+	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	log.L(ed.ctx).Debugf("Dispatching batch of %d %s events: %.10d-%.10d", len(batch), ed.transport.Name(), batch[0].Sequence, batch[len(batch)-1].Sequence)
+	events := make([]*core.CombinedEventDataDelivery, len(batch))
+	var err error
+	for i, event := range batch {
+		events[i] = &core.CombinedEventDataDelivery{Event: event}
+		if withData && event.Message != nil && err == nil {
+			events[i].Data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message)
+		}
+	}
+	if err == nil {
+		err = ed.transport.BatchDeliveryRequest(ed.ctx, ed.connID, ed.subscription.definition, events)
+	}
+	if err != nil {
+		// Rejecting the first event rewinds to the start of the batch, so the whole batch is redelivered
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: batch[0].ID, Rejected: true})
+	}
+}
+
+func (ed *eventDispatcher) deliverEvent(ctx context.Context, event *core.EventDelivery) {
+	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	log.L(ed.ctx).Debugf("Dispatching %s event: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
+	var data []*core.Data
+	var err error
+	if withData && event.Message != nil {
+		data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message)
+	}
+	if err == nil {
+		err = ed.transport.DeliveryRequest(ctx, ed.connID, ed.subscription.definition, event, data)
+	}
+	if err != nil {
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID, Rejected: true})
 	}
 }
 
@@ -395,14 +523,23 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 
 	ed.mux.Lock()
 	var an ackNack
+	var replay *deadLetterReplay
 	event, found := ed.inflight[*response.ID]
 	if found {
 		an.id = *response.ID
 		an.offset = event.Sequence
 		an.isNack = response.Rejected
+	} else {
+		replay = ed.takeReplay(*response.ID)
 	}
 	ed.mux.Unlock()
 
+	// Replays of dead letters have no effect on the offset of the subscription
+	if replay != nil {
+		ed.replayResponse(replay, response)
+		return
+	}
+
 	// Do some extra logging and persistent actions now we're out of lock
 	if !found {
 		l.Warnf("Response for event not in flight: %s rejected=%t info='%s' (likely previous reject)", response.ID, response.Rejected, response.Info)
@@ -426,10 +563,147 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 	}
 }
 
+func (ed *eventDispatcher) deadLetter(deadLetter *core.DeadLetter) {
+	l := log.L(ed.ctx)
+
+	ed.mux.Lock()
+	replay := ed.takeReplay(*deadLetter.Event)
+	_, inflight := ed.inflight[*deadLetter.Event]
+	ed.mux.Unlock()
+
+	var err error
+	if replay != nil {
+		// A replay that exhausted its retries again, so we just update the existing dead letter
+		err = ed.updateDeadLetter(replay.deadLetter, deadLetter.Attempts, deadLetter.Error)
+	} else {
+		deadLetter.ID = fftypes.NewUUID()
+		deadLetter.Namespace = ed.namespace
+		deadLetter.Subscription = ed.subscription.definition.SubscriptionRef
+		deadLetter.Created = fftypes.Now()
+		l.Warnf("Dead lettering %s event %s after %d attempts: %s", ed.transport.Name(), deadLetter.Event, deadLetter.Attempts, deadLetter.Error)
+		err = ed.database.InsertDeadLetter(ed.ctx, deadLetter)
+	}
+	if err != nil {
+		l.Errorf("Failed to record dead letter for event %s: %s", deadLetter.Event, err)
+	}
+
+	if inflight {
+		// Once the dead letter is stored, the subscription can move past the event.
+		// If we failed to store it, we reject the event so it is redelivered.
+		ed.deliveryResponse(&core.EventDeliveryResponse{
+			ID:           deadLetter.Event,
+			Rejected:     err != nil,
+			Info:         deadLetter.Error,
+			Subscription: ed.subscription.definition.SubscriptionRef,
+		})
+	}
+}
+
+func (ed *eventDispatcher) updateDeadLetter(deadLetter *core.DeadLetter, attempts int, errMsg string) error {
+	deadLetter.Attempts += attempts
+	deadLetter.Error = errMsg
+	deadLetter.Updated = fftypes.Now()
+	update := database.DeadLetterQueryFactory.NewUpdate(ed.ctx).
+		Set("attempts", deadLetter.Attempts).
+		Set("error", deadLetter.Error).
+		Set("updated", deadLetter.Updated)
+	return ed.database.UpdateDeadLetter(ed.ctx, ed.namespace, deadLetter.ID, update)
+}
+
+func (ed *eventDispatcher) replayDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	event, err := ed.database.GetEventByID(ctx, ed.namespace, deadLetter.Event)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterEventNotFound, deadLetter.Event, deadLetter.ID)
+	}
+	enrichedEvent, err := ed.enricher.enrichEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	// The transport might never respond (for example a websocket client that does not ack),
+	// so the replay is bounded by a timeout that also stops any webhook retries
+	replayCtx, cancelCtx := context.WithTimeout(ed.ctx, ed.replayTimeout)
+	ed.mux.Lock()
+	ed.replays[*event.ID] = &deadLetterReplay{
+		deadLetter: deadLetter,
+		event:      event,
+		cancelCtx:  cancelCtx,
+	}
+	ed.mux.Unlock()
+	go ed.replayTimeoutCheck(replayCtx, *event.ID)
+
+	// Replays are delivered outside of the ordered stream of events, so do not block it
+	log.L(ed.ctx).Infof("Replaying dead letter %s for event %s", deadLetter.ID, event.ID)
+	go ed.deliverEvent(replayCtx, &core.EventDelivery{
+		EnrichedEvent: *enrichedEvent,
+		Subscription:  ed.subscription.definition.SubscriptionRef,
+		Replay:        true,
+	})
+	return nil
+}
+
+// takeReplay removes a replay from the map, stopping its timeout - must be called with the mutex held
+func (ed *eventDispatcher) takeReplay(eventID fftypes.UUID) *deadLetterReplay {
+	replay := ed.replays[eventID]
+	if replay != nil {
+		delete(ed.replays, eventID)
+		replay.cancelCtx()
+	}
+	return replay
+}
+
+func (ed *eventDispatcher) replayTimeoutCheck(replayCtx context.Context, eventID fftypes.UUID) {
+	<-replayCtx.Done()
+
+	ed.mux.Lock()
+	replay := ed.takeReplay(eventID)
+	ed.mux.Unlock()
+
+	// If the transport responded first, or the dispatcher closed, there is nothing to do
+	if replay == nil || ed.ctx.Err() != nil {
+		return
+	}
+	deadLetter := replay.deadLetter
+	errMsg := i18n.NewError(ed.ctx, coremsgs.MsgDeadLetterReplayTimeout, ed.replayTimeout, deadLetter.ID).Error()
+	log.L(ed.ctx).Warnf("Replay of dead letter %s for event %s failed: %s", deadLetter.ID, deadLetter.Event, errMsg)
+	if err := ed.updateDeadLetter(deadLetter, 1, errMsg); err != nil {
+		log.L(ed.ctx).Errorf("Failed to update dead letter %s after replay: %s", deadLetter.ID, err)
+	}
+}
+
+func (ed *eventDispatcher) replayResponse(replay *deadLetterReplay, response *core.EventDeliveryResponse) {
+	l := log.L(ed.ctx)
+	deadLetter := replay.deadLetter
+
+	var err error
+	if response.Rejected {
+		l.Warnf("Replay of dead letter %s for event %s rejected: %s", deadLetter.ID, deadLetter.Event, response.Info)
+		err = ed.updateDeadLetter(deadLetter, 1, response.Info)
+	} else {
+		if response.Reply != nil {
+			ed.sendReply(ed.ctx, replay.event, response.Reply)
+		}
+		l.Infof("Replay of dead letter %s for event %s succeeded", deadLetter.ID, deadLetter.Event)
+		err = ed.database.DeleteDeadLetter(ed.ctx, ed.namespace, deadLetter.ID)
+	}
+	if err != nil {
+		l.Errorf("Failed to update dead letter %s after replay: %s", deadLetter.ID, err)
+	}
+}
+
 func (ed *eventDispatcher) close() {
 	log.L(ed.ctx).Infof("Dispatcher closing for conn=%s subscription=%s", ed.connID, ed.subscription.definition.ID)
 	ed.cancelCtx()
 	<-ed.closed
+	// Any replays still waiting for a response are abandoned, and remain in the dead letter queue
+	ed.mux.Lock()
+	for eventID := range ed.replays {
+		ed.takeReplay(eventID)
+	}
+	ed.mux.Unlock()
 	if ed.elected {
 		close(ed.eventDelivery)
 		ed.elected = false
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/events/eventfilter"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
@@ -177,9 +178,9 @@ func TestEventDispatcherReadAheadOutOfOrderAcks(t *testing.T) {
 	mdm := ed.data.(*datamocks.Manager)
 
 	eventDeliveries := make(chan *core.EventDelivery)
-	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	deliveryRequestMock.RunFn = func(a mock.Arguments) {
-		eventDeliveries <- a.Get(2).(*core.EventDelivery)
+		eventDeliveries <- a.Get(3).(*core.EventDelivery)
 	}
 
 	// Setup the IDs
@@ -272,9 +273,9 @@ func TestEventDispatcherNoReadAheadInOrder(t *testing.T) {
 	mei := ed.transport.(*eventsmocks.Plugin)
 
 	eventDeliveries := make(chan *core.EventDelivery)
-	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	deliveryRequestMock.RunFn = func(a mock.Arguments) {
-		eventDeliveries <- a.Get(2).(*core.EventDelivery)
+		eventDeliveries <- a.Get(3).(*core.EventDelivery)
 	}
 
 	// Setup the IDs
@@ -603,9 +604,9 @@ func TestEnrichTransactionEvents(t *testing.T) {
 	mei := ed.transport.(*eventsmocks.Plugin)
 
 	eventDeliveries := make(chan *core.EventDelivery)
-	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	deliveryRequestMock.RunFn = func(a mock.Arguments) {
-		eventDeliveries <- a.Get(2).(*core.EventDelivery)
+		eventDeliveries <- a.Get(3).(*core.EventDelivery)
 	}
 
 	// Setup the IDs
@@ -692,9 +693,9 @@ func TestEnrichBlockchainEventEvents(t *testing.T) {
 	mei := ed.transport.(*eventsmocks.Plugin)
 
 	eventDeliveries := make(chan *core.EventDelivery)
-	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliveryRequestMock := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	deliveryRequestMock.RunFn = func(a mock.Arguments) {
-		eventDeliveries <- a.Get(2).(*core.EventDelivery)
+		eventDeliveries <- a.Get(3).(*core.EventDelivery)
 	}
 
 	// Setup the IDs
@@ -762,6 +763,81 @@ func TestEnrichBlockchainEventEvents(t *testing.T) {
 	mei.AssertExpectations(t)
 }
 
+func TestFilterEventsByExpression(t *testing.T) {
+	pool1 := fftypes.NewUUID()
+	expr, err := eventfilter.Parse(context.Background(), fmt.Sprintf(`(tokenTransfer.amount > 1000 and tokenTransfer.pool == "%s") or blockchainEvent.output.value >= 10 or data[0].value.size > 5`, pool1))
+	assert.NoError(t, err)
+	sub := &subscription{
+		definition: &core.Subscription{},
+		expression: expr,
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdm := ed.data.(*datamocks.Manager)
+	msgMatch := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	msgNoMatch := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	mdm.On("GetMessageDataCached", mock.Anything, msgMatch).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"size": 10}`)},
+	}, true, nil)
+	mdm.On("GetMessageDataCached", mock.Anything, msgNoMatch).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"size": 1}`)},
+	}, true, nil)
+
+	events := []*core.EventDelivery{
+		{EnrichedEvent: core.EnrichedEvent{
+			Event:         core.Event{ID: fftypes.NewUUID()},
+			TokenTransfer: &core.TokenTransfer{Pool: pool1, Amount: *fftypes.NewFFBigInt(1001)},
+		}},
+		{EnrichedEvent: core.EnrichedEvent{
+			Event:         core.Event{ID: fftypes.NewUUID()},
+			TokenTransfer: &core.TokenTransfer{Pool: fftypes.NewUUID(), Amount: *fftypes.NewFFBigInt(1001)},
+		}},
+		{EnrichedEvent: core.EnrichedEvent{
+			Event:           core.Event{ID: fftypes.NewUUID()},
+			BlockchainEvent: &core.BlockchainEvent{Output: fftypes.JSONObject{"value": "10"}},
+		}},
+		{EnrichedEvent: core.EnrichedEvent{
+			Event:   core.Event{ID: fftypes.NewUUID()},
+			Message: msgMatch,
+		}},
+		{EnrichedEvent: core.EnrichedEvent{
+			Event:   core.Event{ID: fftypes.NewUUID()},
+			Message: msgNoMatch,
+		}},
+	}
+	matched, err := ed.filterEventsByExpression(events)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(matched))
+	assert.Equal(t, events[0].ID, matched[0].ID)
+	assert.Equal(t, events[2].ID, matched[1].ID)
+	assert.Equal(t, events[3].ID, matched[2].ID)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestBufferedDeliveryExpressionDataFail(t *testing.T) {
+	expr, err := eventfilter.Parse(context.Background(), `data[0].value.size > 5`)
+	assert.NoError(t, err)
+	sub := &subscription{
+		definition: &core.Subscription{},
+		expression: expr,
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(msg, nil, true, nil)
+	mdm.On("GetMessageDataCached", mock.Anything, msg).Return(nil, false, fmt.Errorf("pop"))
+
+	repoll, err := ed.bufferedDelivery([]core.LocallySequenced{&core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed}})
+	assert.False(t, repoll)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+}
+
 func TestBufferedDeliveryNoEvents(t *testing.T) {
 
 	sub := &subscription{
@@ -814,7 +890,7 @@ func TestBufferedDeliveryClosedContext(t *testing.T) {
 	mdi := ed.database.(*databasemocks.Plugin)
 	mei := ed.transport.(*eventsmocks.Plugin)
 	mdi.On("GetDataRefs", mock.Anything, mock.Anything).Return(nil, nil, nil)
-	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	repoll, err := ed.bufferedDelivery([]core.LocallySequenced{&core.Event{ID: fftypes.NewUUID()}})
 	assert.False(t, repoll)
@@ -837,7 +913,7 @@ func TestBufferedDeliveryNackRewind(t *testing.T) {
 	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	delivered := make(chan struct{})
-	deliver := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deliver := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	deliver.RunFn = func(a mock.Arguments) {
 		close(delivered)
 	}
@@ -882,7 +958,7 @@ func TestBufferedDeliveryFailNack(t *testing.T) {
 	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 
 	failNacked := make(chan bool)
-	deliver := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	deliver := mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	deliver.RunFn = func(a mock.Arguments) {
 		failNacked <- true
 	}
@@ -1064,3 +1140,540 @@ func TestEventDispatcherWithReply(t *testing.T) {
 	mbm.AssertExpectations(t)
 	mms.AssertExpectations(t)
 }
+
+func newTestDeadLetterDispatcher() (*eventDispatcher, func()) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	ed.acksNacks = make(chan ackNack, 1)
+	return ed, cancel
+}
+
+func TestDeadLetterInflight(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	eventID := fftypes.NewUUID()
+	ed.inflight[*eventID] = &core.Event{ID: eventID, Sequence: 12345}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertDeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.ID != nil &&
+			dl.Namespace == "ns1" &&
+			dl.Subscription.Name == "sub1" &&
+			dl.Event.Equals(eventID) &&
+			dl.Attempts == 5 &&
+			dl.Created != nil
+	})).Return(nil)
+
+	ed.deadLetter(&core.DeadLetter{Event: eventID, Attempts: 5, Error: "pop"})
+
+	an := <-ed.acksNacks
+	assert.False(t, an.isNack)
+	assert.Equal(t, int64(12345), an.offset)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeadLetterInflightInsertFail(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	eventID := fftypes.NewUUID()
+	ed.inflight[*eventID] = &core.Event{ID: eventID, Sequence: 12345}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertDeadLetter", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ed.deadLetter(&core.DeadLetter{Event: eventID, Attempts: 5, Error: "pop"})
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeadLetterNotInflight(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertDeadLetter", mock.Anything, mock.Anything).Return(nil)
+
+	ed.deadLetter(&core.DeadLetter{Event: fftypes.NewUUID(), Attempts: 5, Error: "pop"})
+	assert.Empty(t, ed.acksNacks)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeadLetterReplayExhausted(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	eventID := fftypes.NewUUID()
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: eventID, Attempts: 5}
+	ed.replays[*eventID] = &deadLetterReplay{deadLetter: dl, cancelCtx: func() {}}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("UpdateDeadLetter", mock.Anything, "ns1", dl.ID, mock.Anything).Return(nil)
+
+	ed.deadLetter(&core.DeadLetter{Event: eventID, Attempts: 3, Error: "pop again"})
+	assert.Equal(t, 8, dl.Attempts)
+	assert.Equal(t, "pop again", dl.Error)
+	assert.NotNil(t, dl.Updated)
+	assert.Empty(t, ed.replays)
+	assert.Empty(t, ed.acksNacks)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterSuccess(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	event := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeContractAPIConfirmed, Reference: fftypes.NewUUID()}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID, Attempts: 5}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", event.ID).Return(event, nil)
+	mdi.On("GetContractAPIByID", mock.Anything, "ns1", event.Reference).Return(nil, nil)
+	deleted := make(chan struct{})
+	mdi.On("DeleteDeadLetter", mock.Anything, "ns1", dl.ID).Return(nil).Run(func(args mock.Arguments) {
+		close(deleted)
+	})
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, ed.subscription.definition, mock.MatchedBy(func(ev *core.EventDelivery) bool {
+		return ev.ID.Equals(event.ID) && ev.Replay
+	}), core.DataArray(nil)).Return(nil).Run(func(args mock.Arguments) {
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID})
+	})
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.NoError(t, err)
+
+	<-deleted
+	assert.Empty(t, ed.acksNacks)
+
+	mdi.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterRejected(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	event := &core.Event{ID: fftypes.NewUUID()}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID, Attempts: 5}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", event.ID).Return(event, nil)
+	updated := make(chan struct{})
+	mdi.On("UpdateDeadLetter", mock.Anything, "ns1", dl.ID, mock.Anything).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(updated)
+	})
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, ed.subscription.definition, mock.Anything, core.DataArray(nil)).Return(fmt.Errorf("pop"))
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.NoError(t, err)
+
+	<-updated
+	assert.Equal(t, 6, dl.Attempts)
+
+	mdi.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterWithReply(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	event := &core.Event{ID: fftypes.NewUUID(), Namespace: "ns1"}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID}
+	ed.replays[*event.ID] = &deadLetterReplay{deadLetter: dl, event: event, cancelCtx: func() {}}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("DeleteDeadLetter", mock.Anything, "ns1", dl.ID).Return(nil)
+
+	mms := &syncasyncmocks.Sender{}
+	mbm := ed.broadcast.(*broadcastmocks.Manager)
+	mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("Send", mock.Anything).Return(nil)
+
+	ed.deliveryResponse(&core.EventDeliveryResponse{
+		ID: event.ID,
+		Reply: &core.MessageInOut{
+			Message: core.Message{
+				Header: core.MessageHeader{
+					CID:  fftypes.NewUUID(),
+					Type: core.MessageTypeBroadcast,
+				},
+			},
+		},
+	})
+	assert.Empty(t, ed.replays)
+
+	mdi.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+	mms.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterTimeout(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+	ed.replayTimeout = 1 * time.Millisecond
+
+	event := &core.Event{ID: fftypes.NewUUID()}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID, Attempts: 5}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", event.ID).Return(event, nil)
+	updated := make(chan struct{})
+	mdi.On("UpdateDeadLetter", mock.Anything, "ns1", dl.ID, mock.Anything).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(updated)
+	})
+
+	// The transport never responds
+	delivered := make(chan struct{})
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, ed.subscription.definition, mock.Anything, core.DataArray(nil)).Return(nil).Run(func(args mock.Arguments) {
+		close(delivered)
+	})
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.NoError(t, err)
+
+	<-delivered
+	<-updated
+	assert.Equal(t, 6, dl.Attempts)
+	assert.Regexp(t, "FF10587", dl.Error)
+	ed.mux.Lock()
+	assert.Empty(t, ed.replays)
+	ed.mux.Unlock()
+
+	// A late response is ignored, and the dead letter is kept
+	ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID})
+	assert.Empty(t, ed.acksNacks)
+
+	mdi.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterClose(t *testing.T) {
+	ed, _ := newTestDeadLetterDispatcher()
+	defer coreconfig.Reset()
+
+	event := &core.Event{ID: fftypes.NewUUID()}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", event.ID).Return(event, nil)
+
+	delivered := make(chan context.Context, 1)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, ed.subscription.definition, mock.Anything, core.DataArray(nil)).Return(nil).Run(func(args mock.Arguments) {
+		delivered <- args[0].(context.Context)
+	})
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.NoError(t, err)
+	replayCtx := <-delivered
+
+	close(ed.closed)
+	ed.close()
+
+	// The replay is dropped without touching the dead letter, and the delivery is cancelled
+	<-replayCtx.Done()
+	assert.Empty(t, ed.replays)
+
+	mdi.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterGetEventFail(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: fftypes.NewUUID()}
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", dl.Event).Return(nil, fmt.Errorf("pop"))
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayDeadLetterEventNotFound(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: fftypes.NewUUID()}
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", dl.Event).Return(nil, nil)
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.Regexp(t, "FF10461", err)
+}
+
+func TestReplayDeadLetterEnrichFail(t *testing.T) {
+	ed, cancel := newTestDeadLetterDispatcher()
+	defer cancel()
+
+	event := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeContractAPIConfirmed, Reference: fftypes.NewUUID()}
+	dl := &core.DeadLetter{ID: fftypes.NewUUID(), Event: event.ID}
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetEventByID", mock.Anything, "ns1", event.ID).Return(event, nil)
+	mdi.On("GetContractAPIByID", mock.Anything, "ns1", event.Reference).Return(nil, fmt.Errorf("pop"))
+
+	err := ed.replayDeadLetter(context.Background(), dl)
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, ed.replays)
+}
+
+func TestEventDispatcherBatchOptions(t *testing.T) {
+	size := uint16(100)
+	timeout := "1s"
+	ed, cancel := newTestEventDispatcher(&subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Batch: &core.SubscriptionBatchOptions{
+						Size:    &size,
+						Timeout: &timeout,
+					},
+				},
+			},
+		},
+	})
+	defer cancel()
+	assert.True(t, ed.batch)
+	assert.Equal(t, 100, ed.batchSize)
+	assert.Equal(t, 1*time.Second, ed.batchTimeout)
+	assert.Equal(t, 100, ed.readAhead)
+}
+
+func TestEventDispatcherBatchDefaults(t *testing.T) {
+	ten := uint16(10)
+	ed, cancel := newTestEventDispatcher(&subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					ReadAhead: &ten,
+					Batch:     &core.SubscriptionBatchOptions{},
+				},
+			},
+		},
+	})
+	defer cancel()
+	assert.True(t, ed.batch)
+	assert.Equal(t, 50, ed.batchSize)
+	assert.Equal(t, 50*time.Millisecond, ed.batchTimeout)
+	assert.Equal(t, 50, ed.readAhead)
+}
+
+func TestEventDispatcherBatchDelivery(t *testing.T) {
+	log.SetLevel("debug")
+	size := uint16(2)
+	timeout := "10ms"
+	sub := &subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+			Ephemeral:       true,
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Batch: &core.SubscriptionBatchOptions{
+						Size:    &size,
+						Timeout: &timeout,
+					},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	go ed.deliverEvents()
+
+	mdm := ed.data.(*datamocks.Manager)
+	mei := ed.transport.(*eventsmocks.Plugin)
+
+	batches := make(chan []*core.CombinedEventDataDelivery)
+	mei.On("BatchDeliveryRequest", mock.Anything, ed.connID, sub.definition, mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		batches <- a.Get(3).([]*core.CombinedEventDataDelivery)
+	})
+
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+	ref2 := fftypes.NewUUID()
+	ev2 := fftypes.NewUUID()
+	ref3 := fftypes.NewUUID()
+	ev3 := fftypes.NewUUID()
+	for _, ref := range []*fftypes.UUID{ref1, ref2, ref3} {
+		mdm.On("GetMessageWithDataCached", mock.Anything, ref).Return(&core.Message{
+			Header: core.MessageHeader{ID: ref},
+		}, nil, true, nil)
+	}
+
+	deliveryDone := make(chan struct{})
+	go func() {
+		repoll, err := ed.bufferedDelivery([]core.LocallySequenced{
+			&core.Event{ID: ev1, Sequence: 10000001, Reference: ref1, Type: core.EventTypeMessageConfirmed},
+			&core.Event{ID: ev2, Sequence: 10000002, Reference: ref2, Type: core.EventTypeMessageConfirmed},
+			&core.Event{ID: ev3, Sequence: 10000003, Reference: ref3, Type: core.EventTypeMessageConfirmed},
+		})
+		assert.NoError(t, err)
+		assert.True(t, repoll)
+		close(deliveryDone)
+	}()
+
+	// The first batch is full
+	batch1 := <-batches
+	assert.Len(t, batch1, 2)
+	assert.Equal(t, *ev1, *batch1[0].Event.ID)
+	assert.Equal(t, *ev2, *batch1[1].Event.ID)
+
+	// The second batch is delivered on the timeout
+	batch2 := <-batches
+	assert.Len(t, batch2, 1)
+	assert.Equal(t, *ev3, *batch2[0].Event.ID)
+
+	for _, e := range append(batch1, batch2...) {
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: e.Event.ID})
+	}
+	<-deliveryDone
+	assert.Equal(t, int64(10000003), ed.eventPoller.getPollingOffset())
+
+	mei.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestDeliverBatchWithData(t *testing.T) {
+	yes := true
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					WithData: &yes,
+					Batch:    &core.SubscriptionBatchOptions{},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, msg).Return(data, true, nil)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("BatchDeliveryRequest", mock.Anything, ed.connID, sub.definition, mock.MatchedBy(func(events []*core.CombinedEventDataDelivery) bool {
+		return len(events) == 2 && events[0].Data[0] == data[0] && events[1].Data == nil
+	})).Return(nil)
+
+	ed.deliverBatch([]*core.EventDelivery{
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}, Message: msg}},
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}},
+	})
+
+	mdm.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestDeliverBatchWithDataFail(t *testing.T) {
+	yes := true
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					WithData: &yes,
+					Batch:    &core.SubscriptionBatchOptions{},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, mock.Anything).Return(nil, false, fmt.Errorf("pop")).Once()
+
+	id1 := fftypes.NewUUID()
+	id2 := fftypes.NewUUID()
+	ed.inflight[*id1] = &core.Event{ID: id1, Sequence: 1}
+	ed.inflight[*id2] = &core.Event{ID: id2, Sequence: 2}
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	go ed.deliverBatch([]*core.EventDelivery{
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: id1}, Message: msg}},
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: id2}, Message: msg}},
+	})
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+	assert.Equal(t, *id1, an.id)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestDeliverBatchTransportFail(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Batch: &core.SubscriptionBatchOptions{},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("BatchDeliveryRequest", mock.Anything, ed.connID, sub.definition, mock.Anything).Return(fmt.Errorf("pop"))
+
+	id1 := fftypes.NewUUID()
+	ed.inflight[*id1] = &core.Event{ID: id1, Sequence: 1}
+	go ed.deliverBatch([]*core.EventDelivery{
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: id1}}},
+	})
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+	assert.Equal(t, *id1, an.id)
+
+	mei.AssertExpectations(t)
+}
+
+func TestDeliverBatchedEventsClosed(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Batch: &core.SubscriptionBatchOptions{},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	close(ed.eventDelivery)
+	ed.deliverEvents()
+	cancel()
+
+	ed, cancel = newTestEventDispatcher(sub)
+	cancel()
+	ed.cancelCtx()
+	ed.deliverEvents()
+}
@@ -56,7 +56,7 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.Transaction = tx
-	case core.EventTypeMessageConfirmed, core.EventTypeMessageRejected:
+	case core.EventTypeMessageConfirmed, core.EventTypeMessageRejected, core.EventTypeMessageReplyOverdue:
 		msg, _, _, err := em.data.GetMessageWithDataCached(ctx, event.Reference)
 		if err != nil {
 			return nil, err
@@ -86,6 +86,12 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.Datatype = dt
+	case core.EventTypeDefinitionProposed, core.EventTypeDefinitionRejected:
+		proposal, err := em.database.GetDefinitionProposalByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.DefinitionProposal = proposal
 	case core.EventTypeIdentityConfirmed, core.EventTypeIdentityUpdated:
 		identity, err := em.database.GetIdentityByID(ctx, em.namespace, event.Reference)
 		if err != nil {
@@ -110,6 +116,12 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.TokenTransfer = transfer
+	case core.EventTypeTokenBalanceCorrected:
+		mismatch, err := em.database.GetTokenBalanceMismatchByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.TokenBalanceMismatch = mismatch
 	case core.EventTypeApprovalOpFailed, core.EventTypeTransferOpFailed, core.EventTypeBlockchainInvokeOpFailed, core.EventTypePoolOpFailed, core.EventTypeBlockchainInvokeOpSucceeded:
 		operation, err := em.operations.GetOperationByIDCached(ctx, event.Reference)
 		if err != nil {
@@ -61,6 +61,7 @@ type EventManager interface {
 	DeleteDurableSubscription(ctx context.Context, subDef *core.Subscription) (err error)
 	CreateUpdateDurableSubscription(ctx context.Context, subDef *core.Subscription, mustNew bool) (err error)
 	EnrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error)
+	ReplayDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error
 	QueueBatchRewind(batchID *fftypes.UUID)
 	Start() error
 	WaitStop()
@@ -68,14 +69,14 @@ type EventManager interface {
 	// Bound blockchain callbacks
 	BatchPinComplete(namespace string, batch *blockchain.BatchPin, signingKey *core.VerifierRef) error
 	BlockchainEvent(event *blockchain.EventWithSubscription) error
-	BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error
+	BlockchainNetworkAction(action, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error
 
 	// Bound dataexchange callbacks
 	DXEvent(plugin dataexchange.Plugin, event dataexchange.DXEvent) error
 
 	// Bound sharedstorage callbacks
 	SharedStorageBatchDownloaded(ss sharedstorage.Plugin, payloadRef string, data []byte) (*fftypes.UUID, error)
-	SharedStorageBlobDownloaded(ss sharedstorage.Plugin, hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error
+	SharedStorageBlobDownloaded(ss sharedstorage.Plugin, hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID, encryption *core.BlobEncryption) error
 
 	// Bound token callbacks
 	TokenPoolCreated(ctx context.Context /* allows security context to be propagated when called in-line with the send TX */, ti tokens.Plugin, pool *tokens.TokenPool) error
@@ -210,6 +211,10 @@ func (em *eventManager) DeletedSubscriptions() chan<- *fftypes.UUID {
 	return em.subManager.deletedSubscriptions
 }
 
+func (em *eventManager) ReplayDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	return em.subManager.replayDeadLetter(ctx, deadLetter)
+}
+
 func (em *eventManager) WaitStop() {
 	em.subManager.close()
 	if em.blobReceiver != nil {
@@ -392,6 +392,15 @@ func TestCreateDurableSubscriptionBadSub(t *testing.T) {
 	assert.Regexp(t, "FF10189", err)
 }
 
+func TestReplayDeadLetterNotActive(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	err := em.ReplayDeadLetter(em.ctx, &core.DeadLetter{
+		Subscription: core.SubscriptionRef{ID: fftypes.NewUUID()},
+	})
+	assert.Regexp(t, "FF10458", err)
+}
+
 func TestCreateDurableSubscriptionDupName(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -0,0 +1,309 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventfilter implements the expression language used to filter the events delivered
+// to a subscription. Expressions are evaluated against the JSON representation of an enriched
+// event delivery, for example:
+//
+//	type == "token_transfer_confirmed" and tokenTransfer.amount > 1000 and tokenTransfer.pool == "1f6e..."
+//
+// Fields are referenced by path, using "." and "[]" to navigate objects and arrays. Literals are
+// numbers, single or double quoted strings, true, false, null and lists like ["a", "b"].
+// The operators are ==, !=, >, >=, <, <=, =~ (regular expression match), in, and, or, not
+// (also written &&, || and !), and parentheses can be used for grouping.
+//
+// Numbers are compared with arbitrary precision, and a string field is compared numerically
+// when the other side of the comparison is a number - so token amounts (which are serialized
+// as strings) can be compared with numeric literals. A path that does not exist evaluates to null,
+// and an ordering comparison involving null is false.
+package eventfilter
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// Expression is a parsed filter expression, that can be evaluated against many inputs
+type Expression struct {
+	root  node
+	roots map[string]bool
+}
+
+type parser struct {
+	ctx    context.Context
+	tokens []*token
+	pos    int
+	roots  map[string]bool
+}
+
+// Parse parses and validates a filter expression
+func Parse(ctx context.Context, expr string) (*Expression, error) {
+	tokens, err := lex(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{ctx: ctx, tokens: tokens, roots: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().typ != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return &Expression{root: root, roots: p.roots}, nil
+}
+
+// References returns true if the expression refers to the given top level field of the input
+func (e *Expression) References(field string) bool {
+	return e.roots[field]
+}
+
+// Matches evaluates the expression against an input, which should be a generic JSON structure
+// parsed with json.Decoder.UseNumber() so that numeric precision is preserved
+func (e *Expression) Matches(input map[string]interface{}) bool {
+	return truthy(e.root.eval(input))
+}
+
+func (p *parser) peek() *token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() *token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.typ == tokenEOF {
+		return i18n.NewError(p.ctx, coremsgs.MsgFilterExpressionUnexpectedEnd)
+	}
+	return i18n.NewError(p.ctx, coremsgs.MsgFilterExpressionUnexpected, t.text, t.pos)
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	for _, op := range ops {
+		if (t.typ == tokenOperator || t.typ == tokenIdent) && t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("or", "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("and", "&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("not", "!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isOperator("==", "!=", ">", ">=", "<", "<="):
+		op := p.next().text
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	case p.isOperator("in"):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{left: left, right: right}, nil
+	case p.isOperator("=~"):
+		p.next()
+		t := p.next()
+		if t.typ != tokenString {
+			return nil, i18n.NewError(p.ctx, coremsgs.MsgFilterExpressionRegexpLiteral, t.pos)
+		}
+		re, err := regexp.Compile(t.value)
+		if err != nil {
+			return nil, i18n.WrapError(p.ctx, err, coremsgs.MsgRegexpCompileFailed, "filter.expression", t.value)
+		}
+		return &matchNode{left: left, re: re}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.peek()
+	switch t.typ {
+	case tokenNumber:
+		p.next()
+		n, ok := parseNumber(t.text)
+		if !ok {
+			return nil, i18n.NewError(p.ctx, coremsgs.MsgFilterExpressionBadNumber, t.text, t.pos)
+		}
+		return &literalNode{value: n}, nil
+	case tokenString:
+		p.next()
+		return &literalNode{value: t.value}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			p.next()
+			return &literalNode{value: true}, nil
+		case "false":
+			p.next()
+			return &literalNode{value: false}, nil
+		case "null":
+			p.next()
+			return &literalNode{value: nil}, nil
+		case "and", "or", "not", "in":
+			return nil, p.unexpected()
+		}
+		return p.parsePath()
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			p.next()
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			return p.parseList()
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseList() (node, error) {
+	p.next()
+	list := &listNode{}
+	if p.isOperator("]") {
+		p.next()
+		return list, nil
+	}
+	for {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (p *parser) parsePath() (node, error) {
+	root := p.next().text
+	p.roots[root] = true
+	path := &pathNode{segments: []interface{}{root}}
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			t := p.peek()
+			if t.typ != tokenIdent {
+				return nil, p.unexpected()
+			}
+			p.next()
+			path.segments = append(path.segments, t.text)
+		case p.isOperator("["):
+			p.next()
+			t := p.peek()
+			switch t.typ {
+			case tokenString:
+				path.segments = append(path.segments, t.value)
+			case tokenNumber:
+				idx, ok := new(big.Int).SetString(t.text, 10)
+				if !ok || !idx.IsInt64() || idx.Sign() < 0 {
+					return nil, p.unexpected()
+				}
+				path.segments = append(path.segments, int(idx.Int64()))
+			default:
+				return nil, p.unexpected()
+			}
+			p.next()
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
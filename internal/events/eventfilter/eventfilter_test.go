@@ -0,0 +1,188 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eventfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEvent = `{
+	"id": "5f4c1a8e-2a8b-4b55-9bfc-0cbe7bbbbd40",
+	"type": "token_transfer_confirmed",
+	"topic": "pool1",
+	"tokenTransfer": {
+		"type": "transfer",
+		"pool": "9c5e3e9c-4b6f-4b6a-8a3a-6f5c0b5d7e01",
+		"amount": "123456789012345678901234567890",
+		"from": "0x111",
+		"to": "0x222"
+	},
+	"blockchainEvent": {
+		"name": "Transfer",
+		"output": {
+			"value": 1500,
+			"tags": ["a", "b"],
+			"flag": true,
+			"ratio": 0.25
+		}
+	},
+	"data": [
+		{"value": {"amount": 2000, "currency": "USD"}}
+	]
+}`
+
+func testInput(t *testing.T) map[string]interface{} {
+	var input map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader([]byte(testEvent)))
+	d.UseNumber()
+	err := d.Decode(&input)
+	assert.NoError(t, err)
+	return input
+}
+
+func TestExpressionMatches(t *testing.T) {
+	input := testInput(t)
+	for expr, expected := range map[string]bool{
+		`type == "token_transfer_confirmed"`: true,
+		`tokenTransfer.amount > 1000 and tokenTransfer.pool == "9c5e3e9c-4b6f-4b6a-8a3a-6f5c0b5d7e01"`: true,
+		`tokenTransfer.amount > 123456789012345678901234567889`:                                        true,
+		`tokenTransfer.amount >= 123456789012345678901234567891`:                                       false,
+		`tokenTransfer.amount < 1e30 || tokenTransfer.amount <= 1.5`:                                   true,
+		`blockchainEvent.output.value >= 1500 && blockchainEvent.output.value < 1501`:                  true,
+		`blockchainEvent.output.value != 1500`:                                                         false,
+		`blockchainEvent.output.ratio == 0.25`:                                                         true,
+		`blockchainEvent.output.flag`:                                                                  true,
+		`blockchainEvent.output.flag == true and not (blockchainEvent.output.flag == false)`:           true,
+		`!blockchainEvent.output.flag`:                                                                 false,
+		`blockchainEvent.output.tags[1] == "b"`:                                                        true,
+		`blockchainEvent.output.tags[2] == null`:                                                       true,
+		`blockchainEvent.output["tags"][0] in ["a", "z"]`:                                              true,
+		`blockchainEvent.name in ["Approval"]`:                                                         false,
+		`blockchainEvent.name in "Transfer"`:                                                           false,
+		`blockchainEvent.name =~ "^Trans"`:                                                             true,
+		`blockchainEvent.output.value =~ "^15"`:                                                        true,
+		`blockchainEvent.output.flag =~ "true"`:                                                        false,
+		`data[0].value.amount > 1000 && data[0].value.currency == 'USD'`:                               true,
+		`message.header.tag == "tag1"`:                                                                 false,
+		`message == null`:                                                                              true,
+		`message > 1`:                                                                                  false,
+		`topic > "pool0" and topic < "pool2"`:                                                          true,
+		`topic > true`:                                                                                 false,
+		`tokenTransfer.from != tokenTransfer.to`:                                                       true,
+		`tokenTransfer.from == 'ox111'`:                                                                false,
+		`type.length == 1`:                                                                             false,
+		`data.amount`:                                                                                  false,
+		`tokenTransfer`:                                                                                true,
+		`topic`:                                                                                        true,
+		`""`:                                                                                           false,
+		`0`:                                                                                            false,
+		`[]`:                                                                                           true,
+		`1 == 1`:                                                                                       true,
+		`false == false`:                                                                               true,
+		`false != true`:                                                                                true,
+		`"1000" == 1000`:                                                                               true,
+		`"1000" == "1000.0"`:                                                                           false,
+	} {
+		e, err := Parse(context.Background(), expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, e.Matches(input), expr)
+	}
+}
+
+func TestExpressionMatchesFloats(t *testing.T) {
+	input := map[string]interface{}{"value": float64(1.5)}
+	for expr, expected := range map[string]bool{
+		`value <= 1.5`:  true,
+		`value < 1.5`:   false,
+		`value == 1.5`:  true,
+		`value =~ "1"`:  false,
+		`1.5 =~ "1"`:    false,
+		`value > "1.4"`: true,
+	} {
+		e, err := Parse(context.Background(), expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, e.Matches(input), expr)
+	}
+}
+
+func TestExpressionReferences(t *testing.T) {
+	e, err := Parse(context.Background(), `type == "message_confirmed" and data[0].value.amount > 10`)
+	assert.NoError(t, err)
+	assert.True(t, e.References("type"))
+	assert.True(t, e.References("data"))
+	assert.False(t, e.References("message"))
+}
+
+func TestExpressionParseErrors(t *testing.T) {
+	for expr, errRegexp := range map[string]string{
+		``:                   "FF10470",
+		`a ==`:               "FF10470",
+		`a == b c`:           "FF10468.*'c'.*7",
+		`(a == b`:            "FF10470",
+		`a and or b`:         "FF10468.*'or'",
+		`a.`:                 "FF10470",
+		`a.1`:                "FF10468.*'1'",
+		`a[b]`:               "FF10468.*'b'",
+		`a[1.5]`:             "FF10468.*'1.5'",
+		`a[-1]`:              "FF10468.*'-1'",
+		`a[0`:                "FF10470",
+		`[1, 2`:              "FF10470",
+		`[1, ]`:              "FF10468.*']'",
+		`a =~ b`:             "FF10471",
+		`a =~ "["`:           "FF10171",
+		`a == "unterminated`: "FF10469",
+		`a ~ b`:              "FF10468.*'~'",
+		`)`:                  "FF10468.*'\\)'",
+		`a in`:               "FF10470",
+		`not`:                "FF10470",
+		`a or`:               "FF10470",
+		`(`:                  "FF10470",
+		`a > 1e100000000`:    "FF10572.*'1e100000000'.*4",
+	} {
+		_, err := Parse(context.Background(), expr)
+		assert.Regexp(t, errRegexp, err, expr)
+	}
+}
+
+func TestExpressionBadNumberToken(t *testing.T) {
+	p := &parser{ctx: context.Background(), tokens: []*token{{typ: tokenNumber, text: "1.2.3"}}}
+	_, err := p.parseOperand()
+	assert.Regexp(t, "FF10572", err)
+}
+
+func TestExpressionMatchesUnboundedNumbers(t *testing.T) {
+	input := map[string]interface{}{
+		"big":      json.Number("1e100000000"),
+		"bigstr":   "-1E+100000000",
+		"infinite": math.Inf(1),
+	}
+	for expr, expected := range map[string]bool{
+		`big > 1`:      false,
+		`bigstr < 1`:   false,
+		`infinite > 1`: false,
+		`big == big`:   false,
+	} {
+		e, err := Parse(context.Background(), expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, e.Matches(input), expr)
+	}
+}
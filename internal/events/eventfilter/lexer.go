@@ -0,0 +1,142 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventfilter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	typ   tokenType
+	text  string
+	value string // unescaped value of a string literal
+	pos   int
+}
+
+// operators are matched longest first
+var operators = []string{"==", "!=", ">=", "<=", "=~", "&&", "||", ">", "<", "!", "(", ")", "[", "]", ",", "."}
+
+// maxExponentDigits bounds the exponent of a number literal, as the exact value of a number with a
+// very large exponent is expensive to compute and hold
+const maxExponentDigits = 4
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func lex(ctx context.Context, expr string) ([]*token, error) {
+	tokens := []*token{}
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(expr) && isIdentChar(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, &token{typ: tokenIdent, text: expr[start:i], pos: start})
+		case isDigit(c) || (c == '-' && i+1 < len(expr) && isDigit(expr[i+1])):
+			start := i
+			i++
+			for i < len(expr) && isDigit(expr[i]) {
+				i++
+			}
+			if i+1 < len(expr) && expr[i] == '.' && isDigit(expr[i+1]) {
+				i++
+				for i < len(expr) && isDigit(expr[i]) {
+					i++
+				}
+			}
+			if i < len(expr) && (expr[i] == 'e' || expr[i] == 'E') {
+				j := i + 1
+				if j < len(expr) && (expr[j] == '+' || expr[j] == '-') {
+					j++
+				}
+				if j < len(expr) && isDigit(expr[j]) {
+					for i = j; i < len(expr) && isDigit(expr[i]); i++ {
+					}
+					if i-j > maxExponentDigits {
+						return nil, i18n.NewError(ctx, coremsgs.MsgFilterExpressionBadNumber, expr[start:i], start)
+					}
+				}
+			}
+			tokens = append(tokens, &token{typ: tokenNumber, text: expr[start:i], pos: start})
+		case c == '\'' || c == '"':
+			start := i
+			var value strings.Builder
+			i++
+			terminated := false
+			for i < len(expr) {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					value.WriteByte(expr[i+1])
+					i += 2
+					continue
+				}
+				if expr[i] == c {
+					terminated = true
+					i++
+					break
+				}
+				value.WriteByte(expr[i])
+				i++
+			}
+			if !terminated {
+				return nil, i18n.NewError(ctx, coremsgs.MsgFilterExpressionUnterminated, start)
+			}
+			tokens = append(tokens, &token{typ: tokenString, text: expr[start:i], value: value.String(), pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, &token{typ: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, i18n.NewError(ctx, coremsgs.MsgFilterExpressionUnexpected, string(c), i)
+			}
+		}
+	}
+	return append(tokens, &token{typ: tokenEOF, pos: len(expr)}), nil
+}
@@ -0,0 +1,61 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eventfilter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexTokens(t *testing.T) {
+	tokens, err := lex(context.Background(), `a.b[0] >= -1.5e3 && $x != 'it\'s' || "q" =~ "^q$"`)
+	assert.NoError(t, err)
+	texts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		texts[i] = tok.text
+	}
+	assert.Equal(t, []string{"a", ".", "b", "[", "0", "]", ">=", "-1.5e3", "&&", "$x", "!=", `'it\'s'`, "||", `"q"`, "=~", `"^q$"`, ""}, texts)
+	assert.Equal(t, tokenNumber, tokens[7].typ)
+	assert.Equal(t, "it's", tokens[11].value)
+	assert.Equal(t, tokenEOF, tokens[16].typ)
+}
+
+func TestLexNumberForms(t *testing.T) {
+	tokens, err := lex(context.Background(), "1 2.5 3e 4E+2 5.x")
+	assert.NoError(t, err)
+	texts := []string{}
+	for _, tok := range tokens {
+		texts = append(texts, tok.text)
+	}
+	assert.Equal(t, []string{"1", "2.5", "3", "e", "4E+2", "5", ".", "x", ""}, texts)
+}
+
+func TestLexUnterminatedString(t *testing.T) {
+	_, err := lex(context.Background(), `a == "abc`)
+	assert.Regexp(t, "FF10469.*5", err)
+}
+
+func TestLexUnexpectedChar(t *testing.T) {
+	_, err := lex(context.Background(), "a # b")
+	assert.Regexp(t, "FF10468.*'#'.*2", err)
+}
+
+func TestLexExponentTooLarge(t *testing.T) {
+	_, err := lex(context.Background(), "a > 1e9999 && b < 2e10000")
+	assert.Regexp(t, "FF10572.*'2e10000'.*18", err)
+}
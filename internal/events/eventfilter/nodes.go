@@ -0,0 +1,246 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventfilter
+
+import (
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+type node interface {
+	eval(input map[string]interface{}) interface{}
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+type listNode struct {
+	items []node
+}
+
+type pathNode struct {
+	segments []interface{} // string field names, or int array indexes
+}
+
+type andNode struct {
+	left, right node
+}
+
+type orNode struct {
+	left, right node
+}
+
+type notNode struct {
+	operand node
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+type inNode struct {
+	left, right node
+}
+
+type matchNode struct {
+	left node
+	re   *regexp.Regexp
+}
+
+func (n *literalNode) eval(input map[string]interface{}) interface{} {
+	return n.value
+}
+
+func (n *listNode) eval(input map[string]interface{}) interface{} {
+	values := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		values[i] = item.eval(input)
+	}
+	return values
+}
+
+func (n *pathNode) eval(input map[string]interface{}) interface{} {
+	var current interface{} = input
+	for _, segment := range n.segments {
+		switch s := segment.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			current = obj[s]
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || s >= len(arr) {
+				return nil
+			}
+			current = arr[s]
+		}
+	}
+	return current
+}
+
+func (n *andNode) eval(input map[string]interface{}) interface{} {
+	return truthy(n.left.eval(input)) && truthy(n.right.eval(input))
+}
+
+func (n *orNode) eval(input map[string]interface{}) interface{} {
+	return truthy(n.left.eval(input)) || truthy(n.right.eval(input))
+}
+
+func (n *notNode) eval(input map[string]interface{}) interface{} {
+	return !truthy(n.operand.eval(input))
+}
+
+func (n *compareNode) eval(input map[string]interface{}) interface{} {
+	left := n.left.eval(input)
+	right := n.right.eval(input)
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+	c, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default: // "<="
+		return c <= 0
+	}
+}
+
+func (n *inNode) eval(input map[string]interface{}) interface{} {
+	left := n.left.eval(input)
+	list, ok := n.right.eval(input).([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if equal(left, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *matchNode) eval(input map[string]interface{}) interface{} {
+	s, ok := toString(n.left.eval(input))
+	return ok && n.re.MatchString(s)
+}
+
+func truthy(v interface{}) bool {
+	switch vt := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return vt
+	case string:
+		return vt != ""
+	}
+	if n, ok := toNumber(v); ok {
+		return n.Sign() != 0
+	}
+	return true
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case *big.Rat, json.Number, float64:
+		return true
+	}
+	return false
+}
+
+// parseNumber parses the exact value of a decimal number, refusing exponents beyond the bounds
+// accepted in a filter expression, so event data cannot force an expensive conversion
+func parseNumber(text string) (*big.Rat, bool) {
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exp := strings.TrimLeft(text[i+1:], "+-")
+		if len(strings.TrimLeft(exp, "0")) > maxExponentDigits {
+			return nil, false
+		}
+	}
+	return new(big.Rat).SetString(text)
+}
+
+func toNumber(v interface{}) (n *big.Rat, ok bool) {
+	switch vt := v.(type) {
+	case *big.Rat:
+		n, ok = vt, true
+	case json.Number:
+		n, ok = parseNumber(vt.String())
+	case float64:
+		n, ok = new(big.Rat).SetFloat64(vt), true
+	case string:
+		n, ok = parseNumber(vt)
+	}
+	// SetFloat64 returns nil for non-finite values
+	return n, ok && n != nil
+}
+
+func toString(v interface{}) (string, bool) {
+	switch vt := v.(type) {
+	case string:
+		return vt, true
+	case json.Number:
+		return vt.String(), true
+	}
+	return "", false
+}
+
+// compare returns the ordering of two values - numerically if either is a number,
+// or lexically if both are strings. Other combinations cannot be ordered.
+func compare(a, b interface{}) (int, bool) {
+	if isNumber(a) || isNumber(b) {
+		na, okA := toNumber(a)
+		nb, okB := toNumber(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		return na.Cmp(nb), true
+	}
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if !okA || !okB {
+		return 0, false
+	}
+	return strings.Compare(sa, sb), true
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	ba, okA := a.(bool)
+	bb, okB := b.(bool)
+	return okA && okB && ba == bb
+}
@@ -0,0 +1,35 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// KafkaConfClusterID is the ID of the Kafka cluster, as used in the REST Proxy v3 API paths
+	KafkaConfClusterID = "clusterId"
+	// KafkaConfTopic is the default topic for subscriptions that do not specify one
+	KafkaConfTopic = "topic"
+)
+
+func (k *Kafka) InitConfig(config config.Section) {
+	ffresty.InitConfig(config)
+	config.AddKnownKey(KafkaConfClusterID)
+	config.AddKnownKey(KafkaConfTopic)
+}
@@ -0,0 +1,313 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// Kafka is an event transport that publishes each event delivery as a record on a Kafka topic,
+// via the Kafka REST Proxy v3 produce API. A publish confirmation from the broker acknowledges
+// the event, and a failed publish rejects it (so it will be redelivered).
+type Kafka struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	client       *resty.Client
+	connID       string
+	clusterID    string
+	defaultTopic string
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// KeyField is the field of the event used as the record key, which determines the partition within the topic
+type KeyField string
+
+const (
+	KeyFieldTopic       KeyField = "topic"
+	KeyFieldType        KeyField = "type"
+	KeyFieldReference   KeyField = "reference"
+	KeyFieldTransaction KeyField = "tx"
+	KeyFieldNone        KeyField = "none"
+)
+
+type kafkaRecord struct {
+	PartitionID *int64           `json:"partition_id,omitempty"`
+	Headers     []*kafkaHeader   `json:"headers,omitempty"`
+	Key         *kafkaRecordData `json:"key,omitempty"`
+	Value       *kafkaRecordData `json:"value"`
+}
+
+type kafkaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"` // base64 encoded
+}
+
+type kafkaRecordData struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type kafkaProduceResponse struct {
+	ErrorCode   int    `json:"error_code"`
+	Message     string `json:"message,omitempty"`
+	ClusterID   string `json:"cluster_id,omitempty"`
+	TopicName   string `json:"topic_name,omitempty"`
+	PartitionID int64  `json:"partition_id"`
+	Offset      int64  `json:"offset"`
+}
+
+type kafkaPayload struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+type publishOptions struct {
+	topic     string
+	keyField  KeyField
+	partition *int64
+	headers   map[string]string
+}
+
+func (k *Kafka) Name() string { return "kafka" }
+
+func (k *Kafka) Init(ctx context.Context, config config.Section) (err error) {
+	if config.GetString(ffresty.HTTPConfigURL) == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "url", "events.kafka")
+	}
+	clusterID := config.GetString(KafkaConfClusterID)
+	if clusterID == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, KafkaConfClusterID, "events.kafka")
+	}
+
+	client, err := ffresty.New(ctx, config)
+	if err != nil {
+		return err
+	}
+	connID := fftypes.ShortID()
+	*k = Kafka{
+		ctx:          log.WithLogField(ctx, "kafka", connID),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		client:       client,
+		connID:       connID,
+		clusterID:    clusterID,
+		defaultTopic: config.GetString(KafkaConfTopic),
+	}
+	return nil
+}
+
+func (k *Kafka) SetHandler(namespace string, handler events.Callbacks) error {
+	k.callbacks.writeLock.Lock()
+	defer k.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(k.callbacks.handlers, namespace)
+		return nil
+	}
+	k.callbacks.handlers[namespace] = handler
+	// We have a single logical connection to the broker, that matches all subscriptions
+	return handler.RegisterConnection(k.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (k *Kafka) Capabilities() *events.Capabilities {
+	return k.capabilities
+}
+
+func (k *Kafka) parseOptions(options fftypes.JSONObject) (*publishOptions, error) {
+	po := &publishOptions{
+		topic:    options.GetString("topic"),
+		keyField: KeyField(options.GetString("key")),
+		headers:  make(map[string]string),
+	}
+	if po.topic == "" {
+		po.topic = k.defaultTopic
+	}
+	if po.topic == "" {
+		return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaTopicEmpty)
+	}
+	switch po.keyField {
+	case "":
+		po.keyField = KeyFieldTopic
+	case KeyFieldTopic, KeyFieldType, KeyFieldReference, KeyFieldTransaction, KeyFieldNone:
+	default:
+		return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaInvalidKeyField, po.keyField)
+	}
+	if partition, ok := options["partition"]; ok {
+		p, ok := partition.(float64)
+		if !ok || p < 0 || p != float64(int64(p)) {
+			return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaInvalidPartition, partition)
+		}
+		pInt := int64(p)
+		po.partition = &pInt
+	}
+	for h, v := range options.GetObject("headers") {
+		s, ok := v.(string)
+		if !ok {
+			return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaInvalidStringMap, "headers", h, v)
+		}
+		po.headers[h] = s
+	}
+	return po, nil
+}
+
+func (k *Kafka) ValidateOptions(options *core.SubscriptionOptions) error {
+	if options.WithData == nil {
+		defaultFalse := false
+		options.WithData = &defaultFalse
+	}
+	_, err := k.parseOptions(options.TransportOptions())
+	return err
+}
+
+func (k *Kafka) recordKey(keyField KeyField, event *core.EventDelivery) *kafkaRecordData {
+	var key string
+	switch keyField {
+	case KeyFieldType:
+		key = event.Type.String()
+	case KeyFieldReference:
+		key = event.Reference.String()
+	case KeyFieldTransaction:
+		key = event.Event.Transaction.String()
+	case KeyFieldTopic:
+		key = event.Topic
+	}
+	if key == "" {
+		// Let the broker choose the partition
+		return nil
+	}
+	return &kafkaRecordData{Type: "STRING", Data: key}
+}
+
+func (k *Kafka) buildRecord(po *publishOptions, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) *kafkaRecord {
+	headers := map[string]string{
+		"ff-namespace":    event.Namespace,
+		"ff-event-id":     event.ID.String(),
+		"ff-event-type":   event.Type.String(),
+		"ff-subscription": sub.Name,
+	}
+	for h, v := range po.headers {
+		headers[h] = v
+	}
+	payload := &kafkaPayload{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		payload.Data = data
+	}
+	record := &kafkaRecord{
+		PartitionID: po.partition,
+		Key:         k.recordKey(po.keyField, event),
+		Value: &kafkaRecordData{
+			Type: "JSON",
+			Data: payload,
+		},
+	}
+	// Emit headers in a deterministic order
+	names := make([]string, 0, len(headers))
+	for h := range headers {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+	for _, h := range names {
+		record.Headers = append(record.Headers, &kafkaHeader{
+			Name:  h,
+			Value: base64.StdEncoding.EncodeToString([]byte(headers[h])),
+		})
+	}
+	return record
+}
+
+func (k *Kafka) publish(po *publishOptions, record *kafkaRecord) (*kafkaProduceResponse, error) {
+	var produceRes kafkaProduceResponse
+	var errRes kafkaProduceResponse
+	path := fmt.Sprintf("/v3/clusters/%s/topics/%s/records", url.PathEscape(k.clusterID), url.PathEscape(po.topic))
+	res, err := k.client.R().
+		SetContext(k.ctx).
+		SetBody(record).
+		SetResult(&produceRes).
+		SetError(&errRes).
+		Post(path)
+	if err != nil || !res.IsSuccess() {
+		if err == nil && errRes.Message != "" {
+			return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaPublishFailed, errRes.ErrorCode, errRes.Message)
+		}
+		return nil, ffresty.WrapRestErr(k.ctx, res, err, coremsgs.MsgKafkaRESTErr)
+	}
+	// The REST proxy can return a 200 with a per-record error code
+	if produceRes.ErrorCode != 0 && produceRes.ErrorCode != 200 {
+		return nil, i18n.NewError(k.ctx, coremsgs.MsgKafkaPublishFailed, produceRes.ErrorCode, produceRes.Message)
+	}
+	return &produceRes, nil
+}
+
+func (k *Kafka) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	po, err := k.parseOptions(sub.Options.TransportOptions())
+	if err != nil {
+		return err
+	}
+
+	record := k.buildRecord(po, sub, event, data)
+	log.L(k.ctx).Debugf("Kafka-> topic=%s event %s on subscription %s", po.topic, event.ID, sub.ID)
+	produceRes, err := k.publish(po, record)
+
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err != nil {
+		// Reject the event, so the dispatcher rewinds and redelivers it
+		log.L(k.ctx).Errorf("Kafka<- topic=%s event %s on subscription %s failed: %s", po.topic, event.ID, sub.ID, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	} else {
+		log.L(k.ctx).Debugf("Kafka<- topic=%s event %s on subscription %s confirmed partition=%d offset=%d", produceRes.TopicName, event.ID, sub.ID, produceRes.PartitionID, produceRes.Offset)
+		response.Info = fmt.Sprintf("partition=%d offset=%d", produceRes.PartitionID, produceRes.Offset)
+	}
+
+	if cb, ok := k.callbacks.handlers[sub.Namespace]; ok {
+		cb.DeliveryResponse(connID, response)
+	}
+	return nil
+}
+
+func (k *Kafka) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(k.ctx, coremsgs.MsgBatchDeliveryNotSupported, k.Name())
+}
+
+func (k *Kafka) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
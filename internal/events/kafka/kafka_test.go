@@ -0,0 +1,473 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testPartitions = 4
+
+// testBroker is an in-process stand-in for a Kafka REST Proxy, holding published records in memory
+type testBroker struct {
+	mux     sync.Mutex
+	server  *httptest.Server
+	topics  map[string][][]*kafkaRecord
+	failing map[string]int
+}
+
+func newTestBroker() *testBroker {
+	b := &testBroker{
+		topics:  make(map[string][][]*kafkaRecord),
+		failing: make(map[string]int),
+	}
+	r := mux.NewRouter()
+	r.HandleFunc("/v3/clusters/{cluster}/topics/{topic}/records", b.produce).Methods(http.MethodPost)
+	b.server = httptest.NewServer(r)
+	return b
+}
+
+func (b *testBroker) produce(res http.ResponseWriter, req *http.Request) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	vars := mux.Vars(req)
+	topic := vars["topic"]
+	res.Header().Set("Content-Type", "application/json")
+	if code, failing := b.failing[topic]; failing {
+		res.WriteHeader(code)
+		_ = json.NewEncoder(res).Encode(&kafkaProduceResponse{ErrorCode: code, Message: "topic unavailable"})
+		return
+	}
+	var record kafkaRecord
+	if err := json.NewDecoder(req.Body).Decode(&record); err != nil {
+		res.WriteHeader(400)
+		return
+	}
+	partitions, ok := b.topics[topic]
+	if !ok {
+		partitions = make([][]*kafkaRecord, testPartitions)
+		b.topics[topic] = partitions
+	}
+	var partition int64
+	switch {
+	case record.PartitionID != nil:
+		partition = *record.PartitionID
+	case record.Key != nil:
+		h := fnv.New32a()
+		h.Write([]byte(record.Key.Data.(string)))
+		partition = int64(h.Sum32() % testPartitions)
+	}
+	partitions[partition] = append(partitions[partition], &record)
+	_ = json.NewEncoder(res).Encode(&kafkaProduceResponse{
+		ErrorCode:   200,
+		ClusterID:   vars["cluster"],
+		TopicName:   topic,
+		PartitionID: partition,
+		Offset:      int64(len(partitions[partition]) - 1),
+	})
+}
+
+func (b *testBroker) records(topic string, partition int) []*kafkaRecord {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	partitions := b.topics[topic]
+	if partitions == nil {
+		return nil
+	}
+	return partitions[partition]
+}
+
+func newTestKafka(t *testing.T, url string) (k *Kafka, cbs *eventsmocks.Callbacks, cancel func()) {
+	coreconfig.Reset()
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	k = &Kafka{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	svrConfig := config.RootSection("ut.kafka")
+	k.InitConfig(svrConfig)
+	svrConfig.Set(ffresty.HTTPConfigURL, url)
+	svrConfig.Set(KafkaConfClusterID, "cluster1")
+	err := k.Init(ctx, svrConfig)
+	assert.NoError(t, err)
+	k.SetHandler("ns1", cbs)
+	assert.Equal(t, "kafka", k.Name())
+	assert.NotNil(t, k.Capabilities())
+	return k, cbs, cancelCtx
+}
+
+func newTestSubscription(options fftypes.JSONObject) *core.Subscription {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "kafka",
+	}
+	for k, v := range options {
+		sub.Options.TransportOptions()[k] = v
+	}
+	return sub
+}
+
+func newTestEvent(sub *core.Subscription, topic string) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Sequence:  12345,
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+				Reference: fftypes.NewUUID(),
+				Topic:     topic,
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+func TestInitMissingURL(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	svrConfig := config.RootSection("ut.kafka")
+	k.InitConfig(svrConfig)
+	err := k.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitMissingClusterID(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	svrConfig := config.RootSection("ut.kafka")
+	k.InitConfig(svrConfig)
+	svrConfig.Set(ffresty.HTTPConfigURL, "http://localhost:8082")
+	err := k.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10138.*clusterId", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	svrConfig := config.RootSection("ut.kafka")
+	k.InitConfig(svrConfig)
+	svrConfig.Set(ffresty.HTTPConfigURL, "https://localhost:8082")
+	svrConfig.Set(KafkaConfClusterID, "cluster1")
+	tlsConfig := svrConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := k.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestValidateOptionsDefaults(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["topic"] = "topic1"
+	err := k.ValidateOptions(opts)
+	assert.NoError(t, err)
+	assert.False(t, *opts.WithData)
+
+	k.SetHandler("ns1", nil)
+	assert.Empty(t, k.callbacks.handlers)
+}
+
+func TestValidateOptionsDefaultTopic(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	err := k.ValidateOptions(opts)
+	assert.Regexp(t, "FF10452", err)
+
+	k.defaultTopic = "default-topic"
+	po, err := k.parseOptions(opts.TransportOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, "default-topic", po.topic)
+	assert.Equal(t, KeyFieldTopic, po.keyField)
+}
+
+func TestValidateOptionsBadKey(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["topic"] = "topic1"
+	opts.TransportOptions()["key"] = "wrong"
+	err := k.ValidateOptions(opts)
+	assert.Regexp(t, "FF10453", err)
+}
+
+func TestValidateOptionsBadPartition(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["topic"] = "topic1"
+	opts.TransportOptions()["partition"] = 1.5
+	err := k.ValidateOptions(opts)
+	assert.Regexp(t, "FF10454", err)
+
+	opts.TransportOptions()["partition"] = "one"
+	err = k.ValidateOptions(opts)
+	assert.Regexp(t, "FF10454", err)
+}
+
+func TestValidateOptionsBadHeaders(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["topic"] = "topic1"
+	opts.TransportOptions()["headers"] = fftypes.JSONObject{
+		"good": "value",
+		"bad":  false,
+	}
+	err := k.ValidateOptions(opts)
+	assert.Regexp(t, "FF10455", err)
+}
+
+func TestDeliveryRequestPartitionByTopic(t *testing.T) {
+	broker := newTestBroker()
+	defer broker.server.Close()
+	k, cbs, cancel := newTestKafka(t, broker.server.URL)
+	defer cancel()
+
+	sub := newTestSubscription(fftypes.JSONObject{
+		"topic": "ff-events",
+		"headers": fftypes.JSONObject{
+			"custom": "value1",
+		},
+	})
+	event1 := newTestEvent(sub, "widgets")
+	event2 := newTestEvent(sub, "widgets")
+
+	acks := make(chan *core.EventDeliveryResponse, 2)
+	cbs.On("DeliveryResponse", k.connID, mock.Anything).Run(func(args mock.Arguments) {
+		acks <- args[1].(*core.EventDeliveryResponse)
+	})
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event1, nil)
+	assert.NoError(t, err)
+	err = k.DeliveryRequest(context.Background(), k.connID, sub, event2, nil)
+	assert.NoError(t, err)
+
+	ack1 := <-acks
+	assert.Equal(t, event1.ID, ack1.ID)
+	assert.False(t, ack1.Rejected)
+	assert.Equal(t, sub.SubscriptionRef, ack1.Subscription)
+	ack2 := <-acks
+	assert.Equal(t, event2.ID, ack2.ID)
+	assert.False(t, ack2.Rejected)
+
+	// Both events must land on the same partition, in order
+	h := fnv.New32a()
+	h.Write([]byte("widgets"))
+	partition := int(h.Sum32() % testPartitions)
+	records := broker.records("ff-events", partition)
+	assert.Len(t, records, 2)
+	assert.Equal(t, fmt.Sprintf("partition=%d offset=0", partition), ack1.Info)
+	assert.Equal(t, fmt.Sprintf("partition=%d offset=1", partition), ack2.Info)
+
+	record := records[0]
+	assert.Equal(t, "STRING", record.Key.Type)
+	assert.Equal(t, "widgets", record.Key.Data)
+	assert.Equal(t, "JSON", record.Value.Type)
+	value := record.Value.Data.(map[string]interface{})
+	assert.Equal(t, event1.ID.String(), value["id"])
+	assert.Nil(t, value["data"])
+	headers := make(map[string]string)
+	for _, h := range record.Headers {
+		b, err := base64.StdEncoding.DecodeString(h.Value)
+		assert.NoError(t, err)
+		headers[h.Name] = string(b)
+	}
+	assert.Equal(t, map[string]string{
+		"custom":          "value1",
+		"ff-event-id":     event1.ID.String(),
+		"ff-event-type":   "message_confirmed",
+		"ff-namespace":    "ns1",
+		"ff-subscription": "sub1",
+	}, headers)
+
+	cbs.AssertExpectations(t)
+}
+
+func TestDeliveryRequestExplicitPartitionWithData(t *testing.T) {
+	broker := newTestBroker()
+	defer broker.server.Close()
+	k, cbs, cancel := newTestKafka(t, broker.server.URL)
+	defer cancel()
+
+	yes := true
+	sub := newTestSubscription(fftypes.JSONObject{
+		"topic":     "ff-events",
+		"partition": float64(3),
+		"key":       "reference",
+	})
+	sub.Options.WithData = &yes
+	event := newTestEvent(sub, "widgets")
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"size":"large"}`)},
+	}
+
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected && r.Info == "partition=3 offset=0"
+	}))
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, data)
+	assert.NoError(t, err)
+
+	records := broker.records("ff-events", 3)
+	assert.Len(t, records, 1)
+	assert.Equal(t, event.Reference.String(), records[0].Key.Data)
+	value := records[0].Value.Data.(map[string]interface{})
+	assert.Equal(t, "large", value["data"].([]interface{})[0].(map[string]interface{})["value"].(map[string]interface{})["size"])
+
+	cbs.AssertExpectations(t)
+}
+
+func TestRecordKeys(t *testing.T) {
+	k := &Kafka{}
+	sub := newTestSubscription(fftypes.JSONObject{})
+	event := newTestEvent(sub, "")
+	event.Event.Transaction = fftypes.NewUUID()
+
+	assert.Nil(t, k.recordKey(KeyFieldTopic, event))
+	assert.Nil(t, k.recordKey(KeyFieldNone, event))
+	assert.Equal(t, "message_confirmed", k.recordKey(KeyFieldType, event).Data)
+	assert.Equal(t, event.Reference.String(), k.recordKey(KeyFieldReference, event).Data)
+	assert.Equal(t, event.Event.Transaction.String(), k.recordKey(KeyFieldTransaction, event).Data)
+}
+
+func TestDeliveryRequestPublishRejected(t *testing.T) {
+	broker := newTestBroker()
+	defer broker.server.Close()
+	broker.failing["ff-events"] = 404
+	k, cbs, cancel := newTestKafka(t, broker.server.URL)
+	defer cancel()
+
+	sub := newTestSubscription(fftypes.JSONObject{"topic": "ff-events"})
+	event := newTestEvent(sub, "widgets")
+
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected && r.ID.Equals(event.ID)
+	})).Run(func(args mock.Arguments) {
+		assert.Regexp(t, "FF10457.*404.*topic unavailable", args[1].(*core.EventDeliveryResponse).Info)
+	})
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	cbs.AssertExpectations(t)
+}
+
+func TestDeliveryRequestRecordErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(res).Encode(&kafkaProduceResponse{ErrorCode: 500, Message: "not enough replicas"})
+	}))
+	defer server.Close()
+	k, cbs, cancel := newTestKafka(t, server.URL)
+	defer cancel()
+
+	sub := newTestSubscription(fftypes.JSONObject{"topic": "ff-events"})
+	event := newTestEvent(sub, "widgets")
+
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Run(func(args mock.Arguments) {
+		assert.Regexp(t, "FF10457.*500.*not enough replicas", args[1].(*core.EventDeliveryResponse).Info)
+	})
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	cbs.AssertExpectations(t)
+}
+
+func TestDeliveryRequestConnectionFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+	server.Close()
+	k, cbs, cancel := newTestKafka(t, server.URL)
+	defer cancel()
+
+	sub := newTestSubscription(fftypes.JSONObject{"topic": "ff-events"})
+	event := newTestEvent(sub, "widgets")
+
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Run(func(args mock.Arguments) {
+		assert.Regexp(t, "FF10456", args[1].(*core.EventDeliveryResponse).Info)
+	})
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	cbs.AssertExpectations(t)
+}
+
+func TestDeliveryRequestBadOptions(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	sub := newTestSubscription(fftypes.JSONObject{})
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, newTestEvent(sub, "widgets"), nil)
+	assert.Regexp(t, "FF10452", err)
+}
+
+func TestNamespaceRestarted(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	k.NamespaceRestarted("ns1", time.Now())
+}
+
+func TestBatchDeliveryRequestNotSupported(t *testing.T) {
+	k, _, cancel := newTestKafka(t, "http://localhost:8082")
+	defer cancel()
+
+	assert.False(t, k.Capabilities().BatchDelivery)
+	err := k.BatchDeliveryRequest(context.Background(), k.connID, &core.Subscription{}, []*core.CombinedEventDataDelivery{})
+	assert.Regexp(t, "FF10465", err)
+}
@@ -27,7 +27,19 @@ func (em *eventManager) actionTerminate(location *fftypes.JSONAny, event *blockc
 	return em.multiparty.TerminateContract(em.ctx, location, event)
 }
 
-func (em *eventManager) BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
+func (em *eventManager) actionMigrate(payload string, location *fftypes.JSONAny, event *blockchain.Event) error {
+	return em.multiparty.MigrateContract(em.ctx, location, payload, event)
+}
+
+func (em *eventManager) actionPause(location *fftypes.JSONAny, event *blockchain.Event) error {
+	return em.multiparty.PauseContract(em.ctx, location, event)
+}
+
+func (em *eventManager) actionResume(location *fftypes.JSONAny, event *blockchain.Event) error {
+	return em.multiparty.ResumeContract(em.ctx, location, event)
+}
+
+func (em *eventManager) BlockchainNetworkAction(action, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
 	if em.multiparty == nil {
 		log.L(em.ctx).Errorf("Ignoring network action from non-multiparty network!")
 		return nil
@@ -48,9 +60,16 @@ func (em *eventManager) BlockchainNetworkAction(action string, location *fftypes
 			return false, nil
 		}
 
-		if action == core.NetworkActionTerminate.String() {
+		switch action {
+		case core.NetworkActionTerminate.String():
 			err = em.actionTerminate(location, event)
-		} else {
+		case core.NetworkActionMigrate.String():
+			err = em.actionMigrate(payload, location, event)
+		case core.NetworkActionPause.String():
+			err = em.actionPause(location, event)
+		case core.NetworkActionResume.String():
+			err = em.actionResume(location, event)
+		default:
 			log.L(em.ctx).Errorf("Ignoring unrecognized network action: %s", action)
 			return false, nil
 		}
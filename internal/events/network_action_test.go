@@ -45,7 +45,33 @@ func TestNetworkAction(t *testing.T) {
 	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
 	em.mmp.On("TerminateContract", em.ctx, location, mock.AnythingOfType("*blockchain.Event")).Return(nil)
 
-	err := em.BlockchainNetworkAction("terminate", location, event, verifier)
+	err := em.BlockchainNetworkAction("terminate", "", location, event, verifier)
+	assert.NoError(t, err)
+}
+
+func TestNetworkActionMigratePauseResume(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	location := fftypes.JSONAnyPtr("{}")
+	verifier := &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x1234",
+	}
+	payload := `{"location":{"address":"0x456"}}`
+
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeOrg}, verifier).Return(&core.Identity{}, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
+	em.mmp.On("MigrateContract", em.ctx, location, payload, mock.AnythingOfType("*blockchain.Event")).Return(nil)
+	em.mmp.On("PauseContract", em.ctx, location, mock.AnythingOfType("*blockchain.Event")).Return(nil)
+	em.mmp.On("ResumeContract", em.ctx, location, mock.AnythingOfType("*blockchain.Event")).Return(nil)
+
+	err := em.BlockchainNetworkAction("migrate", payload, location, &blockchain.Event{ProtocolID: "0001"}, verifier)
+	assert.NoError(t, err)
+	err = em.BlockchainNetworkAction("pause", "", location, &blockchain.Event{ProtocolID: "0002"}, verifier)
+	assert.NoError(t, err)
+	err = em.BlockchainNetworkAction("resume", "", location, &blockchain.Event{ProtocolID: "0003"}, verifier)
 	assert.NoError(t, err)
 }
 
@@ -62,7 +88,7 @@ func TestNetworkActionUnknownIdentity(t *testing.T) {
 	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeOrg}, verifier).Return(nil, fmt.Errorf("pop")).Once()
 	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeOrg}, verifier).Return(nil, nil).Once()
 
-	err := em.BlockchainNetworkAction("terminate", location, &blockchain.Event{}, verifier)
+	err := em.BlockchainNetworkAction("terminate", "", location, &blockchain.Event{}, verifier)
 	assert.NoError(t, err)
 }
 
@@ -82,7 +108,7 @@ func TestNetworkActionNonRootIdentity(t *testing.T) {
 		},
 	}, nil)
 
-	err := em.BlockchainNetworkAction("terminate", location, &blockchain.Event{}, verifier)
+	err := em.BlockchainNetworkAction("terminate", "", location, &blockchain.Event{}, verifier)
 	assert.NoError(t, err)
 }
 
@@ -97,7 +123,7 @@ func TestNetworkActionNonMultiparty(t *testing.T) {
 		Value: "0x1234",
 	}
 
-	err := em.BlockchainNetworkAction("terminate", location, &blockchain.Event{}, verifier)
+	err := em.BlockchainNetworkAction("terminate", "", location, &blockchain.Event{}, verifier)
 	assert.NoError(t, err)
 }
 
@@ -113,7 +139,7 @@ func TestNetworkActionUnknown(t *testing.T) {
 
 	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeOrg}, verifier).Return(&core.Identity{}, nil)
 
-	err := em.BlockchainNetworkAction("bad", location, &blockchain.Event{}, verifier)
+	err := em.BlockchainNetworkAction("bad", "", location, &blockchain.Event{}, verifier)
 	assert.NoError(t, err)
 }
 
@@ -212,8 +212,9 @@ func (em *eventManager) validateBatchMessage(ctx context.Context, batch *core.Ba
 	}
 	// Set the state to pending, for the insertion stage
 	msg.State = core.MessageStatePending
-	// Remove any idempotency key
+	// Remove any idempotency key and reply deadline, as these are local to the sender
 	msg.IdempotencyKey = ""
+	msg.ReplyDeadline = nil
 
 	return true
 }
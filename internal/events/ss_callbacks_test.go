@@ -146,7 +146,7 @@ func TestSharedStorageBlobDownloadedOk(t *testing.T) {
 
 	hash := fftypes.NewRandB32()
 	dataID := fftypes.NewUUID()
-	em.SharedStorageBlobDownloaded(mss, *hash, 12345, "payload1", dataID)
+	em.SharedStorageBlobDownloaded(mss, *hash, 12345, "payload1", dataID, nil)
 
 	brw := <-em.aggregator.rewinder.rewindRequests
 	assert.Equal(t, rewind{hash: *hash, rewindType: rewindBlob}, brw)
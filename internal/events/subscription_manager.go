@@ -20,6 +20,7 @@ import (
 	"context"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/events/eventfilter"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -46,6 +48,7 @@ type subscription struct {
 	blockchainFilter   *blockchainFilter
 	transactionFilter  *transactionFilter
 	topicFilter        *regexp.Regexp
+	expression         *eventfilter.Expression
 }
 
 type messageFilter struct {
@@ -261,6 +264,17 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		return nil, err
 	}
 
+	if batch := subDef.Options.Batch; batch != nil {
+		if !transport.Capabilities().BatchDelivery {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBatchDeliveryNotSupported, subDef.Transport)
+		}
+		if batch.Timeout != nil {
+			if _, err := fftypes.ParseDurationString(*batch.Timeout, time.Millisecond); err != nil {
+				return nil, i18n.WrapError(ctx, err, coremsgs.MsgInvalidSubscriptionBatchTimeout, *batch.Timeout)
+			}
+		}
+	}
+
 	var eventFilter *regexp.Regexp
 	if filter.Events != "" {
 		eventFilter, err = regexp.Compile(filter.Events)
@@ -387,6 +401,13 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		sub.transactionFilter = tf
 	}
 
+	if filter.Expression != "" {
+		sub.expression, err = eventfilter.Parse(ctx, filter.Expression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return sub, err
 }
 
@@ -520,25 +541,51 @@ func (sm *subscriptionManager) connectionClosed(ei events.Plugin, connID string)
 	}
 }
 
-func (sm *subscriptionManager) deliveryResponse(ei events.Plugin, connID string, inflight *core.EventDeliveryResponse) {
+func (sm *subscriptionManager) connDispatcher(ei events.Plugin, connID string, subID *fftypes.UUID, callback string) *eventDispatcher {
 	sm.mux.Lock()
+	defer sm.mux.Unlock()
 	var dispatcher *eventDispatcher
 	conn, ok := sm.connections[connID]
-	if ok && inflight.Subscription.ID != nil {
-		dispatcher = conn.dispatchers[*inflight.Subscription.ID]
+	if ok && subID != nil {
+		dispatcher = conn.dispatchers[*subID]
 	}
 	if ok && conn.ei != ei {
 		err := i18n.NewError(sm.ctx, coremsgs.MsgMismatchedTransport, connID, ei.Name(), conn.ei.Name())
-		log.L(sm.ctx).Errorf("Invalid DeliveryResponse callback from plugin: %s", err)
-		sm.mux.Unlock()
-		return
+		log.L(sm.ctx).Errorf("Invalid %s callback from plugin: %s", callback, err)
+		return nil
 	}
 	if dispatcher == nil {
-		err := i18n.NewError(sm.ctx, coremsgs.MsgConnSubscriptionNotStarted, inflight.Subscription.ID)
-		log.L(sm.ctx).Errorf("Invalid DeliveryResponse callback from plugin: %s", err)
-		sm.mux.Unlock()
-		return
+		err := i18n.NewError(sm.ctx, coremsgs.MsgConnSubscriptionNotStarted, subID)
+		log.L(sm.ctx).Errorf("Invalid %s callback from plugin: %s", callback, err)
+		return nil
+	}
+	return dispatcher
+}
+
+func (sm *subscriptionManager) deliveryResponse(ei events.Plugin, connID string, inflight *core.EventDeliveryResponse) {
+	if dispatcher := sm.connDispatcher(ei, connID, inflight.Subscription.ID, "DeliveryResponse"); dispatcher != nil {
+		dispatcher.deliveryResponse(inflight)
+	}
+}
+
+func (sm *subscriptionManager) deadLetter(ei events.Plugin, connID string, deadLetter *core.DeadLetter) {
+	if dispatcher := sm.connDispatcher(ei, connID, deadLetter.Subscription.ID, "DeadLetter"); dispatcher != nil {
+		dispatcher.deadLetter(deadLetter)
+	}
+}
+
+func (sm *subscriptionManager) replayDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	sm.mux.Lock()
+	var dispatcher *eventDispatcher
+	for _, conn := range sm.connections {
+		if d, ok := conn.dispatchers[*deadLetter.Subscription.ID]; ok {
+			dispatcher = d
+			break
+		}
 	}
 	sm.mux.Unlock()
-	dispatcher.deliveryResponse(inflight)
+	if dispatcher == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterSubNotActive, deadLetter.Subscription.ID)
+	}
+	return dispatcher.replayDeadLetter(ctx, deadLetter)
 }
@@ -295,6 +295,63 @@ func TestCreateSubscriptionBadTransportOptions(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestCreateSubscriptionBatchNotSupported(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Transport: "ut",
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Batch: &core.SubscriptionBatchOptions{},
+			},
+		},
+	})
+	assert.Regexp(t, "FF10465.*ut", err)
+}
+
+func TestCreateSubscriptionBatchBadTimeout(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{BatchDelivery: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	timeout := "forever"
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Transport: "ut",
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Batch: &core.SubscriptionBatchOptions{
+					Timeout: &timeout,
+				},
+			},
+		},
+	})
+	assert.Regexp(t, "FF10466.*forever", err)
+}
+
+func TestCreateSubscriptionBatchOk(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{BatchDelivery: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	timeout := "100ms"
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Transport: "ut",
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Batch: &core.SubscriptionBatchOptions{
+					Timeout: &timeout,
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, sub.definition.Options.Batch)
+}
+
 func TestCreateSubscriptionBadEventilter(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -507,6 +564,35 @@ func TestCreateSubscriptionSuccessTxFilter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateSubscriptionBadExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			Expression: "tokenTransfer.amount >",
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10470", err)
+}
+
+func TestCreateSubscriptionSuccessExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			Expression: "tokenTransfer.amount > 1000",
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, sub.expression)
+}
+
 func TestCreateSubscriptionSuccessBlockchainEvent(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -591,6 +677,63 @@ func TestDispatchDeliveryResponseInvalidSubscription(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestDispatchDeadLetterOK(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	err := sm.start()
+	assert.NoError(t, err)
+	be := &boundCallbacks{sm: sm, ei: mei}
+
+	err = be.EphemeralSubscription("conn1", "ns1", &core.SubscriptionFilter{}, &core.SubscriptionOptions{})
+	assert.NoError(t, err)
+
+	var subID *fftypes.UUID
+	for _, d := range sm.connections["conn1"].dispatchers {
+		subID = d.subscription.definition.ID
+	}
+
+	mdi.On("InsertDeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.Subscription.ID.Equals(subID)
+	})).Return(nil)
+	be.DeadLetter("conn1", &core.DeadLetter{
+		Event: fftypes.NewUUID(), // Won't be in-flight, but that's fine
+		Subscription: core.SubscriptionRef{
+			ID: subID,
+		},
+	})
+
+	// Replay is routed to the same dispatcher
+	eventID := fftypes.NewUUID()
+	mdi.On("GetEventByID", mock.Anything, "ns1", eventID).Return(nil, fmt.Errorf("pop"))
+	err = sm.replayDeadLetter(sm.ctx, &core.DeadLetter{
+		Event: eventID,
+		Subscription: core.SubscriptionRef{
+			ID: subID,
+		},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchDeadLetterInvalidSubscription(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	be := &boundCallbacks{sm: sm, ei: mei}
+
+	be.DeadLetter("conn1", &core.DeadLetter{
+		Event: fftypes.NewUUID(),
+		Subscription: core.SubscriptionRef{
+			ID: fftypes.NewUUID(),
+		},
+	})
+}
+
 func TestConnIDSafetyChecking(t *testing.T) {
 	mei1 := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei1)
@@ -73,7 +73,7 @@ func TestDeliveryRequestOk(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	err = se.DeliveryRequest(se.connID, sub, &core.EventDelivery{
+	err = se.DeliveryRequest(context.Background(), se.connID, sub, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{
 				Namespace: "ns1",
@@ -82,7 +82,7 @@ func TestDeliveryRequestOk(t *testing.T) {
 	}, nil)
 	assert.NoError(t, err)
 
-	err = se.DeliveryRequest(se.connID, &core.Subscription{}, &core.EventDelivery{
+	err = se.DeliveryRequest(context.Background(), se.connID, &core.Subscription{}, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{
 				Namespace: "ns2",
@@ -112,7 +112,7 @@ func TestDeliveryRequestFail(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	err = se.DeliveryRequest(mock.Anything, &core.Subscription{}, &core.EventDelivery{
+	err = se.DeliveryRequest(context.Background(), mock.Anything, &core.Subscription{}, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{
 				Namespace: "ns1",
@@ -142,3 +142,12 @@ func TestNamespaceRestarted(t *testing.T) {
 
 	se.NamespaceRestarted("ns1", time.Now())
 }
+
+func TestBatchDeliveryRequestNotSupported(t *testing.T) {
+	se, cancel := newTestEvents(t)
+	defer cancel()
+
+	assert.False(t, se.Capabilities().BatchDelivery)
+	err := se.BatchDeliveryRequest(context.Background(), se.connID, &core.Subscription{}, []*core.CombinedEventDataDelivery{})
+	assert.Regexp(t, "FF10465", err)
+}
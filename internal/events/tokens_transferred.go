@@ -35,30 +35,83 @@ import (
 //     allowed to trigger side-effects in other pools, but only the event from the targeted pool should use the original LocalID.
 //   - The LocalID must not have been used yet. Connectors are allowed to emit multiple events in response to a single operation,
 //     but only the first of them can use the original LocalID.
-func (em *eventManager) loadTransferID(ctx context.Context, tx *fftypes.UUID, transfer *core.TokenTransfer) (*fftypes.UUID, error) {
-	op, err := em.txHelper.FindOperationInTransaction(ctx, tx, core.OpTypeTokenTransfer)
-	if err != nil {
-		return nil, err
-	}
-	if op != nil {
-		// This transfer matches a transfer transaction+operation submitted by this node.
-		// Check the operation inputs to see if they match the connector and pool on this event.
-		if input, err := txcommon.RetrieveTokenTransferInputs(ctx, op); err != nil {
-			log.L(ctx).Warnf("Failed to read operation inputs for token transfer '%s': %s", transfer.ProtocolID, err)
-		} else if input != nil && input.Connector == transfer.Connector && input.Pool.Equals(transfer.Pool) {
-			// Check if the LocalID has already been used
-			if existing, err := em.database.GetTokenTransferByID(ctx, em.namespace.Name, input.LocalID); err != nil {
+//
+// When the connector echoes back the operation that submitted the transfer, only that operation is checked. Otherwise
+// (for events from older connectors) the operations of the transaction are searched, preferring the operation whose
+// accounts and amount match the event, and falling back to the first operation in the same pool whose LocalID has not been used.
+func (em *eventManager) loadTransferID(ctx context.Context, tx *fftypes.UUID, transfer *tokens.TokenTransfer) (*fftypes.UUID, error) {
+	if transfer.Operation != nil {
+		op, err := em.database.GetOperationByID(ctx, em.namespace.Name, transfer.Operation)
+		if err != nil {
+			return nil, err
+		}
+		if op != nil && op.Transaction.Equals(tx) && op.Type == core.OpTypeTokenTransfer {
+			if input, err := em.unusedTransferInput(ctx, op, &transfer.TokenTransfer); err != nil {
 				return nil, err
-			} else if existing == nil {
-				// Everything matches - use the LocalID that was assigned up-front when the operation was submitted
+			} else if input != nil {
 				return input.LocalID, nil
 			}
 		}
+		return fftypes.NewUUID(), nil
+	}
+
+	ops, err := em.txHelper.FindOperationsInTransaction(ctx, tx, core.OpTypeTokenTransfer)
+	if err != nil {
+		return nil, err
+	}
+	var fallback *fftypes.UUID
+	for _, op := range ops {
+		input, err := em.unusedTransferInput(ctx, op, &transfer.TokenTransfer)
+		if err != nil {
+			return nil, err
+		}
+		if input == nil {
+			continue
+		}
+		if len(ops) == 1 || transferDetailsMatch(input, &transfer.TokenTransfer) {
+			// Everything matches - use the LocalID that was assigned up-front when the operation was submitted
+			return input.LocalID, nil
+		}
+		if fallback == nil {
+			fallback = input.LocalID
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
 	}
 
 	return fftypes.NewUUID(), nil
 }
 
+// unusedTransferInput returns the inputs of an operation, if it targeted the connector and pool of the transfer
+// and its LocalID has not been used
+func (em *eventManager) unusedTransferInput(ctx context.Context, op *core.Operation, transfer *core.TokenTransfer) (*core.TokenTransfer, error) {
+	// Check the operation inputs to see if they match the connector and pool on this event.
+	input, err := txcommon.RetrieveTokenTransferInputs(ctx, op)
+	if err != nil {
+		log.L(ctx).Warnf("Failed to read operation inputs for token transfer '%s': %s", transfer.ProtocolID, err)
+		return nil, nil
+	}
+	if input.Connector != transfer.Connector || !input.Pool.Equals(transfer.Pool) {
+		return nil, nil
+	}
+	// Check if the LocalID has already been used
+	if existing, err := em.database.GetTokenTransferByID(ctx, em.namespace.Name, input.LocalID); err != nil || existing != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+func transferDetailsMatch(input, transfer *core.TokenTransfer) bool {
+	if input.TokenIndex != transfer.TokenIndex || input.Amount.Int().Cmp(transfer.Amount.Int()) != 0 {
+		return false
+	}
+	if transfer.Type != core.TokenTransferTypeMint && input.From != transfer.From {
+		return false
+	}
+	return transfer.Type == core.TokenTransferTypeBurn || input.To == transfer.To
+}
+
 func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *tokens.TokenTransfer) (valid bool, err error) {
 	// Check that this is from a known pool
 	pool, err := em.getPoolByIDOrLocator(ctx, transfer.Pool, transfer.Connector, transfer.PoolLocator)
@@ -83,7 +136,7 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 	if transfer.TX.ID == nil {
 		transfer.LocalID = fftypes.NewUUID()
 	} else {
-		if transfer.LocalID, err = em.loadTransferID(ctx, transfer.TX.ID, &transfer.TokenTransfer); err != nil {
+		if transfer.LocalID, err = em.loadTransferID(ctx, transfer.TX.ID, transfer); err != nil {
 			return false, err
 		}
 		if valid, err := em.txHelper.PersistTransaction(ctx, transfer.TX.ID, transfer.TX.Type, transfer.Event.BlockchainTXID); err != nil || !valid {
@@ -106,7 +159,7 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 		log.L(ctx).Errorf("Failed to record token transfer '%s': %s", transfer.ProtocolID, err)
 		return false, err
 	}
-	if err := em.database.UpdateTokenBalances(ctx, &transfer.TokenTransfer); err != nil {
+	if err := em.database.UpdateTokenBalances(ctx, &transfer.TokenTransfer, chainEvent.Timestamp); err != nil {
 		log.L(ctx).Errorf("Failed to update accounts %s -> %s for token transfer '%s': %s", transfer.From, transfer.To, transfer.ProtocolID, err)
 		return false, err
 	}
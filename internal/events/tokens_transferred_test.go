@@ -82,8 +82,8 @@ func TestTokensTransferredSucceedWithRetries(t *testing.T) {
 	})).Return(nil).Times(3)
 	em.mdi.On("UpsertTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(fmt.Errorf("pop")).Once()
 	em.mdi.On("UpsertTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
-	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(fmt.Errorf("pop")).Once()
-	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Once()
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer, mock.Anything).Return(nil).Once()
 	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
 		return ev.Type == core.EventTypeTransferConfirmed && ev.Reference == transfer.LocalID && ev.Namespace == pool.Namespace
 	})).Return(nil).Once()
@@ -127,7 +127,7 @@ func TestPersistTransferOpFail(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, fmt.Errorf("pop"))
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, fmt.Errorf("pop"))
 
 	valid, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
@@ -153,7 +153,7 @@ func TestPersistTransferBadOp(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op}, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(false, fmt.Errorf("pop"))
 
 	valid, err := em.persistTokenTransfer(em.ctx, transfer)
@@ -180,7 +180,7 @@ func TestPersistTransferTxFail(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op}, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(false, fmt.Errorf("pop"))
 
 	valid, err := em.persistTokenTransfer(em.ctx, transfer)
@@ -209,7 +209,7 @@ func TestPersistTransferGetTransferFail(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op}, nil)
 	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(nil, fmt.Errorf("pop"))
 
 	valid, err := em.persistTokenTransfer(em.ctx, transfer)
@@ -238,7 +238,7 @@ func TestPersistTransferBlockchainEventFail(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op}, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(true, nil)
 	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(nil, nil)
 	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.MatchedBy(func(e *core.BlockchainEvent) bool {
@@ -273,7 +273,7 @@ func TestTokensTransferredWithTransactionRegenerateLocalID(t *testing.T) {
 
 	em.mdi.On("GetTokenTransferByProtocolID", em.ctx, "ns1", pool.ID, "123").Return(nil, nil)
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
-	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op}, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(true, nil)
 	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(&core.TokenTransfer{}, nil)
 	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.MatchedBy(func(e *core.BlockchainEvent) bool {
@@ -283,7 +283,7 @@ func TestTokensTransferredWithTransactionRegenerateLocalID(t *testing.T) {
 		return ev.Type == core.EventTypeBlockchainEventReceived && ev.Namespace == pool.Namespace
 	})).Return(nil)
 	em.mdi.On("UpsertTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil)
-	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer, mock.Anything).Return(nil)
 
 	valid, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.True(t, valid)
@@ -356,7 +356,7 @@ func TestTokensTransferredWithMessageReceived(t *testing.T) {
 		return ev.Type == core.EventTypeBlockchainEventReceived && ev.Namespace == pool.Namespace
 	})).Return(nil).Times(2)
 	em.mdi.On("UpsertTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
-	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer, mock.Anything).Return(nil).Times(2)
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", transfer.Message).Return(nil, fmt.Errorf("pop")).Once()
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", transfer.Message).Return(message, nil).Once()
 	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
@@ -416,7 +416,7 @@ func TestTokensTransferredWithMessageSend(t *testing.T) {
 		return ev.Type == core.EventTypeBlockchainEventReceived && ev.Namespace == pool.Namespace
 	})).Return(nil).Times(2)
 	em.mdi.On("UpsertTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
-	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer, mock.Anything).Return(nil).Times(2)
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", mock.Anything).Return(message, nil).Times(2)
 	em.mdi.On("ReplaceMessage", em.ctx, mock.MatchedBy(func(msg *core.Message) bool {
 		return msg.State == core.MessageStateReady
@@ -430,3 +430,181 @@ func TestTokensTransferredWithMessageSend(t *testing.T) {
 
 	mti.AssertExpectations(t)
 }
+
+func TestLoadTransferIDBatch(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	newOp := func(pool *fftypes.UUID, amount string) (*core.Operation, *fftypes.UUID) {
+		localID := fftypes.NewUUID()
+		return &core.Operation{
+			Input: fftypes.JSONObject{
+				"localId":    localID.String(),
+				"connector":  transfer.Connector,
+				"pool":       pool.String(),
+				"tokenIndex": "0",
+				"from":       "0x1",
+				"to":         "0x2",
+				"amount":     amount,
+			},
+		}, localID
+	}
+	op1, _ := newOp(fftypes.NewUUID(), "1")
+	op2, localID2 := newOp(transfer.Pool, "2")
+	op3, localID3 := newOp(transfer.Pool, "1")
+
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return([]*core.Operation{op1, op2, op3}, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID2).Return(nil, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID3).Return(nil, nil)
+
+	localID, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, localID3, localID)
+}
+
+func TestLoadTransferIDBatchFallback(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	localID1 := fftypes.NewUUID()
+	localID2 := fftypes.NewUUID()
+	ops := []*core.Operation{
+		{Input: fftypes.JSONObject{"localId": localID1.String(), "connector": transfer.Connector, "pool": transfer.Pool.String(), "amount": "1"}},
+		{Input: fftypes.JSONObject{"localId": localID2.String(), "connector": transfer.Connector, "pool": transfer.Pool.String(), "amount": "5"}},
+	}
+
+	em.mth.On("FindOperationsInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(ops, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID1).Return(&core.TokenTransfer{}, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID2).Return(nil, nil)
+
+	localID, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, localID2, localID)
+}
+
+func TestLoadTransferIDByOperation(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	transfer.Operation = fftypes.NewUUID()
+	localID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          transfer.Operation,
+		Type:        core.OpTypeTokenTransfer,
+		Transaction: transfer.TX.ID,
+		Input: fftypes.JSONObject{
+			"localId":   localID.String(),
+			"connector": transfer.Connector,
+			"pool":      transfer.Pool.String(),
+		},
+	}
+
+	em.mdi.On("GetOperationByID", em.ctx, "ns1", transfer.Operation).Return(op, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(nil, nil)
+
+	result, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, localID, result)
+}
+
+func TestLoadTransferIDByOperationUsed(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	transfer.Operation = fftypes.NewUUID()
+	localID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          transfer.Operation,
+		Type:        core.OpTypeTokenTransfer,
+		Transaction: transfer.TX.ID,
+		Input: fftypes.JSONObject{
+			"localId":   localID.String(),
+			"connector": transfer.Connector,
+			"pool":      transfer.Pool.String(),
+		},
+	}
+
+	em.mdi.On("GetOperationByID", em.ctx, "ns1", transfer.Operation).Return(op, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(&core.TokenTransfer{}, nil)
+
+	result, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.NoError(t, err)
+	assert.NotEqual(t, localID, result)
+}
+
+func TestLoadTransferIDByOperationOtherTX(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Operation = fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          transfer.Operation,
+		Type:        core.OpTypeTokenTransfer,
+		Transaction: fftypes.NewUUID(),
+	}
+
+	em.mdi.On("GetOperationByID", em.ctx, "ns1", transfer.Operation).Return(op, nil)
+
+	result, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestLoadTransferIDByOperationFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Operation = fftypes.NewUUID()
+
+	em.mdi.On("GetOperationByID", em.ctx, "ns1", transfer.Operation).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestLoadTransferIDByOperationGetTransferFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	transfer.Operation = fftypes.NewUUID()
+	localID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          transfer.Operation,
+		Type:        core.OpTypeTokenTransfer,
+		Transaction: transfer.TX.ID,
+		Input: fftypes.JSONObject{
+			"localId":   localID.String(),
+			"connector": transfer.Connector,
+			"pool":      transfer.Pool.String(),
+		},
+	}
+
+	em.mdi.On("GetOperationByID", em.ctx, "ns1", transfer.Operation).Return(op, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.loadTransferID(em.ctx, transfer.TX.ID, transfer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTransferDetailsMatch(t *testing.T) {
+	input := &core.TokenTransfer{TokenIndex: "1", From: "0x1", To: "0x2", Amount: *fftypes.NewFFBigInt(10)}
+
+	assert.True(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeTransfer, TokenIndex: "1", From: "0x1", To: "0x2", Amount: *fftypes.NewFFBigInt(10)}))
+	assert.True(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeMint, TokenIndex: "1", To: "0x2", Amount: *fftypes.NewFFBigInt(10)}))
+	assert.True(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeBurn, TokenIndex: "1", From: "0x1", Amount: *fftypes.NewFFBigInt(10)}))
+	assert.False(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeTransfer, TokenIndex: "1", From: "0x1", To: "0x3", Amount: *fftypes.NewFFBigInt(10)}))
+	assert.False(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeBurn, TokenIndex: "1", From: "0x3", Amount: *fftypes.NewFFBigInt(10)}))
+	assert.False(t, transferDetailsMatch(input, &core.TokenTransfer{Type: core.TokenTransferTypeTransfer, TokenIndex: "2", From: "0x1", To: "0x2", Amount: *fftypes.NewFFBigInt(10)}))
+}
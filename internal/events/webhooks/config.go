@@ -19,8 +19,42 @@ package webhooks
 import (
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	// WebhooksConfTLSConfigs is a list of named TLS configurations, that subscriptions can choose between
+	WebhooksConfTLSConfigs = "tlsConfigs"
+	// WebhooksConfTLSConfigName is the name a subscription uses to refer to the TLS configuration
+	WebhooksConfTLSConfigName = "name"
+	// WebhooksConfTLSConfigTLS is the TLS configuration, including any client certificate for mutual TLS
+	WebhooksConfTLSConfigTLS = "tls"
+	// WebhooksConfSigningSecrets is a list of named HMAC secrets, that subscriptions can choose between to sign requests
+	WebhooksConfSigningSecrets = "signingSecrets"
+	// WebhooksConfSigningSecretName is the name a subscription uses to refer to the signing secret
+	WebhooksConfSigningSecretName = "name"
+	// WebhooksConfSigningSecretSecret is the shared secret used as the HMAC key
+	WebhooksConfSigningSecretSecret = "secret"
 )
 
 func (wh *WebHooks) InitConfig(config config.Section) {
 	ffresty.InitConfig(config)
+	initTLSConfigsArray(config)
+	initSigningSecretsArray(config)
+}
+
+// The known keys of array entries are held on the array section, so this is used
+// both at config initialization and when reading the entries
+func initTLSConfigsArray(config config.Section) config.ArraySection {
+	tlsConfigs := config.SubArray(WebhooksConfTLSConfigs)
+	tlsConfigs.AddKnownKey(WebhooksConfTLSConfigName)
+	fftls.InitTLSConfig(tlsConfigs.SubSection(WebhooksConfTLSConfigTLS))
+	return tlsConfigs
+}
+
+func initSigningSecretsArray(config config.Section) config.ArraySection {
+	signingSecrets := config.SubArray(WebhooksConfSigningSecrets)
+	signingSecrets.AddKnownKey(WebhooksConfSigningSecretName)
+	signingSecrets.AddKnownKey(WebhooksConfSigningSecretSecret)
+	return signingSecrets
 }
@@ -19,8 +19,14 @@ package webhooks
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
@@ -31,9 +37,11 @@ import (
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
@@ -44,6 +52,8 @@ type WebHooks struct {
 	capabilities *events.Capabilities
 	callbacks    callbacks
 	client       *resty.Client
+	tlsClients   map[string]*resty.Client
+	secrets      map[string][]byte
 	connID       string
 }
 
@@ -59,6 +69,19 @@ type whRequest struct {
 	body      fftypes.JSONObject
 	forceJSON bool
 	replyTx   string
+	signing   *signingOptions
+}
+
+type signingOptions struct {
+	secret []byte
+	header string
+	hash   func() hash.Hash
+}
+
+// whBatchEvent is an entry in the array sent for a batch, including the data if the subscription delivers data
+type whBatchEvent struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
 }
 
 type whResponse struct {
@@ -67,6 +90,22 @@ type whResponse struct {
 	Body    *fftypes.JSONAny   `json:"body"`
 }
 
+// Defaults for the retry policy of a subscription, when retry is enabled
+const (
+	defaultRetryMaxAttempts  = 5
+	defaultRetryInitialDelay = 250 * time.Millisecond
+	defaultRetryMaximumDelay = 30 * time.Second
+	defaultRetryFactor       = 2.0
+
+	defaultSigningHeader = "X-FireFly-Signature"
+)
+
+type retryPolicy struct {
+	maxAttempts int
+	retry       *retry.Retry
+	statusCodes map[int]bool
+}
+
 func (wh *WebHooks) Name() string { return "webhooks" }
 
 func (wh *WebHooks) Init(ctx context.Context, config config.Section) (err error) {
@@ -77,13 +116,58 @@ func (wh *WebHooks) Init(ctx context.Context, config config.Section) (err error)
 		return err
 	}
 	*wh = WebHooks{
-		ctx:          log.WithLogField(ctx, "webhook", wh.connID),
-		capabilities: &events.Capabilities{},
+		ctx: log.WithLogField(ctx, "webhook", wh.connID),
+		capabilities: &events.Capabilities{
+			BatchDelivery: true,
+		},
 		callbacks: callbacks{
 			handlers: make(map[string]events.Callbacks),
 		},
-		client: client,
-		connID: connID,
+		client:     client,
+		tlsClients: make(map[string]*resty.Client),
+		secrets:    make(map[string][]byte),
+		connID:     connID,
+	}
+
+	// Each named TLS config gets its own client, as the TLS config is bound to the transport
+	tlsConfigs := initTLSConfigsArray(config)
+	tlsConfigsSize := tlsConfigs.ArraySize()
+	for i := 0; i < tlsConfigsSize; i++ {
+		entry := tlsConfigs.ArrayEntry(i)
+		name := entry.GetString(WebhooksConfTLSConfigName)
+		if name == "" {
+			return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, WebhooksConfTLSConfigName, "events.webhooks.tlsConfigs")
+		}
+		if _, exists := wh.tlsClients[name]; exists {
+			return i18n.NewError(ctx, coremsgs.MsgWebhookTLSConfigDuplicate, name)
+		}
+		tlsConfig, err := fftls.ConstructTLSConfig(ctx, entry.SubSection(WebhooksConfTLSConfigTLS), fftls.ClientType)
+		if err != nil {
+			return err
+		}
+		// The plugin config was already validated constructing the default client
+		tlsClient, _ := ffresty.New(ctx, config)
+		wh.tlsClients[name] = tlsClient.SetTLSClientConfig(tlsConfig)
+	}
+
+	// Signing secrets are held in the node config, and only referenced by name from subscriptions,
+	// so they are never stored with (or returned on) the subscription
+	signingSecrets := initSigningSecretsArray(config)
+	signingSecretsSize := signingSecrets.ArraySize()
+	for i := 0; i < signingSecretsSize; i++ {
+		entry := signingSecrets.ArrayEntry(i)
+		name := entry.GetString(WebhooksConfSigningSecretName)
+		if name == "" {
+			return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, WebhooksConfSigningSecretName, "events.webhooks.signingSecrets")
+		}
+		secret := entry.GetString(WebhooksConfSigningSecretSecret)
+		if secret == "" {
+			return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, WebhooksConfSigningSecretSecret, "events.webhooks.signingSecrets")
+		}
+		if _, exists := wh.secrets[name]; exists {
+			return i18n.NewError(ctx, coremsgs.MsgWebhookSigningSecretDuplicate, name)
+		}
+		wh.secrets[name] = []byte(secret)
 	}
 	return nil
 }
@@ -104,15 +188,77 @@ func (wh *WebHooks) Capabilities() *events.Capabilities {
 	return wh.capabilities
 }
 
-func (wh *WebHooks) buildRequest(options fftypes.JSONObject, firstData fftypes.JSONObject) (req *whRequest, err error) {
+func (wh *WebHooks) getClient(options fftypes.JSONObject) (*resty.Client, error) {
+	tlsConfigName := options.GetString("tlsConfigName")
+	if tlsConfigName == "" {
+		return wh.client, nil
+	}
+	client, ok := wh.tlsClients[tlsConfigName]
+	if !ok {
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookTLSConfigNotFound, tlsConfigName)
+	}
+	return client, nil
+}
+
+func (wh *WebHooks) parseSigningOptions(options fftypes.JSONObject) (*signingOptions, error) {
+	signing := options.GetObject("signing")
+	if _, inline := signing["secret"]; inline {
+		// Anything in the options is stored, and returned, with the subscription
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInlineSigningSecret)
+	}
+	secretName := signing.GetString("secretName")
+	if secretName == "" {
+		return nil, nil
+	}
+	secret, ok := wh.secrets[secretName]
+	if !ok {
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookSigningSecretNotFound, secretName)
+	}
+	so := &signingOptions{
+		secret: secret,
+		header: signing.GetString("header"),
+	}
+	if so.header == "" {
+		so.header = defaultSigningHeader
+	}
+	switch algorithm := strings.ToLower(signing.GetString("algorithm")); algorithm {
+	case "", "sha256":
+		so.hash = sha256.New
+	case "sha512":
+		so.hash = sha512.New
+	default:
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidSigningAlgorithm, algorithm)
+	}
+	return so, nil
+}
+
+// signature generates the value of the signature header, as an HMAC over the timestamp and the exact body bytes sent.
+// Including the timestamp allows the receiver to reject replays of old requests.
+func (so *signingOptions) signature(timestamp int64, body []byte) string {
+	mac := hmac.New(so.hash, so.secret)
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func (wh *WebHooks) buildRequest(ctx context.Context, options fftypes.JSONObject, firstData fftypes.JSONObject) (req *whRequest, err error) {
+	client, err := wh.getClient(options)
+	if err != nil {
+		return nil, err
+	}
+	signing, err := wh.parseSigningOptions(options)
+	if err != nil {
+		return nil, err
+	}
 	req = &whRequest{
-		r: wh.client.R().
+		r: client.R().
 			SetDoNotParseResponse(true).
-			SetContext(wh.ctx),
+			SetContext(ctx),
 		url:       options.GetString("url"),
 		method:    options.GetString("method"),
 		forceJSON: options.GetBool("json"),
 		replyTx:   options.GetString("replytx"),
+		signing:   signing,
 	}
 	if req.url == "" {
 		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookURLEmpty)
@@ -190,16 +336,92 @@ func (wh *WebHooks) buildRequest(options fftypes.JSONObject, firstData fftypes.J
 	return req, err
 }
 
+func (wh *WebHooks) parseRetryPolicy(options fftypes.JSONObject) (*retryPolicy, error) {
+	retryOptions, ok := options["retry"]
+	if !ok || retryOptions == nil {
+		return nil, nil
+	}
+	var ro core.WebhookRetryOptions
+	b, _ := json.Marshal(retryOptions)
+	if err := json.Unmarshal(b, &ro); err != nil {
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "retry", err)
+	}
+	if !ro.Enabled {
+		return nil, nil
+	}
+
+	rp := &retryPolicy{
+		maxAttempts: defaultRetryMaxAttempts,
+		retry: &retry.Retry{
+			InitialDelay: defaultRetryInitialDelay,
+			MaximumDelay: defaultRetryMaximumDelay,
+			Factor:       defaultRetryFactor,
+		},
+		statusCodes: make(map[int]bool),
+	}
+	if ro.MaxAttempts < 0 {
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "maxAttempts", ro.MaxAttempts)
+	} else if ro.MaxAttempts > 0 {
+		rp.maxAttempts = ro.MaxAttempts
+	}
+	if ro.InitialDelay != "" {
+		d, err := fftypes.ParseDurationString(ro.InitialDelay, time.Millisecond)
+		if err != nil || d < 0 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "initialDelay", ro.InitialDelay)
+		}
+		rp.retry.InitialDelay = time.Duration(d)
+	}
+	if ro.MaximumDelay != "" {
+		d, err := fftypes.ParseDurationString(ro.MaximumDelay, time.Millisecond)
+		if err != nil || d < 0 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "maximumDelay", ro.MaximumDelay)
+		}
+		rp.retry.MaximumDelay = time.Duration(d)
+	}
+	if ro.Factor != 0 {
+		if ro.Factor < 1 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "factor", ro.Factor)
+		}
+		rp.retry.Factor = ro.Factor
+	}
+	for _, sc := range ro.StatusCodes {
+		if sc < 100 || sc > 599 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "statusCodes", sc)
+		}
+		rp.statusCodes[sc] = true
+	}
+	return rp, nil
+}
+
+// retryableError determines if the outcome of an attempt should be retried.
+// Errors connecting to the webhook are always retried. If no status codes are configured, any 5xx status is retried.
+func (rp *retryPolicy) retryableError(ctx context.Context, res *whResponse, gwErr error) error {
+	if gwErr != nil {
+		return gwErr
+	}
+	if rp.statusCodes[res.Status] || (len(rp.statusCodes) == 0 && res.Status >= 500) {
+		return i18n.NewError(ctx, coremsgs.MsgWebhookFailedStatus, res.Status)
+	}
+	return nil
+}
+
 func (wh *WebHooks) ValidateOptions(options *core.SubscriptionOptions) error {
 	if options.WithData == nil {
 		defaultTrue := true
 		options.WithData = &defaultTrue
 	}
-	_, err := wh.buildRequest(options.TransportOptions(), fftypes.JSONObject{})
+	// Replies cannot be correlated to the individual events in a batch
+	if options.Batch != nil && options.TransportOptions().GetBool("reply") {
+		return i18n.NewError(wh.ctx, coremsgs.MsgWebhookBatchReply)
+	}
+	_, err := wh.buildRequest(wh.ctx, options.TransportOptions(), fftypes.JSONObject{})
+	if err == nil {
+		_, err = wh.parseRetryPolicy(options.TransportOptions())
+	}
 	return err
 }
 
-func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (req *whRequest, res *whResponse, err error) {
+func (wh *WebHooks) attemptRequest(ctx context.Context, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (req *whRequest, res *whResponse, err error) {
 	withData := sub.Options.WithData != nil && *sub.Options.WithData
 	allData := make([]*fftypes.JSONAny, 0, len(data))
 	var firstData fftypes.JSONObject
@@ -224,34 +446,55 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		}
 	}
 
-	req, err = wh.buildRequest(sub.Options.TransportOptions(), firstData)
+	req, err = wh.buildRequest(ctx, sub.Options.TransportOptions(), firstData)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	var body interface{}
 	if req.method == http.MethodPost || req.method == http.MethodPatch || req.method == http.MethodPut {
 		switch {
 		case req.body != nil:
 			// We might have been told to extract a body from the first data record
-			req.r.SetBody(req.body)
+			body = req.body
 		case len(allData) > 1:
 			// We've got an array of data to POST
-			req.r.SetBody(allData)
+			body = allData
 		case len(allData) == 1:
 			// Just send the first object directly
-			req.r.SetBody(firstData)
+			body = firstData
 		default:
 			// Just send the event itself
-			req.r.SetBody(event)
+			body = event
+		}
+	}
+	res, err = wh.sendRequest(ctx, req, body, sub, fmt.Sprintf("event %s", event.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, res, nil
+}
 
+// sendRequest serializes and signs the body, then sends the request and processes the response
+func (wh *WebHooks) sendRequest(ctx context.Context, req *whRequest, body interface{}, sub *core.Subscription, description string) (res *whResponse, err error) {
+	var bodyBytes []byte
+	if body != nil {
+		// We serialize the body ourselves, so the signature is over exactly the bytes we send
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
 		}
+		req.r.SetBody(bodyBytes)
+	}
+	if req.signing != nil {
+		req.r.SetHeader(req.signing.header, req.signing.signature(time.Now().Unix(), bodyBytes))
 	}
 
-	log.L(wh.ctx).Debugf("Webhook-> %s %s event %s on subscription %s", req.method, req.url, event.ID, sub.ID)
+	log.L(ctx).Debugf("Webhook-> %s %s %s on subscription %s", req.method, req.url, description, sub.ID)
 	resp, err := req.r.Execute(req.method, req.url)
 	if err != nil {
-		log.L(wh.ctx).Errorf("Webhook<- %s %s event %s on subscription %s failed: %s", req.method, req.url, event.ID, sub.ID, err)
-		return nil, nil, err
+		log.L(ctx).Errorf("Webhook<- %s %s %s on subscription %s failed: %s", req.method, req.url, description, sub.ID, err)
+		return nil, err
 	}
 	defer func() { _ = resp.RawBody().Close() }()
 
@@ -259,7 +502,7 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		Status:  resp.StatusCode(),
 		Headers: fftypes.JSONObject{},
 	}
-	log.L(wh.ctx).Infof("Webhook<- %s %s event %s on subscription %s returned %d", req.method, req.url, event.ID, sub.ID, res.Status)
+	log.L(wh.ctx).Infof("Webhook<- %s %s %s on subscription %s returned %d", req.method, req.url, description, sub.ID, res.Status)
 	header := resp.Header()
 	for h := range header {
 		res.Headers[h] = header.Get(h)
@@ -274,7 +517,7 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		var resData interface{}
 		err = json.NewDecoder(resp.RawBody()).Decode(&resData)
 		if err != nil {
-			return nil, nil, i18n.WrapError(wh.ctx, err, coremsgs.MsgWebhooksReplyBadJSON)
+			return nil, i18n.WrapError(wh.ctx, err, coremsgs.MsgWebhooksReplyBadJSON)
 		}
 		b, _ := json.Marshal(&resData) // we know we can re-marshal It
 		res.Body = fftypes.JSONAnyPtrBytes(b)
@@ -289,11 +532,59 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		res.Body = fftypes.JSONAnyPtrBytes(buf.Bytes())
 	}
 
-	return req, res, nil
+	return res, nil
+}
+
+// attemptWithRetry makes repeated attempts at a delivery, according to the retry policy.
+// If all the attempts fail, the number of attempts and the final error are returned.
+// Retrying stops as soon as the context of the delivery is cancelled.
+func (wh *WebHooks) attemptWithRetry(ctx context.Context, rp *retryPolicy, attemptFn func() (*whRequest, *whResponse, error)) (req *whRequest, res *whResponse, gwErr error, attempts int, err error) {
+	err = rp.retry.Do(ctx, "webhook delivery", func(attempt int) (retry bool, err error) {
+		attempts = attempt
+		req, res, gwErr = attemptFn()
+		return attempt < rp.maxAttempts, rp.retryableError(ctx, res, gwErr)
+	})
+	return req, res, gwErr, attempts, err
+}
+
+func (wh *WebHooks) deadLetter(connID string, sub *core.Subscription, event *core.EventDelivery, attempts int, err error) {
+	if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
+		cb.DeadLetter(connID, &core.DeadLetter{
+			Subscription: event.Subscription,
+			Event:        event.ID,
+			Attempts:     attempts,
+			Error:        err.Error(),
+		})
+	}
 }
 
-func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription, event *core.EventDelivery, data core.DataArray, fastAck bool) {
-	req, res, gwErr := wh.attemptRequest(sub, event, data)
+func (wh *WebHooks) doDelivery(ctx context.Context, connID string, reply bool, sub *core.Subscription, event *core.EventDelivery, data core.DataArray, fastAck bool) {
+	var req *whRequest
+	var res *whResponse
+	var gwErr error
+	rp, err := wh.parseRetryPolicy(sub.Options.TransportOptions())
+	if err != nil {
+		log.L(wh.ctx).Errorf("Invalid retry policy on subscription %s: %s", sub.ID, err)
+	}
+	if rp == nil {
+		req, res, gwErr = wh.attemptRequest(ctx, sub, event, data)
+	} else {
+		var attempts int
+		req, res, gwErr, attempts, err = wh.attemptWithRetry(ctx, rp, func() (*whRequest, *whResponse, error) {
+			return wh.attemptRequest(ctx, sub, event, data)
+		})
+		if err != nil {
+			if ctx.Err() != nil && !fastAck {
+				// The dispatcher has closed (and will redeliver the unacknowledged event when it restarts),
+				// or the replay of a dead letter has timed out (and the dead letter is kept)
+				log.L(ctx).Infof("Stopped retrying delivery of event %s on subscription %s: %s", event.ID, sub.ID, err)
+				return
+			}
+			// We have exhausted our retries, so hand the event over to the dead letter queue
+			wh.deadLetter(connID, sub, event, attempts, err)
+			return
+		}
+	}
 	if gwErr != nil {
 		// Generate a bad-gateway error response - we always want to send something back,
 		// rather than just causing timeouts
@@ -312,6 +603,24 @@ func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription
 	b, _ := json.Marshal(&res)
 	log.L(wh.ctx).Tracef("Webhook response: %s", string(b))
 
+	// A replay of a dead letter is only acknowledged if the webhook accepted it, as the dead letter is
+	// deleted on acknowledgement. Any reply is sent once a later replay succeeds.
+	if event.Replay && (gwErr != nil || res.Status < 200 || res.Status >= 300) {
+		info := i18n.NewError(ctx, coremsgs.MsgWebhookFailedStatus, res.Status).Error()
+		if gwErr != nil {
+			info = gwErr.Error()
+		}
+		if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
+			cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+				ID:           event.ID,
+				Rejected:     true,
+				Info:         info,
+				Subscription: event.Subscription,
+			})
+		}
+		return
+	}
+
 	// Emit the response
 	if reply && event.Message != nil {
 		txType := fftypes.FFEnum(strings.ToLower(sub.Options.TransportOptions().GetString("replytx")))
@@ -352,7 +661,7 @@ func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription
 	}
 }
 
-func (wh *WebHooks) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+func (wh *WebHooks) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
 	reply := sub.Options.TransportOptions().GetBool("reply")
 	if reply && event.Message != nil && event.Message.Header.CID != nil {
 		// We cowardly refuse to dispatch a message that is itself a reply, as it's hard for users to
@@ -372,7 +681,8 @@ func (wh *WebHooks) DeliveryRequest(connID string, sub *core.Subscription, event
 	// In fastack mode we drive calls in parallel to the backend, immediately acknowledging the event
 	// NOTE: We cannot use this with reply mode, as when we're sending a reply the `DeliveryResponse`
 	//       callback must include the reply in-line.
-	if !reply && sub.Options.TransportOptions().GetBool("fastack") {
+	//       Nor can we use it for the replay of a dead letter, which is only acknowledged once delivered.
+	if !reply && !event.Replay && sub.Options.TransportOptions().GetBool("fastack") {
 		if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
 			cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
 				ID:           event.ID,
@@ -380,11 +690,99 @@ func (wh *WebHooks) DeliveryRequest(connID string, sub *core.Subscription, event
 				Subscription: event.Subscription,
 			})
 		}
-		go wh.doDelivery(connID, reply, sub, event, data, true)
+		go wh.doDelivery(ctx, connID, reply, sub, event, data, true)
+		return nil
+	}
+
+	wh.doDelivery(ctx, connID, reply, sub, event, data, false)
+	return nil
+}
+
+func (wh *WebHooks) attemptBatchRequest(ctx context.Context, sub *core.Subscription, events []*core.CombinedEventDataDelivery) (req *whRequest, res *whResponse, err error) {
+	// Input processing of the data does not apply to batches, as there is no single first data item
+	req, err = wh.buildRequest(ctx, sub.Options.TransportOptions(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withData := sub.Options.WithData != nil && *sub.Options.WithData
+	var body interface{}
+	if req.method == http.MethodPost || req.method == http.MethodPatch || req.method == http.MethodPut {
+		batch := make([]*whBatchEvent, len(events))
+		for i, e := range events {
+			batch[i] = &whBatchEvent{EventDelivery: e.Event}
+			if withData {
+				batch[i].Data = e.Data
+			}
+		}
+		body = batch
+	}
+	res, err = wh.sendRequest(ctx, req, body, sub, fmt.Sprintf("batch of %d events", len(events)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, res, nil
+}
+
+func (wh *WebHooks) ackBatch(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) {
+	if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
+		for _, e := range events {
+			cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+				ID:           e.Event.ID,
+				Rejected:     false,
+				Subscription: e.Event.Subscription,
+			})
+		}
+	}
+}
+
+func (wh *WebHooks) doBatchDelivery(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery, fastAck bool) {
+	var res *whResponse
+	var gwErr error
+	rp, err := wh.parseRetryPolicy(sub.Options.TransportOptions())
+	if err != nil {
+		log.L(wh.ctx).Errorf("Invalid retry policy on subscription %s: %s", sub.ID, err)
+	}
+	if rp == nil {
+		_, res, gwErr = wh.attemptBatchRequest(ctx, sub, events)
+	} else {
+		var attempts int
+		_, res, gwErr, attempts, err = wh.attemptWithRetry(ctx, rp, func() (*whRequest, *whResponse, error) {
+			return wh.attemptBatchRequest(ctx, sub, events)
+		})
+		if err != nil {
+			if ctx.Err() != nil && !fastAck {
+				// The dispatcher has closed, and will redeliver the unacknowledged batch when it restarts
+				log.L(ctx).Infof("Stopped retrying delivery of batch of %d events on subscription %s: %s", len(events), sub.ID, err)
+				return
+			}
+			// We have exhausted our retries, so every event in the batch goes to the dead letter queue
+			for _, e := range events {
+				wh.deadLetter(connID, sub, e.Event, attempts, err)
+			}
+			return
+		}
+	}
+	if gwErr != nil {
+		// There is no reply for a batch, so the failure is only logged
+		log.L(wh.ctx).Errorf("Failed to invoke webhook for batch of %d events: %s", len(events), gwErr)
+	} else {
+		log.L(wh.ctx).Tracef("Webhook response for batch of %d events: %d", len(events), res.Status)
+	}
+	if !fastAck {
+		wh.ackBatch(connID, sub, events)
+	}
+}
+
+func (wh *WebHooks) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	// In fastack mode we acknowledge the whole batch immediately, and deliver it in the background
+	if sub.Options.TransportOptions().GetBool("fastack") {
+		wh.ackBatch(connID, sub, events)
+		go wh.doBatchDelivery(ctx, connID, sub, events, true)
 		return nil
 	}
 
-	wh.doDelivery(connID, reply, sub, event, data, false)
+	wh.doBatchDelivery(ctx, connID, sub, events, false)
 	return nil
 }
 
@@ -18,10 +18,28 @@ package webhooks
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"hash"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,6 +51,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -51,6 +70,7 @@ func newTestWebHooks(t *testing.T) (wh *WebHooks, cancel func()) {
 	wh.InitConfig(svrConfig)
 	wh.Init(ctx, svrConfig)
 	wh.SetHandler("ns1", cbs)
+	wh.secrets["mysecret"] = []byte("shh")
 	assert.Equal(t, "webhooks", wh.Name())
 	assert.NotNil(t, wh.Capabilities())
 	return wh, cancelCtx
@@ -242,7 +262,7 @@ func TestRequestWithBodyReplyEndToEnd(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{data})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{data})
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
@@ -352,7 +372,7 @@ func TestRequestWithEmptyStringBodyReplyEndToEnd(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{data})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{data})
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
@@ -419,7 +439,7 @@ func TestRequestNoBodyNoReply(t *testing.T) {
 		return !response.Rejected
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{data})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{data})
 	assert.NoError(t, err)
 	assert.True(t, called)
 
@@ -483,7 +503,7 @@ func TestRequestReplyEmptyData(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
 	assert.NoError(t, err)
 	assert.True(t, called)
 
@@ -549,7 +569,7 @@ func TestRequestReplyOneData(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{{ID: dataID, Value: fftypes.JSONAnyPtr("foo")}})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{{ID: dataID, Value: fftypes.JSONAnyPtr("foo")}})
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
@@ -602,7 +622,7 @@ func TestRequestReplyBadJSON(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{})
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
@@ -671,7 +691,7 @@ func TestRequestReplyDataArrayBadStatusB64(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)},
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value2"`)},
 	})
@@ -725,7 +745,7 @@ func TestRequestReplyDataArrayError(t *testing.T) {
 		return true
 	})).Return(nil)
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)},
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value2"`)},
 	})
@@ -779,13 +799,13 @@ func TestWebhookFailFastAsk(t *testing.T) {
 		})
 
 	// Drive two deliveries, waiting for them both to ack (noting both will fail)
-	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)},
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value2"`)},
 	})
 	assert.NoError(t, err)
 
-	err = wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{
+	err = wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)},
 		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value2"`)},
 	})
@@ -825,7 +845,7 @@ func TestDeliveryRequestNilMessage(t *testing.T) {
 		},
 	}
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, nil)
 	assert.NoError(t, err)
 	mcb.AssertExpectations(t)
 }
@@ -869,7 +889,7 @@ func TestDeliveryRequestReplyToReply(t *testing.T) {
 		return !response.Rejected // should be accepted as a no-op so we can move on to other events
 	}))
 
-	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, nil)
 	assert.NoError(t, err)
 
 	mcb.AssertExpectations(t)
@@ -881,3 +901,1013 @@ func TestNamespaceRestarted(t *testing.T) {
 
 	wh.NamespaceRestarted("ns1", time.Now())
 }
+
+func TestValidateOptionsRetry(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["retry"] = fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  10,
+		"initialDelay": "1s",
+		"maximumDelay": "1m",
+		"factor":       1.5,
+		"statusCodes":  []interface{}{429, 503},
+	}
+	err := wh.ValidateOptions(opts)
+	assert.NoError(t, err)
+
+	rp, err := wh.parseRetryPolicy(opts.TransportOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, 10, rp.maxAttempts)
+	assert.Equal(t, time.Second, rp.retry.InitialDelay)
+	assert.Equal(t, time.Minute, rp.retry.MaximumDelay)
+	assert.Equal(t, 1.5, rp.retry.Factor)
+	assert.Equal(t, map[int]bool{429: true, 503: true}, rp.statusCodes)
+}
+
+func TestValidateOptionsRetryDefaults(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	rp, err := wh.parseRetryPolicy(fftypes.JSONObject{
+		"retry": fftypes.JSONObject{"enabled": true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRetryMaxAttempts, rp.maxAttempts)
+	assert.Equal(t, defaultRetryInitialDelay, rp.retry.InitialDelay)
+	assert.Equal(t, defaultRetryMaximumDelay, rp.retry.MaximumDelay)
+	assert.Equal(t, defaultRetryFactor, rp.retry.Factor)
+
+	rp, err = wh.parseRetryPolicy(fftypes.JSONObject{
+		"retry": fftypes.JSONObject{"enabled": false},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, rp)
+}
+
+func TestValidateOptionsBadRetry(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	for _, badRetry := range []interface{}{
+		"not an object",
+		fftypes.JSONObject{"enabled": true, "maxAttempts": -1},
+		fftypes.JSONObject{"enabled": true, "initialDelay": "bad"},
+		fftypes.JSONObject{"enabled": true, "maximumDelay": "bad"},
+		fftypes.JSONObject{"enabled": true, "factor": 0.5},
+		fftypes.JSONObject{"enabled": true, "statusCodes": []interface{}{42}},
+	} {
+		opts := &core.SubscriptionOptions{}
+		opts.TransportOptions()["url"] = "/anything"
+		opts.TransportOptions()["retry"] = badRetry
+		err := wh.ValidateOptions(opts)
+		assert.Regexp(t, "FF10459", err)
+	}
+}
+
+func newTestRetrySubscription(url string, retry fftypes.JSONObject) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = url
+	to["retry"] = retry
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestRequestRetrySuccess(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			res.WriteHeader(503)
+			return
+		}
+		res.WriteHeader(200)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"initialDelay": "1ms",
+	})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected && response.ID.Equals(event.ID)
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryExhaustedDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(mux.NewRouter())
+	server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  3,
+		"initialDelay": "1ms",
+	})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.Event.Equals(event.ID) &&
+			dl.Subscription.ID.Equals(sub.ID) &&
+			dl.Attempts == 3 &&
+			dl.Error != ""
+	})).Return()
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryStopsOnCancel(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	ctx, cancelDelivery := context.WithCancel(context.Background())
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		cancelDelivery()
+		res.WriteHeader(503)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  100,
+		"initialDelay": "1ms",
+	})
+
+	// No response and no dead letter, as the event is redelivered when the dispatcher restarts
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+
+	err := wh.DeliveryRequest(ctx, mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryFastAckCancelledDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	ctx, cancelDelivery := context.WithCancel(context.Background())
+	cancelDelivery()
+
+	sub, event := newTestRetrySubscription("http://localhost:12345/myapi", fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  100,
+		"initialDelay": "1ms",
+	})
+
+	// The event has already been acknowledged, so it is dead lettered rather than lost
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.Event.Equals(event.ID) && dl.Attempts == 1
+	})).Return()
+
+	wh.doDelivery(ctx, mock.Anything, false, sub, event, core.DataArray{}, true)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestReplayGatewayErrorRejected(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(mux.NewRouter())
+	server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled": false,
+	})
+	event.Replay = true
+
+	// With retry disabled the gateway error must still reject the replay, so the dead letter is kept
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.ID.Equals(event.ID) &&
+			response.Rejected &&
+			response.Info != "" &&
+			response.Reply == nil
+	})).Return()
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestReplayFailedStatusRejected(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(400)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"initialDelay": "1ms",
+	})
+	sub.Options.TransportOptions()["fastack"] = true
+	event.Replay = true
+
+	// The 400 is not retryable, but it is not a successful delivery either
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.ID.Equals(event.ID) &&
+			response.Rejected &&
+			strings.Contains(response.Info, "FF10460")
+	})).Return().Once()
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+	mcb.AssertNumberOfCalls(t, "DeliveryResponse", 1)
+}
+
+func TestReplaySuccessAcknowledged(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(204)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled": false,
+	})
+	event.Replay = true
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.ID.Equals(event.ID) && !response.Rejected
+	})).Return()
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryStatusCodes(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			res.WriteHeader(429)
+			return
+		}
+		res.WriteHeader(500)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"initialDelay": "1ms",
+		"statusCodes":  []interface{}{429},
+	})
+
+	// The 500 is not in the list of retryable status codes, so is returned as normal
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryInvalidPolicy(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(500)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":     true,
+		"maxAttempts": -1,
+	})
+
+	// We fall back to a single attempt
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil)
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func verifyTestSignature(t *testing.T, hashFn func() hash.Hash, secret, header string, body []byte) {
+	parts := strings.Split(header, ",")
+	assert.Len(t, parts, 2)
+	timestamp := strings.TrimPrefix(parts[0], "t=")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Now().Unix(), ts, 60)
+	mac := hmac.New(hashFn, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	assert.Equal(t, "v1="+hex.EncodeToString(mac.Sum(nil)), parts[1])
+}
+
+func TestValidateOptionsBadSigningAlgorithm(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["signing"] = fftypes.JSONObject{
+		"secretName": "mysecret",
+		"algorithm":  "md5",
+	}
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10464.*md5", err)
+}
+
+func TestValidateOptionsUnknownSigningSecret(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["signing"] = fftypes.JSONObject{
+		"secretName": "unknown",
+	}
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10577.*unknown", err)
+}
+
+func TestValidateOptionsInlineSigningSecret(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["signing"] = fftypes.JSONObject{
+		"secret": "shh",
+	}
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10579", err)
+}
+
+func TestValidateOptionsUnknownTLSConfig(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["tlsConfigName"] = "unknown"
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10462.*unknown", err)
+}
+
+func TestRequestSignedReply(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	msgID := fftypes.NewUUID()
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		verifyTestSignature(t, sha256.New, "shh", req.Header.Get("X-FireFly-Signature"), body)
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(200)
+		res.Write([]byte(`{"replyfield": "replyvalue"}`))
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	to["reply"] = true
+	to["signing"] = fftypes.JSONObject{
+		"secretName": "mysecret",
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					ID:   msgID,
+					Type: core.MessageTypeBroadcast,
+				},
+			},
+		},
+		Subscription: core.SubscriptionRef{
+			ID: sub.ID,
+		},
+	}
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.Reply != nil &&
+			response.Reply.InlineData[0].Value.JSONObject().GetObject("body").GetString("replyfield") == "replyvalue"
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestSignedCustomHeaderSHA512NoBody(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	called := false
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		verifyTestSignature(t, sha512.New, "shh", req.Header.Get("My-Signature"), []byte{})
+		res.WriteHeader(204)
+		called = true
+	}).Methods(http.MethodGet)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	to["method"] = http.MethodGet
+	to["signing"] = fftypes.JSONObject{
+		"secretName": "mysecret",
+		"header":     "My-Signature",
+		"algorithm":  "SHA512",
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+		},
+		Subscription: core.SubscriptionRef{
+			ID: sub.ID,
+		},
+	}
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil)
+
+	err := wh.DeliveryRequest(context.Background(), mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestBadBodyJSON(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	yes := true
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			Namespace: "ns1",
+		},
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				WithData: &yes,
+			},
+		},
+	}
+	sub.Options.TransportOptions()["url"] = "http://localhost:1/myapi"
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+
+	_, _, err := wh.attemptRequest(context.Background(), sub, event, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`!bad`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`!json`)},
+	})
+	assert.Error(t, err)
+}
+
+func writeTestPEM(t *testing.T, dir, name, pemType string, b []byte) string {
+	filename := filepath.Join(dir, name)
+	err := os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: b}), 0600)
+	assert.NoError(t, err)
+	return filename
+}
+
+func newTestClientCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "firefly"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(1 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return writeTestPEM(t, dir, "client.crt", "CERTIFICATE", der), writeTestPEM(t, dir, "client.key", "EC PRIVATE KEY", keyDER), cert
+}
+
+func TestRequestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := newTestClientCert(t, dir)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "firefly", req.TLS.PeerCertificates[0].Subject.CommonName)
+		res.WriteHeader(200)
+	}).Methods(http.MethodPost)
+	server := httptest.NewUnstartedServer(r)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writeTestPEM(t, dir, "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, fmt.Sprintf(`
+ut:
+  webhooks:
+    tlsConfigs:
+    - name: mtls
+      tls:
+        enabled: true
+        caFile: %s
+        certFile: %s
+        keyFile: %s
+`, caFile, certFile, keyFile))
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	err := wh.Init(ctx, svrConfig)
+	assert.NoError(t, err)
+	cbs := &eventsmocks.Callbacks{}
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	wh.SetHandler("ns1", cbs)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = server.URL + "/myapi"
+	to["tlsConfigName"] = "mtls"
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+
+	err = wh.ValidateOptions(&sub.Options)
+	assert.NoError(t, err)
+
+	_, res, err := wh.attemptRequest(context.Background(), sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+
+	// The default client does not present the certificate
+	delete(to, "tlsConfigName")
+	_, _, err = wh.attemptRequest(context.Background(), sub, event, core.DataArray{})
+	assert.Error(t, err)
+}
+
+func newTestTLSConfigsSection(t *testing.T, wh *WebHooks, yaml string) config.Section {
+	coreconfig.Reset()
+	svrConfig := config.RootSection("ut.webhooks")
+	wh.InitConfig(svrConfig)
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(yaml))
+	assert.NoError(t, err)
+	return svrConfig
+}
+
+func TestInitTLSConfigsMissingName(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    tlsConfigs:
+    - tls:
+        enabled: true
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10138.*name", err)
+}
+
+func TestInitTLSConfigsDuplicate(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    tlsConfigs:
+    - name: tls1
+    - name: tls1
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10463.*tls1", err)
+}
+
+func TestInitTLSConfigsBadCA(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    tlsConfigs:
+    - name: tls1
+      tls:
+        enabled: true
+        caFile: BADCA
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitSigningSecretsOk(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    signingSecrets:
+    - name: secret1
+      secret: shh
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("shh"), wh.secrets["secret1"])
+}
+
+func TestInitSigningSecretsMissingName(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    signingSecrets:
+    - secret: shh
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10138.*name", err)
+}
+
+func TestInitSigningSecretsMissingSecret(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    signingSecrets:
+    - name: secret1
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10138.*secret", err)
+}
+
+func TestInitSigningSecretsDuplicate(t *testing.T) {
+	wh := &WebHooks{}
+	svrConfig := newTestTLSConfigsSection(t, wh, `
+ut:
+  webhooks:
+    signingSecrets:
+    - name: secret1
+      secret: shh
+    - name: secret1
+      secret: shh
+`)
+	err := wh.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10578.*secret1", err)
+}
+
+func newTestBatch(sub *core.Subscription) []*core.CombinedEventDataDelivery {
+	newEvent := func() *core.EventDelivery {
+		return &core.EventDelivery{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{
+					ID: fftypes.NewUUID(),
+				},
+			},
+			Subscription: sub.SubscriptionRef,
+		}
+	}
+	return []*core.CombinedEventDataDelivery{
+		{Event: newEvent(), Data: core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1}`)}}},
+		{Event: newEvent()},
+	}
+}
+
+func TestValidateOptionsBatchReply(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+	assert.True(t, wh.Capabilities().BatchDelivery)
+
+	opts := &core.SubscriptionOptions{
+		SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+			Batch: &core.SubscriptionBatchOptions{},
+		},
+	}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["reply"] = true
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10467", err)
+}
+
+func TestBatchDeliveryWithData(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		verifyTestSignature(t, sha256.New, "shh", req.Header.Get("X-FireFly-Signature"), body)
+		var batch []*core.EventDelivery
+		err = json.Unmarshal(body, &batch)
+		assert.NoError(t, err)
+		assert.Len(t, batch, 2)
+		var withData []fftypes.JSONObject
+		err = json.Unmarshal(body, &withData)
+		assert.NoError(t, err)
+		assert.Len(t, withData[0].GetObjectArray("data"), 1)
+		assert.Nil(t, withData[1]["data"])
+		res.WriteHeader(200)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	yes := true
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.WithData = &yes
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	to["signing"] = fftypes.JSONObject{
+		"secretName": "mysecret",
+	}
+	batch := newTestBatch(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	for _, e := range batch {
+		eventID := e.Event.ID
+		mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+			return response.ID.Equals(eventID) && !response.Rejected
+		})).Return(nil).Once()
+	}
+
+	err := wh.BatchDeliveryRequest(context.Background(), mock.Anything, sub, batch)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryFastAckGET(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	called := make(chan struct{})
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(204)
+		close(called)
+	}).Methods(http.MethodGet)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+	to["method"] = http.MethodGet
+	to["fastack"] = true
+	batch := newTestBatch(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(context.Background(), mock.Anything, sub, batch)
+	assert.NoError(t, err)
+
+	<-called
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryFailAcked(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	to := sub.Options.TransportOptions()
+	to["url"] = "!!!://"
+	to["retry"] = fftypes.JSONObject{
+		"enabled":     true,
+		"maxAttempts": -1,
+	}
+	batch := newTestBatch(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(context.Background(), mock.Anything, sub, batch)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryBadOptions(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	_, _, err := wh.attemptBatchRequest(context.Background(), sub, newTestBatch(sub))
+	assert.Regexp(t, "FF10242", err)
+}
+
+func TestBatchDeliveryRetryDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	attempts := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		attempts++
+		res.WriteHeader(503)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, _ := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  2,
+		"initialDelay": "1ms",
+	})
+	batch := newTestBatch(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	for _, e := range batch {
+		eventID := e.Event.ID
+		mcb.On("DeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+			return dl.Event.Equals(eventID) && dl.Attempts == 2
+		})).Return().Once()
+	}
+
+	err := wh.BatchDeliveryRequest(context.Background(), mock.Anything, sub, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRetryStopsOnCancel(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	ctx, cancelDelivery := context.WithCancel(context.Background())
+	cancelDelivery()
+
+	sub, _ := newTestRetrySubscription("http://localhost:12345/myapi", fftypes.JSONObject{
+		"enabled":      true,
+		"maxAttempts":  100,
+		"initialDelay": "1ms",
+	})
+
+	// No acks and no dead letters, as the batch is redelivered when the dispatcher restarts
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+
+	err := wh.BatchDeliveryRequest(ctx, mock.Anything, sub, newTestBatch(sub))
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRetrySuccess(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	attempts := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts == 1 {
+			res.WriteHeader(503)
+			return
+		}
+		res.WriteHeader(200)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, _ := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), fftypes.JSONObject{
+		"enabled":      true,
+		"initialDelay": "1ms",
+	})
+	batch := newTestBatch(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(context.Background(), mock.Anything, sub, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	mcb.AssertExpectations(t)
+}
@@ -49,6 +49,7 @@ type websocketConnection struct {
 	autoAck      bool
 	started      []*websocketStartedSub
 	inflight     []*core.EventDeliveryResponse
+	batches      map[fftypes.UUID][]*core.EventDeliveryResponse
 	mux          sync.Mutex
 	closed       bool
 	remoteAddr   string
@@ -70,6 +71,7 @@ func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn,
 		sendMessages: make(chan interface{}),
 		senderDone:   make(chan struct{}),
 		receiverDone: make(chan struct{}),
+		batches:      make(map[fftypes.UUID][]*core.EventDeliveryResponse),
 		remoteAddr:   req.RemoteAddr,
 		userAgent:    req.UserAgent(),
 		header:       req.Header,
@@ -208,6 +210,51 @@ func (wc *websocketConnection) dispatch(event *core.EventDelivery) error {
 	return nil
 }
 
+// dispatchBatch sends a batch of events in a single message. The batch is tracked as a single in-flight
+// entry, so one ack from the client acknowledges every event in the batch.
+func (wc *websocketConnection) dispatchBatch(sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	batch := &core.WSEventBatch{
+		Type:         core.WSEventBatchType,
+		ID:           fftypes.NewUUID(),
+		Subscription: sub.SubscriptionRef,
+		Events:       make([]*core.EventDelivery, len(events)),
+	}
+	responses := make([]*core.EventDeliveryResponse, len(events))
+	for i, e := range events {
+		batch.Events[i] = e.Event
+		responses[i] = &core.EventDeliveryResponse{
+			ID:           e.Event.ID,
+			Subscription: e.Event.Subscription,
+		}
+	}
+	inflight := &core.EventDeliveryResponse{
+		ID:           batch.ID,
+		Subscription: batch.Subscription,
+	}
+
+	var autoAck bool
+	wc.mux.Lock()
+	autoAck = wc.autoAck
+	if !autoAck {
+		wc.inflight = append(wc.inflight, inflight)
+		wc.batches[*batch.ID] = responses
+	}
+	wc.mux.Unlock()
+
+	err := wc.send(batch)
+	if err != nil {
+		return err
+	}
+
+	if autoAck {
+		for _, response := range responses {
+			wc.ws.ack(wc.connID, response)
+		}
+	}
+
+	return nil
+}
+
 func (wc *websocketConnection) protocolError(err error) {
 	log.L(wc.ctx).Errorf("Sending protocol error to client: %s", err)
 	sendErr := wc.send(&core.WSError{
@@ -278,7 +325,7 @@ func (wc *websocketConnection) durableSubMatcher(sr core.SubscriptionRef) bool {
 	return false
 }
 
-func (wc *websocketConnection) checkAck(ack *core.WSAck) (*core.EventDeliveryResponse, error) {
+func (wc *websocketConnection) checkAck(ack *core.WSAck) ([]*core.EventDeliveryResponse, error) {
 	l := log.L(wc.ctx)
 	var inflight *core.EventDeliveryResponse
 	wc.mux.Lock()
@@ -328,18 +375,25 @@ func (wc *websocketConnection) checkAck(ack *core.WSAck) (*core.EventDeliveryRes
 	if inflight == nil {
 		return nil, i18n.NewError(wc.ctx, coremsgs.MsgWSMsgSubNotMatched)
 	}
-	return inflight, nil
+	// An ack for a batch acknowledges every event in the batch
+	if batch, ok := wc.batches[*inflight.ID]; ok {
+		delete(wc.batches, *inflight.ID)
+		return batch, nil
+	}
+	return []*core.EventDeliveryResponse{inflight}, nil
 }
 
 func (wc *websocketConnection) handleAck(ack *core.WSAck) error {
 	// Perform a locked set of check
-	inflight, err := wc.checkAck(ack)
+	acks, err := wc.checkAck(ack)
 	if err != nil {
 		return err
 	}
 
-	// Deliver the ack to the core, now we're unlocked
-	wc.ws.ack(wc.connID, inflight)
+	// Deliver the acks to the core, now we're unlocked
+	for _, inflight := range acks {
+		wc.ws.ack(wc.connID, inflight)
+	}
 	return nil
 }
 
@@ -50,9 +50,11 @@ func (ws *WebSockets) Name() string { return "websockets" }
 
 func (ws *WebSockets) Init(ctx context.Context, config config.Section) error {
 	*ws = WebSockets{
-		ctx:          ctx,
-		connections:  make(map[string]*websocketConnection),
-		capabilities: &events.Capabilities{},
+		ctx:         ctx,
+		connections: make(map[string]*websocketConnection),
+		capabilities: &events.Capabilities{
+			BatchDelivery: true,
+		},
 		callbacks: callbacks{
 			handlers: make(map[string]events.Callbacks),
 		},
@@ -97,7 +99,7 @@ func (ws *WebSockets) ValidateOptions(options *core.SubscriptionOptions) error {
 	return nil
 }
 
-func (ws *WebSockets) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+func (ws *WebSockets) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
 	ws.connMux.Lock()
 	conn, ok := ws.connections[connID]
 	ws.connMux.Unlock()
@@ -107,6 +109,16 @@ func (ws *WebSockets) DeliveryRequest(connID string, sub *core.Subscription, eve
 	return conn.dispatch(event)
 }
 
+func (ws *WebSockets) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	ws.connMux.Lock()
+	conn, ok := ws.connections[connID]
+	ws.connMux.Unlock()
+	if !ok {
+		return i18n.NewError(ws.ctx, coremsgs.MsgWSConnectionNotActive, connID)
+	}
+	return conn.dispatchBatch(sub, events)
+}
+
 func (ws *WebSockets) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	wsConn, err := ws.upgrader.Upgrade(res, req, nil)
 	if err != nil {
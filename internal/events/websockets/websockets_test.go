@@ -194,7 +194,7 @@ func TestStartReceiveAckEphemeral(t *testing.T) {
 	assert.NoError(t, err)
 
 	<-waitSubscribed
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -247,7 +247,7 @@ func TestStartReceiveDurable(t *testing.T) {
 	assert.NoError(t, err)
 
 	<-waitSubscribed
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -258,7 +258,7 @@ func TestStartReceiveDurable(t *testing.T) {
 		},
 	}, nil)
 	// Put a second in flight
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -327,7 +327,7 @@ func TestStartReceiveDurableWithAuth(t *testing.T) {
 	assert.NoError(t, err)
 
 	<-waitSubscribed
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -338,7 +338,7 @@ func TestStartReceiveDurableWithAuth(t *testing.T) {
 		},
 	}, nil)
 	// Put a second in flight
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -436,7 +436,7 @@ func TestAutoStartReceiveAckEphemeral(t *testing.T) {
 	defer cancel()
 
 	<-waitSubscribed
-	ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+	ws.DeliveryRequest(context.Background(), connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -650,7 +650,7 @@ func TestWebsocketDispatchAfterClose(t *testing.T) {
 		ctx:         context.Background(),
 		connections: make(map[string]*websocketConnection),
 	}
-	err := ws.DeliveryRequest("gone", nil, &core.EventDelivery{}, nil)
+	err := ws.DeliveryRequest(context.Background(), "gone", nil, &core.EventDelivery{}, nil)
 	assert.Regexp(t, "FF10173", err)
 }
 
@@ -674,7 +674,7 @@ func TestDispatchAutoAck(t *testing.T) {
 		autoAck:      true,
 	}
 	wsc.ws.connections[wsc.connID] = wsc
-	err := wsc.ws.DeliveryRequest(wsc.connID, nil, &core.EventDelivery{
+	err := wsc.ws.DeliveryRequest(context.Background(), wsc.connID, nil, &core.EventDelivery{
 		EnrichedEvent: core.EnrichedEvent{
 			Event: core.Event{ID: fftypes.NewUUID()},
 		},
@@ -805,3 +805,118 @@ func TestNamespaceRestartedFailClose(t *testing.T) {
 
 	mcb.AssertExpectations(t)
 }
+
+func TestStartReceiveAckBatchEphemeral(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+	assert.True(t, ws.Capabilities().BatchDelivery)
+	var connID string
+	sub := cbs.On("EphemeralSubscription",
+		mock.MatchedBy(func(s string) bool { connID = s; return true }),
+		"ns1", mock.Anything, mock.Anything).Return(nil)
+	acked := make(chan *fftypes.UUID, 2)
+	ack := cbs.On("DeliveryResponse",
+		mock.MatchedBy(func(s string) bool { return s == connID }),
+		mock.Anything).Return(nil)
+	ack.RunFn = func(a mock.Arguments) {
+		acked <- a[1].(*core.EventDeliveryResponse).ID
+	}
+
+	waitSubscribed := make(chan struct{})
+	sub.RunFn = func(a mock.Arguments) {
+		close(waitSubscribed)
+	}
+
+	err := wsc.Send(context.Background(), []byte(`{"type":"start","namespace":"ns1","ephemeral":true}`))
+	assert.NoError(t, err)
+
+	<-waitSubscribed
+	subRef := core.SubscriptionRef{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	event1 := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID()},
+		},
+		Subscription: subRef,
+	}
+	event2 := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID()},
+		},
+		Subscription: subRef,
+	}
+	err = ws.BatchDeliveryRequest(context.Background(), connID, &core.Subscription{SubscriptionRef: subRef}, []*core.CombinedEventDataDelivery{
+		{Event: event1},
+		{Event: event2},
+	})
+	assert.NoError(t, err)
+
+	b := <-wsc.Receive()
+	var res core.WSEventBatch
+	err = json.Unmarshal(b, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, core.WSEventBatchType, res.Type)
+	assert.Len(t, res.Events, 2)
+	assert.Equal(t, *event1.ID, *res.Events[0].ID)
+	assert.Equal(t, *event2.ID, *res.Events[1].ID)
+
+	err = wsc.Send(context.Background(), []byte(fmt.Sprintf(`{"type":"ack","id":"%s"}`, res.ID)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, *event1.ID, *<-acked)
+	assert.Equal(t, *event2.ID, *<-acked)
+	cbs.AssertExpectations(t)
+}
+
+func TestWebsocketBatchDispatchAfterClose(t *testing.T) {
+	ws := &WebSockets{
+		ctx:         context.Background(),
+		connections: make(map[string]*websocketConnection),
+	}
+	err := ws.BatchDeliveryRequest(context.Background(), "gone", &core.Subscription{}, []*core.CombinedEventDataDelivery{})
+	assert.Regexp(t, "FF10173", err)
+}
+
+func TestConnectionDispatchBatchAfterClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	wsc := &websocketConnection{
+		ctx:     ctx,
+		batches: make(map[fftypes.UUID][]*core.EventDeliveryResponse),
+	}
+	err := wsc.dispatchBatch(&core.Subscription{}, []*core.CombinedEventDataDelivery{
+		{Event: &core.EventDelivery{}},
+	})
+	assert.Regexp(t, "FF00147", err)
+	assert.Len(t, wsc.batches, 1)
+}
+
+func TestDispatchBatchAutoAck(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	cbs.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil).Twice()
+	wsc := &websocketConnection{
+		ctx:    context.Background(),
+		connID: fftypes.NewUUID().String(),
+		ws: &WebSockets{
+			ctx: context.Background(),
+			callbacks: callbacks{
+				handlers: map[string]events.Callbacks{"ns1": cbs},
+			},
+			connections: make(map[string]*websocketConnection),
+		},
+		sendMessages: make(chan interface{}, 1),
+		autoAck:      true,
+	}
+	wsc.ws.connections[wsc.connID] = wsc
+	subRef := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	err := wsc.ws.BatchDeliveryRequest(context.Background(), wsc.connID, &core.Subscription{SubscriptionRef: subRef}, []*core.CombinedEventDataDelivery{
+		{Event: &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}, Subscription: subRef}},
+		{Event: &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}, Subscription: subRef}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, wsc.inflight)
+	cbs.AssertExpectations(t)
+}
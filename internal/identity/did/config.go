@@ -0,0 +1,33 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// DIDConfWebSubconf is the http configuration used to fetch did:web documents. If a url is
+	// configured, documents are fetched from that server (with the domain of the DID as the first
+	// path segment) instead of from the domain itself - for example a local stand-in for testing
+	DIDConfWebSubconf = "web"
+)
+
+func (d *DID) InitConfig(config config.Section) {
+	ffresty.InitConfig(config.SubSection(DIDConfWebSubconf))
+}
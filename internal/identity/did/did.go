@@ -0,0 +1,88 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/identity"
+)
+
+const (
+	MethodKey = "key"
+	MethodWeb = "web"
+)
+
+// DID is an identity plugin that resolves external did:key and did:web DIDs
+type DID struct {
+	ctx          context.Context
+	capabilities *identity.Capabilities
+	webClient    *resty.Client
+	webBaseURL   string
+}
+
+func (d *DID) Name() string {
+	return "did"
+}
+
+func (d *DID) Init(ctx context.Context, config config.Section) (err error) {
+	d.ctx = log.WithLogField(ctx, "identity", "did")
+
+	webConfig := config.SubSection(DIDConfWebSubconf)
+	d.webBaseURL = webConfig.GetString(ffresty.HTTPConfigURL)
+	d.webClient, err = ffresty.New(d.ctx, webConfig)
+	if err != nil {
+		return err
+	}
+	d.capabilities = &identity.Capabilities{
+		DIDMethods: []string{MethodKey, MethodWeb},
+	}
+	return nil
+}
+
+func (d *DID) SetHandler(namespace string, handler identity.Callbacks) {
+}
+
+func (d *DID) Start() error {
+	return nil
+}
+
+func (d *DID) Capabilities() *identity.Capabilities {
+	return d.capabilities
+}
+
+func (d *DID) ResolveDID(ctx context.Context, did string) (*identity.DIDDocument, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[2] == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDInvalid, did)
+	}
+	switch parts[1] {
+	case MethodKey:
+		return resolveDIDKey(ctx, did, parts[2])
+	case MethodWeb:
+		return d.resolveDIDWeb(ctx, did, parts[2])
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, did)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+var utConfig = config.RootSection("did_unit_tests")
+
+func resetConf() {
+	coreconfig.Reset()
+	d := &DID{}
+	d.InitConfig(utConfig)
+}
+
+func newTestDID(t *testing.T, webURL string) *DID {
+	resetConf()
+	utConfig.SubSection(DIDConfWebSubconf).Set(ffresty.HTTPConfigURL, webURL)
+	d := &DID{}
+	err := d.Init(context.Background(), utConfig)
+	assert.NoError(t, err)
+	return d
+}
+
+func TestInit(t *testing.T) {
+	var d identity.Plugin = newTestDID(t, "")
+	assert.Equal(t, "did", d.Name())
+	assert.NoError(t, d.Start())
+	assert.Equal(t, []string{"key", "web"}, d.Capabilities().DIDMethods)
+	d.SetHandler("ns1", &identitymocks.Callbacks{}) // no-op
+}
+
+func TestInitBadTLSConfig(t *testing.T) {
+	resetConf()
+	tlsConf := utConfig.SubSection(DIDConfWebSubconf).SubSection("tls")
+	tlsConf.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConf.Set(fftls.HTTPConfTLSCAFile, "!!!!!badness")
+	d := &DID{}
+	err := d.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestResolveDIDInvalid(t *testing.T) {
+	d := newTestDID(t, "")
+	_, err := d.ResolveDID(context.Background(), "not a did")
+	assert.Regexp(t, "FF10472", err)
+	_, err = d.ResolveDID(context.Background(), "did:key:")
+	assert.Regexp(t, "FF10472", err)
+	_, err = d.ResolveDID(context.Background(), "urn:key:z6Mk")
+	assert.Regexp(t, "FF10472", err)
+}
+
+func TestResolveDIDUnknownMethod(t *testing.T) {
+	d := newTestDID(t, "")
+	_, err := d.ResolveDID(context.Background(), "did:ion:EiClkZMDxPKqC9c")
+	assert.Regexp(t, "FF10349", err)
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/identity"
+	"golang.org/x/crypto/sha3"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// multicodec prefixes (as unsigned varints) of the supported public key types
+	multicodecEd25519   = []byte{0xed, 0x01}
+	multicodecSecp256k1 = []byte{0xe7, 0x01}
+
+	// secp256k1 field prime
+	secp256k1P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+)
+
+func base58Decode(s string) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range []byte(s) {
+		idx := strings.IndexByte(base58Alphabet, c)
+		if idx < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == '1' {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), n.Bytes()...), true
+}
+
+// secp256k1EthAddress decompresses a compressed secp256k1 public key, and returns the Ethereum address for it
+func secp256k1EthAddress(compressed []byte) (string, bool) {
+	if len(compressed) != 33 || (compressed[0] != 0x02 && compressed[0] != 0x03) {
+		return "", false
+	}
+	x := new(big.Int).SetBytes(compressed[1:])
+	if x.Cmp(secp256k1P) >= 0 {
+		return "", false
+	}
+	// y^2 = x^3 + 7, and as p = 3 mod 4 the square root is (y^2)^((p+1)/4)
+	ySquared := new(big.Int).Exp(x, big.NewInt(3), secp256k1P)
+	ySquared.Add(ySquared, big.NewInt(7))
+	ySquared.Mod(ySquared, secp256k1P)
+	exp := new(big.Int).Add(secp256k1P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(ySquared, exp, secp256k1P)
+	if new(big.Int).Exp(y, big.NewInt(2), secp256k1P).Cmp(ySquared) != 0 {
+		return "", false // not on the curve
+	}
+	if y.Bit(0) != uint(compressed[0]&1) {
+		y.Sub(secp256k1P, y)
+	}
+	uncompressed := make([]byte, 64)
+	x.FillBytes(uncompressed[0:32])
+	y.FillBytes(uncompressed[32:64])
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(uncompressed)
+	return "0x" + hex.EncodeToString(hash.Sum(nil)[12:]), true
+}
+
+// resolveDIDKey generates the DID document for a did:key DID, as per https://w3c-ccg.github.io/did-method-key/
+// Ed25519 and secp256k1 keys are supported. The Ethereum address of secp256k1 keys is included in the document,
+// so they can be used as blockchain signing keys.
+func resolveDIDKey(ctx context.Context, did, id string) (*identity.DIDDocument, error) {
+	if !strings.HasPrefix(id, "z") {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDInvalid, did)
+	}
+	keyBytes, ok := base58Decode(id[1:])
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDInvalid, did)
+	}
+
+	vm := &identity.VerificationMethod{
+		ID:                 fmt.Sprintf("%s#%s", did, id),
+		Controller:         did,
+		PublicKeyMultibase: id,
+	}
+	switch {
+	case bytes.HasPrefix(keyBytes, multicodecEd25519) && len(keyBytes) == len(multicodecEd25519)+32:
+		vm.Type = "Ed25519VerificationKey2020"
+	case bytes.HasPrefix(keyBytes, multicodecSecp256k1):
+		vm.Type = "EcdsaSecp256k1VerificationKey2019"
+		if vm.EthereumAddress, ok = secp256k1EthAddress(keyBytes[len(multicodecSecp256k1):]); !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDIDInvalid, did)
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDKeyUnsupported, did)
+	}
+
+	return &identity.DIDDocument{
+		Context:            []string{"https://www.w3.org/ns/did/v1"},
+		ID:                 did,
+		VerificationMethod: []*identity.VerificationMethod{vm},
+		Authentication:     []interface{}{vm.ID},
+		AssertionMethod:    []interface{}{vm.ID},
+	}, nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	encoded := []byte{}
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append([]byte{base58Alphabet[mod.Int64()]}, encoded...)
+	}
+	for i := 0; i < len(b) && b[i] == 0; i++ {
+		encoded = append([]byte{'1'}, encoded...)
+	}
+	return string(encoded)
+}
+
+func TestBase58Decode(t *testing.T) {
+	b, ok := base58Decode("112")
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x00, 0x00, 0x01}, b)
+	_, ok = base58Decode("0OIl")
+	assert.False(t, ok)
+}
+
+func TestResolveDIDKeyEd25519(t *testing.T) {
+	d := newTestDID(t, "")
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	doc, err := d.ResolveDID(context.Background(), did)
+	assert.NoError(t, err)
+	assert.Equal(t, did, doc.ID)
+	assert.Len(t, doc.VerificationMethod, 1)
+	vm := doc.VerificationMethod[0]
+	assert.Equal(t, did+"#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", vm.ID)
+	assert.Equal(t, "Ed25519VerificationKey2020", vm.Type)
+	assert.Equal(t, did, vm.Controller)
+	assert.Equal(t, "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", vm.PublicKeyMultibase)
+	assert.Equal(t, []interface{}{vm.ID}, doc.Authentication)
+	assert.Empty(t, doc.Verifiers())
+}
+
+func TestResolveDIDKeySecp256k1(t *testing.T) {
+	d := newTestDID(t, "")
+	// Compressed public keys for private keys 1 and 6, covering both parities of Y
+	for compressedHex, address := range map[string]string{
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798": "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf",
+		"03fff97bd5755eeea420453a14355235d382f6472f8568a18b2f057a1460297556": "0xe57bfe9f44b819898f47bf37e5af72a0783e1141",
+	} {
+		compressed, err := hex.DecodeString(compressedHex)
+		assert.NoError(t, err)
+		did := "did:key:z" + base58Encode(append(multicodecSecp256k1, compressed...))
+		doc, err := d.ResolveDID(context.Background(), did)
+		assert.NoError(t, err)
+		assert.Equal(t, "EcdsaSecp256k1VerificationKey2019", doc.VerificationMethod[0].Type)
+		assert.Equal(t, address, doc.VerificationMethod[0].EthereumAddress)
+		assert.Equal(t, address, doc.Verifiers()[0].Value)
+	}
+}
+
+func TestResolveDIDKeyBadSecp256k1(t *testing.T) {
+	d := newTestDID(t, "")
+	compressed, err := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	assert.NoError(t, err)
+
+	// Wrong length
+	_, err = d.ResolveDID(context.Background(), "did:key:z"+base58Encode(append(multicodecSecp256k1, compressed[0:32]...)))
+	assert.Regexp(t, "FF10472", err)
+
+	// Bad prefix
+	badPrefix := append([]byte{0x04}, compressed[1:]...)
+	_, err = d.ResolveDID(context.Background(), "did:key:z"+base58Encode(append(multicodecSecp256k1, badPrefix...)))
+	assert.Regexp(t, "FF10472", err)
+
+	// X out of range
+	outOfRange := append([]byte{0x02}, secp256k1P.Bytes()...)
+	_, err = d.ResolveDID(context.Background(), "did:key:z"+base58Encode(append(multicodecSecp256k1, outOfRange...)))
+	assert.Regexp(t, "FF10472", err)
+
+	// Not on the curve
+	offCurve := false
+	for x := int64(1); !offCurve; x++ {
+		point := make([]byte, 33)
+		point[0] = 0x02
+		big.NewInt(x).FillBytes(point[1:])
+		_, offCurve = secp256k1EthAddress(point)
+		offCurve = !offCurve
+	}
+}
+
+func TestResolveDIDKeyInvalid(t *testing.T) {
+	d := newTestDID(t, "")
+	_, err := d.ResolveDID(context.Background(), "did:key:f0123")
+	assert.Regexp(t, "FF10472", err)
+	_, err = d.ResolveDID(context.Background(), "did:key:z0OIl")
+	assert.Regexp(t, "FF10472", err)
+}
+
+func TestResolveDIDKeyUnsupported(t *testing.T) {
+	d := newTestDID(t, "")
+	_, err := d.ResolveDID(context.Background(), "did:key:z"+base58Encode(append([]byte{0x12, 0x00}, make([]byte, 32)...)))
+	assert.Regexp(t, "FF10473", err)
+	// Ed25519 with the wrong length
+	_, err = d.ResolveDID(context.Background(), "did:key:z"+base58Encode(append(multicodecEd25519, make([]byte, 31)...)))
+	assert.Regexp(t, "FF10473", err)
+}
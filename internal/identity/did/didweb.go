@@ -0,0 +1,77 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/identity"
+)
+
+// didWebPath converts the method specific identifier of a did:web DID to the domain and path of its DID document,
+// as per https://w3c-ccg.github.io/did-method-web/#read-resolve
+func didWebPath(ctx context.Context, did, id string) (domain, path string, err error) {
+	segments := strings.Split(id, ":")
+	for i, s := range segments {
+		if segments[i], err = url.PathUnescape(s); err != nil || segments[i] == "" || strings.Contains(segments[i], "/") {
+			return "", "", i18n.NewError(ctx, coremsgs.MsgDIDInvalid, did)
+		}
+	}
+	domain = segments[0]
+	if len(segments) == 1 {
+		return domain, "/.well-known/did.json", nil
+	}
+	return domain, fmt.Sprintf("/%s/did.json", strings.Join(segments[1:], "/")), nil
+}
+
+func (d *DID) resolveDIDWeb(ctx context.Context, did, id string) (*identity.DIDDocument, error) {
+	domain, path, err := didWebPath(ctx, did, id)
+	if err != nil {
+		return nil, err
+	}
+	docURL := fmt.Sprintf("https://%s%s", domain, path)
+	if d.webBaseURL != "" {
+		// Resolve against the configured server, rather than the domain itself
+		docURL = fmt.Sprintf("/%s%s", url.PathEscape(domain), path)
+	}
+
+	var doc identity.DIDDocument
+	res, err := d.webClient.R().
+		SetContext(ctx).
+		SetResult(&doc).
+		Get(docURL)
+	if err == nil && res.StatusCode() == http.StatusNotFound {
+		log.L(ctx).Debugf("DID '%s' not found at %s", did, docURL)
+		return nil, nil
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDIDWebRESTErr)
+	}
+	if doc.ID != did {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDDocumentMismatch, doc.ID, did)
+	}
+	log.L(ctx).Infof("Resolved DID '%s' from %s", did, docURL)
+	return &doc, nil
+}
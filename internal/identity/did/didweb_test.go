@@ -0,0 +1,106 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/identity"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDIDWebServer(t *testing.T, docs map[string]*identity.DIDDocument) (*DID, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error/.well-known/did.json" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		doc, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	return newTestDID(t, server.URL), server.Close
+}
+
+func TestResolveDIDWeb(t *testing.T) {
+	d, done := newTestDIDWebServer(t, map[string]*identity.DIDDocument{
+		"/example.com/.well-known/did.json": {
+			ID: "did:web:example.com",
+			VerificationMethod: []*identity.VerificationMethod{
+				{ID: "did:web:example.com#key1", EthereumAddress: "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+			},
+		},
+		"/example.com:8443/user/alice/did.json": {
+			ID: "did:web:example.com%3A8443:user:alice",
+		},
+		"/mismatch.com/.well-known/did.json": {
+			ID: "did:web:other.com",
+		},
+	})
+	defer done()
+
+	doc, err := d.ResolveDID(context.Background(), "did:web:example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "did:web:example.com", doc.ID)
+	assert.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", doc.Verifiers()[0].Value)
+
+	doc, err = d.ResolveDID(context.Background(), "did:web:example.com%3A8443:user:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "did:web:example.com%3A8443:user:alice", doc.ID)
+
+	doc, err = d.ResolveDID(context.Background(), "did:web:unknown.com")
+	assert.NoError(t, err)
+	assert.Nil(t, doc)
+
+	_, err = d.ResolveDID(context.Background(), "did:web:error")
+	assert.Regexp(t, "FF10474", err)
+
+	_, err = d.ResolveDID(context.Background(), "did:web:mismatch.com")
+	assert.Regexp(t, "FF10475", err)
+}
+
+func TestResolveDIDWebInvalid(t *testing.T) {
+	d := newTestDID(t, "")
+	_, err := d.ResolveDID(context.Background(), "did:web:example.com::alice")
+	assert.Regexp(t, "FF10472", err)
+	_, err = d.ResolveDID(context.Background(), "did:web:%zz")
+	assert.Regexp(t, "FF10472", err)
+	_, err = d.ResolveDID(context.Background(), "did:web:example.com:a%2Fb")
+	assert.Regexp(t, "FF10472", err)
+}
+
+func TestResolveDIDWebDomain(t *testing.T) {
+	d := newTestDID(t, "")
+	httpmock.ActivateNonDefault(d.webClient.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://example.com/user/alice/did.json",
+		httpmock.NewJsonResponderOrPanic(200, &identity.DIDDocument{ID: "did:web:example.com:user:alice"}))
+
+	doc, err := d.ResolveDID(context.Background(), "did:web:example.com:user:alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "did:web:example.com:user:alice", doc.ID)
+}
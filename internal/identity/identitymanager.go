@@ -18,6 +18,8 @@ package identity
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -31,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	idplugin "github.com/hyperledger/firefly/pkg/identity"
 )
 
 const (
@@ -47,8 +50,11 @@ type Manager interface {
 
 	FindIdentityForVerifier(ctx context.Context, iTypes []core.IdentityType, verifier *core.VerifierRef) (identity *core.Identity, err error)
 	CachedIdentityLookupByID(ctx context.Context, id *fftypes.UUID) (identity *core.Identity, err error)
+	CachedVerifierLookup(ctx context.Context, verifierRef *core.VerifierRef) (verifier *core.Verifier, err error)
 	CachedIdentityLookupMustExist(ctx context.Context, did string) (identity *core.Identity, retryable bool, err error)
 	CachedIdentityLookupNilOK(ctx context.Context, did string) (identity *core.Identity, retryable bool, err error)
+	InvalidateCachedIdentity(identity *core.Identity)
+	InvalidateCachedVerifier(verifier *core.Verifier)
 	GetMultipartyRootVerifier(ctx context.Context) (*core.VerifierRef, error)
 	GetMultipartyRootOrg(ctx context.Context) (*core.Identity, error)
 	GetLocalNode(ctx context.Context) (node *core.Identity, err error)
@@ -60,12 +66,13 @@ type identityManager struct {
 	database      database.Plugin
 	blockchain    blockchain.Plugin  // optional
 	multiparty    multiparty.Manager // optional
+	plugin        idplugin.Plugin    // optional
 	namespace     string
 	defaultKey    string
 	identityCache cache.CInterface
 }
 
-func NewIdentityManager(ctx context.Context, ns, defaultKey string, di database.Plugin, bi blockchain.Plugin, mp multiparty.Manager, cacheManager cache.Manager) (Manager, error) {
+func NewIdentityManager(ctx context.Context, ns, defaultKey string, di database.Plugin, bi blockchain.Plugin, mp multiparty.Manager, ii idplugin.Plugin, cacheManager cache.Manager) (Manager, error) {
 	if di == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "IdentityManager")
 	}
@@ -74,6 +81,7 @@ func NewIdentityManager(ctx context.Context, ns, defaultKey string, di database.
 		blockchain: bi,
 		namespace:  ns,
 		multiparty: mp,
+		plugin:     ii,
 		defaultKey: defaultKey,
 	}
 
@@ -226,6 +234,10 @@ func (im *identityManager) ResolveInputSigningIdentity(ctx context.Context, sign
 			if err != nil {
 				return err
 			}
+			// An external DID must list the key as one of its verification methods
+			if verifiers, external := im.externalDIDVerifiers(identity); external && !containsVerifier(verifiers, verifier) {
+				return i18n.NewError(ctx, coremsgs.MsgDIDKeyNotVerificationMethod, verifier.Value, identity.DID)
+			}
 			signerRef.Author = identity.DID
 		default:
 			return i18n.NewError(ctx, coremsgs.MsgAuthorMissingForKey, signerRef.Key)
@@ -252,7 +264,15 @@ func (im *identityManager) ResolveInputSigningIdentity(ctx context.Context, sign
 // firstVerifierForIdentity does a lookup of the first verifier of a given type (such as a blockchain signing key) registered to an identity,
 // as a convenience to allow you to only specify the org name/DID when sending a message
 func (im *identityManager) firstVerifierForIdentity(ctx context.Context, vType core.VerifierType, identity *core.Identity) (verifier *core.VerifierRef, retryable bool, err error) {
-	fb := database.VerifierQueryFactory.NewFilterLimit(ctx, 1)
+	if verifiers, external := im.externalDIDVerifiers(identity); external {
+		for _, v := range verifiers {
+			if v.Type == vType {
+				return v, false, nil
+			}
+		}
+		return nil, false, i18n.NewError(ctx, coremsgs.MsgNoVerifierForIdentity, vType, identity.DID)
+	}
+	fb := database.VerifierQueryFactory.NewFilter(ctx)
 	filter := fb.And(
 		fb.Eq("type", vType),
 		fb.Eq("identity", identity.ID),
@@ -261,10 +281,13 @@ func (im *identityManager) firstVerifierForIdentity(ctx context.Context, vType c
 	if err != nil {
 		return nil, true /* DB Error */, err
 	}
-	if len(verifiers) == 0 {
-		return nil, false, i18n.NewError(ctx, coremsgs.MsgNoVerifierForIdentity, vType, identity.DID)
+	for _, v := range verifiers {
+		// Revoked keys (such as those replaced by a key rotation) are never used for signing
+		if v.Revoked == nil {
+			return &v.VerifierRef, false, nil
+		}
 	}
-	return &verifiers[0].VerifierRef, false, nil
+	return nil, false, i18n.NewError(ctx, coremsgs.MsgNoVerifierForIdentity, vType, identity.DID)
 }
 
 // resolveDefaultSigningIdentity adds the default signing identity into a message
@@ -365,6 +388,10 @@ func (im *identityManager) VerifyIdentityChain(ctx context.Context, checkIdentit
 		return nil, false, err
 	}
 
+	if checkIdentity.Revoked != nil {
+		return nil, false, i18n.NewError(ctx, coremsgs.MsgIdentityRevoked, checkIdentity.DID, checkIdentity.ID)
+	}
+
 	loopDetect := make(map[fftypes.UUID]bool)
 	current := checkIdentity
 	for {
@@ -389,6 +416,9 @@ func (im *identityManager) VerifyIdentityChain(ctx context.Context, checkIdentit
 		if im.multiparty != nil && parent.Messages.Claim == nil {
 			return nil, false, i18n.NewError(ctx, coremsgs.MsgParentIdentityMissingClaim, parent.DID, parent.ID)
 		}
+		if parent.Revoked != nil {
+			return nil, false, i18n.NewError(ctx, coremsgs.MsgIdentityRevoked, parent.DID, parent.ID)
+		}
 		current = parent
 		if immediateParent == nil {
 			immediateParent = parent
@@ -408,7 +438,19 @@ func (im *identityManager) ResolveIdentitySigner(ctx context.Context, identity *
 		return nil, i18n.NewError(ctx, coremsgs.MsgParentIdentityMissingClaim, identity.DID, identity.ID)
 	}
 	// Return the signing identity from that claim
-	return &msg.Header.SignerRef, nil
+	signer = &msg.Header.SignerRef
+	if im.blockchain != nil {
+		// The key that signed the claim might since have been revoked by a key rotation, in which case
+		// only the author is returned so that its current key is resolved at the point of signing
+		verifier, err := im.CachedVerifierLookup(ctx, &core.VerifierRef{Type: im.blockchain.VerifierType(), Value: signer.Key})
+		if err != nil {
+			return nil, err
+		}
+		if verifier != nil && verifier.Revoked != nil {
+			return &core.SignerRef{Author: signer.Author}, nil
+		}
+	}
+	return signer, nil
 }
 
 func (im *identityManager) validateParentType(ctx context.Context, child *core.Identity, parent *core.Identity) error {
@@ -458,6 +500,31 @@ func (im *identityManager) cachedIdentityLookupByVerifierRef(ctx context.Context
 	return identity, nil
 }
 
+func (im *identityManager) cachedVerifierLookup(ctx context.Context, namespace string, verifierRef *core.VerifierRef) (*core.Verifier, error) {
+	cacheKey := fmt.Sprintf("ns=%s,type=%s,verifierobj=%s", namespace, verifierRef.Type, verifierRef.Value)
+	if cachedValue := im.identityCache.Get(cacheKey); cachedValue != nil {
+		return cachedValue.(*core.Verifier), nil
+	}
+	verifier, err := im.database.GetVerifierByValue(ctx, verifierRef.Type, namespace, verifierRef.Value)
+	if err != nil {
+		return nil, err
+	} else if verifier == nil {
+		if namespace != core.LegacySystemNamespace && im.multiparty != nil && im.multiparty.GetNetworkVersion() == 1 {
+			// For V1 networks, fall back to LegacySystemNamespace for looking up verifiers
+			return im.cachedVerifierLookup(ctx, core.LegacySystemNamespace, verifierRef)
+		}
+		return nil, nil
+	}
+	// Cache the result
+	im.identityCache.Set(cacheKey, verifier)
+	return verifier, nil
+}
+
+// CachedVerifierLookup returns the verifier with the given type and value, including its revocation status, or nil if not found
+func (im *identityManager) CachedVerifierLookup(ctx context.Context, verifierRef *core.VerifierRef) (verifier *core.Verifier, err error) {
+	return im.cachedVerifierLookup(ctx, im.namespace, verifierRef)
+}
+
 func (im *identityManager) cachedIdentityLookup(ctx context.Context, namespace, didLookupStr string) (identity *core.Identity, retryable bool, err error) {
 	// Use an LRU cache for the author identity, as it's likely for the same identity to be re-used over and over
 	cacheKey := fmt.Sprintf("ns=%s,did=%s", namespace, didLookupStr)
@@ -475,7 +542,14 @@ func (im *identityManager) cachedIdentityLookup(ctx context.Context, namespace,
 	} else {
 		if strings.HasPrefix(didLookupStr, core.DIDPrefix) {
 			if !strings.HasPrefix(didLookupStr, core.FireFlyDIDPrefix) {
-				return nil, false, i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, didLookupStr)
+				// External DIDs are resolved via the identity plugin
+				if identity, err = im.resolveExternalDID(ctx, namespace, didLookupStr); err != nil {
+					return nil, false, err
+				}
+				if identity != nil {
+					im.identityCache.Set(cacheKey, identity)
+				}
+				return identity, false, nil
 			}
 			// Look up by the full DID
 			if identity, err = im.database.GetIdentityByDID(ctx, namespace, didLookupStr); err != nil {
@@ -509,6 +583,70 @@ func (im *identityManager) cachedIdentityLookup(ctx context.Context, namespace,
 	return identity, false, nil
 }
 
+// resolveExternalDID resolves a DID that is not registered with FireFly via the identity plugin, to an identity
+// that is not persisted. The ID of the identity is derived from the DID, and the DID document is stored in its profile.
+func (im *identityManager) resolveExternalDID(ctx context.Context, namespace, did string) (*core.Identity, error) {
+	if im.plugin == nil || !supportsDIDMethod(im.plugin.Capabilities(), did) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, did)
+	}
+	doc, err := im.plugin.ResolveDID(ctx, did)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	var docJSON fftypes.JSONObject
+	b, _ := json.Marshal(doc)
+	_ = json.Unmarshal(b, &docJSON)
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s", namespace, did)))
+	var id fftypes.UUID
+	copy(id[:], hash[0:16])
+	id[6] = (id[6] & 0x0f) | 0x50 // name based UUID
+	id[8] = (id[8] & 0x3f) | 0x80
+	return &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        &id,
+			DID:       did,
+			Type:      core.IdentityTypeCustom,
+			Namespace: namespace,
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"didDocument": docJSON,
+			},
+		},
+	}, nil
+}
+
+// externalDIDVerifiers returns the verifiers from the DID document of an identity resolved by resolveExternalDID,
+// or false if the identity is not an external DID
+func (im *identityManager) externalDIDVerifiers(identity *core.Identity) ([]*core.VerifierRef, bool) {
+	if !strings.HasPrefix(identity.DID, core.DIDPrefix) || strings.HasPrefix(identity.DID, core.FireFlyDIDPrefix) {
+		return nil, false
+	}
+	var doc idplugin.DIDDocument
+	b, _ := json.Marshal(identity.Profile.GetObject("didDocument"))
+	_ = json.Unmarshal(b, &doc)
+	return doc.Verifiers(), true
+}
+
+func supportsDIDMethod(capabilities *idplugin.Capabilities, did string) bool {
+	for _, method := range capabilities.DIDMethods {
+		if strings.HasPrefix(did, fmt.Sprintf("%s%s:", core.DIDPrefix, method)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVerifier(verifiers []*core.VerifierRef, verifier *core.VerifierRef) bool {
+	for _, v := range verifiers {
+		if v.Type == verifier.Type && v.Value == verifier.Value {
+			return true
+		}
+	}
+	return false
+}
+
 func (im *identityManager) CachedIdentityLookupNilOK(ctx context.Context, didLookupStr string) (identity *core.Identity, retryable bool, err error) {
 	return im.cachedIdentityLookup(ctx, im.namespace, didLookupStr)
 }
@@ -552,6 +690,24 @@ func (im *identityManager) CachedIdentityLookupByID(ctx context.Context, id *fft
 	return im.cachedIdentityLookupByID(ctx, im.namespace, id)
 }
 
+// InvalidateCachedIdentity removes an identity from the cache, so that it is read from the database on the next lookup.
+// Cached identities are shared between callers, so updates must be made to a copy and then invalidated.
+func (im *identityManager) InvalidateCachedIdentity(identity *core.Identity) {
+	im.identityCache.Delete(fmt.Sprintf("ns=%s,id=%s", identity.Namespace, identity.ID))
+	im.identityCache.Delete(fmt.Sprintf("ns=%s,did=%s", identity.Namespace, identity.DID))
+	if identity.Type == core.IdentityTypeOrg {
+		// Orgs can also be looked up by name, or by the DID alias of their UUID
+		im.identityCache.Delete(fmt.Sprintf("ns=%s,did=%s", identity.Namespace, identity.Name))
+		im.identityCache.Delete(fmt.Sprintf("ns=%s,did=%s%s", identity.Namespace, core.FireFlyOrgDIDPrefix, identity.ID))
+	}
+}
+
+// InvalidateCachedVerifier removes a verifier, and the identity resolved from it, from the cache
+func (im *identityManager) InvalidateCachedVerifier(verifier *core.Verifier) {
+	im.identityCache.Delete(fmt.Sprintf("ns=%s,type=%s,verifierobj=%s", verifier.Namespace, verifier.Type, verifier.Value))
+	im.identityCache.Delete(fmt.Sprintf("ns=%s,type=%s,verifier=%s", verifier.Namespace, verifier.Type, verifier.Value))
+}
+
 // Validate that the given identity or one of its ancestors owns the given node.
 func (im *identityManager) ValidateNodeOwner(ctx context.Context, node *core.Identity, identity *core.Identity) (valid bool, err error) {
 	l := log.L(ctx)
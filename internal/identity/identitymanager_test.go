@@ -30,9 +30,11 @@ import (
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
+	idplugin "github.com/hyperledger/firefly/pkg/identity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -48,7 +50,7 @@ func newTestIdentityManager(t *testing.T) (context.Context, *identityManager) {
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress).Maybe()
 	ns := "ns1"
-	im, err := NewIdentityManager(ctx, ns, "", mdi, mbi, mmp, cmi)
+	im, err := NewIdentityManager(ctx, ns, "", mdi, mbi, mmp, nil, cmi)
 	assert.NoError(t, err)
 	cmi.AssertCalled(t, "GetCache", cache.NewCacheConfig(
 		ctx,
@@ -61,7 +63,7 @@ func newTestIdentityManager(t *testing.T) (context.Context, *identityManager) {
 }
 
 func TestNewIdentityManagerMissingDeps(t *testing.T) {
-	_, err := NewIdentityManager(context.Background(), "", "", nil, nil, nil, nil)
+	_, err := NewIdentityManager(context.Background(), "", "", nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -85,7 +87,7 @@ func TestCacheInitFail(t *testing.T) {
 		ns,
 	)).Return(nil, cacheInitError).Once()
 	defer iErrcmi.AssertExpectations(t)
-	_, err := NewIdentityManager(ctx, ns, "", mdi, mbi, mmp, iErrcmi)
+	_, err := NewIdentityManager(ctx, ns, "", mdi, mbi, mmp, nil, iErrcmi)
 	assert.Equal(t, cacheInitError, err)
 
 }
@@ -774,6 +776,34 @@ func TestFirstVerifierForIdentityNotFound(t *testing.T) {
 
 }
 
+func TestFirstVerifierForIdentitySkipsRevoked(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	id := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "myid",
+			Type:      core.IdentityTypeOrg,
+		},
+	}
+
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{
+		{Identity: id.ID, VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"}, Revoked: fftypes.Now()},
+		{Identity: id.ID, VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}},
+	}, nil, nil)
+
+	verifier, _, err := im.firstVerifierForIdentity(ctx, core.VerifierTypeEthAddress, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x23456", verifier.Value)
+
+	mdi.AssertExpectations(t)
+
+}
+
 func TestResolveDefaultSigningIdentityNotFound(t *testing.T) {
 
 	ctx, im := newTestIdentityManager(t)
@@ -1022,6 +1052,130 @@ func TestCachedIdentityLookupByVerifierRefNotFound(t *testing.T) {
 
 }
 
+func TestCachedVerifierLookupCaching(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	verifier := (&core.Verifier{
+		Identity:  fftypes.NewUUID(),
+		Namespace: "ns1",
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x12345",
+		},
+	}).Seal()
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(verifier, nil).Once()
+
+	v1, err := im.CachedVerifierLookup(ctx, &verifier.VerifierRef)
+	assert.NoError(t, err)
+	assert.Equal(t, verifier, v1)
+
+	v2, err := im.CachedVerifierLookup(ctx, &verifier.VerifierRef)
+	assert.NoError(t, err)
+	assert.Same(t, v1, v2)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestInvalidateCachedVerifier(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	verifier := (&core.Verifier{
+		Identity:  fftypes.NewUUID(),
+		Namespace: "ns1",
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x12345",
+		},
+	}).Seal()
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(verifier, nil).Twice()
+
+	v1, err := im.CachedVerifierLookup(ctx, &verifier.VerifierRef)
+	assert.NoError(t, err)
+
+	im.InvalidateCachedVerifier(verifier)
+
+	v2, err := im.CachedVerifierLookup(ctx, &verifier.VerifierRef)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, v2)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestInvalidateCachedIdentity(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	org1 := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+	}
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", ctx, "ns1", org1.ID).Return(org1, nil).Twice()
+	mdi.On("GetIdentityByDID", ctx, "ns1", org1.DID).Return(org1, nil).Twice()
+	mdi.On("GetIdentityByName", ctx, core.IdentityTypeOrg, "ns1", "org1").Return(org1, nil).Twice()
+
+	lookup := func() {
+		_, err := im.CachedIdentityLookupByID(ctx, org1.ID)
+		assert.NoError(t, err)
+		_, _, err = im.CachedIdentityLookupNilOK(ctx, org1.DID)
+		assert.NoError(t, err)
+		_, _, err = im.CachedIdentityLookupNilOK(ctx, org1.Name)
+		assert.NoError(t, err)
+	}
+	lookup()
+	lookup()
+	im.InvalidateCachedIdentity(org1)
+	lookup()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCachedVerifierLookupError(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, fmt.Errorf("pop"))
+
+	_, err := im.CachedVerifierLookup(ctx, &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	})
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCachedVerifierLookupNotFoundLegacyFallback(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, core.LegacySystemNamespace, "0x12345").Return(nil, nil)
+	mmp := im.multiparty.(*multipartymocks.Manager)
+	mmp.On("GetNetworkVersion").Return(1)
+
+	verifier, err := im.CachedVerifierLookup(ctx, &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	mdi.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+}
+
 func TestCachedIdentityLookupMustExistCaching(t *testing.T) {
 
 	ctx, im := newTestIdentityManager(t)
@@ -1194,6 +1348,65 @@ func TestVerifyIdentityInvalid(t *testing.T) {
 
 }
 
+func TestVerifyIdentityChainRevoked(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	id1 := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+		Revoked: fftypes.Now(),
+	}
+
+	_, retryable, err := im.VerifyIdentityChain(ctx, id1)
+	assert.Regexp(t, "FF10553", err)
+	assert.False(t, retryable)
+
+}
+
+func TestVerifyIdentityChainParentRevoked(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	id1 := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+		Messages: core.IdentityMessages{
+			Claim: fftypes.NewUUID(),
+		},
+		Revoked: fftypes.Now(),
+	}
+	id2 := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			Parent:    id1.ID,
+			DID:       "did:firefly:node/node1",
+			Namespace: "ns1",
+			Name:      "node1",
+			Type:      core.IdentityTypeNode,
+		},
+	}
+
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", ctx, "ns1", id1.ID).Return(id1, nil).Once()
+
+	_, retryable, err := im.VerifyIdentityChain(ctx, id2)
+	assert.Regexp(t, "FF10553", err)
+	assert.False(t, retryable)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestVerifyIdentityChainLoop(t *testing.T) {
 
 	ctx, im := newTestIdentityManager(t)
@@ -1415,6 +1628,9 @@ func TestResolveIdentitySignerOk(t *testing.T) {
 			},
 		},
 	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(&core.Verifier{
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"},
+	}, nil)
 
 	signerRef, err := im.ResolveIdentitySigner(ctx, &core.Identity{
 		IdentityBase: core.IdentityBase{
@@ -1458,6 +1674,60 @@ func TestResolveIdentitySignerFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestResolveIdentitySignerKeyRotated(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", msgID).Return(&core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org1",
+				Key:    "0x12345",
+			},
+		},
+	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(&core.Verifier{
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"},
+		Revoked:     fftypes.Now(),
+	}, nil)
+
+	signerRef, err := im.ResolveIdentitySigner(ctx, &core.Identity{
+		Messages: core.IdentityMessages{
+			Claim: msgID,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org1", signerRef.Author)
+	assert.Empty(t, signerRef.Key)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveIdentitySignerVerifierLookupFail(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", msgID).Return(&core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{
+				Key: "0x12345",
+			},
+		},
+	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, fmt.Errorf("pop"))
+
+	_, err := im.ResolveIdentitySigner(ctx, &core.Identity{
+		Messages: core.IdentityMessages{
+			Claim: msgID,
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveIdentitySignerNotFound(t *testing.T) {
 	ctx, im := newTestIdentityManager(t)
 	mdi := im.database.(*databasemocks.Plugin)
@@ -1689,3 +1959,155 @@ func TestValidateNodeOwnerGetError(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func newTestExternalDIDPlugin(im *identityManager) *identitymocks.Plugin {
+	mii := &identitymocks.Plugin{}
+	mii.On("Capabilities").Return(&idplugin.Capabilities{DIDMethods: []string{"key", "web"}})
+	im.plugin = mii
+	return mii
+}
+
+func TestCachedIdentityLookupExternalDIDCaching(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	mii := newTestExternalDIDPlugin(im)
+
+	did := "did:web:example.com"
+	mii.On("ResolveDID", ctx, did).Return(&idplugin.DIDDocument{
+		ID: did,
+		VerificationMethod: []*idplugin.VerificationMethod{
+			{ID: did + "#key1", EthereumAddress: "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+		},
+	}, nil).Once()
+
+	identity, retryable, err := im.CachedIdentityLookupMustExist(ctx, did)
+	assert.NoError(t, err)
+	assert.False(t, retryable)
+	assert.Equal(t, did, identity.DID)
+	assert.Equal(t, core.IdentityTypeCustom, identity.Type)
+	assert.Equal(t, "ns1", identity.Namespace)
+	assert.Equal(t, did, identity.Profile.GetObject("didDocument").GetString("id"))
+
+	// Second lookup is from the cache, and the ID is stable
+	identity2, _, err := im.CachedIdentityLookupMustExist(ctx, did)
+	assert.NoError(t, err)
+	assert.Equal(t, identity.ID, identity2.ID)
+
+	mii.AssertExpectations(t)
+
+}
+
+func TestCachedIdentityLookupExternalDIDNotFound(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	mii := newTestExternalDIDPlugin(im)
+
+	mii.On("ResolveDID", ctx, "did:web:example.com").Return(nil, nil)
+
+	_, retryable, err := im.CachedIdentityLookupMustExist(ctx, "did:web:example.com")
+	assert.Regexp(t, "FF10277", err)
+	assert.False(t, retryable)
+
+	mii.AssertExpectations(t)
+
+}
+
+func TestCachedIdentityLookupExternalDIDResolveFail(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	mii := newTestExternalDIDPlugin(im)
+
+	mii.On("ResolveDID", ctx, "did:web:example.com").Return(nil, fmt.Errorf("pop"))
+
+	_, retryable, err := im.CachedIdentityLookupNilOK(ctx, "did:web:example.com")
+	assert.Regexp(t, "pop", err)
+	assert.False(t, retryable)
+
+	mii.AssertExpectations(t)
+
+}
+
+func TestCachedIdentityLookupExternalDIDUnsupportedMethod(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	newTestExternalDIDPlugin(im)
+
+	_, _, err := im.CachedIdentityLookupNilOK(ctx, "did:ion:EiClkZMDxPKqC9c")
+	assert.Regexp(t, "FF10349", err)
+
+}
+
+func TestResolveInputSigningIdentityExternalDIDKey(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	mii := newTestExternalDIDPlugin(im)
+
+	did := "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+	mii.On("ResolveDID", ctx, did).Return(&idplugin.DIDDocument{
+		ID: did,
+		VerificationMethod: []*idplugin.VerificationMethod{
+			{ID: did + "#key1", EthereumAddress: "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+		},
+	}, nil)
+
+	mbi := im.blockchain.(*blockchainmocks.Plugin)
+	mmp := im.multiparty.(*multipartymocks.Manager)
+	mbi.On("ResolveSigningKey", ctx, "0x7E5F4552091A69125D5DFCB7B8C2659029395BDF", blockchain.ResolveKeyIntentSign).Return("0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", nil)
+	mbi.On("ResolveSigningKey", ctx, "0xab5801a7d398351b8be11c439e05c5b3259aec9b", blockchain.ResolveKeyIntentSign).Return("0xab5801a7d398351b8be11c439e05c5b3259aec9b", nil)
+	mmp.On("GetNetworkVersion").Return(2)
+
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", mock.Anything).Return(nil, nil)
+
+	// Key listed in the DID document
+	signer := &core.SignerRef{
+		Key:    "0x7E5F4552091A69125D5DFCB7B8C2659029395BDF",
+		Author: did,
+	}
+	err := im.ResolveInputSigningIdentity(ctx, signer)
+	assert.NoError(t, err)
+	assert.Equal(t, did, signer.Author)
+	assert.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", signer.Key)
+
+	// Key not listed in the DID document
+	signer = &core.SignerRef{
+		Key:    "0xab5801a7d398351b8be11c439e05c5b3259aec9b",
+		Author: did,
+	}
+	err = im.ResolveInputSigningIdentity(ctx, signer)
+	assert.Regexp(t, "FF10476", err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mii.AssertExpectations(t)
+
+}
+
+func TestResolveInputSigningIdentityExternalDIDAuthorOnly(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+	mii := newTestExternalDIDPlugin(im)
+
+	mii.On("ResolveDID", ctx, "did:web:example.com").Return(&idplugin.DIDDocument{
+		ID: "did:web:example.com",
+		VerificationMethod: []*idplugin.VerificationMethod{
+			{ID: "did:web:example.com#key1", PublicKeyMultibase: "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+			{ID: "did:web:example.com#key2", BlockchainAccountID: "eip155:1:0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+		},
+	}, nil)
+	mii.On("ResolveDID", ctx, "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK").Return(&idplugin.DIDDocument{
+		ID: "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+	}, nil)
+
+	signer := &core.SignerRef{Author: "did:web:example.com"}
+	err := im.ResolveInputSigningIdentity(ctx, signer)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", signer.Key)
+
+	signer = &core.SignerRef{Author: "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"}
+	err = im.ResolveInputSigningIdentity(ctx, signer)
+	assert.Regexp(t, "FF10353", err)
+
+	mii.AssertExpectations(t)
+
+}
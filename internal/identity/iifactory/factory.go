@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/identity/did"
 	"github.com/hyperledger/firefly/internal/identity/tbd"
 	"github.com/hyperledger/firefly/pkg/identity"
 )
@@ -30,6 +31,7 @@ import (
 var pluginsByName = map[string]func() identity.Plugin{
 	// Plugin interface is TBD at this point. Plugin with "onchain" naming, and TBD implementation provided to avoid config migration impact
 	(*tbd.TBD)(nil).Name(): func() identity.Plugin { return &tbd.TBD{} },
+	(*did.DID)(nil).Name(): func() identity.Plugin { return &did.DID{} },
 }
 
 func InitConfig(config config.ArraySection) {
@@ -20,6 +20,8 @@ import (
 	"context"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/identity"
 )
 
@@ -47,3 +49,8 @@ func (tbd *TBD) Start() error {
 func (tbd *TBD) Capabilities() *identity.Capabilities {
 	return tbd.capabilities
 }
+
+func (tbd *TBD) ResolveDID(ctx context.Context, did string) (*identity.DIDDocument, error) {
+	// No DID methods are listed in the capabilities of this plugin
+	return nil, i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, did)
+}
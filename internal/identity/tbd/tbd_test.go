@@ -41,3 +41,9 @@ func TestInit(t *testing.T) {
 	cbs := &identitymocks.Callbacks{}
 	oc.SetHandler("ns1", cbs) // no-op
 }
+
+func TestResolveDIDUnsupported(t *testing.T) {
+	oc := &TBD{}
+	_, err := oc.ResolveDID(context.Background(), "did:key:z6Mk")
+	assert.Regexp(t, "FF10349", err)
+}
@@ -215,6 +215,16 @@ func TestBlockchainEvents(t *testing.T) {
 	assert.Equal(t, float64(1), v)
 }
 
+func TestRecordsPurged(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.RecordsPurged("ns1", "messages", 5)
+	m, err := RetentionPurgedCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", CollectionLabelName: "messages"})
+	assert.NoError(t, err)
+	v := testutil.ToFloat64(m)
+	assert.Equal(t, float64(5), v)
+}
+
 func TestIsMetricsEnabledTrue(t *testing.T) {
 	mm, cancel := newTestMetricsManager(t)
 	defer cancel()
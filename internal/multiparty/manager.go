@@ -18,6 +18,7 @@ package multiparty
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -52,6 +53,26 @@ type Manager interface {
 	// - Updates the namespace contract info to record the point of termination and the newly active contract
 	TerminateContract(ctx context.Context, location *fftypes.JSONAny, termination *blockchain.Event) (err error)
 
+	// MigrateContract marks the given event as the last one to be parsed on the current FireFly contract
+	// - Validates that the event came from the currently active multiparty contract
+	// - Re-initializes the plugin against the contract announced in the payload of the event
+	// - Updates the namespace contract info to record the point of migration and the newly active contract
+	MigrateContract(ctx context.Context, location *fftypes.JSONAny, payload string, migration *blockchain.Event) (err error)
+
+	// PauseContract stops the acceptance of new batch pins on the namespace
+	// - Validates that the event came from the currently active multiparty contract
+	// - Updates the namespace contract info to record the point of the pause, which survives termination and migration
+	PauseContract(ctx context.Context, location *fftypes.JSONAny, pause *blockchain.Event) (err error)
+
+	// ResumeContract clears any pause previously recorded by PauseContract
+	ResumeContract(ctx context.Context, location *fftypes.JSONAny, resume *blockchain.Event) (err error)
+
+	// IsPaused returns true if a network action has paused the acceptance of batch pins on the namespace
+	IsPaused() bool
+
+	// IsPausedAt returns true if a blockchain event at the given position in the ledger was sequenced while the namespace was paused
+	IsPausedAt(protocolID string) bool
+
 	// GetNetworkVersion returns the network version of the active FireFly contract
 	GetNetworkVersion() int
 
@@ -139,7 +160,7 @@ func (mm *multipartyManager) configureContractCommon(ctx context.Context, migrat
 	}
 	active := mm.namespace.Contracts.Active
 	log.L(ctx).Infof("Resolving FireFly contract at index %d", active.Index)
-	current, err := mm.resolveFireFlyContract(ctx, active.Index)
+	current, err := mm.resolveFireFlyContract(ctx, active)
 	if err != nil {
 		return err
 	}
@@ -166,7 +187,17 @@ func (mm *multipartyManager) configureContractCommon(ctx context.Context, migrat
 	return err
 }
 
-func (mm *multipartyManager) resolveFireFlyContract(ctx context.Context, contractIndex int) (contract *blockchain.MultipartyContract, err error) {
+func (mm *multipartyManager) resolveFireFlyContract(ctx context.Context, active *core.MultipartyContract) (contract *blockchain.MultipartyContract, err error) {
+	if active.Info.MigrationEvent != "" {
+		// This contract was announced by a migrate network action, rather than configured
+		return &blockchain.MultipartyContract{
+			Location:   active.Location,
+			FirstEvent: active.FirstEvent,
+			Options:    active.Options,
+		}, nil
+	}
+
+	contractIndex := active.Index
 	if len(mm.config.Contracts) > 0 || contractIndex > 0 {
 		if contractIndex >= len(mm.config.Contracts) {
 			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidFireFlyContractIndex,
@@ -186,26 +217,187 @@ func (mm *multipartyManager) resolveFireFlyContract(ctx context.Context, contrac
 	}, err
 }
 
-func (mm *multipartyManager) TerminateContract(ctx context.Context, location *fftypes.JSONAny, termination *blockchain.Event) (err error) {
+func (mm *multipartyManager) isActiveContract(ctx context.Context, eventType string, location *fftypes.JSONAny) bool {
+	active := mm.namespace.Contracts.Active
+	if active.Location.String() != location.String() {
+		log.L(ctx).Warnf("Ignoring %s event from contract at '%s', which does not match active '%s'", eventType, location, active.Location)
+		return false
+	}
+	return true
+}
+
+func (mm *multipartyManager) retireActiveContract(ctx context.Context, finalEvent *blockchain.Event) (index int) {
 	contracts := mm.namespace.Contracts
-	if contracts.Active.Location.String() != location.String() {
-		log.L(ctx).Warnf("Ignoring termination event from contract at '%s', which does not match active '%s'", location, contracts.Active.Location)
+	mm.blockchain.RemoveFireflySubscription(ctx, contracts.Active.Info.Subscription)
+	contracts.Active.Info.FinalEvent = finalEvent.ProtocolID
+	contracts.Terminated = append(contracts.Terminated, contracts.Active)
+	return contracts.Active.Index
+}
+
+func (mm *multipartyManager) TerminateContract(ctx context.Context, location *fftypes.JSONAny, termination *blockchain.Event) (err error) {
+	if !mm.isActiveContract(ctx, "termination", location) {
 		return nil
 	}
+	contracts := mm.namespace.Contracts
 	log.L(ctx).Infof("Processing termination of contract #%d at '%s'", contracts.Active.Index, contracts.Active.Location)
-	mm.blockchain.RemoveFireflySubscription(ctx, contracts.Active.Info.Subscription)
-	contracts.Active.Info.FinalEvent = termination.ProtocolID
-	contracts.Terminated = append(contracts.Terminated, contracts.Active)
-	contracts.Active = &core.MultipartyContract{Index: contracts.Active.Index + 1}
+	index := mm.retireActiveContract(ctx, termination)
+	contracts.Active = &core.MultipartyContract{Index: index + 1}
 	return mm.configureContractCommon(ctx, true)
 }
 
+func (mm *multipartyManager) validateNextContract(ctx context.Context, actionType core.NetworkActionType, next *core.NetworkActionContract) (err error) {
+	if next == nil || next.Location.IsNil() {
+		return i18n.NewError(ctx, coremsgs.MsgNetworkActionContractRequired, actionType)
+	}
+	if next.Location, err = mm.blockchain.NormalizeContractLocation(ctx, blockchain.NormalizeCall, next.Location); err != nil {
+		return err
+	}
+	if next.Location.String() == mm.namespace.Contracts.Active.Location.String() {
+		return i18n.NewError(ctx, coremsgs.MsgNetworkActionContractUnchanged, actionType)
+	}
+	return nil
+}
+
+// configuredContract finds the contract at the given location in the local config, if there is one
+func (mm *multipartyManager) configuredContract(ctx context.Context, location *fftypes.JSONAny) (index int, contract *blockchain.MultipartyContract, err error) {
+	for i := range mm.config.Contracts {
+		configured, err := mm.blockchain.NormalizeContractLocation(ctx, blockchain.NormalizeCall, mm.config.Contracts[i].Location)
+		if err != nil {
+			return -1, nil, err
+		}
+		if configured.String() == location.String() {
+			return i, &mm.config.Contracts[i], nil
+		}
+	}
+	return -1, nil, nil
+}
+
+// MigrateContract moves to the contract announced in the payload of a migrate network action. The event manager only
+// passes on network actions signed by a root org, so the announced location, first event and options are trusted
+// without each member having to configure the contract.
+func (mm *multipartyManager) MigrateContract(ctx context.Context, location *fftypes.JSONAny, payload string, migration *blockchain.Event) (err error) {
+	if !mm.isActiveContract(ctx, "migration", location) {
+		return nil
+	}
+	var next core.NetworkActionContract
+	if err := json.Unmarshal([]byte(payload), &next); err != nil {
+		log.L(ctx).Warnf("Ignoring migration event with invalid payload '%s': %s", payload, err)
+		return nil
+	}
+	if err := mm.validateNextContract(ctx, core.NetworkActionMigrate, &next); err != nil {
+		log.L(ctx).Warnf("Ignoring migration event with invalid contract: %s", err)
+		return nil
+	}
+
+	// Where the contract is also in the local config, its position there is kept so that a later termination moves on
+	// to the following entry, and the local entry fills in anything the payload leaves out.
+	// Otherwise the index is unchanged, and a later termination moves to the entry after the retired contract.
+	contracts := mm.namespace.Contracts
+	index, configured, err := mm.configuredContract(ctx, next.Location)
+	if err != nil {
+		return err
+	}
+	if configured == nil {
+		index = contracts.Active.Index
+	} else {
+		if next.FirstEvent == "" {
+			next.FirstEvent = configured.FirstEvent
+		}
+		if next.Options == nil {
+			next.Options = configured.Options
+		}
+	}
+	log.L(ctx).Infof("Processing migration of contract #%d at '%s' to #%d at '%s'", contracts.Active.Index, contracts.Active.Location, index, next.Location)
+	mm.retireActiveContract(ctx, migration)
+	contracts.Active = &core.MultipartyContract{
+		Index:      index,
+		Location:   next.Location,
+		FirstEvent: next.FirstEvent,
+		Options:    next.Options,
+		Info: core.MultipartyContractInfo{
+			MigrationEvent: migration.ProtocolID,
+		},
+	}
+	return mm.configureContractCommon(ctx, true)
+}
+
+func (mm *multipartyManager) currentPause() *core.NetworkPause {
+	pauses := mm.namespace.Contracts.Pauses
+	if len(pauses) > 0 && pauses[len(pauses)-1].ResumeEvent == "" {
+		return pauses[len(pauses)-1]
+	}
+	return nil
+}
+
+func (mm *multipartyManager) PauseContract(ctx context.Context, location *fftypes.JSONAny, pause *blockchain.Event) (err error) {
+	if !mm.isActiveContract(ctx, "pause", location) {
+		return nil
+	}
+	contracts := mm.namespace.Contracts
+	for _, existing := range contracts.Pauses {
+		if existing.Event == pause.ProtocolID {
+			log.L(ctx).Infof("Pause of namespace '%s' at event '%s' is already recorded", mm.namespace.Name, pause.ProtocolID)
+			return nil
+		}
+	}
+	if current := mm.currentPause(); current != nil {
+		log.L(ctx).Infof("Namespace '%s' is already paused since event '%s'", mm.namespace.Name, current.Event)
+		return nil
+	}
+	log.L(ctx).Infof("Processing pause of namespace '%s' on contract #%d", mm.namespace.Name, contracts.Active.Index)
+	contracts.Pauses = append(contracts.Pauses, &core.NetworkPause{
+		Event:     pause.ProtocolID,
+		Timestamp: pause.Timestamp,
+	})
+	return mm.database.UpsertNamespace(ctx, mm.namespace, true)
+}
+
+func (mm *multipartyManager) ResumeContract(ctx context.Context, location *fftypes.JSONAny, resume *blockchain.Event) (err error) {
+	if !mm.isActiveContract(ctx, "resume", location) {
+		return nil
+	}
+	current := mm.currentPause()
+	if current == nil {
+		log.L(ctx).Infof("Namespace '%s' is not paused at event '%s'", mm.namespace.Name, resume.ProtocolID)
+		return nil
+	}
+	log.L(ctx).Infof("Processing resume of namespace '%s' on contract #%d", mm.namespace.Name, mm.namespace.Contracts.Active.Index)
+	current.ResumeEvent = resume.ProtocolID
+	current.Resumed = resume.Timestamp
+	return mm.database.UpsertNamespace(ctx, mm.namespace, true)
+}
+
+func (mm *multipartyManager) IsPaused() bool {
+	return mm.currentPause() != nil
+}
+
+func (mm *multipartyManager) IsPausedAt(protocolID string) bool {
+	for _, pause := range mm.namespace.Contracts.Pauses {
+		if pause.Covers(protocolID) {
+			return true
+		}
+	}
+	return false
+}
+
 func (mm *multipartyManager) GetNetworkVersion() int {
 	return mm.namespace.Contracts.Active.Info.Version
 }
 
 func (mm *multipartyManager) SubmitNetworkAction(ctx context.Context, signingKey string, action *core.NetworkAction) error {
-	if action.Type != core.NetworkActionTerminate {
+	var payload string
+	switch action.Type {
+	case core.NetworkActionTerminate, core.NetworkActionPause, core.NetworkActionResume:
+	case core.NetworkActionMigrate:
+		if err := mm.validateNextContract(ctx, action.Type, action.Contract); err != nil {
+			return err
+		}
+		if _, err := mm.blockchain.GetNetworkVersion(ctx, action.Contract.Location); err != nil {
+			return err
+		}
+		payloadBytes, _ := json.Marshal(action.Contract)
+		payload = string(payloadBytes)
+	default:
 		return i18n.NewError(ctx, coremsgs.MsgUnrecognizedNetworkAction, action.Type)
 	}
 
@@ -219,12 +411,12 @@ func (mm *multipartyManager) SubmitNetworkAction(ctx context.Context, signingKey
 		mm.namespace.Name,
 		txid,
 		core.OpTypeBlockchainNetworkAction)
-	addNetworkActionInputs(op, action.Type, signingKey)
+	addNetworkActionInputs(op, action.Type, signingKey, payload)
 	if err := mm.operations.AddOrReuseOperation(ctx, op); err != nil {
 		return err
 	}
 
-	_, err = mm.operations.RunOperation(ctx, opNetworkAction(op, action.Type, signingKey))
+	_, err = mm.operations.RunOperation(ctx, opNetworkAction(op, action.Type, signingKey, payload))
 	return err
 }
 
@@ -245,6 +437,10 @@ func (mm *multipartyManager) prepareInvokeOperation(ctx context.Context, batch *
 }
 
 func (mm *multipartyManager) SubmitBatchPin(ctx context.Context, batch *core.BatchPersisted, contexts []*fftypes.Bytes32, payloadRef string) error {
+	if mm.IsPaused() {
+		return i18n.NewError(ctx, coremsgs.MsgNamespacePaused, mm.namespace.Name)
+	}
+
 	if batch.TX.Type == core.TransactionTypeContractInvokePin {
 		preparedOp, err := mm.prepareInvokeOperation(ctx, batch, contexts, payloadRef)
 		if err != nil {
@@ -185,7 +185,7 @@ func TestResolveContractDeprecatedConfigError(t *testing.T) {
 
 	mp.mbi.On("GetAndConvertDeprecatedContractConfig", context.Background()).Return(nil, "", fmt.Errorf("pop"))
 
-	_, err := mp.resolveFireFlyContract(context.Background(), 0)
+	_, err := mp.resolveFireFlyContract(context.Background(), &core.MultipartyContract{})
 	assert.Regexp(t, "pop", err)
 }
 
@@ -197,7 +197,7 @@ func TestResolveContractDeprecatedConfigNewestBlock(t *testing.T) {
 		"address": "0x123",
 	}.String()), "newst", nil)
 
-	_, err := mp.resolveFireFlyContract(context.Background(), 0)
+	_, err := mp.resolveFireFlyContract(context.Background(), &core.MultipartyContract{})
 	assert.NoError(t, err)
 }
 
@@ -703,3 +703,396 @@ func TestTerminateContractWrongAddress(t *testing.T) {
 	err := mp.TerminateContract(context.Background(), fftypes.JSONAnyPtr("{}"), &blockchain.Event{})
 	assert.NoError(t, err)
 }
+
+func TestSubmitNetworkActionMigrate(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	nextLocation := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x456",
+	}.String())
+	txid := fftypes.NewUUID()
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{Index: 0, Location: location},
+	}
+
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, nextLocation).Return(nextLocation, nil)
+	mp.mbi.On("GetNetworkVersion", context.Background(), nextLocation).Return(2, nil)
+	mp.mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeNetworkAction, core.IdempotencyKey("")).Return(txid, nil)
+	mp.mbi.On("Name").Return("ut")
+	mp.mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		assert.Equal(t, "migrate", op.Input.GetString("type"))
+		assert.JSONEq(t, `{"location":{"address":"0x456"},"firstEvent":"newest"}`, op.Input.GetString("payload"))
+		return true
+	})).Return(nil)
+	mp.mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(networkActionData)
+		return data.Type == core.NetworkActionMigrate && data.Payload != ""
+	})).Return(nil, nil)
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{
+		Type: core.NetworkActionMigrate,
+		Contract: &core.NetworkActionContract{
+			Location:   nextLocation,
+			FirstEvent: "newest",
+		},
+	})
+	assert.NoError(t, err)
+
+	mp.mth.AssertExpectations(t)
+}
+
+func TestSubmitNetworkActionMigrateNoContract(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{Type: core.NetworkActionMigrate})
+	assert.Regexp(t, "FF10557", err)
+}
+
+func TestSubmitNetworkActionMigrateBadLocation(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{
+		Type:     core.NetworkActionMigrate,
+		Contract: &core.NetworkActionContract{Location: fftypes.JSONAnyPtr(`{}`)},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSubmitNetworkActionMigrateUnchanged(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, location).Return(location, nil)
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{
+		Type:     core.NetworkActionMigrate,
+		Contract: &core.NetworkActionContract{Location: location},
+	})
+	assert.Regexp(t, "FF10558", err)
+}
+
+func TestSubmitNetworkActionMigrateVersionFail(t *testing.T) {
+	nextLocation := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x456",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.Contracts = []blockchain.MultipartyContract{{Location: nextLocation}}
+
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, nextLocation).Return(nextLocation, nil)
+	mp.mbi.On("GetNetworkVersion", context.Background(), nextLocation).Return(0, fmt.Errorf("pop"))
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{
+		Type:     core.NetworkActionMigrate,
+		Contract: &core.NetworkActionContract{Location: nextLocation},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSubmitNetworkActionPause(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeNetworkAction, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	err := mp.SubmitNetworkAction(context.Background(), "0x123", &core.NetworkAction{Type: core.NetworkActionPause})
+	assert.EqualError(t, err, "pop")
+
+	mp.mth.AssertExpectations(t)
+}
+
+func TestSubmitBatchPinPaused(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Pauses = []*core.NetworkPause{{Event: "000000000001/000000/000000"}}
+
+	err := mp.SubmitBatchPin(context.Background(), &core.BatchPersisted{}, []*fftypes.Bytes32{}, "payload1")
+	assert.Regexp(t, "FF10559", err)
+}
+
+func TestConfigureMigratedContract(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x456",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{
+			Index:      1,
+			Location:   location,
+			FirstEvent: "newest",
+			Info: core.MultipartyContractInfo{
+				MigrationEvent: "000000000001/000000/000000",
+			},
+		},
+	}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, location).Return(2, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.MatchedBy(func(contract *blockchain.MultipartyContract) bool {
+		return contract.Location == location && contract.FirstEvent == "newest"
+	})).Return("test", nil)
+	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", mp.namespace.Contracts.Active.Info.Subscription)
+	assert.Equal(t, 2, mp.GetNetworkVersion())
+}
+
+func TestMigrateContract(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	nextLocation := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x456",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{
+			Index:    0,
+			Location: location,
+			Info:     core.MultipartyContractInfo{Subscription: "sub1"},
+		},
+	}
+	mp.config.Contracts = []blockchain.MultipartyContract{
+		{Location: location},
+		{Location: fftypes.JSONAnyPtr(`{"address":"0x789"}`)},
+		{Location: nextLocation, FirstEvent: "newest", Options: fftypes.JSONAnyPtr(`{"customPinSupport":true}`)},
+	}
+
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(func(ctx context.Context, intent blockchain.NormalizeType, l *fftypes.JSONAny) *fftypes.JSONAny {
+		return l
+	}, nil)
+	mp.mbi.On("RemoveFireflySubscription", context.Background(), "sub1").Return()
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.MatchedBy(func(contract *blockchain.MultipartyContract) bool {
+		return contract.Location.String() == nextLocation.String() && contract.FirstEvent == "oldest" && contract.Options.String() == `{"customPinSupport":true}`
+	})).Return("sub2", nil)
+	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+
+	// The options missing from the payload are taken from the local config
+	payload := `{"location":{"address":"0x456"},"firstEvent":"oldest"}`
+	err := mp.MigrateContract(context.Background(), location, payload, &blockchain.Event{ProtocolID: "000000000010/000000/000000"})
+	assert.NoError(t, err)
+
+	contracts := mp.namespace.Contracts
+	assert.Len(t, contracts.Terminated, 1)
+	assert.Equal(t, "000000000010/000000/000000", contracts.Terminated[0].Info.FinalEvent)
+	assert.Equal(t, 2, contracts.Active.Index)
+	assert.Equal(t, nextLocation.String(), contracts.Active.Location.String())
+	assert.Equal(t, "000000000010/000000/000000", contracts.Active.Info.MigrationEvent)
+	assert.Equal(t, "sub2", contracts.Active.Info.Subscription)
+}
+
+func TestMigrateContractNotConfigured(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{
+			Index:    1,
+			Location: location,
+			Info:     core.MultipartyContractInfo{Subscription: "sub1"},
+		},
+	}
+	mp.config.Contracts = []blockchain.MultipartyContract{{Location: location}}
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(func(ctx context.Context, intent blockchain.NormalizeType, l *fftypes.JSONAny) *fftypes.JSONAny {
+		return l
+	}, nil)
+	mp.mbi.On("RemoveFireflySubscription", context.Background(), "sub1").Return()
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.MatchedBy(func(contract *blockchain.MultipartyContract) bool {
+		return contract.Location.String() == `{"address":"0x456"}` && contract.FirstEvent == "newest" && contract.Options.String() == `{"other":true}`
+	})).Return("sub2", nil)
+	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+
+	payload := `{"location":{"address":"0x456"},"firstEvent":"newest","options":{"other":true}}`
+	err := mp.MigrateContract(context.Background(), location, payload, &blockchain.Event{ProtocolID: "000000000010/000000/000000"})
+	assert.NoError(t, err)
+
+	contracts := mp.namespace.Contracts
+	assert.Len(t, contracts.Terminated, 1)
+	assert.Equal(t, 1, contracts.Active.Index)
+	assert.Equal(t, `{"address":"0x456"}`, contracts.Active.Location.String())
+	assert.Equal(t, "sub2", contracts.Active.Info.Subscription)
+}
+
+func TestMigrateContractConfigBadLocation(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	nextLocation := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x456",
+	}.String())
+	badLocation := fftypes.JSONAnyPtr(`"bad"`)
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+	mp.config.Contracts = []blockchain.MultipartyContract{{Location: badLocation}}
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, nextLocation).Return(nextLocation, nil)
+	mp.mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, badLocation).Return(nil, fmt.Errorf("pop"))
+
+	err := mp.MigrateContract(context.Background(), location, `{"location":{"address":"0x456"}}`, &blockchain.Event{})
+	assert.Regexp(t, "pop", err)
+	assert.Empty(t, mp.namespace.Contracts.Terminated)
+}
+
+func TestMigrateContractWrongAddress(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+
+	err := mp.MigrateContract(context.Background(), fftypes.JSONAnyPtr("{}"), `{"location":"next"}`, &blockchain.Event{})
+	assert.NoError(t, err)
+	assert.Empty(t, mp.namespace.Contracts.Terminated)
+}
+
+func TestMigrateContractBadPayload(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+
+	err := mp.MigrateContract(context.Background(), location, "!json", &blockchain.Event{})
+	assert.NoError(t, err)
+	assert.Empty(t, mp.namespace.Contracts.Terminated)
+}
+
+func TestMigrateContractBadContract(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+
+	err := mp.MigrateContract(context.Background(), location, `{}`, &blockchain.Event{})
+	assert.NoError(t, err)
+	assert.Empty(t, mp.namespace.Contracts.Terminated)
+}
+
+func TestPauseResumeContract(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	pauseTime := fftypes.Now()
+	resumeTime := fftypes.Now()
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+	mp.mdi.On("UpsertNamespace", mock.Anything, mp.namespace, true).Return(nil)
+
+	assert.False(t, mp.IsPaused())
+	err := mp.PauseContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000001/000000/000000", Timestamp: pauseTime})
+	assert.NoError(t, err)
+	assert.True(t, mp.IsPaused())
+	assert.Equal(t, "000000000001/000000/000000", mp.namespace.Contracts.Pauses[0].Event)
+	assert.Equal(t, pauseTime, mp.namespace.Contracts.Pauses[0].Timestamp)
+
+	// A second pause keeps the point of the original one
+	err = mp.PauseContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000002/000000/000000"})
+	assert.NoError(t, err)
+	assert.Len(t, mp.namespace.Contracts.Pauses, 1)
+
+	err = mp.ResumeContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000003/000000/000000", Timestamp: resumeTime})
+	assert.NoError(t, err)
+	assert.False(t, mp.IsPaused())
+	assert.Equal(t, "000000000003/000000/000000", mp.namespace.Contracts.Pauses[0].ResumeEvent)
+	assert.Equal(t, resumeTime, mp.namespace.Contracts.Pauses[0].Resumed)
+
+	// Replaying the pause and resume from the ledger does not change the record
+	err = mp.PauseContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000001/000000/000000"})
+	assert.NoError(t, err)
+	err = mp.ResumeContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000003/000000/000000"})
+	assert.NoError(t, err)
+	assert.Len(t, mp.namespace.Contracts.Pauses, 1)
+	assert.False(t, mp.IsPaused())
+
+	// Pins are checked against their position in the ledger
+	assert.False(t, mp.IsPausedAt("000000000000/000000/000001"))
+	assert.True(t, mp.IsPausedAt("000000000002/000000/000000"))
+	assert.False(t, mp.IsPausedAt("000000000004/000000/000000"))
+
+	err = mp.PauseContract(context.Background(), location, &blockchain.Event{ProtocolID: "000000000005/000000/000000"})
+	assert.NoError(t, err)
+	assert.True(t, mp.IsPaused())
+	assert.True(t, mp.IsPausedAt("000000000006/000000/000000"))
+
+	mp.mdi.AssertNumberOfCalls(t, "UpsertNamespace", 3)
+}
+
+func TestPauseContractFail(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+	mp.mdi.On("UpsertNamespace", mock.Anything, mp.namespace, true).Return(fmt.Errorf("pop"))
+
+	err := mp.PauseContract(context.Background(), location, &blockchain.Event{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPauseResumeContractWrongAddress(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts.Active.Location = location
+
+	err := mp.PauseContract(context.Background(), fftypes.JSONAnyPtr("{}"), &blockchain.Event{})
+	assert.NoError(t, err)
+	assert.False(t, mp.IsPaused())
+
+	mp.multipartyManager.namespace.Contracts.Pauses = []*core.NetworkPause{{}}
+	err = mp.ResumeContract(context.Background(), fftypes.JSONAnyPtr("{}"), &blockchain.Event{})
+	assert.NoError(t, err)
+	assert.True(t, mp.IsPaused())
+}
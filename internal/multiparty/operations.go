@@ -28,8 +28,9 @@ import (
 )
 
 type networkActionData struct {
-	Type core.NetworkActionType `json:"type"`
-	Key  string                 `json:"key"`
+	Type    core.NetworkActionType `json:"type"`
+	Key     string                 `json:"key"`
+	Payload string                 `json:"payload,omitempty"`
 }
 
 func addBatchPinInputs(op *core.Operation, batchID *fftypes.UUID, contexts []*fftypes.Bytes32, payloadRef string) {
@@ -44,11 +45,14 @@ func addBatchPinInputs(op *core.Operation, batchID *fftypes.UUID, contexts []*ff
 	}
 }
 
-func addNetworkActionInputs(op *core.Operation, actionType core.NetworkActionType, signingKey string) {
+func addNetworkActionInputs(op *core.Operation, actionType core.NetworkActionType, signingKey, payload string) {
 	op.Input = fftypes.JSONObject{
 		"type": actionType.String(),
 		"key":  signingKey,
 	}
+	if payload != "" {
+		op.Input["payload"] = payload
+	}
 }
 
 func retrieveBatchPinInputs(ctx context.Context, op *core.Operation) (batchID *fftypes.UUID, contexts []*fftypes.Bytes32, payloadRef string, err error) {
@@ -68,10 +72,11 @@ func retrieveBatchPinInputs(ctx context.Context, op *core.Operation) (batchID *f
 	return batchID, contexts, payloadRef, nil
 }
 
-func retrieveNetworkActionInputs(op *core.Operation) (actionType core.NetworkActionType, signingKey string) {
+func retrieveNetworkActionInputs(op *core.Operation) (actionType core.NetworkActionType, signingKey, payload string) {
 	actionType = fftypes.FFEnum(op.Input.GetString("type"))
 	signingKey = op.Input.GetString("key")
-	return actionType, signingKey
+	payload = op.Input.GetString("payload")
+	return actionType, signingKey, payload
 }
 
 func (mm *multipartyManager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
@@ -90,8 +95,8 @@ func (mm *multipartyManager) PrepareOperation(ctx context.Context, op *core.Oper
 		return opBatchPin(op, batch, contexts, payloadRef), nil
 
 	case core.OpTypeBlockchainNetworkAction:
-		actionType, signingKey := retrieveNetworkActionInputs(op)
-		return opNetworkAction(op, actionType, signingKey), nil
+		actionType, signingKey, payload := retrieveNetworkActionInputs(op)
+		return opNetworkAction(op, actionType, signingKey, payload), nil
 
 	default:
 		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
@@ -113,7 +118,7 @@ func (mm *multipartyManager) RunOperation(ctx context.Context, op *core.Prepared
 
 	case networkActionData:
 		contract := mm.namespace.Contracts.Active
-		return nil, false, mm.blockchain.SubmitNetworkAction(ctx, op.NamespacedIDString(), data.Key, data.Type, contract.Location)
+		return nil, false, mm.blockchain.SubmitNetworkAction(ctx, op.NamespacedIDString(), data.Key, data.Type, data.Payload, contract.Location)
 
 	default:
 		return nil, false, i18n.NewError(ctx, coremsgs.MsgOperationDataIncorrect, op.Data)
@@ -134,15 +139,16 @@ func opBatchPin(op *core.Operation, batch *core.BatchPersisted, contexts []*ffty
 	}
 }
 
-func opNetworkAction(op *core.Operation, actionType core.NetworkActionType, key string) *core.PreparedOperation {
+func opNetworkAction(op *core.Operation, actionType core.NetworkActionType, key, payload string) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
 		Namespace: op.Namespace,
 		Plugin:    op.Plugin,
 		Type:      op.Type,
 		Data: networkActionData{
-			Type: actionType,
-			Key:  key,
+			Type:    actionType,
+			Key:     key,
+			Payload: payload,
 		},
 	}
 }
@@ -74,15 +74,40 @@ func TestPrepareAndRunNetworkAction(t *testing.T) {
 		ID:        fftypes.NewUUID(),
 		Namespace: "ns1",
 	}
-	addNetworkActionInputs(op, core.NetworkActionTerminate, "0x123")
+	addNetworkActionInputs(op, core.NetworkActionTerminate, "0x123", "")
 
-	mp.mbi.On("SubmitNetworkAction", context.Background(), "ns1:"+op.ID.String(), "0x123", core.NetworkActionTerminate, mock.Anything).Return(nil)
+	mp.mbi.On("SubmitNetworkAction", context.Background(), "ns1:"+op.ID.String(), "0x123", core.NetworkActionTerminate, "", mock.Anything).Return(nil)
 
 	po, err := mp.PrepareOperation(context.Background(), op)
 	assert.NoError(t, err)
 	assert.Equal(t, core.NetworkActionTerminate, po.Data.(networkActionData).Type)
 
-	_, complete, err := mp.RunOperation(context.Background(), opNetworkAction(op, core.NetworkActionTerminate, "0x123"))
+	_, complete, err := mp.RunOperation(context.Background(), opNetworkAction(op, core.NetworkActionTerminate, "0x123", ""))
+
+	assert.False(t, complete)
+	assert.NoError(t, err)
+
+	mp.mbi.AssertExpectations(t)
+}
+
+func TestPrepareAndRunNetworkActionPayload(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	op := &core.Operation{
+		Type:      core.OpTypeBlockchainNetworkAction,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	addNetworkActionInputs(op, core.NetworkActionMigrate, "0x123", `{"location":"next"}`)
+
+	mp.mbi.On("SubmitNetworkAction", context.Background(), "ns1:"+op.ID.String(), "0x123", core.NetworkActionMigrate, `{"location":"next"}`, mock.Anything).Return(nil)
+
+	po, err := mp.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"location":"next"}`, po.Data.(networkActionData).Payload)
+
+	_, complete, err := mp.RunOperation(context.Background(), po)
 
 	assert.False(t, complete)
 	assert.NoError(t, err)
@@ -71,6 +71,13 @@ func InitConfig() {
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractLocation)
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractOptions)
 
+	retentionConf := namespacePredefined.SubSection(coreconfig.NamespaceRetention)
+	retentionConf.AddKnownKey(coreconfig.NamespaceRetentionMessages)
+	retentionConf.AddKnownKey(coreconfig.NamespaceRetentionEvents)
+	retentionConf.AddKnownKey(coreconfig.NamespaceRetentionOperations)
+	retentionConf.AddKnownKey(coreconfig.NamespaceRetentionBlockchainEvents)
+	retentionConf.AddKnownKey(coreconfig.NamespaceRetentionTokenTransfers)
+
 	bifactory.InitConfig(blockchainConfig)
 	difactory.InitConfig(databaseConfig)
 	ssfactory.InitConfig(sharedstorageConfig)
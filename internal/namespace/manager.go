@@ -41,6 +41,7 @@ import (
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/spievents"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
@@ -420,6 +421,10 @@ func (nm *namespaceManager) startNamespacesAndPlugins(namespacesToStart map[stri
 			if err := plugin.tokens.Start(); err != nil {
 				return err
 			}
+		case pluginCategoryIdentity:
+			if err := plugin.identity.Start(); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -728,6 +733,10 @@ func (nm *namespaceManager) initPlugins(pluginsToStart map[string]*plugin) (err
 			if err = p.auth.Init(p.ctx, name, p.config); err != nil {
 				return err
 			}
+		case pluginCategoryIdentity:
+			if err = p.identity.Init(p.ctx, p.config); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -850,10 +859,18 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		}
 	}
 
+	retentionConf := conf.SubSection(coreconfig.NamespaceRetention)
 	config := orchestrator.Config{
 		DefaultKey:          conf.GetString(coreconfig.NamespaceDefaultKey),
 		TokenBroadcastNames: nm.tokenBroadcastNames,
 		KeyNormalization:    keyNormalization,
+		Retention: retention.Config{
+			Messages:         retentionConf.GetDuration(coreconfig.NamespaceRetentionMessages),
+			Events:           retentionConf.GetDuration(coreconfig.NamespaceRetentionEvents),
+			Operations:       retentionConf.GetDuration(coreconfig.NamespaceRetentionOperations),
+			BlockchainEvents: retentionConf.GetDuration(coreconfig.NamespaceRetentionBlockchainEvents),
+			TokenTransfers:   retentionConf.GetDuration(coreconfig.NamespaceRetentionTokenTransfers),
+		},
 	}
 	if multipartyEnabled.(bool) {
 		contractsConf := multipartyConf.SubArray(coreconfig.NamespaceMultipartyContract)
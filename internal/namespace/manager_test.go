@@ -39,6 +39,7 @@ import (
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -271,6 +272,7 @@ func newTestNamespaceManager(t *testing.T, initConfig bool) (*namespaceManager,
 		nmm.mei[1].On("Init", mock.Anything, mock.Anything).Return(nil)
 		nmm.mei[2].On("Init", mock.Anything, mock.Anything).Return(nil)
 		nmm.mai.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		nmm.mii.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
 
 		err = nmm.nm.Init(nmm.nm.ctx, nmm.nm.cancelCtx, nmm.nm.reset, nmm.nm.reloadConfig)
 		assert.NoError(t, err)
@@ -398,6 +400,18 @@ func TestInitEventsFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestInitIdentityFail(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nmm.mii.On("Init", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := nm.initPlugins(map[string]*plugin{
+		"tbd": nm.plugins["tbd"],
+	})
+	assert.EqualError(t, err, "pop")
+}
+
 func TestInitAuthFail(t *testing.T) {
 	nm, nmm, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1305,6 +1319,34 @@ func TestLoadNamespacesUseDefaults(t *testing.T) {
 	assert.Equal(t, "oldest", newNS["ns1"].config.Multiparty.Contracts[0].FirstEvent)
 }
 
+func TestLoadNamespacesRetention(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      retention:
+        messages: 720h
+        events: 24h
+        operations: 168h
+  `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+	assert.Len(t, newNS, 1)
+	assert.Equal(t, retention.Config{
+		Messages:   720 * time.Hour,
+		Events:     24 * time.Hour,
+		Operations: 168 * time.Hour,
+	}, newNS["ns1"].config.Retention)
+}
+
 func TestLoadNamespacesNonMultipartyNoDatabase(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1645,6 +1687,7 @@ func TestStart(t *testing.T) {
 	nmm.mdx.On("Start", mock.Anything).Return(nil)
 	nmm.mti[0].On("Start", mock.Anything).Return(nil)
 	nmm.mti[1].On("Start", mock.Anything).Return(nil)
+	nmm.mii.On("Start", mock.Anything).Return(nil)
 	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
 	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
 	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return(nil)
@@ -1687,6 +1730,20 @@ func TestStartDataExchangeFail(t *testing.T) {
 
 }
 
+func TestStartIdentityFail(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nm.namespaces = nil
+	nmm.mii.On("Start").Return(fmt.Errorf("pop"))
+
+	err := nm.startNamespacesAndPlugins(nm.namespaces, map[string]*plugin{
+		"tbd": nm.plugins["tbd"],
+	})
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestStartTokensFail(t *testing.T) {
 	nm, nmm, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -112,14 +112,18 @@ func (nm *networkMap) withVerifiers(ctx context.Context, identity *core.Identity
 	if err != nil {
 		return nil, err
 	}
-	refs := make([]*core.VerifierRef, len(verifiers))
-	for i, v := range verifiers {
-		refs[i] = &v.VerifierRef
-	}
-	return &core.IdentityWithVerifiers{
+	result := &core.IdentityWithVerifiers{
 		Identity:  *identity,
-		Verifiers: refs,
-	}, nil
+		Verifiers: make([]*core.VerifierRef, 0, len(verifiers)),
+	}
+	for _, v := range verifiers {
+		if v.Revoked != nil {
+			result.RevokedVerifiers = append(result.RevokedVerifiers, &v.VerifierRef)
+		} else {
+			result.Verifiers = append(result.Verifiers, &v.VerifierRef)
+		}
+	}
+	return result, nil
 }
 
 func (nm *networkMap) GetIdentityByIDWithVerifiers(ctx context.Context, id string) (*core.IdentityWithVerifiers, error) {
@@ -225,3 +229,22 @@ func (nm *networkMap) GetVerifierByHash(ctx context.Context, hash string) (*core
 func (nm *networkMap) GetVerifiers(ctx context.Context, filter ffapi.AndFilter) ([]*core.Verifier, *ffapi.FilterResult, error) {
 	return nm.database.GetVerifiers(ctx, nm.namespace, filter)
 }
+
+func (nm *networkMap) GetOrgApplicationByID(ctx context.Context, id string) (*core.OrgApplication, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	application, err := nm.database.GetOrgApplicationByID(ctx, nm.namespace, u)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return application, nil
+}
+
+func (nm *networkMap) GetOrgApplications(ctx context.Context, filter ffapi.AndFilter) ([]*core.OrgApplication, *ffapi.FilterResult, error) {
+	return nm.database.GetOrgApplications(ctx, nm.namespace, filter)
+}
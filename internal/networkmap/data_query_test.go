@@ -213,10 +213,17 @@ func TestGetIdentityByIDWithVerifiers(t *testing.T) {
 			Type:  core.VerifierTypeEthAddress,
 			Value: "0x12345",
 		}, Identity: id},
+		{Hash: fftypes.NewRandB32(), VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x23456",
+		}, Identity: id, Revoked: fftypes.Now()},
 	}, nil, nil)
 	identity, err := nm.GetIdentityByIDWithVerifiers(nm.ctx, id.String())
 	assert.NoError(t, err)
+	assert.Len(t, identity.Verifiers, 1)
 	assert.Equal(t, "0x12345", identity.Verifiers[0].Value)
+	assert.Len(t, identity.RevokedVerifiers, 1)
+	assert.Equal(t, "0x23456", identity.RevokedVerifiers[0].Value)
 }
 
 func TestGetIdentityByIDWithVerifiersFail(t *testing.T) {
@@ -394,3 +401,48 @@ func TestGetIdentitiesWithVerifiersFailEnrich(t *testing.T) {
 	_, _, err := nm.GetOrganizationsWithVerifiers(nm.ctx, database.IdentityQueryFactory.NewFilter(nm.ctx).And())
 	assert.Regexp(t, "pop", err)
 }
+
+func TestGetOrgApplicationByIDOk(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	nm.database.(*databasemocks.Plugin).On("GetOrgApplicationByID", nm.ctx, "ns1", id).
+		Return(&core.OrgApplication{ID: id, Namespace: "ns1"}, nil)
+	res, err := nm.GetOrgApplicationByID(nm.ctx, id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *res.ID)
+}
+
+func TestGetOrgApplicationByIDNotFound(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	nm.database.(*databasemocks.Plugin).On("GetOrgApplicationByID", nm.ctx, "ns1", id).Return(nil, nil)
+	_, err := nm.GetOrgApplicationByID(nm.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetOrgApplicationByIDError(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	nm.database.(*databasemocks.Plugin).On("GetOrgApplicationByID", nm.ctx, "ns1", id).Return(nil, fmt.Errorf("pop"))
+	_, err := nm.GetOrgApplicationByID(nm.ctx, id.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetOrgApplicationByIDBadUUID(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	_, err := nm.GetOrgApplicationByID(nm.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetOrgApplications(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.database.(*databasemocks.Plugin).On("GetOrgApplications", nm.ctx, "ns1", mock.Anything).Return([]*core.OrgApplication{}, nil, nil)
+	res, _, err := nm.GetOrgApplications(nm.ctx, database.OrgApplicationQueryFactory.NewFilter(nm.ctx).And())
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
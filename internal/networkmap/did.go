@@ -18,9 +18,12 @@ package networkmap
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
@@ -30,7 +33,9 @@ type DIDDocument struct {
 	Context             []string              `ffstruct:"DIDDocument" json:"@context"`
 	ID                  string                `ffstruct:"DIDDocument" json:"id"`
 	Authentication      []string              `ffstruct:"DIDDocument" json:"authentication"`
+	KeyAgreement        []string              `ffstruct:"DIDDocument" json:"keyAgreement,omitempty"`
 	VerificationMethods []*VerificationMethod `ffstruct:"DIDDocument" json:"verificationMethod"`
+	Deactivated         bool                  `ffstruct:"DIDDocument" json:"deactivated,omitempty"`
 }
 
 type VerificationMethod struct {
@@ -41,6 +46,9 @@ type VerificationMethod struct {
 	BlockchainAccountID string `ffstruct:"DIDVerificationMethod" json:"blockchainAcountId,omitempty"`
 	MSPIdentityString   string `ffstruct:"DIDVerificationMethod" json:"mspIdentityString,omitempty"`
 	DataExchangePeerID  string `ffstruct:"DIDVerificationMethod" json:"dataExchangePeerID,omitempty"`
+	PublicKeyMultibase  string `ffstruct:"DIDVerificationMethod" json:"publicKeyMultibase,omitempty"`
+	// Set when the verifier has been revoked, in which case it cannot be used for authentication or key agreement
+	Revoked *fftypes.FFTime `ffstruct:"DIDVerificationMethod" json:"revoked,omitempty"`
 }
 
 func (nm *networkMap) generateDIDDocument(ctx context.Context, identity *core.Identity) (doc *DIDDocument, err error) {
@@ -59,15 +67,31 @@ func (nm *networkMap) generateDIDDocument(ctx context.Context, identity *core.Id
 			"https://www.w3.org/ns/did/v1",
 			"https://w3id.org/security/suites/ed25519-2020/v1",
 		},
-		ID: identity.DID,
+		ID:          identity.DID,
+		Deactivated: identity.Revoked != nil,
 	}
 	doc.VerificationMethods = make([]*VerificationMethod, 0, len(verifiers))
 	doc.Authentication = make([]string, 0, len(verifiers))
 	for _, verifier := range verifiers {
+		if verifier.Type == core.VerifierTypeX25519PublicKey {
+			// Encryption keys are for key agreement, rather than authentication
+			if vm := nm.generateX25519KeyAgreement(ctx, identity, verifier); vm != nil {
+				vm.Revoked = verifier.Revoked
+				doc.VerificationMethods = append(doc.VerificationMethods, vm)
+				if verifier.Revoked == nil {
+					doc.KeyAgreement = append(doc.KeyAgreement, fmt.Sprintf("#%s", verifier.Hash.String()))
+				}
+			}
+			continue
+		}
 		vm := nm.generateDIDAuthentication(ctx, identity, verifier)
 		if vm != nil {
+			// Revoked verifiers remain listed, but are no longer valid for authentication
+			vm.Revoked = verifier.Revoked
 			doc.VerificationMethods = append(doc.VerificationMethods, vm)
-			doc.Authentication = append(doc.Authentication, fmt.Sprintf("#%s", verifier.Hash.String()))
+			if verifier.Revoked == nil {
+				doc.Authentication = append(doc.Authentication, fmt.Sprintf("#%s", verifier.Hash.String()))
+			}
 		}
 	}
 	return doc, nil
@@ -113,3 +137,18 @@ func (nm *networkMap) generateDXPeerIDVerifier(identity *core.Identity, verifier
 		DataExchangePeerID: verifier.Value,
 	}
 }
+
+func (nm *networkMap) generateX25519KeyAgreement(ctx context.Context, identity *core.Identity, verifier *core.Verifier) *VerificationMethod {
+	publicKey, err := msgcrypt.ParsePublicKey(ctx, verifier.Value)
+	if err != nil {
+		log.L(ctx).Warnf("Invalid X25519 public key on verifier '%s' of DID '%s' (%s) - cannot add to DID document", verifier.Value, identity.DID, identity.ID)
+		return nil
+	}
+	// The key is prefixed with the X25519 multicodec (0xec01), and multibase 'm' is base64 without padding
+	return &VerificationMethod{
+		ID:                 verifier.Hash.String(),
+		Type:               "X25519KeyAgreementKey2020",
+		Controller:         identity.DID,
+		PublicKeyMultibase: "m" + base64.RawStdEncoding.EncodeToString(append([]byte{0xec, 0x01}, publicKey[:]...)),
+	}
+}
@@ -61,6 +61,24 @@ func TestDIDGenerationOK(t *testing.T) {
 		},
 		Created: fftypes.Now(),
 	}).Seal()
+	verifierX25519 := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeX25519PublicKey,
+			Value: "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=",
+		},
+		Created: fftypes.Now(),
+	}).Seal()
+	verifierX25519Bad := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeX25519PublicKey,
+			Value: "!!!",
+		},
+		Created: fftypes.Now(),
+	}).Seal()
 	verifierUnknown := (&core.Verifier{
 		Identity:  org1.ID,
 		Namespace: org1.Namespace,
@@ -77,6 +95,8 @@ func TestDIDGenerationOK(t *testing.T) {
 		verifierEth,
 		verifierMSP,
 		verifierDX,
+		verifierX25519,
+		verifierX25519Bad,
 		verifierUnknown,
 	}, nil, nil)
 
@@ -107,17 +127,73 @@ func TestDIDGenerationOK(t *testing.T) {
 				Controller:         org1.DID,
 				DataExchangePeerID: verifierDX.Value,
 			},
+			{
+				ID:                 verifierX25519.Hash.String(),
+				Type:               "X25519KeyAgreementKey2020",
+				Controller:         org1.DID,
+				PublicKeyMultibase: "m7AGFIPAJiTCnVHSLfdy0PvdaDb86DSY4GvTrpKmOqptOag",
+			},
 		},
 		Authentication: []string{
 			fmt.Sprintf("#%s", verifierEth.Hash.String()),
 			fmt.Sprintf("#%s", verifierMSP.Hash.String()),
 			fmt.Sprintf("#%s", verifierDX.Hash.String()),
 		},
+		KeyAgreement: []string{
+			fmt.Sprintf("#%s", verifierX25519.Hash.String()),
+		},
 	}, doc)
 
 	mdi.AssertExpectations(t)
 }
 
+func TestDIDGenerationRevoked(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	org1.Revoked = fftypes.Now()
+
+	verifierEth := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0xc90d94dE1021fD17fAA2F1FC4F4D36Dff176120d",
+		},
+		Created: fftypes.Now(),
+		Revoked: fftypes.Now(),
+	}).Seal()
+	verifierX25519 := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeX25519PublicKey,
+			Value: "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=",
+		},
+		Created: fftypes.Now(),
+		Revoked: fftypes.Now(),
+	}).Seal()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{
+		verifierEth,
+		verifierX25519,
+	}, nil, nil)
+
+	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.True(t, doc.Deactivated)
+	assert.Len(t, doc.VerificationMethods, 2)
+	assert.Equal(t, verifierEth.Revoked, doc.VerificationMethods[0].Revoked)
+	assert.Equal(t, verifierX25519.Revoked, doc.VerificationMethods[1].Revoked)
+	assert.Empty(t, doc.Authentication)
+	assert.Empty(t, doc.KeyAgreement)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestDIDGenerationGetVerifiersFail(t *testing.T) {
 	nm, cancel := newTestNetworkmap(t)
 	defer cancel()
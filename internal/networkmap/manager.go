@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/firefly/internal/definitions"
 	"github.com/hyperledger/firefly/internal/identity"
 	"github.com/hyperledger/firefly/internal/multiparty"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -37,6 +38,10 @@ type Manager interface {
 	RegisterNodeOrganization(ctx context.Context, waitConfirm bool) (org *core.Identity, err error)
 	RegisterIdentity(ctx context.Context, dto *core.IdentityCreateDTO, waitConfirm bool) (identity *core.Identity, err error)
 	UpdateIdentity(ctx context.Context, id string, dto *core.IdentityUpdateDTO, waitConfirm bool) (identity *core.Identity, err error)
+	RevokeIdentity(ctx context.Context, id string, dto *core.IdentityRevokeDTO, waitConfirm bool) (identity *core.Identity, err error)
+	RotateIdentityKey(ctx context.Context, id string, dto *core.IdentityKeyRotationDTO, waitConfirm bool) (identity *core.Identity, err error)
+	SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) (*core.OrgApplication, error)
+	ReviewOrgApplication(ctx context.Context, id string, review *core.OrgApplicationReview, waitConfirm bool) (*core.OrgApplication, error)
 
 	GetOrganizationByNameOrID(ctx context.Context, nameOrID string) (*core.Identity, error)
 	GetOrganizations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Identity, *ffapi.FilterResult, error)
@@ -54,6 +59,8 @@ type Manager interface {
 	GetVerifierByHash(ctx context.Context, hash string) (*core.Verifier, error)
 	GetDIDDocForIndentityByID(ctx context.Context, id string) (*DIDDocument, error)
 	GetDIDDocForIndentityByDID(ctx context.Context, did string) (*DIDDocument, error)
+	GetOrgApplicationByID(ctx context.Context, id string) (*core.OrgApplication, error)
+	GetOrgApplications(ctx context.Context, filter ffapi.AndFilter) ([]*core.OrgApplication, *ffapi.FilterResult, error)
 }
 
 type networkMap struct {
@@ -65,6 +72,7 @@ type networkMap struct {
 	identity   identity.Manager
 	syncasync  syncasync.Bridge
 	multiparty multiparty.Manager // optional
+	encryption *msgcrypt.LocalKey // optional
 }
 
 func NewNetworkMap(ctx context.Context, ns string, di database.Plugin, dx dataexchange.Plugin, ds definitions.Sender, im identity.Manager, sa syncasync.Bridge, mm multiparty.Manager) (Manager, error) {
@@ -82,5 +90,10 @@ func NewNetworkMap(ctx context.Context, ns string, di database.Plugin, dx dataex
 		syncasync:  sa,
 		multiparty: mm,
 	}
+
+	var err error
+	if nm.encryption, err = msgcrypt.LoadLocalKey(ctx); err != nil {
+		return nil, err
+	}
 	return nm, nil
 }
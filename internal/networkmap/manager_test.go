@@ -18,9 +18,13 @@ package networkmap
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
@@ -45,6 +49,25 @@ func newTestNetworkmap(t *testing.T) (*networkMap, func()) {
 
 }
 
+func setTestEncryptionKey(t *testing.T, nm *networkMap) {
+	path := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(path, []byte("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"), 0600)
+	assert.NoError(t, err)
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, path)
+	nm.encryption, err = msgcrypt.LoadLocalKey(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewNetworkMapEncryptionKeyFail(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, filepath.Join(t.TempDir(), "missing"))
+	_, err := NewNetworkMap(context.Background(), "ns1", &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, &definitionsmocks.Sender{},
+		&identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, &multipartymocks.Manager{})
+	assert.Regexp(t, "FF10532", err)
+}
+
 func TestNewNetworkMapMissingDep(t *testing.T) {
 	_, err := NewNetworkMap(context.Background(), "", nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
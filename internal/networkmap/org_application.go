@@ -0,0 +1,98 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// SubmitOrgApplication applies for the org and node configured on this node to join the network, under the
+// sponsorship of an existing root org. Nothing is registered (and the DX peer is not added) until the sponsor
+// approves the application.
+func (nm *networkMap) SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) (*core.OrgApplication, error) {
+	application.ID = fftypes.NewUUID()
+	application.Org.ID = fftypes.NewUUID()
+	application.Node.ID = fftypes.NewUUID()
+
+	if application.Org.Name == "" {
+		application.Org.Name = nm.multiparty.RootOrg().Name
+		application.Org.Description = nm.multiparty.RootOrg().Description
+	}
+	if application.Node.Name == "" {
+		application.Node.Name = nm.multiparty.LocalNode().Name
+		application.Node.Description = nm.multiparty.LocalNode().Description
+	}
+	if application.Key == "" {
+		key, err := nm.identity.GetMultipartyRootVerifier(ctx)
+		if err != nil {
+			return nil, err
+		}
+		application.Key = key.Value
+	}
+
+	// The DX peer info is always that of this node, as the application is the only way it is shared
+	profile, err := nm.exchange.GetEndpointInfo(ctx, application.Node.Name)
+	if err != nil {
+		return nil, err
+	}
+	application.Node.Profile = nm.addEncryptionKey(profile)
+
+	if err := application.Validate(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := nm.defsender.SubmitOrgApplication(ctx, application, waitConfirm); err != nil {
+		return nil, err
+	}
+	if waitConfirm {
+		return nm.GetOrgApplicationByID(ctx, application.ID.String())
+	}
+	return application, nil
+}
+
+// ReviewOrgApplication approves or rejects a pending application that names the root org of this node as its sponsor
+func (nm *networkMap) ReviewOrgApplication(ctx context.Context, id string, review *core.OrgApplicationReview, waitConfirm bool) (*core.OrgApplication, error) {
+	application, err := nm.GetOrgApplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.State != core.OrgApplicationStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgOrgApplicationClosed, application.ID, application.State)
+	}
+
+	org, err := nm.identity.GetMultipartyRootOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if org.DID != application.Sponsor {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNotOrgApplicationSponsor, application.ID, application.Sponsor)
+	}
+
+	review.Application = application.ID
+	if err := nm.defsender.ReviewOrgApplication(ctx, review, waitConfirm); err != nil {
+		return nil, err
+	}
+	if waitConfirm {
+		return nm.GetOrgApplicationByID(ctx, id)
+	}
+	return application, nil
+}
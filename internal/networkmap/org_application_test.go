@@ -0,0 +1,321 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/multiparty"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/definitionsmocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testPendingOrgApplication() *core.OrgApplication {
+	return &core.OrgApplication{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Sponsor:   "did:firefly:org/org1",
+		Org: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "org2",
+		},
+		Node: core.OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "node2",
+		},
+		Key:   "0x23456",
+		State: core.OrgApplicationStatePending,
+	}
+}
+
+func TestSubmitOrgApplicationDefaults(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	setTestEncryptionKey(t, nm)
+
+	mmp := nm.multiparty.(*multipartymocks.Manager)
+	mmp.On("RootOrg").Return(multiparty.RootOrg{Name: "org2", Description: "org desc"})
+	mmp.On("LocalNode").Return(multiparty.LocalNode{Name: "node2", Description: "node desc"})
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootVerifier", nm.ctx).Return(&core.VerifierRef{Value: "0x23456"}, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node2").Return(fftypes.JSONObject{"id": "peer2"}, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("SubmitOrgApplication", nm.ctx, mock.MatchedBy(func(application *core.OrgApplication) bool {
+		return application.Sponsor == "org1" &&
+			application.Org.Name == "org2" && application.Org.Description == "org desc" &&
+			application.Node.Name == "node2" && application.Node.Description == "node desc" &&
+			application.Node.Profile.GetString("id") == "peer2" &&
+			application.Node.Profile.GetString(msgcrypt.ProfileKey) != "" &&
+			application.Key == "0x23456"
+	}), false).Return(nil)
+
+	application, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{Sponsor: "org1"}, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, application.ID)
+	assert.NotNil(t, application.Org.ID)
+	assert.NotNil(t, application.Node.ID)
+
+	mmp.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationConfirm(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node2").Return(fftypes.JSONObject{"id": "peer2"}, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("SubmitOrgApplication", nm.ctx, mock.Anything, true).Return(nil)
+
+	stored := testPendingOrgApplication()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", mock.Anything).Return(stored, nil)
+
+	application, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{
+		Sponsor: "org1",
+		Org:     core.OrgApplicationIdentity{Name: "org2"},
+		Node:    core.OrgApplicationIdentity{Name: "node2"},
+		Key:     "0x23456",
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, application)
+
+	mdx.AssertExpectations(t)
+	mds.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationSendFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node2").Return(fftypes.JSONObject{"id": "peer2"}, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("SubmitOrgApplication", nm.ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{
+		Sponsor: "org1",
+		Org:     core.OrgApplicationIdentity{Name: "org2"},
+		Node:    core.OrgApplicationIdentity{Name: "node2"},
+		Key:     "0x23456",
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationInvalid(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node2").Return(fftypes.JSONObject{"id": "peer2"}, nil)
+
+	_, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{
+		Org:  core.OrgApplicationIdentity{Name: "org2"},
+		Node: core.OrgApplicationIdentity{Name: "node2"},
+		Key:  "0x23456",
+	}, false)
+	assert.Regexp(t, "FF00112", err)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationEndpointFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node2").Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{
+		Sponsor: "org1",
+		Org:     core.OrgApplicationIdentity{Name: "org2"},
+		Node:    core.OrgApplicationIdentity{Name: "node2"},
+		Key:     "0x23456",
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+}
+
+func TestSubmitOrgApplicationRootVerifierFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootVerifier", nm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.SubmitOrgApplication(nm.ctx, &core.OrgApplication{
+		Sponsor: "org1",
+		Org:     core.OrgApplicationIdentity{Name: "org2"},
+		Node:    core.OrgApplicationIdentity{Name: "node2"},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestReviewOrgApplicationOk(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil)
+
+	org1 := testOrg("org1")
+	org1.DID = "did:firefly:org/org1"
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(org1, nil)
+
+	review := &core.OrgApplicationReview{Approve: true}
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ReviewOrgApplication", nm.ctx, review, false).Return(nil)
+
+	result, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), review, false)
+	assert.NoError(t, err)
+	assert.Equal(t, application, result)
+	assert.Equal(t, application.ID, review.Application)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestReviewOrgApplicationConfirm(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	approved := testPendingOrgApplication()
+	approved.State = core.OrgApplicationStateApproved
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil).Once()
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(approved, nil).Once()
+
+	org1 := testOrg("org1")
+	org1.DID = "did:firefly:org/org1"
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(org1, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ReviewOrgApplication", nm.ctx, mock.Anything, true).Return(nil)
+
+	result, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), &core.OrgApplicationReview{Approve: true}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, approved, result)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestReviewOrgApplicationSendFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil)
+
+	org1 := testOrg("org1")
+	org1.DID = "did:firefly:org/org1"
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(org1, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ReviewOrgApplication", nm.ctx, mock.Anything, false).Return(fmt.Errorf("pop"))
+
+	_, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), &core.OrgApplicationReview{}, false)
+	assert.EqualError(t, err, "pop")
+
+	mds.AssertExpectations(t)
+}
+
+func TestReviewOrgApplicationNotSponsor(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil)
+
+	org3 := testOrg("org3")
+	org3.DID = "did:firefly:org/org3"
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(org3, nil)
+
+	_, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), &core.OrgApplicationReview{}, false)
+	assert.Regexp(t, "FF10571", err)
+}
+
+func TestReviewOrgApplicationRootOrgFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil)
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), &core.OrgApplicationReview{}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReviewOrgApplicationClosed(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	application := testPendingOrgApplication()
+	application.State = core.OrgApplicationStateRejected
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetOrgApplicationByID", nm.ctx, "ns1", application.ID).Return(application, nil)
+
+	_, err := nm.ReviewOrgApplication(nm.ctx, application.ID.String(), &core.OrgApplicationReview{}, false)
+	assert.Regexp(t, "FF10570", err)
+}
+
+func TestReviewOrgApplicationNotFound(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.ReviewOrgApplication(nm.ctx, "bad", &core.OrgApplicationReview{}, false)
+	assert.Regexp(t, "FF00138", err)
+}
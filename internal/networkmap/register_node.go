@@ -19,8 +19,10 @@ package networkmap
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
@@ -48,6 +50,20 @@ func (nm *networkMap) RegisterNode(ctx context.Context, waitConfirm bool) (ident
 	if err != nil {
 		return nil, err
 	}
+	nodeRequest.Profile = nm.addEncryptionKey(nodeRequest.Profile)
 
 	return nm.RegisterIdentity(ctx, nodeRequest, waitConfirm)
 }
+
+// addEncryptionKey publishes the public key that other nodes use to encrypt private messages
+// for this node, when end-to-end encryption is enabled
+func (nm *networkMap) addEncryptionKey(profile fftypes.JSONObject) fftypes.JSONObject {
+	if nm.encryption == nil {
+		return profile
+	}
+	if profile == nil {
+		profile = fftypes.JSONObject{}
+	}
+	profile[msgcrypt.ProfileKey] = nm.encryption.PublicKey()
+	return profile
+}
@@ -70,6 +70,44 @@ func TestRegisterNodeOk(t *testing.T) {
 	mmp.AssertExpectations(t)
 }
 
+func TestRegisterNodeWithEncryptionKey(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	setTestEncryptionKey(t, nm)
+
+	parentOrg := testOrg("org1")
+	signerRef := &core.SignerRef{Key: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetMultipartyRootOrg", nm.ctx).Return(parentOrg, nil)
+	mim.On("VerifyIdentityChain", nm.ctx, mock.AnythingOfType("*core.Identity")).Return(parentOrg, false, nil)
+	mim.On("ResolveIdentitySigner", nm.ctx, parentOrg).Return(signerRef, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node1").Return(nil, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ClaimIdentity", nm.ctx,
+		mock.MatchedBy(func(claim *core.IdentityClaim) bool {
+			return claim.Identity.Profile.GetString("encryptionPublicKey") == nm.encryption.PublicKey()
+		}),
+		signerRef,
+		(*core.SignerRef)(nil),
+	).Return(nil)
+
+	mmp := nm.multiparty.(*multipartymocks.Manager)
+	mmp.On("LocalNode").Return(multiparty.LocalNode{Name: "node1"})
+
+	node, err := nm.RegisterNode(nm.ctx, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, node)
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
 func TestRegisterNodeMissingName(t *testing.T) {
 
 	nm, cancel := newTestNetworkmap(t)
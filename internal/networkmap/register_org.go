@@ -48,5 +48,15 @@ func (nm *networkMap) RegisterNodeOrganization(ctx context.Context, waitConfirm
 
 func (nm *networkMap) RegisterOrganization(ctx context.Context, orgRequest *core.IdentityCreateDTO, waitConfirm bool) (*core.Identity, error) {
 	orgRequest.Type = core.IdentityTypeOrg
+	if orgRequest.Parent == "" {
+		// Once org onboarding is enabled, the claim would be rejected by every member of the network
+		governance, err := nm.database.GetNetworkGovernance(ctx, nm.namespace)
+		if err != nil {
+			return nil, err
+		}
+		if governance != nil && governance.OrgOnboarding {
+			return nil, i18n.NewError(ctx, coremsgs.MsgOrgOnboardingRequired, nm.namespace)
+		}
+	}
 	return nm.RegisterIdentity(ctx, orgRequest, waitConfirm)
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/multiparty"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
@@ -67,6 +68,9 @@ func TestRegisterNodeOrgOk(t *testing.T) {
 	mmp := nm.multiparty.(*multipartymocks.Manager)
 	mmp.On("RootOrg").Return(multiparty.RootOrg{Name: "org0"})
 
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNetworkGovernance", nm.ctx, "ns1").Return(nil, nil)
+
 	mds := nm.defsender.(*definitionsmocks.Sender)
 	mds.On("ClaimIdentity", nm.ctx,
 		mock.AnythingOfType("*core.IdentityClaim"),
@@ -83,6 +87,7 @@ func TestRegisterNodeOrgOk(t *testing.T) {
 	mim.AssertExpectations(t)
 	mds.AssertExpectations(t)
 	mmp.AssertExpectations(t)
+	mdi.AssertExpectations(t)
 }
 
 func TestRegisterNodeOrgNoName(t *testing.T) {
@@ -117,3 +122,31 @@ func TestRegisterNodeGetOwnerBlockchainKeyFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 }
+
+func TestRegisterOrgOnboardingRequired(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNetworkGovernance", nm.ctx, "ns1").Return(&core.NetworkGovernance{OrgOnboarding: true}, nil)
+
+	_, err := nm.RegisterOrganization(nm.ctx, &core.IdentityCreateDTO{Name: "org1", Key: "0x12345"}, false)
+	assert.Regexp(t, "FF10576", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRegisterOrgGovernanceFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetNetworkGovernance", nm.ctx, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RegisterOrganization(nm.ctx, &core.IdentityCreateDTO{Name: "org1", Key: "0x12345"}, false)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// resolveIdentityForChange gets an active identity in this namespace, along with the signer that must
+// sign any change to it - which is the same as the signer of its original claim
+func (nm *networkMap) resolveIdentityForChange(ctx context.Context, uuidStr string) (identity *core.Identity, signer *core.SignerRef, err error) {
+	id, err := fftypes.ParseUUID(ctx, uuidStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity, err = nm.identity.CachedIdentityLookupByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if identity == nil || identity.Namespace != nm.namespace {
+		return nil, nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	if identity.Revoked != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgIdentityRevoked, identity.DID, identity.ID)
+	}
+	if nm.multiparty != nil {
+		if signer, err = nm.identity.ResolveIdentitySigner(ctx, identity); err != nil {
+			return nil, nil, err
+		}
+	}
+	return identity, signer, nil
+}
+
+func (nm *networkMap) RevokeIdentity(ctx context.Context, uuidStr string, dto *core.IdentityRevokeDTO, waitConfirm bool) (*core.Identity, error) {
+	identity, signer, err := nm.resolveIdentityForChange(ctx, uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	revocation := &core.IdentityRevocation{
+		Identity: identity.IdentityBase,
+	}
+	if dto.Key != "" {
+		// Only the specified key is revoked, which must be an active verifier of the identity
+		verifierRef, err := nm.identity.ResolveInputVerifierRef(ctx, &core.VerifierRef{Value: dto.Key}, blockchain.ResolveKeyIntentLookup)
+		if err != nil {
+			return nil, err
+		}
+		verifier, err := nm.identity.CachedVerifierLookup(ctx, verifierRef)
+		if err != nil {
+			return nil, err
+		}
+		if verifier == nil || !verifier.Identity.Equals(identity.ID) || verifier.Revoked != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgIdentityKeyNotActive, verifierRef.Value, identity.DID)
+		}
+		revocation.Verifier = verifierRef
+	}
+
+	err = nm.defsender.RevokeIdentity(ctx, revocation, signer, waitConfirm)
+	return identity, err
+}
+
+func (nm *networkMap) RotateIdentityKey(ctx context.Context, uuidStr string, dto *core.IdentityKeyRotationDTO, waitConfirm bool) (*core.Identity, error) {
+	identity, signer, err := nm.resolveIdentityForChange(ctx, uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	verifierRef, err := nm.identity.ResolveInputVerifierRef(ctx, &core.VerifierRef{Value: dto.Key}, blockchain.ResolveKeyIntentSign)
+	if err != nil {
+		return nil, err
+	}
+
+	err = nm.defsender.RotateIdentityKey(ctx, &core.IdentityKeyRotation{
+		Identity: identity.IdentityBase,
+		Verifier: *verifierRef,
+	}, signer, waitConfirm)
+	return identity, err
+}
@@ -0,0 +1,271 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/definitionsmocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevokeIdentityOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	signerRef := &core.SignerRef{Key: "0x12345"}
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(signerRef, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("RevokeIdentity", nm.ctx, mock.MatchedBy(func(ir *core.IdentityRevocation) bool {
+		return ir.Identity.ID.Equals(identity.ID) && ir.Verifier == nil
+	}), signerRef, true).Return(nil)
+
+	org, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, identity, org)
+
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestRevokeIdentityKeyOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, &core.VerifierRef{Value: "0x23456"}, blockchain.ResolveKeyIntentLookup).Return(verifierRef, nil)
+	mim.On("CachedVerifierLookup", nm.ctx, verifierRef).Return(&core.Verifier{
+		Identity:    identity.ID,
+		VerifierRef: *verifierRef,
+	}, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("RevokeIdentity", nm.ctx, mock.MatchedBy(func(ir *core.IdentityRevocation) bool {
+		return ir.Verifier == verifierRef
+	}), (*core.SignerRef)(nil), false).Return(nil)
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{Key: "0x23456"}, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestRevokeIdentityKeyNotActive(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentLookup).Return(verifierRef, nil)
+	mim.On("CachedVerifierLookup", nm.ctx, verifierRef).Return(nil, nil)
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{Key: "0x23456"}, false)
+	assert.Regexp(t, "FF10556", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityKeyLookupFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentLookup).Return(verifierRef, nil)
+	mim.On("CachedVerifierLookup", nm.ctx, verifierRef).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{Key: "0x23456"}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityKeyResolveFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentLookup).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{Key: "0x23456"}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityAlreadyRevoked(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	identity.Revoked = fftypes.Now()
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{}, false)
+	assert.Regexp(t, "FF10553", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentitySignerFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityNotFound(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(nil, nil)
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{}, false)
+	assert.Regexp(t, "FF10143", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityLookupFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RevokeIdentity(nm.ctx, identity.ID.String(), &core.IdentityRevokeDTO{}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRevokeIdentityBadID(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.RevokeIdentity(nm.ctx, "badness", &core.IdentityRevokeDTO{}, true)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestRotateIdentityKeyOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	verifierRef := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	signerRef := &core.SignerRef{Key: "0x12345"}
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(signerRef, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, &core.VerifierRef{Value: "0x23456"}, blockchain.ResolveKeyIntentSign).Return(verifierRef, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("RotateIdentityKey", nm.ctx, mock.MatchedBy(func(ik *core.IdentityKeyRotation) bool {
+		return ik.Identity.ID.Equals(identity.ID) && ik.Verifier == *verifierRef
+	}), signerRef, true).Return(nil)
+
+	org, err := nm.RotateIdentityKey(nm.ctx, identity.ID.String(), &core.IdentityKeyRotationDTO{Key: "0x23456"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, identity, org)
+
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestRotateIdentityKeyResolveFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentSign).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.RotateIdentityKey(nm.ctx, identity.ID.String(), &core.IdentityKeyRotationDTO{Key: "0x23456"}, true)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRotateIdentityKeyBadID(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.RotateIdentityKey(nm.ctx, "badness", &core.IdentityKeyRotationDTO{}, true)
+	assert.Regexp(t, "FF00138", err)
+}
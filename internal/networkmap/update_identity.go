@@ -54,6 +54,11 @@ func (nm *networkMap) updateIdentityID(ctx context.Context, id *fftypes.UUID, dt
 		}
 	}
 
+	if identity.Type == core.IdentityTypeNode && nm.multiparty != nil && identity.Name == nm.multiparty.LocalNode().Name {
+		// Updates to the local node always carry its current encryption key, so a rotated key is published
+		dto.Profile = nm.addEncryptionKey(dto.Profile)
+	}
+
 	identity.IdentityProfile = dto.IdentityProfile
 	if err := identity.Validate(ctx); err != nil {
 		return nil, err
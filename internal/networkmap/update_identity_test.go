@@ -21,8 +21,10 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -63,6 +65,48 @@ func TestUpdateIdentityProfileOk(t *testing.T) {
 	mds.AssertExpectations(t)
 }
 
+func TestUpdateIdentityLocalNodeEncryptionKey(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	setTestEncryptionKey(t, nm)
+
+	identity := testOrg("node1")
+	identity.Type = core.IdentityTypeNode
+	identity.Parent = fftypes.NewUUID()
+	identity.DID, _ = identity.GenerateDID(nm.ctx)
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	signerRef := &core.SignerRef{Key: "0x12345"}
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(signerRef, nil)
+
+	mmp := nm.multiparty.(*multipartymocks.Manager)
+	mmp.On("LocalNode").Return(multiparty.LocalNode{Name: "node1"})
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("UpdateIdentity", nm.ctx,
+		mock.AnythingOfType("*core.Identity"),
+		mock.MatchedBy(func(update *core.IdentityUpdate) bool {
+			return update.Updates.Profile.GetString("id") == "peer1" &&
+				update.Updates.Profile.GetString("encryptionPublicKey") == nm.encryption.PublicKey()
+		}),
+		signerRef,
+		true).Return(nil)
+
+	node, err := nm.UpdateIdentity(nm.ctx, identity.ID.String(), &core.IdentityUpdateDTO{
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{"id": "peer1"},
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, node)
+
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+}
+
 func TestUpdateIdentityProfileBroadcastFail(t *testing.T) {
 
 	nm, cancel := newTestNetworkmap(t)
@@ -52,11 +52,11 @@ func (bc *boundCallbacks) SharedStorageBatchDownloaded(payloadRef string, data [
 	return bc.o.events.SharedStorageBatchDownloaded(bc.o.sharedstorage(), payloadRef, data)
 }
 
-func (bc *boundCallbacks) SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error {
+func (bc *boundCallbacks) SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID, encryption *core.BlobEncryption) error {
 	if err := bc.checkStopped(); err != nil {
 		return err
 	}
-	return bc.o.events.SharedStorageBlobDownloaded(bc.o.sharedstorage(), hash, size, payloadRef, dataID)
+	return bc.o.events.SharedStorageBlobDownloaded(bc.o.sharedstorage(), hash, size, payloadRef, dataID, encryption)
 }
 
 func (bc *boundCallbacks) BatchPinComplete(namespace string, batch *blockchain.BatchPin, signingKey *core.VerifierRef) error {
@@ -66,11 +66,11 @@ func (bc *boundCallbacks) BatchPinComplete(namespace string, batch *blockchain.B
 	return bc.o.events.BatchPinComplete(namespace, batch, signingKey)
 }
 
-func (bc *boundCallbacks) BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
+func (bc *boundCallbacks) BlockchainNetworkAction(action, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
 	if err := bc.checkStopped(); err != nil {
 		return err
 	}
-	return bc.o.events.BlockchainNetworkAction(action, location, event, signingKey)
+	return bc.o.events.BlockchainNetworkAction(action, payload, location, event, signingKey)
 }
 
 func (bc *boundCallbacks) BlockchainEvent(event *blockchain.EventWithSubscription) error {
@@ -82,16 +82,16 @@ func TestBoundCallbacks(t *testing.T) {
 	_, err := bc.SharedStorageBatchDownloaded("payload1", []byte(`{}`))
 	assert.EqualError(t, err, "pop")
 
-	mei.On("SharedStorageBlobDownloaded", mss, *hash, int64(12345), "payload1", dataID).Return(nil)
-	err = bc.SharedStorageBlobDownloaded(*hash, 12345, "payload1", dataID)
+	mei.On("SharedStorageBlobDownloaded", mss, *hash, int64(12345), "payload1", dataID, (*core.BlobEncryption)(nil)).Return(nil)
+	err = bc.SharedStorageBlobDownloaded(*hash, 12345, "payload1", dataID, nil)
 	assert.NoError(t, err)
 
 	mei.On("BatchPinComplete", "ns1", &blockchain.BatchPin{}, &core.VerifierRef{}).Return(nil)
 	err = bc.BatchPinComplete("ns1", &blockchain.BatchPin{}, &core.VerifierRef{})
 	assert.NoError(t, err)
 
-	mei.On("BlockchainNetworkAction", "action", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{}).Return(nil)
-	err = bc.BlockchainNetworkAction("action", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{})
+	mei.On("BlockchainNetworkAction", "action", "", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{}).Return(nil)
+	err = bc.BlockchainNetworkAction("action", "", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{})
 	assert.NoError(t, err)
 
 	mei.On("BlockchainEvent", &blockchain.EventWithSubscription{}).Return(nil)
@@ -127,13 +127,13 @@ func TestBoundCallbacksStopped(t *testing.T) {
 	_, err := bc.SharedStorageBatchDownloaded("payload1", []byte(`{}`))
 	assert.Regexp(t, "FF10446", err)
 
-	err = bc.SharedStorageBlobDownloaded(*fftypes.NewRandB32(), 12345, "payload1", nil)
+	err = bc.SharedStorageBlobDownloaded(*fftypes.NewRandB32(), 12345, "payload1", nil, nil)
 	assert.Regexp(t, "FF10446", err)
 
 	err = bc.BatchPinComplete("ns1", &blockchain.BatchPin{}, &core.VerifierRef{})
 	assert.Regexp(t, "FF10446", err)
 
-	err = bc.BlockchainNetworkAction("action", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{})
+	err = bc.BlockchainNetworkAction("action", "", fftypes.JSONAnyPtr("{}"), &blockchain.Event{}, &core.VerifierRef{})
 	assert.Regexp(t, "FF10446", err)
 
 	err = bc.BlockchainEvent(&blockchain.EventWithSubscription{})
@@ -113,11 +113,11 @@ func (or *orchestrator) GetDatatypeByID(ctx context.Context, id string) (*core.D
 	return or.database().GetDatatypeByID(ctx, or.namespace.Name, u)
 }
 
-func (or *orchestrator) GetDatatypeByName(ctx context.Context, name, version string) (*core.Datatype, error) {
+func (or *orchestrator) GetDatatypeByName(ctx context.Context, name, version string, group *fftypes.Bytes32) (*core.Datatype, error) {
 	if err := fftypes.ValidateFFNameFieldNoUUID(ctx, name, "name"); err != nil {
 		return nil, err
 	}
-	return or.database().GetDatatypeByName(ctx, or.namespace.Name, name, version)
+	return or.database().GetDatatypeByName(ctx, or.namespace.Name, name, version, group)
 }
 
 func (or *orchestrator) GetOperationByID(ctx context.Context, id string) (*core.Operation, error) {
@@ -255,6 +255,16 @@ func (or *orchestrator) GetMessageEvents(ctx context.Context, id string, filter
 	return or.database().GetEvents(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) GetMessageReplies(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil || msg == nil {
+		return nil, nil, err
+	}
+	// Replies are correlated to the request by setting their cid to the ID of the request
+	filter = filter.Condition(filter.Builder().Eq("cid", msg.Header.ID))
+	return or.database().GetMessages(ctx, or.namespace.Name, filter)
+}
+
 func (or *orchestrator) GetBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.BatchPersisted, *ffapi.FilterResult, error) {
 	return or.database().GetBatches(ctx, or.namespace.Name, filter)
 }
@@ -279,6 +289,27 @@ func (or *orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return or.database().GetDatatypes(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) GetDefinitionProposalByID(ctx context.Context, id string) (*core.DefinitionProposal, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.database().GetDefinitionProposalByID(ctx, or.namespace.Name, u)
+}
+
+func (or *orchestrator) GetNetworkGovernance(ctx context.Context) (*core.NetworkGovernance, error) {
+	governance, err := or.database().GetNetworkGovernance(ctx, or.namespace.Name)
+	if err != nil || governance != nil {
+		return governance, err
+	}
+	// No governance rules have been confirmed, so definitions are not held for approval
+	return &core.NetworkGovernance{Namespace: or.namespace.Name}, nil
+}
+
+func (or *orchestrator) GetDefinitionProposals(ctx context.Context, filter ffapi.AndFilter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error) {
+	return or.database().GetDefinitionProposals(ctx, or.namespace.Name, filter)
+}
+
 func (or *orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error) {
 	return or.database().GetOperations(ctx, or.namespace.Name, filter)
 }
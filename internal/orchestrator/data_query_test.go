@@ -378,6 +378,40 @@ func TestGetMessageEventsBadMsgID(t *testing.T) {
 	assert.Nil(t, ev)
 }
 
+func TestGetMessageRepliesOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			Namespace: "ns",
+			ID:        fftypes.NewUUID(),
+		},
+	}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", mock.Anything).Return(msg, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("tag", "response"))
+	_, _, err := or.GetMessageReplies(context.Background(), msg.Header.ID.String(), f)
+	assert.NoError(t, err)
+	calculatedFilter, err := or.mdi.Calls[1].Arguments[2].(ffapi.Filter).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(
+		`( tag == 'response' ) && ( cid == '%s' )`,
+		msg.Header.ID,
+	), calculatedFilter.String())
+}
+
+func TestGetMessageRepliesBadMsgID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+	msgs, _, err := or.GetMessageReplies(context.Background(), fftypes.NewUUID().String(), f)
+	assert.Regexp(t, "FF10109", err)
+	assert.Nil(t, msgs)
+}
+
 func TestGetBatchByID(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -467,24 +501,24 @@ func TestGetDatatypeByIDBadID(t *testing.T) {
 func TestGetDatatypeByName(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
-	or.mdi.On("GetDatatypeByName", context.Background(), "ns", "dt", "1").Return(&core.Datatype{
+	or.mdi.On("GetDatatypeByName", context.Background(), "ns", "dt", "1", mock.Anything).Return(&core.Datatype{
 		Namespace: "ns1",
 	}, nil)
-	_, err := or.GetDatatypeByName(context.Background(), "dt", "1")
+	_, err := or.GetDatatypeByName(context.Background(), "dt", "1", nil)
 	assert.NoError(t, err)
 }
 
 func TestGetDatatypeByNameBadNamespace(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
-	_, err := or.GetDatatypeByName(context.Background(), "", "")
+	_, err := or.GetDatatypeByName(context.Background(), "", "", nil)
 	assert.Regexp(t, "FF00140", err)
 }
 
 func TestGetDatatypeByNameBadName(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
-	_, err := or.GetDatatypeByName(context.Background(), "", "")
+	_, err := or.GetDatatypeByName(context.Background(), "", "", nil)
 	assert.Regexp(t, "FF00140", err)
 }
 
@@ -624,6 +658,62 @@ func TestGetDatatypes(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetDefinitionProposalByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDefinitionProposalByID", mock.Anything, "ns", u).Return(&core.DefinitionProposal{
+		Namespace: "ns",
+	}, nil)
+	_, err := or.GetDefinitionProposalByID(context.Background(), u.String())
+	assert.NoError(t, err)
+}
+
+func TestGetDefinitionProposalByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	_, err := or.GetDefinitionProposalByID(context.Background(), "")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetNetworkGovernance(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	governance := &core.NetworkGovernance{Namespace: "ns", DefinitionQuorum: 2}
+	or.mdi.On("GetNetworkGovernance", mock.Anything, "ns").Return(governance, nil)
+	result, err := or.GetNetworkGovernance(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, governance, result)
+}
+
+func TestGetNetworkGovernanceDefault(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetNetworkGovernance", mock.Anything, "ns").Return(nil, nil)
+	result, err := or.GetNetworkGovernance(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "ns", result.Namespace)
+	assert.Zero(t, result.DefinitionQuorum)
+}
+
+func TestGetNetworkGovernanceFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetNetworkGovernance", mock.Anything, "ns").Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetNetworkGovernance(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDefinitionProposals(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetDefinitionProposals", mock.Anything, "ns", mock.Anything).Return([]*core.DefinitionProposal{}, nil, nil)
+	fb := database.DefinitionProposalQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("state", core.DefinitionProposalStateProposed))
+	_, _, err := or.GetDefinitionProposals(context.Background(), f)
+	assert.NoError(t, err)
+}
+
 func TestGetOperations(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
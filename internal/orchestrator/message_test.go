@@ -27,7 +27,7 @@ import (
 func TestRequestReplyMissingGroup(t *testing.T) {
 	or := newTestOrchestrator()
 	input := &core.MessageInOut{}
-	_, err := or.RequestReply(context.Background(), input)
+	_, err := or.RequestReply(context.Background(), input, false)
 	assert.Regexp(t, "FF10271", err)
 }
 
@@ -40,7 +40,22 @@ func TestRequestReply(t *testing.T) {
 			},
 		},
 	}
-	or.mpm.On("RequestReply", context.Background(), input).Return(&core.MessageInOut{}, nil)
-	_, err := or.RequestReply(context.Background(), input)
+	or.mpm.On("RequestReply", context.Background(), input, false).Return(&core.MessageInOut{}, nil)
+	_, err := or.RequestReply(context.Background(), input, false)
 	assert.NoError(t, err)
 }
+
+func TestRequestReplyAsync(t *testing.T) {
+	or := newTestOrchestrator()
+	input := &core.MessageInOut{
+		Group: &core.InputGroup{
+			Members: []core.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	}
+	or.mpm.On("RequestReply", context.Background(), input, true).Return(input, nil)
+	out, err := or.RequestReply(context.Background(), input, true)
+	assert.NoError(t, err)
+	assert.Equal(t, input, out)
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/firefly/internal/networkmap"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/shareddownload"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -83,6 +84,9 @@ type Orchestrator interface {
 	CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	CreateUpdateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	DeleteSubscription(ctx context.Context, id string) error
+	GetSubscriptionDeadLetters(ctx context.Context, subID string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+	ReplaySubscriptionDeadLetter(ctx context.Context, subID, dlID string) (*core.DeadLetter, error)
+	DeleteSubscriptionDeadLetters(ctx context.Context, subID string) error
 
 	// Data Query
 	GetNamespace(ctx context.Context) *core.Namespace
@@ -97,6 +101,7 @@ type Orchestrator interface {
 	GetMessagesWithData(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageInOut, *ffapi.FilterResult, error)
 	GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error)
 	GetMessageEvents(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)
+	GetMessageReplies(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
 	GetMessageData(ctx context.Context, id string) (core.DataArray, error)
 	GetMessagesForData(ctx context.Context, dataID string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
 	GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error)
@@ -105,8 +110,11 @@ type Orchestrator interface {
 	GetData(ctx context.Context, filter ffapi.AndFilter) (core.DataArray, *ffapi.FilterResult, error)
 	GetDataSubPaths(ctx context.Context, path string) ([]string, error)
 	GetDatatypeByID(ctx context.Context, id string) (*core.Datatype, error)
-	GetDatatypeByName(ctx context.Context, name, version string) (*core.Datatype, error)
+	GetDatatypeByName(ctx context.Context, name, version string, group *fftypes.Bytes32) (*core.Datatype, error)
 	GetDatatypes(ctx context.Context, filter ffapi.AndFilter) ([]*core.Datatype, *ffapi.FilterResult, error)
+	GetDefinitionProposalByID(ctx context.Context, id string) (*core.DefinitionProposal, error)
+	GetDefinitionProposals(ctx context.Context, filter ffapi.AndFilter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error)
+	GetNetworkGovernance(ctx context.Context) (*core.NetworkGovernance, error)
 	GetOperationByID(ctx context.Context, id string) (*core.Operation, error)
 	GetOperationByIDWithStatus(ctx context.Context, id string) (*core.OperationWithDetail, error)
 	GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)
@@ -124,7 +132,7 @@ type Orchestrator interface {
 	GetChartHistogram(ctx context.Context, startTime int64, endTime int64, buckets int64, tableName database.CollectionName) ([]*core.ChartHistogram, error)
 
 	// Message Routing
-	RequestReply(ctx context.Context, msg *core.MessageInOut) (reply *core.MessageInOut, err error)
+	RequestReply(ctx context.Context, msg *core.MessageInOut, async bool) (reply *core.MessageInOut, err error)
 
 	// Network Operations
 	SubmitNetworkAction(ctx context.Context, action *core.NetworkAction) error
@@ -184,6 +192,7 @@ type Config struct {
 	KeyNormalization    string
 	Multiparty          multiparty.Config
 	TokenBroadcastNames map[string]string
+	Retention           retention.Config
 }
 
 type orchestrator struct {
@@ -213,6 +222,7 @@ type orchestrator struct {
 	cacheManager   cache.Manager
 	operations     operations.Manager
 	txHelper       txcommon.Helper
+	retention      retention.Manager
 }
 
 func NewOrchestrator(ns *core.Namespace, config Config, plugins *Plugins, metrics metrics.Manager, cacheManager cache.Manager) Orchestrator {
@@ -265,6 +275,10 @@ func (or *orchestrator) sharedstorage() sharedstorage.Plugin {
 	return or.plugins.SharedStorage.Plugin
 }
 
+func (or *orchestrator) identityPlugin() idplugin.Plugin {
+	return or.plugins.Identity.Plugin
+}
+
 func (or *orchestrator) tokens() map[string]tokens.Plugin {
 	result := make(map[string]tokens.Plugin, len(or.plugins.Tokens))
 	for _, plugin := range or.plugins.Tokens {
@@ -283,6 +297,9 @@ func (or *orchestrator) Start() (err error) {
 		if err == nil {
 			err = or.sharedDownload.Start()
 		}
+		if err == nil {
+			or.messaging.Start()
+		}
 	}
 	if err == nil {
 		err = or.events.Start()
@@ -290,6 +307,9 @@ func (or *orchestrator) Start() (err error) {
 	if err == nil {
 		err = or.operations.Start()
 	}
+	if err == nil {
+		or.retention.Start()
+	}
 
 	or.started = true
 	return err
@@ -315,6 +335,10 @@ func (or *orchestrator) WaitStop() {
 		or.sharedDownload.WaitStop()
 		or.sharedDownload = nil
 	}
+	if or.messaging != nil {
+		or.messaging.WaitStop()
+		or.messaging = nil
+	}
 	if or.events != nil {
 		or.events.WaitStop()
 		or.events = nil
@@ -323,6 +347,10 @@ func (or *orchestrator) WaitStop() {
 		or.operations.WaitStop()
 		or.operations = nil
 	}
+	if or.retention != nil {
+		or.retention.WaitStop()
+		or.retention = nil
+	}
 	or.startedLock.Lock()
 	defer or.startedLock.Unlock()
 	or.started = false
@@ -477,7 +505,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.identity == nil {
-		or.identity, err = identity.NewIdentityManager(ctx, or.namespace.Name, or.config.DefaultKey, or.database(), or.blockchain(), or.multiparty, or.cacheManager)
+		or.identity, err = identity.NewIdentityManager(ctx, or.namespace.Name, or.config.DefaultKey, or.database(), or.blockchain(), or.multiparty, or.identityPlugin(), or.cacheManager)
 		if err != nil {
 			return err
 		}
@@ -499,7 +527,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 		}
 
 		if or.sharedDownload == nil {
-			or.sharedDownload, err = shareddownload.NewDownloadManager(ctx, or.namespace, or.database(), or.sharedstorage(), or.dataexchange(), or.operations, &or.bc)
+			or.sharedDownload, err = shareddownload.NewDownloadManager(ctx, or.namespace, or.database(), or.sharedstorage(), or.data, or.operations, &or.bc)
 			if err != nil {
 				return err
 			}
@@ -523,7 +551,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.defsender == nil {
-		or.defsender, or.defhandler, err = definitions.NewDefinitionSender(ctx, or.namespace, or.config.Multiparty.Enabled, or.database(), or.blockchain(), or.dataexchange(), or.broadcast, or.identity, or.data, or.assets, or.contracts, or.config.TokenBroadcastNames)
+		or.defsender, or.defhandler, err = definitions.NewDefinitionSender(ctx, or.namespace, or.config.Multiparty.Enabled, or.database(), or.blockchain(), or.dataexchange(), or.broadcast, or.messaging, or.identity, or.data, or.assets, or.contracts, or.config.TokenBroadcastNames)
 		if err != nil {
 			return err
 		}
@@ -558,6 +586,13 @@ func (or *orchestrator) initComponents(ctx context.Context) (err error) {
 		}
 	}
 
+	if or.retention == nil {
+		or.retention, err = retention.NewRetentionManager(ctx, or.namespace.Name, or.config.Retention, or.database(), or.data, or.metrics)
+		if err != nil {
+			return err
+		}
+	}
+
 	or.syncasync.Init(or.events)
 
 	return nil
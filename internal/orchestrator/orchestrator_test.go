@@ -46,6 +46,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/mocks/retentionmocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
 	"github.com/hyperledger/firefly/mocks/spieventsmocks"
@@ -85,6 +86,7 @@ type testOrchestrator struct {
 	mdh *definitionsmocks.Handler
 	mmp *multipartymocks.Manager
 	mds *definitionsmocks.Sender
+	mrm *retentionmocks.Manager
 }
 
 func (tor *testOrchestrator) cleanup(t *testing.T) {
@@ -111,6 +113,7 @@ func (tor *testOrchestrator) cleanup(t *testing.T) {
 	tor.mae.AssertExpectations(t)
 	tor.mdh.AssertExpectations(t)
 	tor.mmp.AssertExpectations(t)
+	tor.mrm.AssertExpectations(t)
 }
 
 func newTestOrchestrator() *testOrchestrator {
@@ -146,6 +149,7 @@ func newTestOrchestrator() *testOrchestrator {
 		mdh: &definitionsmocks.Handler{},
 		mmp: &multipartymocks.Manager{},
 		mds: &definitionsmocks.Sender{},
+		mrm: &retentionmocks.Manager{},
 	}
 	tor.orchestrator.multiparty = tor.mmp
 	tor.orchestrator.data = tor.mdm
@@ -164,6 +168,7 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.orchestrator.txHelper = tor.mth
 	tor.orchestrator.defhandler = tor.mdh
 	tor.orchestrator.defsender = tor.mds
+	tor.orchestrator.retention = tor.mrm
 	tor.orchestrator.config.Multiparty.Enabled = true
 	tor.orchestrator.plugins = &Plugins{
 		Blockchain: BlockchainPlugin{
@@ -210,6 +215,16 @@ func TestNewOrchestrator(t *testing.T) {
 	assert.NotNil(t, or)
 }
 
+func TestInitRetentionComponentFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.plugins.Database.Plugin = nil
+	or.retention = nil
+	or.mmp.On("ConfigureContract", mock.Anything, mock.Anything).Return(nil)
+	err := or.initComponents(context.Background())
+	assert.Regexp(t, "FF10128", err)
+}
+
 func TestInitOK(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -440,13 +455,17 @@ func TestStartStopOk(t *testing.T) {
 	or.mem.On("Start").Return(nil)
 	or.mbm.On("Start").Return(nil)
 	or.msd.On("Start").Return(nil)
+	or.mpm.On("Start").Return()
 	or.mom.On("Start").Return(nil)
+	or.mrm.On("Start").Return()
 	or.mba.On("WaitStop").Return(nil)
 	or.mbm.On("WaitStop").Return(nil)
 	or.mdm.On("WaitStop").Return(nil)
 	or.msd.On("WaitStop").Return(nil)
+	or.mpm.On("WaitStop").Return()
 	or.mom.On("WaitStop").Return(nil)
 	or.mem.On("WaitStop").Return(nil)
+	or.mrm.On("WaitStop").Return()
 	err := or.Start()
 	assert.NoError(t, err)
 	or.WaitStop()
@@ -103,3 +103,38 @@ func (or *orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 
 	return subWithStatus, nil
 }
+
+func (or *orchestrator) GetSubscriptionDeadLetters(ctx context.Context, subID string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	u, err := fftypes.ParseUUID(ctx, subID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return or.database().GetDeadLetters(ctx, or.namespace.Name, filter.Condition(filter.Builder().Eq("subscription", u)))
+}
+
+func (or *orchestrator) ReplaySubscriptionDeadLetter(ctx context.Context, subID, dlID string) (*core.DeadLetter, error) {
+	subUUID, err := fftypes.ParseUUID(ctx, subID)
+	if err != nil {
+		return nil, err
+	}
+	dlUUID, err := fftypes.ParseUUID(ctx, dlID)
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := or.database().GetDeadLetterByID(ctx, or.namespace.Name, dlUUID)
+	if err != nil {
+		return nil, err
+	}
+	if deadLetter == nil || !deadLetter.Subscription.ID.Equals(subUUID) {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return deadLetter, or.events.ReplayDeadLetter(ctx, deadLetter)
+}
+
+func (or *orchestrator) DeleteSubscriptionDeadLetters(ctx context.Context, subID string) error {
+	u, err := fftypes.ParseUUID(ctx, subID)
+	if err != nil {
+		return err
+	}
+	return or.database().DeleteDeadLetters(ctx, or.namespace.Name, u)
+}
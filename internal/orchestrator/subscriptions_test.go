@@ -217,3 +217,93 @@ func TestGetSGetSubscriptionsByIDWithStatusUnknownSub(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, subWithStatus)
 }
+
+func TestGetSubscriptionDeadLetters(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetters", mock.Anything, "ns", mock.Anything).Return([]*core.DeadLetter{}, nil, nil)
+	fb := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	_, _, err := or.GetSubscriptionDeadLetters(context.Background(), u.String(), f)
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("( subscription == '%s' )", u), fi.String())
+}
+
+func TestGetSubscriptionDeadLettersBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	fb := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptionDeadLetters(context.Background(), "", fb.And())
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestReplaySubscriptionDeadLetter(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	dl := &core.DeadLetter{
+		ID:           fftypes.NewUUID(),
+		Subscription: core.SubscriptionRef{ID: fftypes.NewUUID()},
+	}
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", dl.ID).Return(dl, nil)
+	or.mem.On("ReplayDeadLetter", mock.Anything, dl).Return(nil)
+	res, err := or.ReplaySubscriptionDeadLetter(context.Background(), dl.Subscription.ID.String(), dl.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, dl, res)
+}
+
+func TestReplaySubscriptionDeadLetterWrongSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	dl := &core.DeadLetter{
+		ID:           fftypes.NewUUID(),
+		Subscription: core.SubscriptionRef{ID: fftypes.NewUUID()},
+	}
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", dl.ID).Return(dl, nil)
+	_, err := or.ReplaySubscriptionDeadLetter(context.Background(), fftypes.NewUUID().String(), dl.ID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReplaySubscriptionDeadLetterGetFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	dlID := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", dlID).Return(nil, fmt.Errorf("pop"))
+	_, err := or.ReplaySubscriptionDeadLetter(context.Background(), fftypes.NewUUID().String(), dlID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplaySubscriptionDeadLetterBadIDs(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.ReplaySubscriptionDeadLetter(context.Background(), "", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF00138", err)
+	_, err = or.ReplaySubscriptionDeadLetter(context.Background(), fftypes.NewUUID().String(), "")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDeleteSubscriptionDeadLetters(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	u := fftypes.NewUUID()
+	or.mdi.On("DeleteDeadLetters", mock.Anything, "ns", u).Return(nil)
+	err := or.DeleteSubscriptionDeadLetters(context.Background(), u.String())
+	assert.NoError(t, err)
+}
+
+func TestDeleteSubscriptionDeadLettersBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	err := or.DeleteSubscriptionDeadLetters(context.Background(), "")
+	assert.Regexp(t, "FF00138", err)
+}
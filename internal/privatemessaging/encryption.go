@@ -0,0 +1,96 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// getNodeEncryptionKey returns the most recently published encryption public key of a node
+func (pm *privateMessaging) getNodeEncryptionKey(ctx context.Context, node *core.Identity) (string, error) {
+	fb := database.VerifierQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("identity", node.ID),
+		fb.Eq("type", core.VerifierTypeX25519PublicKey),
+	).Sort("created").Descending().Limit(1)
+	verifiers, _, err := pm.database.GetVerifiers(ctx, pm.namespace.Name, filter)
+	if err != nil {
+		return "", err
+	}
+	if len(verifiers) == 0 {
+		return "", i18n.NewError(ctx, coremsgs.MsgMessageEncryptionNoRecipientKey, node.Name)
+	}
+	return verifiers[0].Value, nil
+}
+
+// checkRecipientKeys verifies that every other node in the group has published an encryption public key
+func (pm *privateMessaging) checkRecipientKeys(ctx context.Context, groupHash *fftypes.Bytes32) error {
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return err
+	}
+	_, nodes, err := pm.groupManager.getGroupNodes(ctx, groupHash, false)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if !node.ID.Equals(localNode.ID) {
+			if _, err := pm.getNodeEncryptionKey(ctx, node); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// batchRequestsEncryption returns true if any message in the batch asked to be encrypted end-to-end.
+// The whole batch is encrypted in that case, including any plaintext messages that share it.
+func batchRequestsEncryption(batch *core.Batch) bool {
+	if batch == nil {
+		return false
+	}
+	for _, msg := range batch.Payload.Messages {
+		if msg.Header.Encrypted {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptTransport seals the transport wrapper for a single recipient node. A batch that asked for
+// encryption is never sent in clear to a node that has not published a key.
+func (pm *privateMessaging) encryptTransport(ctx context.Context, node *core.Identity, tw *core.TransportWrapper) (*core.TransportWrapper, error) {
+	recipientKey, err := pm.getNodeEncryptionKey(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return msgcrypt.Seal(ctx, tw.Batch.Namespace, recipientKey, tw)
+}
+
+func (pm *privateMessaging) DecryptTransport(ctx context.Context, encrypted *core.EncryptedTransport) (*core.TransportWrapper, error) {
+	if pm.encryptionKey == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionDisabled, encrypted.Recipient)
+	}
+	return pm.encryptionKey.Open(ctx, encrypted)
+}
@@ -0,0 +1,262 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testEncryptionKey = "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"
+
+func setTestEncryptionKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(path, []byte(testEncryptionKey), 0600)
+	assert.NoError(t, err)
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, path)
+}
+
+func newTestPrivateMessagingEncrypted(t *testing.T) (*privateMessaging, func()) {
+	pm, cancel := newTestPrivateMessaging(t)
+	setTestEncryptionKeyFile(t)
+	var err error
+	pm.encryptionKey, err = msgcrypt.LoadLocalKey(context.Background())
+	assert.NoError(t, err)
+	return pm, cancel
+}
+
+func testBatchSendOp(node *core.Identity, encrypted bool) *core.PreparedOperation {
+	op := &core.Operation{
+		Type:      core.OpTypeDataExchangeSendBatch,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	return opSendBatch(op, node, &core.TransportWrapper{
+		Group: &core.Group{Hash: fftypes.NewRandB32()},
+		Batch: &core.Batch{
+			BatchHeader: core.BatchHeader{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+			},
+			Payload: core.BatchPayload{
+				Messages: []*core.Message{
+					{Header: core.MessageHeader{ID: fftypes.NewUUID()}},
+					{Header: core.MessageHeader{ID: fftypes.NewUUID(), Encrypted: encrypted}},
+				},
+			},
+		},
+	})
+}
+
+func TestNewPrivateMessagingEncryptionKeyFail(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, filepath.Join(t.TempDir(), "missing"))
+
+	mba := &batchmocks.Manager{}
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(context.Background(), 100, 0), nil)
+
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := NewPrivateMessaging(context.Background(), ns, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, &blockchainmocks.Plugin{},
+		&identitymanagermocks.Manager{}, mba, &datamocks.Manager{}, &syncasyncmocks.Bridge{}, &multipartymocks.Manager{},
+		&metricsmocks.Manager{}, &operationmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10532", err)
+}
+
+func TestRunOperationBatchSendEncryptedAndPlaintext(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingEncrypted(t)
+	defer cancel()
+
+	node := &core.Identity{
+		IdentityBase:    core.IdentityBase{ID: fftypes.NewUUID(), Name: "node2"},
+		IdentityProfile: core.IdentityProfile{Profile: fftypes.JSONObject{"id": "peer2"}},
+	}
+	localNode := &core.Identity{
+		IdentityProfile: core.IdentityProfile{Profile: fftypes.JSONObject{"id": "local1"}},
+	}
+	encryptedOp := testBatchSendOp(node, true)
+	plaintextOp := testBatchSendOp(node, false)
+
+	sent := make(map[string][]byte)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", context.Background(), "ns1", mock.Anything).Return([]*core.Verifier{{
+		Identity:    node.ID,
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeX25519PublicKey, Value: pm.encryptionKey.PublicKey()},
+	}}, nil, nil).Once()
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", context.Background(), mock.Anything, node.Profile, localNode.Profile, mock.Anything).
+		Run(func(args mock.Arguments) { sent[args[1].(string)] = args[4].([]byte) }).
+		Return(nil)
+
+	_, complete, err := pm.RunOperation(context.Background(), encryptedOp)
+	assert.NoError(t, err)
+	assert.False(t, complete)
+	_, complete, err = pm.RunOperation(context.Background(), plaintextOp)
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	// The batch with a message that asked for encryption is sealed for the recipient node
+	var tw core.TransportWrapper
+	err = json.Unmarshal(sent["ns1:"+encryptedOp.ID.String()], &tw)
+	assert.NoError(t, err)
+	assert.Nil(t, tw.Batch)
+	assert.Nil(t, tw.Group)
+	assert.Equal(t, "ns1", tw.Encrypted.Namespace)
+
+	decrypted, err := pm.DecryptTransport(context.Background(), tw.Encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, encryptedOp.Data.(batchSendData).Transport.Batch.ID, decrypted.Batch.ID)
+	assert.Equal(t, encryptedOp.Data.(batchSendData).Transport.Group.Hash, decrypted.Group.Hash)
+
+	// The plaintext batch is sent in clear by the same node
+	tw = core.TransportWrapper{}
+	err = json.Unmarshal(sent["ns1:"+plaintextOp.ID.String()], &tw)
+	assert.NoError(t, err)
+	assert.Nil(t, tw.Encrypted)
+	assert.Equal(t, plaintextOp.Data.(batchSendData).Transport.Batch.ID, tw.Batch.ID)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestRunOperationBatchSendEncryptedNoRecipientKey(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingEncrypted(t)
+	defer cancel()
+
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node2"}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(&core.Identity{}, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", context.Background(), "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+
+	_, _, err := pm.RunOperation(context.Background(), testBatchSendOp(node, true))
+	assert.Regexp(t, "FF10534.*node2", err)
+}
+
+func TestRunOperationBatchSendEncryptedVerifierLookupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingEncrypted(t)
+	defer cancel()
+
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node2"}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(&core.Identity{}, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, err := pm.RunOperation(context.Background(), testBatchSendOp(node, true))
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDecryptTransportDisabled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.DecryptTransport(context.Background(), &core.EncryptedTransport{Recipient: "key1"})
+	assert.Regexp(t, "FF10537.*key1", err)
+}
+
+func TestCheckRecipientKeys(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node1", Type: core.IdentityTypeNode}}
+	node2 := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node2", Type: core.IdentityTypeNode}}
+	group := &core.Group{
+		Hash: fftypes.NewRandB32(),
+		GroupIdentity: core.GroupIdentity{
+			Members: core.Members{{Node: localNode.ID}, {Node: node2.ID}},
+		},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, localNode.ID).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node2.ID).Return(node2, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+	mdi.On("GetVerifiers", pm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil).Once()
+	mdi.On("GetVerifiers", pm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{{
+		Identity:    node2.ID,
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeX25519PublicKey, Value: "key2"},
+	}}, nil, nil).Once()
+
+	// Only the other node is checked, and it fails until it publishes a key
+	err := pm.checkRecipientKeys(pm.ctx, group.Hash)
+	assert.Regexp(t, "FF10534.*node2", err)
+	err = pm.checkRecipientKeys(pm.ctx, group.Hash)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckRecipientKeysLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.checkRecipientKeys(pm.ctx, fftypes.NewRandB32())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckRecipientKeysGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupHash := fftypes.NewRandB32()
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{}, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupHash).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.checkRecipientKeys(pm.ctx, groupHash)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBatchRequestsEncryptionNoBatch(t *testing.T) {
+	assert.False(t, batchRequestsEncryption(nil))
+}
@@ -18,6 +18,7 @@ package privatemessaging
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -53,17 +54,39 @@ func (pm *privateMessaging) SendMessage(ctx context.Context, in *core.MessageInO
 	return &in.Message, err
 }
 
-func (pm *privateMessaging) RequestReply(ctx context.Context, in *core.MessageInOut) (*core.MessageInOut, error) {
+// RequestReply sends a request message, and correlates any reply messages with it by their cid.
+// In synchronous mode the call blocks until the first reply is confirmed, or the reply timeout of
+// the request passes. In asynchronous mode the request is returned as soon as it is sent, and its
+// ID serves as the handle for querying the replies later.
+func (pm *privateMessaging) RequestReply(ctx context.Context, in *core.MessageInOut, async bool) (*core.MessageInOut, error) {
 	if in.Header.Tag == "" {
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestReplyTagRequired)
 	}
 	if in.Header.CID != nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestCannotHaveCID)
 	}
+	if err := checkReplyTimeout(ctx, in); err != nil {
+		return nil, err
+	}
 	message := pm.NewMessage(in)
+	if async {
+		return in, message.Send(ctx)
+	}
+	if in.ReplyTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*in.ReplyTimeout))
+		defer cancel()
+	}
 	return pm.syncasync.WaitForReply(ctx, in.Header.ID, message.Send)
 }
 
+func checkReplyTimeout(ctx context.Context, in *core.MessageInOut) error {
+	if in.ReplyTimeout != nil && *in.ReplyTimeout <= 0 {
+		return i18n.NewError(ctx, coremsgs.MsgReplyTimeoutInvalid, in.ReplyTimeout)
+	}
+	return nil
+}
+
 // sendMethod is the specific operation requested of the messageSender.
 // To minimize duplication and group database operations, there is a single internal flow with subtle differences for each method.
 type messageSender struct {
@@ -135,6 +158,10 @@ func (s *messageSender) resolveAndSend(ctx context.Context, method sendMethod) e
 func (s *messageSender) resolve(ctx context.Context) error {
 	msg := s.msg.Message
 
+	if err := checkReplyTimeout(ctx, msg); err != nil {
+		return err
+	}
+
 	// Resolve the sending identity
 	if err := s.mgr.identity.ResolveInputSigningIdentity(ctx, &msg.Header.SignerRef); err != nil {
 		return i18n.WrapError(ctx, err, coremsgs.MsgAuthorInvalid)
@@ -145,6 +172,13 @@ func (s *messageSender) resolve(ctx context.Context) error {
 		return err
 	}
 
+	// Fail the send now if the message cannot be encrypted for every recipient, rather than when the batch is sent
+	if msg.Header.Encrypted {
+		if err := s.mgr.checkRecipientKeys(ctx, msg.Header.Group); err != nil {
+			return err
+		}
+	}
+
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	err := s.mgr.data.ResolveInlineData(ctx, s.msg)
 	return err
@@ -167,6 +201,11 @@ func (s *messageSender) sendInternal(ctx context.Context, method sendMethod) err
 	if err := s.msg.Message.Seal(ctx); err != nil {
 		return err
 	}
+	if s.msg.Message.ReplyTimeout != nil {
+		// The deadline is local to this node, and is checked by the reply checker once it passes
+		deadline := fftypes.FFTime(msg.Header.Created.Time().Add(time.Duration(*s.msg.Message.ReplyTimeout)))
+		msg.ReplyDeadline = &deadline
+	}
 	if method == methodPrepare {
 		return nil
 	}
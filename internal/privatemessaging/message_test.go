@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/batch"
@@ -171,6 +172,75 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 
 }
 
+func TestSendEncryptedAndPlaintextMessages(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node1", Type: core.IdentityTypeNode}}
+	node2 := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Name: "node2", Type: core.IdentityTypeNode}}
+	groupID := fftypes.NewRandB32()
+	group := &core.Group{
+		Hash: groupID,
+		GroupIdentity: core.GroupIdentity{
+			Members: core.Members{{Node: localNode.ID}, {Node: node2.ID}},
+		},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, localNode.ID).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node2.ID).Return(node2, nil)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.MatchedBy(func(msg *data.NewMessage) bool {
+		return !msg.Message.Header.Encrypted
+	})).Return(nil).Once()
+	mdm.On("WriteNewMessage", pm.ctx, mock.MatchedBy(func(msg *data.NewMessage) bool {
+		return msg.Message.Header.Encrypted
+	})).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(group, nil)
+	mdi.On("GetVerifiers", pm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil).Once()
+	mdi.On("GetVerifiers", pm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{{
+		Identity:    node2.ID,
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeX25519PublicKey, Value: "key2"},
+	}}, nil, nil).Once()
+
+	newMessage := func(encrypted bool) *core.MessageInOut {
+		return &core.MessageInOut{
+			Message: core.Message{
+				Header: core.MessageHeader{
+					Group:     groupID,
+					Encrypted: encrypted,
+				},
+			},
+			InlineData: core.InlineData{
+				{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+			},
+		}
+	}
+
+	// A plaintext message does not need the recipients to have published keys
+	_, err := pm.SendMessage(pm.ctx, newMessage(false), false)
+	assert.NoError(t, err)
+
+	// An encrypted message fails at send time until every other node has published a key
+	_, err = pm.SendMessage(pm.ctx, newMessage(true), false)
+	assert.Regexp(t, "FF10534.*node2", err)
+	msg, err := pm.SendMessage(pm.ctx, newMessage(true), false)
+	assert.NoError(t, err)
+	assert.True(t, msg.Header.Encrypted)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
 func TestSendMessageBadGroup(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -568,7 +638,7 @@ func TestRequestReplyMissingTag(t *testing.T) {
 	msa := pm.syncasync.(*syncasyncmocks.Bridge)
 	msa.On("WaitForReply", pm.ctx, mock.Anything).Return(nil, nil)
 
-	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{})
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{}, false)
 	assert.Regexp(t, "FF10261", err)
 }
 
@@ -587,7 +657,7 @@ func TestRequestReplyInvalidCID(t *testing.T) {
 				Group: fftypes.NewRandB32(),
 			},
 		},
-	})
+	}, false)
 	assert.Regexp(t, "FF10262", err)
 }
 
@@ -625,8 +695,122 @@ func TestRequestReplySuccess(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, false)
+	assert.NoError(t, err)
+}
+
+func TestRequestReplyInvalidTimeout(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	timeout := fftypes.FFDuration(-1)
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: fftypes.NewRandB32(),
+			},
+		},
+		ReplyTimeout: &timeout,
+	}, false)
+	assert.Regexp(t, "FF10552", err)
+}
+
+func TestRequestReplyTimeout(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+
+	msa := pm.syncasync.(*syncasyncmocks.Bridge)
+	msa.On("WaitForReply", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			ctx := args[0].(context.Context)
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			send := args[2].(syncasync.SendFunction)
+			send(ctx)
+		}).
+		Return(nil, fmt.Errorf("FF10260"))
+
+	groupID := fftypes.NewRandB32()
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", mock.Anything, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(msg *data.NewMessage) bool {
+		deadline := msg.Message.ReplyDeadline
+		return deadline != nil && deadline.Time().Sub(*msg.Message.Header.Created.Time()) == time.Minute
+	})).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", mock.Anything, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	timeout := fftypes.FFDuration(time.Minute)
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: groupID,
+				SignerRef: core.SignerRef{
+					Author: "org1",
+				},
+			},
+		},
+		ReplyTimeout: &timeout,
+	}, false)
+	assert.Regexp(t, "FF10260", err)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestRequestReplyAsync(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.Anything).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	timeout := fftypes.FFDuration(time.Minute)
+	in := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: groupID,
+				SignerRef: core.SignerRef{
+					Author: "org1",
+				},
+			},
+		},
+		ReplyTimeout: &timeout,
+	}
+	out, err := pm.RequestReply(pm.ctx, in, true)
 	assert.NoError(t, err)
+	assert.Equal(t, in, out)
+	assert.NotNil(t, out.Header.ID)
+	assert.NotNil(t, out.ReplyDeadline)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestSendMessageInvalidReplyTimeout(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	timeout := fftypes.FFDuration(0)
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		ReplyTimeout: &timeout,
+	}, false)
+	assert.Regexp(t, "FF10552", err)
 }
 
 func TestDispatchedUnpinnedMessageOK(t *testing.T) {
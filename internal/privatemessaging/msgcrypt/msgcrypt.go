@@ -0,0 +1,154 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgcrypt provides end-to-end encryption of private message batches, so that
+// the batch is only readable by the node it is addressed to, and not by the data
+// exchange on any hop in between.
+//
+// Each node that enables encryption holds an X25519 key pair, and publishes the public
+// key on its node identity. A sender seals the serialized TransportWrapper to the public
+// key of each recipient node with an anonymous NaCl box (an ephemeral X25519 key
+// agreement, with XSalsa20-Poly1305 authenticated encryption).
+package msgcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Algorithm is the identifier recorded on each encrypted transport
+const Algorithm = "x25519-xsalsa20-poly1305"
+
+// ProfileKey is the field of a node identity profile that holds its encryption public key
+const ProfileKey = "encryptionPublicKey"
+
+const keyLen = 32
+
+var randReader = rand.Reader
+
+// LocalKey is the X25519 key pair of the local node
+type LocalKey struct {
+	publicKey  [keyLen]byte
+	privateKey [keyLen]byte
+}
+
+// LoadLocalKey reads the private key of the local node from the configured key file.
+// Returns nil if private message encryption is not enabled.
+func LoadLocalKey(ctx context.Context) (*LocalKey, error) {
+	if !config.GetBool(coreconfig.PrivateMessagingEncryptionEnabled) {
+		return nil, nil
+	}
+	path := config.GetString(coreconfig.PrivateMessagingEncryptionKeyFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionKeyInvalid, path, err)
+	}
+	privateKey, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionKeyInvalid, path, err)
+	}
+	if len(privateKey) != keyLen {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionKeyInvalid, path, "key must be 32 bytes")
+	}
+	k := &LocalKey{}
+	copy(k.privateKey[:], privateKey)
+	publicKey, _ := curve25519.X25519(k.privateKey[:], curve25519.Basepoint) // cannot fail for the base point
+	copy(k.publicKey[:], publicKey)
+	return k, nil
+}
+
+// PublicKey returns the base64 encoded public key, as published on the node identity
+func (k *LocalKey) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(k.publicKey[:])
+}
+
+// ParsePublicKey decodes and checks a base64 encoded X25519 public key
+func ParsePublicKey(ctx context.Context, publicKey string) (*[keyLen]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(b) != keyLen {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionPublicKeyInvalid, publicKey)
+	}
+	var key [keyLen]byte
+	copy(key[:], b)
+	return &key, nil
+}
+
+// ProfilePublicKey returns the encryption public key published in a node identity profile, if any
+func ProfilePublicKey(profile fftypes.JSONObject) string {
+	return profile.GetString(ProfileKey)
+}
+
+// Seal encrypts a transport wrapper for the recipient node with the supplied public key. The returned
+// wrapper only carries the encrypted payload, and the namespace needed to route it at the recipient.
+func Seal(ctx context.Context, namespace, recipient string, tw *core.TransportWrapper) (*core.TransportWrapper, error) {
+	recipientKey, err := ParsePublicKey(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(tw)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionFailed, err)
+	}
+	payload, err := box.SealAnonymous(nil, plaintext, recipientKey, randReader)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionFailed, err)
+	}
+	return &core.TransportWrapper{
+		Encrypted: &core.EncryptedTransport{
+			Namespace: namespace,
+			Algorithm: Algorithm,
+			Recipient: recipient,
+			Payload:   payload,
+		},
+	}, nil
+}
+
+// Open decrypts a transport wrapper that was sealed for the local node. The decrypted batch
+// must belong to the namespace the encrypted transport was routed to.
+func (k *LocalKey) Open(ctx context.Context, et *core.EncryptedTransport) (*core.TransportWrapper, error) {
+	if et.Algorithm != Algorithm {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionUnsupported, et.Algorithm)
+	}
+	if et.Recipient != k.PublicKey() {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageEncryptionWrongRecipient, et.Recipient)
+	}
+	plaintext, ok := box.OpenAnonymous(nil, et.Payload, &k.publicKey, &k.privateKey)
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageDecryptionFailed, "authentication failed")
+	}
+	var tw core.TransportWrapper
+	if err := json.Unmarshal(plaintext, &tw); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageDecryptionFailed, err)
+	}
+	if tw.Batch == nil || tw.Batch.Namespace != et.Namespace {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageDecryptionFailed, "payload does not contain a batch for namespace "+et.Namespace)
+	}
+	return &tw, nil
+}
@@ -0,0 +1,198 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Key pair from the X25519 test vectors in RFC 7748
+const testPrivateKey = "0x77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"
+const testPublicKey = "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a"
+
+func newTestLocalKey(t *testing.T, content string) (*LocalKey, error) {
+	coreconfig.Reset()
+	path := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.NoError(t, err)
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, path)
+	return LoadLocalKey(context.Background())
+}
+
+func testTransport() *core.TransportWrapper {
+	return &core.TransportWrapper{
+		Group: &core.Group{
+			Hash: fftypes.NewRandB32(),
+		},
+		Batch: &core.Batch{
+			BatchHeader: core.BatchHeader{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+			},
+		},
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey+"\n")
+	assert.NoError(t, err)
+	pub, _ := hex.DecodeString(testPublicKey)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(pub), k.PublicKey())
+
+	tw := testTransport()
+	sealed, err := Seal(context.Background(), "ns1", k.PublicKey(), tw)
+	assert.NoError(t, err)
+	assert.Nil(t, sealed.Batch)
+	assert.Nil(t, sealed.Group)
+	assert.Equal(t, "ns1", sealed.Encrypted.Namespace)
+	assert.Equal(t, Algorithm, sealed.Encrypted.Algorithm)
+	assert.Equal(t, k.PublicKey(), sealed.Encrypted.Recipient)
+	assert.NotContains(t, string(sealed.Encrypted.Payload), tw.Batch.ID.String())
+
+	opened, err := k.Open(context.Background(), sealed.Encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, tw.Batch.ID, opened.Batch.ID)
+	assert.Equal(t, tw.Group.Hash, opened.Group.Hash)
+}
+
+func TestLoadLocalKeyDisabled(t *testing.T) {
+	coreconfig.Reset()
+	k, err := LoadLocalKey(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, k)
+}
+
+func TestLoadLocalKeyMissing(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingEncryptionEnabled, true)
+	config.Set(coreconfig.PrivateMessagingEncryptionKeyFile, filepath.Join(t.TempDir(), "missing"))
+	_, err := LoadLocalKey(context.Background())
+	assert.Regexp(t, "FF10532", err)
+}
+
+func TestLoadLocalKeyBadHex(t *testing.T) {
+	_, err := newTestLocalKey(t, "not hex")
+	assert.Regexp(t, "FF10532", err)
+}
+
+func TestLoadLocalKeyBadLength(t *testing.T) {
+	_, err := newTestLocalKey(t, "0011")
+	assert.Regexp(t, "FF10532.*32 bytes", err)
+}
+
+func TestParsePublicKeyInvalid(t *testing.T) {
+	_, err := ParsePublicKey(context.Background(), "!!!")
+	assert.Regexp(t, "FF10533", err)
+	_, err = ParsePublicKey(context.Background(), base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Regexp(t, "FF10533", err)
+}
+
+func TestProfilePublicKey(t *testing.T) {
+	assert.Equal(t, "abc", ProfilePublicKey(fftypes.JSONObject{ProfileKey: "abc"}))
+	assert.Empty(t, ProfilePublicKey(nil))
+}
+
+func TestSealBadRecipient(t *testing.T) {
+	_, err := Seal(context.Background(), "ns1", "!!!", testTransport())
+	assert.Regexp(t, "FF10533", err)
+}
+
+func TestSealSerializeFail(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	tw := testTransport()
+	tw.Batch.Payload.Data = core.DataArray{{Value: fftypes.JSONAnyPtr("!json")}}
+	_, err = Seal(context.Background(), "ns1", k.PublicKey(), tw)
+	assert.Regexp(t, "FF10535", err)
+}
+
+func TestSealRandFail(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	randReader = iotest.ErrReader(fmt.Errorf("pop"))
+	defer func() { randReader = rand.Reader }()
+	_, err = Seal(context.Background(), "ns1", k.PublicKey(), testTransport())
+	assert.Regexp(t, "FF10535.*pop", err)
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	_, err = k.Open(context.Background(), &core.EncryptedTransport{Algorithm: "other"})
+	assert.Regexp(t, "FF10538", err)
+}
+
+func TestOpenWrongRecipient(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	_, err = k.Open(context.Background(), &core.EncryptedTransport{Algorithm: Algorithm, Recipient: "other"})
+	assert.Regexp(t, "FF10539", err)
+}
+
+func TestOpenTampered(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	sealed, err := Seal(context.Background(), "ns1", k.PublicKey(), testTransport())
+	assert.NoError(t, err)
+	sealed.Encrypted.Payload[len(sealed.Encrypted.Payload)-1] ^= 0xff
+	_, err = k.Open(context.Background(), sealed.Encrypted)
+	assert.Regexp(t, "FF10536", err)
+}
+
+func TestOpenBadPayload(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	payload, err := box.SealAnonymous(nil, []byte("!json"), &k.publicKey, rand.Reader)
+	assert.NoError(t, err)
+	_, err = k.Open(context.Background(), &core.EncryptedTransport{
+		Namespace: "ns1",
+		Algorithm: Algorithm,
+		Recipient: k.PublicKey(),
+		Payload:   payload,
+	})
+	assert.Regexp(t, "FF10536", err)
+}
+
+func TestOpenNamespaceMismatch(t *testing.T) {
+	k, err := newTestLocalKey(t, testPrivateKey)
+	assert.NoError(t, err)
+	sealed, err := Seal(context.Background(), "ns2", k.PublicKey(), testTransport())
+	assert.NoError(t, err)
+	_, err = k.Open(context.Background(), sealed.Encrypted)
+	assert.Regexp(t, "FF10536.*ns2", err)
+
+	sealed, err = Seal(context.Background(), "ns1", k.PublicKey(), &core.TransportWrapper{})
+	assert.NoError(t, err)
+	_, err = k.Open(context.Background(), sealed.Encrypted)
+	assert.Regexp(t, "FF10536.*ns1", err)
+}
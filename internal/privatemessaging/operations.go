@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -144,7 +145,14 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 		if err != nil {
 			return nil, false, err
 		}
-		return nil, false, pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, data.Blob.PayloadRef)
+		payloadRef, staged, err := pm.data.PrepareBlobTransfer(ctx, data.Blob, op.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if staged {
+			outputs = fftypes.JSONObject{"stagedPayloadRef": payloadRef}
+		}
+		return outputs, false, pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, payloadRef)
 
 	case batchSendData:
 		localNode, err := pm.identity.GetLocalNode(ctx)
@@ -152,7 +160,14 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 			return nil, false, err
 		}
 
-		payload, err := json.Marshal(data.Transport)
+		transport := data.Transport
+		if batchRequestsEncryption(transport.Batch) {
+			if transport, err = pm.encryptTransport(ctx, data.Node, transport); err != nil {
+				return nil, false, err
+			}
+		}
+
+		payload, err := json.Marshal(transport)
 		if err != nil {
 			return nil, false, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
 		}
@@ -164,6 +179,18 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 }
 
 func (pm *privateMessaging) OnOperationUpdate(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
+	// Remove any decrypted copy of a blob that was staged for the transfer, once the transfer is complete
+	if op.Type == core.OpTypeDataExchangeSendBlob && (update.Status == core.OpStatusSucceeded || update.Status == core.OpStatusFailed) {
+		stagedPayloadRef := update.Output.GetString("stagedPayloadRef")
+		if stagedPayloadRef == "" {
+			stagedPayloadRef = op.Output.GetString("stagedPayloadRef")
+		}
+		if stagedPayloadRef != "" {
+			if err := pm.exchange.DeleteBlob(ctx, stagedPayloadRef); err != nil {
+				log.L(ctx).Warnf("Failed to delete blob '%s' staged for transfer: %s", stagedPayloadRef, err)
+			}
+		}
+	}
 	return nil
 }
 
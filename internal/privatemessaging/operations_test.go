@@ -74,6 +74,8 @@ func TestPrepareAndRunTransferBlob(t *testing.T) {
 	mim.On("CachedIdentityLookupByID", context.Background(), mock.Anything).Return(node, nil)
 	mdi.On("GetBlobs", context.Background(), "ns1", mock.Anything).Return([]*core.Blob{blob}, nil, nil)
 	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("PrepareBlobTransfer", context.Background(), blob, op.ID).Return("payload", false, nil)
 	mdx.On("TransferBlob", context.Background(), "ns1:"+op.ID.String(), node.Profile, localNode.Profile, "payload").Return(nil)
 
 	po, err := pm.PrepareOperation(context.Background(), op)
@@ -81,14 +83,80 @@ func TestPrepareAndRunTransferBlob(t *testing.T) {
 	assert.Equal(t, node, po.Data.(transferBlobData).Node)
 	assert.Equal(t, blob, po.Data.(transferBlobData).Blob)
 
-	_, complete, err := pm.RunOperation(context.Background(), po)
+	outputs, complete, err := pm.RunOperation(context.Background(), po)
 
 	assert.False(t, complete)
 	assert.NoError(t, err)
+	assert.Nil(t, outputs)
 
 	mdi.AssertExpectations(t)
 	mdx.AssertExpectations(t)
 	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestRunTransferBlobStaged(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeDataExchangeSendBlob,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	node := &core.Identity{
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{"id": "peer1"},
+		},
+	}
+	localNode := &core.Identity{
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{"id": "local1"},
+		},
+	}
+	blob := &core.Blob{
+		Hash:       fftypes.NewRandB32(),
+		PayloadRef: "payload",
+		Encryption: &core.BlobEncryption{KeyID: "kek1"},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("PrepareBlobTransfer", context.Background(), blob, op.ID).Return("staged", true, nil)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("TransferBlob", context.Background(), "ns1:"+op.ID.String(), node.Profile, localNode.Profile, "staged").Return(nil)
+
+	outputs, complete, err := pm.RunOperation(context.Background(), opSendBlob(op, node, blob))
+
+	assert.False(t, complete)
+	assert.NoError(t, err)
+	assert.Equal(t, "staged", outputs.GetString("stagedPayloadRef"))
+
+	mdx.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestRunTransferBlobStageFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID()}
+	blob := &core.Blob{}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(&core.Identity{}, nil)
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("PrepareBlobTransfer", context.Background(), blob, op.ID).Return("", false, fmt.Errorf("pop"))
+
+	_, complete, err := pm.RunOperation(context.Background(), opSendBlob(op, &core.Identity{}, blob))
+
+	assert.False(t, complete)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 }
 
 func TestPrepareAndRunBatchSend(t *testing.T) {
@@ -649,7 +717,38 @@ func TestRunOperationBlobSendNodeFail(t *testing.T) {
 func TestOperationUpdate(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
-	assert.NoError(t, pm.OnOperationUpdate(context.Background(), nil, nil))
+	assert.NoError(t, pm.OnOperationUpdate(context.Background(), &core.Operation{}, &core.OperationUpdate{}))
+}
+
+func TestOperationUpdateDeletesStagedBlob(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", context.Background(), "staged1").Return(nil)
+	mdx.On("DeleteBlob", context.Background(), "staged2").Return(fmt.Errorf("pop"))
+
+	op := &core.Operation{
+		Type:   core.OpTypeDataExchangeSendBlob,
+		Output: fftypes.JSONObject{"stagedPayloadRef": "staged1"},
+	}
+
+	// Still pending
+	err := pm.OnOperationUpdate(context.Background(), op, &core.OperationUpdate{Status: core.OpStatusPending})
+	assert.NoError(t, err)
+
+	// Transfer complete, with the staged payload ref recorded on the operation
+	err = pm.OnOperationUpdate(context.Background(), op, &core.OperationUpdate{Status: core.OpStatusSucceeded})
+	assert.NoError(t, err)
+
+	// Failed on submission, with the staged payload ref in the update
+	err = pm.OnOperationUpdate(context.Background(), &core.Operation{Type: core.OpTypeDataExchangeSendBlob}, &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+		Output: fftypes.JSONObject{"stagedPayloadRef": "staged2"},
+	})
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
 }
 
 func TestRetrieveBSendBlobInputs(t *testing.T) {
@@ -19,6 +19,7 @@ package privatemessaging
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -34,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/internal/operations"
+	"github.com/hyperledger/firefly/internal/privatemessaging/msgcrypt"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -50,11 +52,17 @@ type Manager interface {
 
 	NewMessage(msg *core.MessageInOut) syncasync.Sender
 	SendMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (out *core.Message, err error)
-	RequestReply(ctx context.Context, request *core.MessageInOut) (reply *core.MessageInOut, err error)
+	RequestReply(ctx context.Context, request *core.MessageInOut, async bool) (reply *core.MessageInOut, err error)
+
+	Start()
+	WaitStop()
 
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
 	RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, complete bool, err error)
+
+	// DecryptTransport opens a transport wrapper that was encrypted end-to-end for this node
+	DecryptTransport(ctx context.Context, encrypted *core.EncryptedTransport) (*core.TransportWrapper, error)
 }
 
 type privateMessaging struct {
@@ -74,6 +82,11 @@ type privateMessaging struct {
 	metrics               metrics.Manager
 	operations            operations.Manager
 	orgFirstNodes         map[string]*core.Identity
+	encryptionKey         *msgcrypt.LocalKey // optional
+	cancelCtx             context.CancelFunc
+	replyCheckInterval    time.Duration
+	replyCheckBatchSize   int
+	replyCheckDone        chan struct{}
 }
 
 type blobTransferTracker struct {
@@ -88,7 +101,6 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 	}
 
 	pm := &privateMessaging{
-		ctx:        ctx,
 		namespace:  ns,
 		database:   di,
 		identity:   im,
@@ -112,7 +124,13 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		metrics:               mm,
 		operations:            om,
 		orgFirstNodes:         make(map[string]*core.Identity),
+		replyCheckInterval:    config.GetDuration(coreconfig.PrivateMessagingReplyCheckInterval),
+		replyCheckBatchSize:   config.GetInt(coreconfig.PrivateMessagingReplyCheckBatchSize),
+	}
+	if pm.replyCheckBatchSize <= 0 {
+		pm.replyCheckBatchSize = 1
 	}
+	pm.ctx, pm.cancelCtx = context.WithCancel(ctx)
 
 	groupCache, err := cacheManager.GetCache(
 		cache.NewCacheConfig(
@@ -128,6 +146,10 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 
 	pm.groupManager.groupCache = groupCache
 
+	if pm.encryptionKey, err = msgcrypt.LoadLocalKey(ctx); err != nil {
+		return nil, err
+	}
+
 	bo := batch.DispatcherOptions{
 		BatchType:      core.BatchTypePrivate,
 		BatchMaxSize:   config.GetUint(coreconfig.PrivateMessagingBatchSize),
@@ -141,6 +163,7 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		[]core.MessageType{
 			core.MessageTypeGroupInit,
 			core.MessageTypePrivate,
+			core.MessageTypeDefinitionPrivate,
 			core.MessageTypeDeprecatedTransferPrivate,
 			core.MessageTypeDeprecatedApprovalPrivate,
 		},
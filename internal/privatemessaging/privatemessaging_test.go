@@ -70,6 +70,7 @@ func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateM
 		[]core.MessageType{
 			core.MessageTypeGroupInit,
 			core.MessageTypePrivate,
+			core.MessageTypeDefinitionPrivate,
 			core.MessageTypeDeprecatedTransferPrivate,
 			core.MessageTypeDeprecatedApprovalPrivate,
 		}, mock.Anything, mock.Anything).Return()
@@ -132,6 +133,24 @@ func TestCacheInitFail(t *testing.T) {
 	assert.Equal(t, cacheInitError, err)
 }
 
+func TestReplyCheckBatchSizeMinimum(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingReplyCheckBatchSize, 0)
+
+	ctx := context.Background()
+	mba := &batchmocks.Manager{}
+	mba.On("RegisterDispatcher", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mom := &operationmocks.Manager{}
+	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	pm, err := NewPrivateMessaging(ctx, ns, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, &blockchainmocks.Plugin{}, &identitymanagermocks.Manager{}, mba, &datamocks.Manager{}, &syncasyncmocks.Bridge{}, &multipartymocks.Manager{}, &metricsmocks.Manager{}, mom, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pm.(*privateMessaging).replyCheckBatchSize)
+}
+
 func mockRunAsGroupPassthrough(mdi *databasemocks.Plugin) {
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
@@ -0,0 +1,111 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (pm *privateMessaging) Start() {
+	pm.replyCheckDone = make(chan struct{})
+	go pm.replyCheckLoop()
+}
+
+func (pm *privateMessaging) WaitStop() {
+	pm.cancelCtx()
+	if pm.replyCheckDone != nil {
+		<-pm.replyCheckDone
+	}
+}
+
+func (pm *privateMessaging) replyCheckLoop() {
+	defer close(pm.replyCheckDone)
+	ctx := log.WithLogField(pm.ctx, "role", "reply-checker")
+	for {
+		count, err := pm.checkReplyDeadlines(ctx, fftypes.Now())
+		if err != nil {
+			log.L(ctx).Errorf("Failed to check reply deadlines after processing %d: %s", count, err)
+		}
+		select {
+		case <-time.After(pm.replyCheckInterval):
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Reply checker exiting")
+			return
+		}
+	}
+}
+
+// checkReplyDeadlines processes every request message whose reply deadline has passed, oldest first,
+// in pages. The deadline is cleared on each request once it has been checked, so each request is
+// only ever processed once.
+func (pm *privateMessaging) checkReplyDeadlines(ctx context.Context, now *fftypes.FFTime) (count int, err error) {
+	for ctx.Err() == nil {
+		fb := database.MessageQueryFactory.NewFilter(ctx)
+		filter := fb.And(fb.Lt("replydeadline", now)).Sort("replydeadline").Limit(uint64(pm.replyCheckBatchSize))
+		msgs, _, err := pm.database.GetMessages(ctx, pm.namespace.Name, filter)
+		if err != nil {
+			return count, err
+		}
+		for _, msg := range msgs {
+			if err := pm.checkReplyDeadline(ctx, msg); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(msgs) < pm.replyCheckBatchSize {
+			break
+		}
+	}
+	return count, nil
+}
+
+// checkReplyDeadline emits a reply overdue event on each topic of the request, unless a reply
+// correlated to the request was confirmed on or before its deadline.
+func (pm *privateMessaging) checkReplyDeadline(ctx context.Context, msg *core.Message) error {
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	replies, _, err := pm.database.GetMessages(ctx, pm.namespace.Name, fb.And(
+		fb.Eq("cid", msg.Header.ID),
+		fb.Eq("state", core.MessageStateConfirmed),
+		fb.Lte("confirmed", msg.ReplyDeadline),
+	).Limit(1))
+	if err != nil {
+		return err
+	}
+	return pm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		update := database.MessageQueryFactory.NewUpdate(ctx).Set("replydeadline", nil)
+		if err := pm.database.UpdateMessage(ctx, pm.namespace.Name, msg.Header.ID, update); err != nil {
+			return err
+		}
+		if len(replies) > 0 {
+			return nil
+		}
+		log.L(ctx).Infof("No reply to message %s before its reply deadline %s", msg.Header.ID, msg.ReplyDeadline)
+		for _, topic := range msg.Header.Topics {
+			event := core.NewEvent(core.EventTypeMessageReplyOverdue, pm.namespace.Name, msg.Header.ID, msg.TransactionID, topic)
+			if err := pm.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRequest(topics ...string) *core.Message {
+	deadline := fftypes.FFTime(time.Now().Add(-time.Minute))
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Topics: topics,
+		},
+		TransactionID: fftypes.NewUUID(),
+		ReplyDeadline: &deadline,
+	}
+}
+
+func filterOn(field string) interface{} {
+	return mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		return err == nil && strings.HasPrefix(fi.String(), "( "+field+" ")
+	})
+}
+
+func TestReplyCheckStartStop(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.replyCheckInterval = time.Hour
+
+	checked := make(chan struct{})
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterOn("replydeadline")).Return([]*core.Message{}, nil, nil).Run(func(args mock.Arguments) {
+		close(checked)
+	}).Once()
+
+	pm.Start()
+	<-checked
+	pm.WaitStop()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReplyCheckLoopError(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.replyCheckInterval = time.Hour
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterOn("replydeadline")).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		pm.cancelCtx()
+	}).Once()
+
+	pm.replyCheckDone = make(chan struct{})
+	pm.replyCheckLoop()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReplyCheckWaitStopNotStarted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.WaitStop()
+}
+
+func TestCheckReplyDeadlinesOverdue(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.replyCheckBatchSize = 1
+
+	msg1 := newTestRequest("topic1", "topic2")
+	msg2 := newTestRequest("topic3")
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg1}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg2}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("cid")).Return([]*core.Message{}, nil, nil).Twice()
+	mdi.On("UpdateMessage", pm.ctx, "ns1", msg1.Header.ID, mock.Anything).Return(nil).Once()
+	mdi.On("UpdateMessage", pm.ctx, "ns1", msg2.Header.ID, mock.Anything).Return(nil).Once()
+	var events []*core.Event
+	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		events = append(events, args[1].(*core.Event))
+	})
+
+	count, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, events, 3)
+	for i, topic := range []string{"topic1", "topic2", "topic3"} {
+		assert.Equal(t, core.EventTypeMessageReplyOverdue, events[i].Type)
+		assert.Equal(t, topic, events[i].Topic)
+		assert.Equal(t, "ns1", events[i].Namespace)
+	}
+	assert.Equal(t, msg1.Header.ID, events[0].Reference)
+	assert.Equal(t, msg1.TransactionID, events[0].Transaction)
+	assert.Equal(t, msg2.Header.ID, events[2].Reference)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckReplyDeadlinesReplied(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg := newTestRequest("topic1")
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == fmt.Sprintf("( cid == '%s' ) && ( state == 'confirmed' ) && ( confirmed <= %d ) limit=1", msg.Header.ID, msg.ReplyDeadline.UnixNano())
+	})).Return([]*core.Message{{}}, nil, nil).Once()
+	mdi.On("UpdateMessage", pm.ctx, "ns1", msg.Header.ID, mock.MatchedBy(func(u ffapi.Update) bool {
+		ui, _ := u.Finalize()
+		return ui.String() == "replydeadline=null"
+	})).Return(nil).Once()
+
+	count, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckReplyDeadlinesQueryFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckReplyDeadlinesRepliesQueryFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg := newTestRequest("topic1")
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("cid")).Return(nil, nil, fmt.Errorf("pop")).Once()
+
+	count, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, 0, count)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckReplyDeadlinesUpdateFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg := newTestRequest("topic1")
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("cid")).Return([]*core.Message{}, nil, nil).Once()
+	mdi.On("UpdateMessage", pm.ctx, "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckReplyDeadlinesInsertEventFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg := newTestRequest("topic1")
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("replydeadline")).Return([]*core.Message{msg}, nil, nil).Once()
+	mdi.On("GetMessages", pm.ctx, "ns1", filterOn("cid")).Return([]*core.Message{}, nil, nil).Once()
+	mdi.On("UpdateMessage", pm.ctx, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.checkReplyDeadlines(pm.ctx, fftypes.Now())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,390 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type Manager interface {
+	Start()
+	WaitStop()
+}
+
+// Config is the retention policy of a namespace. A zero duration means records
+// in that collection are kept forever.
+type Config struct {
+	Messages         time.Duration
+	Events           time.Duration
+	Operations       time.Duration
+	BlockchainEvents time.Duration
+	TokenTransfers   time.Duration
+}
+
+// retentionManager periodically deletes the records of a namespace that have passed the
+// retention period configured for their collection, as well as any messages that have passed
+// the expiry time set in their header, and any resumable blob uploads that were abandoned. Deletion is done in pages, so that each database
+// round trip is bounded.
+type retentionManager struct {
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	namespace string
+	conf      Config
+	database  database.Plugin
+	data      data.Manager
+	metrics   metrics.Manager
+	interval  time.Duration
+	batchSize int
+	loopDone  chan struct{}
+}
+
+func NewRetentionManager(ctx context.Context, ns string, conf Config, di database.Plugin, dm data.Manager, mm metrics.Manager) (Manager, error) {
+	if di == nil || dm == nil || mm == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "RetentionManager")
+	}
+	rm := &retentionManager{
+		namespace: ns,
+		conf:      conf,
+		database:  di,
+		data:      dm,
+		metrics:   mm,
+		interval:  config.GetDuration(coreconfig.RetentionInterval),
+		batchSize: config.GetInt(coreconfig.RetentionBatchSize),
+	}
+	if rm.batchSize <= 0 {
+		rm.batchSize = 1
+	}
+	rm.ctx, rm.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "retention"))
+	return rm, nil
+}
+
+func (rm *retentionManager) Start() {
+	rm.loopDone = make(chan struct{})
+	go rm.purgeLoop()
+}
+
+func (rm *retentionManager) WaitStop() {
+	rm.cancelCtx()
+	if rm.loopDone != nil {
+		<-rm.loopDone
+	}
+}
+
+func (rm *retentionManager) purgeLoop() {
+	defer close(rm.loopDone)
+	for {
+		rm.purgeAll(time.Now())
+		select {
+		case <-time.After(rm.interval):
+		case <-rm.ctx.Done():
+			log.L(rm.ctx).Debugf("Retention purger exiting")
+			return
+		}
+	}
+}
+
+// purgeAll runs a single pass over every collection. A failure in one collection is logged,
+// and does not prevent the others from being purged - the next pass will retry.
+func (rm *retentionManager) purgeAll(now time.Time) {
+	msgCount, dataCount, batchCount, err := rm.purgeMessages(now)
+	rm.recordPurged(string(database.CollectionMessages), msgCount, err)
+	rm.recordPurged(string(database.CollectionData), dataCount, nil)
+	rm.recordPurged(string(database.CollectionBatches), batchCount, nil)
+
+	uploadCount, err := rm.purgeBlobUploads(now)
+	rm.recordPurged(string(database.CollectionBlobUploads), uploadCount, err)
+
+	if rm.conf.Events > 0 {
+		count, err := rm.purgeEvents(cutoff(now, rm.conf.Events))
+		rm.recordPurged(string(database.CollectionEvents), count, err)
+	}
+	if rm.conf.Operations > 0 {
+		count, err := rm.purgeOperations(cutoff(now, rm.conf.Operations))
+		rm.recordPurged(string(database.CollectionOperations), count, err)
+	}
+	if rm.conf.BlockchainEvents > 0 {
+		count, err := rm.purgeBlockchainEvents(cutoff(now, rm.conf.BlockchainEvents))
+		rm.recordPurged(string(database.CollectionBlockchainEvents), count, err)
+	}
+	if rm.conf.TokenTransfers > 0 {
+		count, err := rm.purgeTokenTransfers(cutoff(now, rm.conf.TokenTransfers))
+		rm.recordPurged(string(database.CollectionTokenTransfers), count, err)
+	}
+}
+
+func cutoff(now time.Time, retention time.Duration) *fftypes.FFTime {
+	t := fftypes.FFTime(now.Add(-retention))
+	return &t
+}
+
+func (rm *retentionManager) recordPurged(collection string, count int, err error) {
+	if err != nil {
+		log.L(rm.ctx).Errorf("Failed to purge expired %s after deleting %d: %s", collection, count, err)
+	}
+	if count == 0 {
+		return
+	}
+	log.L(rm.ctx).Infof("Purged %d expired %s", count, collection)
+	if rm.metrics.IsMetricsEnabled() {
+		rm.metrics.RecordsPurged(rm.namespace, collection, count)
+	}
+}
+
+// purgePages repeatedly fetches the oldest page of expired records, and deletes them by ID,
+// until a short page is returned.
+func (rm *retentionManager) purgePages(getPage func() ([]*fftypes.UUID, error), deleteIDs func([]*fftypes.UUID) error) (count int, err error) {
+	for rm.ctx.Err() == nil {
+		ids, err := getPage()
+		if err != nil || len(ids) == 0 {
+			return count, err
+		}
+		if err := deleteIDs(ids); err != nil {
+			return count, err
+		}
+		count += len(ids)
+		if len(ids) < rm.batchSize {
+			break
+		}
+	}
+	return count, nil
+}
+
+// purgeMessages deletes confirmed and rejected messages that were confirmed before the retention
+// cutoff, or that have passed their expiry time. Messages that are still in flight are never deleted,
+// nor are messages in a batch that still has pins waiting to be dispatched. The batch of a message
+// is deleted along with the last message remaining in it.
+func (rm *retentionManager) purgeMessages(now time.Time) (msgCount, dataCount, batchCount int, err error) {
+	fb := database.MessageQueryFactory.NewFilter(rm.ctx)
+	var expired ffapi.Filter = fb.Lt("expires", cutoff(now, 0))
+	if rm.conf.Messages > 0 {
+		expired = fb.Or(expired, fb.Lt("confirmed", cutoff(now, rm.conf.Messages)))
+	}
+
+	lastSequence := int64(-1)
+	pendingBatches := make(map[fftypes.UUID]bool)
+	for rm.ctx.Err() == nil {
+		filter := fb.And(
+			fb.In("state", []driver.Value{core.MessageStateConfirmed, core.MessageStateRejected}),
+			fb.Gt("sequence", lastSequence),
+			expired,
+		).Sort("sequence").Limit(uint64(rm.batchSize))
+		msgs, _, err := rm.database.GetMessages(rm.ctx, rm.namespace, filter)
+		if err != nil {
+			return msgCount, dataCount, batchCount, err
+		}
+		for _, msg := range msgs {
+			lastSequence = msg.Sequence
+			pending, err := rm.batchPending(msg.BatchID, pendingBatches)
+			if err != nil {
+				return msgCount, dataCount, batchCount, err
+			}
+			if pending {
+				log.L(rm.ctx).Debugf("Not purging message %s as batch %s has pins still to be processed", msg.Header.ID, msg.BatchID)
+				continue
+			}
+			batchDeleted, err := rm.purgeBatchOfMessage(msg)
+			if err != nil {
+				return msgCount, dataCount, batchCount, err
+			}
+			if batchDeleted {
+				batchCount++
+			}
+			deleted, err := rm.data.PurgeMessage(rm.ctx, msg)
+			dataCount += deleted
+			if err != nil {
+				return msgCount, dataCount, batchCount, err
+			}
+			msgCount++
+		}
+		if len(msgs) < rm.batchSize {
+			break
+		}
+	}
+	return msgCount, dataCount, batchCount, nil
+}
+
+// purgeBatchOfMessage deletes the batch of a message that is about to be purged, if no other
+// message remains in that batch. The batch is deleted before the message, so that if the purge
+// is interrupted the message is found again on the next pass.
+func (rm *retentionManager) purgeBatchOfMessage(msg *core.Message) (bool, error) {
+	if msg.BatchID == nil {
+		return false, nil
+	}
+	fb := database.MessageQueryFactory.NewFilter(rm.ctx)
+	others, _, err := rm.database.GetMessages(rm.ctx, rm.namespace, fb.And(
+		fb.Eq("batch", msg.BatchID),
+		fb.Neq("id", msg.Header.ID),
+	).Limit(1))
+	if err != nil || len(others) > 0 {
+		return false, err
+	}
+	err = rm.database.DeleteBatch(rm.ctx, rm.namespace, msg.BatchID)
+	if err == fftypes.DeleteRecordNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// purgeBlobUploads deletes resumable blob uploads that have passed their expiry time, along with any chunks
+// staged in the data exchange. Uploads that are being finalized in the background are left in place.
+func (rm *retentionManager) purgeBlobUploads(now time.Time) (count int, err error) {
+	fb := database.BlobUploadQueryFactory.NewFilter(rm.ctx)
+	for rm.ctx.Err() == nil {
+		uploads, _, err := rm.database.GetBlobUploads(rm.ctx, rm.namespace, fb.And(
+			fb.Lt("expires", cutoff(now, 0)),
+			fb.Neq("state", core.BlobUploadStateFinalizing),
+		).Sort("expires").Limit(uint64(rm.batchSize)))
+		if err != nil {
+			return count, err
+		}
+		for _, upload := range uploads {
+			if err := rm.data.PurgeBlobUpload(rm.ctx, upload); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(uploads) < rm.batchSize {
+			break
+		}
+	}
+	return count, nil
+}
+
+func (rm *retentionManager) batchPending(batchID *fftypes.UUID, checked map[fftypes.UUID]bool) (bool, error) {
+	if batchID == nil {
+		return false, nil
+	}
+	if pending, ok := checked[*batchID]; ok {
+		return pending, nil
+	}
+	fb := database.PinQueryFactory.NewFilter(rm.ctx)
+	pins, _, err := rm.database.GetPins(rm.ctx, rm.namespace, fb.And(
+		fb.Eq("batch", batchID),
+		fb.Eq("dispatched", false),
+	).Limit(1))
+	if err != nil {
+		return false, err
+	}
+	checked[*batchID] = len(pins) > 0
+	return checked[*batchID], nil
+}
+
+// purgeEvents deletes events created before the cutoff, that have also been delivered to
+// every durable subscription in the namespace.
+func (rm *retentionManager) purgeEvents(before *fftypes.FFTime) (int, error) {
+	subs, _, err := rm.database.GetSubscriptions(rm.ctx, rm.namespace, database.SubscriptionQueryFactory.NewFilter(rm.ctx).And())
+	if err != nil {
+		return 0, err
+	}
+	maxSequence := int64(-1)
+	for _, sub := range subs {
+		offset, err := rm.database.GetOffset(rm.ctx, core.OffsetTypeSubscription, sub.ID.String())
+		if err != nil {
+			return 0, err
+		}
+		if offset == nil {
+			// The subscription has not started yet, so might still need every event
+			return 0, nil
+		}
+		if maxSequence < 0 || offset.Current < maxSequence {
+			maxSequence = offset.Current
+		}
+	}
+
+	return rm.purgePages(func() ([]*fftypes.UUID, error) {
+		fb := database.EventQueryFactory.NewFilter(rm.ctx)
+		filter := fb.And(fb.Lt("created", before))
+		if maxSequence >= 0 {
+			filter = filter.Condition(fb.Lte("sequence", maxSequence))
+		}
+		events, _, err := rm.database.GetEvents(rm.ctx, rm.namespace, filter.Sort("sequence").Limit(uint64(rm.batchSize)))
+		ids := make([]*fftypes.UUID, len(events))
+		for i, e := range events {
+			ids[i] = e.ID
+		}
+		return ids, err
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteEvents(rm.ctx, rm.namespace, ids)
+	})
+}
+
+// purgeOperations deletes operations that succeeded or failed, and were last updated before the cutoff
+func (rm *retentionManager) purgeOperations(before *fftypes.FFTime) (int, error) {
+	return rm.purgePages(func() ([]*fftypes.UUID, error) {
+		fb := database.OperationQueryFactory.NewFilter(rm.ctx)
+		ops, _, err := rm.database.GetOperations(rm.ctx, rm.namespace, fb.And(
+			fb.In("status", []driver.Value{core.OpStatusSucceeded, core.OpStatusFailed}),
+			fb.Lt("updated", before),
+		).Sort("updated").Limit(uint64(rm.batchSize)))
+		ids := make([]*fftypes.UUID, len(ops))
+		for i, op := range ops {
+			ids[i] = op.ID
+		}
+		return ids, err
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteOperations(rm.ctx, rm.namespace, ids)
+	})
+}
+
+// purgeBlockchainEvents deletes blockchain events with a timestamp before the cutoff
+func (rm *retentionManager) purgeBlockchainEvents(before *fftypes.FFTime) (int, error) {
+	return rm.purgePages(func() ([]*fftypes.UUID, error) {
+		fb := database.BlockchainEventQueryFactory.NewFilter(rm.ctx)
+		events, _, err := rm.database.GetBlockchainEvents(rm.ctx, rm.namespace, fb.And(
+			fb.Lt("timestamp", before),
+		).Sort("timestamp").Limit(uint64(rm.batchSize)))
+		ids := make([]*fftypes.UUID, len(events))
+		for i, e := range events {
+			ids[i] = e.ID
+		}
+		return ids, err
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteBlockchainEvents(rm.ctx, rm.namespace, ids)
+	})
+}
+
+// purgeTokenTransfers deletes token transfers created before the cutoff. Token balances are
+// tracked separately, so are not affected.
+func (rm *retentionManager) purgeTokenTransfers(before *fftypes.FFTime) (int, error) {
+	return rm.purgePages(func() ([]*fftypes.UUID, error) {
+		fb := database.TokenTransferQueryFactory.NewFilter(rm.ctx)
+		transfers, _, err := rm.database.GetTokenTransfers(rm.ctx, rm.namespace, fb.And(
+			fb.Lt("created", before),
+		).Sort("created").Limit(uint64(rm.batchSize)))
+		ids := make([]*fftypes.UUID, len(transfers))
+		for i, t := range transfers {
+			ids[i] = t.LocalID
+		}
+		return ids, err
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteTokenTransfersByID(rm.ctx, rm.namespace, ids)
+	})
+}
@@ -0,0 +1,450 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRetentionManager(t *testing.T, conf Config) (*retentionManager, func()) {
+	coreconfig.Reset()
+	config.Set(coreconfig.RetentionBatchSize, 2)
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	mmi := &metricsmocks.Manager{}
+	rm, err := NewRetentionManager(context.Background(), "ns1", conf, mdi, mdm, mmi)
+	assert.NoError(t, err)
+	return rm.(*retentionManager), func() {
+		rm.WaitStop()
+		mdi.AssertExpectations(t)
+		mdm.AssertExpectations(t)
+		mmi.AssertExpectations(t)
+	}
+}
+
+func filterContains(s string) interface{} {
+	return mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), s)
+	})
+}
+
+func TestNewRetentionManagerMissingDeps(t *testing.T) {
+	_, err := NewRetentionManager(context.Background(), "ns1", Config{}, nil, nil, nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestNewRetentionManagerMinBatchSize(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.RetentionBatchSize, 0)
+	rm, err := NewRetentionManager(context.Background(), "ns1", Config{}, &databasemocks.Plugin{}, &datamocks.Manager{}, &metricsmocks.Manager{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rm.(*retentionManager).batchSize)
+}
+
+func TestStartWaitStop(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+	rm.interval = 1 * time.Millisecond
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	purged := make(chan struct{}, 1)
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).
+		Run(func(args mock.Arguments) {
+			select {
+			case purged <- struct{}{}:
+			default:
+			}
+		})
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{}, nil, nil)
+
+	rm.Start()
+	<-purged
+	<-purged
+}
+
+func TestWaitStopNotStarted(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	done()
+	assert.Error(t, rm.ctx.Err())
+}
+
+func TestPurgeAll(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{
+		Messages:         time.Hour,
+		Events:           time.Hour,
+		Operations:       time.Hour,
+		BlockchainEvents: time.Hour,
+		TokenTransfers:   time.Hour,
+	})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	mmi := rm.metrics.(*metricsmocks.Manager)
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID(), Sequence: 10}
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("confirmed <<")).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("batch ==")).Return([]*core.Message{}, nil, nil)
+	mdi.On("DeleteBatch", mock.Anything, "ns1", msg.BatchID).Return(nil)
+	mdm.On("PurgeMessage", mock.Anything, msg).Return(2, nil)
+
+	upload := &core.BlobUpload{ID: fftypes.NewUUID()}
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", filterContains("state != 'finalizing'")).Return([]*core.BlobUpload{upload}, nil, nil)
+	mdm.On("PurgeBlobUpload", mock.Anything, upload).Return(nil)
+
+	ev := &core.Event{ID: fftypes.NewUUID()}
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{ev}, nil, nil)
+	mdi.On("DeleteEvents", mock.Anything, "ns1", []*fftypes.UUID{ev.ID}).Return(nil)
+
+	op := &core.Operation{ID: fftypes.NewUUID()}
+	mdi.On("GetOperations", mock.Anything, "ns1", filterContains("status IN")).Return([]*core.Operation{op}, nil, nil)
+	mdi.On("DeleteOperations", mock.Anything, "ns1", []*fftypes.UUID{op.ID}).Return(nil)
+
+	bev := &core.BlockchainEvent{ID: fftypes.NewUUID()}
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", filterContains("timestamp <<")).Return([]*core.BlockchainEvent{bev}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1", []*fftypes.UUID{bev.ID}).Return(nil)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID()}
+	mdi.On("GetTokenTransfers", mock.Anything, "ns1", filterContains("created <<")).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	mdi.On("DeleteTokenTransfersByID", mock.Anything, "ns1", []*fftypes.UUID{transfer.LocalID}).Return(nil)
+
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("RecordsPurged", "ns1", "messages", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "data", 2).Return()
+	mmi.On("RecordsPurged", "ns1", "batches", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "blobuploads", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "events", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "operations", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "blockchainevents", 1).Return()
+	mmi.On("RecordsPurged", "ns1", "tokentransfers", 1).Return()
+
+	rm.purgeAll(time.Now())
+}
+
+func TestPurgeAllFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{
+		Events:           time.Hour,
+		Operations:       time.Hour,
+		BlockchainEvents: time.Hour,
+		TokenTransfers:   time.Hour,
+	})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("GetTokenTransfers", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	rm.purgeAll(time.Now())
+}
+
+func TestPurgeMessagesPaged(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+
+	pendingBatch := fftypes.NewUUID()
+	doneBatch := fftypes.NewUUID()
+	msg1 := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: pendingBatch, Sequence: 1}
+	msg2 := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: pendingBatch, Sequence: 2}
+	msg3 := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: doneBatch, Sequence: 3}
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "sequence >> -1") && !strings.Contains(fi.String(), "confirmed <<")
+	})).Return([]*core.Message{msg1, msg2}, nil, nil).Once()
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("sequence >> 2")).Return([]*core.Message{msg3}, nil, nil).Once()
+	mdi.On("GetPins", mock.Anything, "ns1", filterContains(pendingBatch.String())).Return([]*core.Pin{{}}, nil, nil).Once()
+	mdi.On("GetPins", mock.Anything, "ns1", filterContains(doneBatch.String())).Return([]*core.Pin{}, nil, nil).Once()
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains(doneBatch.String())).Return([]*core.Message{}, nil, nil).Once()
+	mdi.On("DeleteBatch", mock.Anything, "ns1", doneBatch).Return(nil)
+	mdm.On("PurgeMessage", mock.Anything, msg3).Return(0, nil)
+
+	msgCount, dataCount, batchCount, err := rm.purgeMessages(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, msgCount)
+	assert.Equal(t, 0, dataCount)
+	assert.Equal(t, 1, batchCount)
+}
+
+func TestPurgeMessagesNoBatch(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, State: core.MessageStateRejected}
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	mdm.On("PurgeMessage", mock.Anything, msg).Return(1, nil)
+
+	msgCount, dataCount, _, err := rm.purgeMessages(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, msgCount)
+	assert.Equal(t, 1, dataCount)
+}
+
+func TestPurgeMessagesGetPinsFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, _, err := rm.purgeMessages(time.Now())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeMessagesBatchStillInUse(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	batchID := fftypes.NewUUID()
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: batchID}
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("state IN")).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("batch ==")).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: batchID},
+	}, nil, nil)
+	mdm.On("PurgeMessage", mock.Anything, msg).Return(0, nil)
+
+	msgCount, _, batchCount, err := rm.purgeMessages(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, msgCount)
+	assert.Equal(t, 0, batchCount)
+	mdi.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeMessagesBatchAlreadyDeleted(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("state IN")).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("batch ==")).Return([]*core.Message{}, nil, nil)
+	mdi.On("DeleteBatch", mock.Anything, "ns1", msg.BatchID).Return(fftypes.DeleteRecordNotFound)
+	mdm.On("PurgeMessage", mock.Anything, msg).Return(0, nil)
+
+	msgCount, _, batchCount, err := rm.purgeMessages(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, msgCount)
+	assert.Equal(t, 0, batchCount)
+}
+
+func TestPurgeMessagesDeleteBatchFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("state IN")).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("batch ==")).Return([]*core.Message{}, nil, nil)
+	mdi.On("DeleteBatch", mock.Anything, "ns1", msg.BatchID).Return(fmt.Errorf("pop"))
+
+	msgCount, _, _, err := rm.purgeMessages(time.Now())
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, msgCount)
+}
+
+func TestPurgeMessagesGetBatchMessagesFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("state IN")).Return([]*core.Message{msg}, nil, nil)
+	mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", filterContains("batch ==")).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, _, err := rm.purgeMessages(time.Now())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeMessagesPurgeFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	mdm.On("PurgeMessage", mock.Anything, msg).Return(1, fmt.Errorf("pop"))
+
+	msgCount, dataCount, _, err := rm.purgeMessages(time.Now())
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, msgCount)
+	assert.Equal(t, 1, dataCount)
+}
+
+func TestPurgeMessagesCancelled(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	done()
+
+	msgCount, dataCount, _, err := rm.purgeMessages(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, msgCount)
+	assert.Equal(t, 0, dataCount)
+}
+
+func TestPurgeBlobUploadsPaged(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+	rm.batchSize = 2
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	upload1 := &core.BlobUpload{ID: fftypes.NewUUID()}
+	upload2 := &core.BlobUpload{ID: fftypes.NewUUID()}
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{upload1, upload2}, nil, nil).Once()
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{}, nil, nil).Once()
+	mdm.On("PurgeBlobUpload", mock.Anything, upload1).Return(nil)
+	mdm.On("PurgeBlobUpload", mock.Anything, upload2).Return(nil)
+
+	count, err := rm.purgeBlobUploads(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	mdm.AssertExpectations(t)
+}
+
+func TestPurgeBlobUploadsPurgeFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	mdm := rm.data.(*datamocks.Manager)
+	upload := &core.BlobUpload{ID: fftypes.NewUUID()}
+	mdi.On("GetBlobUploads", mock.Anything, "ns1", mock.Anything).Return([]*core.BlobUpload{upload}, nil, nil)
+	mdm.On("PurgeBlobUpload", mock.Anything, upload).Return(fmt.Errorf("pop"))
+
+	count, err := rm.purgeBlobUploads(time.Now())
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPurgeBlobUploadsCancelled(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	done()
+
+	count, err := rm.purgeBlobUploads(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPurgeEventsSubscriptionOffsets(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	sub2 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub1, sub2}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub1.ID.String()).Return(&core.Offset{Current: 20}, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub2.ID.String()).Return(&core.Offset{Current: 10}, nil)
+	ev1 := &core.Event{ID: fftypes.NewUUID()}
+	ev2 := &core.Event{ID: fftypes.NewUUID()}
+	mdi.On("GetEvents", mock.Anything, "ns1", filterContains("sequence <= 10")).Return([]*core.Event{ev1, ev2}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", filterContains("sequence <= 10")).Return([]*core.Event{}, nil, nil).Once()
+	mdi.On("DeleteEvents", mock.Anything, "ns1", []*fftypes.UUID{ev1.ID, ev2.ID}).Return(nil)
+
+	count, err := rm.purgeEvents(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestPurgeEventsSubscriptionNotStarted(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	sub := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, nil)
+
+	count, err := rm.purgeEvents(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPurgeEventsGetOffsetFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	sub := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, fmt.Errorf("pop"))
+
+	_, err := rm.purgeEvents(fftypes.Now())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeEventsDeleteFail(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	defer done()
+
+	mdi := rm.database.(*databasemocks.Plugin)
+	ev := &core.Event{ID: fftypes.NewUUID()}
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return !strings.Contains(fi.String(), "sequence <=")
+	})).Return([]*core.Event{ev}, nil, nil)
+	mdi.On("DeleteEvents", mock.Anything, "ns1", []*fftypes.UUID{ev.ID}).Return(fmt.Errorf("pop"))
+
+	count, err := rm.purgeEvents(fftypes.Now())
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPurgePagesCancelled(t *testing.T) {
+	rm, done := newTestRetentionManager(t, Config{})
+	done()
+
+	count, err := rm.purgeOperations(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -28,10 +28,10 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
-	"github.com/hyperledger/firefly/pkg/dataexchange"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
 )
 
@@ -54,7 +54,7 @@ type downloadManager struct {
 	namespace                  *core.Namespace
 	database                   database.Plugin
 	sharedstorage              sharedstorage.Plugin // optional
-	dataexchange               dataexchange.Plugin
+	data                       data.Manager
 	operations                 operations.Manager
 	callbacks                  Callbacks
 	workerCount                int
@@ -76,11 +76,11 @@ type downloadWork struct {
 
 type Callbacks interface {
 	SharedStorageBatchDownloaded(payloadRef string, data []byte) (batchID *fftypes.UUID, err error)
-	SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error
+	SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID, encryption *core.BlobEncryption) error
 }
 
-func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plugin, ss sharedstorage.Plugin, dx dataexchange.Plugin, om operations.Manager, cb Callbacks) (Manager, error) {
-	if di == nil || dx == nil || ss == nil || cb == nil {
+func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plugin, ss sharedstorage.Plugin, dataManager data.Manager, om operations.Manager, cb Callbacks) (Manager, error) {
+	if di == nil || dataManager == nil || ss == nil || cb == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "DownloadManager")
 	}
 
@@ -91,7 +91,7 @@ func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plu
 		namespace:                  ns,
 		database:                   di,
 		sharedstorage:              ss,
-		dataexchange:               dx,
+		data:                       dataManager,
 		operations:                 om,
 		callbacks:                  cb,
 		broadcastBatchPayloadLimit: config.GetByteSize(coreconfig.BroadcastBatchPayloadLimit),
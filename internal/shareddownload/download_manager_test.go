@@ -29,7 +29,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
-	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
@@ -46,7 +46,7 @@ func newTestDownloadManager(t *testing.T) (*downloadManager, func()) {
 
 	mdi := &databasemocks.Plugin{}
 	mss := &sharedstoragemocks.Plugin{}
-	mdx := &dataexchangemocks.Plugin{}
+	mdm := &datamocks.Manager{}
 	mci := &shareddownloadmocks.Callbacks{}
 	mom := &operationmocks.Manager{}
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, []core.OpType{
@@ -56,7 +56,7 @@ func newTestDownloadManager(t *testing.T) (*downloadManager, func()) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	pm, err := NewDownloadManager(ctx, ns, mdi, mss, mdx, mom, mci)
+	pm, err := NewDownloadManager(ctx, ns, mdi, mss, mdm, mom, mci)
 	assert.NoError(t, err)
 
 	return pm.(*downloadManager), cancel
@@ -142,9 +142,16 @@ func TestDownloadBlobWithRetryOk(t *testing.T) {
 	mss.On("Name").Return("utss")
 	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
 
-	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
-	mdx.On("UploadBlob", mock.Anything, "ns1", *dataID, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop")).Twice()
-	mdx.On("UploadBlob", mock.Anything, "ns1", *dataID, mock.Anything).Return("privateRef1", blobHash, int64(12345), nil)
+	mdm := dm.data.(*datamocks.Manager)
+	encryption := &core.BlobEncryption{Algorithm: "AES-256-GCM", KeyID: "kek1"}
+	mdm.On("ImportBlob", mock.Anything, dataID, mock.Anything).Return(nil, fmt.Errorf("pop")).Twice()
+	mdm.On("ImportBlob", mock.Anything, dataID, mock.Anything).Return(&core.Blob{
+		Hash:       blobHash,
+		Size:       12345,
+		PayloadRef: "privateRef1",
+		DataID:     dataID,
+		Encryption: encryption,
+	}, nil)
 
 	called := make(chan struct{})
 
@@ -181,7 +188,7 @@ func TestDownloadBlobWithRetryOk(t *testing.T) {
 	}).Once()
 
 	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
-	mci.On("SharedStorageBlobDownloaded", *blobHash, int64(12345), "privateRef1", dataID).Return(nil)
+	mci.On("SharedStorageBlobDownloaded", *blobHash, int64(12345), "privateRef1", dataID, encryption).Return(nil)
 
 	err := dm.InitiateDownloadBlob(dm.ctx, txID, dataID, "ref1")
 	assert.NoError(t, err)
@@ -189,7 +196,7 @@ func TestDownloadBlobWithRetryOk(t *testing.T) {
 	<-called
 
 	mss.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 	mci.AssertExpectations(t)
 	mom.AssertExpectations(t)
 
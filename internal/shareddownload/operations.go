@@ -146,19 +146,19 @@ func (dm *downloadManager) downloadBlob(ctx context.Context, data downloadBlobDa
 	}
 	defer reader.Close()
 
-	// ... to data exchange
-	dxPayloadRef, hash, blobSize, err := dm.dataexchange.UploadBlob(ctx, dm.namespace.NetworkName, *data.DataID, reader)
+	// ... to data exchange, encrypting it on the way if blob encryption is enabled
+	blob, err := dm.data.ImportBlob(ctx, data.DataID, reader)
 	if err != nil {
 		return nil, false, i18n.WrapError(ctx, err, coremsgs.MsgDownloadSharedFailed, data.PayloadRef)
 	}
-	log.L(ctx).Infof("Transferred blob '%s' (%s) from shared storage '%s' to local data exchange '%s'", hash, units.HumanSizeWithPrecision(float64(blobSize), 2), data.PayloadRef, dxPayloadRef)
+	log.L(ctx).Infof("Transferred blob '%s' (%s) from shared storage '%s' to local data exchange '%s'", blob.Hash, units.HumanSizeWithPrecision(float64(blob.Size), 2), data.PayloadRef, blob.PayloadRef)
 
 	// then callback to store metadata
-	if err := dm.callbacks.SharedStorageBlobDownloaded(*hash, blobSize, dxPayloadRef, data.DataID); err != nil {
+	if err := dm.callbacks.SharedStorageBlobDownloaded(*blob.Hash, blob.Size, blob.PayloadRef, data.DataID, blob.Encryption); err != nil {
 		return nil, false, err
 	}
 
-	return getDownloadBlobOutputs(hash, blobSize, dxPayloadRef), true, nil
+	return getDownloadBlobOutputs(blob.Hash, blob.Size, blob.PayloadRef), true, nil
 }
 
 func (dm *downloadManager) OnOperationUpdate(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
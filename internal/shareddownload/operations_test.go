@@ -26,9 +26,10 @@ import (
 	"testing/iotest"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
-	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -118,8 +119,8 @@ func TestDownloadBlobDownloadDataReadFail(t *testing.T) {
 	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
 	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
 
-	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
-	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, reader).Return("", nil, int64(-1), fmt.Errorf("pop"))
+	mdm := dm.data.(*datamocks.Manager)
+	mdm.On("ImportBlob", mock.Anything, mock.Anything, reader).Return(nil, fmt.Errorf("pop"))
 
 	_, _, err := dm.downloadBlob(dm.ctx, downloadBlobData{
 		PayloadRef: "ref1",
@@ -128,7 +129,7 @@ func TestDownloadBlobDownloadDataReadFail(t *testing.T) {
 	assert.Regexp(t, "FF10376", err)
 
 	mss.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 }
 
 func TestDownloadBlobDownloadDataCBCalled(t *testing.T) {
@@ -141,11 +142,11 @@ func TestDownloadBlobDownloadDataCBCalled(t *testing.T) {
 	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
 	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
 
-	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
-	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, reader).Return("", fftypes.NewRandB32(), int64(-1), nil)
+	mdm := dm.data.(*datamocks.Manager)
+	mdm.On("ImportBlob", mock.Anything, mock.Anything, reader).Return(&core.Blob{Hash: fftypes.NewRandB32(), Size: -1}, nil)
 
 	mdc := &shareddownloadmocks.Callbacks{}
-	mdc.On("SharedStorageBlobDownloaded", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	mdc.On("SharedStorageBlobDownloaded", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	dm.callbacks = mdc
 
 	_, _, err := dm.downloadBlob(dm.ctx, downloadBlobData{
@@ -155,7 +156,7 @@ func TestDownloadBlobDownloadDataCBCalled(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 	mss.AssertExpectations(t)
-	mdx.AssertExpectations(t)
+	mdm.AssertExpectations(t)
 }
 
 func TestOperationUpdate(t *testing.T) {
@@ -0,0 +1,110 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
+)
+
+// payloadRefRegex matches the hex encoded SHA-256 hash of a payload, which is used as its payload reference
+var payloadRefRegex = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// FileSystem is a content addressed shared storage plugin, that stores each payload in a directory under
+// the SHA-256 hash of its content. The directory must be shared by all the members using it, so this
+// plugin is only suitable for development stacks where all the members run on a single host.
+type FileSystem struct {
+	ctx          context.Context
+	capabilities *sharedstorage.Capabilities
+	path         string
+}
+
+func (fs *FileSystem) Name() string {
+	return "filesystem"
+}
+
+func (fs *FileSystem) Init(ctx context.Context, config config.Section) error {
+	fs.ctx = log.WithLogField(ctx, "sharedstorage", "filesystem")
+
+	fs.path = config.GetString(FileSystemConfPath)
+	if fs.path == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(FileSystemConfPath), "filesystem")
+	}
+	if err := os.MkdirAll(fs.path, 0755); err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgSharedStorageFileErr)
+	}
+	fs.capabilities = &sharedstorage.Capabilities{}
+	return nil
+}
+
+func (fs *FileSystem) SetHandler(_ string, handler sharedstorage.Callbacks) {
+}
+
+func (fs *FileSystem) Capabilities() *sharedstorage.Capabilities {
+	return fs.capabilities
+}
+
+func (fs *FileSystem) UploadData(ctx context.Context, data io.Reader) (string, error) {
+	// Write to a temporary file while calculating the hash, then move it into place
+	tmpFile, err := os.CreateTemp(fs.path, ".upload-*")
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgSharedStorageFileErr)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmpFile, hash), data)
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	payloadRef := hex.EncodeToString(hash.Sum(nil))
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), filepath.Join(fs.path, payloadRef))
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgSharedStorageFileErr)
+	}
+	log.L(ctx).Infof("Shared storage file published %s Size=%d", payloadRef, size)
+	return payloadRef, nil
+}
+
+func (fs *FileSystem) DownloadData(ctx context.Context, payloadRef string) (data io.ReadCloser, err error) {
+	if !payloadRefRegex.MatchString(payloadRef) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSharedStorageInvalidPayloadRef, payloadRef)
+	}
+	f, err := os.Open(filepath.Join(fs.path, payloadRef))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgSharedStoragePayloadNotFound, payloadRef)
+		}
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSharedStorageFileErr)
+	}
+	log.L(ctx).Infof("Shared storage file retrieved %s", payloadRef)
+	return f, nil
+}
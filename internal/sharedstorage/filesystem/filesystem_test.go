@@ -0,0 +1,160 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/stretchr/testify/assert"
+)
+
+var utConfig = config.RootSection("filesystem_unit_tests")
+
+func resetConf() {
+	coreconfig.Reset()
+	fs := &FileSystem{}
+	fs.InitConfig(utConfig)
+}
+
+type errReader struct{}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	return 0, fmt.Errorf("pop")
+}
+
+func newTestFileSystem(t *testing.T) *FileSystem {
+	resetConf()
+	utConfig.Set(FileSystemConfPath, filepath.Join(t.TempDir(), "shared"))
+	fs := &FileSystem{}
+	err := fs.Init(context.Background(), utConfig)
+	assert.NoError(t, err)
+	return fs
+}
+
+func TestInit(t *testing.T) {
+	fs := newTestFileSystem(t)
+	cbs := &sharedstoragemocks.Callbacks{}
+	fs.SetHandler("ns1", cbs) // no-op
+	assert.Equal(t, "filesystem", fs.Name())
+	assert.NotNil(t, fs.Capabilities())
+	info, err := os.Stat(fs.path)
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestInitMissingPath(t *testing.T) {
+	resetConf()
+	fs := &FileSystem{}
+	err := fs.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138", err)
+}
+
+func TestInitBadPath(t *testing.T) {
+	resetConf()
+	file := filepath.Join(t.TempDir(), "file")
+	err := os.WriteFile(file, []byte{}, 0644)
+	assert.NoError(t, err)
+	utConfig.Set(FileSystemConfPath, filepath.Join(file, "shared"))
+	fs := &FileSystem{}
+	err = fs.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10478", err)
+}
+
+func TestUploadDownload(t *testing.T) {
+	fs := newTestFileSystem(t)
+
+	data := []byte("some data")
+	hash := sha256.Sum256(data)
+	payloadRef, err := fs.UploadData(context.Background(), bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash[:]), payloadRef)
+
+	// Uploading the same data again results in the same reference
+	payloadRef2, err := fs.UploadData(context.Background(), bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, payloadRef, payloadRef2)
+
+	r, err := fs.DownloadData(context.Background(), payloadRef)
+	assert.NoError(t, err)
+	defer r.Close()
+	downloaded, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(fs.path)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestUploadReadFail(t *testing.T) {
+	fs := newTestFileSystem(t)
+
+	_, err := fs.UploadData(context.Background(), &errReader{})
+	assert.Regexp(t, "FF10478.*pop", err)
+
+	entries, err := os.ReadDir(fs.path)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestUploadCreateFail(t *testing.T) {
+	fs := newTestFileSystem(t)
+	err := os.RemoveAll(fs.path)
+	assert.NoError(t, err)
+
+	_, err = fs.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10478", err)
+}
+
+func TestDownloadInvalidRef(t *testing.T) {
+	fs := newTestFileSystem(t)
+
+	_, err := fs.DownloadData(context.Background(), "../../etc/passwd")
+	assert.Regexp(t, "FF10477", err)
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	fs := newTestFileSystem(t)
+
+	hash := sha256.Sum256([]byte("some data"))
+	_, err := fs.DownloadData(context.Background(), hex.EncodeToString(hash[:]))
+	assert.Regexp(t, "FF10479", err)
+}
+
+func TestDownloadFail(t *testing.T) {
+	fs := newTestFileSystem(t)
+	err := os.RemoveAll(fs.path)
+	assert.NoError(t, err)
+	err = os.WriteFile(fs.path, []byte{}, 0644)
+	assert.NoError(t, err)
+
+	hash := sha256.Sum256([]byte("some data"))
+	_, err = fs.DownloadData(context.Background(), hex.EncodeToString(hash[:]))
+	assert.Regexp(t, "FF10478", err)
+}
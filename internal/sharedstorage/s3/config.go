@@ -0,0 +1,44 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// S3ConfBucket is the bucket in which payloads are stored
+	S3ConfBucket = "bucket"
+	// S3ConfRegion is the region used to sign requests
+	S3ConfRegion = "region"
+	// S3ConfAccessKeyID is the access key ID used to sign requests
+	S3ConfAccessKeyID = "accessKeyID"
+	// S3ConfSecretAccessKey is the secret access key used to sign requests
+	S3ConfSecretAccessKey = "secretAccessKey"
+	// S3ConfTempDir is the directory in which payloads are staged while their hash is calculated
+	S3ConfTempDir = "tempDir"
+)
+
+func (s *S3) InitConfig(config config.Section) {
+	ffresty.InitConfig(config)
+	config.AddKnownKey(S3ConfBucket)
+	config.AddKnownKey(S3ConfRegion, "us-east-1")
+	config.AddKnownKey(S3ConfAccessKeyID)
+	config.AddKnownKey(S3ConfSecretAccessKey)
+	config.AddKnownKey(S3ConfTempDir)
+}
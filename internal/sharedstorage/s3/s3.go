@@ -0,0 +1,209 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
+)
+
+// payloadRefRegex matches the hex encoded SHA-256 hash of a payload, which is used as its object key
+var payloadRefRegex = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// S3 is a content addressed shared storage plugin, that stores each payload in a bucket of an S3 compatible
+// object store under the SHA-256 hash of its content. Requests use path style addressing, so the configured
+// URL is the endpoint of the service rather than of the bucket.
+type S3 struct {
+	ctx             context.Context
+	capabilities    *sharedstorage.Capabilities
+	client          *resty.Client
+	endpoint        string
+	host            string
+	basePath        string
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	tempDir         string
+}
+
+func (s *S3) Name() string {
+	return "s3"
+}
+
+func (s *S3) Init(ctx context.Context, config config.Section) (err error) {
+	s.ctx = log.WithLogField(ctx, "sharedstorage", "s3")
+
+	endpoint := config.GetString(ffresty.HTTPConfigURL)
+	if endpoint == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(ffresty.HTTPConfigURL), "s3")
+	}
+	s.bucket = config.GetString(S3ConfBucket)
+	if s.bucket == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(S3ConfBucket), "s3")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return i18n.WrapError(ctx, err, i18n.MsgInvalidURL, endpoint)
+	}
+	s.endpoint = strings.TrimSuffix(endpoint, "/")
+	s.host = u.Host
+	s.basePath = strings.TrimSuffix(u.Path, "/")
+	s.region = config.GetString(S3ConfRegion)
+	s.accessKeyID = config.GetString(S3ConfAccessKeyID)
+	s.secretAccessKey = config.GetString(S3ConfSecretAccessKey)
+	s.tempDir = config.GetString(S3ConfTempDir)
+	s.client, err = ffresty.New(s.ctx, config)
+	if err != nil {
+		return err
+	}
+	s.capabilities = &sharedstorage.Capabilities{}
+	return nil
+}
+
+func (s *S3) SetHandler(_ string, handler sharedstorage.Callbacks) {
+}
+
+func (s *S3) Capabilities() *sharedstorage.Capabilities {
+	return s.capabilities
+}
+
+// requestHeaders returns the headers for a request for an object in the bucket, signed if credentials are configured
+func (s *S3) requestHeaders(method, path, payloadHash string) map[string]string {
+	now := time.Now()
+	signed := map[string]string{
+		"host":          s.host,
+		amzHeaderDate:   now.UTC().Format(amzDateFormat),
+		amzHeaderSHA256: payloadHash,
+	}
+	headers := map[string]string{
+		amzHeaderDate:   signed[amzHeaderDate],
+		amzHeaderSHA256: payloadHash,
+	}
+	if s.accessKeyID != "" {
+		headers["Authorization"] = s.signV4(method, s.basePath+path, signed, now)
+	}
+	return headers
+}
+
+// newRequest returns a request for an object in the bucket, signed if credentials are configured
+func (s *S3) newRequest(ctx context.Context, method, key, payloadHash string) (*resty.Request, string) {
+	path := fmt.Sprintf("/%s/%s", s.bucket, key)
+	req := s.client.R().
+		SetContext(ctx).
+		SetHeaders(s.requestHeaders(method, path, payloadHash))
+	return req, path
+}
+
+// stagePayload streams the content to a temporary file while calculating its hash, which is used as the key
+func (s *S3) stagePayload(ctx context.Context, data io.Reader) (file *os.File, size int64, payloadRef string, err error) {
+	file, err = os.CreateTemp(s.tempDir, "s3upload-")
+	if err != nil {
+		return nil, 0, "", i18n.WrapError(ctx, err, coremsgs.MsgS3RESTErr)
+	}
+	hash := sha256.New()
+	if size, err = io.Copy(io.MultiWriter(file, hash), data); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		removeStagedPayload(ctx, file)
+		return nil, 0, "", i18n.WrapError(ctx, err, coremsgs.MsgS3RESTErr)
+	}
+	return file, size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func removeStagedPayload(ctx context.Context, file *os.File) {
+	_ = file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		log.L(ctx).Warnf("Failed to remove staged payload '%s': %s", file.Name(), err)
+	}
+}
+
+func (s *S3) UploadData(ctx context.Context, data io.Reader) (string, error) {
+	// The content must be read in full to calculate the key, so it is staged to a file rather than held in memory
+	file, size, payloadRef, err := s.stagePayload(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	defer removeStagedPayload(ctx, file)
+
+	// The REST client buffers request bodies in memory, so the file is streamed using the underlying HTTP client.
+	// S3 does not accept chunked uploads, so the length is set explicitly.
+	path := fmt.Sprintf("/%s/%s", s.bucket, payloadRef)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.endpoint+path, file)
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgS3RESTErr)
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	for k, v := range s.requestHeaders(http.MethodPut, path, payloadRef) {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	res, err := s.client.GetClient().Do(req)
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgS3RESTErr)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		respData, _ := io.ReadAll(io.LimitReader(res.Body, 256))
+		return "", i18n.NewError(ctx, coremsgs.MsgS3RESTErr, string(respData))
+	}
+	log.L(ctx).Infof("S3 published %s Size=%d", payloadRef, size)
+	return payloadRef, nil
+}
+
+func (s *S3) DownloadData(ctx context.Context, payloadRef string) (data io.ReadCloser, err error) {
+	if !payloadRefRegex.MatchString(payloadRef) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSharedStorageInvalidPayloadRef, payloadRef)
+	}
+	req, path := s.newRequest(ctx, http.MethodGet, payloadRef, sha256Hex(nil))
+	res, err := req.
+		SetDoNotParseResponse(true).
+		Get(path)
+	ffresty.OnAfterResponse(s.client, res) // required using SetDoNotParseResponse
+	if err != nil || !res.IsSuccess() {
+		if res != nil && res.RawBody() != nil {
+			_ = res.RawBody().Close()
+		}
+		if err == nil && res.StatusCode() == http.StatusNotFound {
+			return nil, i18n.NewError(ctx, coremsgs.MsgSharedStoragePayloadNotFound, payloadRef)
+		}
+		return nil, ffresty.WrapRestErr(s.ctx, res, err, coremsgs.MsgS3RESTErr)
+	}
+	log.L(ctx).Infof("S3 retrieved %s", payloadRef)
+	return res.RawBody(), nil
+}
@@ -0,0 +1,307 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/stretchr/testify/assert"
+)
+
+var utConfig = config.RootSection("s3_unit_tests")
+
+func resetConf() {
+	coreconfig.Reset()
+	s := &S3{}
+	s.InitConfig(utConfig)
+}
+
+type errReader struct{}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	return 0, fmt.Errorf("pop")
+}
+
+// fakeS3 is an in-memory stand-in for an S3 compatible object store, that verifies request signatures
+type fakeS3 struct {
+	t       *testing.T
+	signer  *S3
+	objects map[string][]byte
+	mux     sync.Mutex
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	now, err := time.Parse(amzDateFormat, r.Header.Get(amzHeaderDate))
+	assert.NoError(f.t, err)
+	expected := f.signer.signV4(r.Method, r.URL.Path, map[string]string{
+		"host":          r.Host,
+		amzHeaderDate:   r.Header.Get(amzHeaderDate),
+		amzHeaderSHA256: r.Header.Get(amzHeaderSHA256),
+	}, now)
+	if r.Header.Get("Authorization") != expected {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		// S3 requires the length of the object up front
+		assert.Empty(f.t, r.TransferEncoding)
+		body, err := io.ReadAll(r.Body)
+		assert.Equal(f.t, r.ContentLength, int64(len(body)))
+		assert.NoError(f.t, err)
+		if sha256Hex(body) != r.Header.Get(amzHeaderSHA256) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3, func()) {
+	fake := &fakeS3{
+		t: t,
+		signer: &S3{
+			region:          "eu-west-1",
+			accessKeyID:     "access1",
+			secretAccessKey: "secret1",
+		},
+		objects: make(map[string][]byte),
+	}
+	server := httptest.NewServer(fake)
+
+	resetConf()
+	utConfig.Set(ffresty.HTTPConfigURL, server.URL+"/s3/")
+	utConfig.Set(S3ConfBucket, "bucket1")
+	utConfig.Set(S3ConfRegion, "eu-west-1")
+	utConfig.Set(S3ConfAccessKeyID, "access1")
+	utConfig.Set(S3ConfSecretAccessKey, "secret1")
+	s := &S3{}
+	err := s.Init(context.Background(), utConfig)
+	assert.NoError(t, err)
+	return s, fake, server.Close
+}
+
+func TestInit(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+
+	cbs := &sharedstoragemocks.Callbacks{}
+	s.SetHandler("ns1", cbs) // no-op
+	assert.Equal(t, "s3", s.Name())
+	assert.NotNil(t, s.Capabilities())
+	assert.Equal(t, "/s3", s.basePath)
+}
+
+func TestInitMissingURL(t *testing.T) {
+	resetConf()
+	utConfig.Set(S3ConfBucket, "bucket1")
+	s := &S3{}
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitMissingBucket(t *testing.T) {
+	resetConf()
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	s := &S3{}
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138.*bucket", err)
+}
+
+func TestInitBadURL(t *testing.T) {
+	resetConf()
+	utConfig.Set(ffresty.HTTPConfigURL, "::::")
+	utConfig.Set(S3ConfBucket, "bucket1")
+	s := &S3{}
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00149", err)
+}
+
+func TestInitBadTLSConfig(t *testing.T) {
+	resetConf()
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(S3ConfBucket, "bucket1")
+	tlsConf := utConfig.SubSection("tls")
+	tlsConf.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConf.Set(fftls.HTTPConfTLSCAFile, "!!!!!badness")
+	s := &S3{}
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestUploadDownload(t *testing.T) {
+	s, fake, done := newTestS3(t)
+	defer done()
+
+	data := []byte("some data")
+	payloadRef, err := s.UploadData(context.Background(), bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex(data), payloadRef)
+	assert.Equal(t, data, fake.objects["/s3/bucket1/"+payloadRef])
+
+	r, err := s.DownloadData(context.Background(), payloadRef)
+	assert.NoError(t, err)
+	defer r.Close()
+	downloaded, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+}
+
+func TestUploadStagedPayloadRemoved(t *testing.T) {
+	s, fake, done := newTestS3(t)
+	defer done()
+	s.tempDir = t.TempDir()
+
+	data := bytes.Repeat([]byte("some data "), 500000)
+	payloadRef, err := s.UploadData(context.Background(), bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex(data), payloadRef)
+	assert.Equal(t, data, fake.objects["/s3/bucket1/"+payloadRef])
+
+	staged, err := os.ReadDir(s.tempDir)
+	assert.NoError(t, err)
+	assert.Empty(t, staged)
+}
+
+func TestUploadEmpty(t *testing.T) {
+	s, fake, done := newTestS3(t)
+	defer done()
+
+	payloadRef, err := s.UploadData(context.Background(), bytes.NewReader([]byte{}))
+	assert.NoError(t, err)
+	assert.Equal(t, sha256Hex(nil), payloadRef)
+	assert.Empty(t, fake.objects["/s3/bucket1/"+payloadRef])
+}
+
+func TestUploadBadTempDir(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+	s.tempDir = filepath.Join(t.TempDir(), "missing")
+
+	_, err := s.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestUploadBadEndpoint(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+	s.endpoint = "::::"
+
+	_, err := s.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestUploadConnectionFail(t *testing.T) {
+	s, _, done := newTestS3(t)
+	done()
+
+	_, err := s.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestRemoveStagedPayloadFail(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "s3upload-")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(file.Name()))
+
+	removeStagedPayload(context.Background(), file) // only logged
+}
+
+func TestUploadUnsigned(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+	s.accessKeyID = ""
+
+	_, err := s.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestUploadReadFail(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+
+	_, err := s.UploadData(context.Background(), &errReader{})
+	assert.Regexp(t, "FF10480.*pop", err)
+}
+
+func TestUploadBadSignature(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+	s.secretAccessKey = "wrong"
+
+	_, err := s.UploadData(context.Background(), bytes.NewReader([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestDownloadInvalidRef(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+
+	_, err := s.DownloadData(context.Background(), "../bucket2/"+strings.Repeat("0", 64))
+	assert.Regexp(t, "FF10477", err)
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+
+	_, err := s.DownloadData(context.Background(), sha256Hex([]byte("some data")))
+	assert.Regexp(t, "FF10479", err)
+}
+
+func TestDownloadBadSignature(t *testing.T) {
+	s, _, done := newTestS3(t)
+	defer done()
+	s.secretAccessKey = "wrong"
+
+	_, err := s.DownloadData(context.Background(), sha256Hex([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
+
+func TestDownloadConnectionFail(t *testing.T) {
+	s, _, done := newTestS3(t)
+	done()
+
+	_, err := s.DownloadData(context.Background(), sha256Hex([]byte("some data")))
+	assert.Regexp(t, "FF10480", err)
+}
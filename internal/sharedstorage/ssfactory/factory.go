@@ -23,12 +23,16 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/sharedstorage/filesystem"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ipfs"
+	"github.com/hyperledger/firefly/internal/sharedstorage/s3"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
 )
 
 var pluginsByName = map[string]func() sharedstorage.Plugin{
-	(*ipfs.IPFS)(nil).Name(): func() sharedstorage.Plugin { return &ipfs.IPFS{} },
+	(*ipfs.IPFS)(nil).Name():             func() sharedstorage.Plugin { return &ipfs.IPFS{} },
+	(*filesystem.FileSystem)(nil).Name(): func() sharedstorage.Plugin { return &filesystem.FileSystem{} },
+	(*s3.S3)(nil).Name():                 func() sharedstorage.Plugin { return &s3.S3{} },
 }
 
 func InitConfig(config config.ArraySection) {
@@ -149,6 +149,7 @@ type tokenData struct {
 	TXType      core.TransactionType `json:"txtype,omitempty"`
 	Message     *fftypes.UUID        `json:"message,omitempty"`
 	MessageHash *fftypes.Bytes32     `json:"messageHash,omitempty"`
+	Operation   *fftypes.UUID        `json:"op,omitempty"`
 }
 
 type createPool struct {
@@ -232,6 +233,11 @@ type tokenApproval struct {
 	Interface   interface{}        `json:"interface,omitempty"`
 }
 
+type balanceResponse struct {
+	Balance     string `json:"balance"`
+	BlockNumber string `json:"blockNumber,omitempty"`
+}
+
 type tokenError struct {
 	Error   string `json:"error,omitempty"`
 	Message string `json:"message,omitempty"`
@@ -262,7 +268,9 @@ func (ft *FFTokens) Init(ctx context.Context, cancelCtx context.CancelFunc, name
 	ft.ctx = log.WithLogField(ctx, "proto", "fftokens")
 	ft.cancelCtx = cancelCtx
 	ft.configuredName = name
-	ft.capabilities = &tokens.Capabilities{}
+	ft.capabilities = &tokens.Capabilities{
+		BalanceQuery: true,
+	}
 	ft.callbacks = callbacks{
 		plugin:     ft,
 		handlers:   make(map[string]tokens.Callbacks),
@@ -537,7 +545,8 @@ func (ft *FFTokens) handleTokenTransfer(ctx context.Context, t core.TokenTransfe
 				Type: txType,
 			},
 		},
-		Event: blockchainEvent,
+		Operation: transferData.Operation,
+		Event:     blockchainEvent,
 	}
 
 	// If there's an error dispatching the event, we must return the error and shutdown
@@ -831,108 +840,125 @@ func (ft *FFTokens) CheckInterface(ctx context.Context, pool *core.TokenPool, me
 	return fftypes.JSONAnyPtrBytes(res.Body()), nil
 }
 
-func (ft *FFTokens) MintTokens(ctx context.Context, nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) error {
+func transferData(nsOpID string, transfer *core.TokenTransfer) string {
+	// The operation is echoed back on the resulting events, so they can be matched to the operation directly
+	_, opID, _ := core.ParseNamespacedOpID(context.Background(), nsOpID)
 	data, _ := json.Marshal(tokenData{
-		TX:          mint.TX.ID,
-		TXType:      mint.TX.Type,
-		Message:     mint.Message,
-		MessageHash: mint.MessageHash,
+		TX:          transfer.TX.ID,
+		TXType:      transfer.TX.Type,
+		Message:     transfer.Message,
+		MessageHash: transfer.MessageHash,
+		Operation:   opID,
 	})
+	return string(data)
+}
 
-	var iface interface{}
+func transferInterface(methods *fftypes.JSONAny, method string) interface{} {
 	if methods != nil {
-		iface = methods.JSONObject()["mint"]
+		return methods.JSONObject()[method]
 	}
+	return nil
+}
 
-	var errRes tokenError
-	res, err := ft.client.R().SetContext(ctx).
-		SetBody(&mintTokens{
-			PoolLocator: poolLocator,
-			TokenIndex:  mint.TokenIndex,
-			To:          mint.To,
-			Amount:      mint.Amount.Int().String(),
-			RequestID:   nsOpID,
-			Signer:      mint.Key,
-			Data:        string(data),
-			URI:         mint.URI,
-			Config:      mint.Config,
-			Interface:   iface,
-		}).
-		SetError(&errRes).
-		Post("/api/v1/mint")
-	if err != nil || !res.IsSuccess() {
-		return wrapError(ctx, &errRes, res, err)
+func buildMintRequest(nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) *mintTokens {
+	return &mintTokens{
+		PoolLocator: poolLocator,
+		TokenIndex:  mint.TokenIndex,
+		To:          mint.To,
+		Amount:      mint.Amount.Int().String(),
+		RequestID:   nsOpID,
+		Signer:      mint.Key,
+		Data:        transferData(nsOpID, mint),
+		URI:         mint.URI,
+		Config:      mint.Config,
+		Interface:   transferInterface(methods, "mint"),
 	}
-	return nil
 }
 
-func (ft *FFTokens) BurnTokens(ctx context.Context, nsOpID string, poolLocator string, burn *core.TokenTransfer, methods *fftypes.JSONAny) error {
-	data, _ := json.Marshal(tokenData{
-		TX:          burn.TX.ID,
-		TXType:      burn.TX.Type,
-		Message:     burn.Message,
-		MessageHash: burn.MessageHash,
-	})
+func buildBurnRequest(nsOpID string, poolLocator string, burn *core.TokenTransfer, methods *fftypes.JSONAny) *burnTokens {
+	return &burnTokens{
+		PoolLocator: poolLocator,
+		TokenIndex:  burn.TokenIndex,
+		From:        burn.From,
+		Amount:      burn.Amount.Int().String(),
+		RequestID:   nsOpID,
+		Signer:      burn.Key,
+		Data:        transferData(nsOpID, burn),
+		Config:      burn.Config,
+		Interface:   transferInterface(methods, "burn"),
+	}
+}
 
-	var iface interface{}
-	if methods != nil {
-		iface = methods.JSONObject()["burn"]
+func buildTransferRequest(nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) *transferTokens {
+	return &transferTokens{
+		PoolLocator: poolLocator,
+		TokenIndex:  transfer.TokenIndex,
+		From:        transfer.From,
+		To:          transfer.To,
+		Amount:      transfer.Amount.Int().String(),
+		RequestID:   nsOpID,
+		Signer:      transfer.Key,
+		Data:        transferData(nsOpID, transfer),
+		Config:      transfer.Config,
+		Interface:   transferInterface(methods, "transfer"),
 	}
+}
 
+func (ft *FFTokens) postTransfer(ctx context.Context, path string, body interface{}) error {
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
-		SetBody(&burnTokens{
-			PoolLocator: poolLocator,
-			TokenIndex:  burn.TokenIndex,
-			From:        burn.From,
-			Amount:      burn.Amount.Int().String(),
-			RequestID:   nsOpID,
-			Signer:      burn.Key,
-			Data:        string(data),
-			Config:      burn.Config,
-			Interface:   iface,
-		}).
+		SetBody(body).
 		SetError(&errRes).
-		Post("/api/v1/burn")
+		Post(path)
 	if err != nil || !res.IsSuccess() {
 		return wrapError(ctx, &errRes, res, err)
 	}
 	return nil
 }
 
+func (ft *FFTokens) MintTokens(ctx context.Context, nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return ft.postTransfer(ctx, "/api/v1/mint", buildMintRequest(nsOpID, poolLocator, mint, methods))
+}
+
+func (ft *FFTokens) BurnTokens(ctx context.Context, nsOpID string, poolLocator string, burn *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return ft.postTransfer(ctx, "/api/v1/burn", buildBurnRequest(nsOpID, poolLocator, burn, methods))
+}
+
 func (ft *FFTokens) TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error {
-	data, _ := json.Marshal(tokenData{
-		TX:          transfer.TX.ID,
-		TXType:      transfer.TX.Type,
-		Message:     transfer.Message,
-		MessageHash: transfer.MessageHash,
-	})
+	return ft.postTransfer(ctx, "/api/v1/transfer", buildTransferRequest(nsOpID, poolLocator, transfer, methods))
+}
 
-	var iface interface{}
-	if methods != nil {
-		iface = methods.JSONObject()["transfer"]
-	}
+// TransferTokensBatch is not supported, as the fftokens connector API has no batch endpoint.
+// Batches are submitted to fftokens as individual mints, burns and transfers.
+func (ft *FFTokens) TransferTokensBatch(ctx context.Context, items []*tokens.TransferBatchItem) error {
+	return i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchNotSupported, ft.configuredName)
+}
 
+func (ft *FFTokens) QueryBalance(ctx context.Context, poolLocator, tokenIndex, key, blockNumber string) (*tokens.Balance, error) {
+	var balanceRes balanceResponse
 	var errRes tokenError
-	res, err := ft.client.R().SetContext(ctx).
-		SetBody(&transferTokens{
-			PoolLocator: poolLocator,
-			TokenIndex:  transfer.TokenIndex,
-			From:        transfer.From,
-			To:          transfer.To,
-			Amount:      transfer.Amount.Int().String(),
-			RequestID:   nsOpID,
-			Signer:      transfer.Key,
-			Data:        string(data),
-			Config:      transfer.Config,
-			Interface:   iface,
-		}).
-		SetError(&errRes).
-		Post("/api/v1/transfer")
+	req := ft.client.R().SetContext(ctx).
+		SetQueryParam("poolLocator", poolLocator).
+		SetQueryParam("tokenIndex", tokenIndex).
+		SetQueryParam("account", key).
+		SetResult(&balanceRes).
+		SetError(&errRes)
+	if blockNumber != "" {
+		req.SetQueryParam("blockNumber", blockNumber)
+	}
+	res, err := req.Get("/api/v1/balance")
 	if err != nil || !res.IsSuccess() {
-		return wrapError(ctx, &errRes, res, err)
+		return nil, wrapError(ctx, &errRes, res, err)
 	}
-	return nil
+
+	var balance fftypes.FFBigInt
+	if _, ok := balance.Int().SetString(balanceRes.Balance, 10); !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenBalanceInvalid, balanceRes.Balance)
+	}
+	return &tokens.Balance{
+		Balance:     &balance,
+		BlockNumber: balanceRes.BlockNumber,
+	}, nil
 }
 
 func (ft *FFTokens) TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error {
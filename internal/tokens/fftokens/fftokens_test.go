@@ -641,11 +641,8 @@ func TestMintTokens(t *testing.T) {
 					"foo": "bar",
 				},
 				"requestId": "ns1:" + opID.String(),
-				"data": fftypes.JSONObject{
-					"tx":     mint.TX.ID.String(),
-					"txtype": core.TransactionTypeTokenTransfer.String(),
-				}.String(),
-				"uri": "FLAPFLIP",
+				"data":      fmt.Sprintf(`{"tx":"%s","txtype":"token_transfer","op":"%s"}`, mint.TX.ID, opID),
+				"uri":       "FLAPFLIP",
 			}, body)
 
 			res := &http.Response{
@@ -698,10 +695,7 @@ func TestMintTokensWithInterface(t *testing.T) {
 					"foo": "bar",
 				},
 				"requestId": "ns1:" + opID.String(),
-				"data": fftypes.JSONObject{
-					"tx":     mint.TX.ID.String(),
-					"txtype": core.TransactionTypeTokenTransfer.String(),
-				}.String(),
+				"data":      fmt.Sprintf(`{"tx":"%s","txtype":"token_transfer","op":"%s"}`, mint.TX.ID, opID),
 				"uri":       "FLAPFLIP",
 				"interface": "test_interface",
 			}, body)
@@ -839,10 +833,7 @@ func TestBurnTokens(t *testing.T) {
 					"foo": "bar",
 				},
 				"requestId": "ns1:" + opID.String(),
-				"data": fftypes.JSONObject{
-					"tx":     burn.TX.ID.String(),
-					"txtype": core.TransactionTypeTokenTransfer.String(),
-				}.String(),
+				"data":      fmt.Sprintf(`{"tx":"%s","txtype":"token_transfer","op":"%s"}`, burn.TX.ID, opID),
 			}, body)
 
 			res := &http.Response{
@@ -912,10 +903,7 @@ func TestTransferTokens(t *testing.T) {
 					"foo": "bar",
 				},
 				"requestId": "ns1:" + opID.String(),
-				"data": fftypes.JSONObject{
-					"tx":     transfer.TX.ID.String(),
-					"txtype": core.TransactionTypeTokenTransfer.String(),
-				}.String(),
+				"data":      fmt.Sprintf(`{"tx":"%s","txtype":"token_transfer","op":"%s"}`, transfer.TX.ID, opID),
 			}, body)
 
 			res := &http.Response{
@@ -947,6 +935,84 @@ func TestTransferTokensError(t *testing.T) {
 	assert.Regexp(t, "FF10274", err)
 }
 
+func TestTransferTokensBatchNotSupported(t *testing.T) {
+	h, _, _, _, done := newTestFFTokens(t)
+	defer done()
+
+	assert.False(t, h.Capabilities().BatchTransfers)
+	items := []*tokens.TransferBatchItem{
+		{NSOpID: "ns1:op1", PoolLocator: "F1", Transfer: &core.TokenTransfer{Type: core.TokenTransferTypeMint}},
+	}
+	err := h.TransferTokensBatch(context.Background(), items)
+	assert.Regexp(t, "FF10586", err)
+}
+
+func TestQueryBalance(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	assert.True(t, h.Capabilities().BalanceQuery)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "F1", req.URL.Query().Get("poolLocator"))
+			assert.Equal(t, "1", req.URL.Query().Get("tokenIndex"))
+			assert.Equal(t, "0x123", req.URL.Query().Get("account"))
+			assert.Equal(t, "42", req.URL.Query().Get("blockNumber"))
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"balance":     "100000000000000000000",
+				"blockNumber": "42",
+			})(req)
+		})
+
+	balance, err := h.QueryBalance(context.Background(), "F1", "1", "0x123", "42")
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000000000000000", balance.Balance.String())
+	assert.Equal(t, "42", balance.BlockNumber)
+}
+
+func TestQueryBalanceLatest(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.False(t, req.URL.Query().Has("blockNumber"))
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"balance": "5",
+			})(req)
+		})
+
+	balance, err := h.QueryBalance(context.Background(), "F1", "1", "0x123", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", balance.Balance.String())
+	assert.Empty(t, balance.BlockNumber)
+}
+
+func TestQueryBalanceError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.QueryBalance(context.Background(), "F1", "1", "0x123", "")
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestQueryBalanceBadBalance(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"balance": "lots",
+		}))
+
+	_, err := h.QueryBalance(context.Background(), "F1", "1", "0x123", "")
+	assert.Regexp(t, "FF10507.*lots", err)
+}
+
 func TestIgnoredEvents(t *testing.T) {
 	h, toServer, fromServer, _, done := newTestFFTokens(t)
 	defer done()
@@ -1344,9 +1410,10 @@ func TestTransferEvents(t *testing.T) {
 	msg = <-toServer
 	assert.Equal(t, `{"data":{"id":"10"},"event":"ack"}`, string(msg))
 
+	opID := fftypes.NewUUID()
 	// token-mint: success
 	mcb.On("TokensTransferred", h, mock.MatchedBy(func(t *tokens.TokenTransfer) bool {
-		return t.Amount.Int().Int64() == 2 && t.To == "0x0" && t.TokenIndex == "" && *t.TX.ID == *txID && t.PoolLocator == "F1" && t.Event.ProtocolID == "000000000010/000020/000030"
+		return t.Amount.Int().Int64() == 2 && t.To == "0x0" && t.TokenIndex == "" && *t.TX.ID == *txID && t.PoolLocator == "F1" && t.Event.ProtocolID == "000000000010/000020/000030" && *t.Operation == *opID
 	})).Return(nil).Once()
 	fromServer <- fftypes.JSONObject{
 		"id":    "11",
@@ -1358,7 +1425,7 @@ func TestTransferEvents(t *testing.T) {
 			"signer":      "0x0",
 			"to":          "0x0",
 			"amount":      "2",
-			"data":        fftypes.JSONObject{"tx": txID.String()}.String(),
+			"data":        fftypes.JSONObject{"tx": txID.String(), "op": opID.String()}.String(),
 			"blockchain": fftypes.JSONObject{
 				"id": "000000000010/000020/000030",
 				"info": fftypes.JSONObject{
@@ -86,3 +86,15 @@ func RetrieveTokenApprovalInputs(ctx context.Context, op *core.Operation) (appro
 	}
 	return &approve, nil
 }
+
+func AddTokenReconcileInputs(op *core.Operation, poolID *fftypes.UUID, correct bool) {
+	op.Input = fftypes.JSONObject{
+		"pool":    poolID.String(),
+		"correct": correct,
+	}
+}
+
+func RetrieveTokenReconcileInputs(ctx context.Context, op *core.Operation) (poolID *fftypes.UUID, correct bool, err error) {
+	poolID, err = fftypes.ParseUUID(ctx, op.Input.GetString("pool"))
+	return poolID, op.Input.GetBool("correct"), err
+}
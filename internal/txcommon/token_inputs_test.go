@@ -204,3 +204,38 @@ func TestRetrieveTokenApprovalInputsBadID(t *testing.T) {
 	_, err := RetrieveTokenApprovalInputs(context.Background(), op)
 	assert.Regexp(t, "FF00127", err)
 }
+
+func TestAddTokenReconcileInputs(t *testing.T) {
+	op := &core.Operation{}
+	poolID := fftypes.NewUUID()
+
+	AddTokenReconcileInputs(op, poolID, true)
+	assert.Equal(t, poolID.String(), op.Input.GetString("pool"))
+	assert.True(t, op.Input.GetBool("correct"))
+}
+
+func TestRetrieveTokenReconcileInputs(t *testing.T) {
+	id := fftypes.NewUUID()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"pool":    id.String(),
+			"correct": true,
+		},
+	}
+
+	poolID, correct, err := RetrieveTokenReconcileInputs(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *poolID)
+	assert.True(t, correct)
+}
+
+func TestRetrieveTokenReconcileInputsBadID(t *testing.T) {
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"pool": "bad",
+		},
+	}
+
+	_, _, err := RetrieveTokenReconcileInputs(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
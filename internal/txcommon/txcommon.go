@@ -37,6 +37,7 @@ type Helper interface {
 	GetTransactionByIDCached(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error)
 	GetBlockchainEventByIDCached(ctx context.Context, id *fftypes.UUID) (*core.BlockchainEvent, error)
 	FindOperationInTransaction(ctx context.Context, tx *fftypes.UUID, opType core.OpType) (*core.Operation, error)
+	FindOperationsInTransaction(ctx context.Context, tx *fftypes.UUID, opType core.OpType) ([]*core.Operation, error)
 }
 
 type transactionHelper struct {
@@ -216,14 +217,19 @@ func (t *transactionHelper) InsertOrGetBlockchainEvent(ctx context.Context, even
 }
 
 func (t *transactionHelper) FindOperationInTransaction(ctx context.Context, tx *fftypes.UUID, opType core.OpType) (*core.Operation, error) {
+	ops, err := t.FindOperationsInTransaction(ctx, tx, opType)
+	if err != nil || len(ops) == 0 {
+		return nil, err
+	}
+	return ops[0], nil
+}
+
+func (t *transactionHelper) FindOperationsInTransaction(ctx context.Context, tx *fftypes.UUID, opType core.OpType) ([]*core.Operation, error) {
 	fb := database.OperationQueryFactory.NewFilter(ctx)
 	filter := fb.And(
 		fb.Eq("tx", tx),
 		fb.Eq("type", opType),
 	)
 	ops, _, err := t.database.GetOperations(ctx, t.namespace, filter)
-	if err != nil || len(ops) == 0 {
-		return nil, err
-	}
-	return ops[0], nil
+	return ops, err
 }
@@ -60,6 +60,32 @@ func (_m *Manager) BurnTokens(ctx context.Context, transfer *core.TokenTransferI
 	return r0, r1
 }
 
+// BurnTokensBatch provides a mock function with given fields: ctx, batch
+func (_m *Manager) BurnTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	ret := _m.Called(ctx, batch)
+
+	var r0 *core.TokenTransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)); ok {
+		return rf(ctx, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) *core.TokenTransferBatch); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferBatchInput) error); ok {
+		r1 = rf(ctx, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTokenPool provides a mock function with given fields: ctx, pool, waitConfirm
 func (_m *Manager) CreateTokenPool(ctx context.Context, pool *core.TokenPoolInput, waitConfirm bool) (*core.TokenPool, error) {
 	ret := _m.Called(ctx, pool, waitConfirm)
@@ -205,6 +231,76 @@ func (_m *Manager) GetTokenApprovals(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetTokenBalanceHistory provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenBalanceHistory(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenBalanceChange
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenBalanceChange); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBalanceMismatches provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenBalanceMismatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenBalances provides a mock function with given fields: ctx, filter
 func (_m *Manager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -240,6 +336,41 @@ func (_m *Manager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter)
 	return r0, r1, r2
 }
 
+// GetTokenBalancesAt provides a mock function with given fields: ctx, point, filter
+func (_m *Manager) GetTokenBalancesAt(ctx context.Context, point *core.TokenBalancePoint, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, point, filter)
+
+	var r0 []*core.TokenBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBalancePoint, ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, point, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBalancePoint, ffapi.AndFilter) []*core.TokenBalance); ok {
+		r0 = rf(ctx, point, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenBalancePoint, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, point, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *core.TokenBalancePoint, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, point, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenConnectors provides a mock function with given fields: ctx
 func (_m *Manager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
 	ret := _m.Called(ctx)
@@ -256,6 +387,32 @@ func (_m *Manager) GetTokenConnectors(ctx context.Context) []*core.TokenConnecto
 	return r0
 }
 
+// GetTokenPoolBalanceSnapshot provides a mock function with given fields: ctx, poolNameOrID, point
+func (_m *Manager) GetTokenPoolBalanceSnapshot(ctx context.Context, poolNameOrID string, point *core.TokenBalancePoint) (*core.TokenBalanceSnapshot, error) {
+	ret := _m.Called(ctx, poolNameOrID, point)
+
+	var r0 *core.TokenBalanceSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenBalancePoint) (*core.TokenBalanceSnapshot, error)); ok {
+		return rf(ctx, poolNameOrID, point)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenBalancePoint) *core.TokenBalanceSnapshot); ok {
+		r0 = rf(ctx, poolNameOrID, point)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBalanceSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenBalancePoint) error); ok {
+		r1 = rf(ctx, poolNameOrID, point)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenPoolByID provides a mock function with given fields: ctx, id
 func (_m *Manager) GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*core.TokenPool, error) {
 	ret := _m.Called(ctx, id)
@@ -369,6 +526,32 @@ func (_m *Manager) GetTokenPools(ctx context.Context, filter ffapi.AndFilter) ([
 	return r0, r1, r2
 }
 
+// GetTokenTransferBatch provides a mock function with given fields: ctx, txID
+func (_m *Manager) GetTokenTransferBatch(ctx context.Context, txID string) (*core.TokenTransferBatch, error) {
+	ret := _m.Called(ctx, txID)
+
+	var r0 *core.TokenTransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenTransferBatch, error)); ok {
+		return rf(ctx, txID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenTransferBatch); ok {
+		r0 = rf(ctx, txID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, txID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenTransferByID provides a mock function with given fields: ctx, id
 func (_m *Manager) GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error) {
 	ret := _m.Called(ctx, id)
@@ -456,6 +639,32 @@ func (_m *Manager) MintTokens(ctx context.Context, transfer *core.TokenTransferI
 	return r0, r1
 }
 
+// MintTokensBatch provides a mock function with given fields: ctx, batch
+func (_m *Manager) MintTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	ret := _m.Called(ctx, batch)
+
+	var r0 *core.TokenTransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)); ok {
+		return rf(ctx, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) *core.TokenTransferBatch); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferBatchInput) error); ok {
+		r1 = rf(ctx, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -528,6 +737,32 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// ReconcileTokenBalances provides a mock function with given fields: ctx, input
+func (_m *Manager) ReconcileTokenBalances(ctx context.Context, input *core.TokenReconcileInput) (*core.TokenReconciliation, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *core.TokenReconciliation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenReconcileInput) (*core.TokenReconciliation, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenReconcileInput) *core.TokenReconciliation); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenReconciliation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenReconcileInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolvePoolMethods provides a mock function with given fields: ctx, pool
 func (_m *Manager) ResolvePoolMethods(ctx context.Context, pool *core.TokenPool) error {
 	ret := _m.Called(ctx, pool)
@@ -627,6 +862,32 @@ func (_m *Manager) TransferTokens(ctx context.Context, transfer *core.TokenTrans
 	return r0, r1
 }
 
+// TransferTokensBatch provides a mock function with given fields: ctx, batch
+func (_m *Manager) TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error) {
+	ret := _m.Called(ctx, batch)
+
+	var r0 *core.TokenTransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) (*core.TokenTransferBatch, error)); ok {
+		return rf(ctx, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput) *core.TokenTransferBatch); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferBatchInput) error); ok {
+		r1 = rf(ctx, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewManager interface {
 	mock.TestingT
 	Cleanup(func())
@@ -44,13 +44,13 @@ func (_m *Callbacks) BlockchainEvent(event *blockchain.EventWithSubscription) er
 	return r0
 }
 
-// BlockchainNetworkAction provides a mock function with given fields: action, location, event, signingKey
-func (_m *Callbacks) BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
-	ret := _m.Called(action, location, event, signingKey)
+// BlockchainNetworkAction provides a mock function with given fields: action, payload, location, event, signingKey
+func (_m *Callbacks) BlockchainNetworkAction(action string, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
+	ret := _m.Called(action, payload, location, event, signingKey)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *fftypes.JSONAny, *blockchain.Event, *core.VerifierRef) error); ok {
-		r0 = rf(action, location, event, signingKey)
+	if rf, ok := ret.Get(0).(func(string, string, *fftypes.JSONAny, *blockchain.Event, *core.VerifierRef) error); ok {
+		r0 = rf(action, payload, location, event, signingKey)
 	} else {
 		r0 = ret.Error(0)
 	}
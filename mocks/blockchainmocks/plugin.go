@@ -468,13 +468,13 @@ func (_m *Plugin) SubmitBatchPin(ctx context.Context, nsOpID string, networkName
 	return r0
 }
 
-// SubmitNetworkAction provides a mock function with given fields: ctx, nsOpID, signingKey, action, location
-func (_m *Plugin) SubmitNetworkAction(ctx context.Context, nsOpID string, signingKey string, action fftypes.FFEnum, location *fftypes.JSONAny) error {
-	ret := _m.Called(ctx, nsOpID, signingKey, action, location)
+// SubmitNetworkAction provides a mock function with given fields: ctx, nsOpID, signingKey, action, payload, location
+func (_m *Plugin) SubmitNetworkAction(ctx context.Context, nsOpID string, signingKey string, action fftypes.FFEnum, payload string, location *fftypes.JSONAny) error {
+	ret := _m.Called(ctx, nsOpID, signingKey, action, payload, location)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, fftypes.FFEnum, *fftypes.JSONAny) error); ok {
-		r0 = rf(ctx, nsOpID, signingKey, action, location)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, fftypes.FFEnum, string, *fftypes.JSONAny) error); ok {
+		r0 = rf(ctx, nsOpID, signingKey, action, payload, location)
 	} else {
 		r0 = ret.Error(0)
 	}
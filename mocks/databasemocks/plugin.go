@@ -39,6 +39,20 @@ func (_m *Plugin) Capabilities() *database.Capabilities {
 	return r0
 }
 
+// DeleteBatch provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteBatch(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteBlob provides a mock function with given fields: ctx, sequence
 func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	ret := _m.Called(ctx, sequence)
@@ -53,6 +67,34 @@ func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	return r0
 }
 
+// DeleteBlobUpload provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBlockchainEvents provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteBlockchainEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteContractAPI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteContractAPI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -95,6 +137,48 @@ func (_m *Plugin) DeleteData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// DeleteDeadLetter provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDeadLetters provides a mock function with given fields: ctx, namespace, subscriptionID
+func (_m *Plugin) DeleteDeadLetters(ctx context.Context, namespace string, subscriptionID *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, subscriptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEvents provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteFFI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -109,6 +193,20 @@ func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.U
 	return r0
 }
 
+// DeleteMessage provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteMessage(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteNonce provides a mock function with given fields: ctx, hash
 func (_m *Plugin) DeleteNonce(ctx context.Context, hash *fftypes.Bytes32) error {
 	ret := _m.Called(ctx, hash)
@@ -137,6 +235,20 @@ func (_m *Plugin) DeleteOffset(ctx context.Context, t fftypes.FFEnum, name strin
 	return r0
 }
 
+// DeleteOperations provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteOperations(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscriptionByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -207,6 +319,20 @@ func (_m *Plugin) DeleteTokenTransfers(ctx context.Context, namespace string, po
 	return r0
 }
 
+// DeleteTokenTransfersByID provides a mock function with given fields: ctx, namespace, localIDs
+func (_m *Plugin) DeleteTokenTransfersByID(ctx context.Context, namespace string, localIDs []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, localIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, localIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -320,6 +446,67 @@ func (_m *Plugin) GetBatches(ctx context.Context, namespace string, filter ffapi
 	return r0, r1, r2
 }
 
+// GetBlobUploadByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.BlobUpload, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.BlobUpload); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlobUploads provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.BlobUpload
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.BlobUpload); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetBlobs provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetBlobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Blob, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -520,25 +707,25 @@ func (_m *Plugin) GetContractAPIByName(ctx context.Context, namespace string, na
 	return r0, r1
 }
 
-// GetContractAPIByNetworkName provides a mock function with given fields: ctx, namespace, networkName
-func (_m *Plugin) GetContractAPIByNetworkName(ctx context.Context, namespace string, networkName string) (*core.ContractAPI, error) {
-	ret := _m.Called(ctx, namespace, networkName)
+// GetContractAPIByNetworkName provides a mock function with given fields: ctx, namespace, networkName, group
+func (_m *Plugin) GetContractAPIByNetworkName(ctx context.Context, namespace string, networkName string, group *fftypes.Bytes32) (*core.ContractAPI, error) {
+	ret := _m.Called(ctx, namespace, networkName, group)
 
 	var r0 *core.ContractAPI
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.ContractAPI, error)); ok {
-		return rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) (*core.ContractAPI, error)); ok {
+		return rf(ctx, namespace, networkName, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.ContractAPI); ok {
-		r0 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) *core.ContractAPI); ok {
+		r0 = rf(ctx, namespace, networkName, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.ContractAPI)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, namespace, networkName, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -842,25 +1029,25 @@ func (_m *Plugin) GetDatatypeByID(ctx context.Context, namespace string, id *fft
 	return r0, r1
 }
 
-// GetDatatypeByName provides a mock function with given fields: ctx, namespace, name, version
-func (_m *Plugin) GetDatatypeByName(ctx context.Context, namespace string, name string, version string) (*core.Datatype, error) {
-	ret := _m.Called(ctx, namespace, name, version)
+// GetDatatypeByName provides a mock function with given fields: ctx, namespace, name, version, group
+func (_m *Plugin) GetDatatypeByName(ctx context.Context, namespace string, name string, version string, group *fftypes.Bytes32) (*core.Datatype, error) {
+	ret := _m.Called(ctx, namespace, name, version, group)
 
 	var r0 *core.Datatype
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*core.Datatype, error)); ok {
-		return rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32) (*core.Datatype, error)); ok {
+		return rf(ctx, namespace, name, version, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *core.Datatype); ok {
-		r0 = rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32) *core.Datatype); ok {
+		r0 = rf(ctx, namespace, name, version, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.Datatype)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, namespace, name, version, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -903,6 +1090,128 @@ func (_m *Plugin) GetDatatypes(ctx context.Context, namespace string, filter ffa
 	return r0, r1, r2
 }
 
+// GetDeadLetterByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.DeadLetter, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDefinitionProposalByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetDefinitionProposalByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DefinitionProposal, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.DefinitionProposal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.DefinitionProposal, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.DefinitionProposal); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DefinitionProposal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDefinitionProposals provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetDefinitionProposals(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.DefinitionProposal
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.DefinitionProposal); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DefinitionProposal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Event, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -1016,25 +1325,25 @@ func (_m *Plugin) GetFFIByID(ctx context.Context, namespace string, id *fftypes.
 	return r0, r1
 }
 
-// GetFFIByNetworkName provides a mock function with given fields: ctx, namespace, networkName, version
-func (_m *Plugin) GetFFIByNetworkName(ctx context.Context, namespace string, networkName string, version string) (*fftypes.FFI, error) {
-	ret := _m.Called(ctx, namespace, networkName, version)
+// GetFFIByNetworkName provides a mock function with given fields: ctx, namespace, networkName, version, group
+func (_m *Plugin) GetFFIByNetworkName(ctx context.Context, namespace string, networkName string, version string, group *fftypes.Bytes32) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, namespace, networkName, version, group)
 
 	var r0 *fftypes.FFI
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFI, error)); ok {
-		return rf(ctx, namespace, networkName, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32) (*fftypes.FFI, error)); ok {
+		return rf(ctx, namespace, networkName, version, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFI); ok {
-		r0 = rf(ctx, namespace, networkName, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32) *fftypes.FFI); ok {
+		r0 = rf(ctx, namespace, networkName, version, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*fftypes.FFI)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, namespace, networkName, version)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, namespace, networkName, version, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1556,11 +1865,37 @@ func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*core.Namespac
 	return r0, r1
 }
 
-// GetNextPins provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, namespace, filter)
+// GetNetworkGovernance provides a mock function with given fields: ctx, namespace
+func (_m *Plugin) GetNetworkGovernance(ctx context.Context, namespace string) (*core.NetworkGovernance, error) {
+	ret := _m.Called(ctx, namespace)
 
-	var r0 []*core.NextPin
+	var r0 *core.NetworkGovernance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.NetworkGovernance, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.NetworkGovernance); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkGovernance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNextPins provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.NextPin
 	var r1 *ffapi.FilterResult
 	var r2 error
 	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error)); ok {
@@ -1800,6 +2135,67 @@ func (_m *Plugin) GetOperations(ctx context.Context, namespace string, filter ff
 	return r0, r1, r2
 }
 
+// GetOrgApplicationByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetOrgApplicationByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.OrgApplication, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.OrgApplication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.OrgApplication, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.OrgApplication); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrgApplications provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetOrgApplications(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.OrgApplication, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.OrgApplication
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.OrgApplication, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.OrgApplication); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetPins provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Pin, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -2105,6 +2501,102 @@ func (_m *Plugin) GetTokenBalance(ctx context.Context, namespace string, poolID
 	return r0, r1
 }
 
+// GetTokenBalanceHistory provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenBalanceHistory(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenBalanceChange
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBalanceChange); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBalanceMismatchByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenBalanceMismatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenBalanceMismatch, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBalanceMismatches provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenBalanceMismatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenBalances provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetTokenBalances(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -2140,6 +2632,41 @@ func (_m *Plugin) GetTokenBalances(ctx context.Context, namespace string, filter
 	return r0, r1, r2
 }
 
+// GetTokenBalancesAt provides a mock function with given fields: ctx, namespace, point, filter
+func (_m *Plugin) GetTokenBalancesAt(ctx context.Context, namespace string, point *core.TokenBalancePoint, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, point, filter)
+
+	var r0 []*core.TokenBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenBalancePoint, ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, point, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenBalancePoint, ffapi.Filter) []*core.TokenBalance); ok {
+		r0 = rf(ctx, namespace, point, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenBalancePoint, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, point, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *core.TokenBalancePoint, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, point, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenPool provides a mock function with given fields: ctx, namespace, name
 func (_m *Plugin) GetTokenPool(ctx context.Context, namespace string, name string) (*core.TokenPool, error) {
 	ret := _m.Called(ctx, namespace, name)
@@ -2192,25 +2719,25 @@ func (_m *Plugin) GetTokenPoolByID(ctx context.Context, namespace string, id *ff
 	return r0, r1
 }
 
-// GetTokenPoolByNetworkName provides a mock function with given fields: ctx, namespace, networkName
-func (_m *Plugin) GetTokenPoolByNetworkName(ctx context.Context, namespace string, networkName string) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, namespace, networkName)
+// GetTokenPoolByNetworkName provides a mock function with given fields: ctx, namespace, networkName, group
+func (_m *Plugin) GetTokenPoolByNetworkName(ctx context.Context, namespace string, networkName string, group *fftypes.Bytes32) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, namespace, networkName, group)
 
 	var r0 *core.TokenPool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
-		return rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) (*core.TokenPool, error)); ok {
+		return rf(ctx, namespace, networkName, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
-		r0 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) *core.TokenPool); ok {
+		r0 = rf(ctx, namespace, networkName, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.TokenPool)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, namespace, networkName, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2521,6 +3048,20 @@ func (_m *Plugin) InsertBlob(ctx context.Context, blob *core.Blob) error {
 	return r0
 }
 
+// InsertBlobUpload provides a mock function with given fields: ctx, upload
+func (_m *Plugin) InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	ret := _m.Called(ctx, upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUpload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertBlobs provides a mock function with given fields: ctx, blobs
 func (_m *Plugin) InsertBlobs(ctx context.Context, blobs []*core.Blob) error {
 	ret := _m.Called(ctx, blobs)
@@ -2563,6 +3104,34 @@ func (_m *Plugin) InsertDataArray(ctx context.Context, data core.DataArray) erro
 	return r0
 }
 
+// InsertDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *Plugin) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertDefinitionProposal provides a mock function with given fields: ctx, proposal
+func (_m *Plugin) InsertDefinitionProposal(ctx context.Context, proposal *core.DefinitionProposal) error {
+	ret := _m.Called(ctx, proposal)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DefinitionProposal) error); ok {
+		r0 = rf(ctx, proposal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertEvent provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	ret := _m.Called(ctx, data)
@@ -2725,25 +3294,25 @@ func (_m *Plugin) InsertOrGetContractAPI(ctx context.Context, api *core.Contract
 	return r0, r1
 }
 
-// InsertOrGetFFI provides a mock function with given fields: ctx, ffi
-func (_m *Plugin) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI) (*fftypes.FFI, error) {
-	ret := _m.Called(ctx, ffi)
+// InsertOrGetFFI provides a mock function with given fields: ctx, ffi, group
+func (_m *Plugin) InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, ffi, group)
 
 	var r0 *fftypes.FFI
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI) (*fftypes.FFI, error)); ok {
-		return rf(ctx, ffi)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, *fftypes.Bytes32) (*fftypes.FFI, error)); ok {
+		return rf(ctx, ffi, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI) *fftypes.FFI); ok {
-		r0 = rf(ctx, ffi)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, *fftypes.Bytes32) *fftypes.FFI); ok {
+		r0 = rf(ctx, ffi, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*fftypes.FFI)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFI) error); ok {
-		r1 = rf(ctx, ffi)
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFI, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, ffi, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2777,6 +3346,20 @@ func (_m *Plugin) InsertOrGetTokenPool(ctx context.Context, pool *core.TokenPool
 	return r0, r1
 }
 
+// InsertOrgApplication provides a mock function with given fields: ctx, application
+func (_m *Plugin) InsertOrgApplication(ctx context.Context, application *core.OrgApplication) error {
+	ret := _m.Called(ctx, application)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OrgApplication) error); ok {
+		r0 = rf(ctx, application)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertPins provides a mock function with given fields: ctx, pins
 func (_m *Plugin) InsertPins(ctx context.Context, pins []*core.Pin) error {
 	ret := _m.Called(ctx, pins)
@@ -2791,6 +3374,20 @@ func (_m *Plugin) InsertPins(ctx context.Context, pins []*core.Pin) error {
 	return r0
 }
 
+// InsertTokenBalanceMismatch provides a mock function with given fields: ctx, mismatch
+func (_m *Plugin) InsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) error {
+	ret := _m.Called(ctx, mismatch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBalanceMismatch) error); ok {
+		r0 = rf(ctx, mismatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertTransaction provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertTransaction(ctx context.Context, data *core.Transaction) error {
 	ret := _m.Called(ctx, data)
@@ -2866,6 +3463,30 @@ func (_m *Plugin) UpdateBatch(ctx context.Context, namespace string, id *fftypes
 	return r0
 }
 
+// UpdateBlobUpload provides a mock function with given fields: ctx, namespace, id, filter, update
+func (_m *Plugin) UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (bool, error) {
+	ret := _m.Called(ctx, namespace, id, filter, update)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) (bool, error)); ok {
+		return rf(ctx, namespace, id, filter, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) bool); ok {
+		r0 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) error); ok {
+		r1 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateContractListener provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateContractListener(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -2894,6 +3515,34 @@ func (_m *Plugin) UpdateData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// UpdateDeadLetter provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDefinitionProposal provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateDefinitionProposal(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMessage provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateMessage(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -2988,6 +3637,20 @@ func (_m *Plugin) UpdateOperation(ctx context.Context, namespace string, id *fft
 	return r0, r1
 }
 
+// UpdateOrgApplication provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateOrgApplication(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePins provides a mock function with given fields: ctx, namespace, filter, update
 func (_m *Plugin) UpdatePins(ctx context.Context, namespace string, filter ffapi.Filter, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, filter, update)
@@ -3030,13 +3693,13 @@ func (_m *Plugin) UpdateTokenApprovals(ctx context.Context, filter ffapi.Filter,
 	return r0
 }
 
-// UpdateTokenBalances provides a mock function with given fields: ctx, transfer
-func (_m *Plugin) UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer) error {
-	ret := _m.Called(ctx, transfer)
+// UpdateTokenBalances provides a mock function with given fields: ctx, transfer, timestamp
+func (_m *Plugin) UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer, timestamp *fftypes.FFTime) error {
+	ret := _m.Called(ctx, transfer, timestamp)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer) error); ok {
-		r0 = rf(ctx, transfer)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer, *fftypes.FFTime) error); ok {
+		r0 = rf(ctx, transfer, timestamp)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3100,13 +3763,13 @@ func (_m *Plugin) UpsertDatatype(ctx context.Context, datadef *core.Datatype, al
 	return r0
 }
 
-// UpsertFFI provides a mock function with given fields: ctx, ffi, optimization
-func (_m *Plugin) UpsertFFI(ctx context.Context, ffi *fftypes.FFI, optimization database.UpsertOptimization) error {
-	ret := _m.Called(ctx, ffi, optimization)
+// UpsertFFI provides a mock function with given fields: ctx, ffi, group, optimization
+func (_m *Plugin) UpsertFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32, optimization database.UpsertOptimization) error {
+	ret := _m.Called(ctx, ffi, group, optimization)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, database.UpsertOptimization) error); ok {
-		r0 = rf(ctx, ffi, optimization)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, *fftypes.Bytes32, database.UpsertOptimization) error); ok {
+		r0 = rf(ctx, ffi, group, optimization)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3219,6 +3882,20 @@ func (_m *Plugin) UpsertNamespace(ctx context.Context, data *core.Namespace, all
 	return r0
 }
 
+// UpsertNetworkGovernance provides a mock function with given fields: ctx, governance
+func (_m *Plugin) UpsertNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance) error {
+	ret := _m.Called(ctx, governance)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.NetworkGovernance) error); ok {
+		r0 = rf(ctx, governance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertOffset provides a mock function with given fields: ctx, data, allowExisting
 func (_m *Plugin) UpsertOffset(ctx context.Context, data *core.Offset, allowExisting bool) error {
 	ret := _m.Called(ctx, data, allowExisting)
@@ -36,6 +36,20 @@ func (_m *Manager) BlobsEnabled() bool {
 	return r0
 }
 
+// CancelBlobUpload provides a mock function with given fields: ctx, uploadID
+func (_m *Manager) CancelBlobUpload(ctx context.Context, uploadID string) error {
+	ret := _m.Called(ctx, uploadID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckDatatype provides a mock function with given fields: ctx, datatype
 func (_m *Manager) CheckDatatype(ctx context.Context, datatype *core.Datatype) error {
 	ret := _m.Called(ctx, datatype)
@@ -50,6 +64,32 @@ func (_m *Manager) CheckDatatype(ctx context.Context, datatype *core.Datatype) e
 	return r0
 }
 
+// CreateBlobUpload provides a mock function with given fields: ctx, input
+func (_m *Manager) CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) (*core.BlobUpload, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) *core.BlobUpload); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.BlobUploadInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteData provides a mock function with given fields: ctx, dataID
 func (_m *Manager) DeleteData(ctx context.Context, dataID string) error {
 	ret := _m.Called(ctx, dataID)
@@ -99,6 +139,98 @@ func (_m *Manager) DownloadBlob(ctx context.Context, dataID string) (*core.Blob,
 	return r0, r1, r2
 }
 
+// EncryptReceivedBlob provides a mock function with given fields: ctx, blob
+func (_m *Manager) EncryptReceivedBlob(ctx context.Context, blob *core.Blob) error {
+	ret := _m.Called(ctx, blob)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob) error); ok {
+		r0 = rf(ctx, blob)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinalizeBlobUpload provides a mock function with given fields: ctx, uploadID, input
+func (_m *Manager) FinalizeBlobUpload(ctx context.Context, uploadID string, input *core.BlobUploadFinalize) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, uploadID, input)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BlobUploadFinalize) (*core.BlobUpload, error)); ok {
+		return rf(ctx, uploadID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BlobUploadFinalize) *core.BlobUpload); ok {
+		r0 = rf(ctx, uploadID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.BlobUploadFinalize) error); ok {
+		r1 = rf(ctx, uploadID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlobUpload provides a mock function with given fields: ctx, uploadID
+func (_m *Manager) GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, uploadID)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.BlobUpload, error)); ok {
+		return rf(ctx, uploadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.BlobUpload); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uploadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataBlob provides a mock function with given fields: ctx, dataID
+func (_m *Manager) GetDataBlob(ctx context.Context, dataID string) (*core.Blob, error) {
+	ret := _m.Called(ctx, dataID)
+
+	var r0 *core.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Blob, error)); ok {
+		return rf(ctx, dataID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Blob); ok {
+		r0 = rf(ctx, dataID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Blob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, dataID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessageDataCached provides a mock function with given fields: ctx, msg, options
 func (_m *Manager) GetMessageDataCached(ctx context.Context, msg *core.Message, options ...data.CacheReadOption) (core.DataArray, bool, error) {
 	_va := make([]interface{}, len(options))
@@ -214,6 +346,84 @@ func (_m *Manager) HydrateBatch(ctx context.Context, persistedBatch *core.BatchP
 	return r0, r1
 }
 
+// ImportBlob provides a mock function with given fields: ctx, dataID, reader
+func (_m *Manager) ImportBlob(ctx context.Context, dataID *fftypes.UUID, reader io.Reader) (*core.Blob, error) {
+	ret := _m.Called(ctx, dataID, reader)
+
+	var r0 *core.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, io.Reader) (*core.Blob, error)); ok {
+		return rf(ctx, dataID, reader)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, io.Reader) *core.Blob); ok {
+		r0 = rf(ctx, dataID, reader)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Blob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, io.Reader) error); ok {
+		r1 = rf(ctx, dataID, reader)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenBlob provides a mock function with given fields: ctx, blob
+func (_m *Manager) OpenBlob(ctx context.Context, blob *core.Blob) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, blob)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob) (io.ReadCloser, error)); ok {
+		return rf(ctx, blob)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob) io.ReadCloser); ok {
+		r0 = rf(ctx, blob)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Blob) error); ok {
+		r1 = rf(ctx, blob)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenBlobRange provides a mock function with given fields: ctx, blob, offset, length
+func (_m *Manager) OpenBlobRange(ctx context.Context, blob *core.Blob, offset int64, length int64) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, blob, offset, length)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob, int64, int64) (io.ReadCloser, error)); ok {
+		return rf(ctx, blob, offset, length)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob, int64, int64) io.ReadCloser); ok {
+		r0 = rf(ctx, blob, offset, length)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Blob, int64, int64) error); ok {
+		r1 = rf(ctx, blob, offset, length)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PeekMessageCache provides a mock function with given fields: ctx, id, options
 func (_m *Manager) PeekMessageCache(ctx context.Context, id *fftypes.UUID, options ...data.CacheReadOption) (*core.Message, core.DataArray) {
 	_va := make([]interface{}, len(options))
@@ -249,6 +459,75 @@ func (_m *Manager) PeekMessageCache(ctx context.Context, id *fftypes.UUID, optio
 	return r0, r1
 }
 
+// PrepareBlobTransfer provides a mock function with given fields: ctx, blob, transferID
+func (_m *Manager) PrepareBlobTransfer(ctx context.Context, blob *core.Blob, transferID *fftypes.UUID) (string, bool, error) {
+	ret := _m.Called(ctx, blob, transferID)
+
+	var r0 string
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob, *fftypes.UUID) (string, bool, error)); ok {
+		return rf(ctx, blob, transferID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob, *fftypes.UUID) string); ok {
+		r0 = rf(ctx, blob, transferID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Blob, *fftypes.UUID) bool); ok {
+		r1 = rf(ctx, blob, transferID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *core.Blob, *fftypes.UUID) error); ok {
+		r2 = rf(ctx, blob, transferID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PurgeBlobUpload provides a mock function with given fields: ctx, upload
+func (_m *Manager) PurgeBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	ret := _m.Called(ctx, upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUpload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeMessage provides a mock function with given fields: ctx, msg
+func (_m *Manager) PurgeMessage(ctx context.Context, msg *core.Message) (int, error) {
+	ret := _m.Called(ctx, msg)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message) (int, error)); ok {
+		return rf(ctx, msg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message) int); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Message) error); ok {
+		r1 = rf(ctx, msg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolveInlineData provides a mock function with given fields: ctx, msg
 func (_m *Manager) ResolveInlineData(ctx context.Context, msg *data.NewMessage) error {
 	ret := _m.Called(ctx, msg)
@@ -309,6 +588,32 @@ func (_m *Manager) UploadBlob(ctx context.Context, inData *core.DataRefOrValue,
 	return r0, r1
 }
 
+// UploadBlobChunk provides a mock function with given fields: ctx, uploadID, offset, reader
+func (_m *Manager) UploadBlobChunk(ctx context.Context, uploadID string, offset int64, reader io.Reader) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, uploadID, offset, reader)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) (*core.BlobUpload, error)); ok {
+		return rf(ctx, uploadID, offset, reader)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) *core.BlobUpload); ok {
+		r0 = rf(ctx, uploadID, offset, reader)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader) error); ok {
+		r1 = rf(ctx, uploadID, offset, reader)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UploadJSON provides a mock function with given fields: ctx, inData
 func (_m *Manager) UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error) {
 	ret := _m.Called(ctx, inData)
@@ -335,23 +640,23 @@ func (_m *Manager) UploadJSON(ctx context.Context, inData *core.DataRefOrValue)
 	return r0, r1
 }
 
-// ValidateAll provides a mock function with given fields: ctx, _a1
-func (_m *Manager) ValidateAll(ctx context.Context, _a1 core.DataArray) (bool, error) {
-	ret := _m.Called(ctx, _a1)
+// ValidateAll provides a mock function with given fields: ctx, _a1, group
+func (_m *Manager) ValidateAll(ctx context.Context, _a1 core.DataArray, group *fftypes.Bytes32) (bool, error) {
+	ret := _m.Called(ctx, _a1, group)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, core.DataArray) (bool, error)); ok {
-		return rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, core.DataArray, *fftypes.Bytes32) (bool, error)); ok {
+		return rf(ctx, _a1, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, core.DataArray) bool); ok {
-		r0 = rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, core.DataArray, *fftypes.Bytes32) bool); ok {
+		r0 = rf(ctx, _a1, group)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, core.DataArray) error); ok {
-		r1 = rf(ctx, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, core.DataArray, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, _a1, group)
 	} else {
 		r1 = ret.Error(1)
 	}
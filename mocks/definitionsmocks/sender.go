@@ -45,13 +45,13 @@ func (_m *Sender) DefineContractAPI(ctx context.Context, httpServerURL string, a
 	return r0
 }
 
-// DefineDatatype provides a mock function with given fields: ctx, datatype, waitConfirm
-func (_m *Sender) DefineDatatype(ctx context.Context, datatype *core.Datatype, waitConfirm bool) error {
-	ret := _m.Called(ctx, datatype, waitConfirm)
+// DefineDatatype provides a mock function with given fields: ctx, datatype, group, waitConfirm
+func (_m *Sender) DefineDatatype(ctx context.Context, datatype *core.Datatype, group *fftypes.Bytes32, waitConfirm bool) error {
+	ret := _m.Called(ctx, datatype, group, waitConfirm)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *core.Datatype, bool) error); ok {
-		r0 = rf(ctx, datatype, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Datatype, *fftypes.Bytes32, bool) error); ok {
+		r0 = rf(ctx, datatype, group, waitConfirm)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -73,6 +73,20 @@ func (_m *Sender) DefineFFI(ctx context.Context, ffi *fftypes.FFI, waitConfirm b
 	return r0
 }
 
+// DefineNetworkGovernance provides a mock function with given fields: ctx, governance, waitConfirm
+func (_m *Sender) DefineNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance, waitConfirm bool) error {
+	ret := _m.Called(ctx, governance, waitConfirm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.NetworkGovernance, bool) error); ok {
+		r0 = rf(ctx, governance, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DefineTokenPool provides a mock function with given fields: ctx, pool, waitConfirm
 func (_m *Sender) DefineTokenPool(ctx context.Context, pool *core.TokenPool, waitConfirm bool) error {
 	ret := _m.Called(ctx, pool, waitConfirm)
@@ -101,25 +115,25 @@ func (_m *Sender) Name() string {
 	return r0
 }
 
-// PublishContractAPI provides a mock function with given fields: ctx, httpServerURL, name, networkName, waitConfirm
-func (_m *Sender) PublishContractAPI(ctx context.Context, httpServerURL string, name string, networkName string, waitConfirm bool) (*core.ContractAPI, error) {
-	ret := _m.Called(ctx, httpServerURL, name, networkName, waitConfirm)
+// PublishContractAPI provides a mock function with given fields: ctx, httpServerURL, name, networkName, group, waitConfirm
+func (_m *Sender) PublishContractAPI(ctx context.Context, httpServerURL string, name string, networkName string, group *fftypes.Bytes32, waitConfirm bool) (*core.ContractAPI, error) {
+	ret := _m.Called(ctx, httpServerURL, name, networkName, group, waitConfirm)
 
 	var r0 *core.ContractAPI
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) (*core.ContractAPI, error)); ok {
-		return rf(ctx, httpServerURL, name, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) (*core.ContractAPI, error)); ok {
+		return rf(ctx, httpServerURL, name, networkName, group, waitConfirm)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) *core.ContractAPI); ok {
-		r0 = rf(ctx, httpServerURL, name, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) *core.ContractAPI); ok {
+		r0 = rf(ctx, httpServerURL, name, networkName, group, waitConfirm)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.ContractAPI)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool) error); ok {
-		r1 = rf(ctx, httpServerURL, name, networkName, waitConfirm)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) error); ok {
+		r1 = rf(ctx, httpServerURL, name, networkName, group, waitConfirm)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -127,25 +141,25 @@ func (_m *Sender) PublishContractAPI(ctx context.Context, httpServerURL string,
 	return r0, r1
 }
 
-// PublishFFI provides a mock function with given fields: ctx, name, version, networkName, waitConfirm
-func (_m *Sender) PublishFFI(ctx context.Context, name string, version string, networkName string, waitConfirm bool) (*fftypes.FFI, error) {
-	ret := _m.Called(ctx, name, version, networkName, waitConfirm)
+// PublishFFI provides a mock function with given fields: ctx, name, version, networkName, group, waitConfirm
+func (_m *Sender) PublishFFI(ctx context.Context, name string, version string, networkName string, group *fftypes.Bytes32, waitConfirm bool) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, name, version, networkName, group, waitConfirm)
 
 	var r0 *fftypes.FFI
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) (*fftypes.FFI, error)); ok {
-		return rf(ctx, name, version, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) (*fftypes.FFI, error)); ok {
+		return rf(ctx, name, version, networkName, group, waitConfirm)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) *fftypes.FFI); ok {
-		r0 = rf(ctx, name, version, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) *fftypes.FFI); ok {
+		r0 = rf(ctx, name, version, networkName, group, waitConfirm)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*fftypes.FFI)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool) error); ok {
-		r1 = rf(ctx, name, version, networkName, waitConfirm)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *fftypes.Bytes32, bool) error); ok {
+		r1 = rf(ctx, name, version, networkName, group, waitConfirm)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -153,25 +167,25 @@ func (_m *Sender) PublishFFI(ctx context.Context, name string, version string, n
 	return r0, r1
 }
 
-// PublishTokenPool provides a mock function with given fields: ctx, poolNameOrID, networkName, waitConfirm
-func (_m *Sender) PublishTokenPool(ctx context.Context, poolNameOrID string, networkName string, waitConfirm bool) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, poolNameOrID, networkName, waitConfirm)
+// PublishTokenPool provides a mock function with given fields: ctx, poolNameOrID, networkName, group, waitConfirm
+func (_m *Sender) PublishTokenPool(ctx context.Context, poolNameOrID string, networkName string, group *fftypes.Bytes32, waitConfirm bool) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID, networkName, group, waitConfirm)
 
 	var r0 *core.TokenPool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (*core.TokenPool, error)); ok {
-		return rf(ctx, poolNameOrID, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32, bool) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID, networkName, group, waitConfirm)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) *core.TokenPool); ok {
-		r0 = rf(ctx, poolNameOrID, networkName, waitConfirm)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32, bool) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID, networkName, group, waitConfirm)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.TokenPool)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
-		r1 = rf(ctx, poolNameOrID, networkName, waitConfirm)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *fftypes.Bytes32, bool) error); ok {
+		r1 = rf(ctx, poolNameOrID, networkName, group, waitConfirm)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -179,6 +193,62 @@ func (_m *Sender) PublishTokenPool(ctx context.Context, poolNameOrID string, net
 	return r0, r1
 }
 
+// ReviewOrgApplication provides a mock function with given fields: ctx, review, waitConfirm
+func (_m *Sender) ReviewOrgApplication(ctx context.Context, review *core.OrgApplicationReview, waitConfirm bool) error {
+	ret := _m.Called(ctx, review, waitConfirm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OrgApplicationReview, bool) error); ok {
+		r0 = rf(ctx, review, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeIdentity provides a mock function with given fields: ctx, def, signingIdentity, waitConfirm
+func (_m *Sender) RevokeIdentity(ctx context.Context, def *core.IdentityRevocation, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	ret := _m.Called(ctx, def, signingIdentity, waitConfirm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.IdentityRevocation, *core.SignerRef, bool) error); ok {
+		r0 = rf(ctx, def, signingIdentity, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateIdentityKey provides a mock function with given fields: ctx, def, signingIdentity, waitConfirm
+func (_m *Sender) RotateIdentityKey(ctx context.Context, def *core.IdentityKeyRotation, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	ret := _m.Called(ctx, def, signingIdentity, waitConfirm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.IdentityKeyRotation, *core.SignerRef, bool) error); ok {
+		r0 = rf(ctx, def, signingIdentity, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmitOrgApplication provides a mock function with given fields: ctx, application, waitConfirm
+func (_m *Sender) SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) error {
+	ret := _m.Called(ctx, application, waitConfirm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OrgApplication, bool) error); ok {
+		r0 = rf(ctx, application, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateIdentity provides a mock function with given fields: ctx, identity, def, signingIdentity, waitConfirm
 func (_m *Sender) UpdateIdentity(ctx context.Context, identity *core.Identity, def *core.IdentityUpdate, signingIdentity *core.SignerRef, waitConfirm bool) error {
 	ret := _m.Called(ctx, identity, def, signingIdentity, waitConfirm)
@@ -193,6 +263,32 @@ func (_m *Sender) UpdateIdentity(ctx context.Context, identity *core.Identity, d
 	return r0
 }
 
+// VoteDefinitionProposal provides a mock function with given fields: ctx, id, vote, waitConfirm
+func (_m *Sender) VoteDefinitionProposal(ctx context.Context, id *fftypes.UUID, vote *core.DefinitionVote, waitConfirm bool) (*core.DefinitionProposal, error) {
+	ret := _m.Called(ctx, id, vote, waitConfirm)
+
+	var r0 *core.DefinitionProposal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.DefinitionVote, bool) (*core.DefinitionProposal, error)); ok {
+		return rf(ctx, id, vote, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.DefinitionVote, bool) *core.DefinitionProposal); ok {
+		r0 = rf(ctx, id, vote, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DefinitionProposal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, *core.DefinitionVote, bool) error); ok {
+		r1 = rf(ctx, id, vote, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewSender interface {
 	mock.TestingT
 	Cleanup(func())
@@ -69,13 +69,13 @@ func (_m *EventManager) BlockchainEvent(event *blockchain.EventWithSubscription)
 	return r0
 }
 
-// BlockchainNetworkAction provides a mock function with given fields: action, location, event, signingKey
-func (_m *EventManager) BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
-	ret := _m.Called(action, location, event, signingKey)
+// BlockchainNetworkAction provides a mock function with given fields: action, payload, location, event, signingKey
+func (_m *EventManager) BlockchainNetworkAction(action string, payload string, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef) error {
+	ret := _m.Called(action, payload, location, event, signingKey)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *fftypes.JSONAny, *blockchain.Event, *core.VerifierRef) error); ok {
-		r0 = rf(action, location, event, signingKey)
+	if rf, ok := ret.Get(0).(func(string, string, *fftypes.JSONAny, *blockchain.Event, *core.VerifierRef) error); ok {
+		r0 = rf(action, payload, location, event, signingKey)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -236,6 +236,20 @@ func (_m *EventManager) QueueBatchRewind(batchID *fftypes.UUID) {
 	_m.Called(batchID)
 }
 
+// ReplayDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *EventManager) ReplayDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SharedStorageBatchDownloaded provides a mock function with given fields: ss, payloadRef, data
 func (_m *EventManager) SharedStorageBatchDownloaded(ss sharedstorage.Plugin, payloadRef string, data []byte) (*fftypes.UUID, error) {
 	ret := _m.Called(ss, payloadRef, data)
@@ -262,13 +276,13 @@ func (_m *EventManager) SharedStorageBatchDownloaded(ss sharedstorage.Plugin, pa
 	return r0, r1
 }
 
-// SharedStorageBlobDownloaded provides a mock function with given fields: ss, hash, size, payloadRef, dataID
-func (_m *EventManager) SharedStorageBlobDownloaded(ss sharedstorage.Plugin, hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error {
-	ret := _m.Called(ss, hash, size, payloadRef, dataID)
+// SharedStorageBlobDownloaded provides a mock function with given fields: ss, hash, size, payloadRef, dataID, encryption
+func (_m *EventManager) SharedStorageBlobDownloaded(ss sharedstorage.Plugin, hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID, encryption *core.BlobEncryption) error {
+	ret := _m.Called(ss, hash, size, payloadRef, dataID, encryption)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(sharedstorage.Plugin, fftypes.Bytes32, int64, string, *fftypes.UUID) error); ok {
-		r0 = rf(ss, hash, size, payloadRef, dataID)
+	if rf, ok := ret.Get(0).(func(sharedstorage.Plugin, fftypes.Bytes32, int64, string, *fftypes.UUID, *core.BlobEncryption) error); ok {
+		r0 = rf(ss, hash, size, payloadRef, dataID, encryption)
 	} else {
 		r0 = ret.Error(0)
 	}
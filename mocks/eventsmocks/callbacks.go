@@ -19,6 +19,11 @@ func (_m *Callbacks) ConnectionClosed(connID string) {
 	_m.Called(connID)
 }
 
+// DeadLetter provides a mock function with given fields: connID, deadLetter
+func (_m *Callbacks) DeadLetter(connID string, deadLetter *core.DeadLetter) {
+	_m.Called(connID, deadLetter)
+}
+
 // DeliveryResponse provides a mock function with given fields: connID, inflight
 func (_m *Callbacks) DeliveryResponse(connID string, inflight *core.EventDeliveryResponse) {
 	_m.Called(connID, inflight)
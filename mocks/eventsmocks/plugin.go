@@ -21,6 +21,20 @@ type Plugin struct {
 	mock.Mock
 }
 
+// BatchDeliveryRequest provides a mock function with given fields: ctx, connID, sub, _a3
+func (_m *Plugin) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, _a3 []*core.CombinedEventDataDelivery) error {
+	ret := _m.Called(ctx, connID, sub, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.Subscription, []*core.CombinedEventDataDelivery) error); ok {
+		r0 = rf(ctx, connID, sub, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *events.Capabilities {
 	ret := _m.Called()
@@ -37,13 +51,13 @@ func (_m *Plugin) Capabilities() *events.Capabilities {
 	return r0
 }
 
-// DeliveryRequest provides a mock function with given fields: connID, sub, event, data
-func (_m *Plugin) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
-	ret := _m.Called(connID, sub, event, data)
+// DeliveryRequest provides a mock function with given fields: ctx, connID, sub, event, data
+func (_m *Plugin) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	ret := _m.Called(ctx, connID, sub, event, data)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *core.Subscription, *core.EventDelivery, core.DataArray) error); ok {
-		r0 = rf(connID, sub, event, data)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.Subscription, *core.EventDelivery, core.DataArray) error); ok {
+		r0 = rf(ctx, connID, sub, event, data)
 	} else {
 		r0 = ret.Error(0)
 	}
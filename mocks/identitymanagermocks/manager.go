@@ -111,6 +111,32 @@ func (_m *Manager) CachedIdentityLookupNilOK(ctx context.Context, did string) (*
 	return r0, r1, r2
 }
 
+// CachedVerifierLookup provides a mock function with given fields: ctx, verifierRef
+func (_m *Manager) CachedVerifierLookup(ctx context.Context, verifierRef *core.VerifierRef) (*core.Verifier, error) {
+	ret := _m.Called(ctx, verifierRef)
+
+	var r0 *core.Verifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.VerifierRef) (*core.Verifier, error)); ok {
+		return rf(ctx, verifierRef)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.VerifierRef) *core.Verifier); ok {
+		r0 = rf(ctx, verifierRef)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Verifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.VerifierRef) error); ok {
+		r1 = rf(ctx, verifierRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindIdentityForVerifier provides a mock function with given fields: ctx, iTypes, verifier
 func (_m *Manager) FindIdentityForVerifier(ctx context.Context, iTypes []fftypes.FFEnum, verifier *core.VerifierRef) (*core.Identity, error) {
 	ret := _m.Called(ctx, iTypes, verifier)
@@ -215,6 +241,16 @@ func (_m *Manager) GetMultipartyRootVerifier(ctx context.Context) (*core.Verifie
 	return r0, r1
 }
 
+// InvalidateCachedIdentity provides a mock function with given fields: _a0
+func (_m *Manager) InvalidateCachedIdentity(_a0 *core.Identity) {
+	_m.Called(_a0)
+}
+
+// InvalidateCachedVerifier provides a mock function with given fields: verifier
+func (_m *Manager) InvalidateCachedVerifier(verifier *core.Verifier) {
+	_m.Called(verifier)
+}
+
 // ResolveIdentitySigner provides a mock function with given fields: ctx, _a1
 func (_m *Manager) ResolveIdentitySigner(ctx context.Context, _a1 *core.Identity) (*core.SignerRef, error) {
 	ret := _m.Called(ctx, _a1)
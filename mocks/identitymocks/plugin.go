@@ -66,6 +66,32 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// ResolveDID provides a mock function with given fields: ctx, did
+func (_m *Plugin) ResolveDID(ctx context.Context, did string) (*identity.DIDDocument, error) {
+	ret := _m.Called(ctx, did)
+
+	var r0 *identity.DIDDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*identity.DIDDocument, error)); ok {
+		return rf(ctx, did)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *identity.DIDDocument); ok {
+		r0 = rf(ctx, did)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*identity.DIDDocument)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, did)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler identity.Callbacks) {
 	_m.Called(namespace, handler)
@@ -89,6 +89,11 @@ func (_m *Manager) MessageSubmitted(msg *core.Message) {
 	_m.Called(msg)
 }
 
+// RecordsPurged provides a mock function with given fields: namespace, collection, count
+func (_m *Manager) RecordsPurged(namespace string, collection string, count int) {
+	_m.Called(namespace, collection, count)
+}
+
 // TransferConfirmed provides a mock function with given fields: transfer
 func (_m *Manager) TransferConfirmed(transfer *core.TokenTransfer) {
 	_m.Called(transfer)
@@ -49,6 +49,34 @@ func (_m *Manager) GetNetworkVersion() int {
 	return r0
 }
 
+// IsPaused provides a mock function with given fields:
+func (_m *Manager) IsPaused() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsPausedAt provides a mock function with given fields: protocolID
+func (_m *Manager) IsPausedAt(protocolID string) bool {
+	ret := _m.Called(protocolID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(protocolID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // LocalNode provides a mock function with given fields:
 func (_m *Manager) LocalNode() multiparty.LocalNode {
 	ret := _m.Called()
@@ -63,6 +91,20 @@ func (_m *Manager) LocalNode() multiparty.LocalNode {
 	return r0
 }
 
+// MigrateContract provides a mock function with given fields: ctx, location, payload, migration
+func (_m *Manager) MigrateContract(ctx context.Context, location *fftypes.JSONAny, payload string, migration *blockchain.Event) error {
+	ret := _m.Called(ctx, location, payload, migration)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.JSONAny, string, *blockchain.Event) error); ok {
+		r0 = rf(ctx, location, payload, migration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -77,6 +119,20 @@ func (_m *Manager) Name() string {
 	return r0
 }
 
+// PauseContract provides a mock function with given fields: ctx, location, pause
+func (_m *Manager) PauseContract(ctx context.Context, location *fftypes.JSONAny, pause *blockchain.Event) error {
+	ret := _m.Called(ctx, location, pause)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.JSONAny, *blockchain.Event) error); ok {
+		r0 = rf(ctx, location, pause)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PrepareOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	ret := _m.Called(ctx, op)
@@ -103,6 +159,20 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// ResumeContract provides a mock function with given fields: ctx, location, resume
+func (_m *Manager) ResumeContract(ctx context.Context, location *fftypes.JSONAny, resume *blockchain.Event) error {
+	ret := _m.Called(ctx, location, resume)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.JSONAny, *blockchain.Event) error); ok {
+		r0 = rf(ctx, location, resume)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RootOrg provides a mock function with given fields:
 func (_m *Manager) RootOrg() multiparty.RootOrg {
 	ret := _m.Called()
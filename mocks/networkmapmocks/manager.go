@@ -340,6 +340,67 @@ func (_m *Manager) GetNodes(ctx context.Context, filter ffapi.AndFilter) ([]*cor
 	return r0, r1, r2
 }
 
+// GetOrgApplicationByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetOrgApplicationByID(ctx context.Context, id string) (*core.OrgApplication, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.OrgApplication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.OrgApplication, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.OrgApplication); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrgApplications provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetOrgApplications(ctx context.Context, filter ffapi.AndFilter) ([]*core.OrgApplication, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.OrgApplication
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.OrgApplication, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.OrgApplication); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetOrganizationByNameOrID provides a mock function with given fields: ctx, nameOrID
 func (_m *Manager) GetOrganizationByNameOrID(ctx context.Context, nameOrID string) (*core.Identity, error) {
 	ret := _m.Called(ctx, nameOrID)
@@ -601,6 +662,110 @@ func (_m *Manager) RegisterOrganization(ctx context.Context, org *core.IdentityC
 	return r0, r1
 }
 
+// ReviewOrgApplication provides a mock function with given fields: ctx, id, review, waitConfirm
+func (_m *Manager) ReviewOrgApplication(ctx context.Context, id string, review *core.OrgApplicationReview, waitConfirm bool) (*core.OrgApplication, error) {
+	ret := _m.Called(ctx, id, review, waitConfirm)
+
+	var r0 *core.OrgApplication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.OrgApplicationReview, bool) (*core.OrgApplication, error)); ok {
+		return rf(ctx, id, review, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.OrgApplicationReview, bool) *core.OrgApplication); ok {
+		r0 = rf(ctx, id, review, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.OrgApplicationReview, bool) error); ok {
+		r1 = rf(ctx, id, review, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeIdentity provides a mock function with given fields: ctx, id, dto, waitConfirm
+func (_m *Manager) RevokeIdentity(ctx context.Context, id string, dto *core.IdentityRevokeDTO, waitConfirm bool) (*core.Identity, error) {
+	ret := _m.Called(ctx, id, dto, waitConfirm)
+
+	var r0 *core.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityRevokeDTO, bool) (*core.Identity, error)); ok {
+		return rf(ctx, id, dto, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityRevokeDTO, bool) *core.Identity); ok {
+		r0 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.IdentityRevokeDTO, bool) error); ok {
+		r1 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateIdentityKey provides a mock function with given fields: ctx, id, dto, waitConfirm
+func (_m *Manager) RotateIdentityKey(ctx context.Context, id string, dto *core.IdentityKeyRotationDTO, waitConfirm bool) (*core.Identity, error) {
+	ret := _m.Called(ctx, id, dto, waitConfirm)
+
+	var r0 *core.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityKeyRotationDTO, bool) (*core.Identity, error)); ok {
+		return rf(ctx, id, dto, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityKeyRotationDTO, bool) *core.Identity); ok {
+		r0 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.IdentityKeyRotationDTO, bool) error); ok {
+		r1 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmitOrgApplication provides a mock function with given fields: ctx, application, waitConfirm
+func (_m *Manager) SubmitOrgApplication(ctx context.Context, application *core.OrgApplication, waitConfirm bool) (*core.OrgApplication, error) {
+	ret := _m.Called(ctx, application, waitConfirm)
+
+	var r0 *core.OrgApplication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OrgApplication, bool) (*core.OrgApplication, error)); ok {
+		return rf(ctx, application, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OrgApplication, bool) *core.OrgApplication); ok {
+		r0 = rf(ctx, application, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.OrgApplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.OrgApplication, bool) error); ok {
+		r1 = rf(ctx, application, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateIdentity provides a mock function with given fields: ctx, id, dto, waitConfirm
 func (_m *Manager) UpdateIdentity(ctx context.Context, id string, dto *core.IdentityUpdateDTO, waitConfirm bool) (*core.Identity, error) {
 	ret := _m.Called(ctx, id, dto, waitConfirm)
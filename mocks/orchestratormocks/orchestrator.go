@@ -220,6 +220,20 @@ func (_m *Orchestrator) DeleteSubscription(ctx context.Context, id string) error
 	return r0
 }
 
+// DeleteSubscriptionDeadLetters provides a mock function with given fields: ctx, subID
+func (_m *Orchestrator) DeleteSubscriptionDeadLetters(ctx context.Context, subID string) error {
+	ret := _m.Called(ctx, subID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, subID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Events provides a mock function with given fields:
 func (_m *Orchestrator) Events() events.EventManager {
 	ret := _m.Called()
@@ -497,25 +511,25 @@ func (_m *Orchestrator) GetDatatypeByID(ctx context.Context, id string) (*core.D
 	return r0, r1
 }
 
-// GetDatatypeByName provides a mock function with given fields: ctx, name, version
-func (_m *Orchestrator) GetDatatypeByName(ctx context.Context, name string, version string) (*core.Datatype, error) {
-	ret := _m.Called(ctx, name, version)
+// GetDatatypeByName provides a mock function with given fields: ctx, name, version, group
+func (_m *Orchestrator) GetDatatypeByName(ctx context.Context, name string, version string, group *fftypes.Bytes32) (*core.Datatype, error) {
+	ret := _m.Called(ctx, name, version, group)
 
 	var r0 *core.Datatype
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.Datatype, error)); ok {
-		return rf(ctx, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) (*core.Datatype, error)); ok {
+		return rf(ctx, name, version, group)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.Datatype); ok {
-		r0 = rf(ctx, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *fftypes.Bytes32) *core.Datatype); ok {
+		r0 = rf(ctx, name, version, group)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.Datatype)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, name, version)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, name, version, group)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -558,6 +572,67 @@ func (_m *Orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetDefinitionProposalByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetDefinitionProposalByID(ctx context.Context, id string) (*core.DefinitionProposal, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.DefinitionProposal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.DefinitionProposal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.DefinitionProposal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DefinitionProposal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDefinitionProposals provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetDefinitionProposals(ctx context.Context, filter ffapi.AndFilter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.DefinitionProposal
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.DefinitionProposal, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.DefinitionProposal); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DefinitionProposal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetEventByID(ctx context.Context, id string) (*core.Event, error) {
 	ret := _m.Called(ctx, id)
@@ -793,6 +868,41 @@ func (_m *Orchestrator) GetMessageEvents(ctx context.Context, id string, filter
 	return r0, r1, r2
 }
 
+// GetMessageReplies provides a mock function with given fields: ctx, id, filter
+func (_m *Orchestrator) GetMessageReplies(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id, filter)
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.Message); ok {
+		r0 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, id, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMessageTransaction provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error) {
 	ret := _m.Called(ctx, id)
@@ -940,6 +1050,32 @@ func (_m *Orchestrator) GetNamespace(ctx context.Context) *core.Namespace {
 	return r0
 }
 
+// GetNetworkGovernance provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetNetworkGovernance(ctx context.Context) (*core.NetworkGovernance, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *core.NetworkGovernance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.NetworkGovernance, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NetworkGovernance); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkGovernance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNextPins provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetNextPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.NextPin, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -1175,6 +1311,41 @@ func (_m *Orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 	return r0, r1
 }
 
+// GetSubscriptionDeadLetters provides a mock function with given fields: ctx, subID, filter
+func (_m *Orchestrator) GetSubscriptionDeadLetters(ctx context.Context, subID string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, subID, filter)
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, subID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, subID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, subID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, subID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetSubscriptions provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -1466,30 +1637,51 @@ func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	return r0
 }
 
-// Purge provides a mock function with given fields:
-func (_m *Orchestrator) Purge() {
-	_m.Called()
+// ReplaySubscriptionDeadLetter provides a mock function with given fields: ctx, subID, dlID
+func (_m *Orchestrator) ReplaySubscriptionDeadLetter(ctx context.Context, subID string, dlID string) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, subID, dlID)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.DeadLetter, error)); ok {
+		return rf(ctx, subID, dlID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.DeadLetter); ok {
+		r0 = rf(ctx, subID, dlID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, subID, dlID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// RequestReply provides a mock function with given fields: ctx, msg
-func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (*core.MessageInOut, error) {
-	ret := _m.Called(ctx, msg)
+// RequestReply provides a mock function with given fields: ctx, msg, async
+func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut, async bool) (*core.MessageInOut, error) {
+	ret := _m.Called(ctx, msg, async)
 
 	var r0 *core.MessageInOut
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) (*core.MessageInOut, error)); ok {
-		return rf(ctx, msg)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut, bool) (*core.MessageInOut, error)); ok {
+		return rf(ctx, msg, async)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) *core.MessageInOut); ok {
-		r0 = rf(ctx, msg)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut, bool) *core.MessageInOut); ok {
+		r0 = rf(ctx, msg, async)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.MessageInOut)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut) error); ok {
-		r1 = rf(ctx, msg)
+	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut, bool) error); ok {
+		r1 = rf(ctx, msg, async)
 	} else {
 		r1 = ret.Error(1)
 	}
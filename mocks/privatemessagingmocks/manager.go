@@ -20,6 +20,32 @@ type Manager struct {
 	mock.Mock
 }
 
+// DecryptTransport provides a mock function with given fields: ctx, encrypted
+func (_m *Manager) DecryptTransport(ctx context.Context, encrypted *core.EncryptedTransport) (*core.TransportWrapper, error) {
+	ret := _m.Called(ctx, encrypted)
+
+	var r0 *core.TransportWrapper
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EncryptedTransport) (*core.TransportWrapper, error)); ok {
+		return rf(ctx, encrypted)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EncryptedTransport) *core.TransportWrapper); ok {
+		r0 = rf(ctx, encrypted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TransportWrapper)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.EncryptedTransport) error); ok {
+		r1 = rf(ctx, encrypted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnsureLocalGroup provides a mock function with given fields: ctx, group, creator
 func (_m *Manager) EnsureLocalGroup(ctx context.Context, group *core.Group, creator *core.Member) (bool, error) {
 	ret := _m.Called(ctx, group, creator)
@@ -161,25 +187,25 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
-// RequestReply provides a mock function with given fields: ctx, request
-func (_m *Manager) RequestReply(ctx context.Context, request *core.MessageInOut) (*core.MessageInOut, error) {
-	ret := _m.Called(ctx, request)
+// RequestReply provides a mock function with given fields: ctx, request, async
+func (_m *Manager) RequestReply(ctx context.Context, request *core.MessageInOut, async bool) (*core.MessageInOut, error) {
+	ret := _m.Called(ctx, request, async)
 
 	var r0 *core.MessageInOut
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) (*core.MessageInOut, error)); ok {
-		return rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut, bool) (*core.MessageInOut, error)); ok {
+		return rf(ctx, request, async)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) *core.MessageInOut); ok {
-		r0 = rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut, bool) *core.MessageInOut); ok {
+		r0 = rf(ctx, request, async)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.MessageInOut)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut, bool) error); ok {
+		r1 = rf(ctx, request, async)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -272,6 +298,16 @@ func (_m *Manager) SendMessage(ctx context.Context, in *core.MessageInOut, waitC
 	return r0, r1
 }
 
+// Start provides a mock function with given fields:
+func (_m *Manager) Start() {
+	_m.Called()
+}
+
+// WaitStop provides a mock function with given fields:
+func (_m *Manager) WaitStop() {
+	_m.Called()
+}
+
 type mockConstructorTestingTNewManager interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,6 +4,7 @@ package shareddownloadmocks
 
 import (
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
+	core "github.com/hyperledger/firefly/pkg/core"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -38,13 +39,13 @@ func (_m *Callbacks) SharedStorageBatchDownloaded(payloadRef string, data []byte
 	return r0, r1
 }
 
-// SharedStorageBlobDownloaded provides a mock function with given fields: hash, size, payloadRef, dataID
-func (_m *Callbacks) SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error {
-	ret := _m.Called(hash, size, payloadRef, dataID)
+// SharedStorageBlobDownloaded provides a mock function with given fields: hash, size, payloadRef, dataID, encryption
+func (_m *Callbacks) SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID, encryption *core.BlobEncryption) error {
+	ret := _m.Called(hash, size, payloadRef, dataID, encryption)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(fftypes.Bytes32, int64, string, *fftypes.UUID) error); ok {
-		r0 = rf(hash, size, payloadRef, dataID)
+	if rf, ok := ret.Get(0).(func(fftypes.Bytes32, int64, string, *fftypes.UUID, *core.BlobEncryption) error); ok {
+		r0 = rf(hash, size, payloadRef, dataID, encryption)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -186,6 +186,32 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// QueryBalance provides a mock function with given fields: ctx, poolLocator, tokenIndex, key, blockNumber
+func (_m *Plugin) QueryBalance(ctx context.Context, poolLocator string, tokenIndex string, key string, blockNumber string) (*tokens.Balance, error) {
+	ret := _m.Called(ctx, poolLocator, tokenIndex, key, blockNumber)
+
+	var r0 *tokens.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*tokens.Balance, error)); ok {
+		return rf(ctx, poolLocator, tokenIndex, key, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *tokens.Balance); ok {
+		r0 = rf(ctx, poolLocator, tokenIndex, key, blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tokens.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, poolLocator, tokenIndex, key, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler tokens.Callbacks) {
 	_m.Called(namespace, handler)
@@ -238,6 +264,20 @@ func (_m *Plugin) TransferTokens(ctx context.Context, nsOpID string, poolLocator
 	return r0
 }
 
+// TransferTokensBatch provides a mock function with given fields: ctx, items
+func (_m *Plugin) TransferTokensBatch(ctx context.Context, items []*tokens.TransferBatchItem) error {
+	ret := _m.Called(ctx, items)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*tokens.TransferBatchItem) error); ok {
+		r0 = rf(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPlugin interface {
 	mock.TestingT
 	Cleanup(func())
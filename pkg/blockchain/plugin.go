@@ -74,8 +74,8 @@ type Plugin interface {
 	// SubmitBatchPin sequences a batch of message globally to all viewers of a given ledger
 	SubmitBatchPin(ctx context.Context, nsOpID, networkNamespace, signingKey string, batch *BatchPin, location *fftypes.JSONAny) error
 
-	// SubmitNetworkAction writes a special "BatchPin" event which signals the plugin to take an action
-	SubmitNetworkAction(ctx context.Context, nsOpID, signingKey string, action core.NetworkActionType, location *fftypes.JSONAny) error
+	// SubmitNetworkAction writes a special "BatchPin" event which signals the plugin to take an action, with an optional payload
+	SubmitNetworkAction(ctx context.Context, nsOpID, signingKey string, action core.NetworkActionType, payload string, location *fftypes.JSONAny) error
 
 	// DeployContract submits a new transaction to deploy a new instance of a smart contract
 	DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) error
@@ -150,10 +150,10 @@ type Callbacks interface {
 	// Error should only be returned in shutdown scenarios
 	BatchPinComplete(namespace string, batch *BatchPin, signingKey *core.VerifierRef) error
 
-	// BlockchainNetworkAction notifies on the arrival of a network operator action
+	// BlockchainNetworkAction notifies on the arrival of a network operator action, with any payload it carries
 	//
 	// Error should only be returned in shutdown scenarios
-	BlockchainNetworkAction(action string, location *fftypes.JSONAny, event *Event, signingKey *core.VerifierRef) error
+	BlockchainNetworkAction(action, payload string, location *fftypes.JSONAny, event *Event, signingKey *core.VerifierRef) error
 
 	// BlockchainEvent notifies on the arrival of any event from a user-created subscription.
 	BlockchainEvent(event *EventWithSubscription) error
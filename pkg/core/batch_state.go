@@ -37,11 +37,20 @@ type BatchState struct {
 	// - If any Finalize callback errors out, batch will be aborted and retried (small chance of duplicate execution here)
 	Finalize []func(ctx context.Context) error
 
+	// Cleanup callbacks discard in-memory state (such as cache entries) derived from the database updates of the batch
+	// - Will execute once the database RunAsGroup of the batch has completed, whether it committed or rolled back
+	// - Cannot fail the batch
+	Cleanup []func(ctx context.Context)
+
 	// PendingConfirms are messages that are pending confirmation after already being processed in this batch
 	PendingConfirms map[fftypes.UUID]*Message
 
 	// ConfirmedDIDClaims are DID claims locked in within this batch
 	ConfirmedDIDClaims []string
+
+	// PinSequence is the sequence of the pin for the definition currently being processed
+	// - Zero for definitions that are processed locally, without being pinned
+	PinSequence int64
 }
 
 func (bs *BatchState) AddPreFinalize(action func(ctx context.Context) error) {
@@ -52,6 +61,10 @@ func (bs *BatchState) AddFinalize(action func(ctx context.Context) error) {
 	bs.Finalize = append(bs.Finalize, action)
 }
 
+func (bs *BatchState) AddCleanup(action func(ctx context.Context)) {
+	bs.Cleanup = append(bs.Cleanup, action)
+}
+
 func (bs *BatchState) AddPendingConfirm(id *fftypes.UUID, message *Message) {
 	bs.PendingConfirms[*id] = message
 }
@@ -77,3 +90,9 @@ func (bs *BatchState) RunFinalize(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (bs *BatchState) RunCleanup(ctx context.Context) {
+	for _, action := range bs.Cleanup {
+		action(ctx)
+	}
+}
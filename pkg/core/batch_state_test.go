@@ -51,6 +51,17 @@ func TestBatchStateFinalizerErrors(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestBatchStateCleanup(t *testing.T) {
+	bs := BatchState{}
+
+	run := 0
+	bs.AddCleanup(func(ctx context.Context) { run++ })
+	bs.AddCleanup(func(ctx context.Context) { run++ })
+
+	bs.RunCleanup(context.Background())
+	assert.Equal(t, 2, run)
+}
+
 func TestBatchStateIdentities(t *testing.T) {
 	bs := BatchState{
 		PendingConfirms: make(map[fftypes.UUID]*Message),
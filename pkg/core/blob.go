@@ -16,7 +16,14 @@
 
 package core
 
-import "github.com/hyperledger/firefly-common/pkg/fftypes"
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
 
 type Blob struct {
 	Sequence   int64            `json:"-"`
@@ -27,4 +34,33 @@ type Blob struct {
 	Peer       string           `json:"peer,omitempty"`
 	Size       int64            `json:"size"`
 	DataID     *fftypes.UUID    `json:"data_id"`
+	Encryption *BlobEncryption  `json:"encryption,omitempty"`
+}
+
+// BlobEncryption records how a blob is encrypted at rest in the local data exchange.
+// The hash and size of the blob always describe the plaintext.
+type BlobEncryption struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"keyId"`
+	DataKey   []byte `json:"dataKey"` // wrapped by the key encryption key identified by KeyID
+}
+
+// Scan implements sql.Scanner
+func (be *BlobEncryption) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		}
+		return json.Unmarshal(src, be)
+	case string:
+		return be.Scan([]byte(src))
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, be)
+	}
+}
+
+// Value implements sql.Valuer
+func (be BlobEncryption) Value() (driver.Value, error) {
+	return json.Marshal(be)
 }
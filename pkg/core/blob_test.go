@@ -0,0 +1,51 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobEncryptionDatabaseSerialization(t *testing.T) {
+	enc1 := &BlobEncryption{
+		Algorithm: "aes-256-gcm-stream",
+		KeyID:     "kek1",
+		DataKey:   []byte{0x01, 0x02},
+	}
+
+	// Verify it serializes as bytes to the database
+	val1, err := enc1.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"algorithm":"aes-256-gcm-stream","keyId":"kek1","dataKey":"AQI="}`, string(val1.([]byte)))
+
+	// Verify it restores ok
+	enc2 := &BlobEncryption{}
+	err = enc2.Scan(val1)
+	assert.NoError(t, err)
+	assert.Equal(t, *enc1, *enc2)
+
+	// Verify it ignores a blank string
+	err = enc2.Scan("")
+	assert.NoError(t, err)
+	assert.Equal(t, "kek1", enc2.KeyID)
+
+	// Out of luck with anything else
+	err = enc2.Scan(false)
+	assert.Regexp(t, "FF00105", err)
+}
@@ -0,0 +1,107 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// BlobUploadState is the state of a resumable blob upload
+type BlobUploadState = fftypes.FFEnum
+
+var (
+	// BlobUploadStateReceiving is an upload that is receiving chunks
+	BlobUploadStateReceiving = fftypes.FFEnumValue("blobuploadstate", "receiving")
+	// BlobUploadStateFinalizing is an upload whose chunks are being combined into a blob in the background
+	BlobUploadStateFinalizing = fftypes.FFEnumValue("blobuploadstate", "finalizing")
+	// BlobUploadStateComplete is an upload that has been stored as a data item
+	BlobUploadStateComplete = fftypes.FFEnumValue("blobuploadstate", "complete")
+	// BlobUploadStateFailed is an upload that could not be finalized, which can be finalized again
+	BlobUploadStateFailed = fftypes.FFEnumValue("blobuploadstate", "failed")
+)
+
+// BlobUpload is a resumable upload of a blob, which is received as a sequence of chunks that are
+// staged in the local data exchange, and then finalized into a data item with the blob attached
+type BlobUpload struct {
+	ID        *fftypes.UUID       `ffstruct:"BlobUpload" json:"id"`
+	Namespace string              `ffstruct:"BlobUpload" json:"namespace"`
+	State     BlobUploadState     `ffstruct:"BlobUpload" json:"state" ffenum:"blobuploadstate"`
+	Size      int64               `ffstruct:"BlobUpload" json:"size,omitempty"`
+	Received  int64               `ffstruct:"BlobUpload" json:"received"`
+	Data      *fftypes.UUID       `ffstruct:"BlobUpload" json:"data,omitempty"`
+	Error     string              `ffstruct:"BlobUpload" json:"error,omitempty"`
+	Created   *fftypes.FFTime     `ffstruct:"BlobUpload" json:"created"`
+	Updated   *fftypes.FFTime     `ffstruct:"BlobUpload" json:"updated"`
+	Expires   *fftypes.FFTime     `ffstruct:"BlobUpload" json:"expires"`
+	Chunks    BlobUploadChunks    `json:"-"`
+	HashState string              `json:"-"` // serialized state of the hash over all the chunks received so far
+	Finalize  *BlobUploadFinalize `json:"-"` // the finalize request being processed in the background
+}
+
+// BlobUploadChunk is a chunk of a blob upload that has been staged in the local data exchange
+type BlobUploadChunk struct {
+	Offset     int64           `json:"offset"`
+	Size       int64           `json:"size"`
+	PayloadRef string          `json:"payloadRef"`
+	Encryption *BlobEncryption `json:"encryption,omitempty"`
+}
+
+type BlobUploadChunks []*BlobUploadChunk
+
+// Scan implements sql.Scanner
+func (bc *BlobUploadChunks) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		}
+		return json.Unmarshal(src, bc)
+	case string:
+		return bc.Scan([]byte(src))
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, bc)
+	}
+}
+
+// Value implements sql.Valuer
+func (bc BlobUploadChunks) Value() (driver.Value, error) {
+	return json.Marshal(bc)
+}
+
+// BlobUploadInput is the input to start a resumable blob upload
+type BlobUploadInput struct {
+	Size int64 `ffstruct:"BlobUploadInput" json:"size,omitempty"`
+}
+
+// BlobUploadFinalize is the input to complete a resumable blob upload, and create the data item.
+// It is stored on the upload while the upload is finalized in the background.
+type BlobUploadFinalize struct {
+	Validator ValidatorType    `ffstruct:"BlobUploadFinalize" json:"validator,omitempty"`
+	Datatype  *DatatypeRef     `ffstruct:"BlobUploadFinalize" json:"datatype,omitempty"`
+	Value     *fftypes.JSONAny `ffstruct:"BlobUploadFinalize" json:"value,omitempty"`
+	Hash      *fftypes.Bytes32 `ffstruct:"BlobUploadFinalize" json:"hash,omitempty"`
+	AutoMeta  bool             `ffstruct:"BlobUploadFinalize" json:"autometa,omitempty"`
+	Filename  string           `ffstruct:"BlobUploadFinalize" json:"filename,omitempty"`
+	Mimetype  string           `ffstruct:"BlobUploadFinalize" json:"mimetype,omitempty"`
+}
@@ -0,0 +1,52 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobUploadChunksDatabaseSerialization(t *testing.T) {
+	chunks1 := BlobUploadChunks{
+		{Offset: 0, Size: 10, PayloadRef: "ns1/chunk1"},
+		{Offset: 10, Size: 5, PayloadRef: "ns1/chunk2", Encryption: &BlobEncryption{Algorithm: "aes-256-gcm-stream", KeyID: "kek1"}},
+	}
+
+	// Verify it serializes as bytes to the database
+	val1, err := chunks1.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"offset":0,"size":10,"payloadRef":"ns1/chunk1"},{"offset":10,"size":5,"payloadRef":"ns1/chunk2","encryption":{"algorithm":"aes-256-gcm-stream","keyId":"kek1","dataKey":null}}]`, string(val1.([]byte)))
+
+	// Verify it restores ok
+	var chunks2 BlobUploadChunks
+	err = chunks2.Scan(val1)
+	assert.NoError(t, err)
+	assert.Equal(t, chunks1, chunks2)
+
+	// Verify it ignores a blank string and nil
+	err = chunks2.Scan("")
+	assert.NoError(t, err)
+	err = chunks2.Scan(nil)
+	assert.NoError(t, err)
+	assert.Len(t, chunks2, 2)
+
+	// Out of luck with anything else
+	err = chunks2.Scan(false)
+	assert.Regexp(t, "FF00105", err)
+}
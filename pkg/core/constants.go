@@ -60,4 +60,22 @@ const (
 
 	// SystemTagIdentityUpdate is the tag for messages that broadcast an identity update
 	SystemTagIdentityUpdate = "ff_identity_update"
+
+	// SystemTagIdentityRevoke is the tag for messages that broadcast the revocation of an identity, or one of its verifiers
+	SystemTagIdentityRevoke = "ff_identity_revoke"
+
+	// SystemTagIdentityKeyRotation is the tag for messages that broadcast a new verifier replacing the existing verifiers of an identity
+	SystemTagIdentityKeyRotation = "ff_identity_rotate_key"
+
+	// SystemTagDefinitionVote is the tag for messages that broadcast a vote on a definition proposal
+	SystemTagDefinitionVote = "ff_definition_vote"
+
+	// SystemTagOrgApplication is the tag for messages that broadcast an application for an org and its node to join the network
+	SystemTagOrgApplication = "ff_org_application"
+
+	// SystemTagOrgApplicationReview is the tag for messages that broadcast the decision of a sponsor on an org application
+	SystemTagOrgApplicationReview = "ff_org_application_review"
+
+	// SystemTagDefineNetworkGovernance is the tag for messages that broadcast the governance rules of the network
+	SystemTagDefineNetworkGovernance = "ff_define_network_governance"
 )
@@ -67,6 +67,7 @@ type ContractAPI struct {
 	Name        string                `ffstruct:"ContractAPI" json:"name"`
 	NetworkName string                `ffstruct:"ContractAPI" json:"networkName,omitempty"`
 	Message     *fftypes.UUID         `ffstruct:"ContractAPI" json:"message,omitempty" ffexcludeinput:"true"`
+	Group       *fftypes.Bytes32      `ffstruct:"ContractAPI" json:"group,omitempty" ffexcludeinput:"true"`
 	URLs        ContractURLs          `ffstruct:"ContractAPI" json:"urls" ffexcludeinput:"true"`
 	Published   bool                  `ffstruct:"ContractAPI" json:"published" ffexcludeinput:"true"`
 }
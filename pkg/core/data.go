@@ -132,7 +132,7 @@ func (da DataArray) Refs() DataRefs {
 
 func CheckValidatorType(ctx context.Context, validator ValidatorType) error {
 	switch validator {
-	case ValidatorTypeJSON, ValidatorTypeNone, ValidatorTypeSystemDefinition:
+	case ValidatorTypeJSON, ValidatorTypeNone, ValidatorTypeSystemDefinition, ValidatorTypeXSD, ValidatorTypeProtobuf:
 		return nil
 	default:
 		return i18n.NewError(ctx, i18n.MsgUnknownValidatorType, validator)
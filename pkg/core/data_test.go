@@ -54,6 +54,12 @@ func TestValidateBadValidator(t *testing.T) {
 	assert.Regexp(t, "FF00108", err)
 }
 
+func TestValidateValidatorTypes(t *testing.T) {
+	for _, v := range []ValidatorType{ValidatorTypeJSON, ValidatorTypeNone, ValidatorTypeSystemDefinition, ValidatorTypeXSD, ValidatorTypeProtobuf} {
+		assert.NoError(t, CheckValidatorType(context.Background(), v))
+	}
+}
+
 func TestSealNoData(t *testing.T) {
 	d := &Data{}
 	err := d.Seal(context.Background(), nil)
@@ -32,12 +32,17 @@ var (
 	ValidatorTypeNone = fftypes.FFEnumValue("validatortype", "none")
 	// ValidatorTypeSystemDefinition is the validator type for system definitions
 	ValidatorTypeSystemDefinition = fftypes.FFEnumValue("validatortype", "definition")
+	// ValidatorTypeXSD is the validator type for XML Schema validation of XML documents
+	ValidatorTypeXSD = fftypes.FFEnumValue("validatortype", "xsd")
+	// ValidatorTypeProtobuf is the validator type for validation against a protobuf message descriptor
+	ValidatorTypeProtobuf = fftypes.FFEnumValue("validatortype", "protobuf")
 )
 
 // Datatype is the structure defining a data definition, such as a JSON schema
 type Datatype struct {
 	ID        *fftypes.UUID    `ffstruct:"Datatype" json:"id,omitempty" ffexcludeinput:"true"`
 	Message   *fftypes.UUID    `ffstruct:"Datatype" json:"message,omitempty" ffexcludeinput:"true"`
+	Group     *fftypes.Bytes32 `ffstruct:"Datatype" json:"group,omitempty" ffexcludeinput:"true"`
 	Validator ValidatorType    `ffstruct:"Datatype" json:"validator" ffenum:"validatortype"`
 	Namespace string           `ffstruct:"Datatype" json:"namespace,omitempty" ffexcludeinput:"true"`
 	Name      string           `ffstruct:"Datatype" json:"name,omitempty"`
@@ -48,7 +53,9 @@ type Datatype struct {
 }
 
 func (dt *Datatype) Validate(ctx context.Context, existing bool) (err error) {
-	if dt.Validator != ValidatorTypeJSON {
+	switch dt.Validator {
+	case ValidatorTypeJSON, ValidatorTypeXSD, ValidatorTypeProtobuf:
+	default:
 		return i18n.NewError(ctx, i18n.MsgUnknownFieldValue, "validator", dt.Validator)
 	}
 	if err = fftypes.ValidateFFNameFieldNoUUID(ctx, dt.Name, "name"); err != nil {
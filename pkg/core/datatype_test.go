@@ -63,6 +63,14 @@ func TestDatatypeValidation(t *testing.T) {
 	}
 	assert.NoError(t, dt.Validate(context.Background(), false))
 
+	dt.Validator = ValidatorTypeXSD
+	assert.NoError(t, dt.Validate(context.Background(), false))
+	dt.Validator = ValidatorTypeProtobuf
+	assert.NoError(t, dt.Validate(context.Background(), false))
+	dt.Validator = ValidatorTypeNone
+	assert.Regexp(t, "FF00111.*none", dt.Validate(context.Background(), false))
+	dt.Validator = ValidatorTypeJSON
+
 	assert.Regexp(t, "FF00114", dt.Validate(context.Background(), true))
 
 	dt.ID = fftypes.NewUUID()
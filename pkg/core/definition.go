@@ -27,5 +27,6 @@ type Definition interface {
 }
 
 type DefinitionPublish struct {
-	NetworkName string `ffstruct:"DefinitionPublish" json:"networkName,omitempty"`
+	NetworkName string           `ffstruct:"DefinitionPublish" json:"networkName,omitempty"`
+	Group       *fftypes.Bytes32 `ffstruct:"DefinitionPublish" json:"group,omitempty"`
 }
@@ -0,0 +1,115 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// DefinitionProposalState is the governance state of a definition broadcast
+type DefinitionProposalState = fftypes.FFEnum
+
+var (
+	// DefinitionProposalStateProposed is a definition that is awaiting a quorum of votes from the root orgs of the network
+	DefinitionProposalStateProposed = fftypes.FFEnumValue("proposalstate", "proposed")
+	// DefinitionProposalStateConfirmed is a definition that reached a quorum of approvals, and has been applied
+	DefinitionProposalStateConfirmed = fftypes.FFEnumValue("proposalstate", "confirmed")
+	// DefinitionProposalStateRejected is a definition that reached a quorum of rejections, or failed to apply once approved
+	DefinitionProposalStateRejected = fftypes.FFEnumValue("proposalstate", "rejected")
+)
+
+// DefinitionProposal is a definition broadcast that is held until a quorum of the root orgs in the network
+// have voted to approve it, in a namespace where definition governance is enabled.
+// The ID of the proposal is the ID of the message that broadcast the definition.
+type DefinitionProposal struct {
+	ID        *fftypes.UUID           `ffstruct:"DefinitionProposal" json:"id"`
+	Namespace string                  `ffstruct:"DefinitionProposal" json:"namespace"`
+	Tag       string                  `ffstruct:"DefinitionProposal" json:"tag"`
+	Topic     string                  `ffstruct:"DefinitionProposal" json:"topic"`
+	Author    string                  `ffstruct:"DefinitionProposal" json:"author"`
+	TX        *fftypes.UUID           `ffstruct:"DefinitionProposal" json:"tx,omitempty"`
+	State     DefinitionProposalState `ffstruct:"DefinitionProposal" json:"state" ffenum:"proposalstate"`
+	Quorum    int                     `ffstruct:"DefinitionProposal" json:"quorum"`
+	Votes     DefinitionProposalVotes `ffstruct:"DefinitionProposal" json:"votes"`
+	Error     string                  `ffstruct:"DefinitionProposal" json:"error,omitempty"`
+	Created   *fftypes.FFTime         `ffstruct:"DefinitionProposal" json:"created"`
+	Updated   *fftypes.FFTime         `ffstruct:"DefinitionProposal" json:"updated,omitempty"`
+}
+
+// DefinitionProposalVote is a vote recorded against a proposal, from a root org of the network
+type DefinitionProposalVote struct {
+	Org     *fftypes.UUID   `ffstruct:"DefinitionProposalVote" json:"org"`
+	Author  string          `ffstruct:"DefinitionProposalVote" json:"author"`
+	Approve bool            `ffstruct:"DefinitionProposalVote" json:"approve"`
+	Message *fftypes.UUID   `ffstruct:"DefinitionProposalVote" json:"message"`
+	Created *fftypes.FFTime `ffstruct:"DefinitionProposalVote" json:"created"`
+}
+
+type DefinitionProposalVotes []*DefinitionProposalVote
+
+// Scan implements sql.Scanner
+func (dv *DefinitionProposalVotes) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		}
+		return json.Unmarshal(src, dv)
+	case string:
+		return dv.Scan([]byte(src))
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, dv)
+	}
+}
+
+// Value implements sql.Valuer
+func (dv DefinitionProposalVotes) Value() (driver.Value, error) {
+	return json.Marshal(dv)
+}
+
+// Count returns the number of votes for or against the proposal
+func (dv DefinitionProposalVotes) Count(approve bool) (count int) {
+	for _, v := range dv {
+		if v.Approve == approve {
+			count++
+		}
+	}
+	return count
+}
+
+// DefinitionVote is the data payload used in a message to broadcast a vote on a definition proposal.
+// It is broadcast on the same topic as the proposal, so that it is always sequenced after the proposal.
+type DefinitionVote struct {
+	Proposal      *fftypes.UUID `ffstruct:"DefinitionVote" json:"proposal" ffexcludeinput:"true"`
+	ProposalTopic string        `ffstruct:"DefinitionVote" json:"topic" ffexcludeinput:"true"`
+	Approve       bool          `ffstruct:"DefinitionVote" json:"approve"`
+}
+
+func (dv *DefinitionVote) Topic() string {
+	return dv.ProposalTopic
+}
+
+func (dv *DefinitionVote) SetBroadcastMessage(msgID *fftypes.UUID) {
+	// nop-op here, the definition handler records the vote message ID on the proposal.
+}
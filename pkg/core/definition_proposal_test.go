@@ -0,0 +1,73 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitionProposalVotesDatabaseSerialization(t *testing.T) {
+	org := fftypes.MustParseUUID("4a5ee1e2-3b5c-4a4d-9e0c-6a5ee1e23b5c")
+	msg := fftypes.MustParseUUID("5b6ff2f3-4c6d-4b5e-8f1d-7b6ff2f34c6d")
+	votes1 := DefinitionProposalVotes{
+		{Org: org, Author: "did:firefly:org/org1", Approve: true, Message: msg},
+	}
+
+	// Verify it serializes as bytes to the database
+	val1, err := votes1.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"org":"4a5ee1e2-3b5c-4a4d-9e0c-6a5ee1e23b5c","author":"did:firefly:org/org1","approve":true,"message":"5b6ff2f3-4c6d-4b5e-8f1d-7b6ff2f34c6d","created":null}]`, string(val1.([]byte)))
+
+	// Verify it restores ok
+	var votes2 DefinitionProposalVotes
+	err = votes2.Scan(val1)
+	assert.NoError(t, err)
+	assert.Equal(t, votes1, votes2)
+
+	// Verify it ignores a blank string and nil
+	err = votes2.Scan("")
+	assert.NoError(t, err)
+	err = votes2.Scan(nil)
+	assert.NoError(t, err)
+	assert.Len(t, votes2, 1)
+
+	// Out of luck with anything else
+	err = votes2.Scan(false)
+	assert.Regexp(t, "FF00105", err)
+}
+
+func TestDefinitionProposalVotesCount(t *testing.T) {
+	votes := DefinitionProposalVotes{
+		{Approve: true},
+		{Approve: false},
+		{Approve: true},
+	}
+	assert.Equal(t, 2, votes.Count(true))
+	assert.Equal(t, 1, votes.Count(false))
+}
+
+func TestDefinitionVoteTopic(t *testing.T) {
+	vote := &DefinitionVote{
+		Proposal:      fftypes.NewUUID(),
+		ProposalTopic: "topic1",
+	}
+	vote.SetBroadcastMessage(fftypes.NewUUID())
+	assert.Equal(t, "topic1", vote.Topic())
+}
@@ -29,6 +29,8 @@ var (
 	EventTypeMessageConfirmed = fftypes.FFEnumValue("eventtype", "message_confirmed")
 	// EventTypeMessageRejected occurs if a message is received and confirmed from a sequencing perspective, but is rejected as invalid (mismatch to schema, or duplicate system broadcast)
 	EventTypeMessageRejected = fftypes.FFEnumValue("eventtype", "message_rejected")
+	// EventTypeMessageReplyOverdue occurs on the sending node when a request message passes its reply deadline without a reply being confirmed
+	EventTypeMessageReplyOverdue = fftypes.FFEnumValue("eventtype", "message_reply_overdue")
 	// EventTypeDatatypeConfirmed occurs when a new datatype is ready for use (on the namespace of the datatype)
 	EventTypeDatatypeConfirmed = fftypes.FFEnumValue("eventtype", "datatype_confirmed")
 	// EventTypeIdentityConfirmed occurs when a new identity has been confirmed, as as result of a signed claim broadcast, and any associated claim verification
@@ -51,6 +53,10 @@ var (
 	EventTypeContractInterfaceConfirmed = fftypes.FFEnumValue("eventtype", "contract_interface_confirmed")
 	// EventTypeContractAPIConfirmed occurs when a new contract API has been confirmed
 	EventTypeContractAPIConfirmed = fftypes.FFEnumValue("eventtype", "contract_api_confirmed")
+	// EventTypeDefinitionProposed occurs when a definition broadcast is held for approval, in a namespace with definition governance enabled
+	EventTypeDefinitionProposed = fftypes.FFEnumValue("eventtype", "definition_proposed")
+	// EventTypeDefinitionRejected occurs when a definition proposal is voted down, or fails to apply once approved
+	EventTypeDefinitionRejected = fftypes.FFEnumValue("eventtype", "definition_rejected")
 	// EventTypeBlockchainEventReceived occurs when a new event has been received from the blockchain
 	EventTypeBlockchainEventReceived = fftypes.FFEnumValue("eventtype", "blockchain_event_received")
 	// EventTypeBlockchainInvokeOpSucceeded occurs when a blockchain "invoke" request has succeeded
@@ -61,6 +67,8 @@ var (
 	EventTypeBlockchainContractDeployOpSucceeded = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_succeeded")
 	// EventTypeBlockchainContractDeployOpFailed occurs when a contract deployment request has failed
 	EventTypeBlockchainContractDeployOpFailed = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_failed")
+	// EventTypeTokenBalanceCorrected occurs when token balance reconciliation corrects a local balance to match the token connector
+	EventTypeTokenBalanceCorrected = fftypes.FFEnumValue("eventtype", "token_balance_corrected")
 )
 
 // Event is an activity in the system, delivered reliably to applications, that indicates something has happened in the network
@@ -79,17 +87,19 @@ type Event struct {
 // EnrichedEvent adds the referred object to an event
 type EnrichedEvent struct {
 	Event
-	BlockchainEvent   *BlockchainEvent `ffstruct:"EnrichedEvent" json:"blockchainEvent,omitempty"`
-	ContractAPI       *ContractAPI     `ffstruct:"EnrichedEvent" json:"contractAPI,omitempty"`
-	ContractInterface *fftypes.FFI     `ffstruct:"EnrichedEvent" json:"contractInterface,omitempty"`
-	Datatype          *Datatype        `ffstruct:"EnrichedEvent" json:"datatype,omitempty"`
-	Identity          *Identity        `ffstruct:"EnrichedEvent" json:"identity,omitempty"`
-	Message           *Message         `ffstruct:"EnrichedEvent" json:"message,omitempty"`
-	TokenApproval     *TokenApproval   `ffstruct:"EnrichedEvent" json:"tokenApproval,omitempty"`
-	TokenPool         *TokenPool       `ffstruct:"EnrichedEvent" json:"tokenPool,omitempty"`
-	TokenTransfer     *TokenTransfer   `ffstruct:"EnrichedEvent" json:"tokenTransfer,omitempty"`
-	Transaction       *Transaction     `ffstruct:"EnrichedEvent" json:"transaction,omitempty"`
-	Operation         *Operation       `ffstruct:"EnrichedEvent" json:"operation,omitempty"`
+	BlockchainEvent      *BlockchainEvent      `ffstruct:"EnrichedEvent" json:"blockchainEvent,omitempty"`
+	ContractAPI          *ContractAPI          `ffstruct:"EnrichedEvent" json:"contractAPI,omitempty"`
+	ContractInterface    *fftypes.FFI          `ffstruct:"EnrichedEvent" json:"contractInterface,omitempty"`
+	Datatype             *Datatype             `ffstruct:"EnrichedEvent" json:"datatype,omitempty"`
+	DefinitionProposal   *DefinitionProposal   `ffstruct:"EnrichedEvent" json:"definitionProposal,omitempty"`
+	Identity             *Identity             `ffstruct:"EnrichedEvent" json:"identity,omitempty"`
+	Message              *Message              `ffstruct:"EnrichedEvent" json:"message,omitempty"`
+	TokenApproval        *TokenApproval        `ffstruct:"EnrichedEvent" json:"tokenApproval,omitempty"`
+	TokenBalanceMismatch *TokenBalanceMismatch `ffstruct:"EnrichedEvent" json:"tokenBalanceMismatch,omitempty"`
+	TokenPool            *TokenPool            `ffstruct:"EnrichedEvent" json:"tokenPool,omitempty"`
+	TokenTransfer        *TokenTransfer        `ffstruct:"EnrichedEvent" json:"tokenTransfer,omitempty"`
+	Transaction          *Transaction          `ffstruct:"EnrichedEvent" json:"transaction,omitempty"`
+	Operation            *Operation            `ffstruct:"EnrichedEvent" json:"operation,omitempty"`
 }
 
 // EventDelivery adds the referred object to an event, as well as details of the subscription that caused the event to
@@ -97,6 +107,14 @@ type EnrichedEvent struct {
 type EventDelivery struct {
 	EnrichedEvent
 	Subscription SubscriptionRef `json:"subscription"`
+	Replay       bool            `json:"-"` // a replay of a dead letter, which must only be acknowledged once it has been successfully delivered
+}
+
+// CombinedEventDataDelivery pairs an event with its data (if the subscription is configured to include data),
+// for delivery as part of a batch
+type CombinedEventDataDelivery struct {
+	Event *EventDelivery
+	Data  DataArray
 }
 
 // EventDeliveryResponse is the payload an application sends back, to confirm it has accepted (or rejected) the event and as such
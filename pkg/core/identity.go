@@ -50,6 +50,7 @@ type IdentityMessages struct {
 	Claim        *fftypes.UUID `ffstruct:"IdentityMessages" json:"claim"`
 	Verification *fftypes.UUID `ffstruct:"IdentityMessages" json:"verification"`
 	Update       *fftypes.UUID `ffstruct:"IdentityMessages" json:"update"`
+	Revocation   *fftypes.UUID `ffstruct:"IdentityMessages" json:"revocation"`
 }
 
 // IdentityBase are the immutable fields of an identity that determine what the identity itself is
@@ -75,12 +76,14 @@ type Identity struct {
 	Messages IdentityMessages `ffstruct:"Identity" json:"messages,omitempty" ffexcludeinput:"true"`
 	Created  *fftypes.FFTime  `ffstruct:"Identity" json:"created,omitempty" ffexcludeinput:"true"`
 	Updated  *fftypes.FFTime  `ffstruct:"Identity" json:"updated,omitempty"`
+	Revoked  *fftypes.FFTime  `ffstruct:"Identity" json:"revoked,omitempty" ffexcludeinput:"true"`
 }
 
 // IdentityWithVerifiers has an embedded array of verifiers
 type IdentityWithVerifiers struct {
 	Identity
-	Verifiers []*VerifierRef `ffstruct:"IdentityWithVerifiers" json:"verifiers"`
+	Verifiers        []*VerifierRef `ffstruct:"IdentityWithVerifiers" json:"verifiers"`
+	RevokedVerifiers []*VerifierRef `ffstruct:"IdentityWithVerifiers" json:"revokedVerifiers,omitempty"`
 }
 
 // IdentityCreateDTO is the input structure to submit to register an identity.
@@ -100,6 +103,18 @@ type IdentityUpdateDTO struct {
 	IdentityProfile
 }
 
+// IdentityRevokeDTO is the input structure to submit to revoke an identity.
+// If a key is supplied only that verifier is revoked, otherwise the identity itself
+// and all of its verifiers are revoked.
+type IdentityRevokeDTO struct {
+	Key string `ffstruct:"IdentityRevokeDTO" json:"key,omitempty"`
+}
+
+// IdentityKeyRotationDTO is the input structure to submit to rotate the blockchain signing key of an identity.
+type IdentityKeyRotationDTO struct {
+	Key string `ffstruct:"IdentityKeyRotationDTO" json:"key"`
+}
+
 // SignerRef is the nested structure representing the identity that signed a message.
 // It might comprise a resolvable by FireFly identity DID, a blockchain signing key, or both.
 type SignerRef struct {
@@ -131,6 +146,22 @@ type IdentityUpdate struct {
 	Updates  IdentityProfile `ffstruct:"IdentityUpdate" json:"updates,omitempty"`
 }
 
+// IdentityRevocation is the data payload used in a message to broadcast the revocation of an identity.
+// When a verifier is specified only that verifier is revoked, otherwise the identity and all of its
+// verifiers are revoked. Messages signed by a revoked verifier are rejected if they are sequenced
+// after the revocation.
+type IdentityRevocation struct {
+	Identity IdentityBase `ffstruct:"IdentityRevocation" json:"identity"`
+	Verifier *VerifierRef `ffstruct:"IdentityRevocation" json:"verifier,omitempty"`
+}
+
+// IdentityKeyRotation is the data payload used in a message to broadcast a new verifier for an identity.
+// All existing verifiers of the same type on the identity are revoked as the new verifier is established.
+type IdentityKeyRotation struct {
+	Identity IdentityBase `ffstruct:"IdentityKeyRotation" json:"identity"`
+	Verifier VerifierRef  `ffstruct:"IdentityKeyRotation" json:"verifier"`
+}
+
 func (ic *IdentityClaim) Topic() string {
 	return ic.Identity.Topic()
 }
@@ -156,6 +187,22 @@ func (iu *IdentityUpdate) SetBroadcastMessage(msgID *fftypes.UUID) {
 	// nop-op here, as the IdentityUpdate doesn't have a reference to the original Identity to set this.
 }
 
+func (ir *IdentityRevocation) Topic() string {
+	return ir.Identity.Topic()
+}
+
+func (ir *IdentityRevocation) SetBroadcastMessage(msgID *fftypes.UUID) {
+	// nop-op here, the definition handler records the revocation message ID on the Identity.
+}
+
+func (ik *IdentityKeyRotation) Topic() string {
+	return ik.Identity.Topic()
+}
+
+func (ik *IdentityKeyRotation) SetBroadcastMessage(msgID *fftypes.UUID) {
+	// nop-op here, as the IdentityKeyRotation doesn't have a reference to the original Identity to set this.
+}
+
 func (i *IdentityBase) Topic() string {
 	h := sha256.New()
 	h.Write([]byte(i.DID))
@@ -225,4 +225,16 @@ func TestDefinitionObjects(t *testing.T) {
 	updateMsg := fftypes.NewUUID()
 	iu.SetBroadcastMessage(updateMsg)
 
+	var ir Definition = &IdentityRevocation{
+		Identity: o.IdentityBase,
+	}
+	assert.Equal(t, o.Topic(), ir.Topic())
+	ir.SetBroadcastMessage(fftypes.NewUUID())
+
+	var ik Definition = &IdentityKeyRotation{
+		Identity: o.IdentityBase,
+	}
+	assert.Equal(t, o.Topic(), ik.Topic())
+	ik.SetBroadcastMessage(fftypes.NewUUID())
+
 }
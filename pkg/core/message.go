@@ -23,6 +23,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 )
 
 const (
@@ -36,6 +37,8 @@ type MessageType = fftypes.FFEnum
 var (
 	// MessageTypeDefinition is a message broadcasting a definition of a system type, pre-defined by firefly (namespaces, identities, data definitions, etc.)
 	MessageTypeDefinition = fftypes.FFEnumValue("messagetype", "definition")
+	// MessageTypeDefinitionPrivate is a message sharing a definition privately with the members of a group, rather than the whole network
+	MessageTypeDefinitionPrivate = fftypes.FFEnumValue("messagetype", "definition_private")
 	// MessageTypeBroadcast is a broadcast message, meaning it is intended to be visible by all parties in the network
 	MessageTypeBroadcast = fftypes.FFEnumValue("messagetype", "broadcast")
 	// MessageTypePrivate is a private message, meaning it is only sent explicitly to individual parties in the network
@@ -85,6 +88,8 @@ type MessageHeader struct {
 	Tag       string                `ffstruct:"MessageHeader" json:"tag,omitempty"`
 	DataHash  *fftypes.Bytes32      `ffstruct:"MessageHeader" json:"datahash,omitempty" ffexcludeinput:"true"`
 	TxParent  *TransactionRef       `ffstruct:"MessageHeader" json:"txparent,omitempty" ffexcludeinput:"true"`
+	Expires   *fftypes.FFTime       `ffstruct:"MessageHeader" json:"expires,omitempty"`
+	Encrypted bool                  `ffstruct:"MessageHeader" json:"encrypted,omitempty" ffexclude:"postNewMessageBroadcast"`
 }
 
 // Message is the envelope by which coordinated data exchange can happen between parties in the network
@@ -101,6 +106,7 @@ type Message struct {
 	Data           DataRefs              `ffstruct:"Message" json:"data" ffexcludeinput:"true"`
 	Pins           fftypes.FFStringArray `ffstruct:"Message" json:"pins,omitempty" ffexcludeinput:"true"`
 	IdempotencyKey IdempotencyKey        `ffstruct:"Message" json:"idempotencyKey,omitempty"`
+	ReplyDeadline  *fftypes.FFTime       `ffstruct:"Message" json:"replyDeadline,omitempty" ffexcludeinput:"true"`
 	Sequence       int64                 `ffstruct:"Message" json:"-"` // Local database sequence used internally for batch assembly
 }
 
@@ -125,8 +131,9 @@ func (m *Message) BatchMessage() *Message {
 // will be broken out and stored separately during the call.
 type MessageInOut struct {
 	Message
-	InlineData InlineData  `ffstruct:"MessageInOut" json:"data,omitempty"`
-	Group      *InputGroup `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	InlineData   InlineData          `ffstruct:"MessageInOut" json:"data,omitempty"`
+	Group        *InputGroup         `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	ReplyTimeout *fftypes.FFDuration `ffstruct:"MessageInOut" json:"replyTimeout,omitempty" ffexclude:"postNewMessageBroadcast"`
 }
 
 // InputGroup declares a group in-line for automatic resolution, without having to define a group up-front
@@ -208,6 +215,9 @@ func (m *Message) Seal(ctx context.Context) (err error) {
 	if m.Header.TxType == "" {
 		m.Header.TxType = TransactionTypeBatchPin
 	}
+	if m.Header.Expires != nil && !m.Header.Expires.Time().After(*m.Header.Created.Time()) {
+		return i18n.NewError(ctx, coremsgs.MsgMessageExpiryInPast, m.Header.Expires)
+	}
 	err = m.VerifyFields(ctx)
 	if err == nil {
 		m.Header.DataHash = m.Data.Hash()
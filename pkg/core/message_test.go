@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,29 @@ func TestSealBadTagString(t *testing.T) {
 	assert.Regexp(t, `FF00140.*header.tag`, err)
 }
 
+func TestSealExpiryInPast(t *testing.T) {
+	expired := fftypes.FFTime(time.Now().Add(-1 * time.Hour))
+	msg := Message{
+		Header: MessageHeader{
+			Expires: &expired,
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.Regexp(t, "FF10540", err)
+}
+
+func TestSealExpiryInFuture(t *testing.T) {
+	expires := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg := Message{
+		Header: MessageHeader{
+			Expires: &expires,
+		},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, msg.Verify(context.Background()))
+}
+
 func TestVerifyTXType(t *testing.T) {
 	msg := Message{
 		Header: MessageHeader{
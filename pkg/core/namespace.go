@@ -45,6 +45,22 @@ type NamespaceWithInitStatus struct {
 type MultipartyContracts struct {
 	Active     *MultipartyContract   `ffstruct:"MultipartyContracts" json:"active"`
 	Terminated []*MultipartyContract `ffstruct:"MultipartyContracts" json:"terminated,omitempty"`
+	Pauses     []*NetworkPause       `ffstruct:"MultipartyContracts" json:"pauses,omitempty"`
+}
+
+// NetworkPause records the network actions that paused, and then resumed, the acceptance of batch pins on a namespace.
+// The events identify positions in the ledger, so every member rejects the same batch pins - including when replaying.
+type NetworkPause struct {
+	Event       string          `ffstruct:"NetworkPause" json:"event"`
+	Timestamp   *fftypes.FFTime `ffstruct:"NetworkPause" json:"timestamp"`
+	ResumeEvent string          `ffstruct:"NetworkPause" json:"resumeEvent,omitempty"`
+	Resumed     *fftypes.FFTime `ffstruct:"NetworkPause" json:"resumed,omitempty"`
+}
+
+// Covers returns true if the blockchain event with the given protocol ID is after the pause, and before any resume.
+// Protocol IDs are zero-padded ledger positions, so they order correctly as strings.
+func (np *NetworkPause) Covers(protocolID string) bool {
+	return protocolID > np.Event && (np.ResumeEvent == "" || protocolID < np.ResumeEvent)
 }
 
 // MultipartyContract represents identifying details about a FireFly multiparty contract, as read from the config file
@@ -52,14 +68,16 @@ type MultipartyContract struct {
 	Index      int                    `ffstruct:"MultipartyContract" json:"index"`
 	Location   *fftypes.JSONAny       `ffstruct:"MultipartyContract" json:"location,omitempty"`
 	FirstEvent string                 `ffstruct:"MultipartyContract" json:"firstEvent,omitempty"`
+	Options    *fftypes.JSONAny       `ffstruct:"MultipartyContract" json:"options,omitempty"`
 	Info       MultipartyContractInfo `ffstruct:"MultipartyContract" json:"info"`
 }
 
 // MultipartyContractInfo stores additional info about the FireFly multiparty contract that is computed during node operation
 type MultipartyContractInfo struct {
-	Subscription string `ffstruct:"MultipartyContract" json:"subscription,omitempty"`
-	FinalEvent   string `ffstruct:"MultipartyContract" json:"finalEvent,omitempty"`
-	Version      int    `ffstruct:"MultipartyContract" json:"version,omitempty"`
+	Subscription   string `ffstruct:"MultipartyContract" json:"subscription,omitempty"`
+	FinalEvent     string `ffstruct:"MultipartyContract" json:"finalEvent,omitempty"`
+	MigrationEvent string `ffstruct:"MultipartyContract" json:"migrationEvent,omitempty"`
+	Version        int    `ffstruct:"MultipartyContract" json:"version,omitempty"`
 }
 
 // NetworkActionType is a type of action to perform
@@ -68,10 +86,24 @@ type NetworkActionType = fftypes.FFEnum
 var (
 	// NetworkActionTerminate request all network members to stop using the current contract and move to the next one configured
 	NetworkActionTerminate = fftypes.FFEnumValue("networkactiontype", "terminate")
+	// NetworkActionPause requests all network members to stop accepting new batch pins on the namespace, until it is resumed
+	NetworkActionPause = fftypes.FFEnumValue("networkactiontype", "pause")
+	// NetworkActionResume requests all network members to start accepting batch pins again, after a pause
+	NetworkActionResume = fftypes.FFEnumValue("networkactiontype", "resume")
+	// NetworkActionMigrate request all network members to stop using the current contract and move to the one announced in the action
+	NetworkActionMigrate = fftypes.FFEnumValue("networkactiontype", "migrate")
 )
 
 type NetworkAction struct {
-	Type NetworkActionType `ffstruct:"NetworkAction" json:"type" ffenum:"networkactiontype"`
+	Type     NetworkActionType      `ffstruct:"NetworkAction" json:"type" ffenum:"networkactiontype"`
+	Contract *NetworkActionContract `ffstruct:"NetworkAction" json:"contract,omitempty"`
+}
+
+// NetworkActionContract is the FireFly multiparty contract announced to the network by a migrate action
+type NetworkActionContract struct {
+	Location   *fftypes.JSONAny `ffstruct:"NetworkActionContract" json:"location"`
+	FirstEvent string           `ffstruct:"NetworkActionContract" json:"firstEvent,omitempty"`
+	Options    *fftypes.JSONAny `ffstruct:"NetworkActionContract" json:"options,omitempty"`
 }
 
 // Scan implements sql.Scanner
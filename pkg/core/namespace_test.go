@@ -74,3 +74,15 @@ func TestMultipartyContractsDatabaseSerialization(t *testing.T) {
 	err = contracts2.Scan(false)
 	assert.Regexp(t, "FF00105", err)
 }
+
+func TestNetworkPauseCovers(t *testing.T) {
+	pause := &NetworkPause{Event: "000000000010/000000/000000"}
+	assert.False(t, pause.Covers("000000000009/000000/000000"))
+	assert.False(t, pause.Covers("000000000010/000000/000000"))
+	assert.True(t, pause.Covers("000000000100/000000/000000"))
+
+	pause.ResumeEvent = "000000000020/000000/000000"
+	assert.True(t, pause.Covers("000000000019/000000/000005"))
+	assert.False(t, pause.Covers("000000000020/000000/000000"))
+	assert.False(t, pause.Covers("000000000100/000000/000000"))
+}
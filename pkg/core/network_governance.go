@@ -0,0 +1,52 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// NetworkGovernance holds the governance rules agreed by the root orgs of a multiparty network.
+// The rules are broadcast as a definition, so every member applies a change at the same point in the ledger.
+type NetworkGovernance struct {
+	Namespace        string          `ffstruct:"NetworkGovernance" json:"namespace" ffexcludeinput:"true"`
+	DefinitionQuorum int             `ffstruct:"NetworkGovernance" json:"definitionQuorum"`
+	OrgOnboarding    bool            `ffstruct:"NetworkGovernance" json:"orgOnboarding"`
+	Author           string          `ffstruct:"NetworkGovernance" json:"author,omitempty" ffexcludeinput:"true"`
+	Message          *fftypes.UUID   `ffstruct:"NetworkGovernance" json:"message,omitempty" ffexcludeinput:"true"`
+	Updated          *fftypes.FFTime `ffstruct:"NetworkGovernance" json:"updated,omitempty" ffexcludeinput:"true"`
+}
+
+func (ng *NetworkGovernance) Validate(ctx context.Context) error {
+	if ng.DefinitionQuorum < 0 {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidDefinitionQuorum, ng.DefinitionQuorum)
+	}
+	return nil
+}
+
+// Topic is the same for every change, so that changes to the governance rules are applied in order
+func (ng *NetworkGovernance) Topic() string {
+	return fftypes.TypeNamespaceNameTopicHash("governance", ng.Namespace, "network")
+}
+
+func (ng *NetworkGovernance) SetBroadcastMessage(msgID *fftypes.UUID) {
+	ng.Message = msgID
+}
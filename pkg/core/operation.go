@@ -61,6 +61,8 @@ var (
 	OpTypeTokenTransfer = fftypes.FFEnumValue("optype", "token_transfer")
 	// OpTypeTokenApproval is a token approval
 	OpTypeTokenApproval = fftypes.FFEnumValue("optype", "token_approval")
+	// OpTypeTokenReconcile is a reconciliation of the local balances of a token pool against the token connector
+	OpTypeTokenReconcile = fftypes.FFEnumValue("optype", "token_reconcile")
 )
 
 func (op *Operation) IsBlockchainOperation() bool {
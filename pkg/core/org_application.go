@@ -0,0 +1,173 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// OrgApplicationState is the state of an application for an org to join the network
+type OrgApplicationState = fftypes.FFEnum
+
+var (
+	// OrgApplicationStatePending is an application that is awaiting the review of its sponsor
+	OrgApplicationStatePending = fftypes.FFEnumValue("orgapplicationstate", "pending")
+	// OrgApplicationStateApproved is an application that was approved by its sponsor, and the org and node registered
+	OrgApplicationStateApproved = fftypes.FFEnumValue("orgapplicationstate", "approved")
+	// OrgApplicationStateRejected is an application that was rejected by its sponsor, or failed to register once approved
+	OrgApplicationStateRejected = fftypes.FFEnumValue("orgapplicationstate", "rejected")
+)
+
+// OrgApplicationIdentity is the org or node that will be registered when an application is approved
+type OrgApplicationIdentity struct {
+	ID   *fftypes.UUID `ffstruct:"OrgApplicationIdentity" json:"id,omitempty" ffexcludeinput:"true"`
+	Name string        `ffstruct:"OrgApplicationIdentity" json:"name,omitempty"`
+	IdentityProfile
+}
+
+// Scan implements sql.Scanner
+func (oi *OrgApplicationIdentity) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		}
+		return json.Unmarshal(src, oi)
+	case string:
+		return oi.Scan([]byte(src))
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, oi)
+	}
+}
+
+// Value implements sql.Valuer
+func (oi OrgApplicationIdentity) Value() (driver.Value, error) {
+	return json.Marshal(oi)
+}
+
+// OrgApplication is an application from a prospective org to join the network, along with its node.
+// It is broadcast by the applicant, signed with the key of the org, and an existing root org of the
+// network sponsors it. The org and node are only registered once the sponsor approves the application.
+type OrgApplication struct {
+	ID        *fftypes.UUID          `ffstruct:"OrgApplication" json:"id,omitempty" ffexcludeinput:"true"`
+	Namespace string                 `ffstruct:"OrgApplication" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Sponsor   string                 `ffstruct:"OrgApplication" json:"sponsor"`
+	Org       OrgApplicationIdentity `ffstruct:"OrgApplication" json:"org"`
+	Node      OrgApplicationIdentity `ffstruct:"OrgApplication" json:"node"`
+	Key       string                 `ffstruct:"OrgApplication" json:"key,omitempty"`
+	State     OrgApplicationState    `ffstruct:"OrgApplication" json:"state,omitempty" ffenum:"orgapplicationstate" ffexcludeinput:"true"`
+	Reason    string                 `ffstruct:"OrgApplication" json:"reason,omitempty" ffexcludeinput:"true"`
+	Message   *fftypes.UUID          `ffstruct:"OrgApplication" json:"message,omitempty" ffexcludeinput:"true"`
+	Review    *fftypes.UUID          `ffstruct:"OrgApplication" json:"review,omitempty" ffexcludeinput:"true"`
+	Created   *fftypes.FFTime        `ffstruct:"OrgApplication" json:"created,omitempty" ffexcludeinput:"true"`
+	Updated   *fftypes.FFTime        `ffstruct:"OrgApplication" json:"updated,omitempty" ffexcludeinput:"true"`
+}
+
+func orgApplicationTopic(id *fftypes.UUID) string {
+	return fftypes.TypeNamespaceNameTopicHash("orgapplication", "", id.String())
+}
+
+func (oa *OrgApplication) Validate(ctx context.Context) (err error) {
+	if oa.ID == nil || oa.Org.ID == nil || oa.Node.ID == nil {
+		return i18n.NewError(ctx, i18n.MsgNilID)
+	}
+	if err = fftypes.ValidateFFNameFieldNoUUID(ctx, oa.Org.Name, "org.name"); err != nil {
+		return err
+	}
+	if err = fftypes.ValidateFFNameFieldNoUUID(ctx, oa.Node.Name, "node.name"); err != nil {
+		return err
+	}
+	if oa.Sponsor == "" {
+		return i18n.NewError(ctx, i18n.MsgMissingRequiredField, "sponsor")
+	}
+	if oa.Key == "" {
+		return i18n.NewError(ctx, i18n.MsgMissingRequiredField, "key")
+	}
+	return nil
+}
+
+// OrgDID is the DID the org will be registered with, which the applicant uses to sign the application
+func (oa *OrgApplication) OrgDID() string {
+	return fmt.Sprintf("%s%s", FireFlyOrgDIDPrefix, oa.Org.Name)
+}
+
+// OrgIdentity is the root org identity that is registered when the application is approved
+func (oa *OrgApplication) OrgIdentity() *Identity {
+	return &Identity{
+		IdentityBase: IdentityBase{
+			ID:        oa.Org.ID,
+			DID:       oa.OrgDID(),
+			Type:      IdentityTypeOrg,
+			Namespace: oa.Namespace,
+			Name:      oa.Org.Name,
+		},
+		IdentityProfile: oa.Org.IdentityProfile,
+		Messages: IdentityMessages{
+			Claim: oa.Message,
+		},
+	}
+}
+
+// NodeIdentity is the node identity, owned by the org, that is registered when the application is approved
+func (oa *OrgApplication) NodeIdentity() *Identity {
+	return &Identity{
+		IdentityBase: IdentityBase{
+			ID:        oa.Node.ID,
+			DID:       fmt.Sprintf("%s%s", FireFlyNodeDIDPrefix, oa.Node.Name),
+			Type:      IdentityTypeNode,
+			Parent:    oa.Org.ID,
+			Namespace: oa.Namespace,
+			Name:      oa.Node.Name,
+		},
+		IdentityProfile: oa.Node.IdentityProfile,
+		Messages: IdentityMessages{
+			Claim: oa.Message,
+		},
+	}
+}
+
+func (oa *OrgApplication) Topic() string {
+	return orgApplicationTopic(oa.ID)
+}
+
+func (oa *OrgApplication) SetBroadcastMessage(msgID *fftypes.UUID) {
+	oa.Message = msgID
+}
+
+// OrgApplicationReview is the decision of the sponsor on an application. It is broadcast on the same
+// topic as the application, so that it is always sequenced after the application.
+type OrgApplicationReview struct {
+	Application *fftypes.UUID `ffstruct:"OrgApplicationReview" json:"application" ffexcludeinput:"true"`
+	Approve     bool          `ffstruct:"OrgApplicationReview" json:"approve"`
+	Reason      string        `ffstruct:"OrgApplicationReview" json:"reason,omitempty"`
+}
+
+func (oar *OrgApplicationReview) Topic() string {
+	return orgApplicationTopic(oar.Application)
+}
+
+func (oar *OrgApplicationReview) SetBroadcastMessage(msgID *fftypes.UUID) {
+	// nop-op here, the definition handler records the review message ID on the application.
+}
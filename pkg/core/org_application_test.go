@@ -0,0 +1,132 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOrgApplication() *OrgApplication {
+	return &OrgApplication{
+		ID:      fftypes.NewUUID(),
+		Sponsor: "did:firefly:org/org1",
+		Org: OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "org2",
+		},
+		Node: OrgApplicationIdentity{
+			ID:   fftypes.NewUUID(),
+			Name: "node2",
+			IdentityProfile: IdentityProfile{
+				Profile: fftypes.JSONObject{"id": "peer2"},
+			},
+		},
+		Key: "0x12345",
+	}
+}
+
+func TestOrgApplicationValidate(t *testing.T) {
+	ctx := context.Background()
+
+	app := newTestOrgApplication()
+	assert.NoError(t, app.Validate(ctx))
+
+	app = newTestOrgApplication()
+	app.Node.ID = nil
+	assert.Regexp(t, "FF00114", app.Validate(ctx))
+
+	app = newTestOrgApplication()
+	app.Org.Name = "!bad"
+	assert.Regexp(t, "FF00140.*org.name", app.Validate(ctx))
+
+	app = newTestOrgApplication()
+	app.Node.Name = "!bad"
+	assert.Regexp(t, "FF00140.*node.name", app.Validate(ctx))
+
+	app = newTestOrgApplication()
+	app.Sponsor = ""
+	assert.Regexp(t, "FF00112.*sponsor", app.Validate(ctx))
+
+	app = newTestOrgApplication()
+	app.Key = ""
+	assert.Regexp(t, "FF00112.*key", app.Validate(ctx))
+}
+
+func TestOrgApplicationIdentities(t *testing.T) {
+	ctx := context.Background()
+	app := newTestOrgApplication()
+	app.Namespace = "ns1"
+	app.Message = fftypes.NewUUID()
+
+	org := app.OrgIdentity()
+	assert.Equal(t, "did:firefly:org/org2", org.DID)
+	assert.Equal(t, app.Org.ID, org.ID)
+	assert.Nil(t, org.Parent)
+	assert.Equal(t, app.Message, org.Messages.Claim)
+	assert.NoError(t, org.Validate(ctx))
+
+	node := app.NodeIdentity()
+	assert.Equal(t, "did:firefly:node/node2", node.DID)
+	assert.Equal(t, app.Org.ID, node.Parent)
+	assert.Equal(t, "peer2", node.Profile.GetString("id"))
+	assert.NoError(t, node.Validate(ctx))
+}
+
+func TestOrgApplicationTopic(t *testing.T) {
+	app := newTestOrgApplication()
+	msgID := fftypes.NewUUID()
+	app.SetBroadcastMessage(msgID)
+	assert.Equal(t, msgID, app.Message)
+
+	review := &OrgApplicationReview{Application: app.ID}
+	review.SetBroadcastMessage(fftypes.NewUUID())
+	assert.Equal(t, app.Topic(), review.Topic())
+	assert.NotEqual(t, newTestOrgApplication().Topic(), review.Topic())
+}
+
+func TestOrgApplicationIdentityDatabaseSerialization(t *testing.T) {
+	id1 := OrgApplicationIdentity{
+		ID:   fftypes.MustParseUUID("4a5ee1e2-3b5c-4a4d-9e0c-6a5ee1e23b5c"),
+		Name: "org2",
+	}
+
+	// Verify it serializes as bytes to the database
+	val1, err := id1.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"4a5ee1e2-3b5c-4a4d-9e0c-6a5ee1e23b5c","name":"org2"}`, string(val1.([]byte)))
+
+	// Verify it restores ok
+	var id2 OrgApplicationIdentity
+	err = id2.Scan(val1)
+	assert.NoError(t, err)
+	assert.Equal(t, id1, id2)
+
+	// Verify it ignores a blank string and nil
+	err = id2.Scan("")
+	assert.NoError(t, err)
+	err = id2.Scan(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "org2", id2.Name)
+
+	// Out of luck with anything else
+	err = id2.Scan(false)
+	assert.Regexp(t, "FF00105", err)
+}
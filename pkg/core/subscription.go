@@ -26,7 +26,8 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 )
 
-// SubscriptionFilter contains regular expressions to match against events. All must match for an event to be dispatched to a subscription
+// SubscriptionFilter contains regular expressions to match against events, and an optional filter expression
+// evaluated against the enriched event. All must match for an event to be dispatched to a subscription
 type SubscriptionFilter struct {
 	Events           string                `ffstruct:"SubscriptionFilter" json:"events,omitempty"`
 	Message          MessageFilter         `ffstruct:"SubscriptionFilter" json:"message,omitempty"`
@@ -37,6 +38,7 @@ type SubscriptionFilter struct {
 	DeprecatedTag    string                `ffstruct:"SubscriptionFilter" json:"tag,omitempty"`
 	DeprecatedGroup  string                `ffstruct:"SubscriptionFilter" json:"group,omitempty"`
 	DeprecatedAuthor string                `ffstruct:"SubscriptionFilter" json:"author,omitempty"`
+	Expression       string                `ffstruct:"SubscriptionFilter" json:"expression,omitempty"`
 }
 
 func NewSubscriptionFilterFromQuery(query url.Values) SubscriptionFilter {
@@ -59,6 +61,7 @@ func NewSubscriptionFilterFromQuery(query url.Values) SubscriptionFilter {
 		DeprecatedTopics: query.Get("filter.topics"),
 		DeprecatedGroup:  query.Get("filter.group"),
 		DeprecatedAuthor: query.Get("filter.author"),
+		Expression:       query.Get("filter.expression"),
 	}
 }
 
@@ -89,15 +92,24 @@ const (
 
 // SubscriptionCoreOptions are the core options that apply across all transports
 type SubscriptionCoreOptions struct {
-	FirstEvent *SubOptsFirstEvent `ffstruct:"SubscriptionCoreOptions" json:"firstEvent,omitempty"`
-	ReadAhead  *uint16            `ffstruct:"SubscriptionCoreOptions" json:"readAhead,omitempty"`
-	WithData   *bool              `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
+	FirstEvent *SubOptsFirstEvent        `ffstruct:"SubscriptionCoreOptions" json:"firstEvent,omitempty"`
+	ReadAhead  *uint16                   `ffstruct:"SubscriptionCoreOptions" json:"readAhead,omitempty"`
+	WithData   *bool                     `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
+	Batch      *SubscriptionBatchOptions `ffstruct:"SubscriptionCoreOptions" json:"batch,omitempty"`
+}
+
+// SubscriptionBatchOptions enable delivery of events in batches, on transports that support it.
+// A batch is delivered when it reaches the maximum size, or when the timeout expires after the first event arrives.
+type SubscriptionBatchOptions struct {
+	Size    *uint16 `ffstruct:"SubscriptionBatchOptions" json:"size,omitempty"`
+	Timeout *string `ffstruct:"SubscriptionBatchOptions" json:"timeout,omitempty"`
 }
 
 // SubscriptionOptions customize the behavior of subscriptions
 type SubscriptionOptions struct {
 	SubscriptionCoreOptions
 	WebhookSubOptions
+	KafkaSubOptions
 
 	// Extensible by the specific transport - so we serialize/de-serialize via map.
 	additionalOptions fftypes.JSONObject
@@ -143,6 +155,7 @@ func (so *SubscriptionOptions) UnmarshalJSON(b []byte) error {
 	delete(so.additionalOptions, "firstEvent")
 	delete(so.additionalOptions, "readAhead")
 	delete(so.additionalOptions, "withData")
+	delete(so.additionalOptions, "batch")
 	return nil
 }
 
@@ -159,6 +172,9 @@ func (so SubscriptionOptions) MarshalJSON() ([]byte, error) {
 	if so.ReadAhead != nil {
 		so.additionalOptions["readAhead"] = float64(*so.ReadAhead)
 	}
+	if so.Batch != nil {
+		so.additionalOptions["batch"] = so.Batch
+	}
 	return json.Marshal(&so.additionalOptions)
 }
 
@@ -94,6 +94,30 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	assert.Regexp(t, "FF00105", err)
 }
 
+func TestSubscriptionOptionsBatchSerialization(t *testing.T) {
+	size := uint16(10)
+	timeout := "100ms"
+	opts := SubscriptionOptions{
+		SubscriptionCoreOptions: SubscriptionCoreOptions{
+			Batch: &SubscriptionBatchOptions{
+				Size:    &size,
+				Timeout: &timeout,
+			},
+		},
+	}
+
+	b, err := opts.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"batch":{"size":10,"timeout":"100ms"}}`, string(b.([]byte)))
+
+	var opts2 SubscriptionOptions
+	err = opts2.Scan(b)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(10), *opts2.Batch.Size)
+	assert.Equal(t, "100ms", *opts2.Batch.Timeout)
+	assert.Nil(t, opts2.TransportOptions()["batch"])
+}
+
 func TestSubscriptionUnMarshalFail(t *testing.T) {
 
 	b, err := json.Marshal(&SubscriptionOptions{})
@@ -127,3 +151,10 @@ func TestNewSubscriptionFilterFromQuery(t *testing.T) {
 	assert.Equal(t, expectedFilter, filter)
 
 }
+
+func TestNewSubscriptionFilterFromQueryExpression(t *testing.T) {
+	query := url.Values{}
+	query.Set("filter.expression", "tokenTransfer.amount > 1000")
+	filter := NewSubscriptionFilterFromQuery(query)
+	assert.Equal(t, "tokenTransfer.amount > 1000", filter.Expression)
+}
@@ -45,3 +45,38 @@ type TokenAccount struct {
 type TokenAccountPool struct {
 	Pool *fftypes.UUID `ffstruct:"TokenBalance" json:"pool,omitempty"`
 }
+
+// TokenBalanceChange is an entry in the history of token balances, recording the balance of an
+// account after each confirmed transfer that changed it
+type TokenBalanceChange struct {
+	Sequence                int64            `ffstruct:"TokenBalanceChange" json:"sequence"`
+	Pool                    *fftypes.UUID    `ffstruct:"TokenBalanceChange" json:"pool,omitempty"`
+	TokenIndex              string           `ffstruct:"TokenBalanceChange" json:"tokenIndex,omitempty"`
+	URI                     string           `ffstruct:"TokenBalanceChange" json:"uri,omitempty"`
+	Connector               string           `ffstruct:"TokenBalanceChange" json:"connector,omitempty"`
+	Namespace               string           `ffstruct:"TokenBalanceChange" json:"namespace,omitempty"`
+	Key                     string           `ffstruct:"TokenBalanceChange" json:"key,omitempty"`
+	Amount                  fftypes.FFBigInt `ffstruct:"TokenBalanceChange" json:"amount"`
+	Balance                 fftypes.FFBigInt `ffstruct:"TokenBalanceChange" json:"balance"`
+	Transfer                *fftypes.UUID    `ffstruct:"TokenBalanceChange" json:"transfer,omitempty"`
+	BlockchainEvent         *fftypes.UUID    `ffstruct:"TokenBalanceChange" json:"blockchainEvent,omitempty"`
+	BlockchainEventSequence int64            `ffstruct:"TokenBalanceChange" json:"blockchainEventSequence,omitempty"`
+	Timestamp               *fftypes.FFTime  `ffstruct:"TokenBalanceChange" json:"timestamp,omitempty"`
+	Created                 *fftypes.FFTime  `ffstruct:"TokenBalanceChange" json:"created,omitempty"`
+}
+
+// TokenBalancePoint is a point in the history of token balances. Balances are taken as of the last
+// change with a blockchain event timestamp at or before the timestamp, and/or with a blockchain event
+// sequence at or before the sequence. When neither is set, the point is the latest change.
+type TokenBalancePoint struct {
+	Timestamp *fftypes.FFTime `ffstruct:"TokenBalancePoint" json:"timestamp,omitempty"`
+	Sequence  int64           `ffstruct:"TokenBalancePoint" json:"sequence,omitempty"`
+}
+
+// TokenBalanceSnapshot is the set of non-zero balances in a token pool, at a point in history
+type TokenBalanceSnapshot struct {
+	Pool      *fftypes.UUID   `ffstruct:"TokenBalanceSnapshot" json:"pool,omitempty"`
+	Timestamp *fftypes.FFTime `ffstruct:"TokenBalanceSnapshot" json:"timestamp,omitempty"`
+	Sequence  int64           `ffstruct:"TokenBalanceSnapshot" json:"sequence,omitempty"`
+	Balances  []*TokenBalance `ffstruct:"TokenBalanceSnapshot" json:"balances"`
+}
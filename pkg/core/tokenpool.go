@@ -64,6 +64,7 @@ type TokenPool struct {
 	Decimals        int                   `ffstruct:"TokenPool" json:"decimals,omitempty" ffexcludeinput:"true"`
 	Connector       string                `ffstruct:"TokenPool" json:"connector,omitempty"`
 	Message         *fftypes.UUID         `ffstruct:"TokenPool" json:"message,omitempty" ffexcludeinput:"true"`
+	Group           *fftypes.Bytes32      `ffstruct:"TokenPool" json:"group,omitempty" ffexcludeinput:"true"`
 	State           TokenPoolState        `ffstruct:"TokenPool" json:"state,omitempty" ffenum:"tokenpoolstate" ffexcludeinput:"true"`
 	Created         *fftypes.FFTime       `ffstruct:"TokenPool" json:"created,omitempty" ffexcludeinput:"true"`
 	Config          fftypes.JSONObject    `ffstruct:"TokenPool" json:"config,omitempty" ffexcludeoutput:"true"` // for REST calls only (not stored)
@@ -0,0 +1,52 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenReconcileInput is the input to reconcile the local token balances of a namespace against the token connectors
+type TokenReconcileInput struct {
+	Pool    string `ffstruct:"TokenReconcileInput" json:"pool,omitempty"`
+	Correct bool   `ffstruct:"TokenReconcileInput" json:"correct,omitempty"`
+}
+
+// TokenReconciliation is a run of token balance reconciliation, submitted as a transaction with a
+// token_reconcile operation for each pool. The operations run in the background, and record their
+// progress in their status and output.
+type TokenReconciliation struct {
+	ID      *fftypes.UUID   `ffstruct:"TokenReconciliation" json:"id"`
+	Pools   []*fftypes.UUID `ffstruct:"TokenReconciliation" json:"pools"`
+	Skipped []*fftypes.UUID `ffstruct:"TokenReconciliation" json:"skipped,omitempty"`
+	Correct bool            `ffstruct:"TokenReconciliation" json:"correct"`
+	Created *fftypes.FFTime `ffstruct:"TokenReconciliation" json:"created,omitempty"`
+}
+
+// TokenBalanceMismatch records a difference found during reconciliation, between the balance of an account
+// stored locally and the balance reported by the token connector
+type TokenBalanceMismatch struct {
+	ID             *fftypes.UUID    `ffstruct:"TokenBalanceMismatch" json:"id"`
+	Reconciliation *fftypes.UUID    `ffstruct:"TokenBalanceMismatch" json:"reconciliation"`
+	Namespace      string           `ffstruct:"TokenBalanceMismatch" json:"namespace,omitempty"`
+	Pool           *fftypes.UUID    `ffstruct:"TokenBalanceMismatch" json:"pool,omitempty"`
+	TokenIndex     string           `ffstruct:"TokenBalanceMismatch" json:"tokenIndex,omitempty"`
+	Connector      string           `ffstruct:"TokenBalanceMismatch" json:"connector,omitempty"`
+	Key            string           `ffstruct:"TokenBalanceMismatch" json:"key,omitempty"`
+	LocalBalance   fftypes.FFBigInt `ffstruct:"TokenBalanceMismatch" json:"localBalance"`
+	ChainBalance   fftypes.FFBigInt `ffstruct:"TokenBalanceMismatch" json:"chainBalance"`
+	Corrected      bool             `ffstruct:"TokenBalanceMismatch" json:"corrected"`
+	Created        *fftypes.FFTime  `ffstruct:"TokenBalanceMismatch" json:"created,omitempty"`
+}
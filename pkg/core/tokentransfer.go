@@ -53,3 +53,28 @@ type TokenTransferInput struct {
 	Pool           string         `ffstruct:"TokenTransferInput" json:"pool,omitempty"`
 	IdempotencyKey IdempotencyKey `ffstruct:"TokenTransferInput" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
 }
+
+// TokenTransferBatchInput is a set of token transfers to be submitted together, in a single FireFly transaction
+type TokenTransferBatchInput struct {
+	Items          []*TokenTransferBatchInputItem `ffstruct:"TokenTransferBatchInput" json:"items"`
+	IdempotencyKey IdempotencyKey                 `ffstruct:"TokenTransferBatchInput" json:"idempotencyKey,omitempty"`
+}
+
+type TokenTransferBatchInputItem struct {
+	TokenTransfer
+	Pool string `ffstruct:"TokenTransferBatchInputItem" json:"pool,omitempty"`
+}
+
+// TokenTransferBatch is the set of token transfers submitted in a single FireFly transaction, with the status of each
+type TokenTransferBatch struct {
+	TX    TransactionRef            `ffstruct:"TokenTransferBatch" json:"tx"`
+	Items []*TokenTransferBatchItem `ffstruct:"TokenTransferBatch" json:"items"`
+}
+
+type TokenTransferBatchItem struct {
+	Transfer  *TokenTransfer `ffstruct:"TokenTransferBatchItem" json:"transfer"`
+	Operation *fftypes.UUID  `ffstruct:"TokenTransferBatchItem" json:"operation,omitempty"`
+	Status    OpStatus       `ffstruct:"TokenTransferBatchItem" json:"status,omitempty"`
+	Error     string         `ffstruct:"TokenTransferBatchItem" json:"error,omitempty"`
+	Confirmed bool           `ffstruct:"TokenTransferBatchItem" json:"confirmed"`
+}
@@ -45,6 +45,8 @@ var (
 	TransactionTypeTokenApproval = fftypes.FFEnumValue("txtype", "token_approval")
 	// TransactionTypeDataPublish represents a publish to shared storage
 	TransactionTypeDataPublish = fftypes.FFEnumValue("txtype", "data_publish")
+	// TransactionTypeTokenReconcile represents a reconciliation of local token balances against the token connectors
+	TransactionTypeTokenReconcile = fftypes.FFEnumValue("txtype", "token_reconcile")
 )
 
 // TransactionRef refers to a transaction, in other types
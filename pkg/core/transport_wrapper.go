@@ -27,6 +27,29 @@ var (
 
 // TransportWrapper wraps paylaods over data exchange transfers, for easy deserialization at target
 type TransportWrapper struct {
-	Group *Group `json:"group,omitempty"`
-	Batch *Batch `json:"batch,omitempty"`
+	Group     *Group              `json:"group,omitempty"`
+	Batch     *Batch              `json:"batch,omitempty"`
+	Encrypted *EncryptedTransport `json:"encrypted,omitempty"`
+}
+
+// EncryptedTransport is a TransportWrapper that has been encrypted end-to-end for a single recipient node.
+// Only the namespace is left in clear, so the data exchange can route the payload to the right namespace.
+type EncryptedTransport struct {
+	Namespace string `json:"namespace"`
+	Algorithm string `json:"algorithm"`
+	Recipient string `json:"recipient"`
+	Payload   []byte `json:"payload"`
+}
+
+// Namespace returns the namespace the wrapper should be routed to at the target, or an empty
+// string if it carries neither a batch nor an encrypted payload
+func (tw *TransportWrapper) Namespace() string {
+	switch {
+	case tw.Encrypted != nil:
+		return tw.Encrypted.Namespace
+	case tw.Batch != nil:
+		return tw.Batch.Namespace
+	default:
+		return ""
+	}
 }
@@ -32,6 +32,8 @@ var (
 	VerifierTypeMSPIdentity = fftypes.FFEnumValue("verifiertype", "fabric_msp_id")
 	// VerifierTypeFFDXPeerID is the peer identifier that FireFly Data Exchange verifies (using plugin specific tech) when receiving data
 	VerifierTypeFFDXPeerID = fftypes.FFEnumValue("verifiertype", "dx_peer_id")
+	// VerifierTypeX25519PublicKey is the base64 encoded X25519 public key a node uses to receive end-to-end encrypted private messages
+	VerifierTypeX25519PublicKey = fftypes.FFEnumValue("verifiertype", "x25519_public_key")
 )
 
 // VerifierRef is just the type + value (public key identifier etc.) from the verifier
@@ -46,7 +48,9 @@ type Verifier struct {
 	Identity  *fftypes.UUID    `ffstruct:"Verifier" json:"identity,omitempty"`
 	Namespace string           `ffstruct:"Verifier" json:"namespace,omitempty"`
 	VerifierRef
-	Created *fftypes.FFTime `ffstruct:"Verifier" json:"created,omitempty"`
+	Created         *fftypes.FFTime `ffstruct:"Verifier" json:"created,omitempty"`
+	Revoked         *fftypes.FFTime `ffstruct:"Verifier" json:"revoked,omitempty"`
+	RevokedSequence int64           `ffstruct:"Verifier" json:"revokedSequence,omitempty"`
 }
 
 // Seal updates the hash to be deterministically generated from the namespace+type+value, such that
@@ -17,16 +17,19 @@
 package core
 
 type WebhookSubOptions struct {
-	Fastack  bool                `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
-	URL      string              `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
-	Method   string              `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
-	JSON     bool                `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
-	Reply    bool                `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
-	ReplyTag string              `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
-	ReplyTX  string              `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
-	Headers  map[string]string   `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
-	Query    map[string]string   `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
-	Input    WebhookInputOptions `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
+	Fastack       bool                  `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
+	URL           string                `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
+	Method        string                `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
+	JSON          bool                  `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
+	Reply         bool                  `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
+	ReplyTag      string                `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
+	ReplyTX       string                `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
+	Headers       map[string]string     `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
+	Query         map[string]string     `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
+	Input         WebhookInputOptions   `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
+	Retry         WebhookRetryOptions   `ffstruct:"WebhookSubOptions" json:"retry,omitempty"`
+	Signing       WebhookSigningOptions `ffstruct:"WebhookSubOptions" json:"signing,omitempty"`
+	TLSConfigName string                `ffstruct:"WebhookSubOptions" json:"tlsConfigName,omitempty"`
 }
 
 type WebhookInputOptions struct {
@@ -36,3 +39,18 @@ type WebhookInputOptions struct {
 	Path    string `ffstruct:"WebhookInputOptions" json:"path,omitempty"`
 	ReplyTX string `ffstruct:"WebhookInputOptions" json:"replytx,omitempty"`
 }
+
+type WebhookRetryOptions struct {
+	Enabled      bool    `ffstruct:"WebhookRetryOptions" json:"enabled,omitempty"`
+	MaxAttempts  int     `ffstruct:"WebhookRetryOptions" json:"maxAttempts,omitempty"`
+	InitialDelay string  `ffstruct:"WebhookRetryOptions" json:"initialDelay,omitempty"`
+	MaximumDelay string  `ffstruct:"WebhookRetryOptions" json:"maximumDelay,omitempty"`
+	Factor       float64 `ffstruct:"WebhookRetryOptions" json:"factor,omitempty"`
+	StatusCodes  []int   `ffstruct:"WebhookRetryOptions" json:"statusCodes,omitempty"`
+}
+
+type WebhookSigningOptions struct {
+	SecretName string `ffstruct:"WebhookSigningOptions" json:"secretName,omitempty"`
+	Header     string `ffstruct:"WebhookSigningOptions" json:"header,omitempty"`
+	Algorithm  string `ffstruct:"WebhookSigningOptions" json:"algorithm,omitempty"`
+}
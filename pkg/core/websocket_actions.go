@@ -29,6 +29,9 @@ var (
 
 	// WSProtocolErrorEventType is a special event "type" field for server to send the client, if it performs a ProtocolError
 	WSProtocolErrorEventType = fftypes.FFEnumValue("wstype", "protocol_error")
+
+	// WSEventBatchType is the "type" field of a batch of events sent to the client, on a subscription with batch delivery enabled
+	WSEventBatchType = fftypes.FFEnumValue("wstype", "event_batch")
 )
 
 // WSActionBase is the base fields of all client actions sent on the websocket
@@ -61,3 +64,12 @@ type WSError struct {
 	Type  WSClientPayloadType `ffstruct:"WSAck" json:"type" ffenum:"wstype"`
 	Error string              `ffstruct:"WSAck" json:"error"`
 }
+
+// WSEventBatch is a batch of events sent to the client in a single message, on a subscription with batch delivery enabled.
+// The client acknowledges the whole batch with a single ack, using the ID of the batch
+type WSEventBatch struct {
+	Type         WSClientPayloadType `ffstruct:"WSEventBatch" json:"type" ffenum:"wstype"`
+	ID           *fftypes.UUID       `ffstruct:"WSEventBatch" json:"id"`
+	Subscription SubscriptionRef     `ffstruct:"WSEventBatch" json:"subscription"`
+	Events       []*EventDelivery    `ffstruct:"WSEventBatch" json:"events"`
+}
@@ -18,6 +18,9 @@ package database
 
 import (
 	"context"
+	"database/sql/driver"
+	"strconv"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -97,7 +100,9 @@ type iMessageCollection interface {
 	// GetMessageByID - Get a message by ID
 	GetMessageByID(ctx context.Context, namespace string, id *fftypes.UUID) (message *core.Message, err error)
 
-	// GetMessages - List messages, reverse sorted (newest first) by Confirmed then Created, with pagination, and simple must filters
+	// GetMessages - List messages, reverse sorted (newest first) by Confirmed then Created, with pagination, and simple must filters.
+	// Filters on paths within data values (see WithDataValueFields) match if any data item of the message matches. Sorting on such a path
+	// uses the first data item of each message containing that path
 	GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, res *ffapi.FilterResult, err error)
 
 	// GetMessageIDs - Retrieves messages, but only querying the messages ID (no other fields)
@@ -111,6 +116,9 @@ type iMessageCollection interface {
 
 	// GetBatchIDsForDataAttachments - an optimized query to retrieve any non-null batch IDs for a list of data IDs that might be attached to messages in batches
 	GetBatchIDsForDataAttachments(ctx context.Context, namespace string, dataIDs []*fftypes.UUID) (batchIDs []*fftypes.UUID, err error)
+
+	// DeleteMessage - Deletes a message, and its references to data, by ID
+	DeleteMessage(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
 
 type iDataCollection interface {
@@ -128,7 +136,7 @@ type iDataCollection interface {
 	// GetDataByID - Get a data record by ID
 	GetDataByID(ctx context.Context, namespace string, id *fftypes.UUID, withValue bool) (message *core.Data, err error)
 
-	// GetData - Get data
+	// GetData - Get data, including filtering and sorting on paths within the JSON value (see WithDataValueFields)
 	GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error)
 
 	// GetDataSubPaths - returns unique paths that have files in them, under the specified path.
@@ -154,6 +162,9 @@ type iBatchCollection interface {
 
 	// GetBatches - Get batches
 	GetBatches(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.BatchPersisted, res *ffapi.FilterResult, err error)
+
+	// DeleteBatch - Deletes a batch by ID
+	DeleteBatch(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
 
 type iTransactionCollection interface {
@@ -177,8 +188,8 @@ type iDatatypeCollection interface {
 	// GetDatatypeByID - Get a data definition by ID
 	GetDatatypeByID(ctx context.Context, namespace string, id *fftypes.UUID) (datadef *core.Datatype, err error)
 
-	// GetDatatypeByName - Get a data definition by name
-	GetDatatypeByName(ctx context.Context, namespace, name, version string) (datadef *core.Datatype, err error)
+	// GetDatatypeByName - Get a data definition by name and version, preferring one shared with the group (if any) over one published to the whole network
+	GetDatatypeByName(ctx context.Context, namespace, name, version string, group *fftypes.Bytes32) (datadef *core.Datatype, err error)
 
 	// GetDatatypes - Get data definitions
 	GetDatatypes(ctx context.Context, namespace string, filter ffapi.Filter) (datadef []*core.Datatype, res *ffapi.FilterResult, err error)
@@ -227,6 +238,9 @@ type iOperationCollection interface {
 
 	// GetOperations - Get operation
 	GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) (operation []*core.Operation, res *ffapi.FilterResult, err error)
+
+	// DeleteOperations - Delete a list of operations by ID
+	DeleteOperations(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iSubscriptionCollection interface {
@@ -250,6 +264,62 @@ type iSubscriptionCollection interface {
 	DeleteSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
 
+type iDeadLetterCollection interface {
+	// InsertDeadLetter - Insert a dead letter for an event that could not be delivered on a subscription
+	InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (err error)
+
+	// UpdateDeadLetter - Update a dead letter
+	UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error)
+
+	// GetDeadLetterByID - Get a dead letter by ID
+	GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (deadLetter *core.DeadLetter, err error)
+
+	// GetDeadLetters - Get dead letters
+	GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) (deadLetters []*core.DeadLetter, res *ffapi.FilterResult, err error)
+
+	// DeleteDeadLetter - Delete a single dead letter
+	DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
+
+	// DeleteDeadLetters - Delete all dead letters for a subscription
+	DeleteDeadLetters(ctx context.Context, namespace string, subscriptionID *fftypes.UUID) (err error)
+}
+
+type iDefinitionProposalCollection interface {
+	// InsertDefinitionProposal - Insert a definition proposal that is awaiting a quorum of votes
+	InsertDefinitionProposal(ctx context.Context, proposal *core.DefinitionProposal) (err error)
+
+	// UpdateDefinitionProposal - Update a definition proposal
+	UpdateDefinitionProposal(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error)
+
+	// GetDefinitionProposalByID - Get a definition proposal by ID
+	GetDefinitionProposalByID(ctx context.Context, namespace string, id *fftypes.UUID) (proposal *core.DefinitionProposal, err error)
+
+	// GetDefinitionProposals - Get definition proposals
+	GetDefinitionProposals(ctx context.Context, namespace string, filter ffapi.Filter) (proposals []*core.DefinitionProposal, res *ffapi.FilterResult, err error)
+}
+
+type iNetworkGovernanceCollection interface {
+	// UpsertNetworkGovernance - Replace the governance rules of a namespace
+	UpsertNetworkGovernance(ctx context.Context, governance *core.NetworkGovernance) (err error)
+
+	// GetNetworkGovernance - Get the governance rules of a namespace, or nil if none have been confirmed
+	GetNetworkGovernance(ctx context.Context, namespace string) (governance *core.NetworkGovernance, err error)
+}
+
+type iOrgApplicationCollection interface {
+	// InsertOrgApplication - Insert an application for an org to join the network
+	InsertOrgApplication(ctx context.Context, application *core.OrgApplication) (err error)
+
+	// UpdateOrgApplication - Update an org application
+	UpdateOrgApplication(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error)
+
+	// GetOrgApplicationByID - Get an org application by ID
+	GetOrgApplicationByID(ctx context.Context, namespace string, id *fftypes.UUID) (application *core.OrgApplication, err error)
+
+	// GetOrgApplications - Get org applications
+	GetOrgApplications(ctx context.Context, namespace string, filter ffapi.Filter) (applications []*core.OrgApplication, res *ffapi.FilterResult, err error)
+}
+
 type iEventCollection interface {
 	// InsertEvent - Insert an event. The order of the sequences added to the database, must match the order that
 	//               the rows/objects appear available to the event dispatcher. For a concurrency enabled database
@@ -262,6 +332,9 @@ type iEventCollection interface {
 
 	// GetEvents - Get events
 	GetEvents(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Event, res *ffapi.FilterResult, err error)
+
+	// DeleteEvents - Delete a list of events by ID
+	DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iIdentitiesCollection interface {
@@ -351,9 +424,26 @@ type iBlobCollection interface {
 	DeleteBlob(ctx context.Context, sequence int64) (err error)
 }
 
+type iBlobUploadCollection interface {
+	// InsertBlobUpload - insert a resumable blob upload
+	InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) (err error)
+
+	// UpdateBlobUpload - update a resumable blob upload, if it matches the supplied filter
+	UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error)
+
+	// GetBlobUploadByID - get a resumable blob upload by ID
+	GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (upload *core.BlobUpload, err error)
+
+	// GetBlobUploads - get resumable blob uploads
+	GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) (uploads []*core.BlobUpload, res *ffapi.FilterResult, err error)
+
+	// DeleteBlobUpload - delete a resumable blob upload
+	DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
+}
+
 type iTokenPoolCollection interface {
 	// InsertTokenPool - Insert a new token pool
-	// If a pool with the same name has already been recorded, does not insert but returns the existing row
+	// If a pool with the same name, or the same network name in the same group, has already been recorded, does not insert but returns the existing row
 	InsertOrGetTokenPool(ctx context.Context, pool *core.TokenPool) (existing *core.TokenPool, err error)
 
 	// UpsertTokenPool - Upsert a token pool
@@ -365,8 +455,8 @@ type iTokenPoolCollection interface {
 	// GetTokenPoolByID - Get a token pool by pool ID
 	GetTokenPoolByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPool, error)
 
-	// GetTokenPoolByNetworkName - Get a token pool by network name
-	GetTokenPoolByNetworkName(ctx context.Context, namespace, networkName string) (*core.TokenPool, error)
+	// GetTokenPoolByNetworkName - Get a token pool by network name, within a group (or network-wide if group is nil)
+	GetTokenPoolByNetworkName(ctx context.Context, namespace, networkName string, group *fftypes.Bytes32) (*core.TokenPool, error)
 
 	// GetTokenPools - Get token pools
 	GetTokenPools(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenPool, *ffapi.FilterResult, error)
@@ -376,8 +466,9 @@ type iTokenPoolCollection interface {
 }
 
 type iTokenBalanceCollection interface {
-	// UpdateTokenBalances - Move some token balance from one account to another
-	UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer) error
+	// UpdateTokenBalances - Move some token balance from one account to another, recording the change in the
+	// balance history at the given timestamp of the blockchain event
+	UpdateTokenBalances(ctx context.Context, transfer *core.TokenTransfer, timestamp *fftypes.FFTime) error
 
 	// GetTokenBalance - Get a token balance by pool and account identity
 	GetTokenBalance(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex, identity string) (*core.TokenBalance, error)
@@ -391,10 +482,27 @@ type iTokenBalanceCollection interface {
 	// GetTokenAccountPools - Get the list of pools referenced by a given account
 	GetTokenAccountPools(ctx context.Context, namespace, key string, filter ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)
 
-	// DeleteTokenBalances - Delete token balances from a particular pool
+	// GetTokenBalanceHistory - Get the changes to token balances
+	GetTokenBalanceHistory(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceChange, *ffapi.FilterResult, error)
+
+	// GetTokenBalancesAt - Get token balances as they were at a point in the balance history
+	GetTokenBalancesAt(ctx context.Context, namespace string, point *core.TokenBalancePoint, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
+
+	// DeleteTokenBalances - Delete token balances, and their history, from a particular pool
 	DeleteTokenBalances(ctx context.Context, namespace string, poolID *fftypes.UUID) error
 }
 
+type iTokenBalanceMismatchCollection interface {
+	// InsertTokenBalanceMismatch - Record a mismatch found while reconciling token balances
+	InsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) error
+
+	// GetTokenBalanceMismatchByID - Get a token balance mismatch by ID
+	GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error)
+
+	// GetTokenBalanceMismatches - Get token balance mismatches
+	GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)
+}
+
 type iTokenTransferCollection interface {
 	// UpsertTokenTransfer - Upsert a token transfer
 	UpsertTokenTransfer(ctx context.Context, transfer *core.TokenTransfer) error
@@ -410,6 +518,9 @@ type iTokenTransferCollection interface {
 
 	// DeleteTokenTransfers - Delete token transfers from a particular pool
 	DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error
+
+	// DeleteTokenTransfersByID - Delete a list of token transfers by local ID
+	DeleteTokenTransfersByID(ctx context.Context, namespace string, localIDs []*fftypes.UUID) error
 }
 
 type iTokenApprovalCollection interface {
@@ -433,12 +544,12 @@ type iTokenApprovalCollection interface {
 }
 
 type iFFICollection interface {
-	// InsertOrGetFFI - Insert an FFI
-	// If an FFI with the same name has already been recorded, does not insert but returns the existing row
-	InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI) (*fftypes.FFI, error)
+	// InsertOrGetFFI - Insert an FFI, shared with a group (or network-wide if group is nil)
+	// If an FFI with the same name, or the same network name in the same group, has already been recorded, does not insert but returns the existing row
+	InsertOrGetFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32) (*fftypes.FFI, error)
 
-	// UpsertFFI - Upsert an FFI
-	UpsertFFI(ctx context.Context, ffi *fftypes.FFI, optimization UpsertOptimization) error
+	// UpsertFFI - Upsert an FFI, shared with a group (or network-wide if group is nil)
+	UpsertFFI(ctx context.Context, ffi *fftypes.FFI, group *fftypes.Bytes32, optimization UpsertOptimization) error
 
 	// GetFFIs - Get FFIs
 	GetFFIs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*fftypes.FFI, *ffapi.FilterResult, error)
@@ -449,8 +560,8 @@ type iFFICollection interface {
 	// GetFFI - Get an FFI by name and version
 	GetFFI(ctx context.Context, namespace, name, version string) (*fftypes.FFI, error)
 
-	// GetFFIByNetworkName - Get an FFI by network name and version
-	GetFFIByNetworkName(ctx context.Context, namespace, networkName, version string) (*fftypes.FFI, error)
+	// GetFFIByNetworkName - Get an FFI by network name and version, within a group (or network-wide if group is nil)
+	GetFFIByNetworkName(ctx context.Context, namespace, networkName, version string, group *fftypes.Bytes32) (*fftypes.FFI, error)
 
 	// DeleteFFI - Delete an FFI
 	DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error
@@ -488,7 +599,7 @@ type iFFIErrorCollection interface {
 
 type iContractAPICollection interface {
 	// InsertOrGetContractAPI - Insert a contract API
-	// If an API with the same name has already been recorded, does not insert but returns the existing row
+	// If an API with the same name, or the same network name in the same group, has already been recorded, does not insert but returns the existing row
 	InsertOrGetContractAPI(ctx context.Context, api *core.ContractAPI) (*core.ContractAPI, error)
 
 	// UpsertFFIEvent - Upsert a contract API
@@ -503,8 +614,8 @@ type iContractAPICollection interface {
 	// GetContractAPIByName - Get a contract API by name
 	GetContractAPIByName(ctx context.Context, namespace, name string) (*core.ContractAPI, error)
 
-	// GetContractAPIByNetworkName - Get a contract API by network name
-	GetContractAPIByNetworkName(ctx context.Context, namespace, networkName string) (*core.ContractAPI, error)
+	// GetContractAPIByNetworkName - Get a contract API by network name, within a group (or network-wide if group is nil)
+	GetContractAPIByNetworkName(ctx context.Context, namespace, networkName string, group *fftypes.Bytes32) (*core.ContractAPI, error)
 
 	// DeleteContractAPI - Delete a contract API
 	DeleteContractAPI(ctx context.Context, namespace string, id *fftypes.UUID) error
@@ -546,6 +657,9 @@ type iBlockchainEventCollection interface {
 
 	// GetBlockchainEvents - get blockchain events
 	GetBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+
+	// DeleteBlockchainEvents - Delete a list of blockchain events by ID
+	DeleteBlockchainEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) error
 }
 
 // PersistenceInterface are the operations that must be implemented by a database interface plugin.
@@ -598,6 +712,10 @@ type PersistenceInterface interface {
 	iPinCollection
 	iOperationCollection
 	iSubscriptionCollection
+	iDeadLetterCollection
+	iDefinitionProposalCollection
+	iNetworkGovernanceCollection
+	iOrgApplicationCollection
 	iEventCollection
 	iIdentitiesCollection
 	iVerifiersCollection
@@ -605,8 +723,10 @@ type PersistenceInterface interface {
 	iNonceCollection
 	iNextPinCollection
 	iBlobCollection
+	iBlobUploadCollection
 	iTokenPoolCollection
 	iTokenBalanceCollection
+	iTokenBalanceMismatchCollection
 	iTokenTransferCollection
 	iTokenApprovalCollection
 	iFFICollection
@@ -646,23 +766,27 @@ const (
 type UUIDCollectionNS CollectionName
 
 const (
-	CollectionBatches           UUIDCollectionNS = "batches"
-	CollectionBlockchainEvents  UUIDCollectionNS = "blockchainevents"
-	CollectionData              UUIDCollectionNS = "data"
-	CollectionDataTypes         UUIDCollectionNS = "datatypes"
-	CollectionOperations        UUIDCollectionNS = "operations"
-	CollectionSubscriptions     UUIDCollectionNS = "subscriptions"
-	CollectionTransactions      UUIDCollectionNS = "transactions"
-	CollectionTokenPools        UUIDCollectionNS = "tokenpools"
-	CollectionTokenTransfers    UUIDCollectionNS = "tokentransfers"
-	CollectionTokenApprovals    UUIDCollectionNS = "tokenapprovals"
-	CollectionFFIs              UUIDCollectionNS = "ffi"
-	CollectionFFIMethods        UUIDCollectionNS = "ffimethods"
-	CollectionFFIEvents         UUIDCollectionNS = "ffievents"
-	CollectionFFIErrors         UUIDCollectionNS = "ffierrors"
-	CollectionContractAPIs      UUIDCollectionNS = "contractapis"
-	CollectionContractListeners UUIDCollectionNS = "contractlisteners"
-	CollectionIdentities        UUIDCollectionNS = "identities"
+	CollectionBatches             UUIDCollectionNS = "batches"
+	CollectionBlockchainEvents    UUIDCollectionNS = "blockchainevents"
+	CollectionData                UUIDCollectionNS = "data"
+	CollectionDataTypes           UUIDCollectionNS = "datatypes"
+	CollectionOperations          UUIDCollectionNS = "operations"
+	CollectionSubscriptions       UUIDCollectionNS = "subscriptions"
+	CollectionTransactions        UUIDCollectionNS = "transactions"
+	CollectionTokenPools          UUIDCollectionNS = "tokenpools"
+	CollectionTokenTransfers      UUIDCollectionNS = "tokentransfers"
+	CollectionTokenApprovals      UUIDCollectionNS = "tokenapprovals"
+	CollectionFFIs                UUIDCollectionNS = "ffi"
+	CollectionFFIMethods          UUIDCollectionNS = "ffimethods"
+	CollectionFFIEvents           UUIDCollectionNS = "ffievents"
+	CollectionFFIErrors           UUIDCollectionNS = "ffierrors"
+	CollectionContractAPIs        UUIDCollectionNS = "contractapis"
+	CollectionContractListeners   UUIDCollectionNS = "contractlisteners"
+	CollectionDeadLetters         UUIDCollectionNS = "deadletters"
+	CollectionDefinitionProposals UUIDCollectionNS = "definitionproposals"
+	CollectionIdentities          UUIDCollectionNS = "identities"
+	CollectionOrgApplications     UUIDCollectionNS = "orgapplications"
+	CollectionTokenMismatches     UUIDCollectionNS = "tokenbalancemismatches"
 )
 
 // HashCollectionNS is a collection where the primary key is a hash, such that it can
@@ -683,6 +807,7 @@ type OtherCollection CollectionName
 
 const (
 	CollectionBlobs         OtherCollection = "blobs"
+	CollectionBlobUploads   OtherCollection = "blobuploads"
 	CollectionNextpins      OtherCollection = "nextpins"
 	CollectionNonces        OtherCollection = "nonces"
 	CollectionOffsets       OtherCollection = "offsets"
@@ -747,6 +872,9 @@ var MessageQueryFactory = &ffapi.QueryFields{
 	"txid":           &ffapi.UUIDField{},
 	"txparent.type":  &ffapi.StringField{},
 	"txparent.id":    &ffapi.UUIDField{},
+	"expires":        &ffapi.TimeField{},
+	"replydeadline":  &ffapi.TimeField{},
+	"encrypted":      &ffapi.BoolField{},
 }
 
 // BatchQueryFactory filter fields for batches
@@ -791,10 +919,65 @@ var DataQueryFactory = &ffapi.QueryFields{
 	"public":           &ffapi.StringField{},
 }
 
+// DataValueFieldPrefix is the prefix of filter fields that match a dot-separated JSON path within the value of data
+const DataValueFieldPrefix = "value."
+
+// DataValueNumberFieldPrefix is the prefix of filter fields that match a JSON number at a dot-separated path within
+// the value of data, which are compared numerically rather than as text. Values that are not numbers never match.
+const DataValueNumberFieldPrefix = "valuenum."
+
+// WithDataValueFields returns a copy of the supplied query fields, extended with fields for each of the supplied
+// dot-separated JSON paths within the value of data (such as "customer.id"). Filters built from the result can query
+// on fields such as "value.customer.id", which are compared as text against the scalar value at that path, and
+// "valuenum.customer.id", which are compared numerically.
+func WithDataValueFields(queryFields *ffapi.QueryFields, paths ...string) *ffapi.QueryFields {
+	extended := make(ffapi.QueryFields, len(*queryFields)+len(paths)*3)
+	for name, field := range *queryFields {
+		extended[name] = field
+	}
+	for _, path := range paths {
+		// Conditions are looked up in lower case, but sort fields are not
+		extended[strings.ToLower(DataValueFieldPrefix+path)] = &ffapi.StringField{}
+		extended[DataValueFieldPrefix+path] = &ffapi.StringField{}
+		extended[strings.ToLower(DataValueNumberFieldPrefix+path)] = &NumberField{}
+	}
+	return &extended
+}
+
+// NumberField is a filter field holding a decimal number
+type NumberField struct{}
+type numberField struct{ f float64 }
+
+func (f *numberField) Scan(src interface{}) (err error) {
+	switch tv := src.(type) {
+	case int:
+		f.f = float64(tv)
+	case int64:
+		f.f = float64(tv)
+	case float64:
+		f.f = tv
+	case string:
+		if f.f, err = strconv.ParseFloat(tv, 64); err != nil {
+			return i18n.WrapError(context.Background(), err, i18n.MsgTypeRestoreFailed, src, f.f)
+		}
+	case nil:
+		f.f = 0
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, f.f)
+	}
+	return nil
+}
+func (f *numberField) Value() (driver.Value, error)               { return f.f, nil }
+func (f *numberField) String() string                             { return strconv.FormatFloat(f.f, 'f', -1, 64) }
+func (f *NumberField) GetSerialization() ffapi.FieldSerialization { return &numberField{} }
+func (f *NumberField) FilterAsString() bool                       { return false }
+func (f *NumberField) Description() string                        { return "Number" }
+
 // DatatypeQueryFactory filter fields for data definitions
 var DatatypeQueryFactory = &ffapi.QueryFields{
 	"id":        &ffapi.UUIDField{},
 	"message":   &ffapi.UUIDField{},
+	"group":     &ffapi.Bytes32Field{},
 	"validator": &ffapi.StringField{},
 	"name":      &ffapi.StringField{},
 	"version":   &ffapi.StringField{},
@@ -834,6 +1017,47 @@ var SubscriptionQueryFactory = &ffapi.QueryFields{
 	"created":   &ffapi.TimeField{},
 }
 
+// DeadLetterQueryFactory filter fields for dead letters
+var DeadLetterQueryFactory = &ffapi.QueryFields{
+	"id":           &ffapi.UUIDField{},
+	"subscription": &ffapi.UUIDField{},
+	"event":        &ffapi.UUIDField{},
+	"attempts":     &ffapi.Int64Field{},
+	"error":        &ffapi.StringField{},
+	"created":      &ffapi.TimeField{},
+	"updated":      &ffapi.TimeField{},
+}
+
+// DefinitionProposalQueryFactory filter fields for definition proposals
+var DefinitionProposalQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"tag":     &ffapi.StringField{},
+	"topic":   &ffapi.StringField{},
+	"author":  &ffapi.StringField{},
+	"tx":      &ffapi.UUIDField{},
+	"state":   &ffapi.StringField{},
+	"quorum":  &ffapi.Int64Field{},
+	"votes":   &ffapi.JSONField{},
+	"error":   &ffapi.StringField{},
+	"created": &ffapi.TimeField{},
+	"updated": &ffapi.TimeField{},
+}
+
+// OrgApplicationQueryFactory filter fields for org applications
+var OrgApplicationQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"sponsor": &ffapi.StringField{},
+	"org":     &ffapi.JSONField{},
+	"node":    &ffapi.JSONField{},
+	"key":     &ffapi.StringField{},
+	"state":   &ffapi.StringField{},
+	"reason":  &ffapi.StringField{},
+	"message": &ffapi.UUIDField{},
+	"review":  &ffapi.UUIDField{},
+	"created": &ffapi.TimeField{},
+	"updated": &ffapi.TimeField{},
+}
+
 // EventQueryFactory filter fields for data events
 var EventQueryFactory = &ffapi.QueryFields{
 	"id":         &ffapi.UUIDField{},
@@ -865,21 +1089,25 @@ var IdentityQueryFactory = &ffapi.QueryFields{
 	"messages.claim":        &ffapi.UUIDField{},
 	"messages.verification": &ffapi.UUIDField{},
 	"messages.update":       &ffapi.UUIDField{},
+	"messages.revocation":   &ffapi.UUIDField{},
 	"type":                  &ffapi.StringField{},
 	"name":                  &ffapi.StringField{},
 	"description":           &ffapi.StringField{},
 	"profile":               &ffapi.JSONField{},
 	"created":               &ffapi.TimeField{},
 	"updated":               &ffapi.TimeField{},
+	"revoked":               &ffapi.TimeField{},
 }
 
 // VerifierQueryFactory filter fields for identities
 var VerifierQueryFactory = &ffapi.QueryFields{
-	"hash":     &ffapi.Bytes32Field{},
-	"identity": &ffapi.UUIDField{},
-	"type":     &ffapi.StringField{},
-	"value":    &ffapi.StringField{},
-	"created":  &ffapi.TimeField{},
+	"hash":            &ffapi.Bytes32Field{},
+	"identity":        &ffapi.UUIDField{},
+	"type":            &ffapi.StringField{},
+	"value":           &ffapi.StringField{},
+	"created":         &ffapi.TimeField{},
+	"revoked":         &ffapi.TimeField{},
+	"revokedsequence": &ffapi.Int64Field{},
 }
 
 // GroupQueryFactory filter fields for groups
@@ -914,6 +1142,22 @@ var BlobQueryFactory = &ffapi.QueryFields{
 	"data_id":    &ffapi.UUIDField{},
 }
 
+// BlobUploadQueryFactory filter fields for resumable blob uploads
+var BlobUploadQueryFactory = &ffapi.QueryFields{
+	"id":        &ffapi.UUIDField{},
+	"state":     &ffapi.StringField{},
+	"size":      &ffapi.Int64Field{},
+	"received":  &ffapi.Int64Field{},
+	"data":      &ffapi.UUIDField{},
+	"error":     &ffapi.StringField{},
+	"chunks":    &ffapi.JSONField{},
+	"hashstate": &ffapi.StringField{},
+	"finalize":  &ffapi.JSONField{},
+	"created":   &ffapi.TimeField{},
+	"updated":   &ffapi.TimeField{},
+	"expires":   &ffapi.TimeField{},
+}
+
 // TokenPoolQueryFactory filter fields for token pools
 var TokenPoolQueryFactory = &ffapi.QueryFields{
 	"id":              &ffapi.UUIDField{},
@@ -925,6 +1169,7 @@ var TokenPoolQueryFactory = &ffapi.QueryFields{
 	"symbol":          &ffapi.StringField{},
 	"decimals":        &ffapi.Int64Field{},
 	"message":         &ffapi.UUIDField{},
+	"group":           &ffapi.Bytes32Field{},
 	"state":           &ffapi.StringField{},
 	"created":         &ffapi.TimeField{},
 	"connector":       &ffapi.StringField{},
@@ -946,6 +1191,36 @@ var TokenBalanceQueryFactory = &ffapi.QueryFields{
 	"updated":    &ffapi.TimeField{},
 }
 
+// TokenBalanceChangeQueryFactory filter fields for the history of token balances
+var TokenBalanceChangeQueryFactory = &ffapi.QueryFields{
+	"sequence":        &ffapi.Int64Field{},
+	"pool":            &ffapi.UUIDField{},
+	"tokenindex":      &ffapi.StringField{},
+	"uri":             &ffapi.StringField{},
+	"connector":       &ffapi.StringField{},
+	"key":             &ffapi.StringField{},
+	"amount":          &ffapi.Int64Field{},
+	"balance":         &ffapi.Int64Field{},
+	"transfer":        &ffapi.UUIDField{},
+	"blockchainevent": &ffapi.UUIDField{},
+	"timestamp":       &ffapi.TimeField{},
+	"created":         &ffapi.TimeField{},
+}
+
+// TokenBalanceMismatchQueryFactory filter fields for token balance mismatches
+var TokenBalanceMismatchQueryFactory = &ffapi.QueryFields{
+	"id":             &ffapi.UUIDField{},
+	"reconciliation": &ffapi.UUIDField{},
+	"pool":           &ffapi.UUIDField{},
+	"tokenindex":     &ffapi.StringField{},
+	"connector":      &ffapi.StringField{},
+	"key":            &ffapi.StringField{},
+	"localbalance":   &ffapi.Int64Field{},
+	"chainbalance":   &ffapi.Int64Field{},
+	"corrected":      &ffapi.BoolField{},
+	"created":        &ffapi.TimeField{},
+}
+
 // TokenAccountQueryFactory filter fields for token accounts
 var TokenAccountQueryFactory = &ffapi.QueryFields{
 	"key":     &ffapi.StringField{},
@@ -1004,6 +1279,7 @@ var FFIQueryFactory = &ffapi.QueryFields{
 	"networkname": &ffapi.StringField{},
 	"version":     &ffapi.StringField{},
 	"published":   &ffapi.BoolField{},
+	"group":       &ffapi.Bytes32Field{},
 }
 
 // FFIMethodQueryFactory filter fields for contract methods
@@ -1067,4 +1343,5 @@ var ContractAPIQueryFactory = &ffapi.QueryFields{
 	"networkname": &ffapi.StringField{},
 	"interface":   &ffapi.UUIDField{},
 	"published":   &ffapi.BoolField{},
+	"group":       &ffapi.Bytes32Field{},
 }
@@ -0,0 +1,61 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDataValueFields(t *testing.T) {
+	fb := WithDataValueFields(DataQueryFactory, "customer.ID").NewFilter(context.Background())
+	f := fb.And(fb.Eq("value.customer.ID", "123")).Sort("value.customer.ID")
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( value.customer.ID == '123' ) sort=value.customer.ID", fi.String())
+
+	_, ok := (*DataQueryFactory)["value.customer.id"]
+	assert.False(t, ok)
+}
+
+func TestWithDataValueFieldsNumber(t *testing.T) {
+	fb := WithDataValueFields(DataQueryFactory, "total").NewFilter(context.Background())
+	f := fb.And(fb.Gt("valuenum.total", "9.5"), fb.Lte("valuenum.total", 100), fb.Eq("valuenum.total", nil))
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	v, err := fi.Children[0].Value.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 9.5, v)
+	v, err = fi.Children[1].Value.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), v)
+
+	_, err = fb.And(fb.Gt("valuenum.total", "bad")).Finalize()
+	assert.Regexp(t, "FF00105", err)
+	_, err = fb.And(fb.Gt("valuenum.total", true)).Finalize()
+	assert.Regexp(t, "FF00105", err)
+
+	var nf numberField
+	assert.NoError(t, nf.Scan(int64(5)))
+	assert.NoError(t, nf.Scan(float64(5)))
+	assert.NoError(t, nf.Scan(nil))
+	assert.Equal(t, "0", nf.String())
+	assert.Equal(t, "Number", (&NumberField{}).Description())
+	assert.False(t, (&NumberField{}).FilterAsString())
+}
@@ -18,6 +18,7 @@ package dataexchange
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
@@ -53,6 +54,8 @@ import (
 //   - When data is received from other members in the network, be able to return the hash when provided with the remote peerID string, namespace and ID
 //   - Could be done by having a data store to resolve the transfers, or simply a deterministic path to metadata like "receive/peerID/namespace/ID"
 //   - Events triggered for arrival of blobs must contain the payloadRef, and the hash
+//   - The namespace passed when uploading a blob may itself contain "/" separators, such as for a copy staged for
+//     a single transfer (see StagedBlobNamespace). The last two segments of the path identify the namespace and ID
 type Plugin interface {
 	core.Named
 
@@ -89,6 +92,9 @@ type Plugin interface {
 	// DownloadBlob streams a received blob out of storage
 	DownloadBlob(ctx context.Context, payloadRef string) (content io.ReadCloser, err error)
 
+	// DownloadBlobRange streams part of a blob out of storage, starting at offset, for length bytes - or to the end of the blob if length is negative
+	DownloadBlobRange(ctx context.Context, payloadRef string, offset, length int64) (content io.ReadCloser, err error)
+
 	// DeleteBlob streams a deletes a blob from the local DB and DX
 	DeleteBlob(ctx context.Context, payloadRef string) (err error)
 
@@ -140,6 +146,15 @@ type PrivateBlobReceived struct {
 	DataID     string
 }
 
+// StagedBlobPrefix is the first path segment of blobs staged for a single transfer
+const StagedBlobPrefix = "staged"
+
+// StagedBlobNamespace returns the namespace to upload a copy of a blob staged for a single transfer.
+// The payload reference still ends in "namespace/dataID", which is how the recipient identifies the data.
+func StagedBlobNamespace(ns string, transferID *fftypes.UUID) string {
+	return fmt.Sprintf("%s/%s/%s", StagedBlobPrefix, transferID, ns)
+}
+
 // Capabilities the supported featureset of the data exchange
 // interface implemented by the plugin, with the specified config
 type Capabilities struct {
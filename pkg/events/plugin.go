@@ -48,8 +48,14 @@ type Plugin interface {
 	ValidateOptions(options *core.SubscriptionOptions) error
 
 	// DeliveryRequest requests delivery of work on a connection, which must later be responded to
-	// Data will only be supplied as non-nil if the subscription is set to include data
-	DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error
+	// Data will only be supplied as non-nil if the subscription is set to include data.
+	// The context is that of the dispatcher, and is cancelled when the dispatcher closes, so any retry of the delivery must stop.
+	DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error
+
+	// BatchDeliveryRequest requests delivery of a batch of events on a connection, in a single request to the transport.
+	// Only called for subscriptions with batching enabled, on plugins that declare the BatchDelivery capability.
+	// Every event in the batch must later be responded to, although a transport might receive a single ack for the batch
+	BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error
 
 	// NamespaceRestarted is called after a namespace restarts. For a connect-in style plugin, like
 	// WebSockets, this must re-register any active connections that started before the time passed in.
@@ -81,6 +87,14 @@ type Callbacks interface {
 	// - Reject it: This resets the associated subscription back to the last committed offset
 	//   * Note all message since the last committed offet will be redelivered, so additional messages to be redelivered if streaming ahead
 	DeliveryResponse(connID string, inflight *core.EventDeliveryResponse)
+
+	// DeadLetter notifies that the plugin has given up delivering an event, after exhausting its retry policy.
+	// The event is persisted as a dead letter for the subscription, and acknowledged so the offset can move forwards.
+	// Can be fired in place of DeliveryResponse, or after it (when the plugin acknowledged the event before delivering).
+	DeadLetter(connID string, deadLetter *core.DeadLetter)
 }
 
-type Capabilities struct{}
+type Capabilities struct {
+	// BatchDelivery is set if the plugin supports BatchDeliveryRequest
+	BatchDelivery bool
+}
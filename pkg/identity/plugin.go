@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2023 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,12 +18,18 @@ package identity
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
-// Plugin is the interface implemented by each identity plugin
+// Plugin is the interface implemented by each identity plugin.
+//
+// Identity plugins resolve external DIDs (those that are not FireFly registered "did:firefly:" identities)
+// to their DID documents, so that the verification methods they contain can be used to authenticate
+// signers that have never been registered with FireFly.
 type Plugin interface {
 	core.Named
 
@@ -43,14 +49,9 @@ type Plugin interface {
 	// Capabilities returns capabilities - not called until after Init
 	Capabilities() *Capabilities
 
-	// INTERFACE IS TBD SINCE INTRODUCTION OF THE IDENTITY MANAGER [Im] COMPONENT
-	//
-	// There is a strong thought that a pluggable infrastructure for mapping external DID based identity
-	// solutions into FireFly is required. However, the immediate shift in Sep 2021 moved to defining
-	// a strong enough identity construct within FireFly to map from/to.
-	//
-	// See issue https://github.com/hyperledger/firefly/issues/187 to contribute to the discussion
-
+	// ResolveDID resolves a DID, using one of the DID methods listed in the capabilities of the plugin, to its DID document.
+	// Returns nil (without an error) if the DID does not exist
+	ResolveDID(ctx context.Context, did string) (*DIDDocument, error)
 }
 
 // Callbacks is the interface provided to the identity plugin, to allow it to request information from firefly, or pass events.
@@ -60,4 +61,48 @@ type Callbacks interface {
 // Capabilities the supported featureset of the identity
 // interface implemented by the plugin, with the specified config
 type Capabilities struct {
+	// DIDMethods are the DID methods the plugin can resolve, such as "key" for "did:key:..." DIDs
+	DIDMethods []string
+}
+
+// DIDDocument is the subset of a W3C DID document that FireFly uses to authenticate signers
+type DIDDocument struct {
+	Context            interface{}           `json:"@context,omitempty"`
+	ID                 string                `json:"id"`
+	Controller         interface{}           `json:"controller,omitempty"`
+	VerificationMethod []*VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication     []interface{}         `json:"authentication,omitempty"`
+	AssertionMethod    []interface{}         `json:"assertionMethod,omitempty"`
+}
+
+// VerificationMethod is a public key, or blockchain account, that can be used to authenticate as the DID subject
+type VerificationMethod struct {
+	ID                  string             `json:"id"`
+	Type                string             `json:"type"`
+	Controller          string             `json:"controller"`
+	PublicKeyMultibase  string             `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk        fftypes.JSONObject `json:"publicKeyJwk,omitempty"`
+	BlockchainAccountID string             `json:"blockchainAccountId,omitempty"`
+	EthereumAddress     string             `json:"ethereumAddress,omitempty"`
+}
+
+// Verifiers returns the FireFly verifiers for the verification methods in the document that can be
+// mapped to one - currently Ethereum addresses, from either an "ethereumAddress" or an "eip155"
+// CAIP-10 "blockchainAccountId"
+func (doc *DIDDocument) Verifiers() []*core.VerifierRef {
+	verifiers := []*core.VerifierRef{}
+	for _, vm := range doc.VerificationMethod {
+		address := vm.EthereumAddress
+		if address == "" && strings.HasPrefix(vm.BlockchainAccountID, "eip155:") {
+			parts := strings.Split(vm.BlockchainAccountID, ":")
+			address = parts[len(parts)-1]
+		}
+		if address != "" {
+			verifiers = append(verifiers, &core.VerifierRef{
+				Type:  core.VerifierTypeEthAddress,
+				Value: strings.ToLower(address),
+			})
+		}
+	}
+	return verifiers
 }
@@ -0,0 +1,39 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDDocumentVerifiers(t *testing.T) {
+	doc := &DIDDocument{
+		VerificationMethod: []*VerificationMethod{
+			{ID: "did:example:1#key1", EthereumAddress: "0xAB5801A7D398351B8BE11C439E05C5B3259AEC9B"},
+			{ID: "did:example:1#key2", BlockchainAccountID: "eip155:1:0x7E5F4552091A69125D5DFCB7B8C2659029395BDF"},
+			{ID: "did:example:1#key3", BlockchainAccountID: "bip122:000000000019d6689c085ae165831e93:128Lkh3S7CkDTBZ8W7BbpsN3YYizJMp8p6"},
+			{ID: "did:example:1#key4", PublicKeyMultibase: "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+		},
+	}
+	assert.Equal(t, []*core.VerifierRef{
+		{Type: core.VerifierTypeEthAddress, Value: "0xab5801a7d398351b8be11c439e05c5b3259aec9b"},
+		{Type: core.VerifierTypeEthAddress, Value: "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"},
+	}, doc.Verifiers())
+}
@@ -44,7 +44,7 @@ type Plugin interface {
 	// UploadData publishes data to the Shared Storage, and returns a payload reference ID
 	UploadData(ctx context.Context, data io.Reader) (payloadRef string, err error)
 
-	// DownloadData reads data back from IPFS using the payload reference format returned from UploadData
+	// DownloadData reads data back from the Shared Storage using the payload reference format returned from UploadData
 	DownloadData(ctx context.Context, payloadRef string) (data io.ReadCloser, err error)
 }
 
@@ -70,8 +70,17 @@ type Plugin interface {
 	// TransferTokens transfers tokens within a pool from one account to another
 	TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error
 
+	// TransferTokensBatch submits a batch of mints, burns and transfers to the connector in a single request.
+	// Only called on plugins that declare the BatchTransfers capability.
+	TransferTokensBatch(ctx context.Context, items []*TransferBatchItem) error
+
 	// TokenApproval approves an operator to transfer tokens on the owner's behalf
 	TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error
+
+	// QueryBalance queries the on-chain balance of an account within a pool, as of the given block number
+	// (or the latest block the connector has seen, if blockNumber is empty).
+	// Only called on plugins that declare the BalanceQuery capability.
+	QueryBalance(ctx context.Context, poolLocator, tokenIndex, key, blockNumber string) (*Balance, error)
 }
 
 // Callbacks is the interface provided to the tokens plugin, to allow it to pass events back to firefly.
@@ -103,6 +112,20 @@ type Callbacks interface {
 
 // Capabilities is the supported featureset of the tokens interface implemented by the plugin, with the specified config
 type Capabilities struct {
+	// BatchTransfers is set if the plugin supports TransferTokensBatch
+	BatchTransfers bool
+
+	// BalanceQuery is set if the plugin supports QueryBalance
+	BalanceQuery bool
+}
+
+// Balance is the on-chain balance of an account, as returned by the connector
+type Balance struct {
+	// Balance is the balance of the account
+	Balance *fftypes.FFBigInt
+
+	// BlockNumber is the block the balance was read at, if reported by the connector (optional)
+	BlockNumber string
 }
 
 // TokenPool is the set of data returned from the connector when a token pool is created.
@@ -159,10 +182,28 @@ type TokenTransfer struct {
 	// PoolLocator is the ID assigned to the token pool by the connector
 	PoolLocator string
 
+	// Operation is the ID of the FireFly operation that submitted this transfer, if known
+	Operation *fftypes.UUID
+
 	// Event contains info on the underlying blockchain event for this transfer
 	Event *blockchain.Event
 }
 
+// TransferBatchItem is a single mint, burn or transfer within a batch, with its own operation
+type TransferBatchItem struct {
+	// NSOpID is the namespaced ID of the operation for this item, used to correlate receipts
+	NSOpID string
+
+	// PoolLocator is the ID assigned to the token pool by the connector
+	PoolLocator string
+
+	// Transfer is the mint, burn or transfer to perform
+	Transfer *core.TokenTransfer
+
+	// Methods are the interface methods resolved for the pool
+	Methods *fftypes.JSONAny
+}
+
 type TokenApproval struct {
 	// Although not every field will be filled in, embed core.TokenApproval to avoid duplicating lots of fields
 	core.TokenApproval
@@ -170,6 +211,9 @@ type TokenApproval struct {
 	// PoolLocator is the ID assigned to the token pool by the connector
 	PoolLocator string
 
+	// Operation is the ID of the FireFly operation that submitted this transfer, if known
+	Operation *fftypes.UUID
+
 	// Event contains info on the underlying blockchain event for this transfer
 	Event *blockchain.Event
 }